@@ -8,7 +8,9 @@ import (
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -83,6 +85,8 @@ type OpenShiftRoute struct {
 type AmazonCloudWatchAgentSpec struct {
 	// ManagementState defines if the CR should be managed by the operator or not.
 	// Default is managed.
+	// When unmanaged, the operator stops updating the objects of the CR, leaving manual changes to them in place,
+	// but keeps its status up to date.
 	//
 	// +required
 	// +kubebuilder:validation:Required
@@ -91,13 +95,30 @@ type AmazonCloudWatchAgentSpec struct {
 	// Resources to set on the OpenTelemetry Collector pods.
 	// +optional
 	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+	// GoRuntime overrides the GOMAXPROCS and GOMEMLIMIT environment variables the operator derives from the CPU and
+	// memory limits of the agent container, so that the agent neither gets throttled by nor exceeds its limits.
+	// +optional
+	GoRuntime *GoRuntimeSpec `json:"goRuntime,omitempty"`
 	// NodeSelector to schedule OpenTelemetry Collector pods.
 	// This is only relevant to daemonset, statefulset, and deployment mode
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
-	// Args is the set of arguments to pass to the OpenTelemetry Collector binary
-	// +optional
-	Args map[string]string `json:"args,omitempty"`
+	// Command overrides the entrypoint of the agent image, for instance with a debugging shell or a custom
+	// entrypoint. The Args are passed to it. Not executed within a shell. Variable references $(VAR_NAME) are expanded
+	// using the container's environment.
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// Args is the set of arguments to pass to the OpenTelemetry Collector binary, either as a map of flags passed as
+	// --key=value, or as a list passed as is, which can hold positional arguments and flags repeated several times.
+	// The values of the map and the items of the list are strings.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Args *Args `json:"args,omitempty"`
+	// FeatureGates enables, when true, or disables, when false, the feature gates of the agent, passed to it with
+	// the --feature-gates flag. The feature gates unknown to the operator are passed too, with a warning.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 	// Replicas is the number of pod instances for the underlying OpenTelemetry Collector. Set this if your are not using autoscaling
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
@@ -139,6 +160,12 @@ type AmazonCloudWatchAgentSpec struct {
 	//
 	// +optional
 	PodSecurityContext *v1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// SecurityProfiles sets the seccomp and AppArmor profiles of the amazon-cloudwatch-agent pods, when running as
+	// a deployment, daemonset, or statefulset, as the namespaces enforcing the restricted Pod Security Standard
+	// require.
+	//
+	// +optional
+	SecurityProfiles *SecurityProfilesSpec `json:"securityProfiles,omitempty"`
 	// PodAnnotations is the set of annotations that will be attached to
 	// Collector and Target Allocator pods.
 	// +optional
@@ -153,9 +180,34 @@ type AmazonCloudWatchAgentSpec struct {
 	// the operator will not automatically create a ServiceAccount for the collector.
 	// +optional
 	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// ServiceAccountAnnotations are the annotations added to the ServiceAccount created by the operator.
+	// They can't be set together with ServiceAccount.
+	// +optional
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+	// NameOverride replaces the name of the AmazonCloudWatchAgent in the names of the objects the operator creates
+	// for it, such as its config maps, services and workloads, which are prefixed with the resource name prefix of
+	// the operator. It tells apart instances whose names share a long prefix, which the names of their objects
+	// truncate. It must not name the objects like those of another instance of the namespace.
+	// +optional
+	NameOverride string `json:"nameOverride,omitempty"`
+	// IAMRoleArn is the ARN of the IAM role the agent assumes through IAM roles for service accounts (IRSA).
+	// It is set as the eks.amazonaws.com/role-arn annotation of the ServiceAccount created by the operator,
+	// and can't be set together with ServiceAccount.
+	// +optional
+	IAMRoleArn string `json:"iamRoleArn,omitempty"`
+	// RoleArn is the ARN of an IAM role the agent assumes with its credentials to send the telemetry, such as a role
+	// of a central observability account. It is rendered as the role_arn of the credentials of the agent config, and
+	// of the AWS exporters of the OtelConfig which don't set one.
+	// +optional
+	RoleArn string `json:"roleArn,omitempty"`
 	// Image indicates the container image to use for the OpenTelemetry Collector.
 	// +optional
 	Image string `json:"image,omitempty"`
+	// ImageDigest pins the digest of the Image the agents run, which they are then deployed by. Otherwise, an operator
+	// requiring image digests resolves the tag of the Image through the registry.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	ImageDigest string `json:"imageDigest,omitempty"`
 	// WorkingDir represents Container's working directory. If not specified,
 	// the container runtime's default will be used, which might
 	// be configured in the container image. Cannot be updated.
@@ -170,7 +222,18 @@ type AmazonCloudWatchAgentSpec struct {
 	// Prometheus is the raw YAML to be used as the collector's prometheus configuration.
 	// +optional
 	Prometheus PrometheusConfig `json:"prometheus,omitempty"`
+	// PrometheusReload reloads the agent in place when the Prometheus configuration changes, instead of
+	// leaving the new configuration unused until the pods restart. A reloader sidecar watches the mounted
+	// configuration and signals the agent, the pods sharing their process namespace. Linux only.
+	// +optional
+	PrometheusReload *PrometheusReloadSpec `json:"prometheusReload,omitempty"`
+	// ControlPlaneMetrics scrapes the metrics of the Kubernetes control plane, adding the scrape configs of its
+	// components to the Prometheus configuration. The agent scrapes them through the API server, with the token of
+	// its service account. It is not supported in the daemonset and sidecar modes, where every pod would scrape them.
+	// +optional
+	ControlPlaneMetrics *ControlPlaneMetricsSpec `json:"controlPlaneMetrics,omitempty"`
 	// Config is the raw JSON to be used as the collector's configuration. Refer to the OpenTelemetry Collector documentation for details.
+	// The ${cluster_name}, ${region}, ${namespace} and ${name} variables are substituted with the cluster name and the region of the operator, and the namespace and the name of the AmazonCloudWatchAgent.
 	// +required
 	Config string `json:"config,omitempty"`
 	// Config is the raw YAML to be used as the collector's configuration. Refer to the OpenTelemetry Collector documentation for details.
@@ -183,9 +246,28 @@ type AmazonCloudWatchAgentSpec struct {
 	// Ports allows a set of ports to be exposed by the underlying v1.Service. By default, the operator
 	// will attempt to infer the required ports by parsing the .Spec.Config property but this property can be
 	// used to open additional ports that can't be inferred by the operator, like for custom receivers.
+	// Their appProtocol and nodePort are set on the ports of the Service.
 	// +optional
 	// +listType=atomic
 	Ports []v1.ServicePort `json:"ports,omitempty"`
+	// ServiceType is the type of the Service exposing the ports of the agent, ClusterIP by default. NodePort and
+	// LoadBalancer let the senders outside of the cluster reach the agent.
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType v1.ServiceType `json:"serviceType,omitempty"`
+	// ServiceAnnotations are the annotations of the Service exposing the ports of the agent, for instance to
+	// provision a Network Load Balancer.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// IPFamilies are the IP families of the Services exposing the ports of the agent, for instance [IPv6] on
+	// IPv6-only clusters or [IPv4, IPv6] on dual-stack clusters. The cluster default applies when unset.
+	// +optional
+	// +listType=atomic
+	IPFamilies []v1.IPFamily `json:"ipFamilies,omitempty"`
+	// IPFamilyPolicy tells whether the Services exposing the ports of the agent are single-stack or dual-stack.
+	// +optional
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	IPFamilyPolicy *v1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
 	// ENV vars to set on the OpenTelemetry Collector's Pods. These can then in certain cases be
 	// consumed in the config file for the Collector.
 	// +optional
@@ -214,6 +296,18 @@ type AmazonCloudWatchAgentSpec struct {
 	// HostNetwork indicates if the pod should run in the host networking namespace.
 	// +optional
 	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// HostPID indicates if the pod should run in the host process ID namespace, which lets the agent
+	// monitor the processes of the node, for example with procstat.
+	// +optional
+	HostPID bool `json:"hostPID,omitempty"`
+	// HostIPC indicates if the pod should run in the host IPC namespace.
+	// +optional
+	HostIPC bool `json:"hostIPC,omitempty"`
+	// ShareProcessNamespace indicates if the containers of the pod share a single process namespace.
+	// It is always enabled when PrometheusReload or the HotReload ConfigReload is in effect, and can't be
+	// combined with HostPID.
+	// +optional
+	ShareProcessNamespace *bool `json:"shareProcessNamespace,omitempty"`
 	// If specified, indicates the pod's priority.
 	// If not specified, the pod priority will be default or zero if there is no
 	// default.
@@ -225,13 +319,21 @@ type AmazonCloudWatchAgentSpec struct {
 	// Actions that the management system should take in response to container lifecycle events. Cannot be updated.
 	// +optional
 	Lifecycle *v1.Lifecycle `json:"lifecycle,omitempty"`
-	// Duration in seconds the pod needs to terminate gracefully upon probe failure.
+	// Duration in seconds the pod needs to terminate gracefully, which gives the agent time to flush
+	// its buffered telemetry. Defaults to 30 seconds.
 	// +optional
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 	// Liveness config for the OpenTelemetry Collector except the probe handler which is auto generated from the health extension of the collector.
 	// It is only effective when healthcheckextension is configured in the OpenTelemetry Collector pipeline.
 	// +optional
 	LivenessProbe *Probe `json:"livenessProbe,omitempty"`
+	// ContainerOverride is a patch of the agent container, strategically merged onto the container generated by the
+	// operator, such as {"stdin": true, "tty": true}. It is an escape hatch for the container fields the spec doesn't
+	// model yet. The name of the container can't be changed.
+	// +optional
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ContainerOverride *runtime.RawExtension `json:"containerOverride,omitempty"`
 	// InitContainers allows injecting initContainers to the Collector's pod definition.
 	// These init containers can be used to fetch secrets for injection into the
 	// configuration from external sources, run added checks, etc. Any errors during the execution of
@@ -275,11 +377,131 @@ type AmazonCloudWatchAgentSpec struct {
 	// object, which shall be mounted into the Collector Pods.
 	// Each ConfigMap will be added to the Collector's Deployments as a volume named `configmap-<configmap-name>`.
 	ConfigMaps []ConfigMapsSpec `json:"configmaps,omitempty"`
+	// ExtraMounts mounts keys of ConfigMaps and Secrets in the same namespace as the AmazonCloudWatchAgent
+	// at the given paths of the agent container, such as the credentials files and CA bundles referenced
+	// by the agent config.
+	// +optional
+	ExtraMounts []ExtraMountSpec `json:"extraMounts,omitempty"`
 	// UpdateStrategy represents the strategy the operator will take replacing existing DaemonSet pods with new pods
 	// https://kubernetes.io/docs/reference/kubernetes-api/workload-resources/daemon-set-v1/#DaemonSetSpec
 	// This is only applicable to Daemonset mode.
 	// +optional
 	UpdateStrategy appsv1.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// Buffer defines the storage backing the spool directories used by the agent to buffer telemetry,
+	// such as the directories of the file_storage extensions found in the OtelConfig.
+	// +optional
+	Buffer BufferSpec `json:"buffer,omitempty"`
+	// LogVolumes defines the hostPath volumes generated for the files collected through the
+	// logs.logs_collected.files section of the Config. This is only applicable to Daemonset mode.
+	// +optional
+	LogVolumes LogVolumesSpec `json:"logVolumes,omitempty"`
+	// Proxy defines the egress proxy used by the agent, rendered as the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// environment variables of the agent container. Variables set through Env take precedence.
+	// +optional
+	Proxy ProxySpec `json:"proxy,omitempty"`
+	// TrustedCA adds the certificate authorities of a bundle to the ones the agent trusts, such as the ones of
+	// corporate TLS-intercepting proxies and private OTLP backends, without rebuilding the agent image.
+	// +optional
+	TrustedCA *TrustedCASpec `json:"trustedCA,omitempty"`
+	// AWSEndpointOverrides defines the endpoints of the AWS services the agent reaches, for VPC endpoints and
+	// partitions where the default ones are unreachable. They are rendered into the Config and the OtelConfig,
+	// overriding the ones set there, and as the AWS_ENDPOINT_URL_* environment variables of the agent container.
+	// +optional
+	AWSEndpointOverrides AWSEndpointOverridesSpec `json:"awsEndpointOverrides,omitempty"`
+	// ConfigReload defines how the agent pods pick up a change of the Config. With HotReload, changes limited
+	// to the sections the agent can hot-reload don't roll out the pods: a reloader sidecar signals the agent with
+	// SIGHUP to reload the translated configs it is started on, the pods sharing their process namespace. Linux
+	// only, requires the --config-translator of the operator, the changes rolling out the pods otherwise. Defaults
+	// to Restart.
+	// +optional
+	ConfigReload ConfigReloadStrategy `json:"configReload,omitempty"`
+	// Persistence attaches a PersistentVolumeClaim to each agent pod and makes the sending queues of the
+	// exporters in the OtelConfig use a file_storage extension on it, so that buffered telemetry survives
+	// pod restarts. This is only applicable to Statefulset mode.
+	// +optional
+	Persistence *PersistenceSpec `json:"persistence,omitempty"`
+	// OTLPReceiver defines settings rendered into every otlp receiver of the OtelConfig, overriding
+	// the ones set there. The otlp receivers of the Config don't take these settings, which require the OtelConfig.
+	// +optional
+	OTLPReceiver OTLPReceiverSpec `json:"otlpReceiver,omitempty"`
+	// InjectedEnvPolicy defines how the environment variables computed by the operator, such as POD_NAME,
+	// SHARD and the proxy variables, are injected into the agent container. Variables defined in Env are
+	// never injected again.
+	// +optional
+	InjectedEnvPolicy InjectedEnvPolicy `json:"injectedEnvPolicy,omitempty"`
+	// TLS enables TLS on the otlp and Application Signals receivers of the agent, with a certificate
+	// from a user provided Secret or issued by cert-manager. When the Config enables Application Signals,
+	// the CA certificate is distributed as the ca.crt key of the <name>-ca-bundle ConfigMap into the
+	// namespaces of the instrumented workloads.
+	// +optional
+	TLS *TLSSpec `json:"tls,omitempty"`
+	// XRay defines the settings of the X-Ray trace collection, rendered into the traces section of the
+	// Config and overriding the ones set there. It requires the xray receiver in traces.traces_collected.
+	// +optional
+	XRay *XRaySpec `json:"xray,omitempty"`
+	// Alarms defines CloudWatch alarms monitoring the health of the agents, which the operator creates
+	// and deletes together with the agent when it is started with --enable-cloudwatch-alarms.
+	// +optional
+	Alarms *AlarmsSpec `json:"alarms,omitempty"`
+	// VersionSplit runs the agents of the nodes carrying a label with another image, to validate an
+	// agent upgrade on a cohort of nodes before rolling it out to all of them. It is only supported
+	// in the daemonset mode.
+	// +optional
+	VersionSplit *VersionSplitSpec `json:"versionSplit,omitempty"`
+	// RolloutStrategy defines how the changes of the Config and the OtelConfig are rolled out to the agents, such
+	// as to a few canary nodes first. It is only supported in the daemonset mode.
+	// +optional
+	RolloutStrategy *RolloutStrategySpec `json:"rolloutStrategy,omitempty"`
+	// NodeGroups adapt the Config to the nodes carrying a label, such as enabling the GPU metrics on the
+	// nodes labeled nvidia.com/gpu.present. Each node group runs in a daemonset of its own, and a node
+	// carrying the labels of several groups belongs to the first of them. It is only supported in the
+	// daemonset mode.
+	// +optional
+	NodeGroups []NodeGroupSpec `json:"nodeGroups,omitempty"`
+	// NodeConfigOverrides adapt the Config to the nodes matching a node selector, such as GPU, Neuron and
+	// general-purpose nodes. Like the NodeGroups, which they follow, each override runs in a daemonset of its
+	// own, and a node matching several overrides belongs to the first of them. It is only supported in the
+	// daemonset mode.
+	// +optional
+	NodeConfigOverrides []NodeConfigOverrideSpec `json:"nodeConfigOverrides,omitempty"`
+	// PipelineSplit runs the metrics and the traces of the agent in deployments of their own, each with the
+	// sections and the pipelines of the Config and the OtelConfig for its signal, and leaves the logs and the metrics
+	// collected from the nodes to the daemonset. The Service of the agent keeps the ports of the traces. It is only
+	// supported in the daemonset mode.
+	// +optional
+	PipelineSplit *PipelineSplitSpec `json:"pipelineSplit,omitempty"`
+	// Logs defines the naming of the log groups the agent writes to, rendered into the log outputs of the
+	// Config and the OtelConfig.
+	// +optional
+	Logs *LogsSpec `json:"logs,omitempty"`
+	// Destinations sets where the telemetry of each signal goes, the region, the log group and the metrics
+	// namespace, without writing them in the Config and the OtelConfig. Each destination applies either to the
+	// section of its signal of the Config, or to a pipeline of the OtelConfig, so that the pipelines of a signal
+	// can each send to a region, a log group or a namespace of their own.
+	// +optional
+	Destinations []DestinationSpec `json:"destinations,omitempty"`
+	// NodeTermination flushes the telemetry buffered by the agent pods of the nodes about to be terminated, once
+	// the nodes are tainted for their termination, by deleting the pods so that the agent flushes on the SIGTERM.
+	// It is only supported in the daemonset mode, and requires the operator to run with --node-termination-flush.
+	// +optional
+	NodeTermination *NodeTerminationSpec `json:"nodeTermination,omitempty"`
+	// Diagnostics enables the debug surfaces of the OtelConfig pipelines. They listen on localhost only and
+	// are reached with kubectl port-forward to the agent pods, never through a Service.
+	// +optional
+	Diagnostics *DiagnosticsSpec `json:"diagnostics,omitempty"`
+	// Debug exposes the pprof and zpages endpoints of the agent to the cluster through an internal Service, to
+	// troubleshoot the agent pods without port-forwarding to each of them.
+	// +optional
+	Debug *DebugSpec `json:"debug,omitempty"`
+	// ApplicationSignals enables Application Signals without writing its sections of the Config, which
+	// also exposes its ports on the agent Services and points the Instrumentation exporters at them.
+	// +optional
+	ApplicationSignals *ApplicationSignalsSpec `json:"applicationSignals,omitempty"`
+	// LabelsPolicy defines the set of labels put on the agent pods. The app.kubernetes.io labels and the
+	// container names are kept stable across operator versions, and Legacy adds the name label of the
+	// manifests the agent was deployed with before the operator. Defaults to Standard.
+	// +optional
+	LabelsPolicy LabelsPolicy `json:"labelsPolicy,omitempty"`
 }
 
 // AmazonCloudWatchAgentTargetAllocator defines the configurations for the Prometheus target allocator.
@@ -397,6 +619,10 @@ type AmazonCloudWatchAgentStatus struct {
 	// +optional
 	Image string `json:"image,omitempty"`
 
+	// ImageDigest is the digest of the image the agents are deployed by, when they run an immutable image reference.
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+
 	// Messages about actions performed by the operator on this resource.
 	// +optional
 	// +listType=atomic
@@ -407,11 +633,81 @@ type AmazonCloudWatchAgentStatus struct {
 	// +optional
 	// Deprecated: use "AmazonCloudWatchAgent.Status.Scale.Replicas" instead.
 	Replicas int32 `json:"replicas,omitempty"`
+
+	// Conditions represent the latest available observations of the AmazonCloudWatchAgent's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// VersionSplit compares the cohorts of agents when the daemonset is split between two versions.
+	// +optional
+	VersionSplit *VersionSplitStatus `json:"versionSplit,omitempty"`
+
+	// CanaryRollout records the progress of the canary rollout of the last change of the Config and the OtelConfig.
+	// +optional
+	CanaryRollout *CanaryRolloutStatus `json:"canaryRollout,omitempty"`
+
+	// Workloads reports the state of each daemonset, deployment and statefulset the operator runs for the
+	// AmazonCloudWatchAgent, such as the ones of its node groups or of the signals split out of its daemonset.
+	// +optional
+	Workloads []WorkloadStatus `json:"workloads,omitempty"`
+
+	// ConfigHashes counts the agent pods running each config, identified by the hash the pods are labeled with
+	// through the cloudwatch.aws.amazon.com/config-hash label, to find the nodes still running a stale config during
+	// a rollout.
+	// +optional
+	// +listType=atomic
+	ConfigHashes []ConfigHashStatus `json:"configHashes,omitempty"`
+
+	// Restart records the progress of the restart requested through the cloudwatch.aws.amazon.com/restart
+	// annotation.
+	// +optional
+	Restart *RestartStatus `json:"restart,omitempty"`
 }
 
+const (
+	// ConditionTypeAlarmsSynced tells whether the CloudWatch alarms of the agent were last created, updated or
+	// deleted successfully. The agents are reconciled while the CloudWatch API fails, and the alarms retried.
+	ConditionTypeAlarmsSynced = "AlarmsSynced"
+	// ConditionTypeConfigWarnings tells whether the configs of the agent contain common mistakes which the validation
+	// accepts, such as EMF ports the agent doesn't listen on, or a Prometheus receiver without the RBAC to discover
+	// its targets.
+	ConditionTypeConfigWarnings = "ConfigWarnings"
+	// ConditionTypeConfigRolledOut tells whether the Config and the OtelConfig run on all the agents, or are held on
+	// the canary nodes of a canary rollout, either while it progresses or after it was rolled back.
+	ConditionTypeConfigRolledOut = "ConfigRolledOut"
+	// ConditionTypeCredentialsAvailable tells whether the operator found AWS credentials for the agent, either
+	// through IRSA, the container environment or the agent config. Without them, the agent falls back to the
+	// credentials of the node it runs on.
+	ConditionTypeCredentialsAvailable = "CredentialsAvailable"
+	// ConditionTypeDegraded tells whether the operator holds the rollout of the agents because their configs
+	// failed the validation, in which case the agents keep running the last good configs.
+	ConditionTypeDegraded = "Degraded"
+	// ConditionTypeHostPortConflict tells whether the operator holds the rollout of the daemonset of the agent because
+	// another agent or daemonset already binds one of its host ports on the same nodes, where its pods can't run.
+	ConditionTypeHostPortConflict = "HostPortConflict"
+	// ConditionTypeOwnershipConflict tells whether objects the operator should create already exist and are managed
+	// by another tool, in which case the operator leaves them untouched.
+	ConditionTypeOwnershipConflict = "OwnershipConflict"
+	// ConditionTypeQuotaExceeded tells whether the resource quotas of the namespace leave too little room for
+	// the agent pods, comparing the resources the agent pods need with the ones the quotas leave available.
+	ConditionTypeQuotaExceeded = "QuotaExceeded"
+	// ConditionTypeServiceAccountUserManaged tells whether the service account of the agent already existed and
+	// was not created by the operator, in which case the operator leaves it and its annotations, such as the IRSA
+	// role, untouched.
+	ConditionTypeServiceAccountUserManaged = "ServiceAccountUserManaged"
+	// ConditionTypeUnmanaged tells whether the operator stopped updating the objects of the agent because its
+	// managementState is unmanaged, in which case their manual changes are left in place.
+	ConditionTypeUnmanaged = "Unmanaged"
+	// ConditionTypeVersionsSupported tells whether the versions of the operator, of the agent image and of Kubernetes
+	// are supported together by the support matrix of the operator.
+	ConditionTypeVersionsSupported = "VersionsSupported"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
-// +kubebuilder:resource:shortName=otelcol;otelcols
+// +kubebuilder:resource:shortName=otelcol;otelcols;cwa
 // +kubebuilder:subresource:status
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
 // +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode",description="Deployment Mode"
@@ -465,6 +761,26 @@ type AutoscalerSpec struct {
 	// +optional
 	// TargetMemoryUtilization sets the target average memory utilization across all replicas
 	TargetMemoryUtilization *int32 `json:"targetMemoryUtilization,omitempty"`
+	// Vertical renders a VerticalPodAutoscaler adjusting the resource requests of the agent container,
+	// for instance for daemonset agents whose memory needs vary with the log volume of their node.
+	// It requires the Vertical Pod Autoscaler to be installed in the cluster.
+	// +optional
+	Vertical *VerticalAutoscalerSpec `json:"vertical,omitempty"`
+}
+
+// VerticalAutoscalerSpec defines the VerticalPodAutoscaler of the agent workload.
+type VerticalAutoscalerSpec struct {
+	// UpdateMode controls whether the recommendations are only computed (Off), applied to new pods
+	// (Initial), or also applied by evicting the running pods (Recreate and Auto). Defaults to Auto.
+	// +optional
+	// +kubebuilder:validation:Enum=Off;Initial;Recreate;Auto
+	UpdateMode string `json:"updateMode,omitempty"`
+	// MinAllowed is the lower bound of the recommended resource requests of the agent container.
+	// +optional
+	MinAllowed v1.ResourceList `json:"minAllowed,omitempty"`
+	// MaxAllowed is the upper bound of the recommended resource requests of the agent container.
+	// +optional
+	MaxAllowed v1.ResourceList `json:"maxAllowed,omitempty"`
 }
 
 // PodDisruptionBudgetSpec defines the AmazonCloudWatchAgent's pod disruption budget specification.
@@ -503,6 +819,227 @@ type ObservabilitySpec struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Metrics Config"
 	Metrics MetricsConfigSpec `json:"metrics,omitempty"`
+
+	// SelfTelemetry exports the logs and the internal metrics of the agent to CloudWatch, apart from the
+	// telemetry it collects, and exposes its internal metrics on the monitoring Service.
+	//
+	// +optional
+	SelfTelemetry *SelfTelemetrySpec `json:"selfTelemetry,omitempty"`
+}
+
+// SelfTelemetrySpec defines where the agent exports its own logs and metrics.
+type SelfTelemetrySpec struct {
+	// LogGroupName is the log group of the logs of the agent, and of the EMF logs of its internal metrics. The logs
+	// are only collected when the agent.logfile of the Config sets the file the agent logs to.
+	// Defaults to /aws/cloudwatch-agent/<namespace>/<name>.
+	// +optional
+	LogGroupName string `json:"logGroupName,omitempty"`
+	// MetricsNamespace is the CloudWatch namespace of the internal metrics of the agent, which are only exported
+	// when the agent runs an OtelConfig. Defaults to CWAgent/SelfTelemetry.
+	// +optional
+	MetricsNamespace string `json:"metricsNamespace,omitempty"`
+	// MetricsPort is the port of the internal metrics endpoint of the agent. Defaults to 8888.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+}
+
+// BufferSpec defines how the agent's buffered telemetry is stored.
+type BufferSpec struct {
+	// Storage defines the emptyDir volumes rendered for each spool directory.
+	// +optional
+	Storage BufferStorageSpec `json:"storage,omitempty"`
+}
+
+// BufferStorageSpec defines the emptyDir volume backing a spool directory.
+type BufferStorageSpec struct {
+	// Medium is the storage medium backing the spool directories. Use "Memory" for a tmpfs backed emptyDir,
+	// which counts against the container memory limit. Defaults to the node's default medium.
+	// +optional
+	// +kubebuilder:validation:Enum="";Memory
+	Medium v1.StorageMedium `json:"medium,omitempty"`
+	// SizeLimit is the total amount of local storage that can be used by each spool directory.
+	// Defaults to 1Gi.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
+
+// LogVolumesSpec defines the hostPath volumes generated for the collected log files.
+type LogVolumesSpec struct {
+	// ReadOnly mounts the generated hostPath volumes as read-only. Defaults to true.
+	// +optional
+	ReadOnly *bool `json:"readOnly,omitempty"`
+}
+
+// InjectedEnvPolicy defines how the operator computed environment variables are injected.
+type InjectedEnvPolicy struct {
+	// Disabled is the list of the operator computed environment variables which are not injected.
+	// +optional
+	// +listType=set
+	Disabled []string `json:"disabled,omitempty"`
+	// Rename maps the names of operator computed environment variables to the names they are injected as.
+	// +optional
+	Rename map[string]string `json:"rename,omitempty"`
+}
+
+// GoRuntimeSpec defines the tuning of the Go runtime of the agent.
+type GoRuntimeSpec struct {
+	// MaxProcs is the GOMAXPROCS of the agent. Defaults to the CPU limit of the agent container, rounded up.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxProcs *int32 `json:"maxProcs,omitempty"`
+	// MemoryLimit is the GOMEMLIMIT of the agent. Defaults to the MemoryLimitPercent of the memory limit of the
+	// agent container.
+	// +optional
+	MemoryLimit *resource.Quantity `json:"memoryLimit,omitempty"`
+	// MemoryLimitPercent is the percentage of the memory limit of the agent container the default GOMEMLIMIT
+	// is set to, leaving the rest to the memory the Go runtime doesn't manage. Defaults to 90.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MemoryLimitPercent *int32 `json:"memoryLimitPercent,omitempty"`
+}
+
+// DefaultGoMemoryLimitPercent is the percentage of the memory limit the GOMEMLIMIT of the agents is set to, unless
+// they set one.
+const DefaultGoMemoryLimitPercent int32 = 90
+
+// OTLPReceiverSpec defines the settings of the otlp receivers.
+type OTLPReceiverSpec struct {
+	// GRPCMaxRecvMsgSizeMiB is the maximum size of the messages accepted by the gRPC server, in MiB.
+	// The receiver defaults to 4.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	GRPCMaxRecvMsgSizeMiB *int32 `json:"grpcMaxRecvMsgSizeMiB,omitempty"`
+	// HTTPMaxRequestBodySize is the maximum size of the request bodies accepted by the HTTP server.
+	// +optional
+	HTTPMaxRequestBodySize *resource.Quantity `json:"httpMaxRequestBodySize,omitempty"`
+	// HTTPCompressionAlgorithms are the compression algorithms of the request bodies accepted by the HTTP server.
+	// +optional
+	HTTPCompressionAlgorithms []CompressionAlgorithm `json:"httpCompressionAlgorithms,omitempty"`
+}
+
+// CompressionAlgorithm is a compression algorithm supported by the otlp receiver.
+// +kubebuilder:validation:Enum=gzip;zstd;zlib;deflate;snappy;lz4
+type CompressionAlgorithm string
+
+// PersistenceSpec defines the volume persisting the agent's sending queues.
+type PersistenceSpec struct {
+	// StorageClassName is the name of the StorageClass of the PersistentVolumeClaim.
+	// Defaults to the cluster's default StorageClass.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// Size is the requested size of the PersistentVolumeClaim. Defaults to 1Gi.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Directory is the path the volume is mounted at, and the directory of the file_storage extension
+	// used by the sending queues. Defaults to /var/lib/otelcol/file_storage.
+	// +optional
+	Directory string `json:"directory,omitempty"`
+}
+
+// TLSSpec defines the certificate served by the agent's receivers.
+type TLSSpec struct {
+	// SecretName is the name of the kubernetes.io/tls Secret holding the certificate and key of the receivers.
+	// When CertManager is set, it is the Secret the certificate is issued into, and defaults to <name>-tls.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// SecretNamespace is the namespace of the Secret, defaults to the namespace of the agent. The Secret of
+	// another namespace is copied into the namespace of the agent, and must be annotated with
+	// cloudwatch.aws.amazon.com/shared-with to allow it.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	SecretNamespace string `json:"secretNamespace,omitempty"`
+	// CertManager requests the certificate from a cert-manager issuer. cert-manager must be installed in the cluster.
+	// +optional
+	CertManager *CertManagerSpec `json:"certManager,omitempty"`
+}
+
+// CertManagerSpec defines the cert-manager Certificate requested for the agent.
+type CertManagerSpec struct {
+	// IssuerRef is the cert-manager Issuer or ClusterIssuer signing the certificate.
+	IssuerRef CertManagerIssuerRef `json:"issuerRef"`
+	// DNSNames are added to the DNS names of the agent services in the certificate.
+	// +optional
+	// +listType=set
+	DNSNames []string `json:"dnsNames,omitempty"`
+	// Duration is the requested lifetime of the certificate. Defaults to cert-manager's default.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// RenewBefore is how long before the expiry the certificate is renewed. Defaults to cert-manager's default.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// CertManagerIssuerRef references a cert-manager issuer.
+type CertManagerIssuerRef struct {
+	// Name of the issuer.
+	Name string `json:"name"`
+	// Kind of the issuer.
+	// +optional
+	// +kubebuilder:default:=Issuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+	// Group of the issuer. Defaults to cert-manager.io, set it for external issuers.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// ProxySpec defines the egress proxy used by the agent.
+type ProxySpec struct {
+	// HTTPProxy is the proxy used for HTTP requests.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// HTTPSProxy is the proxy used for HTTPS requests.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// NoProxy is the comma-separated list of hosts, domains and CIDRs which are reached without the proxy.
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// TrustedCASpec defines the ConfigMap or the Secret holding the PEM bundle of the additional certificate
+// authorities trusted by the agent. Exactly one of ConfigMap and Secret must be set.
+type TrustedCASpec struct {
+	// ConfigMap is the name of the ConfigMap holding the bundle, such as the ones filled by trust-manager.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+	// Secret is the name of the Secret holding the bundle.
+	// +optional
+	Secret string `json:"secret,omitempty"`
+	// Namespace is the namespace of the ConfigMap or the Secret, defaults to the namespace of the agent.
+	// The ConfigMap or the Secret of another namespace is copied into the namespace of the agent, and must
+	// be annotated with cloudwatch.aws.amazon.com/shared-with to allow it.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Namespace string `json:"namespace,omitempty"`
+	// Key is the key of the ConfigMap or the Secret holding the bundle. Defaults to ca.crt.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DefaultTrustedCAKey is the key of the bundle of the trusted certificate authorities which don't set one.
+const DefaultTrustedCAKey = "ca.crt"
+
+// AWSEndpointOverridesSpec defines the endpoints of the AWS services the agent reaches.
+type AWSEndpointOverridesSpec struct {
+	// CloudWatch is the endpoint of the CloudWatch metrics, such as https://monitoring.us-gov-west-1.amazonaws.com.
+	// +optional
+	CloudWatch string `json:"cloudwatch,omitempty"`
+	// Logs is the endpoint of CloudWatch Logs.
+	// +optional
+	Logs string `json:"logs,omitempty"`
+	// XRay is the endpoint of X-Ray. The EndpointOverride of the XRay settings takes precedence.
+	// +optional
+	XRay string `json:"xray,omitempty"`
+	// STS is the endpoint of the Security Token Service the credentials are retrieved from.
+	// +optional
+	STS string `json:"sts,omitempty"`
+	// STSRegionalEndpoints makes the agent use the STS endpoint of its region instead of the global one, which
+	// the VPC endpoints of STS require. It is implied by STS.
+	// +optional
+	STSRegionalEndpoints bool `json:"stsRegionalEndpoints,omitempty"`
 }
 
 // Probe defines the OpenTelemetry's pod probe config. Only Liveness probe is supported currently.
@@ -557,6 +1094,543 @@ type ConfigMapsSpec struct {
 	MountPath string `json:"mountpath"`
 }
 
+// ExtraMountSpec defines a ConfigMap or a Secret mounted into the agent container. Exactly one of
+// ConfigMap and Secret must be set.
+type ExtraMountSpec struct {
+	// ConfigMap is the name of the ConfigMap to mount.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+	// Secret is the name of the Secret to mount.
+	// +optional
+	Secret string `json:"secret,omitempty"`
+	// Namespace is the namespace of the ConfigMap or the Secret, defaults to the namespace of the agent.
+	// The ConfigMap or the Secret of another namespace is copied into the namespace of the agent, and must
+	// be annotated with cloudwatch.aws.amazon.com/shared-with to allow it.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Namespace string `json:"namespace,omitempty"`
+	// Items projects the listed keys to the given paths, relative to the mount. All the keys are
+	// projected as files named after them when empty.
+	// +optional
+	Items []v1.KeyToPath `json:"items,omitempty"`
+	// MountPath is the path in the agent container the ConfigMap or the Secret is mounted at.
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+	// SubPath mounts a single file or directory of the volume at the MountPath instead of its root.
+	// Files mounted with a SubPath are not updated when the ConfigMap or the Secret changes.
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+}
+
+// XRaySpec defines the settings of the X-Ray trace collection.
+type XRaySpec struct {
+	// LocalMode stops the agent from retrieving the EC2 instance metadata, for nodes which are not
+	// EC2 instances.
+	// +optional
+	LocalMode *bool `json:"localMode,omitempty"`
+	// RegionOverride is the region the segments are sent to, instead of the region of the node.
+	// +optional
+	RegionOverride string `json:"regionOverride,omitempty"`
+	// EndpointOverride is the X-Ray endpoint the segments are sent to.
+	// +optional
+	EndpointOverride string `json:"endpointOverride,omitempty"`
+	// ResourceARN is the Amazon Resource Name of the resource the agent runs on, added to the segments.
+	// +optional
+	ResourceARN string `json:"resourceArn,omitempty"`
+	// BufferSizeMB is the size of the buffer holding the segments before they are sent, in MB.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BufferSizeMB *int32 `json:"bufferSizeMB,omitempty"`
+	// Concurrency is the maximum number of concurrent calls to X-Ray to upload the segments.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Concurrency *int32 `json:"concurrency,omitempty"`
+}
+
+// AlarmsSpec defines the CloudWatch alarms monitoring the agents of a cluster.
+type AlarmsSpec struct {
+	// ClusterName is the value of the ClusterName dimension of the alarmed metrics.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+	// ActionARNs are the ARNs of the actions, such as SNS topics, executed when an alarm enters the
+	// ALARM state.
+	// +optional
+	// +listType=set
+	ActionARNs []string `json:"actionArns,omitempty"`
+	// Heartbeat defines the alarm entering the ALARM state when the agents stop publishing metrics.
+	// +optional
+	Heartbeat HeartbeatAlarmSpec `json:"heartbeat,omitempty"`
+	// ExportErrors defines the alarm entering the ALARM state when the agents fail to export telemetry.
+	// The alarm is not created when unset.
+	// +optional
+	ExportErrors *ExportErrorsAlarmSpec `json:"exportErrors,omitempty"`
+}
+
+// HeartbeatAlarmSpec defines the alarm raised when a metric published by the agents goes missing.
+type HeartbeatAlarmSpec struct {
+	// Disabled skips the creation of the alarm.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Namespace is the namespace of the metric. Defaults to ContainerInsights.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// MetricName is the name of the metric. Defaults to node_cpu_utilization.
+	// +optional
+	MetricName string `json:"metricName,omitempty"`
+	// PeriodSeconds is the length of the periods the metric is evaluated over. Defaults to 300.
+	// +optional
+	// +kubebuilder:validation:Minimum=60
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// EvaluationPeriods is the number of periods without data points raising the alarm. Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	EvaluationPeriods *int32 `json:"evaluationPeriods,omitempty"`
+}
+
+// ExportErrorsAlarmSpec defines the alarm raised when a metric counting the export errors of the agents
+// exceeds a threshold.
+type ExportErrorsAlarmSpec struct {
+	// Namespace is the namespace of the metric.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// MetricName is the name of the metric.
+	// +kubebuilder:validation:MinLength=1
+	MetricName string `json:"metricName"`
+	// Threshold is the sum of the metric over a period above which the alarm is raised.
+	// +kubebuilder:validation:Minimum=0
+	Threshold int64 `json:"threshold"`
+	// PeriodSeconds is the length of the periods the metric is evaluated over. Defaults to 300.
+	// +optional
+	// +kubebuilder:validation:Minimum=60
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// EvaluationPeriods is the number of periods above the threshold raising the alarm. Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	EvaluationPeriods *int32 `json:"evaluationPeriods,omitempty"`
+}
+
+// VersionSplitSpec defines the canary cohort of a daemonset split between two agent versions.
+type VersionSplitSpec struct {
+	// Image is the image of the agents of the canary cohort.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// NodeLabel is the label of the nodes of the canary cohort, for instance put on 10% of the nodes.
+	// The other nodes run the stable cohort, with the image of the AmazonCloudWatchAgent.
+	NodeLabel NodeLabel `json:"nodeLabel"`
+}
+
+// RolloutStrategyType is the way the changes of the configs are rolled out to the agents.
+// +kubebuilder:validation:Enum=RollingUpdate;Canary
+type RolloutStrategyType string
+
+const (
+	// RolloutStrategyRollingUpdate rolls the changes out to all the agents, following the updateStrategy.
+	RolloutStrategyRollingUpdate RolloutStrategyType = "RollingUpdate"
+	// RolloutStrategyCanary rolls the changes out to the agents of a few canary nodes first, and to the other agents
+	// once the canary agents stayed healthy for the bake duration.
+	RolloutStrategyCanary RolloutStrategyType = "Canary"
+)
+
+// RolloutStrategySpec defines how the changes of the configs are rolled out to the agents.
+type RolloutStrategySpec struct {
+	// Type is the way the changes are rolled out, RollingUpdate or Canary.
+	Type RolloutStrategyType `json:"type"`
+	// Canary defines the canary nodes and the bake duration of the Canary rollouts.
+	// +optional
+	Canary *CanaryRolloutSpec `json:"canary,omitempty"`
+}
+
+// CanaryRolloutSpec defines the canary nodes and the bake duration of a canary rollout.
+type CanaryRolloutSpec struct {
+	// MaxCanaryNodes is the maximum number of canary nodes. Defaults to 1 when Percentage isn't set.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxCanaryNodes int32 `json:"maxCanaryNodes,omitempty"`
+	// Percentage is the share of the nodes of the agent chosen as canary nodes, rounded up and capped by
+	// MaxCanaryNodes.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percentage int32 `json:"percentage,omitempty"`
+	// BakeDuration is how long the canary agents must stay ready and healthy before the change is rolled out to the
+	// other agents. Defaults to 10m.
+	// +optional
+	BakeDuration *metav1.Duration `json:"bakeDuration,omitempty"`
+	// MaxRestarts is the number of restarts of the canary agents beyond which the change is rolled back.
+	// Defaults to 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRestarts int32 `json:"maxRestarts,omitempty"`
+	// MaxExportFailurePercentage is the share of the telemetry the canary agents fail to export, read from their
+	// internal metrics, beyond which the change is rolled back. The internal metrics are only served by the agents
+	// running an OtelConfig, the other agents being checked on their readiness and restarts alone. Defaults to 5.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MaxExportFailurePercentage *int32 `json:"maxExportFailurePercentage,omitempty"`
+}
+
+// NodeConfigOverrideSpec defines the Config of the agents of the nodes matching a node selector.
+type NodeConfigOverrideSpec struct {
+	// Name identifies the override in the names of its daemonset and ConfigMap.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// NodeSelector selects the nodes carrying all of its labels.
+	// +kubebuilder:validation:MinProperties=1
+	NodeSelector map[string]string `json:"nodeSelector"`
+	// Config is a patch of the agent JSON config, merged into the Config for the agents of the nodes.
+	// Objects are merged recursively, other values of the patch replace the ones of the Config.
+	// +kubebuilder:validation:MinLength=1
+	Config string `json:"config"`
+}
+
+// NodeTerminationSpec defines how the agent pods of the nodes about to be terminated are deleted.
+type NodeTerminationSpec struct {
+	// GracePeriodSeconds is the grace period of the deletion of the agent pods, during which the agent flushes the
+	// telemetry it buffers after the SIGTERM. Defaults to the terminationGracePeriodSeconds of the pods.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+}
+
+// PipelineSplitSpec defines the deployments running the metrics and the traces of a split agent.
+type PipelineSplitSpec struct {
+	// Metrics is the deployment running the metrics section of the Config and the metrics pipelines of the
+	// OtelConfig.
+	// +optional
+	Metrics PipelineDeploymentSpec `json:"metrics,omitempty"`
+	// Traces is the deployment running the traces section of the Config and the traces pipelines of the
+	// OtelConfig.
+	// +optional
+	Traces PipelineDeploymentSpec `json:"traces,omitempty"`
+}
+
+// PipelineDeploymentSpec defines the scaling of the deployment of a signal of a split agent. The deployment is
+// only created when the Config or the OtelConfig has something to run for the signal.
+type PipelineDeploymentSpec struct {
+	// Replicas is the number of pods of the deployment. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Autoscaler scales the deployment horizontally between its minReplicas and maxReplicas, which is
+	// required. The minReplicas defaults to the Replicas.
+	// +optional
+	Autoscaler *AutoscalerSpec `json:"autoscaler,omitempty"`
+}
+
+// NodeGroupSpec defines the Config of the agents of the nodes carrying a label.
+type NodeGroupSpec struct {
+	// Name identifies the node group in the names of its daemonset and ConfigMap.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// NodeLabel is the label of the nodes of the group.
+	NodeLabel NodeLabel `json:"nodeLabel"`
+	// Config is a fragment of the agent JSON config, merged into the Config for the agents of the group.
+	// Objects are merged recursively, other values of the fragment replace the ones of the Config.
+	// +kubebuilder:validation:MinLength=1
+	Config string `json:"config"`
+}
+
+// NodeLabel is a label of the nodes.
+type NodeLabel struct {
+	// Key is the key of the label.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+	// Value is the value of the label.
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+// VersionSplitStatus compares the stable and the canary cohorts of a daemonset split between two
+// agent versions.
+type VersionSplitStatus struct {
+	// Stable is the status of the agents running the image of the AmazonCloudWatchAgent.
+	Stable CohortStatus `json:"stable"`
+	// Canary is the status of the agents running the image of the version split.
+	Canary CohortStatus `json:"canary"`
+}
+
+// CanaryRolloutPhase is the phase of a canary rollout.
+type CanaryRolloutPhase string
+
+const (
+	// CanaryRolloutProgressing means the change runs on the canary nodes, the other nodes running the stable configs.
+	CanaryRolloutProgressing CanaryRolloutPhase = "Progressing"
+	// CanaryRolloutSucceeded means the change runs on all the nodes.
+	CanaryRolloutSucceeded CanaryRolloutPhase = "Succeeded"
+	// CanaryRolloutRolledBack means the canary agents failed, and all the nodes run the stable configs until the
+	// configs change again.
+	CanaryRolloutRolledBack CanaryRolloutPhase = "RolledBack"
+)
+
+// CanaryRolloutStatus defines the progress of the canary rollout of a change of the configs.
+type CanaryRolloutStatus struct {
+	// Phase is the phase of the rollout: Progressing, Succeeded or RolledBack.
+	Phase CanaryRolloutPhase `json:"phase"`
+	// Revision identifies the Config and the OtelConfig rolled out.
+	Revision string `json:"revision"`
+	// StableConfig is the Config the agents out of the canary nodes run.
+	// +optional
+	StableConfig string `json:"stableConfig,omitempty"`
+	// StableOtelConfig is the OtelConfig the agents out of the canary nodes run.
+	// +optional
+	StableOtelConfig string `json:"stableOtelConfig,omitempty"`
+	// Nodes are the names of the canary nodes.
+	// +optional
+	// +listType=atomic
+	Nodes []string `json:"nodes,omitempty"`
+	// StartedAt is the time the change was rolled out to the canary nodes.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// ReadySince is the time since which all the canary agents are ready.
+	// +optional
+	ReadySince *metav1.Time `json:"readySince,omitempty"`
+	// ExportBaseline is the telemetry the canary agents had sent and failed to send when the bake started, by pod,
+	// which their export failures during the bake are counted from.
+	// +optional
+	ExportBaseline map[string]CanaryExportTotals `json:"exportBaseline,omitempty"`
+	// Message tells why the rollout is in its phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CanaryExportTotals defines the telemetry a canary agent sent and failed to send, summed over its exporters.
+type CanaryExportTotals struct {
+	// Sent is the number of the items of telemetry sent.
+	Sent int64 `json:"sent"`
+	// Failed is the number of the items of telemetry which failed to be sent.
+	Failed int64 `json:"failed"`
+}
+
+// RestartStatus defines the progress of a restart of the workloads of the instance. The workloads are restarted
+// one after the other, each one following its own update strategy.
+type RestartStatus struct {
+	// RequestedAt is the value of the restart annotation the restart is for.
+	RequestedAt string `json:"requestedAt"`
+	// Restarted lists the workloads restarted so far, in order. Only the last one can still be rolling out.
+	// +optional
+	// +listType=atomic
+	Restarted []string `json:"restarted,omitempty"`
+	// Completed tells whether all the workloads are restarted and rolled out.
+	// +optional
+	Completed bool `json:"completed,omitempty"`
+}
+
+// CohortStatus defines the observed state of the agents of a cohort.
+type CohortStatus struct {
+	// Image is the image of the agents of the cohort.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// DesiredNumberScheduled is the number of nodes which should run an agent of the cohort.
+	DesiredNumberScheduled int32 `json:"desiredNumberScheduled"`
+	// NumberReady is the number of agents of the cohort which are ready.
+	NumberReady int32 `json:"numberReady"`
+	// Restarts is the total number of restarts of the agent containers of the cohort.
+	Restarts int32 `json:"restarts"`
+}
+
+// WorkloadStatus defines the observed state of a workload running agents.
+type WorkloadStatus struct {
+	// Kind is the kind of the workload: DaemonSet, Deployment or StatefulSet.
+	Kind string `json:"kind"`
+	// Name is the name of the workload.
+	Name string `json:"name"`
+	// Desired is the number of pods the workload should run: the desired number of scheduled pods of a daemonset,
+	// or the replicas of a deployment or a statefulset.
+	Desired int32 `json:"desired"`
+	// Ready is the number of pods of the workload which are ready.
+	Ready int32 `json:"ready"`
+	// Updated is the number of pods of the workload running its latest pod template.
+	Updated int32 `json:"updated"`
+	// StatusReplicas is the number of ready pods over the desired ones, such as 2/3.
+	// +optional
+	StatusReplicas string `json:"statusReplicas,omitempty"`
+	// Image is the image of the agent container of the workload.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Version is the version of the agents of the workload, taken from its app.kubernetes.io/version label.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// ConfigHash is the hash of the config of the pod template of the workload, taken from its
+	// cloudwatch.aws.amazon.com/config-hash label.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+}
+
+// ConfigHashStatus counts the agent pods running a config.
+type ConfigHashStatus struct {
+	// Hash is the hash of the config, empty for the pods started before the operator labeled them with it.
+	// +optional
+	Hash string `json:"hash,omitempty"`
+	// Current tells whether the config is the one of the pod template of a workload of the agent. The pods running
+	// the other configs are yet to be rolled out.
+	Current bool `json:"current"`
+	// Pods is the number of pods running the config.
+	Pods int32 `json:"pods"`
+	// Ready is the number of ready pods running the config.
+	Ready int32 `json:"ready"`
+}
+
+// SecurityProfilesSpec defines the seccomp and AppArmor profiles of the agent pods.
+type SecurityProfilesSpec struct {
+	// Seccomp is the seccomp profile of the pods, set in their pod security context.
+	// +optional
+	Seccomp *v1.SeccompProfile `json:"seccomp,omitempty"`
+	// AppArmor is the AppArmor profile of the containers of the pods, set through their
+	// container.apparmor.security.beta.kubernetes.io annotations: runtime/default, localhost/<profile> or
+	// unconfined. The annotations set in podAnnotations take precedence.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(runtime/default|unconfined|localhost/.+)$`
+	AppArmor string `json:"appArmor,omitempty"`
+}
+
+// PrometheusReloadSpec defines the sidecar reloading the Prometheus configuration of the agent.
+type PrometheusReloadSpec struct {
+	// Image is the image of the reloader sidecar, which needs a shell with md5sum and pkill. Defaults to
+	// the image set in the operator.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Signal is the signal sent to the agent process when the configuration changes.
+	// +optional
+	// +kubebuilder:default:=HUP
+	// +kubebuilder:validation:Enum=HUP;USR1;USR2
+	Signal string `json:"signal,omitempty"`
+	// IntervalSeconds is the interval between two checks of the mounted configuration. Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
+}
+
+// ControlPlaneComponent is a component of the Kubernetes control plane exposing metrics.
+// +kubebuilder:validation:Enum=apiserver;controller-manager;scheduler
+type ControlPlaneComponent string
+
+const (
+	// ControlPlaneAPIServer is the API server, whose metrics include the ones of its etcd storage.
+	ControlPlaneAPIServer ControlPlaneComponent = "apiserver"
+	// ControlPlaneControllerManager is the controller manager, exposed through the API server by Amazon EKS.
+	ControlPlaneControllerManager ControlPlaneComponent = "controller-manager"
+	// ControlPlaneScheduler is the scheduler, exposed through the API server by Amazon EKS.
+	ControlPlaneScheduler ControlPlaneComponent = "scheduler"
+)
+
+// ControlPlaneMetricsSpec defines the components of the control plane whose metrics are scraped.
+type ControlPlaneMetricsSpec struct {
+	// Enabled adds the scrape configs of the control plane components to the Prometheus configuration.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Components are the components of the control plane to scrape. Defaults to all of them, the controller
+	// manager and the scheduler requiring Amazon EKS 1.28 or later.
+	// +optional
+	Components []ControlPlaneComponent `json:"components,omitempty"`
+	// ScrapeInterval is the interval between the scrapes of the components. Defaults to the global scrape interval
+	// of the Prometheus configuration.
+	// +optional
+	ScrapeInterval *metav1.Duration `json:"scrapeInterval,omitempty"`
+}
+
+// ControlPlaneJobName returns the name of the scrape job of a control plane component.
+func ControlPlaneJobName(component ControlPlaneComponent) string {
+	return "kubernetes-control-plane-" + string(component)
+}
+
+// LogsSpec defines the naming of the log groups of the agent.
+type LogsSpec struct {
+	// GroupNameTemplate is the log group name of the logs.logs_collected entries of the Config and of the
+	// awscloudwatchlogs exporters of the OtelConfig which don't set a log_group_name. The ${cluster_name} variable
+	// expands to the ClusterName, ${namespace} to the namespace of the AmazonCloudWatchAgent and ${pod} to the
+	// hostname of the agent pod, which is the pod name unless the pod uses the host network.
+	// +kubebuilder:validation:MinLength=1
+	GroupNameTemplate string `json:"groupNameTemplate"`
+	// ClusterName is the value of the ${cluster_name} variable. Defaults to the cluster_name of
+	// logs.metrics_collected.kubernetes in the Config, or else to the cluster name of the operator.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// DestinationSpec defines where the telemetry of a signal goes. The fields it sets take precedence over the ones of
+// the Config and the OtelConfig.
+type DestinationSpec struct {
+	// Signal is the signal of the telemetry: metrics, logs or traces.
+	// +kubebuilder:validation:Enum=metrics;logs;traces
+	Signal string `json:"signal"`
+	// Pipeline is the ID of the pipeline of the OtelConfig the destination applies to, such as metrics/app, whose
+	// awsemf, awscloudwatchlogs and awsxray exporters it is rendered into. An exporter shared with other pipelines
+	// is copied for the pipeline. When empty, the destination applies to the section of the signal of the Config.
+	// +optional
+	Pipeline string `json:"pipeline,omitempty"`
+	// Region is the AWS region the telemetry is sent to. The sections of the Config share the region of the agent,
+	// so the destinations of the Config must not set different regions.
+	// +optional
+	Region string `json:"region,omitempty"`
+	// LogGroup is the log group the logs, or the embedded metric format logs of the metrics, are written to. For
+	// the logs section of the Config, it is the log group of all the collected log files and Windows events.
+	// +optional
+	LogGroup string `json:"logGroup,omitempty"`
+	// MetricsNamespace is the CloudWatch namespace of the metrics.
+	// +optional
+	MetricsNamespace string `json:"metricsNamespace,omitempty"`
+}
+
+// ApplicationSignalsSpec defines the Application Signals collection of the agent.
+type ApplicationSignalsSpec struct {
+	// Enabled adds the application_signals sections to logs.metrics_collected and traces.traces_collected
+	// of the Config, unless the Config already defines them.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// IsEnabled returns whether Application Signals is enabled by the spec.
+func (a *ApplicationSignalsSpec) IsEnabled() bool {
+	return a != nil && a.Enabled
+}
+
+// DiagnosticsSpec defines the debug surfaces rendered into the OtelConfig.
+type DiagnosticsSpec struct {
+	// ZPages enables the zpages extension on localhost:55679, to inspect the live pipelines and traces.
+	// +optional
+	ZPages bool `json:"zpages,omitempty"`
+	// Tap adds the remotetap processor at the end of every pipeline on localhost:12001, to stream samples
+	// of the telemetry flowing through them.
+	// +optional
+	Tap bool `json:"tap,omitempty"`
+}
+
+const (
+	// DefaultDebugPprofPort is the port of the pprof endpoint of the agents which don't set one.
+	DefaultDebugPprofPort int32 = 1777
+	// DefaultDebugZPagesPort is the port of the zpages endpoint of the agents which don't set one.
+	DefaultDebugZPagesPort int32 = 55679
+)
+
+// DebugSpec defines the debug endpoints of the agent exposed to the cluster.
+type DebugSpec struct {
+	// Enabled adds the pprof and zpages ports to the agent container, and exposes them through the
+	// <name>-debug ClusterIP Service, which the serviceType and the serviceAnnotations don't apply to.
+	// Not supported on the host network, which would expose the endpoints on the nodes.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// PprofPort is the port of the pprof endpoint. Defaults to 1777.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	PprofPort int32 `json:"pprofPort,omitempty"`
+	// ZPagesPort is the port of the zpages endpoint. Defaults to 55679.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	ZPagesPort int32 `json:"zpagesPort,omitempty"`
+	// InjectConfig adds the pprof and zpages extensions listening on these ports to the OtelConfig. Otherwise,
+	// the agent is expected to serve the endpoints itself.
+	// +optional
+	InjectConfig bool `json:"injectConfig,omitempty"`
+}
+
+// IsEnabled returns whether the debug endpoints are exposed by the spec.
+func (d *DebugSpec) IsEnabled() bool {
+	return d != nil && d.Enabled
+}
+
 func init() {
 	SchemeBuilder.Register(&AmazonCloudWatchAgent{}, &AmazonCloudWatchAgentList{})
 }
@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AmazonCloudWatchAgentConfigOverrideSpec defines the patches layered onto the configs of the selected agents.
+type AmazonCloudWatchAgentConfigOverrideSpec struct {
+	// Priority orders the overrides selecting the same agent: they are applied by increasing priority, then by
+	// name, so that the override with the highest priority wins over the others.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// Selector selects the AmazonCloudWatchAgents the override applies to by their labels, in every namespace.
+	// The override applies to all the agents when empty.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Config is a JSON merge patch (RFC 7386) applied to the Config of the selected agents: its objects are merged
+	// into the ones of the agents, its other values, arrays included, replace theirs, and its null values remove
+	// them.
+	// +optional
+	Config string `json:"config,omitempty"`
+	// OtelConfig is a YAML merge patch applied to the OtelConfig of the selected agents, following the rules of
+	// the Config. The agents without an OtelConfig are left without one.
+	// +optional
+	OtelConfig string `json:"otelConfig,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=cwagentoverride;cwagentoverrides
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:displayName="Amazon CloudWatch Agent Config Override"
+
+// AmazonCloudWatchAgentConfigOverride is the Schema for the AmazonCloudWatchAgentConfigOverride API. The operator
+// layers its patches onto the configs of the selected AmazonCloudWatchAgents when it renders them, without changing
+// the AmazonCloudWatchAgents themselves, when it runs with --enable-config-overrides.
+type AmazonCloudWatchAgentConfigOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AmazonCloudWatchAgentConfigOverrideSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AmazonCloudWatchAgentConfigOverrideList contains a list of AmazonCloudWatchAgentConfigOverride.
+type AmazonCloudWatchAgentConfigOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AmazonCloudWatchAgentConfigOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AmazonCloudWatchAgentConfigOverride{}, &AmazonCloudWatchAgentConfigOverrideList{})
+}
@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultTenantTemplate is the template of the tenant namespaces which don't select one.
+const DefaultTenantTemplate = "default"
+
+// AmazonCloudWatchAgentTemplateSpec defines the AmazonCloudWatchAgent stamped out in each tenant namespace.
+type AmazonCloudWatchAgentTemplateSpec struct {
+	// AgentName is the name of the AmazonCloudWatchAgent stamped out in the tenant namespaces.
+	// Defaults to cloudwatch-agent.
+	// +optional
+	AgentName string `json:"agentName,omitempty"`
+	// Labels are added to the labels of the stamped out agents.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the annotations of the stamped out agents.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Agent is the spec of the stamped out agents. The {namespace} variable is replaced with the tenant namespace
+	// in every string of the spec, such as the agent config, to keep the telemetry of the tenants apart.
+	// +required
+	Agent AmazonCloudWatchAgentSpec `json:"agent"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=cwagenttemplate;cwagenttemplates
+// +kubebuilder:printcolumn:name="Agent",type="string",JSONPath=".spec.agentName"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:displayName="Amazon CloudWatch Agent Template"
+
+// AmazonCloudWatchAgentTemplate is the Schema for the AmazonCloudWatchAgentTemplate API. The operator stamps out an
+// AmazonCloudWatchAgent from it in each namespace labeled cloudwatch.aws.amazon.com/tenant=true, when it runs
+// with --enable-tenants.
+type AmazonCloudWatchAgentTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AmazonCloudWatchAgentTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AmazonCloudWatchAgentTemplateList contains a list of AmazonCloudWatchAgentTemplate.
+type AmazonCloudWatchAgentTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AmazonCloudWatchAgentTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AmazonCloudWatchAgentTemplate{}, &AmazonCloudWatchAgentTemplateList{})
+}
@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Args are the arguments passed to the agent binary, written either as a map of flags or as a list. The flags of
+// the map are passed as --key=value, sorted by key, and the list is passed as is, which allows positional arguments
+// and flags repeated several times.
+type Args struct {
+	// Flags are the arguments written as a map.
+	Flags map[string]string `json:"-"`
+	// List are the arguments written as a list.
+	List []string `json:"-"`
+	// invalid holds the arguments written in neither form, kept for the validating webhook to reject them rather than
+	// failing to decode the whole agent, which would stop the operator from listing the agents.
+	invalid json.RawMessage
+}
+
+var _ json.Marshaler = Args{}
+var _ json.Unmarshaler = &Args{}
+
+// UnmarshalJSON accepts both the map and the list forms of the arguments. Any other value is kept as written, and
+// reported by Valid.
+func (a *Args) UnmarshalJSON(b []byte) error {
+	*a = Args{}
+	b = bytes.TrimSpace(b)
+	var err error
+	switch {
+	case bytes.Equal(b, []byte("null")):
+		return nil
+	case bytes.HasPrefix(b, []byte("[")):
+		err = json.Unmarshal(b, &a.List)
+	case bytes.HasPrefix(b, []byte("{")):
+		err = json.Unmarshal(b, &a.Flags)
+	}
+	if err != nil || (a.List == nil && a.Flags == nil) {
+		*a = Args{invalid: append(json.RawMessage(nil), b...)}
+	}
+	return nil
+}
+
+// Valid returns whether the arguments are written as a map or as a list of strings.
+func (a *Args) Valid() bool {
+	return a == nil || a.invalid == nil
+}
+
+// hasFlag returns whether the arguments set the flag with the given name.
+func (a *Args) hasFlag(name string) bool {
+	if a == nil {
+		return false
+	}
+	if _, ok := a.Flags[name]; ok {
+		return true
+	}
+	for _, arg := range a.List {
+		if arg == "--"+name || strings.HasPrefix(arg, "--"+name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON writes the arguments back in the form they were written in.
+func (a Args) MarshalJSON() ([]byte, error) {
+	if a.invalid != nil {
+		return a.invalid, nil
+	}
+	if a.List != nil {
+		return json.Marshal(a.List)
+	}
+	return json.Marshal(a.Flags)
+}
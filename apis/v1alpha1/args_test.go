@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgsJSON(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		json     string
+		expected Args
+	}{
+		{
+			desc:     "map",
+			json:     `{"log-level":"debug"}`,
+			expected: Args{Flags: map[string]string{"log-level": "debug"}},
+		},
+		{
+			desc:     "list",
+			json:     `["--feature-gates=a","--feature-gates=b"]`,
+			expected: Args{List: []string{"--feature-gates=a", "--feature-gates=b"}},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			var spec AmazonCloudWatchAgentSpec
+			require.NoError(t, json.Unmarshal([]byte(`{"args":`+tt.json+`}`), &spec))
+			assert.Equal(t, &tt.expected, spec.Args)
+
+			raw, err := json.Marshal(spec.Args)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(raw))
+		})
+	}
+
+	// the other values are kept as written for the webhook to reject them, the agent still decoding
+	for _, invalid := range []string{`"--log-level=debug"`, `{"log-level":3}`, `["--log-level",3]`} {
+		var spec AmazonCloudWatchAgentSpec
+		require.NoError(t, json.Unmarshal([]byte(`{"args":`+invalid+`}`), &spec))
+		assert.False(t, spec.Args.Valid())
+		copied := spec.DeepCopy()
+		raw, err := json.Marshal(copied.Args)
+		require.NoError(t, err)
+		assert.JSONEq(t, invalid, string(raw))
+	}
+}
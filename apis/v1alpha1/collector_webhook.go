@@ -5,25 +5,43 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
+	_ "github.com/prometheus/prometheus/discovery/install" // Package install has the side-effect of registering all builtin.
+	"gopkg.in/yaml.v2"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/agentgates"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	ta "github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/targetallocator/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/promguardrails"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 )
 
 var (
 	_ admission.CustomValidator = &CollectorWebhook{}
 	_ admission.CustomDefaulter = &CollectorWebhook{}
+
+	iamRoleArnRegexp           = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/.+$`)
+	logGroupNameVariableRegexp = regexp.MustCompile(`\$\{[^{}]*\}`)
 )
 
 // +kubebuilder:webhook:path=/mutate-cloudwatch-aws-amazon-com-v1alpha1-amazoncloudwatchagent,mutating=true,failurePolicy=fail,groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=create;update,versions=v1alpha1,name=mamazoncloudwatchagent.kb.io,sideEffects=none,admissionReviewVersions=v1
@@ -35,6 +53,8 @@ type CollectorWebhook struct {
 	logger logr.Logger
 	cfg    config.Config
 	scheme *runtime.Scheme
+	// reader lists the agents of the namespace whose objects could take the same names, when set.
+	reader client.Reader
 }
 
 func (c CollectorWebhook) Default(ctx context.Context, obj runtime.Object) error {
@@ -50,6 +70,9 @@ func (c CollectorWebhook) ValidateCreate(ctx context.Context, obj runtime.Object
 	if !ok {
 		return nil, fmt.Errorf("expected an AmazonCloudWatchAgent, received %T", obj)
 	}
+	if err := c.checkResourceNames(ctx, otelcol); err != nil {
+		return nil, err
+	}
 	return c.validate(otelcol)
 }
 
@@ -58,6 +81,13 @@ func (c CollectorWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj run
 	if !ok {
 		return nil, fmt.Errorf("expected an AmazonCloudWatchAgent, received %T", newObj)
 	}
+	// the finalizers of a deleted agent are removed whether its spec is still valid or not
+	if otelcol.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	if err := c.checkResourceNames(ctx, otelcol); err != nil {
+		return nil, err
+	}
 	return c.validate(otelcol)
 }
 
@@ -66,15 +96,46 @@ func (c CollectorWebhook) ValidateDelete(ctx context.Context, obj runtime.Object
 	if !ok || otelcol == nil {
 		return nil, fmt.Errorf("expected an AmazonCloudWatchAgent, received %T", obj)
 	}
+	if otelcol.DeletionTimestamp != nil {
+		return nil, nil
+	}
 	return c.validate(otelcol)
 }
 
+// checkResourceNames rejects the agent when another agent of its namespace names its objects the same, through
+// their nameOverride or their name.
+func (c CollectorWebhook) checkResourceNames(ctx context.Context, r *AmazonCloudWatchAgent) error {
+	if c.reader == nil {
+		return nil
+	}
+	agents := &AmazonCloudWatchAgentList{}
+	if err := c.reader.List(ctx, agents, client.InNamespace(r.Namespace)); err != nil {
+		return fmt.Errorf("failed to list the agents of the namespace %s: %w", r.Namespace, err)
+	}
+	for i := range agents.Items {
+		other := &agents.Items[i]
+		if other.Name != r.Name && other.ResourceName() == r.ResourceName() {
+			return fmt.Errorf("the objects of the agent would be named %s like the objects of the agent %s, set another nameOverride", r.ResourceName(), other.Name)
+		}
+	}
+	return nil
+}
+
+// ApplyDefaults sets the defaults of the webhook on the agent. The controllers which write the spec of the agents
+// they own apply them before the update, so that their spec matches the spec stored after the webhook.
+func ApplyDefaults(r *AmazonCloudWatchAgent) {
+	_ = CollectorWebhook{}.defaulter(r)
+}
+
 func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
+	defaults := map[string]string{}
 	if len(r.Spec.Mode) == 0 {
 		r.Spec.Mode = ModeDeployment
+		defaults["mode"] = string(r.Spec.Mode)
 	}
 	if len(r.Spec.UpgradeStrategy) == 0 {
 		r.Spec.UpgradeStrategy = UpgradeStrategyAutomatic
+		defaults["upgradeStrategy"] = string(r.Spec.UpgradeStrategy)
 	}
 
 	if r.Labels == nil {
@@ -89,9 +150,11 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 	one := int32(1)
 	if r.Spec.Replicas == nil {
 		r.Spec.Replicas = &one
+		defaults["replicas"] = strconv.Itoa(int(one))
 	}
 	if r.Spec.TargetAllocator.Enabled && r.Spec.TargetAllocator.Replicas == nil {
 		r.Spec.TargetAllocator.Replicas = &one
+		defaults["targetAllocator.replicas"] = strconv.Itoa(int(one))
 	}
 
 	if r.Spec.MaxReplicas != nil || (r.Spec.Autoscaler != nil && r.Spec.Autoscaler.MaxReplicas != nil) {
@@ -113,6 +176,7 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 		if r.Spec.Autoscaler.TargetMemoryUtilization == nil && r.Spec.Autoscaler.TargetCPUUtilization == nil {
 			defaultCPUTarget := int32(90)
 			r.Spec.Autoscaler.TargetCPUUtilization = &defaultCPUTarget
+			defaults["autoscaler.targetCPUUtilization"] = strconv.Itoa(int(defaultCPUTarget))
 		}
 	}
 
@@ -127,6 +191,7 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 				IntVal: 1,
 			},
 		}
+		defaults["podDisruptionBudget.maxUnavailable"] = r.Spec.PodDisruptionBudget.MaxUnavailable.String()
 	}
 
 	if r.Spec.Ingress.Type == IngressTypeRoute && r.Spec.Ingress.Route.Termination == "" {
@@ -135,14 +200,72 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 	if r.Spec.Ingress.Type == IngressTypeNginx && r.Spec.Ingress.RuleType == "" {
 		r.Spec.Ingress.RuleType = IngressRuleTypePath
 	}
+	if r.Spec.Debug.IsEnabled() {
+		if r.Spec.Debug.PprofPort == 0 {
+			r.Spec.Debug.PprofPort = DefaultDebugPprofPort
+			defaults["debug.pprofPort"] = strconv.Itoa(int(DefaultDebugPprofPort))
+		}
+		if r.Spec.Debug.ZPagesPort == 0 {
+			r.Spec.Debug.ZPagesPort = DefaultDebugZPagesPort
+			defaults["debug.zpagesPort"] = strconv.Itoa(int(DefaultDebugZPagesPort))
+		}
+	}
+
 	// If someone upgrades to a later version without upgrading their CRD they will not have a management state set.
 	// This results in a default state of unmanaged preventing reconciliation from continuing.
 	if len(r.Spec.ManagementState) == 0 {
 		r.Spec.ManagementState = ManagementStateManaged
+		defaults["managementState"] = string(r.Spec.ManagementState)
 	}
+	RecordDefaultsApplied(r, defaultedFields(r), defaults)
 	return nil
 }
 
+// windowsEventsPrivilegesWarning describes the privileges the pods of an agent collecting Windows event logs are
+// escalated to, which the event logs only readable from the host require.
+func windowsEventsPrivilegesWarning(r *AmazonCloudWatchAgent) string {
+	user := "NT AUTHORITY\\SYSTEM"
+	if sc := r.Spec.PodSecurityContext; sc != nil && sc.WindowsOptions != nil && sc.WindowsOptions.RunAsUserName != nil {
+		user = *sc.WindowsOptions.RunAsUserName
+	}
+	return fmt.Sprintf("collecting Windows event logs escalates the privileges of the agent pods: they run as HostProcess containers with the host network, as the user %s", user)
+}
+
+// defaultedFields returns the current values of the fields defaulted by the webhook, formatted as their recorded
+// defaults.
+func defaultedFields(r *AmazonCloudWatchAgent) map[string]string {
+	fields := map[string]string{
+		"mode":                               string(r.Spec.Mode),
+		"upgradeStrategy":                    string(r.Spec.UpgradeStrategy),
+		"replicas":                           formatReplicas(r.Spec.Replicas),
+		"targetAllocator.replicas":           formatReplicas(r.Spec.TargetAllocator.Replicas),
+		"autoscaler.targetCPUUtilization":    "",
+		"podDisruptionBudget.maxUnavailable": "",
+		"debug.pprofPort":                    "",
+		"debug.zpagesPort":                   "",
+		"managementState":                    string(r.Spec.ManagementState),
+	}
+	if r.Spec.Autoscaler != nil {
+		fields["autoscaler.targetCPUUtilization"] = formatReplicas(r.Spec.Autoscaler.TargetCPUUtilization)
+	}
+	if r.Spec.PodDisruptionBudget != nil && r.Spec.PodDisruptionBudget.MaxUnavailable != nil {
+		fields["podDisruptionBudget.maxUnavailable"] = r.Spec.PodDisruptionBudget.MaxUnavailable.String()
+	}
+	if r.Spec.Debug != nil {
+		fields["debug.pprofPort"] = strconv.Itoa(int(r.Spec.Debug.PprofPort))
+		fields["debug.zpagesPort"] = strconv.Itoa(int(r.Spec.Debug.ZPagesPort))
+	}
+	return fields
+}
+
+// formatReplicas formats an optional count as its recorded default, empty when unset.
+func formatReplicas(replicas *int32) string {
+	if replicas == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*replicas))
+}
+
 func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings, error) {
 	warnings := admission.Warnings{}
 	// validate volumeClaimTemplates
@@ -150,6 +273,295 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'volumeClaimTemplates'", r.Spec.Mode)
 	}
 
+	// validate persistence
+	if r.Spec.Persistence != nil {
+		if r.Spec.Mode != ModeStatefulSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'persistence'", r.Spec.Mode)
+		}
+		if r.Spec.Persistence.Directory != "" && !path.IsAbs(r.Spec.Persistence.Directory) {
+			return warnings, fmt.Errorf("the persistence directory %q must be an absolute path", r.Spec.Persistence.Directory)
+		}
+		if r.Spec.OtelConfig != "" {
+			directory := r.Spec.Persistence.Directory
+			if directory == "" {
+				directory = adapters.DefaultFileStorageDirectory
+			}
+			otelCfg, err := adapters.ConfigFromString(r.Spec.OtelConfig)
+			if err != nil {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec OtelConfig is incorrect, %w", err)
+			}
+			if err := adapters.ConfigWithPersistentQueue(otelCfg, directory); err != nil {
+				return warnings, fmt.Errorf("the OpenTelemetry Spec OtelConfig can't be persisted, %w", err)
+			}
+		}
+	}
+
+	// validate otlp receiver settings, which the JSON config of the agent can't take
+	if otlp := r.Spec.OTLPReceiver; (otlp.GRPCMaxRecvMsgSizeMiB != nil || otlp.HTTPMaxRequestBodySize != nil || len(otlp.HTTPCompressionAlgorithms) > 0) && r.Spec.OtelConfig == "" {
+		return warnings, fmt.Errorf("the attribute 'otlpReceiver' only applies to the otlp receivers of the 'otelConfig', which is not set")
+	}
+	if size := r.Spec.OTLPReceiver.HTTPMaxRequestBodySize; size != nil && size.Sign() <= 0 {
+		return warnings, fmt.Errorf("the OTLP receiver httpMaxRequestBodySize must be positive, got %s", size.String())
+	}
+
+	// validate tls
+	if r.Spec.TLS != nil && r.Spec.TLS.SecretName == "" && r.Spec.TLS.CertManager == nil {
+		return warnings, fmt.Errorf("the attribute 'tls' requires either 'secretName' or 'certManager'")
+	}
+	if r.Spec.TLS != nil && r.Spec.TLS.SecretNamespace != "" && (r.Spec.TLS.SecretName == "" || r.Spec.TLS.CertManager != nil) {
+		return warnings, fmt.Errorf("the attribute 'tls.secretNamespace' requires 'secretName', and can't be set together with 'certManager' which issues the certificate into the namespace of the agent")
+	}
+
+	// validate image digest
+	if _, digest, found := strings.Cut(r.Spec.Image, "@"); found && r.Spec.ImageDigest != "" && digest != r.Spec.ImageDigest {
+		return warnings, fmt.Errorf("the imageDigest %s conflicts with the digest %s of the image", r.Spec.ImageDigest, digest)
+	}
+
+	// validate name override
+	if r.Spec.NameOverride != "" {
+		if errs := validation.IsDNS1123Label(r.Spec.NameOverride); len(errs) > 0 {
+			return warnings, fmt.Errorf("the nameOverride %q is not a valid name: %s", r.Spec.NameOverride, strings.Join(errs, ", "))
+		}
+	}
+
+	// validate service account annotations
+	if r.Spec.ServiceAccount != "" && (r.Spec.IAMRoleArn != "" || len(r.Spec.ServiceAccountAnnotations) > 0) {
+		return warnings, fmt.Errorf("the attributes 'iamRoleArn' and 'serviceAccountAnnotations' can't be set together with 'serviceAccount', annotate the existing service account %s instead", r.Spec.ServiceAccount)
+	}
+	if r.Spec.IAMRoleArn != "" {
+		if !iamRoleArnRegexp.MatchString(r.Spec.IAMRoleArn) {
+			return warnings, fmt.Errorf("the iamRoleArn %q is not a valid IAM role ARN", r.Spec.IAMRoleArn)
+		}
+		if roleArn, ok := r.Spec.ServiceAccountAnnotations[constants.AnnotationIAMRoleArn]; ok && roleArn != r.Spec.IAMRoleArn {
+			return warnings, fmt.Errorf("the iamRoleArn %q conflicts with the %s service account annotation %q", r.Spec.IAMRoleArn, constants.AnnotationIAMRoleArn, roleArn)
+		}
+	}
+
+	// validate role to assume
+	if r.Spec.RoleArn != "" {
+		if !iamRoleArnRegexp.MatchString(r.Spec.RoleArn) {
+			return warnings, fmt.Errorf("the roleArn %q is not a valid IAM role ARN", r.Spec.RoleArn)
+		}
+		if config, err := adapters.ConfigFromJSONString(r.Spec.Config); err == nil {
+			agent, _ := config["agent"].(map[string]interface{})
+			credentials, _ := agent["credentials"].(map[string]interface{})
+			if roleArn, ok := credentials["role_arn"].(string); ok && roleArn != r.Spec.RoleArn {
+				return warnings, fmt.Errorf("the roleArn %q conflicts with the role_arn %q of the agent credentials of the config", r.Spec.RoleArn, roleArn)
+			}
+		}
+	}
+
+	// validate xray settings
+	if r.Spec.XRay != nil {
+		cwaConfig, err := adapters.ConfigStructFromJSONString(r.Spec.Config)
+		if err != nil || cwaConfig == nil || cwaConfig.Traces == nil || cwaConfig.Traces.TracesCollected == nil || cwaConfig.Traces.TracesCollected.XRay == nil {
+			return warnings, fmt.Errorf("the attribute 'xray' requires the xray receiver in traces.traces_collected of the Amazon CloudWatch Agent config, which exposes the xray port")
+		}
+	}
+
+	// validate prometheus reload
+	if r.Spec.PrometheusReload != nil {
+		if r.Spec.Prometheus.IsEmpty() {
+			warnings = append(warnings, "the attribute 'prometheusReload' is ignored without the attribute 'prometheus'")
+		} else if r.Spec.NodeSelector["kubernetes.io/os"] == "windows" {
+			warnings = append(warnings, "the attribute 'prometheusReload' is ignored for Windows agents")
+		} else if r.Spec.ShareProcessNamespace != nil && !*r.Spec.ShareProcessNamespace {
+			return warnings, fmt.Errorf("the attribute 'prometheusReload' requires a shared process namespace, 'shareProcessNamespace' can't be false")
+		}
+	}
+
+	// validate config reload
+	if r.Spec.ConfigReload == ConfigReloadHotReload && r.Spec.Mode != ModeSidecar && r.Spec.NodeSelector["kubernetes.io/os"] != "windows" &&
+		r.Spec.ShareProcessNamespace != nil && !*r.Spec.ShareProcessNamespace {
+		return warnings, fmt.Errorf("the attribute 'configReload' HotReload requires a shared process namespace, 'shareProcessNamespace' can't be false")
+	}
+	if r.Spec.ConfigReload == ConfigReloadHotReload && c.cfg.ConfigTranslator() == nil {
+		warnings = append(warnings, "the attribute 'configReload' HotReload requires the operator to translate the configs, with --config-translator, the config changes roll out the pods")
+	}
+
+	// validate self-telemetry
+	if r.Spec.Observability.SelfTelemetry != nil {
+		if cwaConfig, err := adapters.ConfigFromJSONString(r.Spec.Config); err == nil {
+			agent, _ := cwaConfig["agent"].(map[string]interface{})
+			if logFile, _ := agent["logfile"].(string); logFile == "" {
+				warnings = append(warnings, "the logs of the agent are not collected by the attribute 'observability.selfTelemetry' without agent.logfile in the Amazon CloudWatch Agent config")
+			}
+		}
+	}
+
+	// validate alarm actions
+	if r.Spec.Alarms != nil {
+		for _, arn := range r.Spec.Alarms.ActionARNs {
+			if !strings.HasPrefix(arn, "arn:") {
+				return warnings, fmt.Errorf("the attribute 'alarms.actionArns' contains %q, which is not an ARN", arn)
+			}
+		}
+	}
+
+	// validate log group name template
+	if r.Spec.Logs != nil {
+		for _, variable := range logGroupNameVariableRegexp.FindAllString(r.Spec.Logs.GroupNameTemplate, -1) {
+			switch variable {
+			case "${cluster_name}":
+				if r.Spec.Logs.ClusterName != "" || c.cfg.ClusterName() != "" {
+					continue
+				}
+				if cwaConfig, err := adapters.ConfigStructFromJSONString(r.Spec.Config); err != nil || cwaConfig == nil || cwaConfig.GetClusterName() == "" {
+					return warnings, fmt.Errorf("the attribute 'logs.groupNameTemplate' uses ${cluster_name}, which requires 'logs.clusterName', the cluster_name of logs.metrics_collected.kubernetes in the Amazon CloudWatch Agent config or the cluster name of the operator")
+				}
+			case "${namespace}", "${pod}":
+			default:
+				return warnings, fmt.Errorf("the attribute 'logs.groupNameTemplate' contains the unknown variable %s, the supported variables are ${cluster_name}, ${namespace} and ${pod}", variable)
+			}
+		}
+	}
+
+	// validate extra mounts
+	for i, m := range r.Spec.ExtraMounts {
+		if (m.ConfigMap == "") == (m.Secret == "") {
+			return warnings, fmt.Errorf("the attribute 'extraMounts[%d]' must set exactly one of 'configMap' and 'secret'", i)
+		}
+		// Windows paths are left to the kubelet
+		if r.Spec.NodeSelector["kubernetes.io/os"] != "windows" && !path.IsAbs(m.MountPath) {
+			return warnings, fmt.Errorf("the attribute 'extraMounts[%d].mountPath' must be an absolute path", i)
+		}
+	}
+
+	// validate container override
+	if r.Spec.ContainerOverride != nil {
+		override := corev1.Container{}
+		if err := json.Unmarshal(r.Spec.ContainerOverride.Raw, &override); err != nil {
+			return warnings, fmt.Errorf("the attribute 'containerOverride' is not a valid container patch, %w", err)
+		}
+		if override.Name != "" {
+			return warnings, fmt.Errorf("the attribute 'containerOverride' can't change the name of the agent container")
+		}
+	}
+
+	// validate diagnostics
+	if r.Spec.Diagnostics != nil && (r.Spec.Diagnostics.ZPages || r.Spec.Diagnostics.Tap) && r.Spec.OtelConfig == "" {
+		return warnings, fmt.Errorf("the attribute 'diagnostics' only applies to the pipelines of the 'otelConfig', which is not set")
+	}
+
+	// validate debug
+	if debug := r.Spec.Debug; debug.IsEnabled() {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'debug'", r.Spec.Mode)
+		}
+		// the endpoints listen on all the interfaces of the pod, which are the ones of the node with the host network
+		if r.Spec.HostNetwork {
+			return warnings, fmt.Errorf("the attribute 'debug' can't be enabled with 'hostNetwork', which would expose the pprof and zpages endpoints on the node")
+		}
+		if debug.PprofPort == debug.ZPagesPort {
+			return warnings, fmt.Errorf("the attribute 'debug' uses the port %d for both pprof and zpages", debug.PprofPort)
+		}
+		if debug.InjectConfig && r.Spec.OtelConfig == "" {
+			return warnings, fmt.Errorf("the attribute 'debug.injectConfig' only applies to the 'otelConfig', which is not set")
+		}
+		if debug.InjectConfig && r.Spec.Diagnostics != nil && r.Spec.Diagnostics.ZPages {
+			return warnings, fmt.Errorf("the attribute 'debug.injectConfig' conflicts with 'diagnostics.zpages', which keeps zpages on localhost")
+		}
+	}
+
+	// validate go runtime
+	if r.Spec.GoRuntime != nil && r.Spec.GoRuntime.MemoryLimit != nil && r.Spec.GoRuntime.MemoryLimit.Sign() <= 0 {
+		return warnings, fmt.Errorf("the attribute 'goRuntime.memoryLimit' must be positive")
+	}
+
+	// validate security profiles
+	if profiles := r.Spec.SecurityProfiles; profiles != nil {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'securityProfiles'", r.Spec.Mode)
+		}
+		if r.Spec.NodeSelector["kubernetes.io/os"] == "windows" {
+			return warnings, fmt.Errorf("the attribute 'securityProfiles' is not supported on Windows")
+		}
+		if profiles.Seccomp != nil && r.Spec.PodSecurityContext != nil && r.Spec.PodSecurityContext.SeccompProfile != nil {
+			return warnings, fmt.Errorf("the attributes 'securityProfiles.seccomp' and 'podSecurityContext.seccompProfile' can't both be set")
+		}
+	}
+
+	// validate trusted CA
+	if trustedCA := r.Spec.TrustedCA; trustedCA != nil {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'trustedCA'", r.Spec.Mode)
+		}
+		if (trustedCA.ConfigMap == "") == (trustedCA.Secret == "") {
+			return warnings, fmt.Errorf("the attribute 'trustedCA' must set exactly one of 'configMap' and 'secret'")
+		}
+		// the agents of Windows nodes only trust the certificate store of the node
+		if r.Spec.NodeSelector["kubernetes.io/os"] == "windows" {
+			return warnings, fmt.Errorf("the attribute 'trustedCA' is not supported on Windows, add the certificate authorities to the certificate store of the nodes instead")
+		}
+	}
+
+	// validate the log files, whose directories are mounted from the hosts of the daemonset agents
+	if r.Spec.Mode == ModeDaemonSet {
+		if cwaConfig, err := adapters.ConfigStructFromJSONString(r.Spec.Config); err == nil && cwaConfig != nil {
+			for _, filePath := range cwaConfig.GetLogFilePaths() {
+				if strings.HasPrefix(filePath, "/") && adapters.LogDirectory(filePath) == "" {
+					return warnings, fmt.Errorf("the file_path %q of logs.logs_collected.files would mount the root of the host or one of its top-level directories, its directory must be at least two levels deep, such as /var/log", filePath)
+				}
+			}
+		}
+	}
+
+	// validate windows event logs
+	if cwaConfig, err := adapters.ConfigStructFromJSONString(r.Spec.Config); err == nil && cwaConfig != nil {
+		if err := cwaConfig.ValidateWindowsEvents(); err != nil {
+			return warnings, fmt.Errorf("the Amazon CloudWatch Agent config is incorrect, %w", err)
+		}
+		if len(cwaConfig.GetWindowsEvents()) > 0 {
+			if r.Spec.Mode != ModeDaemonSet || r.Spec.NodeSelector["kubernetes.io/os"] != "windows" {
+				warnings = append(warnings, "Windows event logs are only collected by a daemonset with the kubernetes.io/os: windows node selector")
+			} else if r.Spec.Debug.IsEnabled() {
+				return warnings, fmt.Errorf("the attribute 'debug' can't be enabled when collecting Windows event logs, whose HostProcess pods would expose the pprof and zpages endpoints on the node")
+			} else if sc := r.Spec.PodSecurityContext; sc != nil && sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && !*sc.WindowsOptions.HostProcess {
+				return warnings, fmt.Errorf("collecting Windows event logs requires HostProcess pods, 'podSecurityContext.windowsOptions.hostProcess' can't be false")
+			} else {
+				warnings = append(warnings, windowsEventsPrivilegesWarning(r))
+			}
+		}
+	}
+
+	// validate the destinations, the exporters and the Prometheus scrape configs against the policies of the operator
+	policyWarnings, err := ValidateConfigPolicies(c.cfg, r)
+	warnings = append(warnings, policyWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+
+	// validate the Prometheus scrape helpers
+	if err := validatePrometheusHelpers(r.Spec.Prometheus); err != nil {
+		return warnings, err
+	}
+
+	// validate controlPlaneMetrics
+	if spec := r.Spec.ControlPlaneMetrics; spec != nil && spec.Enabled {
+		if r.Spec.Mode == ModeDaemonSet || r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'controlPlaneMetrics'", r.Spec.Mode)
+		}
+		if err := checkControlPlaneMetrics(*spec, r.Spec.Prometheus); err != nil {
+			return warnings, fmt.Errorf("the attribute 'controlPlaneMetrics' is incorrect, %w", err)
+		}
+		if r.Spec.Replicas != nil && *r.Spec.Replicas > 1 && !r.Spec.TargetAllocator.Enabled {
+			warnings = append(warnings, fmt.Sprintf("each of the %d replicas scrapes the control plane, which duplicates its metrics unless the target allocator is enabled", *r.Spec.Replicas))
+		}
+	}
+
+	// validate the versions against the support matrix
+	if checker := c.cfg.CompatibilityChecker(); checker != nil {
+		image := r.Spec.Image
+		if image == "" {
+			image = c.cfg.CollectorImage()
+		}
+		problems := checker.Check(image)
+		if len(problems) > 0 && checker.Enforce {
+			return warnings, fmt.Errorf("the versions are not supported together: %s", strings.Join(problems, "; "))
+		}
+		warnings = append(warnings, problems...)
+	}
+
 	// validate tolerations
 	if r.Spec.Mode == ModeSidecar && len(r.Spec.Tolerations) > 0 {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'tolerations'", r.Spec.Mode)
@@ -160,6 +572,15 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'priorityClassName'", r.Spec.Mode)
 	}
 
+	// validate host namespaces
+	if r.Spec.Mode == ModeSidecar && (r.Spec.HostPID || r.Spec.HostIPC || r.Spec.ShareProcessNamespace != nil) {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attributes 'hostPID', 'hostIPC' and 'shareProcessNamespace'", r.Spec.Mode)
+	}
+	// the pods can't both share the process namespace of their containers and join the one of the node
+	if share := r.SharedProcessNamespace(); r.Spec.HostPID && share != nil && *share {
+		return warnings, fmt.Errorf("the attribute 'hostPID' can't be combined with a shared process namespace, which 'shareProcessNamespace', 'prometheusReload' and the HotReload 'configReload' enable")
+	}
+
 	// validate affinity
 	if r.Spec.Mode == ModeSidecar && r.Spec.Affinity != nil {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'affinity'", r.Spec.Mode)
@@ -170,6 +591,9 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 	}
 
 	// validate target allocation
+	if r.Spec.TargetAllocator.Enabled && !c.cfg.TargetAllocatorEnabled() {
+		return warnings, fmt.Errorf("the target allocator is disabled in the operator, which does not support the attribute 'targetAllocator.enabled'")
+	}
 	if r.Spec.TargetAllocator.Enabled && r.Spec.Mode != ModeStatefulSet {
 		warnings = append(warnings, fmt.Sprintf("The Amazon CloudWatch Agent mode is set to %s, we do not recommend enabling Target Allocator when not running as a StatefulSet", r.Spec.Mode))
 	}
@@ -203,6 +627,19 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 			return warnings, fmt.Errorf("the OpenTelemetry Spec Ports configuration is incorrect, port name '%s' errors: %s, num '%d' errors: %s",
 				p.Name, nameErrs, p.Port, numErrs)
 		}
+		if p.NodePort != 0 && r.Spec.ServiceType != corev1.ServiceTypeNodePort && r.Spec.ServiceType != corev1.ServiceTypeLoadBalancer {
+			return warnings, fmt.Errorf("the OpenTelemetry Spec Ports configuration is incorrect, the port '%s' sets a nodePort, which requires the NodePort or LoadBalancer serviceType", p.Name)
+		}
+	}
+
+	if warning := agentgates.Check(r.Spec.FeatureGates); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if !r.Spec.Args.Valid() {
+		return warnings, fmt.Errorf("the attribute 'args' must be a map or a list of strings")
+	}
+	if len(r.Spec.FeatureGates) > 0 && r.Spec.Args.hasFlag("feature-gates") {
+		warnings = append(warnings, "both the featureGates and the args set the --feature-gates flag, the agent gets both")
 	}
 
 	var maxReplicas *int32
@@ -237,6 +674,22 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		)
 	}
 
+	// validate vertical autoscaling
+	if r.Spec.Autoscaler != nil && r.Spec.Autoscaler.Vertical != nil {
+		if r.Spec.Mode == ModeSidecar {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'autoscaler.vertical'", r.Spec.Mode)
+		}
+		// the horizontal autoscaler is defaulted to scale on the CPU utilization, both would react to the same usage
+		if maxReplicas != nil {
+			return warnings, fmt.Errorf("the attribute 'autoscaler.vertical' can't be used with the horizontal autoscaling of 'maxReplicas'")
+		}
+		for resource, minAllowed := range r.Spec.Autoscaler.Vertical.MinAllowed {
+			if maxAllowed, ok := r.Spec.Autoscaler.Vertical.MaxAllowed[resource]; ok && minAllowed.Cmp(maxAllowed) > 0 {
+				return warnings, fmt.Errorf("the attribute 'autoscaler.vertical' allows a minimum %s of %s, greater than the maximum of %s", resource, minAllowed.String(), maxAllowed.String())
+			}
+		}
+	}
+
 	// validate autoscale with horizontal pod autoscaler
 	if maxReplicas != nil {
 		if *maxReplicas < int32(1) {
@@ -295,9 +748,186 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'updateStrategy'", r.Spec.Mode)
 	}
 
+	// validate versionSplit for DaemonSet
+	if r.Spec.Mode != ModeDaemonSet && r.Spec.VersionSplit != nil {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'versionSplit'", r.Spec.Mode)
+	}
+
+	// validate rolloutStrategy for DaemonSet
+	if strategy := r.Spec.RolloutStrategy; strategy != nil {
+		if r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'rolloutStrategy'", r.Spec.Mode)
+		}
+		if strategy.Canary != nil && strategy.Type != RolloutStrategyCanary {
+			return warnings, fmt.Errorf("the attribute 'rolloutStrategy.canary' requires the %s rollout strategy type", RolloutStrategyCanary)
+		}
+		if strategy.Canary != nil && strategy.Canary.BakeDuration != nil && strategy.Canary.BakeDuration.Duration <= 0 {
+			return warnings, fmt.Errorf("the attribute 'rolloutStrategy.canary.bakeDuration' must be positive")
+		}
+		// the canary nodes run a daemonset of their own, which the other splits of the daemonset would overlap
+		if strategy.Type == RolloutStrategyCanary {
+			for _, attribute := range []struct {
+				name string
+				set  bool
+			}{
+				{"versionSplit", r.Spec.VersionSplit != nil},
+				{"nodeGroups", len(r.Spec.NodeGroups) > 0},
+				{"nodeConfigOverrides", len(r.Spec.NodeConfigOverrides) > 0},
+				{"pipelineSplit", r.Spec.PipelineSplit != nil},
+			} {
+				if attribute.set {
+					return warnings, fmt.Errorf("the canary rollout strategy and the attribute '%s' can't be used together", attribute.name)
+				}
+			}
+		}
+	}
+
+	// validate nodeGroups and nodeConfigOverrides for DaemonSet
+	for _, attribute := range []struct {
+		name  string
+		count int
+	}{{"nodeGroups", len(r.Spec.NodeGroups)}, {"nodeConfigOverrides", len(r.Spec.NodeConfigOverrides)}} {
+		if attribute.count == 0 {
+			continue
+		}
+		if r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute '%s'", r.Spec.Mode, attribute.name)
+		}
+		if r.Spec.VersionSplit != nil {
+			return warnings, fmt.Errorf("the attributes '%s' and 'versionSplit' can't be used together", attribute.name)
+		}
+	}
+	// the node groups and the overrides share the names of their daemonsets
+	names := map[string]bool{}
+	for _, group := range r.Spec.NodeGroups {
+		if names[group.Name] {
+			return warnings, fmt.Errorf("the attribute 'nodeGroups' contains the node group %s more than once", group.Name)
+		}
+		names[group.Name] = true
+		if _, err := adapters.ConfigFromJSONString(group.Config); err != nil {
+			return warnings, fmt.Errorf("the config of node group %s is not a JSON object: %w", group.Name, err)
+		}
+	}
+	for _, override := range r.Spec.NodeConfigOverrides {
+		if names[override.Name] {
+			return warnings, fmt.Errorf("the name %s of the node config override is already used by another node group or override", override.Name)
+		}
+		names[override.Name] = true
+		if len(override.NodeSelector) == 0 {
+			return warnings, fmt.Errorf("the node config override %s requires a 'nodeSelector'", override.Name)
+		}
+		if _, err := adapters.ConfigFromJSONString(override.Config); err != nil {
+			return warnings, fmt.Errorf("the config of node config override %s is not a JSON object: %w", override.Name, err)
+		}
+	}
+
+	// validate pipelineSplit for DaemonSet
+	if r.Spec.PipelineSplit != nil {
+		if r.Spec.Mode != ModeDaemonSet {
+			return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'pipelineSplit'", r.Spec.Mode)
+		}
+		if r.Spec.VersionSplit != nil {
+			return warnings, fmt.Errorf("the attributes 'pipelineSplit' and 'versionSplit' can't be used together")
+		}
+		for _, pipeline := range []struct {
+			signal     string
+			deployment PipelineDeploymentSpec
+		}{{"metrics", r.Spec.PipelineSplit.Metrics}, {"traces", r.Spec.PipelineSplit.Traces}} {
+			// the deployments of the signals are named like the daemonsets of the node groups
+			if names[pipeline.signal] {
+				return warnings, fmt.Errorf("the name %s of the node group or override is already used by the deployment of the 'pipelineSplit'", pipeline.signal)
+			}
+			if err := checkPipelineDeploymentSpec(pipeline.deployment); err != nil {
+				return warnings, fmt.Errorf("the attribute 'pipelineSplit.%s' is incorrect, %w", pipeline.signal, err)
+			}
+		}
+	}
+
+	// validate nodeTermination for DaemonSet
+	if r.Spec.NodeTermination != nil && r.Spec.Mode != ModeDaemonSet {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'nodeTermination'", r.Spec.Mode)
+	}
+
 	return warnings, nil
 }
 
+// checkDestinations checks that each destination applies to a section of the Config or to a pipeline of the
+// OtelConfig of its signal once, and only sets the fields its signal supports.
+func checkDestinations(destinations []DestinationSpec, otelConfig string) error {
+	if len(destinations) == 0 {
+		return nil
+	}
+	var pipelines map[interface{}]interface{}
+	if otelConfig != "" {
+		if config, err := adapters.ConfigFromString(otelConfig); err == nil {
+			service, _ := config["service"].(map[interface{}]interface{})
+			pipelines, _ = service["pipelines"].(map[interface{}]interface{})
+		}
+	}
+	seen := map[DestinationSpec]bool{}
+	var region string
+	for _, destination := range destinations {
+		target := "the " + destination.Signal + " section of the config"
+		if destination.Pipeline != "" {
+			target = "the pipeline " + destination.Pipeline
+		}
+		key := DestinationSpec{Signal: destination.Signal, Pipeline: destination.Pipeline}
+		if seen[key] {
+			return fmt.Errorf("%s has several destinations", target)
+		}
+		seen[key] = true
+		if destination.MetricsNamespace != "" && destination.Signal != "metrics" {
+			return fmt.Errorf("the destination of %s sets a metricsNamespace, which only the metrics support", target)
+		}
+		if destination.Pipeline == "" {
+			if destination.LogGroup != "" && destination.Signal != "logs" {
+				return fmt.Errorf("the destination of %s sets a logGroup, which only the logs section supports", target)
+			}
+			if destination.Region != "" && region != "" && destination.Region != region {
+				return fmt.Errorf("the destinations of the sections of the config set the regions %s and %s, while the sections share the region of the agent", region, destination.Region)
+			}
+			if destination.Region != "" {
+				region = destination.Region
+			}
+			continue
+		}
+		if _, ok := pipelines[destination.Pipeline]; !ok {
+			return fmt.Errorf("the pipeline %s doesn't exist in the otelConfig", destination.Pipeline)
+		}
+		if signal := strings.SplitN(destination.Pipeline, "/", 2)[0]; signal != destination.Signal {
+			return fmt.Errorf("the pipeline %s is a %s pipeline, not a %s one", destination.Pipeline, signal, destination.Signal)
+		}
+		if destination.LogGroup != "" && destination.Signal == "traces" {
+			return fmt.Errorf("the destination of %s sets a logGroup, which the traces don't support", target)
+		}
+	}
+	return nil
+}
+
+func checkPipelineDeploymentSpec(deployment PipelineDeploymentSpec) error {
+	if deployment.Autoscaler == nil {
+		return nil
+	}
+	autoscaler := deployment.Autoscaler
+	if autoscaler.Vertical != nil {
+		return fmt.Errorf("the deployment doesn't support the attribute 'autoscaler.vertical'")
+	}
+	if autoscaler.MaxReplicas == nil || *autoscaler.MaxReplicas < int32(1) {
+		return fmt.Errorf("maxReplicas should be defined and one or more")
+	}
+	minReplicas := autoscaler.MinReplicas
+	if minReplicas == nil {
+		minReplicas = deployment.Replicas
+	}
+	if minReplicas != nil && *minReplicas > *autoscaler.MaxReplicas {
+		return fmt.Errorf("minReplicas must not be greater than maxReplicas")
+	}
+	if minReplicas != nil && *minReplicas < int32(1) {
+		return fmt.Errorf("minReplicas should be one or more")
+	}
+	return checkAutoscalerSpec(autoscaler)
+}
+
 func checkAutoscalerSpec(autoscaler *AutoscalerSpec) error {
 	if autoscaler.Behavior != nil {
 		if autoscaler.Behavior.ScaleDown != nil && autoscaler.Behavior.ScaleDown.StabilizationWindowSeconds != nil &&
@@ -344,6 +974,7 @@ func SetupCollectorWebhook(mgr ctrl.Manager, cfg config.Config) error {
 		logger: mgr.GetLogger().WithValues("handler", "CollectorWebhook"),
 		scheme: mgr.GetScheme(),
 		cfg:    cfg,
+		reader: mgr.GetAPIReader(),
 	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&AmazonCloudWatchAgent{}).
@@ -351,3 +982,174 @@ func SetupCollectorWebhook(mgr ctrl.Manager, cfg config.Config) error {
 		WithDefaulter(cvw).
 		Complete()
 }
+
+// validatePrometheusHelpers checks the global scrape interval and the extra scrape configs of the Prometheus config,
+// parsing the extra scrape configs as Prometheus does, which validates their relabel configs.
+func validatePrometheusHelpers(prometheus PrometheusConfig) error {
+	if prometheus.GlobalScrapeInterval != "" {
+		if _, err := model.ParseDuration(prometheus.GlobalScrapeInterval); err != nil {
+			return fmt.Errorf("the attribute 'prometheus.globalScrapeInterval' is not a valid duration: %w", err)
+		}
+	}
+	if len(prometheus.ExtraScrapeConfigs) == 0 {
+		return nil
+	}
+
+	jobs := map[string]bool{}
+	if prometheus.Config != nil {
+		scrapeConfigs, _ := prometheus.Config.Object["scrape_configs"].([]interface{})
+		for _, sc := range scrapeConfigs {
+			if sc, ok := sc.(map[string]interface{}); ok {
+				if job, ok := sc["job_name"].(string); ok {
+					jobs[job] = true
+				}
+			}
+		}
+	}
+	for i, sc := range prometheus.ExtraScrapeConfigs {
+		out, err := yaml.Marshal(sc.Object)
+		if err != nil {
+			return err
+		}
+		scrapeConfig := &promconfig.ScrapeConfig{}
+		if err := yaml.UnmarshalStrict(out, scrapeConfig); err != nil {
+			return fmt.Errorf("the scrape config %d of 'prometheus.extraScrapeConfigs' is invalid: %w", i, err)
+		}
+		if jobs[scrapeConfig.JobName] {
+			return fmt.Errorf("the scrape config %d of 'prometheus.extraScrapeConfigs' reuses the job name %q", i, scrapeConfig.JobName)
+		}
+		jobs[scrapeConfig.JobName] = true
+	}
+	return nil
+}
+
+// checkControlPlaneMetrics checks that the control plane components are listed once, and that the jobs scraping
+// them aren't already defined in the Prometheus configuration.
+func checkControlPlaneMetrics(spec ControlPlaneMetricsSpec, prometheus PrometheusConfig) error {
+	if spec.ScrapeInterval != nil && spec.ScrapeInterval.Duration <= 0 {
+		return fmt.Errorf("the scrapeInterval must be positive")
+	}
+	components := map[ControlPlaneComponent]bool{}
+	for _, component := range spec.Components {
+		if components[component] {
+			return fmt.Errorf("the component %s is listed more than once", component)
+		}
+		components[component] = true
+	}
+	var scrapeConfigs []interface{}
+	if prometheus.Config != nil {
+		scrapeConfigs, _ = prometheus.Config.Object["scrape_configs"].([]interface{})
+	}
+	for _, sc := range prometheus.ExtraScrapeConfigs {
+		scrapeConfigs = append(scrapeConfigs, sc.Object)
+	}
+	for _, sc := range scrapeConfigs {
+		sc, _ := sc.(map[string]interface{})
+		job, _ := sc["job_name"].(string)
+		if strings.HasPrefix(job, ControlPlaneJobName("")) {
+			return fmt.Errorf("the Prometheus configuration already defines the job %s", job)
+		}
+	}
+	return nil
+}
+
+// ValidateConfigPolicies checks the destinations of the instance against its OtelConfig, and its configs against the
+// exporter policy and the Prometheus guardrails of the operator, returning the violations of the guardrails which
+// aren't enforced as warnings. The operator checks the configs again once the AmazonCloudWatchAgentConfigOverrides
+// are layered onto them, which the webhook doesn't see.
+func ValidateConfigPolicies(cfg config.Config, r *AmazonCloudWatchAgent) ([]string, error) {
+	if err := checkDestinations(r.Spec.Destinations, r.Spec.OtelConfig); err != nil {
+		return nil, fmt.Errorf("the attribute 'destinations' is incorrect, %w", err)
+	}
+
+	// validate exporters against the exporter policy
+	if policy := cfg.ExporterPolicy(); policy != nil {
+		if r.Spec.Config != "" {
+			cwaConfig, err := adapters.ConfigFromJSONString(r.Spec.Config)
+			if err != nil {
+				return nil, fmt.Errorf("the Amazon CloudWatch Agent config is incorrect, %w", err)
+			}
+			if err := policy.ValidateConfig(cwaConfig); err != nil {
+				return nil, fmt.Errorf("the Amazon CloudWatch Agent config is rejected, %w", err)
+			}
+		}
+		if r.Spec.OtelConfig != "" {
+			otelCfg, err := adapters.ConfigFromString(r.Spec.OtelConfig)
+			if err != nil {
+				return nil, fmt.Errorf("the OpenTelemetry Spec OtelConfig is incorrect, %w", err)
+			}
+			if err := policy.ValidateOtelConfig(otelCfg); err != nil {
+				return nil, fmt.Errorf("the OpenTelemetry Spec OtelConfig is rejected, %w", err)
+			}
+		}
+		overrides := r.Spec.AWSEndpointOverrides
+		for _, endpoint := range []string{overrides.CloudWatch, overrides.Logs, overrides.XRay} {
+			if endpoint == "" {
+				continue
+			}
+			if err := policy.ValidateEndpoint(endpoint); err != nil {
+				return nil, fmt.Errorf("the awsEndpointOverrides are rejected by the exporter policy, %w", err)
+			}
+		}
+		if r.Spec.XRay != nil && r.Spec.XRay.EndpointOverride != "" {
+			if err := policy.ValidateEndpoint(r.Spec.XRay.EndpointOverride); err != nil {
+				return nil, fmt.Errorf("the attribute 'xray.endpointOverride' is rejected by the exporter policy, %w", err)
+			}
+		}
+	}
+
+	// validate the Prometheus scrape configs against the guardrails
+	if guardrails := cfg.PrometheusGuardrails(); guardrails != nil {
+		violations, err := prometheusGuardrailViolations(guardrails, r)
+		if err != nil {
+			return nil, err
+		}
+		if len(violations) > 0 && guardrails.Enforce {
+			return nil, fmt.Errorf("the Prometheus scrape configs exceed the guardrails: %s", strings.Join(violations, "; "))
+		}
+		return violations, nil
+	}
+	return nil, nil
+}
+
+// prometheusGuardrailViolations checks the Prometheus config of the instance, and the configs of the prometheus
+// receivers of its OtelConfig, against the guardrails.
+func prometheusGuardrailViolations(guardrails *promguardrails.Guardrails, r *AmazonCloudWatchAgent) ([]string, error) {
+	var violations []string
+	if r.Spec.Prometheus.Config != nil || len(r.Spec.Prometheus.ExtraScrapeConfigs) > 0 {
+		promConfigYaml, err := r.Spec.Prometheus.Yaml()
+		if err != nil {
+			return nil, fmt.Errorf("%s could not convert json to yaml", err)
+		}
+		promCfg, err := adapters.ConfigFromString(promConfigYaml)
+		if err != nil {
+			return nil, fmt.Errorf("the OpenTelemetry Spec Prometheus configuration is incorrect, %w", err)
+		}
+		if config, ok := promCfg["config"].(map[interface{}]interface{}); ok {
+			violations = append(violations, guardrails.Check(config)...)
+		}
+	}
+	if r.Spec.OtelConfig != "" {
+		otelCfg, err := adapters.ConfigFromString(r.Spec.OtelConfig)
+		if err != nil {
+			return nil, fmt.Errorf("the OpenTelemetry Spec OtelConfig is incorrect, %w", err)
+		}
+		receivers, _ := otelCfg["receivers"].(map[interface{}]interface{})
+		names := make([]string, 0, len(receivers))
+		for k := range receivers {
+			if name, ok := k.(string); ok && (name == "prometheus" || strings.HasPrefix(name, "prometheus/")) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			receiver, _ := receivers[name].(map[interface{}]interface{})
+			if config, ok := receiver["config"].(map[interface{}]interface{}); ok {
+				for _, violation := range guardrails.Check(config) {
+					violations = append(violations, fmt.Sprintf("receiver %s: %s", name, violation))
+				}
+			}
+		}
+	}
+	return violations, nil
+}
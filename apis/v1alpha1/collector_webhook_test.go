@@ -5,9 +5,11 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
@@ -21,8 +23,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/compatibility"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/exporterpolicy"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/promguardrails"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
 var (
@@ -52,6 +59,9 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 					Labels: map[string]string{
 						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 					},
+					Annotations: map[string]string{
+						constants.AnnotationDefaultsApplied: `{"managementState":"managed","mode":"deployment","podDisruptionBudget.maxUnavailable":"1","replicas":"1","upgradeStrategy":"automatic"}`,
+					},
 				},
 				Spec: AmazonCloudWatchAgentSpec{
 					Mode:            ModeDeployment,
@@ -81,6 +91,9 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 					Labels: map[string]string{
 						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 					},
+					Annotations: map[string]string{
+						constants.AnnotationDefaultsApplied: `{"managementState":"managed","podDisruptionBudget.maxUnavailable":"1"}`,
+					},
 				},
 				Spec: AmazonCloudWatchAgentSpec{
 					Mode:            ModeSidecar,
@@ -111,6 +124,9 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 					Labels: map[string]string{
 						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 					},
+					Annotations: map[string]string{
+						constants.AnnotationDefaultsApplied: `{"podDisruptionBudget.maxUnavailable":"1"}`,
+					},
 				},
 				Spec: AmazonCloudWatchAgentSpec{
 					Mode:            ModeSidecar,
@@ -141,6 +157,9 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 					Labels: map[string]string{
 						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 					},
+					Annotations: map[string]string{
+						constants.AnnotationDefaultsApplied: `{"autoscaler.targetCPUUtilization":"90","managementState":"managed","mode":"deployment","podDisruptionBudget.maxUnavailable":"1","replicas":"1","upgradeStrategy":"automatic"}`,
+					},
 				},
 				Spec: AmazonCloudWatchAgentSpec{
 					Mode:            ModeDeployment,
@@ -173,6 +192,9 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 					Labels: map[string]string{
 						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 					},
+					Annotations: map[string]string{
+						constants.AnnotationDefaultsApplied: `{"autoscaler.targetCPUUtilization":"90","managementState":"managed","mode":"deployment","podDisruptionBudget.maxUnavailable":"1","replicas":"1","upgradeStrategy":"automatic"}`,
+					},
 				},
 				Spec: AmazonCloudWatchAgentSpec{
 					Mode:            ModeDeployment,
@@ -211,6 +233,9 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 					Labels: map[string]string{
 						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 					},
+					Annotations: map[string]string{
+						constants.AnnotationDefaultsApplied: `{"managementState":"managed","podDisruptionBudget.maxUnavailable":"1","replicas":"1","upgradeStrategy":"automatic"}`,
+					},
 				},
 				Spec: AmazonCloudWatchAgentSpec{
 					Mode:            ModeDeployment,
@@ -250,6 +275,9 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 					Labels: map[string]string{
 						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 					},
+					Annotations: map[string]string{
+						constants.AnnotationDefaultsApplied: `{"managementState":"managed","replicas":"1","upgradeStrategy":"automatic"}`,
+					},
 				},
 				Spec: AmazonCloudWatchAgentSpec{
 					Mode:            ModeDeployment,
@@ -265,6 +293,39 @@ func TestOTELColDefaultingWebhook(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "debug ports default",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:     ModeDeployment,
+					Replicas: &one,
+					PodDisruptionBudget: &PodDisruptionBudgetSpec{
+						MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+					},
+					Debug: &DebugSpec{Enabled: true, ZPagesPort: 55680},
+				},
+			},
+			expected: AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+					},
+					Annotations: map[string]string{
+						constants.AnnotationDefaultsApplied: `{"debug.pprofPort":"1777","managementState":"managed","upgradeStrategy":"automatic"}`,
+					},
+				},
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeDeployment,
+					Replicas:        &one,
+					UpgradeStrategy: UpgradeStrategyAutomatic,
+					ManagementState: ManagementStateManaged,
+					PodDisruptionBudget: &PodDisruptionBudgetSpec{
+						MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+					},
+					Debug: &DebugSpec{Enabled: true, PprofPort: 1777, ZPagesPort: 55680},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -302,6 +363,7 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 	one := int32(1)
 	three := int32(3)
 	five := int32(5)
+	zeroQuantity := resource.MustParse("0")
 
 	promCfg := PrometheusConfig{}
 	err := yaml.Unmarshal([]byte(promCfgYaml), &promCfg)
@@ -366,401 +428,1251 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			expectedErr: "does not support the attribute 'volumeClaimTemplates'",
 		},
 		{
-			name: "invalid mode with tolerations",
+			name: "otlp receiver settings without otelConfig",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode:        ModeSidecar,
-					Tolerations: []v1.Toleration{{}, {}},
+					Config: `{"traces": {"traces_collected": {"otlp": {}}}}`,
+					OTLPReceiver: OTLPReceiverSpec{
+						HTTPCompressionAlgorithms: []CompressionAlgorithm{"gzip"},
+					},
 				},
 			},
-			expectedErr: "does not support the attribute 'tolerations'",
+			expectedErr: "the attribute 'otlpReceiver' only applies to the otlp receivers of the 'otelConfig', which is not set",
 		},
 		{
-			name: "invalid target allocator config",
+			name: "invalid otlp receiver max request body size",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode: ModeStatefulSet,
-					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
-						Enabled: true,
+					OtelConfig: "service:\n  pipelines: {}\n",
+					OTLPReceiver: OTLPReceiverSpec{
+						HTTPMaxRequestBodySize: &zeroQuantity,
 					},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec Prometheus configuration is incorrect",
+			expectedErr: "httpMaxRequestBodySize must be positive",
 		},
 		{
-			name: "invalid port name",
+			name: "invalid mode with persistence",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Ports: []v1.ServicePort{
-						{
-							// this port name contains a non alphanumeric character, which is invalid.
-							Name:     "-test🦄port",
-							Port:     12345,
-							Protocol: v1.ProtocolTCP,
-						},
-					},
+					Mode:        ModeDaemonSet,
+					Persistence: &PersistenceSpec{},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec Ports configuration is incorrect",
+			expectedErr: "does not support the attribute 'persistence'",
 		},
 		{
-			name: "invalid port name, too long",
+			name: "relative persistence directory",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Ports: []v1.ServicePort{
-						{
-							Name: "aaaabbbbccccdddd", // len: 16, too long
-							Port: 5555,
-						},
-					},
+					Mode:        ModeStatefulSet,
+					Persistence: &PersistenceSpec{Directory: "queue"},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec Ports configuration is incorrect",
+			expectedErr: "must be an absolute path",
 		},
 		{
-			name: "invalid port num",
+			name: "sending queue not persisted",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Ports: []v1.ServicePort{
-						{
-							Name: "aaaabbbbccccddd", // len: 15
-							// no port set means it's 0, which is invalid
-						},
-					},
+					Mode:        ModeStatefulSet,
+					Persistence: &PersistenceSpec{},
+					OtelConfig: `extensions:
+  file_storage/other:
+    directory: /var/spool/other
+exporters:
+  otlp:
+    sending_queue:
+      storage: file_storage/other`,
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec Ports configuration is incorrect",
+			expectedErr: "can't be persisted",
 		},
 		{
-			name: "invalid max replicas",
+			name: "tls without certificate source",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &zero,
+					TLS: &TLSSpec{},
 				},
 			},
-			expectedErr:      "maxReplicas should be defined and one or more",
-			expectedWarnings: []string{"MaxReplicas is deprecated"},
+			expectedErr: "requires either 'secretName' or 'certManager'",
 		},
 		{
-			name: "invalid replicas, greater than max",
+			name: "tls secret namespace with cert-manager",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					Replicas:    &five,
+					TLS: &TLSSpec{SecretName: "tls", SecretNamespace: "certificates", CertManager: &CertManagerSpec{}},
 				},
 			},
-			expectedErr:      "replicas must not be greater than maxReplicas",
-			expectedWarnings: []string{"MaxReplicas is deprecated"},
+			expectedErr: "'tls.secretNamespace' requires 'secretName'",
 		},
 		{
-			name: "invalid min replicas, greater than max",
+			name: "xray settings without xray receiver",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					MinReplicas: &five,
+					Config: `{"traces":{"traces_collected":{"otlp":{}}}}`,
+					XRay:   &XRaySpec{RegionOverride: "us-west-2"},
 				},
 			},
-			expectedErr:      "minReplicas must not be greater than maxReplicas",
-			expectedWarnings: []string{"MaxReplicas is deprecated", "MinReplicas is deprecated"},
+			expectedErr: "the attribute 'xray' requires the xray receiver",
 		},
 		{
-			name: "invalid min replicas, lesser than 1",
+			name: "xray settings with xray receiver",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					MinReplicas: &zero,
+					Config: `{"traces":{"traces_collected":{"xray":{}}}}`,
+					XRay:   &XRaySpec{RegionOverride: "us-west-2"},
 				},
 			},
-			expectedErr:      "minReplicas should be one or more",
-			expectedWarnings: []string{"MaxReplicas is deprecated", "MinReplicas is deprecated"},
 		},
 		{
-			name: "invalid autoscaler scale down",
+			name: "alarm action which is not an ARN",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					Autoscaler: &AutoscalerSpec{
-						Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
-							ScaleDown: &autoscalingv2.HPAScalingRules{
-								StabilizationWindowSeconds: &zero,
-							},
-						},
-					},
+					Alarms: &AlarmsSpec{ClusterName: "cluster", ActionARNs: []string{"my-topic"}},
 				},
 			},
-			expectedErr:      "scaleDown should be one or more",
-			expectedWarnings: []string{"MaxReplicas is deprecated"},
+			expectedErr: "the attribute 'alarms.actionArns' contains \"my-topic\", which is not an ARN",
 		},
 		{
-			name: "invalid autoscaler scale up",
+			name: "log group name template with unknown variable",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					Autoscaler: &AutoscalerSpec{
-						Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
-							ScaleUp: &autoscalingv2.HPAScalingRules{
-								StabilizationWindowSeconds: &zero,
-							},
-						},
-					},
+					Logs: &LogsSpec{GroupNameTemplate: "/aws/${cluster_name}/${node}", ClusterName: "cluster"},
 				},
 			},
-			expectedErr:      "scaleUp should be one or more",
-			expectedWarnings: []string{"MaxReplicas is deprecated"},
+			expectedErr: "the attribute 'logs.groupNameTemplate' contains the unknown variable ${node}",
 		},
 		{
-			name: "invalid autoscaler target cpu utilization",
+			name: "log group name template without cluster name",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					Autoscaler: &AutoscalerSpec{
-						TargetCPUUtilization: &zero,
-					},
+					Config: `{"logs":{"metrics_collected":{"kubernetes":{}}}}`,
+					Logs:   &LogsSpec{GroupNameTemplate: "/aws/${cluster_name}/${namespace}"},
 				},
 			},
-			expectedErr:      "targetCPUUtilization should be greater than 0 and less than 100",
-			expectedWarnings: []string{"MaxReplicas is deprecated"},
+			expectedErr: "the attribute 'logs.groupNameTemplate' uses ${cluster_name}, which requires 'logs.clusterName'",
 		},
 		{
-			name: "autoscaler minReplicas is less than maxReplicas",
+			name: "log group name template with cluster name from config",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Autoscaler: &AutoscalerSpec{
-						MaxReplicas: &one,
-						MinReplicas: &five,
-					},
+					Config: `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"cluster"}}}}`,
+					Logs:   &LogsSpec{GroupNameTemplate: "/aws/${cluster_name}/${namespace}/${pod}"},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec autoscale configuration is incorrect, minReplicas must not be greater than maxReplicas",
 		},
 		{
-			name: "invalid autoscaler metric type",
+			name: "extra mount without source",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					Autoscaler: &AutoscalerSpec{
-						Metrics: []MetricSpec{
-							{
-								Type: autoscalingv2.ResourceMetricSourceType,
-							},
-						},
-					},
+					ExtraMounts: []ExtraMountSpec{{MountPath: "/etc/ca"}},
 				},
 			},
-			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, metric type unsupported. Expected metric of source type Pod",
-			expectedWarnings: []string{"MaxReplicas is deprecated"},
+			expectedErr: "the attribute 'extraMounts[0]' must set exactly one of 'configMap' and 'secret'",
 		},
 		{
-			name: "invalid pod metric average value",
+			name: "extra mount with configmap and secret",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					Autoscaler: &AutoscalerSpec{
-						Metrics: []MetricSpec{
-							{
-								Type: autoscalingv2.PodsMetricSourceType,
-								Pods: &autoscalingv2.PodsMetricSource{
-									Metric: autoscalingv2.MetricIdentifier{
-										Name: "custom1",
-									},
-									Target: autoscalingv2.MetricTarget{
-										Type:         autoscalingv2.AverageValueMetricType,
-										AverageValue: resource.NewQuantity(int64(0), resource.DecimalSI),
-									},
-								},
-							},
-						},
-					},
+					ExtraMounts: []ExtraMountSpec{{ConfigMap: "ca", Secret: "credentials", MountPath: "/etc/ca"}},
 				},
 			},
-			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, average value should be greater than 0",
-			expectedWarnings: []string{"MaxReplicas is deprecated"},
+			expectedErr: "the attribute 'extraMounts[0]' must set exactly one of 'configMap' and 'secret'",
 		},
 		{
-			name: "utilization target is not valid with pod metrics",
+			name: "extra mount with relative path",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					MaxReplicas: &three,
-					Autoscaler: &AutoscalerSpec{
-						Metrics: []MetricSpec{
-							{
-								Type: autoscalingv2.PodsMetricSourceType,
-								Pods: &autoscalingv2.PodsMetricSource{
-									Metric: autoscalingv2.MetricIdentifier{
-										Name: "custom1",
-									},
-									Target: autoscalingv2.MetricTarget{
-										Type:               autoscalingv2.UtilizationMetricType,
-										AverageUtilization: &one,
-									},
-								},
-							},
-						},
-					},
+					ExtraMounts: []ExtraMountSpec{{Secret: "credentials", MountPath: "etc/credentials"}},
 				},
 			},
-			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, invalid pods target type",
-			expectedWarnings: []string{"MaxReplicas is deprecated"},
+			expectedErr: "the attribute 'extraMounts[0].mountPath' must be an absolute path",
 		},
 		{
-			name: "invalid deployment mode incompabible with ingress settings",
+			name: "container override renaming the container",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode: ModeSidecar,
-					Ingress: Ingress{
-						Type: IngressTypeNginx,
-					},
+					ContainerOverride: &runtime.RawExtension{Raw: []byte(`{"name":"debug","tty":true}`)},
 				},
 			},
-			expectedErr: fmt.Sprintf("Ingress can only be used in combination with the modes: %s, %s, %s",
-				ModeDeployment, ModeDaemonSet, ModeStatefulSet,
-			),
+			expectedErr: "the attribute 'containerOverride' can't change the name of the agent container",
 		},
 		{
-			name: "invalid mode with priorityClassName",
+			name: "invalid container override",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode:              ModeSidecar,
-					PriorityClassName: "test-class",
+					ContainerOverride: &runtime.RawExtension{Raw: []byte(`{"tty":"yes"}`)},
 				},
 			},
-			expectedErr: "does not support the attribute 'priorityClassName'",
+			expectedErr: "the attribute 'containerOverride' is not a valid container patch",
 		},
 		{
-			name: "invalid mode with affinity",
+			name: "node groups in deployment mode",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode: ModeSidecar,
-					Affinity: &v1.Affinity{
-						NodeAffinity: &v1.NodeAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
-								NodeSelectorTerms: []v1.NodeSelectorTerm{
-									{
-										MatchExpressions: []v1.NodeSelectorRequirement{
-											{
-												Key:      "node",
-												Operator: v1.NodeSelectorOpIn,
-												Values:   []string{"test-node"},
-											},
-										},
-									},
-								},
-							},
-						},
-					},
+					Mode:       ModeDeployment,
+					NodeGroups: []NodeGroupSpec{{Name: "gpu", NodeLabel: NodeLabel{Key: "nvidia.com/gpu.present", Value: "true"}, Config: "{}"}},
 				},
 			},
-			expectedErr: "does not support the attribute 'affinity'",
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'nodeGroups'",
 		},
 		{
-			name: "invalid InitialDelaySeconds",
+			name: "duplicate node groups",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					LivenessProbe: &Probe{
-						InitialDelaySeconds: &minusOne,
+					Mode: ModeDaemonSet,
+					NodeGroups: []NodeGroupSpec{
+						{Name: "gpu", NodeLabel: NodeLabel{Key: "nvidia.com/gpu.present", Value: "true"}, Config: "{}"},
+						{Name: "gpu", NodeLabel: NodeLabel{Key: "aws.amazon.com/neuron.present", Value: "true"}, Config: "{}"},
 					},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec LivenessProbe InitialDelaySeconds configuration is incorrect",
+			expectedErr: "the attribute 'nodeGroups' contains the node group gpu more than once",
 		},
 		{
-			name: "invalid PeriodSeconds",
+			name: "node config override in deployment mode",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					LivenessProbe: &Probe{
-						PeriodSeconds: &zero,
+					Mode: ModeDeployment,
+					NodeConfigOverrides: []NodeConfigOverrideSpec{
+						{Name: "large", NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "m5.24xlarge"}, Config: "{}"},
 					},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec LivenessProbe PeriodSeconds configuration is incorrect",
+			expectedErr: "does not support the attribute 'nodeConfigOverrides'",
 		},
 		{
-			name: "invalid TimeoutSeconds",
+			name: "node config override named after a node group",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					LivenessProbe: &Probe{
-						TimeoutSeconds: &zero,
+					Mode:       ModeDaemonSet,
+					NodeGroups: []NodeGroupSpec{{Name: "gpu", NodeLabel: NodeLabel{Key: "nvidia.com/gpu.present", Value: "true"}, Config: "{}"}},
+					NodeConfigOverrides: []NodeConfigOverrideSpec{
+						{Name: "gpu", NodeSelector: map[string]string{"nvidia.com/gpu.product": "A100"}, Config: "{}"},
 					},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec LivenessProbe TimeoutSeconds configuration is incorrect",
+			expectedErr: "the name gpu of the node config override is already used",
 		},
 		{
-			name: "invalid SuccessThreshold",
+			name: "node config override with invalid config",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					LivenessProbe: &Probe{
-						SuccessThreshold: &zero,
+					Mode: ModeDaemonSet,
+					NodeConfigOverrides: []NodeConfigOverrideSpec{
+						{Name: "large", NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "m5.24xlarge"}, Config: "[]"},
 					},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec LivenessProbe SuccessThreshold configuration is incorrect",
+			expectedErr: "the config of node config override large is not a JSON object",
 		},
 		{
-			name: "invalid FailureThreshold",
+			name: "node group with invalid config",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					LivenessProbe: &Probe{
-						FailureThreshold: &zero,
-					},
+					Mode:       ModeDaemonSet,
+					NodeGroups: []NodeGroupSpec{{Name: "gpu", NodeLabel: NodeLabel{Key: "nvidia.com/gpu.present", Value: "true"}, Config: "[]"}},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec LivenessProbe FailureThreshold configuration is incorrect",
+			expectedErr: "the config of node group gpu is not a JSON object",
 		},
 		{
-			name: "invalid TerminationGracePeriodSeconds",
+			name: "vertical autoscaler in sidecar mode",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					LivenessProbe: &Probe{
-						TerminationGracePeriodSeconds: &zero64,
-					},
+					Mode:       ModeSidecar,
+					Autoscaler: &AutoscalerSpec{Vertical: &VerticalAutoscalerSpec{}},
 				},
 			},
-			expectedErr: "the OpenTelemetry Spec LivenessProbe TerminationGracePeriodSeconds configuration is incorrect",
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'autoscaler.vertical'",
 		},
 		{
-			name: "invalid AdditionalContainers",
+			name: "vertical autoscaler with horizontal autoscaling",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode: ModeSidecar,
-					AdditionalContainers: []v1.Container{
-						{
-							Name: "test",
-						},
-					},
+					Mode:       ModeDeployment,
+					Autoscaler: &AutoscalerSpec{MaxReplicas: &three, Vertical: &VerticalAutoscalerSpec{}},
 				},
 			},
-			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'AdditionalContainers'",
+			expectedErr: "the attribute 'autoscaler.vertical' can't be used with the horizontal autoscaling of 'maxReplicas'",
 		},
 		{
-			name: "missing ingress hostname for subdomain ruleType",
+			name: "vertical autoscaler with minimum above maximum",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Ingress: Ingress{
-						RuleType: IngressRuleTypeSubdomain,
-					},
+					Mode: ModeDaemonSet,
+					Autoscaler: &AutoscalerSpec{Vertical: &VerticalAutoscalerSpec{
+						MinAllowed: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+						MaxAllowed: v1.ResourceList{v1.ResourceMemory: resource.MustParse("512Mi")},
+					}},
 				},
 			},
-			expectedErr: "a valid Ingress hostname has to be defined for subdomain ruleType",
+			expectedErr: "the attribute 'autoscaler.vertical' allows a minimum memory of 1Gi, greater than the maximum of 512Mi",
 		},
 		{
-			name: "invalid updateStrategy for Deployment mode",
+			name: "diagnostics without otel config",
 			otelcol: AmazonCloudWatchAgent{
 				Spec: AmazonCloudWatchAgentSpec{
-					Mode: ModeDeployment,
-					UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
-						Type: "RollingUpdate",
-						RollingUpdate: &appsv1.RollingUpdateDaemonSet{
-							MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
-							MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
-						},
-					},
+					Diagnostics: &DiagnosticsSpec{ZPages: true},
 				},
 			},
-			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'updateStrategy'",
+			expectedErr: "the attribute 'diagnostics' only applies to the pipelines of the 'otelConfig'",
 		},
-	}
-
+		{
+			name: "iam role with existing service account",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ServiceAccount: "agent",
+					IAMRoleArn:     "arn:aws:iam::123456789012:role/agent",
+				},
+			},
+			expectedErr: "can't be set together with 'serviceAccount'",
+		},
+		{
+			name: "invalid iam role arn",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					IAMRoleArn: "arn:aws:iam::123456789012:user/agent",
+				},
+			},
+			expectedErr: "is not a valid IAM role ARN",
+		},
+		{
+			name: "conflicting iam role arn",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					IAMRoleArn:                "arn:aws-cn:iam::123456789012:role/agent",
+					ServiceAccountAnnotations: map[string]string{constants.AnnotationIAMRoleArn: "arn:aws:iam::123456789012:role/other"},
+				},
+			},
+			expectedErr: "conflicts with the eks.amazonaws.com/role-arn service account annotation",
+		},
+		{
+			name: "invalid name override",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					NameOverride: "Payments_Team",
+				},
+			},
+			expectedErr: "the nameOverride \"Payments_Team\" is not a valid name",
+		},
+		{
+			name: "invalid role arn to assume",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					RoleArn: "123456789012:role/telemetry",
+				},
+			},
+			expectedErr: "the roleArn \"123456789012:role/telemetry\" is not a valid IAM role ARN",
+		},
+		{
+			name: "conflicting role arn to assume",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					RoleArn: "arn:aws:iam::123456789012:role/telemetry",
+					Config:  `{"agent":{"credentials":{"role_arn":"arn:aws:iam::123456789012:role/other"}}}`,
+				},
+			},
+			expectedErr: "conflicts with the role_arn \"arn:aws:iam::123456789012:role/other\" of the agent credentials of the config",
+		},
+		{
+			name: "conflicting image digest",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Image:       "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:latest@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+					ImageDigest: "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+				},
+			},
+			expectedErr: "conflicts with the digest sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef of the image",
+		},
+		{
+			name: "log file at the root of the host",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Config: `{"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app/*.log"},{"file_path":"/**/app.log"}]}}}}`,
+				},
+			},
+			expectedErr: `the file_path "/**/app.log" of logs.logs_collected.files would mount the root of the host`,
+		},
+		{
+			name: "invalid windows events",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:         ModeDaemonSet,
+					NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+					Config:       `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["ERROR"]}]}}}}`,
+				},
+			},
+			expectedErr: "requires a log_group_name",
+		},
+		{
+			name: "windows events without host process",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:         ModeDaemonSet,
+					NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+					Config:       `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["ERROR"], "log_group_name": "system"}]}}}}`,
+					PodSecurityContext: &v1.PodSecurityContext{
+						WindowsOptions: &v1.WindowsSecurityContextOptions{HostProcess: &[]bool{false}[0]},
+					},
+				},
+			},
+			expectedErr: "requires HostProcess pods",
+		},
+		{
+			name: "windows events with debug",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:         ModeDaemonSet,
+					NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+					Config:       `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["ERROR"], "log_group_name": "system"}]}}}}`,
+					Debug:        &DebugSpec{Enabled: true, PprofPort: 1777, ZPagesPort: 55679},
+				},
+			},
+			expectedErr: "the attribute 'debug' can't be enabled when collecting Windows event logs",
+		},
+		{
+			name: "windows events on linux",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:   ModeDaemonSet,
+					Config: `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["ERROR"], "log_group_name": "system"}]}}}}`,
+				},
+			},
+			expectedWarnings: []string{"Windows event logs are only collected by a daemonset with the kubernetes.io/os: windows node selector"},
+		},
+		{
+			name: "self-telemetry without log file",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config:        `{"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app.log"}]}}}}`,
+					Observability: ObservabilitySpec{SelfTelemetry: &SelfTelemetrySpec{}},
+				},
+			},
+			expectedWarnings: []string{"the logs of the agent are not collected by the attribute 'observability.selfTelemetry' without agent.logfile in the Amazon CloudWatch Agent config"},
+		},
+		{
+			name: "prometheus reload without prometheus config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					PrometheusReload: &PrometheusReloadSpec{},
+				},
+			},
+			expectedWarnings: []string{"the attribute 'prometheusReload' is ignored without the attribute 'prometheus'"},
+		},
+		{
+			name: "prometheus reload without shared process namespace",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Prometheus:            PrometheusConfig{TrimMetricSuffixes: true},
+					PrometheusReload:      &PrometheusReloadSpec{},
+					ShareProcessNamespace: &[]bool{false}[0],
+				},
+			},
+			expectedErr: "the attribute 'prometheusReload' requires a shared process namespace",
+		},
+		{
+			name: "hot reload without shared process namespace",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					ConfigReload:          ConfigReloadHotReload,
+					ShareProcessNamespace: &[]bool{false}[0],
+				},
+			},
+			expectedErr: "the attribute 'configReload' HotReload requires a shared process namespace",
+		},
+		{
+			name: "invalid mode with debug",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:  ModeSidecar,
+					Debug: &DebugSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'debug'",
+		},
+		{
+			name: "debug with host network",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					HostNetwork: true,
+					Debug:       &DebugSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the attribute 'debug' can't be enabled with 'hostNetwork'",
+		},
+		{
+			name: "debug with the same port for pprof and zpages",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Debug: &DebugSpec{Enabled: true, PprofPort: 8000, ZPagesPort: 8000},
+				},
+			},
+			expectedErr: "the attribute 'debug' uses the port 8000 for both pprof and zpages",
+		},
+		{
+			name: "debug injecting the config without otelConfig",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Debug: &DebugSpec{Enabled: true, PprofPort: 1777, ZPagesPort: 55679, InjectConfig: true},
+				},
+			},
+			expectedErr: "the attribute 'debug.injectConfig' only applies to the 'otelConfig', which is not set",
+		},
+		{
+			name: "debug injecting the config with the zpages diagnostics",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig:  "service:\n  pipelines: {}\n",
+					Diagnostics: &DiagnosticsSpec{ZPages: true},
+					Debug:       &DebugSpec{Enabled: true, PprofPort: 1777, ZPagesPort: 55679, InjectConfig: true},
+				},
+			},
+			expectedErr: "the attribute 'debug.injectConfig' conflicts with 'diagnostics.zpages'",
+		},
+		{
+			name: "goRuntime with a zero memoryLimit",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					GoRuntime: &GoRuntimeSpec{MemoryLimit: resource.NewQuantity(0, resource.BinarySI)},
+				},
+			},
+			expectedErr: "the attribute 'goRuntime.memoryLimit' must be positive",
+		},
+		{
+			name: "invalid mode with securityProfiles",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:             ModeSidecar,
+					SecurityProfiles: &SecurityProfilesSpec{AppArmor: "runtime/default"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'securityProfiles'",
+		},
+		{
+			name: "securityProfiles on Windows",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					NodeSelector:     map[string]string{"kubernetes.io/os": "windows"},
+					SecurityProfiles: &SecurityProfilesSpec{AppArmor: "runtime/default"},
+				},
+			},
+			expectedErr: "the attribute 'securityProfiles' is not supported on Windows",
+		},
+		{
+			name: "seccomp profile set twice",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					PodSecurityContext: &v1.PodSecurityContext{SeccompProfile: &v1.SeccompProfile{Type: v1.SeccompProfileTypeUnconfined}},
+					SecurityProfiles:   &SecurityProfilesSpec{Seccomp: &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault}},
+				},
+			},
+			expectedErr: "the attributes 'securityProfiles.seccomp' and 'podSecurityContext.seccompProfile' can't both be set",
+		},
+		{
+			name: "invalid mode with trustedCA",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:      ModeSidecar,
+					TrustedCA: &TrustedCASpec{ConfigMap: "corporate-ca"},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'trustedCA'",
+		},
+		{
+			name: "trustedCA with both a configMap and a secret",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					TrustedCA: &TrustedCASpec{ConfigMap: "corporate-ca", Secret: "corporate-ca"},
+				},
+			},
+			expectedErr: "the attribute 'trustedCA' must set exactly one of 'configMap' and 'secret'",
+		},
+		{
+			name: "trustedCA without a configMap or a secret",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					TrustedCA: &TrustedCASpec{Key: "ca.crt"},
+				},
+			},
+			expectedErr: "the attribute 'trustedCA' must set exactly one of 'configMap' and 'secret'",
+		},
+		{
+			name: "trustedCA on Windows",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+					TrustedCA:    &TrustedCASpec{Secret: "corporate-ca"},
+				},
+			},
+			expectedErr: "the attribute 'trustedCA' is not supported on Windows",
+		},
+		{
+			name: "invalid mode with controlPlaneMetrics",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                ModeDaemonSet,
+					ControlPlaneMetrics: &ControlPlaneMetricsSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to daemonset, which does not support the attribute 'controlPlaneMetrics'",
+		},
+		{
+			name: "controlPlaneMetrics with a duplicate component",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					ControlPlaneMetrics: &ControlPlaneMetricsSpec{
+						Enabled:    true,
+						Components: []ControlPlaneComponent{ControlPlaneAPIServer, ControlPlaneAPIServer},
+					},
+				},
+			},
+			expectedErr: "the attribute 'controlPlaneMetrics' is incorrect, the component apiserver is listed more than once",
+		},
+		{
+			name: "controlPlaneMetrics with a job already defined",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					Prometheus: PrometheusConfig{ExtraScrapeConfigs: []AnyConfig{{Object: map[string]interface{}{
+						"job_name": "kubernetes-control-plane-apiserver",
+					}}}},
+					ControlPlaneMetrics: &ControlPlaneMetricsSpec{Enabled: true},
+				},
+			},
+			expectedErr: "the Prometheus configuration already defines the job kubernetes-control-plane-apiserver",
+		},
+		{
+			name: "invalid mode with hostPID",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:    ModeSidecar,
+					HostPID: true,
+				},
+			},
+			expectedErr: "does not support the attributes 'hostPID', 'hostIPC' and 'shareProcessNamespace'",
+		},
+		{
+			name: "hostPID with shareProcessNamespace",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:                  ModeDaemonSet,
+					HostPID:               true,
+					ShareProcessNamespace: &[]bool{true}[0],
+				},
+			},
+			expectedErr: "the attribute 'hostPID' can't be combined with a shared process namespace",
+		},
+		{
+			name: "hostPID with the HotReload configReload",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:         ModeDaemonSet,
+					HostPID:      true,
+					ConfigReload: ConfigReloadHotReload,
+				},
+			},
+			expectedErr:      "the attribute 'hostPID' can't be combined with a shared process namespace",
+			expectedWarnings: []string{"the attribute 'configReload' HotReload requires the operator to translate the configs, with --config-translator, the config changes roll out the pods"},
+		},
+		{
+			name: "invalid mode with tolerations",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:        ModeSidecar,
+					Tolerations: []v1.Toleration{{}, {}},
+				},
+			},
+			expectedErr: "does not support the attribute 'tolerations'",
+		},
+		{
+			name: "invalid target allocator config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled: true,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec Prometheus configuration is incorrect",
+		},
+		{
+			name: "invalid port name",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Ports: []v1.ServicePort{
+						{
+							// this port name contains a non alphanumeric character, which is invalid.
+							Name:     "-test🦄port",
+							Port:     12345,
+							Protocol: v1.ProtocolTCP,
+						},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec Ports configuration is incorrect",
+		},
+		{
+			name: "node port of a ClusterIP service",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Ports: []v1.ServicePort{
+						{
+							Name:     "emf-tcp",
+							Port:     25888,
+							NodePort: 30888,
+						},
+					},
+				},
+			},
+			expectedErr: "sets a nodePort, which requires the NodePort or LoadBalancer serviceType",
+		},
+		{
+			name: "invalid port name, too long",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Ports: []v1.ServicePort{
+						{
+							Name: "aaaabbbbccccdddd", // len: 16, too long
+							Port: 5555,
+						},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec Ports configuration is incorrect",
+		},
+		{
+			name: "invalid port num",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Ports: []v1.ServicePort{
+						{
+							Name: "aaaabbbbccccddd", // len: 15
+							// no port set means it's 0, which is invalid
+						},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec Ports configuration is incorrect",
+		},
+		{
+			name: "invalid max replicas",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &zero,
+				},
+			},
+			expectedErr:      "maxReplicas should be defined and one or more",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "invalid replicas, greater than max",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Replicas:    &five,
+				},
+			},
+			expectedErr:      "replicas must not be greater than maxReplicas",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "invalid min replicas, greater than max",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					MinReplicas: &five,
+				},
+			},
+			expectedErr:      "minReplicas must not be greater than maxReplicas",
+			expectedWarnings: []string{"MaxReplicas is deprecated", "MinReplicas is deprecated"},
+		},
+		{
+			name: "invalid min replicas, lesser than 1",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					MinReplicas: &zero,
+				},
+			},
+			expectedErr:      "minReplicas should be one or more",
+			expectedWarnings: []string{"MaxReplicas is deprecated", "MinReplicas is deprecated"},
+		},
+		{
+			name: "invalid autoscaler scale down",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Autoscaler: &AutoscalerSpec{
+						Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+							ScaleDown: &autoscalingv2.HPAScalingRules{
+								StabilizationWindowSeconds: &zero,
+							},
+						},
+					},
+				},
+			},
+			expectedErr:      "scaleDown should be one or more",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "invalid autoscaler scale up",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Autoscaler: &AutoscalerSpec{
+						Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+							ScaleUp: &autoscalingv2.HPAScalingRules{
+								StabilizationWindowSeconds: &zero,
+							},
+						},
+					},
+				},
+			},
+			expectedErr:      "scaleUp should be one or more",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "invalid autoscaler target cpu utilization",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Autoscaler: &AutoscalerSpec{
+						TargetCPUUtilization: &zero,
+					},
+				},
+			},
+			expectedErr:      "targetCPUUtilization should be greater than 0 and less than 100",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "autoscaler minReplicas is less than maxReplicas",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Autoscaler: &AutoscalerSpec{
+						MaxReplicas: &one,
+						MinReplicas: &five,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec autoscale configuration is incorrect, minReplicas must not be greater than maxReplicas",
+		},
+		{
+			name: "invalid autoscaler metric type",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Autoscaler: &AutoscalerSpec{
+						Metrics: []MetricSpec{
+							{
+								Type: autoscalingv2.ResourceMetricSourceType,
+							},
+						},
+					},
+				},
+			},
+			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, metric type unsupported. Expected metric of source type Pod",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "invalid pod metric average value",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Autoscaler: &AutoscalerSpec{
+						Metrics: []MetricSpec{
+							{
+								Type: autoscalingv2.PodsMetricSourceType,
+								Pods: &autoscalingv2.PodsMetricSource{
+									Metric: autoscalingv2.MetricIdentifier{
+										Name: "custom1",
+									},
+									Target: autoscalingv2.MetricTarget{
+										Type:         autoscalingv2.AverageValueMetricType,
+										AverageValue: resource.NewQuantity(int64(0), resource.DecimalSI),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, average value should be greater than 0",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "utilization target is not valid with pod metrics",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					MaxReplicas: &three,
+					Autoscaler: &AutoscalerSpec{
+						Metrics: []MetricSpec{
+							{
+								Type: autoscalingv2.PodsMetricSourceType,
+								Pods: &autoscalingv2.PodsMetricSource{
+									Metric: autoscalingv2.MetricIdentifier{
+										Name: "custom1",
+									},
+									Target: autoscalingv2.MetricTarget{
+										Type:               autoscalingv2.UtilizationMetricType,
+										AverageUtilization: &one,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErr:      "the OpenTelemetry Spec autoscale configuration is incorrect, invalid pods target type",
+			expectedWarnings: []string{"MaxReplicas is deprecated"},
+		},
+		{
+			name: "invalid deployment mode incompabible with ingress settings",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeSidecar,
+					Ingress: Ingress{
+						Type: IngressTypeNginx,
+					},
+				},
+			},
+			expectedErr: fmt.Sprintf("Ingress can only be used in combination with the modes: %s, %s, %s",
+				ModeDeployment, ModeDaemonSet, ModeStatefulSet,
+			),
+		},
+		{
+			name: "invalid mode with priorityClassName",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:              ModeSidecar,
+					PriorityClassName: "test-class",
+				},
+			},
+			expectedErr: "does not support the attribute 'priorityClassName'",
+		},
+		{
+			name: "invalid mode with affinity",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeSidecar,
+					Affinity: &v1.Affinity{
+						NodeAffinity: &v1.NodeAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+								NodeSelectorTerms: []v1.NodeSelectorTerm{
+									{
+										MatchExpressions: []v1.NodeSelectorRequirement{
+											{
+												Key:      "node",
+												Operator: v1.NodeSelectorOpIn,
+												Values:   []string{"test-node"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErr: "does not support the attribute 'affinity'",
+		},
+		{
+			name: "invalid InitialDelaySeconds",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					LivenessProbe: &Probe{
+						InitialDelaySeconds: &minusOne,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec LivenessProbe InitialDelaySeconds configuration is incorrect",
+		},
+		{
+			name: "invalid PeriodSeconds",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					LivenessProbe: &Probe{
+						PeriodSeconds: &zero,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec LivenessProbe PeriodSeconds configuration is incorrect",
+		},
+		{
+			name: "invalid TimeoutSeconds",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					LivenessProbe: &Probe{
+						TimeoutSeconds: &zero,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec LivenessProbe TimeoutSeconds configuration is incorrect",
+		},
+		{
+			name: "invalid SuccessThreshold",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					LivenessProbe: &Probe{
+						SuccessThreshold: &zero,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec LivenessProbe SuccessThreshold configuration is incorrect",
+		},
+		{
+			name: "invalid FailureThreshold",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					LivenessProbe: &Probe{
+						FailureThreshold: &zero,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec LivenessProbe FailureThreshold configuration is incorrect",
+		},
+		{
+			name: "invalid TerminationGracePeriodSeconds",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					LivenessProbe: &Probe{
+						TerminationGracePeriodSeconds: &zero64,
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Spec LivenessProbe TerminationGracePeriodSeconds configuration is incorrect",
+		},
+		{
+			name: "invalid AdditionalContainers",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeSidecar,
+					AdditionalContainers: []v1.Container{
+						{
+							Name: "test",
+						},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to sidecar, which does not support the attribute 'AdditionalContainers'",
+		},
+		{
+			name: "missing ingress hostname for subdomain ruleType",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Ingress: Ingress{
+						RuleType: IngressRuleTypeSubdomain,
+					},
+				},
+			},
+			expectedErr: "a valid Ingress hostname has to be defined for subdomain ruleType",
+		},
+		{
+			name: "invalid updateStrategy for Deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+						Type: "RollingUpdate",
+						RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+							MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
+							MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: int32(1)},
+						},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'updateStrategy'",
+		},
+		{
+			name: "invalid versionSplit for Deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDeployment,
+					VersionSplit: &VersionSplitSpec{
+						Image:     "cloudwatch-agent:next",
+						NodeLabel: NodeLabel{Key: "agent-cohort", Value: "canary"},
+					},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'versionSplit'",
+		},
+		{
+			name: "invalid nodeTermination for Deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeDeployment,
+					NodeTermination: &NodeTerminationSpec{},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'nodeTermination'",
+		},
+		{
+			name: "invalid rolloutStrategy for Deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeDeployment,
+					RolloutStrategy: &RolloutStrategySpec{Type: RolloutStrategyCanary},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'rolloutStrategy'",
+		},
+		{
+			name: "canary settings of a rolling update",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeDaemonSet,
+					RolloutStrategy: &RolloutStrategySpec{Type: RolloutStrategyRollingUpdate, Canary: &CanaryRolloutSpec{MaxCanaryNodes: 2}},
+				},
+			},
+			expectedErr: "the attribute 'rolloutStrategy.canary' requires the Canary rollout strategy type",
+		},
+		{
+			name: "canary rollout with a negative bake duration",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					RolloutStrategy: &RolloutStrategySpec{
+						Type:   RolloutStrategyCanary,
+						Canary: &CanaryRolloutSpec{BakeDuration: &metav1.Duration{Duration: -time.Minute}},
+					},
+				},
+			},
+			expectedErr: "the attribute 'rolloutStrategy.canary.bakeDuration' must be positive",
+		},
+		{
+			name: "canary rollout with a versionSplit",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:            ModeDaemonSet,
+					RolloutStrategy: &RolloutStrategySpec{Type: RolloutStrategyCanary},
+					VersionSplit: &VersionSplitSpec{
+						Image:     "cloudwatch-agent:next",
+						NodeLabel: NodeLabel{Key: "agent-cohort", Value: "canary"},
+					},
+				},
+			},
+			expectedErr: "the canary rollout strategy and the attribute 'versionSplit' can't be used together",
+		},
+		{
+			name: "invalid pipelineSplit for Deployment mode",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:          ModeDeployment,
+					PipelineSplit: &PipelineSplitSpec{},
+				},
+			},
+			expectedErr: "the OpenTelemetry Collector mode is set to deployment, which does not support the attribute 'pipelineSplit'",
+		},
+		{
+			name: "pipelineSplit clashing with a node group",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode:          ModeDaemonSet,
+					NodeGroups:    []NodeGroupSpec{{Name: "traces", NodeLabel: NodeLabel{Key: "tier", Value: "traces"}, Config: "{}"}},
+					PipelineSplit: &PipelineSplitSpec{},
+				},
+			},
+			expectedErr: "the name traces of the node group or override is already used by the deployment of the 'pipelineSplit'",
+		},
+		{
+			name: "pipelineSplit autoscaler without maxReplicas",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					PipelineSplit: &PipelineSplitSpec{
+						Metrics: PipelineDeploymentSpec{Autoscaler: &AutoscalerSpec{MinReplicas: &one}},
+					},
+				},
+			},
+			expectedErr: "the attribute 'pipelineSplit.metrics' is incorrect, maxReplicas should be defined and one or more",
+		},
+		{
+			name: "pipelineSplit autoscaler with replicas above maxReplicas",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeDaemonSet,
+					PipelineSplit: &PipelineSplitSpec{
+						Metrics: PipelineDeploymentSpec{Replicas: &three, Autoscaler: &AutoscalerSpec{MaxReplicas: &one}},
+					},
+				},
+			},
+			expectedErr: "the attribute 'pipelineSplit.metrics' is incorrect, minReplicas must not be greater than maxReplicas",
+		},
+		{
+			name: "invalid args",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Args: &Args{invalid: json.RawMessage(`"--log-level=debug"`)},
+				},
+			},
+			expectedErr: "the attribute 'args' must be a map or a list of strings",
+		},
+		{
+			name: "valid destinations",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig: "service:\n  pipelines:\n    metrics/app:\n      receivers: [otlp]\n",
+					Destinations: []DestinationSpec{
+						{Signal: "metrics", Region: "us-east-1", MetricsNamespace: "Cluster"},
+						{Signal: "logs", Region: "us-east-1", LogGroup: "/eks/cluster"},
+						{Signal: "metrics", Pipeline: "metrics/app", Region: "eu-west-1", LogGroup: "/eks/app/metrics"},
+					},
+				},
+			},
+		},
+		{
+			name: "destinations of the config with different regions",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Destinations: []DestinationSpec{{Signal: "metrics", Region: "us-east-1"}, {Signal: "logs", Region: "eu-west-1"}},
+				},
+			},
+			expectedErr: "the attribute 'destinations' is incorrect, the destinations of the sections of the config set the regions us-east-1 and eu-west-1",
+		},
+		{
+			name: "several destinations of a section",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Destinations: []DestinationSpec{{Signal: "logs", LogGroup: "a"}, {Signal: "logs", LogGroup: "b"}},
+				},
+			},
+			expectedErr: "the attribute 'destinations' is incorrect, the logs section of the config has several destinations",
+		},
+		{
+			name: "metrics namespace of the logs",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Destinations: []DestinationSpec{{Signal: "logs", MetricsNamespace: "App"}},
+				},
+			},
+			expectedErr: "the destination of the logs section of the config sets a metricsNamespace, which only the metrics support",
+		},
+		{
+			name: "destination of a missing pipeline",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig:   "service:\n  pipelines:\n    metrics/app:\n      receivers: [otlp]\n",
+					Destinations: []DestinationSpec{{Signal: "metrics", Pipeline: "metrics/other", Region: "us-east-1"}},
+				},
+			},
+			expectedErr: "the pipeline metrics/other doesn't exist in the otelConfig",
+		},
+		{
+			name: "destination of a pipeline of another signal",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig:   "service:\n  pipelines:\n    metrics/app:\n      receivers: [otlp]\n",
+					Destinations: []DestinationSpec{{Signal: "logs", Pipeline: "metrics/app", LogGroup: "app"}},
+				},
+			},
+			expectedErr: "the pipeline metrics/app is a metrics pipeline, not a logs one",
+		},
+	}
+
 	for _, test := range tests {
 		test := test
 		t.Run(test.name, func(t *testing.T) {
@@ -787,3 +1699,504 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestOTELColValidatingWebhookControlPlaneMetrics(t *testing.T) {
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg:    config.New(config.WithCollectorImage("collector:v0.0.0")),
+	}
+	otelcol := &AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{
+			Mode:                ModeDeployment,
+			Replicas:            &[]int32{2}[0],
+			ControlPlaneMetrics: &ControlPlaneMetricsSpec{Enabled: true},
+		},
+	}
+	warnings, err := cvw.ValidateCreate(context.Background(), otelcol)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"each of the 2 replicas scrapes the control plane, which duplicates its metrics unless the target allocator is enabled"}, warnings)
+
+	otelcol.Spec.Replicas = &[]int32{1}[0]
+	warnings, err = cvw.ValidateCreate(context.Background(), otelcol)
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestOTELColValidatingWebhookFeatureGates(t *testing.T) {
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg:    config.New(config.WithCollectorImage("collector:v0.0.0")),
+	}
+	otelcol := &AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{
+			Mode:         ModeDaemonSet,
+			FeatureGates: map[string]bool{"exporter.xray.allowDot": true},
+		},
+	}
+	warnings, err := cvw.ValidateCreate(context.Background(), otelcol)
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	otelcol.Spec.Args = &Args{List: []string{"--feature-gates=+receiver.otlp.grpc"}}
+	warnings, err = cvw.ValidateCreate(context.Background(), otelcol)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"both the featureGates and the args set the --feature-gates flag, the agent gets both"}, warnings)
+
+	// the feature gates unknown to the operator are passed to the agent, whose version may support them
+	otelcol.Spec.Args = nil
+	otelcol.Spec.FeatureGates["receiver.unknown"] = true
+	warnings, err = cvw.ValidateCreate(context.Background(), otelcol)
+	assert.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "the feature gates receiver.unknown are unknown to the operator")
+}
+
+func TestOTELColValidatingWebhookWindowsEvents(t *testing.T) {
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg:    config.New(config.WithCollectorImage("collector:v0.0.0")),
+	}
+	otelcol := &AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{
+			Mode:         ModeDaemonSet,
+			NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+			Config:       `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["ERROR"], "log_group_name": "system"}]}}}}`,
+		},
+	}
+	warnings, err := cvw.ValidateCreate(context.Background(), otelcol)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{`collecting Windows event logs escalates the privileges of the agent pods: they run as HostProcess containers with the host network, as the user NT AUTHORITY\SYSTEM`}, warnings)
+
+	otelcol.Spec.PodSecurityContext = &v1.PodSecurityContext{
+		WindowsOptions: &v1.WindowsSecurityContextOptions{RunAsUserName: &[]string{`NT AUTHORITY\Local service`}[0]},
+	}
+	warnings, err = cvw.ValidateCreate(context.Background(), otelcol)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{`collecting Windows event logs escalates the privileges of the agent pods: they run as HostProcess containers with the host network, as the user NT AUTHORITY\Local service`}, warnings)
+}
+
+func TestOTELColValidatingWebhookTargetAllocatorDisabled(t *testing.T) {
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg:    config.New(config.WithTargetAllocatorEnabled(false)),
+	}
+
+	_, err := cvw.ValidateCreate(context.Background(), &AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{
+			Mode:            ModeStatefulSet,
+			TargetAllocator: AmazonCloudWatchAgentTargetAllocator{Enabled: true},
+		},
+	})
+	assert.ErrorContains(t, err, "the target allocator is disabled in the operator")
+
+	_, err = cvw.ValidateCreate(context.Background(), &AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{Mode: ModeStatefulSet},
+	})
+	assert.NoError(t, err)
+}
+
+func TestOTELColValidatingWebhookResourceNames(t *testing.T) {
+	existing := &AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "team"},
+		Spec:       AmazonCloudWatchAgentSpec{NameOverride: "shared"},
+	}
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg:    config.New(),
+		reader: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(existing).Build(),
+	}
+
+	for _, tt := range []struct {
+		name        string
+		otelcol     *AmazonCloudWatchAgent
+		expectedErr string
+	}{
+		{
+			name:        "same name override",
+			otelcol:     &AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team"}, Spec: AmazonCloudWatchAgentSpec{NameOverride: "shared"}},
+			expectedErr: "the objects of the agent would be named shared like the objects of the agent agent",
+		},
+		{
+			name:        "name override of another agent",
+			otelcol:     &AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "team"}},
+			expectedErr: "the objects of the agent would be named shared like the objects of the agent agent",
+		},
+		{
+			name:    "other namespace",
+			otelcol: &AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "search"}, Spec: AmazonCloudWatchAgentSpec{NameOverride: "shared"}},
+		},
+		{
+			name:    "update of the agent itself",
+			otelcol: existing,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cvw.ValidateUpdate(context.Background(), tt.otelcol, tt.otelcol)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestOTELColValidatingWebhookDeletedAgent(t *testing.T) {
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg:    config.New(config.WithTargetAllocatorEnabled(false)),
+	}
+	otelcol := &AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{
+			Mode:            ModeStatefulSet,
+			TargetAllocator: AmazonCloudWatchAgentTargetAllocator{Enabled: true},
+		},
+	}
+	_, err := cvw.ValidateUpdate(context.Background(), otelcol, otelcol)
+	assert.Error(t, err)
+
+	// the finalizers of a deleted agent are removed even when the operator no longer accepts its spec
+	now := metav1.Now()
+	otelcol.DeletionTimestamp = &now
+	_, err = cvw.ValidateUpdate(context.Background(), otelcol, otelcol)
+	assert.NoError(t, err)
+	_, err = cvw.ValidateDelete(context.Background(), otelcol)
+	assert.NoError(t, err)
+}
+
+func TestOTELColValidatingWebhookExporterPolicy(t *testing.T) {
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg: config.New(
+			config.WithExporterPolicy(&exporterpolicy.Policy{
+				AllowedExporters: []string{"awsemf", "awsxray"},
+				AllowedEndpoints: []string{"*.amazonaws.com"},
+			}),
+		),
+	}
+
+	tests := []struct {
+		name        string
+		otelcol     AmazonCloudWatchAgent
+		expectedErr string
+	}{
+		{
+			name: "allowed exporters",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config:     `{"traces":{"endpoint_override":"https://xray.us-west-2.amazonaws.com"}}`,
+					OtelConfig: "exporters:\n  awsemf:\n  awsxray/traces:\n    endpoint: https://xray.us-west-2.amazonaws.com\n",
+				},
+			},
+		},
+		{
+			name: "exporter not allowed",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig: "exporters:\n  otlphttp/saas:\n    endpoint: https://otlp.example.com\n",
+				},
+			},
+			expectedErr: `the exporter "otlphttp/saas" is not allowed by the exporter policy`,
+		},
+		{
+			name: "endpoint not allowed",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					OtelConfig: "exporters:\n  awsxray:\n    endpoint: https://xray.example.com\n",
+				},
+			},
+			expectedErr: `the endpoint "https://xray.example.com" is not allowed`,
+		},
+		{
+			name: "endpoint override not allowed",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: `{"logs":{"endpoint_override":"logs.example.com:443"}}`,
+				},
+			},
+			expectedErr: "the Amazon CloudWatch Agent config is rejected",
+		},
+		{
+			name: "config that doesn't parse",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: `{"logs":{"endpoint_override":"logs.example.com:443"}`,
+				},
+			},
+			expectedErr: "the Amazon CloudWatch Agent config is incorrect",
+		},
+		{
+			name: "endpoint overrides not allowed",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					AWSEndpointOverrides: AWSEndpointOverridesSpec{
+						CloudWatch: "https://monitoring.us-west-2.amazonaws.com",
+						Logs:       "https://logs.example.com",
+					},
+				},
+			},
+			expectedErr: "the awsEndpointOverrides are rejected by the exporter policy",
+		},
+		{
+			name: "xray endpoint override not allowed",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: `{"traces":{"traces_collected":{"xray":{}}}}`,
+					XRay:   &XRaySpec{EndpointOverride: "https://xray.example.com"},
+				},
+			},
+			expectedErr: "the attribute 'xray.endpointOverride' is rejected by the exporter policy",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := cvw.ValidateCreate(context.Background(), &test.otelcol)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.expectedErr)
+
+			// the operator checks the configs again once the overrides are layered onto them
+			_, err = ValidateConfigPolicies(cvw.cfg, &test.otelcol)
+			assert.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestOTELColValidatingWebhookPrometheusGuardrails(t *testing.T) {
+	prometheus := PrometheusConfig{Config: &AnyConfig{Object: map[string]interface{}{
+		"scrape_configs": []interface{}{
+			map[string]interface{}{"job_name": "a", "static_configs": []interface{}{map[string]interface{}{"targets": []interface{}{"a:9100", "b:9100"}}}},
+			map[string]interface{}{"job_name": "b", "kubernetes_sd_configs": []interface{}{map[string]interface{}{"role": "pod"}}},
+		},
+	}}}
+	otelConfig := "receivers:\n  prometheus/apps:\n    config:\n      scrape_configs:\n      - job_name: c\n      - job_name: d\n      - job_name: e\n"
+
+	tests := []struct {
+		name             string
+		guardrails       promguardrails.Guardrails
+		otelcol          AmazonCloudWatchAgent
+		expectedErr      string
+		expectedWarnings []string
+	}{
+		{
+			name:       "within the guardrails",
+			guardrails: promguardrails.Guardrails{Enforce: true, MaxScrapeConfigs: 3},
+			otelcol:    AmazonCloudWatchAgent{Spec: AmazonCloudWatchAgentSpec{Prometheus: prometheus, OtelConfig: otelConfig}},
+		},
+		{
+			name:       "warned",
+			guardrails: promguardrails.Guardrails{MaxScrapeConfigs: 2, DenyClusterWideDiscovery: true},
+			otelcol:    AmazonCloudWatchAgent{Spec: AmazonCloudWatchAgentSpec{Prometheus: prometheus, OtelConfig: otelConfig}},
+			expectedWarnings: []string{
+				"the scrape config b discovers the pod targets of the whole cluster, restrict it with namespaces or selectors",
+				"receiver prometheus/apps: the config has 3 scrape configs, more than the limit of 2",
+			},
+		},
+		{
+			name:        "enforced",
+			guardrails:  promguardrails.Guardrails{Enforce: true, MaxStaticTargets: 1},
+			otelcol:     AmazonCloudWatchAgent{Spec: AmazonCloudWatchAgentSpec{Prometheus: prometheus}},
+			expectedErr: "the Prometheus scrape configs exceed the guardrails: the config has 2 static targets, more than the limit of 1",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cvw := &CollectorWebhook{
+				logger: logr.Discard(),
+				scheme: testScheme,
+				cfg:    config.New(config.WithPrometheusGuardrails(&test.guardrails)),
+			}
+			warnings, err := cvw.ValidateCreate(context.Background(), &test.otelcol)
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			for _, warning := range test.expectedWarnings {
+				assert.Contains(t, warnings, warning)
+			}
+		})
+	}
+}
+
+func TestOTELColValidatingWebhookCompatibility(t *testing.T) {
+	matrix := &compatibility.Matrix{Entries: []compatibility.Entry{{
+		Operator:   compatibility.Range{Min: "2.0.0"},
+		Agent:      compatibility.Range{Min: "1.300040.0"},
+		Kubernetes: compatibility.Range{Min: "1.25", Max: "1.31"},
+	}}}
+
+	tests := []struct {
+		name             string
+		checker          compatibility.Checker
+		otelcol          AmazonCloudWatchAgent
+		expectedErr      string
+		expectedWarnings []string
+	}{
+		{
+			name:    "supported",
+			checker: compatibility.Checker{Enforce: true, Matrix: matrix, Operator: "2.0.1", Kubernetes: "v1.30.0"},
+			otelcol: AmazonCloudWatchAgent{Spec: AmazonCloudWatchAgentSpec{Image: "cloudwatch-agent:1.300045.0"}},
+		},
+		{
+			name:    "warned",
+			checker: compatibility.Checker{Matrix: matrix, Operator: "2.0.1", Kubernetes: "v1.32.0"},
+			otelcol: AmazonCloudWatchAgent{Spec: AmazonCloudWatchAgentSpec{Image: "cloudwatch-agent:1.300039.0"}},
+			expectedWarnings: []string{
+				"the Kubernetes version v1.32.0 is not supported by the operator version 2.0.1, which supports versions 1.25 to 1.31",
+				"the agent version 1.300039.0 is not supported by the operator version 2.0.1, which supports versions 1.300040.0 and later",
+			},
+		},
+		{
+			name:        "enforced on the default image",
+			checker:     compatibility.Checker{Enforce: true, Matrix: matrix, Operator: "2.0.1"},
+			expectedErr: "the versions are not supported together: the agent version 1.300032.0 is not supported",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cvw := &CollectorWebhook{
+				logger: logr.Discard(),
+				scheme: testScheme,
+				cfg: config.New(
+					config.WithCollectorImage("cloudwatch-agent:1.300032.0"),
+					config.WithCompatibilityChecker(&test.checker),
+				),
+			}
+			warnings, err := cvw.ValidateCreate(context.Background(), &test.otelcol)
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			for _, warning := range test.expectedWarnings {
+				assert.Contains(t, warnings, warning)
+			}
+		})
+	}
+}
+
+func TestOTELColValidatingWebhookPrometheusHelpers(t *testing.T) {
+	base := &AnyConfig{Object: map[string]interface{}{
+		"scrape_configs": []interface{}{map[string]interface{}{"job_name": "a"}},
+	}}
+	scrapeConfig := func(job string, relabel map[string]interface{}) AnyConfig {
+		sc := map[string]interface{}{
+			"job_name":              job,
+			"kubernetes_sd_configs": []interface{}{map[string]interface{}{"role": "pod"}},
+		}
+		if relabel != nil {
+			sc["relabel_configs"] = []interface{}{relabel}
+		}
+		return AnyConfig{Object: sc}
+	}
+
+	tests := []struct {
+		name        string
+		prometheus  PrometheusConfig
+		expectedErr string
+	}{
+		{
+			name: "valid helpers",
+			prometheus: PrometheusConfig{
+				Config:               base,
+				GlobalScrapeInterval: "30s",
+				ExtraScrapeConfigs: []AnyConfig{scrapeConfig("b", map[string]interface{}{
+					"source_labels": []interface{}{"__meta_kubernetes_pod_annotation_prometheus_io_scrape"},
+					"action":        "keep",
+					"regex":         "true",
+				})},
+			},
+		},
+		{
+			name:        "invalid global scrape interval",
+			prometheus:  PrometheusConfig{GlobalScrapeInterval: "30 seconds"},
+			expectedErr: "the attribute 'prometheus.globalScrapeInterval' is not a valid duration",
+		},
+		{
+			name: "invalid relabel config",
+			prometheus: PrometheusConfig{ExtraScrapeConfigs: []AnyConfig{
+				scrapeConfig("b", map[string]interface{}{"action": "replace", "regex": "(", "target_label": "pod"}),
+			}},
+			expectedErr: "the scrape config 0 of 'prometheus.extraScrapeConfigs' is invalid",
+		},
+		{
+			name: "unknown relabel action",
+			prometheus: PrometheusConfig{ExtraScrapeConfigs: []AnyConfig{
+				scrapeConfig("b", map[string]interface{}{"action": "drop_all"}),
+			}},
+			expectedErr: "the scrape config 0 of 'prometheus.extraScrapeConfigs' is invalid",
+		},
+		{
+			name:        "missing job name",
+			prometheus:  PrometheusConfig{ExtraScrapeConfigs: []AnyConfig{scrapeConfig("", nil)}},
+			expectedErr: "the scrape config 0 of 'prometheus.extraScrapeConfigs' is invalid",
+		},
+		{
+			name:        "reused job name",
+			prometheus:  PrometheusConfig{Config: base, ExtraScrapeConfigs: []AnyConfig{scrapeConfig("a", nil)}},
+			expectedErr: "the scrape config 0 of 'prometheus.extraScrapeConfigs' reuses the job name \"a\"",
+		},
+	}
+
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg:    config.New(),
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := cvw.ValidateCreate(context.Background(), &AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{Mode: ModeDaemonSet, Prometheus: test.prometheus},
+			})
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestPrometheusConfigYamlHelpers(t *testing.T) {
+	prometheus := PrometheusConfig{
+		Config: &AnyConfig{Object: map[string]interface{}{
+			"global":         map[string]interface{}{"scrape_timeout": "10s"},
+			"scrape_configs": []interface{}{map[string]interface{}{"job_name": "a"}},
+		}},
+		GlobalScrapeInterval: "30s",
+		ExtraScrapeConfigs:   []AnyConfig{{Object: map[string]interface{}{"job_name": "b"}}},
+	}
+
+	out, err := prometheus.Yaml()
+	require.NoError(t, err)
+	assert.YAMLEq(t, `
+config:
+  global:
+    scrape_interval: 30s
+    scrape_timeout: 10s
+  scrape_configs:
+  - job_name: a
+  - job_name: b
+`, out)
+	// the config of the spec is left untouched
+	assert.Len(t, prometheus.Config.Object["scrape_configs"], 1)
+	assert.NotContains(t, prometheus.Config.Object["global"], "scrape_interval")
+	assert.False(t, (&PrometheusConfig{GlobalScrapeInterval: "30s"}).IsEmpty())
+}
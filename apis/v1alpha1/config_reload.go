@@ -0,0 +1,20 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+type (
+	// ConfigReloadStrategy represents how the agent pods pick up a change of the agent config.
+	// +kubebuilder:validation:Enum=Restart;HotReload
+	ConfigReloadStrategy string
+)
+
+const (
+	// ConfigReloadRestart specifies that every config change rolls out the agent pods.
+	ConfigReloadRestart ConfigReloadStrategy = "Restart"
+
+	// ConfigReloadHotReload specifies that changes limited to the config sections the agent can hot-reload are
+	// applied by making the agent reload its translated configs in its pod, while any other change rolls out the agent
+	// pods.
+	ConfigReloadHotReload ConfigReloadStrategy = "HotReload"
+)
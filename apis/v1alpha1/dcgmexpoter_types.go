@@ -43,6 +43,10 @@ type DcgmExporterSpec struct {
 	// consumed in the config file for the Collector.
 	// +optional
 	Env []v1.EnvVar `json:"env,omitempty"`
+	// List of sources to populate environment variables on the DCGM Exporter Pods.
+	// Values defined by Env with a duplicate key take precedence.
+	// +optional
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
 	// Toleration to schedule DCGM Exporter pods.
 	// This is only relevant to daemonset, statefulset, and deployment mode
 	// +optional
@@ -88,7 +92,7 @@ type DcgmExporterStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
-// +kubebuilder:resource:shortName=dcgmexp;dcgmexps
+// +kubebuilder:resource:shortName=dcgmexp;dcgmexps;dcgm
 // +kubebuilder:subresource:status
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="DCGM exporter Version"
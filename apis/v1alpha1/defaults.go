@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// RecordDefaultsApplied rebuilds the entries of the defaults-applied annotation of the object for the fields the caller
+// defaults, so that operator defaults can be told apart from user intent. The fields map them to their current values:
+// the fields just defaulted are recorded with their defaults, and the other ones keep their entries only while they
+// still hold the recorded defaults, dropping the entries of the fields the user changed since. The entries of the
+// fields defaulted elsewhere are left untouched. It returns whether the annotation changed.
+func RecordDefaultsApplied(obj metav1.Object, fields, defaults map[string]string) bool {
+	recorded := DefaultsApplied(obj)
+	rebuilt := map[string]string{}
+	for field, value := range recorded {
+		if current, ok := fields[field]; !ok || current == value {
+			rebuilt[field] = value
+		}
+	}
+	for field, value := range defaults {
+		rebuilt[field] = value
+	}
+
+	annotations := obj.GetAnnotations()
+	_, annotated := annotations[constants.AnnotationDefaultsApplied]
+	if len(rebuilt) == 0 {
+		if !annotated {
+			return false
+		}
+		delete(annotations, constants.AnnotationDefaultsApplied)
+		obj.SetAnnotations(annotations)
+		return true
+	}
+	// a map of strings always marshals
+	summary, _ := json.Marshal(rebuilt)
+	if annotated && annotations[constants.AnnotationDefaultsApplied] == string(summary) {
+		return false
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[constants.AnnotationDefaultsApplied] = string(summary)
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// DefaultsApplied returns the defaulted fields recorded in the defaults-applied annotation of the object.
+// An unreadable annotation is treated as empty.
+func DefaultsApplied(obj metav1.Object) map[string]string {
+	recorded := map[string]string{}
+	if summary, ok := obj.GetAnnotations()[constants.AnnotationDefaultsApplied]; ok {
+		if err := json.Unmarshal([]byte(summary), &recorded); err != nil {
+			return map[string]string{}
+		}
+	}
+	return recorded
+}
+
+// formatResourceList returns a compact, stable representation of the given resources, e.g. cpu=50m,memory=64Mi.
+func formatResourceList(resources corev1.ResourceList) string {
+	var values []string
+	for name, quantity := range resources {
+		values = append(values, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
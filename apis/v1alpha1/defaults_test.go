@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestRecordDefaultsApplied(t *testing.T) {
+	agent := &AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"foo": "bar"},
+		},
+	}
+
+	assert.False(t, RecordDefaultsApplied(agent, nil, nil))
+	assert.True(t, RecordDefaultsApplied(agent, map[string]string{"mode": "deployment"}, map[string]string{"mode": "deployment"}))
+	assert.True(t, RecordDefaultsApplied(agent, map[string]string{"image": ""}, map[string]string{"image": "agent:1"}))
+	assert.False(t, RecordDefaultsApplied(agent, map[string]string{"image": ""}, map[string]string{"image": "agent:1"}))
+	// the field still holding its default keeps its entry
+	assert.False(t, RecordDefaultsApplied(agent, map[string]string{"mode": "deployment"}, nil))
+
+	assert.Equal(t, `{"image":"agent:1","mode":"deployment"}`, agent.Annotations[constants.AnnotationDefaultsApplied])
+	assert.Equal(t, "bar", agent.Annotations["foo"])
+	assert.Equal(t, map[string]string{"image": "agent:1", "mode": "deployment"}, DefaultsApplied(agent))
+}
+
+func TestDefaultsAppliedInvalidAnnotation(t *testing.T) {
+	agent := &AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{constants.AnnotationDefaultsApplied: "mode=deployment"},
+		},
+	}
+
+	assert.Empty(t, DefaultsApplied(agent))
+	assert.True(t, RecordDefaultsApplied(agent, map[string]string{"mode": "deployment"}, map[string]string{"mode": "deployment"}))
+	assert.Equal(t, `{"mode":"deployment"}`, agent.Annotations[constants.AnnotationDefaultsApplied])
+}
+
+func TestRecordDefaultsAppliedRebuildsEntries(t *testing.T) {
+	agent := &AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{constants.AnnotationDefaultsApplied: `{"image":"agent:1","mode":"deployment","replicas":"1"}`},
+		},
+	}
+
+	// the mode set by the user since is no longer a default, the image defaulted elsewhere is left untouched
+	assert.True(t, RecordDefaultsApplied(agent, map[string]string{"mode": "daemonset", "replicas": "1"}, nil))
+	assert.Equal(t, map[string]string{"image": "agent:1", "replicas": "1"}, DefaultsApplied(agent))
+
+	// the image defaulted to a new value replaces the recorded one
+	assert.True(t, RecordDefaultsApplied(agent, map[string]string{"image": ""}, map[string]string{"image": "agent:2"}))
+	assert.Equal(t, map[string]string{"image": "agent:2", "replicas": "1"}, DefaultsApplied(agent))
+
+	// the annotation is removed once no default remains
+	assert.True(t, RecordDefaultsApplied(agent, map[string]string{"image": "custom:1", "replicas": "3"}, nil))
+	assert.NotContains(t, agent.Annotations, constants.AnnotationDefaultsApplied)
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// ServiceHost returns the host name of the Service of the agent within the cluster.
+func (a AmazonCloudWatchAgent) ServiceHost() string {
+	return naming.Service(a.ResourceName()) + "." + a.Namespace
+}
+
+// ExportsOnNode tells whether the pods can export to the agent of their own node through the IP of the node, the
+// agent being a daemonset binding its ports on the host network. The certificate of a TLS agent is issued for its
+// Service, so the pods keep exporting to the Service of a TLS agent.
+func (a AmazonCloudWatchAgent) ExportsOnNode() bool {
+	return a.Spec.Mode == ModeDaemonSet && a.Spec.HostNetwork && a.Spec.TLS == nil
+}
+
+// NodeLocalEndpoint returns the endpoint on the IP of the node of the pod, which the host IP variable holds, matching
+// a plain HTTP endpoint of the Service of the agent.
+func (a AmazonCloudWatchAgent) NodeLocalEndpoint(endpoint string) (string, bool) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "http" || u.Port() == "" {
+		return "", false
+	}
+	switch host := a.ServiceHost(); u.Hostname() {
+	case host, host + ".svc", host + ".svc.cluster.local":
+	default:
+		return "", false
+	}
+	local := fmt.Sprintf("http://$(%s):%s%s", constants.EnvHostIP, u.Port(), u.EscapedPath())
+	if u.RawQuery != "" {
+		local += "?" + u.RawQuery
+	}
+	return local, true
+}
@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExportsOnNode(t *testing.T) {
+	agent := AmazonCloudWatchAgent{Spec: AmazonCloudWatchAgentSpec{Mode: ModeDaemonSet, HostNetwork: true}}
+	assert.True(t, agent.ExportsOnNode())
+
+	// the certificate of a TLS agent is issued for its Service
+	agent.Spec.TLS = &TLSSpec{SecretName: "tls"}
+	assert.False(t, agent.ExportsOnNode())
+}
+
+func TestNodeLocalEndpoint(t *testing.T) {
+	agent := AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "team"}, Spec: AmazonCloudWatchAgentSpec{NameOverride: "team-agent"}}
+	for _, tt := range []struct {
+		endpoint string
+		expected string
+	}{
+		{endpoint: "http://team-agent.team:4316/v1/traces", expected: "http://$(HOST_IP):4316/v1/traces"},
+		{endpoint: "http://team-agent.team.svc.cluster.local:4316/v1/metrics?compression=gzip", expected: "http://$(HOST_IP):4316/v1/metrics?compression=gzip"},
+		{endpoint: "https://team-agent.team:4316"},
+		{endpoint: "http://team-agent.team"},
+		{endpoint: "http://agent.team:4316"},
+	} {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			endpoint, ok := agent.NodeLocalEndpoint(tt.endpoint)
+			assert.Equal(t, tt.expected != "", ok)
+			assert.Equal(t, tt.expected, endpoint)
+		})
+	}
+}
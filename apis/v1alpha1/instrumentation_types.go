@@ -32,6 +32,8 @@ type InstrumentationSpec struct {
 	// Env defines common env vars. There are four layers for env vars' definitions and
 	// the precedence order is: `original container env vars` > `language specific env vars` > `common env vars` > `instrument spec configs' vars`.
 	// If the former var had been defined, then the other vars would be ignored.
+	// Values can come from Secrets, ConfigMaps and fields of the pod through valueFrom, e.g. OTEL_EXPORTER_OTLP_HEADERS.
+	// The resource attributes are appended to an OTEL_RESOURCE_ATTRIBUTES defined through valueFrom.
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
@@ -65,6 +67,45 @@ type InstrumentationSpec struct {
 	// Nginx defines configuration for Nginx auto-instrumentation.
 	// +optional
 	Nginx Nginx `json:"nginx,omitempty"`
+
+	// Batch defines the configuration of the pods of Jobs and CronJobs, whose processes often exit before the
+	// telemetry buffered by the SDK is exported.
+	// +optional
+	Batch Batch `json:"batch,omitempty"`
+}
+
+// BatchWorkloadKind is a kind of batch workload.
+// +kubebuilder:validation:Enum=Job;CronJob
+type BatchWorkloadKind string
+
+const (
+	// BatchWorkloadJob is the kind of the Jobs which aren't created by a CronJob.
+	BatchWorkloadJob BatchWorkloadKind = "Job"
+	// BatchWorkloadCronJob is the kind of the CronJobs, whose Jobs create the pods.
+	BatchWorkloadCronJob BatchWorkloadKind = "CronJob"
+)
+
+// Batch defines the configuration of the pods of Jobs and CronJobs.
+type Batch struct {
+	// ExportInterval is the interval between the exports of the spans, the logs and the metrics of the pods, set in
+	// the OTEL_BSP_SCHEDULE_DELAY, OTEL_BLRP_SCHEDULE_DELAY and OTEL_METRIC_EXPORT_INTERVAL env vars unless the
+	// containers define them. Defaults to 1s.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	ExportInterval *metav1.Duration `json:"exportInterval,omitempty"`
+
+	// ShutdownDelay bounds the export of the telemetry the SDK flushes when the process exits, set in the
+	// OTEL_BSP_EXPORT_TIMEOUT, OTEL_BLRP_EXPORT_TIMEOUT and OTEL_METRIC_EXPORT_TIMEOUT env vars unless the containers
+	// define them. From Kubernetes 1.30, which enables the sleep action of the lifecycle hooks by default, a preStop hook
+	// also leaves this time to the SDK when a running job is stopped, such as on its active deadline, before the
+	// containers receive SIGTERM. The containers defining a preStop hook keep theirs. Defaults to 5s, 0s disables both.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	ShutdownDelay *metav1.Duration `json:"shutdownDelay,omitempty"`
+
+	// SkipKinds are the kinds of batch workloads whose pods aren't instrumented.
+	// +optional
+	SkipKinds []BatchWorkloadKind `json:"skipKinds,omitempty"`
 }
 
 // Resource defines the configuration for the resource attributes, as defined by the OpenTelemetry specification.
@@ -122,6 +163,59 @@ type Java struct {
 	// Resources describes the compute resource requirements.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PodMonitor makes the javaagent expose the JMX and application metrics of the instrumented pods, such as the
+	// Micrometer ones, in the Prometheus format, and creates a PodMonitor scraping them. It requires the Prometheus
+	// Operator CRDs.
+	// +optional
+	PodMonitor *JavaPodMonitor `json:"podMonitor,omitempty"`
+
+	// Extensions are the javaagent extensions loaded by the instrumented applications, such as custom
+	// instrumentations, passed to the javaagent through the otel.javaagent.extensions system property.
+	// +optional
+	Extensions []JavaExtension `json:"extensions,omitempty"`
+
+	// JVMArgs are appended to the JAVA_TOOL_OPTIONS of the instrumented containers, in order, after the javaagent and
+	// its extensions. An argument the container already sets is not repeated, and one setting a system property or
+	// a JVM option to another value than the container fails the injection.
+	// +optional
+	JVMArgs []string `json:"jvmArgs,omitempty"`
+}
+
+// JavaExtension defines the jars of a javaagent extension, found in a container image or in a ConfigMap.
+type JavaExtension struct {
+	// Image is a container image holding the jars of the extension in its Dir, copied next to the javaagent by an
+	// init container.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Dir is the directory of the Image holding the jars of the extension.
+	// +optional
+	Dir string `json:"dir,omitempty"`
+
+	// ConfigMap is the name of a ConfigMap of the namespace of the pods holding the jars of the extension in its
+	// binaryData, mounted in the instrumented containers. Either the Image or the ConfigMap is required.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// JavaPodMonitor defines the PodMonitor scraping the metrics of the Java instrumented pods.
+type JavaPodMonitor struct {
+	// Port is the port the javaagent exposes the metrics on. Only the first instrumented container of a pod exposes
+	// them. Defaults to 9464.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// Interval between the scrapes of the instrumented pods. Defaults to the interval of the scraper.
+	// +optional
+	// +kubebuilder:validation:Format:=duration
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Labels are added to the PodMonitor, for the Prometheus or the target allocator selecting it.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // NodeJS defines NodeJS SDK and instrumentation configuration.
@@ -143,11 +237,23 @@ type NodeJS struct {
 	// Resources describes the compute resource requirements.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// Runtime is the runtime identifier of the auto-instrumentation to inject. The
+	// instrumentation.opentelemetry.io/otel-nodejs-auto-runtime annotation takes precedence. When neither is set,
+	// linux-arm64 is used for pods selecting arm64 nodes and linux-x64 otherwise.
+	// +optional
+	// +kubebuilder:validation:Enum=linux-x64;linux-musl-x64;linux-arm64
+	Runtime string `json:"runtime,omitempty"`
+
+	// RuntimeImages overrides Image for the given runtime identifiers, for example with an arm64 build.
+	// +optional
+	RuntimeImages map[string]string `json:"runtimeImages,omitempty"`
 }
 
 // Python defines Python SDK and instrumentation configuration.
 type Python struct {
-	// Image is a container image with Python SDK and auto-instrumentation.
+	// Image is a container image with Python SDK and auto-instrumentation. An image set explicitly pins the version
+	// of the instrumentation, which the operator no longer upgrades along with itself.
 	// +optional
 	Image string `json:"image,omitempty"`
 
@@ -164,6 +270,12 @@ type Python struct {
 	// Resources describes the compute resource requirements.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// ExcludedInstrumentations are the names of the Python instrumentations not to load, such as psycopg2 or
+	// requests, for the libraries whose instrumentation breaks the applications. They are added to the
+	// OTEL_PYTHON_DISABLED_INSTRUMENTATIONS of the instrumented containers.
+	// +optional
+	ExcludedInstrumentations []string `json:"excludedInstrumentations,omitempty"`
 }
 
 // DotNet defines DotNet SDK and instrumentation configuration.
@@ -184,6 +296,17 @@ type DotNet struct {
 	// Resources describes the compute resource requirements.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// Runtime is the runtime identifier of the auto-instrumentation to inject. The
+	// instrumentation.opentelemetry.io/otel-dotnet-auto-runtime annotation takes precedence. When neither is set,
+	// linux-arm64 is used for pods selecting arm64 nodes and linux-x64 otherwise.
+	// +optional
+	// +kubebuilder:validation:Enum=linux-x64;linux-musl-x64;linux-arm64
+	Runtime string `json:"runtime,omitempty"`
+
+	// RuntimeImages overrides Image for the given runtime identifiers, for example with an arm64 build.
+	// +optional
+	RuntimeImages map[string]string `json:"runtimeImages,omitempty"`
 }
 
 type Go struct {
@@ -276,6 +399,33 @@ type Nginx struct {
 
 // InstrumentationStatus defines status of the instrumentation.
 type InstrumentationStatus struct {
+	// Languages counts the pods the pod webhook injected the instrumentation of each language into, or failed to,
+	// since the Instrumentation was created.
+	// +optional
+	// +listType=map
+	// +listMapKey=language
+	Languages []InstrumentationLanguageStatus `json:"languages,omitempty"`
+
+	// LastInjectionTime is the last time the pod webhook injected the instrumentation into a pod.
+	// +optional
+	LastInjectionTime *metav1.Time `json:"lastInjectionTime,omitempty"`
+
+	// LastError is the reason of the last failed injection.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// InstrumentationLanguageStatus counts the injections of the instrumentation of a language.
+type InstrumentationLanguageStatus struct {
+	// Language is the injected language, such as java or python.
+	Language string `json:"language"`
+
+	// InjectedPods is the number of pods the instrumentation of the language was injected into.
+	InjectedPods int64 `json:"injectedPods"`
+
+	// FailedPods is the number of pods the injection of the instrumentation of the language failed for.
+	// +optional
+	FailedPods int64 `json:"failedPods,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -285,6 +435,7 @@ type InstrumentationStatus struct {
 // +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.exporter.endpoint"
 // +kubebuilder:printcolumn:name="Sampler",type="string",JSONPath=".spec.sampler.type"
 // +kubebuilder:printcolumn:name="Sampler Arg",type="string",JSONPath=".spec.sampler.argument"
+// +kubebuilder:printcolumn:name="Last Injection",type="date",JSONPath=".status.lastInjectionTime"
 // +operator-sdk:csv:customresourcedefinitions:displayName="OpenTelemetry Instrumentation"
 // +operator-sdk:csv:customresourcedefinitions:resources={{Pod,v1}}
 
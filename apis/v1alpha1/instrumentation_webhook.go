@@ -6,6 +6,7 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
@@ -23,6 +25,10 @@ import (
 const (
 	envPrefix       = "OTEL_"
 	envSplunkPrefix = "SPLUNK_"
+
+	// the agent the default Instrumentation exports to
+	defaultAgentName      = "cloudwatch-agent"
+	defaultAgentNamespace = "amazon-cloudwatch"
 )
 
 var (
@@ -47,6 +53,8 @@ type InstrumentationWebhook struct {
 	logger logr.Logger
 	cfg    config.Config
 	scheme *runtime.Scheme
+	// reader gets the agent the exporter endpoint is defaulted to, when set.
+	reader client.Reader
 }
 
 func (w InstrumentationWebhook) Default(ctx context.Context, obj runtime.Object) error {
@@ -54,7 +62,68 @@ func (w InstrumentationWebhook) Default(ctx context.Context, obj runtime.Object)
 	if !ok {
 		return fmt.Errorf("expected an Instrumentation, received %T", obj)
 	}
-	return w.defaulter(instrumentation)
+	if err := w.defaulter(instrumentation); err != nil {
+		return err
+	}
+	w.defaultExporter(ctx, instrumentation)
+	return nil
+}
+
+// defaultExporter points an Instrumentation without exporter endpoint at the Application Signals endpoint of an agent
+// enabling Application Signals through its spec: an agent of the namespace of the Instrumentation, otherwise the
+// default agent. The propagators, when unset, default to those of the default Instrumentation.
+func (w InstrumentationWebhook) defaultExporter(ctx context.Context, r *Instrumentation) {
+	if r.Spec.Exporter.Endpoint != "" || w.reader == nil {
+		return
+	}
+	agent := w.exportingAgent(ctx, r.Namespace)
+	if agent == nil {
+		return
+	}
+	r.Spec.Exporter.Endpoint = agentEndpoint(agent)
+	defaults := map[string]string{"exporter.endpoint": r.Spec.Exporter.Endpoint}
+	if len(r.Spec.Propagators) == 0 {
+		r.Spec.Propagators = []Propagator{TraceContext, Baggage, B3, XRay}
+		defaults["propagators"] = "tracecontext,baggage,b3,xray"
+	}
+	RecordDefaultsApplied(r, instrumentationDefaultedFields(r), defaults)
+}
+
+// exportingAgent returns the first agent of the namespace enabling Application Signals, or the default agent when it
+// enables them, nil otherwise.
+func (w InstrumentationWebhook) exportingAgent(ctx context.Context, namespace string) *AmazonCloudWatchAgent {
+	if namespace != "" && namespace != defaultAgentNamespace {
+		agents := &AmazonCloudWatchAgentList{}
+		if err := w.reader.List(ctx, agents, client.InNamespace(namespace)); err != nil {
+			w.logger.V(2).Info("unable to list the agents of the namespace", "namespace", namespace, "err", err)
+		}
+		sort.Slice(agents.Items, func(i, j int) bool {
+			return agents.Items[i].Name < agents.Items[j].Name
+		})
+		for i := range agents.Items {
+			if agents.Items[i].Spec.ApplicationSignals.IsEnabled() {
+				return &agents.Items[i]
+			}
+		}
+	}
+	agent := &AmazonCloudWatchAgent{}
+	if err := w.reader.Get(ctx, client.ObjectKey{Namespace: defaultAgentNamespace, Name: defaultAgentName}, agent); err != nil {
+		w.logger.V(2).Info("not defaulting the exporter endpoint, unable to get the agent", "err", err)
+		return nil
+	}
+	if !agent.Spec.ApplicationSignals.IsEnabled() {
+		return nil
+	}
+	return agent
+}
+
+// agentEndpoint returns the Application Signals endpoint of the agent, on its Service. The pods of a daemonset agent
+// on the host network are pointed at the agent of their own node when they are injected, unless they opt out.
+func agentEndpoint(agent *AmazonCloudWatchAgent) string {
+	if agent.Spec.TLS != nil {
+		return fmt.Sprintf("https://%s:4316", agent.ServiceHost())
+	}
+	return fmt.Sprintf("http://%s:4316", agent.ServiceHost())
 }
 
 func (w InstrumentationWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
@@ -82,6 +151,7 @@ func (w InstrumentationWebhook) ValidateDelete(ctx context.Context, obj runtime.
 }
 
 func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
+	defaults := map[string]string{}
 	if r.Labels == nil {
 		r.Labels = map[string]string{}
 	}
@@ -91,88 +161,65 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 
 	if r.Spec.Java.Image == "" {
 		r.Spec.Java.Image = w.cfg.AutoInstrumentationJavaImage()
+		defaults["java.image"] = r.Spec.Java.Image
 	}
-	if r.Spec.Java.Resources.Limits == nil {
-		r.Spec.Java.Resources.Limits = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("500m"),
-			corev1.ResourceMemory: resource.MustParse("64Mi"),
-		}
-	}
-	if r.Spec.Java.Resources.Requests == nil {
-		r.Spec.Java.Resources.Requests = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("50m"),
-			corev1.ResourceMemory: resource.MustParse("64Mi"),
-		}
-	}
+	defaultResources(&r.Spec.Java.Resources, "java", corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("50m"),
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	}, defaults)
 	if r.Spec.NodeJS.Image == "" {
 		r.Spec.NodeJS.Image = w.cfg.AutoInstrumentationNodeJSImage()
+		defaults["nodejs.image"] = r.Spec.NodeJS.Image
 	}
-	if r.Spec.NodeJS.Resources.Limits == nil {
-		r.Spec.NodeJS.Resources.Limits = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("500m"),
-			corev1.ResourceMemory: resource.MustParse("128Mi"),
-		}
-	}
-	if r.Spec.NodeJS.Resources.Requests == nil {
-		r.Spec.NodeJS.Resources.Requests = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("50m"),
-			corev1.ResourceMemory: resource.MustParse("128Mi"),
-		}
-	}
-	if r.Spec.Python.Image == "" {
+	defaultResources(&r.Spec.NodeJS.Resources, "nodejs", corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("50m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}, defaults)
+	pythonImageDefaulted := r.Spec.Python.Image == ""
+	if pythonImageDefaulted {
 		r.Spec.Python.Image = w.cfg.AutoInstrumentationPythonImage()
+		defaults["python.image"] = r.Spec.Python.Image
 	}
-	if r.Spec.Python.Resources.Limits == nil {
-		r.Spec.Python.Resources.Limits = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("500m"),
-			corev1.ResourceMemory: resource.MustParse("32Mi"),
-		}
-	}
-	if r.Spec.Python.Resources.Requests == nil {
-		r.Spec.Python.Resources.Requests = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("50m"),
-			corev1.ResourceMemory: resource.MustParse("32Mi"),
-		}
-	}
+	defaultResources(&r.Spec.Python.Resources, "python", corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("32Mi"),
+	}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("50m"),
+		corev1.ResourceMemory: resource.MustParse("32Mi"),
+	}, defaults)
 	if r.Spec.DotNet.Image == "" {
 		r.Spec.DotNet.Image = w.cfg.AutoInstrumentationDotNetImage()
+		defaults["dotnet.image"] = r.Spec.DotNet.Image
 	}
-	if r.Spec.DotNet.Resources.Limits == nil {
-		r.Spec.DotNet.Resources.Limits = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("500m"),
-			corev1.ResourceMemory: resource.MustParse("128Mi"),
-		}
-	}
-	if r.Spec.DotNet.Resources.Requests == nil {
-		r.Spec.DotNet.Resources.Requests = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("50m"),
-			corev1.ResourceMemory: resource.MustParse("128Mi"),
-		}
-	}
+	defaultResources(&r.Spec.DotNet.Resources, "dotnet", corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("50m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}, defaults)
 	if r.Spec.Go.Image == "" {
 		r.Spec.Go.Image = w.cfg.AutoInstrumentationGoImage()
+		defaults["go.image"] = r.Spec.Go.Image
 	}
-	if r.Spec.Go.Resources.Limits == nil {
-		r.Spec.Go.Resources.Limits = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("500m"),
-			corev1.ResourceMemory: resource.MustParse("32Mi"),
-		}
-	}
-	if r.Spec.Go.Resources.Requests == nil {
-		r.Spec.Go.Resources.Requests = corev1.ResourceList{
-			corev1.ResourceCPU:    resource.MustParse("50m"),
-			corev1.ResourceMemory: resource.MustParse("32Mi"),
-		}
-	}
+	defaultResources(&r.Spec.Go.Resources, "go", corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("32Mi"),
+	}, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("50m"),
+		corev1.ResourceMemory: resource.MustParse("32Mi"),
+	}, defaults)
 	if r.Spec.ApacheHttpd.Image == "" {
 		r.Spec.ApacheHttpd.Image = w.cfg.AutoInstrumentationApacheHttpdImage()
+		defaults["apache-httpd.image"] = r.Spec.ApacheHttpd.Image
 	}
-	if r.Spec.ApacheHttpd.Resources.Limits == nil {
-		r.Spec.ApacheHttpd.Resources.Limits = initContainerDefaultLimitResources
-	}
-	if r.Spec.ApacheHttpd.Resources.Requests == nil {
-		r.Spec.ApacheHttpd.Resources.Requests = initContainerDefaultRequestedResources
-	}
+	defaultResources(&r.Spec.ApacheHttpd.Resources, "apache-httpd", initContainerDefaultLimitResources, initContainerDefaultRequestedResources, defaults)
 	if r.Spec.ApacheHttpd.Version == "" {
 		r.Spec.ApacheHttpd.Version = "2.4"
 	}
@@ -181,13 +228,9 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 	}
 	if r.Spec.Nginx.Image == "" {
 		r.Spec.Nginx.Image = w.cfg.AutoInstrumentationNginxImage()
+		defaults["nginx.image"] = r.Spec.Nginx.Image
 	}
-	if r.Spec.Nginx.Resources.Limits == nil {
-		r.Spec.Nginx.Resources.Limits = initContainerDefaultLimitResources
-	}
-	if r.Spec.Nginx.Resources.Requests == nil {
-		r.Spec.Nginx.Resources.Requests = initContainerDefaultRequestedResources
-	}
+	defaultResources(&r.Spec.Nginx.Resources, "nginx", initContainerDefaultLimitResources, initContainerDefaultRequestedResources, defaults)
 	if r.Spec.Nginx.ConfigFile == "" {
 		r.Spec.Nginx.ConfigFile = "/etc/nginx/nginx.conf"
 	}
@@ -197,14 +240,75 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 	}
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationJava] = w.cfg.AutoInstrumentationJavaImage()
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationNodeJS] = w.cfg.AutoInstrumentationNodeJSImage()
-	r.Annotations[constants.AnnotationDefaultAutoInstrumentationPython] = w.cfg.AutoInstrumentationPythonImage()
+	// the annotation marks the Python image to upgrade along with the operator, as long as it is the default one,
+	// while an image set explicitly is pinned
+	if pythonImageDefaulted || r.Annotations[constants.AnnotationDefaultAutoInstrumentationPython] == r.Spec.Python.Image {
+		r.Annotations[constants.AnnotationDefaultAutoInstrumentationPython] = r.Spec.Python.Image
+	} else {
+		delete(r.Annotations, constants.AnnotationDefaultAutoInstrumentationPython)
+	}
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationDotNet] = w.cfg.AutoInstrumentationDotNetImage()
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationGo] = w.cfg.AutoInstrumentationGoImage()
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationApacheHttpd] = w.cfg.AutoInstrumentationApacheHttpdImage()
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationNginx] = w.cfg.AutoInstrumentationNginxImage()
+	RecordDefaultsApplied(r, instrumentationDefaultedFields(r), defaults)
 	return nil
 }
 
+// instrumentationDefaultedFields returns the current values of the fields defaulted by the webhook, formatted as
+// their recorded defaults.
+func instrumentationDefaultedFields(r *Instrumentation) map[string]string {
+	propagators := make([]string, len(r.Spec.Propagators))
+	for i, propagator := range r.Spec.Propagators {
+		propagators[i] = string(propagator)
+	}
+	fields := map[string]string{
+		"exporter.endpoint": r.Spec.Exporter.Endpoint,
+		"propagators":       strings.Join(propagators, ","),
+	}
+	for language, spec := range map[string]struct {
+		image     string
+		resources corev1.ResourceRequirements
+	}{
+		"java":         {r.Spec.Java.Image, r.Spec.Java.Resources},
+		"nodejs":       {r.Spec.NodeJS.Image, r.Spec.NodeJS.Resources},
+		"python":       {r.Spec.Python.Image, r.Spec.Python.Resources},
+		"dotnet":       {r.Spec.DotNet.Image, r.Spec.DotNet.Resources},
+		"go":           {r.Spec.Go.Image, r.Spec.Go.Resources},
+		"apache-httpd": {r.Spec.ApacheHttpd.Image, r.Spec.ApacheHttpd.Resources},
+		"nginx":        {r.Spec.Nginx.Image, r.Spec.Nginx.Resources},
+	} {
+		fields[language+".image"] = spec.image
+		fields[language+".resources.limits"] = formatResourceList(spec.resources.Limits)
+		fields[language+".resources.requests"] = formatResourceList(spec.resources.Requests)
+	}
+	return fields
+}
+
+// defaultResources sets the limits and the requests of the init containers of the language left unset to the given
+// defaults. The defaulted limits are raised to the requests, and the defaulted requests capped at the limits, so that
+// the injected pods remain valid when only one of them is set.
+func defaultResources(resources *corev1.ResourceRequirements, language string, limits, requests corev1.ResourceList, defaults map[string]string) {
+	if resources.Limits == nil {
+		resources.Limits = limits.DeepCopy()
+		for name, request := range resources.Requests {
+			if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+				resources.Limits[name] = request.DeepCopy()
+			}
+		}
+		defaults[language+".resources.limits"] = formatResourceList(resources.Limits)
+	}
+	if resources.Requests == nil {
+		resources.Requests = requests.DeepCopy()
+		for name, limit := range resources.Limits {
+			if request, ok := resources.Requests[name]; ok && request.Cmp(limit) > 0 {
+				resources.Requests[name] = limit.DeepCopy()
+			}
+		}
+		defaults[language+".resources.requests"] = formatResourceList(resources.Requests)
+	}
+}
+
 func (w InstrumentationWebhook) validate(r *Instrumentation) (admission.Warnings, error) {
 	var warnings []string
 	switch r.Spec.Sampler.Type {
@@ -242,6 +346,21 @@ func (w InstrumentationWebhook) validate(r *Instrumentation) (admission.Warnings
 	if err := w.validateEnv(r.Spec.Java.Env); err != nil {
 		return warnings, err
 	}
+	if err := validateJava(r.Spec.Java); err != nil {
+		return warnings, err
+	}
+	if err := validatePython(r.Spec.Python); err != nil {
+		return warnings, err
+	}
+	if err := validateResources(r.Spec); err != nil {
+		return warnings, err
+	}
+	if r.Spec.Batch.ExportInterval != nil && r.Spec.Batch.ExportInterval.Duration <= 0 {
+		return warnings, fmt.Errorf("spec.batch.exportInterval must be positive: %s", r.Spec.Batch.ExportInterval.Duration)
+	}
+	if r.Spec.Batch.ShutdownDelay != nil && r.Spec.Batch.ShutdownDelay.Duration < 0 {
+		return warnings, fmt.Errorf("spec.batch.shutdownDelay can't be negative: %s", r.Spec.Batch.ShutdownDelay.Duration)
+	}
 	if err := w.validateEnv(r.Spec.NodeJS.Env); err != nil {
 		return warnings, err
 	}
@@ -264,10 +383,97 @@ func (w InstrumentationWebhook) validate(r *Instrumentation) (admission.Warnings
 }
 
 func (w InstrumentationWebhook) validateEnv(envs []corev1.EnvVar) error {
+	names := map[string]bool{}
 	for _, env := range envs {
 		if !strings.HasPrefix(env.Name, envPrefix) && !strings.HasPrefix(env.Name, envSplunkPrefix) {
 			return fmt.Errorf("env name should start with \"OTEL_\" or \"SPLUNK_\": %s", env.Name)
 		}
+		// a duplicated name would be injected once, with a value depending on the order of the list
+		if names[env.Name] {
+			return fmt.Errorf("env %s is defined more than once", env.Name)
+		}
+		names[env.Name] = true
+		if env.ValueFrom == nil {
+			continue
+		}
+		if env.Value != "" {
+			return fmt.Errorf("env %s can't have both a value and a valueFrom", env.Name)
+		}
+		sources := 0
+		for _, set := range []bool{env.ValueFrom.FieldRef != nil, env.ValueFrom.ResourceFieldRef != nil, env.ValueFrom.ConfigMapKeyRef != nil, env.ValueFrom.SecretKeyRef != nil} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("env %s must have exactly one of fieldRef, resourceFieldRef, configMapKeyRef and secretKeyRef in valueFrom", env.Name)
+		}
+	}
+	return nil
+}
+
+// validateJava checks the extensions and the JVM arguments added to the JAVA_TOOL_OPTIONS of the instrumented
+// containers.
+func validateJava(java Java) error {
+	for i, extension := range java.Extensions {
+		if (extension.Image == "") == (extension.ConfigMap == "") {
+			return fmt.Errorf("spec.java.extensions[%d] must have exactly one of image and configMap", i)
+		}
+		if extension.Image != "" && extension.Dir == "" {
+			return fmt.Errorf("spec.java.extensions[%d].dir is required with an image", i)
+		}
+	}
+	for i, arg := range java.JVMArgs {
+		if !strings.HasPrefix(arg, "-") || strings.ContainsAny(arg, " \t\n") {
+			return fmt.Errorf("spec.java.jvmArgs[%d] must be a single argument starting with \"-\": %q", i, arg)
+		}
+		if len(java.Extensions) > 0 && strings.HasPrefix(arg, "-Dotel.javaagent.extensions=") {
+			return fmt.Errorf("spec.java.jvmArgs[%d] can't set the extensions of the javaagent with spec.java.extensions", i)
+		}
+	}
+	return nil
+}
+
+// validateResources checks that the requests of the init containers of each language don't exceed their limits,
+// which would make the API server reject the injected pods.
+func validateResources(spec InstrumentationSpec) error {
+	for _, language := range []struct {
+		path      string
+		resources corev1.ResourceRequirements
+	}{
+		{"spec.java.resources", spec.Java.Resources},
+		{"spec.nodejs.resourceRequirements", spec.NodeJS.Resources},
+		{"spec.python.resourceRequirements", spec.Python.Resources},
+		{"spec.dotnet.resourceRequirements", spec.DotNet.Resources},
+		{"spec.go.resourceRequirements", spec.Go.Resources},
+		{"spec.apacheHttpd.resourceRequirements", spec.ApacheHttpd.Resources},
+		{"spec.nginx.resourceRequirements", spec.Nginx.Resources},
+	} {
+		names := make([]string, 0, len(language.resources.Requests))
+		for name := range language.resources.Requests {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			request := language.resources.Requests[corev1.ResourceName(name)]
+			if limit, ok := language.resources.Limits[corev1.ResourceName(name)]; ok && request.Cmp(limit) > 0 {
+				return fmt.Errorf("%s.requests.%s can't exceed the limit: %s > %s", language.path, name, request.String(), limit.String())
+			}
+		}
+	}
+	return nil
+}
+
+func validatePython(python Python) error {
+	excluded := map[string]bool{}
+	for i, name := range python.ExcludedInstrumentations {
+		if name == "" || strings.ContainsAny(name, ", \t\n") {
+			return fmt.Errorf("spec.python.excludedInstrumentations[%d] must be the name of an instrumentation: %q", i, name)
+		}
+		if excluded[name] {
+			return fmt.Errorf("spec.python.excludedInstrumentations contains %s more than once", name)
+		}
+		excluded[name] = true
 	}
 	return nil
 }
@@ -317,6 +523,7 @@ func SetupInstrumentationWebhook(mgr ctrl.Manager, cfg config.Config) error {
 		mgr.GetScheme(),
 		cfg,
 	)
+	ivw.reader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&Instrumentation{}).
 		WithValidator(ivw).
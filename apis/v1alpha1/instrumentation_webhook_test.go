@@ -8,9 +8,17 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
 func TestInstrumentationDefaultingWebhook(t *testing.T) {
@@ -32,6 +40,133 @@ func TestInstrumentationDefaultingWebhook(t *testing.T) {
 	assert.Equal(t, "dotnet-img:1", inst.Spec.DotNet.Image)
 	assert.Equal(t, "apache-httpd-img:1", inst.Spec.ApacheHttpd.Image)
 	assert.Equal(t, "nginx-img:1", inst.Spec.Nginx.Image)
+
+	defaults := DefaultsApplied(inst)
+	assert.Equal(t, "java-img:1", defaults["java.image"])
+	assert.Equal(t, "cpu=500m,memory=64Mi", defaults["java.resources.limits"])
+	assert.Equal(t, "cpu=50m,memory=64Mi", defaults["java.resources.requests"])
+}
+
+func TestInstrumentationDefaultingPythonImage(t *testing.T) {
+	webhook := InstrumentationWebhook{cfg: config.New(config.WithAutoInstrumentationPythonImage("python-img:2"))}
+	for _, tt := range []struct {
+		name        string
+		image       string
+		annotation  string
+		expected    string
+		upgradeable bool
+	}{
+		{name: "defaulted", expected: "python-img:2", upgradeable: true},
+		{name: "defaulted before", image: "python-img:1", annotation: "python-img:1", expected: "python-img:1", upgradeable: true},
+		{name: "pinned", image: "python-img:1", expected: "python-img:1"},
+		{name: "pinned to the default image", image: "python-img:2", expected: "python-img:2"},
+		{name: "pinned after being defaulted", image: "python-img:1", annotation: "python-img:2", expected: "python-img:1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := &Instrumentation{Spec: InstrumentationSpec{Python: Python{Image: tt.image}}}
+			if tt.annotation != "" {
+				inst.Annotations = map[string]string{constants.AnnotationDefaultAutoInstrumentationPython: tt.annotation}
+			}
+			require.NoError(t, webhook.Default(context.Background(), inst))
+			assert.Equal(t, tt.expected, inst.Spec.Python.Image)
+			annotation, ok := inst.Annotations[constants.AnnotationDefaultAutoInstrumentationPython]
+			assert.Equal(t, tt.upgradeable, ok)
+			if ok {
+				assert.Equal(t, tt.expected, annotation)
+			}
+		})
+	}
+}
+
+func TestInstrumentationDefaultingResources(t *testing.T) {
+	inst := &Instrumentation{Spec: InstrumentationSpec{
+		// the default requests are capped at lower limits
+		Python: Python{Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Mi")},
+		}},
+		// the default limits are raised to higher requests
+		DotNet: DotNet{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		}},
+	}}
+	require.NoError(t, InstrumentationWebhook{cfg: config.New()}.Default(context.Background(), inst))
+
+	assert.Equal(t, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Mi")}, inst.Spec.Python.Resources.Limits)
+	assert.Equal(t, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("50m"),
+		corev1.ResourceMemory: resource.MustParse("16Mi"),
+	}, inst.Spec.Python.Resources.Requests)
+	assert.Equal(t, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}, inst.Spec.DotNet.Resources.Limits)
+	assert.Equal(t, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")}, inst.Spec.DotNet.Resources.Requests)
+	assert.Equal(t, "cpu=50m,memory=16Mi", DefaultsApplied(inst)["python.resources.requests"])
+
+	// the shared defaults of the init containers are left untouched
+	inst = &Instrumentation{Spec: InstrumentationSpec{Nginx: Nginx{Resources: corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}}}}
+	require.NoError(t, InstrumentationWebhook{cfg: config.New()}.Default(context.Background(), inst))
+	assert.Equal(t, resource.MustParse("64Mi"), inst.Spec.Nginx.Resources.Requests[corev1.ResourceMemory])
+	assert.Equal(t, resource.MustParse("128Mi"), initContainerDefaultRequestedResources[corev1.ResourceMemory])
+}
+
+func TestInstrumentationDefaultingExporter(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, AddToScheme(s))
+	agent := func(spec AmazonCloudWatchAgentSpec) *AmazonCloudWatchAgent {
+		return &AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"}, Spec: spec}
+	}
+	teamAgent := func(name string, spec AmazonCloudWatchAgentSpec) *AmazonCloudWatchAgent {
+		return &AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"}, Spec: spec}
+	}
+	enabled := &ApplicationSignalsSpec{Enabled: true}
+	for _, tt := range []struct {
+		name     string
+		agents   []client.Object
+		endpoint string
+		expected string
+	}{
+		{name: "without agent"},
+		{name: "without Application Signals", agents: []client.Object{agent(AmazonCloudWatchAgentSpec{})}},
+		{name: "with Application Signals", agents: []client.Object{agent(AmazonCloudWatchAgentSpec{ApplicationSignals: enabled})}, expected: "http://cloudwatch-agent.amazon-cloudwatch:4316"},
+		{name: "with TLS", agents: []client.Object{agent(AmazonCloudWatchAgentSpec{ApplicationSignals: enabled, TLS: &TLSSpec{SecretName: "tls"}})}, expected: "https://cloudwatch-agent.amazon-cloudwatch:4316"},
+		{name: "with endpoint", agents: []client.Object{agent(AmazonCloudWatchAgentSpec{ApplicationSignals: enabled})}, endpoint: "http://collector:4318", expected: "http://collector:4318"},
+		{name: "with daemonset on the host network", agents: []client.Object{agent(AmazonCloudWatchAgentSpec{ApplicationSignals: enabled, Mode: ModeDaemonSet, HostNetwork: true})}, expected: "http://cloudwatch-agent.amazon-cloudwatch:4316"},
+		{
+			name: "with agent in the namespace",
+			agents: []client.Object{
+				agent(AmazonCloudWatchAgentSpec{ApplicationSignals: enabled}),
+				teamAgent("a-agent", AmazonCloudWatchAgentSpec{}),
+				teamAgent("b-agent", AmazonCloudWatchAgentSpec{ApplicationSignals: enabled}),
+			},
+			expected: "http://b-agent.team:4316",
+		},
+		{
+			name: "without Application Signals in the namespace",
+			agents: []client.Object{
+				agent(AmazonCloudWatchAgentSpec{ApplicationSignals: enabled}),
+				teamAgent("agent", AmazonCloudWatchAgentSpec{}),
+			},
+			expected: "http://cloudwatch-agent.amazon-cloudwatch:4316",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := fake.NewClientBuilder().WithScheme(s).WithObjects(tt.agents...).Build()
+			inst := &Instrumentation{
+				ObjectMeta: metav1.ObjectMeta{Name: "instrumentation", Namespace: "team"},
+				Spec:       InstrumentationSpec{Exporter: Exporter{Endpoint: tt.endpoint}},
+			}
+			err := InstrumentationWebhook{cfg: config.New(), reader: reader}.Default(context.Background(), inst)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, inst.Spec.Exporter.Endpoint)
+			if tt.endpoint == "" && tt.expected != "" {
+				assert.Equal(t, tt.expected, DefaultsApplied(inst)["exporter.endpoint"])
+				assert.Equal(t, []Propagator{TraceContext, Baggage, B3, XRay}, inst.Spec.Propagators)
+			}
+		})
+	}
 }
 
 func TestInstrumentationValidatingWebhook(t *testing.T) {
@@ -102,6 +237,168 @@ func TestInstrumentationValidatingWebhook(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "env from secret",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Env: []corev1.EnvVar{{
+						Name: "OTEL_EXPORTER_OTLP_HEADERS",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "otlp"}, Key: "headers"},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name: "duplicated env",
+			err:  "env OTEL_SERVICE_NAME is defined more than once",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Python: Python{
+						Env: []corev1.EnvVar{{Name: "OTEL_SERVICE_NAME", Value: "a"}, {Name: "OTEL_SERVICE_NAME", Value: "b"}},
+					},
+				},
+			},
+		},
+		{
+			name: "env with value and valueFrom",
+			err:  "env OTEL_SERVICE_NAME can't have both a value and a valueFrom",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Env: []corev1.EnvVar{{
+						Name:      "OTEL_SERVICE_NAME",
+						Value:     "a",
+						ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+					}},
+				},
+			},
+		},
+		{
+			name: "env with several sources",
+			err:  "env OTEL_SERVICE_NAME must have exactly one of fieldRef, resourceFieldRef, configMapKeyRef and secretKeyRef",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Env: []corev1.EnvVar{{
+						Name: "OTEL_SERVICE_NAME",
+						ValueFrom: &corev1.EnvVarSource{
+							FieldRef:     &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+							SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "otlp"}, Key: "name"},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name: "java extension with image and configMap",
+			err:  "spec.java.extensions[0] must have exactly one of image and configMap",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Java: Java{
+						Extensions: []JavaExtension{{Image: "extension:1.0", Dir: "/extensions", ConfigMap: "extension"}},
+					},
+				},
+			},
+		},
+		{
+			name: "java extension image without dir",
+			err:  "spec.java.extensions[0].dir is required with an image",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Java: Java{
+						Extensions: []JavaExtension{{Image: "extension:1.0"}},
+					},
+				},
+			},
+		},
+		{
+			name: "requests exceeding the limits",
+			err:  "spec.nodejs.resourceRequirements.requests.memory can't exceed the limit: 256Mi > 128Mi",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					NodeJS: NodeJS{
+						Resources: corev1.ResourceRequirements{
+							Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+							Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "java jvmArgs with several arguments",
+			err:  "spec.java.jvmArgs[1] must be a single argument",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Java: Java{
+						JVMArgs: []string{"-Xmx512m", "-Dfoo=bar -Dbar=foo"},
+					},
+				},
+			},
+		},
+		{
+			name: "java jvmArgs setting the extensions",
+			err:  "spec.java.jvmArgs[0] can't set the extensions of the javaagent",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Java: Java{
+						Extensions: []JavaExtension{{ConfigMap: "extension"}},
+						JVMArgs:    []string{"-Dotel.javaagent.extensions=/extensions"},
+					},
+				},
+			},
+		},
+		{
+			name: "python excludedInstrumentations as a list",
+			err:  "spec.python.excludedInstrumentations[0] must be the name of an instrumentation",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Python:  Python{ExcludedInstrumentations: []string{"psycopg2,requests"}},
+				},
+			},
+		},
+		{
+			name: "python excludedInstrumentations repeated",
+			err:  "spec.python.excludedInstrumentations contains psycopg2 more than once",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Python:  Python{ExcludedInstrumentations: []string{"psycopg2", "requests", "psycopg2"}},
+				},
+			},
+		},
+		{
+			name: "batch export interval of 0",
+			err:  "spec.batch.exportInterval must be positive: 0s",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Batch:   Batch{ExportInterval: &metav1.Duration{}},
+				},
+			},
+		},
+		{
+			name: "java extensions and jvmArgs",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Java: Java{
+						Extensions: []JavaExtension{{Image: "extension:1.0", Dir: "/extensions"}, {ConfigMap: "extension"}},
+						JVMArgs:    []string{"-Xmx512m", "-XX:+UseG1GC"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
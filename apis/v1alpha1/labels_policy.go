@@ -0,0 +1,20 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+type (
+	// LabelsPolicy represents the set of labels put on the agent pods.
+	// +kubebuilder:validation:Enum=Standard;Legacy
+	LabelsPolicy string
+)
+
+const (
+	// LabelsPolicyStandard specifies that the agent pods only carry the app.kubernetes.io labels.
+	LabelsPolicyStandard LabelsPolicy = "Standard"
+
+	// LabelsPolicyLegacy specifies that the agent pods also carry the name label of the manifests the agent was
+	// deployed with before the operator, so that the queries and dashboards selecting the agent pods by it keep
+	// working.
+	LabelsPolicyLegacy LabelsPolicy = "Legacy"
+)
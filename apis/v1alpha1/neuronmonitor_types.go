@@ -49,8 +49,10 @@ type NeuronMonitorSpec struct {
 	// Image indicates the container image to use for the Neuron Monitor Exporter.
 	// +optional
 	Image string `json:"image,omitempty"`
-	// MonitorConfig is the raw Json to be used as monitor configuration.
-	// +required
+	// MonitorConfig is the raw Json to be used as monitor configuration, such as the period and the metric groups
+	// collected. It is mounted into the exporter, whose pods are rolled out when it changes. Defaults to the metrics
+	// of the enhanced Container Insights, collected every 5 seconds.
+	// +optional
 	MonitorConfig string `json:"monitorConfig,omitempty"`
 	// Ports allows a set of ports to be exposed by the underlying v1.Service. By default, the operator
 	// will attempt to infer the required ports by parsing the .Spec.Config property but this property can be
@@ -58,10 +60,23 @@ type NeuronMonitorSpec struct {
 	// +optional
 	// +listType=atomic
 	Ports []v1.ServicePort `json:"ports,omitempty"`
+	// IPFamilies are the IP families of the Service of the Neuron Monitor Exporter, for instance [IPv6] on
+	// IPv6-only clusters or [IPv4, IPv6] on dual-stack clusters. The cluster default applies when unset.
+	// +optional
+	// +listType=atomic
+	IPFamilies []v1.IPFamily `json:"ipFamilies,omitempty"`
+	// IPFamilyPolicy tells whether the Service of the Neuron Monitor Exporter is single-stack or dual-stack.
+	// +optional
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	IPFamilyPolicy *v1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
 	// ENV vars to set on the Neuron Monitor Exporter Pods. These can then in certain cases be
 	// consumed in the config file for the Collector.
 	// +optional
 	Env []v1.EnvVar `json:"env,omitempty"`
+	// List of sources to populate environment variables on the Neuron Monitor Exporter Pods.
+	// Values defined by Env with a duplicate key take precedence.
+	// +optional
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
 	// Toleration to schedule Neuron Monitor Exporter pods.
 	// This is only relevant to daemonset, statefulset, and deployment mode
 	// +optional
@@ -107,7 +122,7 @@ type NeuronMonitorStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
-// +kubebuilder:resource:shortName=neuronexp;neuronexps
+// +kubebuilder:resource:shortName=neuronexp;neuronexps;neuron
 // +kubebuilder:subresource:status
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Neuron Monitor exporter Version"
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// PrometheusReloadEnabled tells whether the Prometheus configuration of the agent is reloaded by a sidecar.
+func (a AmazonCloudWatchAgent) PrometheusReloadEnabled() bool {
+	return a.Spec.PrometheusReload != nil && !a.Spec.Prometheus.IsEmpty() &&
+		a.Spec.NodeSelector["kubernetes.io/os"] != "windows"
+}
+
+// HotReloadEnabled tells whether the changes of the hot-reloadable sections of the agent config are applied by the
+// config reloader sidecar instead of rolling out the pods.
+func (a AmazonCloudWatchAgent) HotReloadEnabled() bool {
+	return a.Spec.ConfigReload == ConfigReloadHotReload && a.Spec.Mode != ModeSidecar &&
+		a.Spec.NodeSelector["kubernetes.io/os"] != "windows"
+}
+
+// SharedProcessNamespace returns the ShareProcessNamespace of the agent pods, always enabled when a reloader
+// sidecar needs to signal the agent process.
+func (a AmazonCloudWatchAgent) SharedProcessNamespace() *bool {
+	if !a.PrometheusReloadEnabled() && !a.HotReloadEnabled() {
+		return a.Spec.ShareProcessNamespace
+	}
+	share := true
+	return &share
+}
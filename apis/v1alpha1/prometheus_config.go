@@ -72,14 +72,24 @@ type PrometheusConfig struct {
 	ReportExtraScrapeMetrics bool `json:"report_extra_scrape_metrics,omitempty" yaml:"report_extra_scrape_metrics,omitempty"`
 	// +kubebuilder:pruning:PreserveUnknownFields
 	TargetAllocator *AnyConfig `json:"target_allocator,omitempty" yaml:"target_allocator,omitempty"`
+	// ExtraScrapeConfigs are scrape configs appended to the scrape_configs of Config, which adds jobs without
+	// writing the whole Prometheus configuration. Their relabel configs are validated.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraScrapeConfigs []AnyConfig `json:"extraScrapeConfigs,omitempty" yaml:"-"`
+	// GlobalScrapeInterval sets the scrape_interval of the global section of Config, such as 30s.
+	// +optional
+	GlobalScrapeInterval string `json:"globalScrapeInterval,omitempty" yaml:"-"`
 }
 
-// Yaml encodes the current object and returns it as a string.
+// Yaml encodes the current object, with the ExtraScrapeConfigs and the GlobalScrapeInterval merged into its Config,
+// and returns it as a string.
 func (pc *PrometheusConfig) Yaml() (string, error) {
 	var buf bytes.Buffer
 	yamlEncoder := yaml.NewEncoder(&buf)
 	yamlEncoder.SetIndent(2)
-	if err := yamlEncoder.Encode(&pc); err != nil {
+	merged := pc.merged()
+	if err := yamlEncoder.Encode(&merged); err != nil {
 		return "", err
 	}
 	return buf.String(), nil
@@ -92,5 +102,42 @@ func (pc *PrometheusConfig) IsEmpty() bool {
 		!pc.UseStartTimeMetric &&
 		pc.StartTimeMetricRegex == "" &&
 		!pc.ReportExtraScrapeMetrics &&
-		pc.TargetAllocator == nil
+		pc.TargetAllocator == nil &&
+		len(pc.ExtraScrapeConfigs) == 0 &&
+		pc.GlobalScrapeInterval == ""
+}
+
+// merged returns a copy of the config with the ExtraScrapeConfigs and the GlobalScrapeInterval merged into its
+// Config, leaving the receiver untouched.
+func (pc *PrometheusConfig) merged() *PrometheusConfig {
+	if len(pc.ExtraScrapeConfigs) == 0 && pc.GlobalScrapeInterval == "" {
+		return pc
+	}
+	out := *pc
+	out.Config = pc.Config.DeepCopy()
+	if out.Config == nil {
+		out.Config = &AnyConfig{}
+	}
+	if out.Config.Object == nil {
+		out.Config.Object = map[string]interface{}{}
+	}
+	if pc.GlobalScrapeInterval != "" {
+		global := map[string]interface{}{}
+		if g, ok := out.Config.Object["global"].(map[string]interface{}); ok {
+			for k, v := range g {
+				global[k] = v
+			}
+		}
+		global["scrape_interval"] = pc.GlobalScrapeInterval
+		out.Config.Object["global"] = global
+	}
+	if len(pc.ExtraScrapeConfigs) > 0 {
+		existing, _ := out.Config.Object["scrape_configs"].([]interface{})
+		scrapeConfigs := append([]interface{}{}, existing...)
+		for _, scrapeConfig := range pc.ExtraScrapeConfigs {
+			scrapeConfigs = append(scrapeConfigs, scrapeConfig.Object)
+		}
+		out.Config.Object["scrape_configs"] = scrapeConfigs
+	}
+	return &out
 }
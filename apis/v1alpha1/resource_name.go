@@ -0,0 +1,14 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// ResourceName returns the base of the names of the objects the operator creates for the AmazonCloudWatchAgent: its
+// NameOverride, or else its name, after the resource name prefix of the operator.
+func (a AmazonCloudWatchAgent) ResourceName() string {
+	return naming.Instance(a.Name, a.Spec.NameOverride)
+}
@@ -7,6 +7,8 @@
 package v1alpha1
 
 import (
+	"encoding/json"
+
 	v2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
@@ -15,6 +17,47 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSEndpointOverridesSpec) DeepCopyInto(out *AWSEndpointOverridesSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSEndpointOverridesSpec.
+func (in *AWSEndpointOverridesSpec) DeepCopy() *AWSEndpointOverridesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSEndpointOverridesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlarmsSpec) DeepCopyInto(out *AlarmsSpec) {
+	*out = *in
+	if in.ActionARNs != nil {
+		in, out := &in.ActionARNs, &out.ActionARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Heartbeat.DeepCopyInto(&out.Heartbeat)
+	if in.ExportErrors != nil {
+		in, out := &in.ExportErrors, &out.ExportErrors
+		*out = new(ExportErrorsAlarmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlarmsSpec.
+func (in *AlarmsSpec) DeepCopy() *AlarmsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlarmsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AmazonCloudWatchAgent) DeepCopyInto(out *AmazonCloudWatchAgent) {
 	*out = *in
@@ -42,6 +85,84 @@ func (in *AmazonCloudWatchAgent) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmazonCloudWatchAgentConfigOverride) DeepCopyInto(out *AmazonCloudWatchAgentConfigOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentConfigOverride.
+func (in *AmazonCloudWatchAgentConfigOverride) DeepCopy() *AmazonCloudWatchAgentConfigOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(AmazonCloudWatchAgentConfigOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AmazonCloudWatchAgentConfigOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmazonCloudWatchAgentConfigOverrideList) DeepCopyInto(out *AmazonCloudWatchAgentConfigOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AmazonCloudWatchAgentConfigOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentConfigOverrideList.
+func (in *AmazonCloudWatchAgentConfigOverrideList) DeepCopy() *AmazonCloudWatchAgentConfigOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(AmazonCloudWatchAgentConfigOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AmazonCloudWatchAgentConfigOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmazonCloudWatchAgentConfigOverrideSpec) DeepCopyInto(out *AmazonCloudWatchAgentConfigOverrideSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentConfigOverrideSpec.
+func (in *AmazonCloudWatchAgentConfigOverrideSpec) DeepCopy() *AmazonCloudWatchAgentConfigOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AmazonCloudWatchAgentConfigOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AmazonCloudWatchAgentList) DeepCopyInto(out *AmazonCloudWatchAgentList) {
 	*out = *in
@@ -78,6 +199,11 @@ func (in *AmazonCloudWatchAgentList) DeepCopyObject() runtime.Object {
 func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec) {
 	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.GoRuntime != nil {
+		in, out := &in.GoRuntime, &out.GoRuntime
+		*out = new(GoRuntimeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -85,9 +211,19 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 			(*out)[key] = val
 		}
 	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Args != nil {
 		in, out := &in.Args, &out.Args
-		*out = make(map[string]string, len(*in))
+		*out = new(Args)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -127,6 +263,11 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 		*out = new(corev1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfilesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodAnnotations != nil {
 		in, out := &in.PodAnnotations, &out.PodAnnotations
 		*out = make(map[string]string, len(*in))
@@ -135,7 +276,24 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 		}
 	}
 	in.TargetAllocator.DeepCopyInto(&out.TargetAllocator)
+	if in.ServiceAccountAnnotations != nil {
+		in, out := &in.ServiceAccountAnnotations, &out.ServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	if in.PrometheusReload != nil {
+		in, out := &in.PrometheusReload, &out.PrometheusReload
+		*out = new(PrometheusReloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneMetrics != nil {
+		in, out := &in.ControlPlaneMetrics, &out.ControlPlaneMetrics
+		*out = new(ControlPlaneMetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
 		*out = make([]corev1.VolumeMount, len(*in))
@@ -150,6 +308,23 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(corev1.IPFamilyPolicy)
+		**out = **in
+	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
 		*out = make([]corev1.EnvVar, len(*in))
@@ -186,6 +361,11 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 		}
 	}
 	in.Ingress.DeepCopyInto(&out.Ingress)
+	if in.ShareProcessNamespace != nil {
+		in, out := &in.ShareProcessNamespace, &out.ShareProcessNamespace
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
 		*out = new(corev1.Affinity)
@@ -206,6 +386,11 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 		*out = new(Probe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ContainerOverride != nil {
+		in, out := &in.ContainerOverride, &out.ContainerOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
 		*out = make([]corev1.Container, len(*in))
@@ -220,7 +405,7 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.Observability = in.Observability
+	in.Observability.DeepCopyInto(&out.Observability)
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
 		*out = make([]corev1.TopologySpreadConstraint, len(*in))
@@ -233,7 +418,102 @@ func (in *AmazonCloudWatchAgentSpec) DeepCopyInto(out *AmazonCloudWatchAgentSpec
 		*out = make([]ConfigMapsSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExtraMounts != nil {
+		in, out := &in.ExtraMounts, &out.ExtraMounts
+		*out = make([]ExtraMountSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
+	in.Buffer.DeepCopyInto(&out.Buffer)
+	in.LogVolumes.DeepCopyInto(&out.LogVolumes)
+	out.Proxy = in.Proxy
+	if in.TrustedCA != nil {
+		in, out := &in.TrustedCA, &out.TrustedCA
+		*out = new(TrustedCASpec)
+		**out = **in
+	}
+	out.AWSEndpointOverrides = in.AWSEndpointOverrides
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(PersistenceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.OTLPReceiver.DeepCopyInto(&out.OTLPReceiver)
+	in.InjectedEnvPolicy.DeepCopyInto(&out.InjectedEnvPolicy)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.XRay != nil {
+		in, out := &in.XRay, &out.XRay
+		*out = new(XRaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alarms != nil {
+		in, out := &in.Alarms, &out.Alarms
+		*out = new(AlarmsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VersionSplit != nil {
+		in, out := &in.VersionSplit, &out.VersionSplit
+		*out = new(VersionSplitSpec)
+		**out = **in
+	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]NodeGroupSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeConfigOverrides != nil {
+		in, out := &in.NodeConfigOverrides, &out.NodeConfigOverrides
+		*out = make([]NodeConfigOverrideSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PipelineSplit != nil {
+		in, out := &in.PipelineSplit, &out.PipelineSplit
+		*out = new(PipelineSplitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(LogsSpec)
+		**out = **in
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]DestinationSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeTermination != nil {
+		in, out := &in.NodeTermination, &out.NodeTermination
+		*out = new(NodeTerminationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(DiagnosticsSpec)
+		**out = **in
+	}
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(DebugSpec)
+		**out = **in
+	}
+	if in.ApplicationSignals != nil {
+		in, out := &in.ApplicationSignals, &out.ApplicationSignals
+		*out = new(ApplicationSignalsSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentSpec.
@@ -255,6 +535,38 @@ func (in *AmazonCloudWatchAgentStatus) DeepCopyInto(out *AmazonCloudWatchAgentSt
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VersionSplit != nil {
+		in, out := &in.VersionSplit, &out.VersionSplit
+		*out = new(VersionSplitStatus)
+		**out = **in
+	}
+	if in.CanaryRollout != nil {
+		in, out := &in.CanaryRollout, &out.CanaryRollout
+		*out = new(CanaryRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]WorkloadStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigHashes != nil {
+		in, out := &in.ConfigHashes, &out.ConfigHashes
+		*out = make([]ConfigHashStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Restart != nil {
+		in, out := &in.Restart, &out.Restart
+		*out = new(RestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentStatus.
@@ -361,6 +673,94 @@ func (in *AmazonCloudWatchAgentTargetAllocatorPrometheusCR) DeepCopy() *AmazonCl
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmazonCloudWatchAgentTemplate) DeepCopyInto(out *AmazonCloudWatchAgentTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentTemplate.
+func (in *AmazonCloudWatchAgentTemplate) DeepCopy() *AmazonCloudWatchAgentTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(AmazonCloudWatchAgentTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AmazonCloudWatchAgentTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmazonCloudWatchAgentTemplateList) DeepCopyInto(out *AmazonCloudWatchAgentTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AmazonCloudWatchAgentTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentTemplateList.
+func (in *AmazonCloudWatchAgentTemplateList) DeepCopy() *AmazonCloudWatchAgentTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(AmazonCloudWatchAgentTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AmazonCloudWatchAgentTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmazonCloudWatchAgentTemplateSpec) DeepCopyInto(out *AmazonCloudWatchAgentTemplateSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Agent.DeepCopyInto(&out.Agent)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentTemplateSpec.
+func (in *AmazonCloudWatchAgentTemplateSpec) DeepCopy() *AmazonCloudWatchAgentTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AmazonCloudWatchAgentTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApacheHttpd) DeepCopyInto(out *ApacheHttpd) {
 	*out = *in
@@ -397,14 +797,61 @@ func (in *ApacheHttpd) DeepCopy() *ApacheHttpd {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AutoscalerSpec) DeepCopyInto(out *AutoscalerSpec) {
+func (in *ApplicationSignalsSpec) DeepCopyInto(out *ApplicationSignalsSpec) {
 	*out = *in
-	if in.MinReplicas != nil {
-		in, out := &in.MinReplicas, &out.MinReplicas
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSignalsSpec.
+func (in *ApplicationSignalsSpec) DeepCopy() *ApplicationSignalsSpec {
+	if in == nil {
+		return nil
 	}
-	if in.MaxReplicas != nil {
+	out := new(ApplicationSignalsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Args) DeepCopyInto(out *Args) {
+	*out = *in
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.List != nil {
+		in, out := &in.List, &out.List
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.invalid != nil {
+		in, out := &in.invalid, &out.invalid
+		*out = make(json.RawMessage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Args.
+func (in *Args) DeepCopy() *Args {
+	if in == nil {
+		return nil
+	}
+	out := new(Args)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalerSpec) DeepCopyInto(out *AutoscalerSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
 		in, out := &in.MaxReplicas, &out.MaxReplicas
 		*out = new(int32)
 		**out = **in
@@ -431,6 +878,11 @@ func (in *AutoscalerSpec) DeepCopyInto(out *AutoscalerSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Vertical != nil {
+		in, out := &in.Vertical, &out.Vertical
+		*out = new(VerticalAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerSpec.
@@ -443,6 +895,223 @@ func (in *AutoscalerSpec) DeepCopy() *AutoscalerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Batch) DeepCopyInto(out *Batch) {
+	*out = *in
+	if in.ExportInterval != nil {
+		in, out := &in.ExportInterval, &out.ExportInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ShutdownDelay != nil {
+		in, out := &in.ShutdownDelay, &out.ShutdownDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SkipKinds != nil {
+		in, out := &in.SkipKinds, &out.SkipKinds
+		*out = make([]BatchWorkloadKind, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Batch.
+func (in *Batch) DeepCopy() *Batch {
+	if in == nil {
+		return nil
+	}
+	out := new(Batch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BufferSpec) DeepCopyInto(out *BufferSpec) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BufferSpec.
+func (in *BufferSpec) DeepCopy() *BufferSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BufferSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BufferStorageSpec) DeepCopyInto(out *BufferStorageSpec) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BufferStorageSpec.
+func (in *BufferStorageSpec) DeepCopy() *BufferStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BufferStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryExportTotals) DeepCopyInto(out *CanaryExportTotals) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryExportTotals.
+func (in *CanaryExportTotals) DeepCopy() *CanaryExportTotals {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryExportTotals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRolloutSpec) DeepCopyInto(out *CanaryRolloutSpec) {
+	*out = *in
+	if in.BakeDuration != nil {
+		in, out := &in.BakeDuration, &out.BakeDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxExportFailurePercentage != nil {
+		in, out := &in.MaxExportFailurePercentage, &out.MaxExportFailurePercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRolloutSpec.
+func (in *CanaryRolloutSpec) DeepCopy() *CanaryRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRolloutStatus) DeepCopyInto(out *CanaryRolloutStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ReadySince != nil {
+		in, out := &in.ReadySince, &out.ReadySince
+		*out = (*in).DeepCopy()
+	}
+	if in.ExportBaseline != nil {
+		in, out := &in.ExportBaseline, &out.ExportBaseline
+		*out = make(map[string]CanaryExportTotals, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRolloutStatus.
+func (in *CanaryRolloutStatus) DeepCopy() *CanaryRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CohortStatus) DeepCopyInto(out *CohortStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CohortStatus.
+func (in *CohortStatus) DeepCopy() *CohortStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CohortStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigHashStatus) DeepCopyInto(out *ConfigHashStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigHashStatus.
+func (in *ConfigHashStatus) DeepCopy() *ConfigHashStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigHashStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapsSpec) DeepCopyInto(out *ConfigMapsSpec) {
 	*out = *in
@@ -458,6 +1127,31 @@ func (in *ConfigMapsSpec) DeepCopy() *ConfigMapsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneMetricsSpec) DeepCopyInto(out *ControlPlaneMetricsSpec) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ControlPlaneComponent, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScrapeInterval != nil {
+		in, out := &in.ScrapeInterval, &out.ScrapeInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneMetricsSpec.
+func (in *ControlPlaneMetricsSpec) DeepCopy() *ControlPlaneMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DcgmExporter) DeepCopyInto(out *DcgmExporter) {
 	*out = *in
@@ -549,6 +1243,13 @@ func (in *DcgmExporterSpec) DeepCopyInto(out *DcgmExporterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]corev1.Toleration, len(*in))
@@ -609,50 +1310,52 @@ func (in *DcgmExporterStatus) DeepCopy() *DcgmExporterStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DotNet) DeepCopyInto(out *DotNet) {
+func (in *DebugSpec) DeepCopyInto(out *DebugSpec) {
 	*out = *in
-	if in.VolumeSizeLimit != nil {
-		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugSpec.
+func (in *DebugSpec) DeepCopy() *DebugSpec {
+	if in == nil {
+		return nil
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
+	out := new(DebugSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DotNet.
-func (in *DotNet) DeepCopy() *DotNet {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DestinationSpec) DeepCopyInto(out *DestinationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationSpec.
+func (in *DestinationSpec) DeepCopy() *DestinationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DotNet)
+	out := new(DestinationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Exporter) DeepCopyInto(out *Exporter) {
+func (in *DiagnosticsSpec) DeepCopyInto(out *DiagnosticsSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Exporter.
-func (in *Exporter) DeepCopy() *Exporter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticsSpec.
+func (in *DiagnosticsSpec) DeepCopy() *DiagnosticsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Exporter)
+	out := new(DiagnosticsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Go) DeepCopyInto(out *Go) {
+func (in *DotNet) DeepCopyInto(out *DotNet) {
 	*out = *in
 	if in.VolumeSizeLimit != nil {
 		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
@@ -667,25 +1370,177 @@ func (in *Go) DeepCopyInto(out *Go) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.RuntimeImages != nil {
+		in, out := &in.RuntimeImages, &out.RuntimeImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Go.
-func (in *Go) DeepCopy() *Go {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DotNet.
+func (in *DotNet) DeepCopy() *DotNet {
 	if in == nil {
 		return nil
 	}
-	out := new(Go)
+	out := new(DotNet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ingress) DeepCopyInto(out *Ingress) {
+func (in *ExportErrorsAlarmSpec) DeepCopyInto(out *ExportErrorsAlarmSpec) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EvaluationPeriods != nil {
+		in, out := &in.EvaluationPeriods, &out.EvaluationPeriods
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportErrorsAlarmSpec.
+func (in *ExportErrorsAlarmSpec) DeepCopy() *ExportErrorsAlarmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportErrorsAlarmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Exporter) DeepCopyInto(out *Exporter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Exporter.
+func (in *Exporter) DeepCopy() *Exporter {
+	if in == nil {
+		return nil
+	}
+	out := new(Exporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraMountSpec) DeepCopyInto(out *ExtraMountSpec) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]corev1.KeyToPath, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraMountSpec.
+func (in *ExtraMountSpec) DeepCopy() *ExtraMountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraMountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Go) DeepCopyInto(out *Go) {
+	*out = *in
+	if in.VolumeSizeLimit != nil {
+		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Go.
+func (in *Go) DeepCopy() *Go {
+	if in == nil {
+		return nil
+	}
+	out := new(Go)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoRuntimeSpec) DeepCopyInto(out *GoRuntimeSpec) {
+	*out = *in
+	if in.MaxProcs != nil {
+		in, out := &in.MaxProcs, &out.MaxProcs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MemoryLimit != nil {
+		in, out := &in.MemoryLimit, &out.MemoryLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MemoryLimitPercent != nil {
+		in, out := &in.MemoryLimitPercent, &out.MemoryLimitPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoRuntimeSpec.
+func (in *GoRuntimeSpec) DeepCopy() *GoRuntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GoRuntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeartbeatAlarmSpec) DeepCopyInto(out *HeartbeatAlarmSpec) {
+	*out = *in
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EvaluationPeriods != nil {
+		in, out := &in.EvaluationPeriods, &out.EvaluationPeriods
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeartbeatAlarmSpec.
+func (in *HeartbeatAlarmSpec) DeepCopy() *HeartbeatAlarmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HeartbeatAlarmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ingress) DeepCopyInto(out *Ingress) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
@@ -714,10 +1569,37 @@ func (in *Ingress) DeepCopy() *Ingress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectedEnvPolicy) DeepCopyInto(out *InjectedEnvPolicy) {
+	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rename != nil {
+		in, out := &in.Rename, &out.Rename
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectedEnvPolicy.
+func (in *InjectedEnvPolicy) DeepCopy() *InjectedEnvPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectedEnvPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Instrumentation) DeepCopyInto(out *Instrumentation) {
 	*out = *in
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	out.TypeMeta = in.TypeMeta
 	in.Spec.DeepCopyInto(&out.Spec)
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -741,6 +1623,21 @@ func (in *Instrumentation) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstrumentationLanguageStatus) DeepCopyInto(out *InstrumentationLanguageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationLanguageStatus.
+func (in *InstrumentationLanguageStatus) DeepCopy() *InstrumentationLanguageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstrumentationLanguageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstrumentationList) DeepCopyInto(out *InstrumentationList) {
 	*out = *in
@@ -798,6 +1695,7 @@ func (in *InstrumentationSpec) DeepCopyInto(out *InstrumentationSpec) {
 	in.Go.DeepCopyInto(&out.Go)
 	in.ApacheHttpd.DeepCopyInto(&out.ApacheHttpd)
 	in.Nginx.DeepCopyInto(&out.Nginx)
+	in.Batch.DeepCopyInto(&out.Batch)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationSpec.
@@ -813,6 +1711,15 @@ func (in *InstrumentationSpec) DeepCopy() *InstrumentationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstrumentationStatus) DeepCopyInto(out *InstrumentationStatus) {
 	*out = *in
+	if in.Languages != nil {
+		in, out := &in.Languages, &out.Languages
+		*out = make([]InstrumentationLanguageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastInjectionTime != nil {
+		in, out := &in.LastInjectionTime, &out.LastInjectionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationStatus.
@@ -841,6 +1748,21 @@ func (in *Java) DeepCopyInto(out *Java) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.PodMonitor != nil {
+		in, out := &in.PodMonitor, &out.PodMonitor
+		*out = new(JavaPodMonitor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]JavaExtension, len(*in))
+		copy(*out, *in)
+	}
+	if in.JVMArgs != nil {
+		in, out := &in.JVMArgs, &out.JVMArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Java.
@@ -853,6 +1775,83 @@ func (in *Java) DeepCopy() *Java {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JavaExtension) DeepCopyInto(out *JavaExtension) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JavaExtension.
+func (in *JavaExtension) DeepCopy() *JavaExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(JavaExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JavaPodMonitor) DeepCopyInto(out *JavaPodMonitor) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JavaPodMonitor.
+func (in *JavaPodMonitor) DeepCopy() *JavaPodMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(JavaPodMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogVolumesSpec) DeepCopyInto(out *LogVolumesSpec) {
+	*out = *in
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogVolumesSpec.
+func (in *LogVolumesSpec) DeepCopy() *LogVolumesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogVolumesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogsSpec) DeepCopyInto(out *LogsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogsSpec.
+func (in *LogsSpec) DeepCopy() *LogsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
 	*out = *in
@@ -982,6 +1981,16 @@ func (in *NeuronMonitorSpec) DeepCopyInto(out *NeuronMonitorSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(corev1.IPFamilyPolicy)
+		**out = **in
+	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
 		*out = make([]corev1.EnvVar, len(*in))
@@ -989,6 +1998,13 @@ func (in *NeuronMonitorSpec) DeepCopyInto(out *NeuronMonitorSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]corev1.Toleration, len(*in))
@@ -1084,51 +2100,165 @@ func (in *Nginx) DeepCopy() *Nginx {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeJS) DeepCopyInto(out *NodeJS) {
+func (in *NodeConfigOverrideSpec) DeepCopyInto(out *NodeConfigOverrideSpec) {
 	*out = *in
-	if in.VolumeSizeLimit != nil {
-		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeJS.
-func (in *NodeJS) DeepCopy() *NodeJS {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigOverrideSpec.
+func (in *NodeConfigOverrideSpec) DeepCopy() *NodeConfigOverrideSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeJS)
+	out := new(NodeConfigOverrideSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
+func (in *NodeGroupSpec) DeepCopyInto(out *NodeGroupSpec) {
 	*out = *in
-	out.Metrics = in.Metrics
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilitySpec.
-func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupSpec.
+func (in *NodeGroupSpec) DeepCopy() *NodeGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservabilitySpec)
+	out := new(NodeGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenShiftRoute) DeepCopyInto(out *OpenShiftRoute) {
+func (in *NodeJS) DeepCopyInto(out *NodeJS) {
+	*out = *in
+	if in.VolumeSizeLimit != nil {
+		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.RuntimeImages != nil {
+		in, out := &in.RuntimeImages, &out.RuntimeImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeJS.
+func (in *NodeJS) DeepCopy() *NodeJS {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeJS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLabel) DeepCopyInto(out *NodeLabel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLabel.
+func (in *NodeLabel) DeepCopy() *NodeLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTerminationSpec) DeepCopyInto(out *NodeTerminationSpec) {
+	*out = *in
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTerminationSpec.
+func (in *NodeTerminationSpec) DeepCopy() *NodeTerminationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTerminationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OTLPReceiverSpec) DeepCopyInto(out *OTLPReceiverSpec) {
+	*out = *in
+	if in.GRPCMaxRecvMsgSizeMiB != nil {
+		in, out := &in.GRPCMaxRecvMsgSizeMiB, &out.GRPCMaxRecvMsgSizeMiB
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HTTPMaxRequestBodySize != nil {
+		in, out := &in.HTTPMaxRequestBodySize, &out.HTTPMaxRequestBodySize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.HTTPCompressionAlgorithms != nil {
+		in, out := &in.HTTPCompressionAlgorithms, &out.HTTPCompressionAlgorithms
+		*out = make([]CompressionAlgorithm, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPReceiverSpec.
+func (in *OTLPReceiverSpec) DeepCopy() *OTLPReceiverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OTLPReceiverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
+	*out = *in
+	out.Metrics = in.Metrics
+	if in.SelfTelemetry != nil {
+		in, out := &in.SelfTelemetry, &out.SelfTelemetry
+		*out = new(SelfTelemetrySpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilitySpec.
+func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenShiftRoute) DeepCopyInto(out *OpenShiftRoute) {
 	*out = *in
 }
 
@@ -1142,6 +2272,73 @@ func (in *OpenShiftRoute) DeepCopy() *OpenShiftRoute {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceSpec) DeepCopyInto(out *PersistenceSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceSpec.
+func (in *PersistenceSpec) DeepCopy() *PersistenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineDeploymentSpec) DeepCopyInto(out *PipelineDeploymentSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaler != nil {
+		in, out := &in.Autoscaler, &out.Autoscaler
+		*out = new(AutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineDeploymentSpec.
+func (in *PipelineDeploymentSpec) DeepCopy() *PipelineDeploymentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineDeploymentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineSplitSpec) DeepCopyInto(out *PipelineSplitSpec) {
+	*out = *in
+	in.Metrics.DeepCopyInto(&out.Metrics)
+	in.Traces.DeepCopyInto(&out.Traces)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSplitSpec.
+func (in *PipelineSplitSpec) DeepCopy() *PipelineSplitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineSplitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
 	*out = *in
@@ -1223,6 +2420,13 @@ func (in *PrometheusConfig) DeepCopyInto(out *PrometheusConfig) {
 		in, out := &in.TargetAllocator, &out.TargetAllocator
 		*out = (*in).DeepCopy()
 	}
+	if in.ExtraScrapeConfigs != nil {
+		in, out := &in.ExtraScrapeConfigs, &out.ExtraScrapeConfigs
+		*out = make([]AnyConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusConfig.
@@ -1235,6 +2439,41 @@ func (in *PrometheusConfig) DeepCopy() *PrometheusConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusReloadSpec) DeepCopyInto(out *PrometheusReloadSpec) {
+	*out = *in
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusReloadSpec.
+func (in *PrometheusReloadSpec) DeepCopy() *PrometheusReloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusReloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Python) DeepCopyInto(out *Python) {
 	*out = *in
@@ -1251,6 +2490,11 @@ func (in *Python) DeepCopyInto(out *Python) {
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ExcludedInstrumentations != nil {
+		in, out := &in.ExcludedInstrumentations, &out.ExcludedInstrumentations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Python.
@@ -1285,6 +2529,46 @@ func (in *Resource) DeepCopy() *Resource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartStatus) DeepCopyInto(out *RestartStatus) {
+	*out = *in
+	if in.Restarted != nil {
+		in, out := &in.Restarted, &out.Restarted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartStatus.
+func (in *RestartStatus) DeepCopy() *RestartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategySpec) DeepCopyInto(out *RolloutStrategySpec) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategySpec.
+func (in *RolloutStrategySpec) DeepCopy() *RolloutStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sampler) DeepCopyInto(out *Sampler) {
 	*out = *in
@@ -1314,3 +2598,180 @@ func (in *ScaleSubresourceStatus) DeepCopy() *ScaleSubresourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityProfilesSpec) DeepCopyInto(out *SecurityProfilesSpec) {
+	*out = *in
+	if in.Seccomp != nil {
+		in, out := &in.Seccomp, &out.Seccomp
+		*out = new(corev1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityProfilesSpec.
+func (in *SecurityProfilesSpec) DeepCopy() *SecurityProfilesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityProfilesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTelemetrySpec) DeepCopyInto(out *SelfTelemetrySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTelemetrySpec.
+func (in *SelfTelemetrySpec) DeepCopy() *SelfTelemetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTelemetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSpec.
+func (in *TLSSpec) DeepCopy() *TLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedCASpec) DeepCopyInto(out *TrustedCASpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCASpec.
+func (in *TrustedCASpec) DeepCopy() *TrustedCASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedCASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionSplitSpec) DeepCopyInto(out *VersionSplitSpec) {
+	*out = *in
+	out.NodeLabel = in.NodeLabel
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionSplitSpec.
+func (in *VersionSplitSpec) DeepCopy() *VersionSplitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionSplitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionSplitStatus) DeepCopyInto(out *VersionSplitStatus) {
+	*out = *in
+	out.Stable = in.Stable
+	out.Canary = in.Canary
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionSplitStatus.
+func (in *VersionSplitStatus) DeepCopy() *VersionSplitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionSplitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerticalAutoscalerSpec) DeepCopyInto(out *VerticalAutoscalerSpec) {
+	*out = *in
+	if in.MinAllowed != nil {
+		in, out := &in.MinAllowed, &out.MinAllowed
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxAllowed != nil {
+		in, out := &in.MaxAllowed, &out.MaxAllowed
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalAutoscalerSpec.
+func (in *VerticalAutoscalerSpec) DeepCopy() *VerticalAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerticalAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
+func (in *WorkloadStatus) DeepCopy() *WorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XRaySpec) DeepCopyInto(out *XRaySpec) {
+	*out = *in
+	if in.LocalMode != nil {
+		in, out := &in.LocalMode, &out.LocalMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BufferSizeMB != nil {
+		in, out := &in.BufferSizeMB, &out.BufferSizeMB
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XRaySpec.
+func (in *XRaySpec) DeepCopy() *XRaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(XRaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
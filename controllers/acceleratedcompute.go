@@ -0,0 +1,251 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/neuronmonitor"
+)
+
+const (
+	// the DcgmExporter and the NeuronMonitor deployed on behalf of the agent
+	autoDcgmExporterName  = "dcgm-exporter"
+	autoNeuronMonitorName = "neuron-monitor"
+
+	instanceTypeLabel = "node.kubernetes.io/instance-type"
+	gpuResource       = corev1.ResourceName("nvidia.com/gpu")
+
+	dcgmExporterPort  = 9400
+	neuronMonitorPort = 8000
+
+	// defaultDcgmMetricsConfig are the GPU metrics collected by the enhanced Container Insights.
+	defaultDcgmMetricsConfig = `DCGM_FI_DEV_GPU_UTIL,      gauge, GPU utilization (in %).
+DCGM_FI_DEV_MEM_COPY_UTIL, gauge, Memory utilization (in %).
+DCGM_FI_DEV_FB_FREE,       gauge, Framebuffer memory free (in MiB).
+DCGM_FI_DEV_FB_USED,       gauge, Framebuffer memory used (in MiB).
+DCGM_FI_DEV_FB_TOTAL,      gauge, Framebuffer memory total (in MiB).
+DCGM_FI_DEV_GPU_TEMP,      gauge, GPU temperature (in C).
+DCGM_FI_DEV_POWER_USAGE,   gauge, Power draw (in W).
+`
+)
+
+// neuronResources are the extended resources of the nodes with Neuron devices.
+var neuronResources = []corev1.ResourceName{"aws.amazon.com/neuron", "aws.amazon.com/neuroncore", "aws.amazon.com/neurondevice"}
+
+// reconcileAcceleratedCompute deploys the DcgmExporter and the NeuronMonitor on the instance types of the nodes with
+// GPUs and Neuron devices, when the operator auto-deploys them and the agent collects the accelerated compute metrics.
+// They are deleted once the agent no longer collects these metrics, or no node has the hardware anymore. A
+// DcgmExporter or a NeuronMonitor created by the users is left untouched.
+func (r *AmazonCloudWatchAgentReconciler) reconcileAcceleratedCompute(ctx context.Context, log logr.Logger, instance *v1alpha1.AmazonCloudWatchAgent) error {
+	// the DcgmExporter and NeuronMonitor controllers only follow the config of this agent
+	if !r.config.AcceleratedComputeAutoDeploy() || instance.Namespace != amazonCloudWatchNamespace || instance.Name != amazonCloudWatchAgentName {
+		return nil
+	}
+
+	var gpuTypes, neuronTypes []string
+	if enabledAcceleratedCompute(instance.Spec.Config, log) {
+		nodes := &corev1.NodeList{}
+		if err := r.List(ctx, nodes); err != nil {
+			return fmt.Errorf("failed to list the nodes: %w", err)
+		}
+		gpuTypes = instanceTypesWith(nodes.Items, gpuResource)
+		neuronTypes = instanceTypesWith(nodes.Items, neuronResources...)
+	}
+
+	dcgm := &v1alpha1.DcgmExporter{ObjectMeta: metav1.ObjectMeta{Name: autoDcgmExporterName, Namespace: instance.Namespace}}
+	if err := r.reconcileAutoDeployed(ctx, instance, dcgm, len(gpuTypes) > 0, func() {
+		dcgm.Spec = autoDcgmExporterSpec(dcgm.Spec, gpuTypes)
+	}); err != nil {
+		return fmt.Errorf("failed to deploy the DCGM exporter: %w", err)
+	}
+	neuron := &v1alpha1.NeuronMonitor{ObjectMeta: metav1.ObjectMeta{Name: autoNeuronMonitorName, Namespace: instance.Namespace}}
+	if err := r.reconcileAutoDeployed(ctx, instance, neuron, len(neuronTypes) > 0, func() {
+		neuron.Spec = autoNeuronMonitorSpec(neuron.Spec, neuronTypes)
+	}); err != nil {
+		return fmt.Errorf("failed to deploy the Neuron monitor: %w", err)
+	}
+	return nil
+}
+
+// reconcileAutoDeployed creates or updates an object deployed on behalf of the instance while it is needed, and
+// deletes it otherwise. The objects the instance doesn't control were created by the users, and are left untouched.
+func (r *AmazonCloudWatchAgentReconciler) reconcileAutoDeployed(ctx context.Context, instance *v1alpha1.AmazonCloudWatchAgent, obj client.Object, needed bool, mutate func()) error {
+	kind := objectKind(obj, r.scheme)
+	err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(obj, instance) {
+		return nil
+	}
+
+	if !needed {
+		if !exists {
+			return nil
+		}
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		recordEvent(r.recorder, instance, corev1.EventTypeNormal, "Deleted", "Deleted %s %s, which no node needs anymore", kind, obj.GetName())
+		return nil
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		mutate()
+		return controllerutil.SetControllerReference(instance, obj, r.scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op == controllerutil.OperationResultCreated {
+		recordEvent(r.recorder, instance, corev1.EventTypeNormal, "Created", "Created %s %s", kind, obj.GetName())
+	}
+	return nil
+}
+
+// watchAcceleratedCompute reconciles the agent deploying the DcgmExporter and the NeuronMonitor when they are changed
+// or deleted, and when a node gains or loses its GPUs or Neuron devices.
+func (r *AmazonCloudWatchAgentReconciler) watchAcceleratedCompute(b *builder.Builder) {
+	// the updates only matter when the instance types with accelerators change
+	accelerated := builder.WithPredicates(predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return hasAccelerators(e.Object.(*corev1.Node)) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldGPU, oldNeuron := acceleratedTypes(e.ObjectOld.(*corev1.Node))
+			newGPU, newNeuron := acceleratedTypes(e.ObjectNew.(*corev1.Node))
+			return !slices.Equal(oldGPU, newGPU) || !slices.Equal(oldNeuron, newNeuron)
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return hasAccelerators(e.Object.(*corev1.Node)) },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	})
+	b.Owns(&v1alpha1.DcgmExporter{}).
+		Owns(&v1alpha1.NeuronMonitor{}).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(enqueueAcceleratedComputeAgent), accelerated)
+}
+
+// enqueueAcceleratedComputeAgent maps the nodes to the agent deploying the DcgmExporter and the NeuronMonitor.
+func enqueueAcceleratedComputeAgent(context.Context, client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: amazonCloudWatchNamespace, Name: amazonCloudWatchAgentName}}}
+}
+
+// acceleratedTypes returns the instance type of the node when it has GPUs, and when it has Neuron devices.
+func acceleratedTypes(node *corev1.Node) (gpu []string, neuron []string) {
+	nodes := []corev1.Node{*node}
+	return instanceTypesWith(nodes, gpuResource), instanceTypesWith(nodes, neuronResources...)
+}
+
+// hasAccelerators returns whether the node has GPUs or Neuron devices.
+func hasAccelerators(node *corev1.Node) bool {
+	gpu, neuron := acceleratedTypes(node)
+	return len(gpu) > 0 || len(neuron) > 0
+}
+
+// instanceTypesWith returns the sorted instance types of the nodes having one of the given resources.
+func instanceTypesWith(nodes []corev1.Node, resources ...corev1.ResourceName) []string {
+	found := map[string]bool{}
+	for _, node := range nodes {
+		instanceType := node.Labels[instanceTypeLabel]
+		if instanceType == "" {
+			continue
+		}
+		for _, resource := range resources {
+			if quantity, ok := node.Status.Capacity[resource]; ok && !quantity.IsZero() {
+				found[instanceType] = true
+			}
+		}
+	}
+	types := make([]string, 0, len(found))
+	for instanceType := range found {
+		types = append(types, instanceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// instanceTypesAffinity schedules pods on the nodes of the given instance types.
+func instanceTypesAffinity(types []string) *corev1.Affinity {
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      instanceTypeLabel,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   types,
+					}},
+				}},
+			},
+		},
+	}
+}
+
+// autoDcgmExporterSpec returns the spec of the DcgmExporter deployed on the given GPU instance types, keeping the
+// image pinned on the current spec.
+func autoDcgmExporterSpec(current v1alpha1.DcgmExporterSpec, types []string) v1alpha1.DcgmExporterSpec {
+	return v1alpha1.DcgmExporterSpec{
+		Image:         current.Image,
+		MetricsConfig: defaultDcgmMetricsConfig,
+		Ports: []corev1.ServicePort{{
+			Name:       "metrics",
+			Port:       dcgmExporterPort,
+			TargetPort: intstr.FromInt(dcgmExporterPort),
+			Protocol:   corev1.ProtocolTCP,
+		}},
+		Env: []corev1.EnvVar{
+			{Name: "DCGM_EXPORTER_KUBERNETES", Value: "true"},
+			{Name: "DCGM_EXPORTER_LISTEN", Value: fmt.Sprintf(":%d", dcgmExporterPort)},
+			{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+		},
+		// the pod resources of the kubelet map the GPUs to the pods using them
+		Volumes: []corev1.Volume{{
+			Name:         "pod-resources",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/kubelet/pod-resources"}},
+		}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "pod-resources", MountPath: "/var/lib/kubelet/pod-resources", ReadOnly: true}},
+		Tolerations:  []corev1.Toleration{{Key: string(gpuResource), Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		Affinity:     instanceTypesAffinity(types),
+	}
+}
+
+// autoNeuronMonitorSpec returns the spec of the NeuronMonitor deployed on the given Neuron instance types, keeping the
+// image pinned on the current spec.
+func autoNeuronMonitorSpec(current v1alpha1.NeuronMonitorSpec, types []string) v1alpha1.NeuronMonitorSpec {
+	privileged := true
+	return v1alpha1.NeuronMonitorSpec{
+		Image:         current.Image,
+		MonitorConfig: neuronmonitor.DefaultMonitorConfig,
+		Command:       []string{"/opt/bin/entrypoint.sh"},
+		Ports: []corev1.ServicePort{{
+			Name:       "metrics",
+			Port:       neuronMonitorPort,
+			TargetPort: intstr.FromInt(neuronMonitorPort),
+			Protocol:   corev1.ProtocolTCP,
+		}},
+		Env: []corev1.EnvVar{
+			{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+		},
+		SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+		Tolerations:     []corev1.Toleration{{Key: string(neuronResources[0]), Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		Affinity:        instanceTypesAffinity(types),
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestReconcileAcceleratedCompute(t *testing.T) {
+	ctx := context.Background()
+	logger := logf.Log.WithName("unit-tests")
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	node := func(name, instanceType string, capacity corev1.ResourceList) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{instanceTypeLabel: instanceType}},
+			Status:     corev1.NodeStatus{Capacity: capacity},
+		}
+	}
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: amazonCloudWatchAgentName, Namespace: amazonCloudWatchNamespace, UID: "agent-uid"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`,
+		},
+	}
+	// a NeuronMonitor created by the users is left to them
+	neuron := &v1alpha1.NeuronMonitor{ObjectMeta: metav1.ObjectMeta{Name: autoNeuronMonitorName, Namespace: amazonCloudWatchNamespace}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		agent,
+		neuron,
+		node("gpu", "g5.xlarge", corev1.ResourceList{gpuResource: resource.MustParse("1")}),
+		node("inferentia", "inf2.xlarge", corev1.ResourceList{"aws.amazon.com/neuroncore": resource.MustParse("2")}),
+		node("cpu", "m5.large", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}),
+	).Build()
+	r := NewReconciler(Params{
+		Client:   c,
+		Log:      logger,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Config:   config.New(config.WithAcceleratedComputeAutoDeploy(true)),
+	})
+
+	require.NoError(t, r.reconcileAcceleratedCompute(ctx, logger, agent))
+	dcgm := &v1alpha1.DcgmExporter{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: amazonCloudWatchNamespace, Name: autoDcgmExporterName}, dcgm))
+	assert.True(t, metav1.IsControlledBy(dcgm, agent))
+	assert.Equal(t, []string{"g5.xlarge"}, dcgm.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Values)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(neuron), neuron))
+	assert.Empty(t, neuron.OwnerReferences)
+	assert.Empty(t, neuron.Spec.MonitorConfig)
+
+	// the DcgmExporter is deleted once the agent no longer collects the accelerated compute metrics
+	agent.Spec.Config = `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true,"accelerated_compute_metrics":false}}}}`
+	require.NoError(t, r.reconcileAcceleratedCompute(ctx, logger, agent))
+	err := c.Get(ctx, client.ObjectKeyFromObject(dcgm), &v1alpha1.DcgmExporter{})
+	assert.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(neuron), neuron))
+}
+
+func TestAcceleratedTypes(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{instanceTypeLabel: "g5.xlarge"}},
+		Status:     corev1.NodeStatus{Capacity: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+	}
+	assert.False(t, hasAccelerators(node))
+
+	// the device plugin advertises the GPUs once the node is registered
+	node.Status.Capacity[gpuResource] = resource.MustParse("1")
+	gpu, neuron := acceleratedTypes(node)
+	assert.Equal(t, []string{"g5.xlarge"}, gpu)
+	assert.Empty(t, neuron)
+	assert.True(t, hasAccelerators(node))
+}
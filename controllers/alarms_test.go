@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestFinalizeAlarmsWithoutAlarms(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	instance := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", Finalizers: []string{alarmsFinalizer}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &AmazonCloudWatchAgentReconciler{Client: c, recorder: recorder}
+
+	// the alarms aren't orphaned by an operator which doesn't manage them
+	require.NoError(t, r.finalizeAlarms(ctx, logr.Discard(), instance))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(instance), instance))
+	assert.Equal(t, []string{alarmsFinalizer}, instance.Finalizers)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning AlarmsNotDeleted The CloudWatch alarms are disabled in the operator")
+}
@@ -6,32 +6,65 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/alarms"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/imagedigest"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
 	collectorStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/collector"
 )
 
+var (
+	certificateListGVK           = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "CertificateList"}
+	verticalPodAutoscalerListGVK = schema.GroupVersionKind{Group: "autoscaling.k8s.io", Version: "v1", Kind: "VerticalPodAutoscalerList"}
+)
+
+// alarmsFinalizer makes sure the CloudWatch alarms of an AmazonCloudWatchAgent are deleted along with it.
+const alarmsFinalizer = "cloudwatch.aws.amazon.com/alarms"
+
+// alarmsRetryInterval is the interval the alarms are retried at while the CloudWatch API fails.
+const alarmsRetryInterval = time.Minute
+
 // AmazonCloudWatchAgentReconciler reconciles a AmazonCloudWatchAgent object.
 type AmazonCloudWatchAgentReconciler struct {
 	client.Client
+	// reader reads the objects the cache doesn't hold from the API server, such as the Secrets referenced in other
+	// namespaces.
+	reader   client.Reader
 	recorder record.EventRecorder
 	scheme   *runtime.Scheme
 	log      logr.Logger
 	config   config.Config
+	alarms   *alarms.Reconciler
+	digests  *imagedigest.Resolver
+	// scrape reads the internal metrics of the canary agents.
+	scrape  scrapeFunc
+	requeue *requeuer
+	tasks   []Task
 }
 
 // Params is the set of options to build a new AmazonCloudWatchAgentReconciler.
@@ -41,6 +74,14 @@ type Params struct {
 	Scheme   *runtime.Scheme
 	Log      logr.Logger
 	Config   config.Config
+	// Alarms manages the CloudWatch alarms of the agents, when set.
+	Alarms *alarms.Reconciler
+	// ImageDigests resolves the tags of the agent images when the operator requires image digests.
+	ImageDigests *imagedigest.Resolver
+	// Reader reads the objects the manager doesn't cache from the API server. Defaults to the client.
+	Reader client.Reader
+	// DisabledTasks are the names of the registered tasks the reconciler doesn't run.
+	DisabledTasks []string
 }
 
 func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx context.Context, owner v1alpha1.AmazonCloudWatchAgent) (map[types.UID]client.Object, error) {
@@ -83,7 +124,12 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 		return nil, err
 	}
 	for i := range serviceAccountList.Items {
-		ownedObjects[serviceAccountList.Items[i].GetUID()] = &serviceAccountList.Items[i]
+		// the service account the agent runs with is never pruned unless the operator created it
+		sa := &serviceAccountList.Items[i]
+		if sa.Name == collector.ServiceAccountName(owner) && !metav1.IsControlledBy(sa, &owner) {
+			continue
+		}
+		ownedObjects[sa.GetUID()] = sa
 	}
 
 	// List Deployments
@@ -113,6 +159,38 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 		ownedObjects[daemonSetList.Items[i].GetUID()] = &daemonSetList.Items[i]
 	}
 
+	// List HorizontalPodAutoscalers, such as the ones of the deployments of a pipeline split
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	err = r.List(ctx, hpaList, listOps)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hpaList.Items {
+		ownedObjects[hpaList.Items[i].GetUID()] = &hpaList.Items[i]
+	}
+
+	// List cert-manager Certificates, skipped when cert-manager isn't installed or the operator isn't allowed to use it
+	certificateList := &unstructured.UnstructuredList{}
+	certificateList.SetGroupVersionKind(certificateListGVK)
+	err = r.List(ctx, certificateList, listOps)
+	if err != nil && !meta.IsNoMatchError(err) && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+	for i := range certificateList.Items {
+		ownedObjects[certificateList.Items[i].GetUID()] = &certificateList.Items[i]
+	}
+
+	// List VerticalPodAutoscalers, skipped when the Vertical Pod Autoscaler isn't installed
+	vpaList := &unstructured.UnstructuredList{}
+	vpaList.SetGroupVersionKind(verticalPodAutoscalerListGVK)
+	err = r.List(ctx, vpaList, listOps)
+	if err != nil && !meta.IsNoMatchError(err) && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+	for i := range vpaList.Items {
+		ownedObjects[vpaList.Items[i].GetUID()] = &vpaList.Items[i]
+	}
+
 	return ownedObjects, nil
 
 }
@@ -135,12 +213,23 @@ func NewReconciler(p Params) *AmazonCloudWatchAgentReconciler {
 		scheme:   p.Scheme,
 		config:   p.Config,
 		recorder: p.Recorder,
+		alarms:   p.Alarms,
+		digests:  p.ImageDigests,
+		scrape:   scrapeMetrics,
+		requeue:  newRequeuer(amazonCloudWatchAgentController, p.Config.ReconcileInterval()),
+		tasks:    enabledTasks(p.DisabledTasks),
+	}
+	r.reader = p.Reader
+	if r.reader == nil {
+		r.reader = p.Client
 	}
 	return r
 }
 
 // +kubebuilder:rbac:groups="",resources=pods;configmaps;services;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
@@ -148,9 +237,20 @@ func NewReconciler(p Params) *AmazonCloudWatchAgentReconciler {
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=dcgmexporters;neuronmonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagentconfigoverrides,verbs=get;list;watch
+// the nodes are patched cluster-wide to set and remove the canary node labels, any of them may be a canary node
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=list;watch;patch
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// the service accounts of the agents scraping the control plane are the subjects of the binding of the cluster role
+// deployed along with the operator, which the operator binds without holding the permissions of the role
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=cloudwatch-control-plane-metrics
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;update,resourceNames=cloudwatch-control-plane-metrics
 
 // Reconcile the current state of an OpenTelemetry collector resource with the desired state.
 func (r *AmazonCloudWatchAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -160,33 +260,253 @@ func (r *AmazonCloudWatchAgentReconciler) Reconcile(ctx context.Context, req ctr
 	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
 		if !apierrors.IsNotFound(err) {
 			log.Error(err, "unable to fetch AmazonCloudWatchAgent")
+			return ctrl.Result{}, err
 		}
 
 		// we'll ignore not-found errors, since they can't be fixed by an immediate
 		// requeue (we'll need to wait for a new notification), and we can get them
-		// on deleted requests.
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		// on deleted requests. The nodes labeled as canary nodes of the instance are released.
+		forgetReconciledGeneration(&instance, req.NamespacedName)
+		return ctrl.Result{}, releaseCanaryNodes(ctx, r.Client, r.reader, req.NamespacedName)
 	}
 	// We have a deletion, short circuit and let the deletion happen
 	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		if err := releaseCanaryNodes(ctx, r.Client, r.reader, req.NamespacedName); err != nil {
+			return r.requeue.result(ctrl.Result{}, err)
+		}
+		if err := r.finalizeAlarms(ctx, log, &instance); err != nil {
+			return r.requeue.result(ctrl.Result{}, err)
+		}
+		if err := r.finalizeControlPlaneMetrics(ctx, &instance); err != nil {
+			return r.requeue.result(ctrl.Result{}, err)
+		}
+		forgetReconciledGeneration(&instance, req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
+	// the objects of unmanaged AmazonCloudWatchAgent custom resources are left untouched, only their status is updated
 	if instance.Spec.ManagementState == v1alpha1.ManagementStateUnmanaged {
-		log.Info("Skipping reconciliation for unmanaged AmazonCloudWatchAgent resource", "name", req.String())
-		// Stop requeueing for unmanaged AmazonCloudWatchAgent custom resources
-		return ctrl.Result{}, nil
+		log.V(2).Info("Skipping reconciliation for unmanaged AmazonCloudWatchAgent resource", "name", req.String())
+		result, statusErr := collectorStatus.HandleUnmanaged(ctx, log, r.getParams(instance))
+		return r.requeue.result(result, statusErr)
+	}
+
+	if err := recordDefaultsApplied(ctx, r.Client, &instance, imageFields(instance), r.imageDefaults(instance)); err != nil {
+		log.V(2).Info("unable to record the applied defaults", "err", err)
 	}
 
 	params := r.getParams(instance)
 
-	desiredObjects, buildErr := BuildCollector(params)
+	// the config overrides of the cluster are layered onto a copy of the instance the agents are rendered from, so
+	// that they are never persisted to the instance itself
+	overrides, err := r.configOverrides(ctx)
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+	rendered, err := collector.WithConfigOverrides(instance, overrides)
+
+	// the agents are rendered from the config with its variables substituted, so that its hash changes with them
+	if err == nil {
+		rendered, err = collector.WithConfigVariables(r.config, rendered)
+	}
+
+	// an invalid config is never rolled out, the agents keep running the last good one until it is fixed
+	if err == nil {
+		err = collector.ValidateConfig(rendered)
+	}
+	// the webhook only sees the configs of the instance, the configs rendered from the config overrides and variables
+	// are checked against the policies of the operator before they are rolled out
+	if err == nil && (rendered.Spec.Config != instance.Spec.Config || rendered.Spec.OtelConfig != instance.Spec.OtelConfig) {
+		var warnings []string
+		warnings, err = v1alpha1.ValidateConfigPolicies(r.config, &rendered)
+		for _, warning := range warnings {
+			log.Info("the rendered configs exceed the guardrails", "warning", warning)
+		}
+	}
+	if err != nil {
+		result, statusErr := collectorStatus.HandleInvalidConfig(ctx, log, params, err)
+		return r.requeue.result(result, statusErr)
+	}
+
+	// two daemonsets binding the same host port can't run on the same nodes
+	conflict, err := findHostPortConflict(ctx, r.reader, instance)
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+	if conflict != "" {
+		result, statusErr := collectorStatus.HandleHostPortConflict(ctx, log, params, conflict)
+		return r.requeue.result(result, statusErr)
+	}
+
+	// a change of the configs rolled out to canary nodes first leaves the other nodes on the stable configs
+	rendered, rolloutRequeueAfter, err := reconcileCanaryRollout(ctx, r.Client, r.reader, r.scrape, r.recorder, rendered, time.Now())
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+	instance.Status = rendered.Status
+
+	// the agents are deployed by digest when it is pinned or required
+	params.OtelCol, err = withPinnedImages(ctx, r.config, r.digestResolver(rendered), rendered)
+	if err != nil {
+		result, statusErr := collectorStatus.HandleReconcileStatus(ctx, log, params, err)
+		return r.requeue.result(result, statusErr)
+	}
+
+	// the agents serve the renewed certificates of their receivers once their pods roll out
+	params.OtelCol, err = withTLSHash(ctx, r.reader, params.OtelCol)
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+
+	start := time.Now()
+	desiredObjects, buildErr := BuildCollector(ctx, params)
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskBuild, start, buildErr)
 	if buildErr != nil {
-		return ctrl.Result{}, buildErr
+		return r.requeue.result(ctrl.Result{}, buildErr)
+	}
+	desiredObjects, err = withoutUserManagedServiceAccount(ctx, r.Client, instance, desiredObjects)
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
 	}
 
-	err := reconcileDesiredObjectsWPrune(ctx, r.Client, log, params.OtelCol, params.Scheme, desiredObjects, r.findCloudWatchAgentOwnedObjects)
-	return collectorStatus.HandleReconcileStatus(ctx, log, params, err)
+	start = time.Now()
+	err = reconcileReferenceCopies(ctx, r.Client, r.reader, params.Scheme, instance)
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskReferences, start, err)
+	if err != nil {
+		r.recorder.Event(&instance, corev1.EventTypeWarning, "InvalidReference", err.Error())
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+
+	start = time.Now()
+	err = reconcileRestart(ctx, r.Client, instance, desiredObjects)
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskRestart, start, err)
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+
+	start = time.Now()
+	err = reconcileDesiredObjectsWPrune(ctx, r.Client, log, r.recorder, params.OtelCol, params.Scheme, desiredObjects, r.findCloudWatchAgentOwnedObjects)
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskApply, start, err)
+	if err != nil {
+		result, err := collectorStatus.HandleReconcileStatus(ctx, log, params, err)
+		return r.requeue.result(result, err)
+	}
+
+	start = time.Now()
+	err = reconcileConfigMapHistory(ctx, r.Client, params.Scheme, params.OtelCol, r.config.ConfigMapHistory(), desiredObjects)
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskConfigMapHistory, start, err)
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+
+	// the agents are reconciled while the CloudWatch API fails, the failure is reported in the status and retried
+	start = time.Now()
+	alarmsErr := r.reconcileAlarms(ctx, log, &instance)
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskAlarms, start, alarmsErr)
+	if alarmsErr != nil {
+		log.Error(alarmsErr, "failed to reconcile the CloudWatch alarms")
+		r.recorder.Event(&instance, corev1.EventTypeWarning, "AlarmsFailed", alarmsErr.Error())
+	}
+
+	start = time.Now()
+	err = r.reconcileControlPlaneMetrics(ctx, &instance)
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskControlPlaneMetrics, start, err)
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+
+	start = time.Now()
+	err = r.reconcileAcceleratedCompute(ctx, log, &rendered)
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskAcceleratedCompute, start, err)
+	if err != nil {
+		return r.requeue.result(ctrl.Result{}, err)
+	}
+
+	for _, task := range r.tasks {
+		start = time.Now()
+		err = task.Do(ctx, params)
+		metrics.ObserveReconcileTask(amazonCloudWatchAgentController, task.Name, start, err)
+		if err != nil && task.BailOnError {
+			return r.requeue.result(ctrl.Result{}, err)
+		} else if err != nil {
+			log.Error(err, "reconcile task failed", "task", task.Name)
+			r.recorder.Eventf(&instance, corev1.EventTypeWarning, "TaskFailed", "The task %s failed: %v", task.Name, err)
+		}
+	}
+
+	start = time.Now()
+	result, statusErr := collectorStatus.HandleReconcileStatus(ctx, log, params, nil, collectorStatus.WithAlarms(r.alarms != nil, alarmsErr))
+	metrics.ObserveReconcileTask(amazonCloudWatchAgentController, metrics.TaskStatus, start, statusErr)
+	if rolloutRequeueAfter > 0 && (result.RequeueAfter == 0 || rolloutRequeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = rolloutRequeueAfter
+	}
+	if alarmsErr != nil && (result.RequeueAfter == 0 || alarmsRetryInterval < result.RequeueAfter) {
+		result.RequeueAfter = alarmsRetryInterval
+	}
+	return r.requeue.result(result, statusErr)
+}
+
+// reconcileAlarms creates or updates the CloudWatch alarms of the instance, holding the deletion of the instance
+// until they are deleted. The alarms are deleted when the instance no longer defines them.
+func (r *AmazonCloudWatchAgentReconciler) reconcileAlarms(ctx context.Context, log logr.Logger, instance *v1alpha1.AmazonCloudWatchAgent) error {
+	if instance.Spec.Alarms == nil {
+		return r.finalizeAlarms(ctx, log, instance)
+	}
+	if r.alarms == nil {
+		log.V(2).Info("ignoring the alarms, the CloudWatch alarms are disabled in the operator")
+		return nil
+	}
+	if !controllerutil.ContainsFinalizer(instance, alarmsFinalizer) {
+		patch := client.MergeFrom(instance.DeepCopy())
+		controllerutil.AddFinalizer(instance, alarmsFinalizer)
+		if err := r.Patch(ctx, instance, patch); err != nil {
+			return err
+		}
+	}
+	return r.alarms.Reconcile(ctx, *instance)
+}
+
+// finalizeAlarms deletes the CloudWatch alarms of the instance and removes its alarms finalizer. The finalizer is
+// kept while the operator doesn't manage the alarms.
+func (r *AmazonCloudWatchAgentReconciler) finalizeAlarms(ctx context.Context, log logr.Logger, instance *v1alpha1.AmazonCloudWatchAgent) error {
+	if !controllerutil.ContainsFinalizer(instance, alarmsFinalizer) {
+		return nil
+	}
+	// the finalizer is kept until an operator managing the alarms deletes them, so that they aren't orphaned
+	if r.alarms == nil {
+		log.Info("keeping the alarms finalizer, the CloudWatch alarms are disabled in the operator")
+		r.recorder.Event(instance, corev1.EventTypeWarning, "AlarmsNotDeleted", "The CloudWatch alarms are disabled in the operator, "+
+			"the alarms of the instance are deleted once the operator is started with --enable-cloudwatch-alarms, "+
+			"or delete them manually and remove the finalizer "+alarmsFinalizer)
+		return nil
+	}
+	if err := r.alarms.Delete(ctx, *instance); err != nil {
+		return err
+	}
+	// a patch of the finalizers only, the spec of the instance may no longer be valid
+	patch := client.MergeFrom(instance.DeepCopy())
+	controllerutil.RemoveFinalizer(instance, alarmsFinalizer)
+	return r.Patch(ctx, instance, patch)
+}
+
+// imageDefaults returns the images defaulted from the operator configuration for the given instance.
+func (r *AmazonCloudWatchAgentReconciler) imageDefaults(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
+	defaults := map[string]string{}
+	if instance.Spec.Image == "" {
+		defaults["image"] = r.config.CollectorImage()
+	}
+	if instance.Spec.TargetAllocator.Enabled && instance.Spec.TargetAllocator.Image == "" {
+		defaults["targetAllocator.image"] = r.config.TargetAllocatorImage()
+	}
+	return defaults
+}
+
+// imageFields returns the current images of the instance defaulted by the reconciler.
+func imageFields(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
+	return map[string]string{
+		"image":                 instance.Spec.Image,
+		"targetAllocator.image": instance.Spec.TargetAllocator.Image,
+	}
 }
 
 // SetupWithManager tells the manager what our controller is interested in.
@@ -194,11 +514,30 @@ func (r *AmazonCloudWatchAgentReconciler) SetupWithManager(mgr ctrl.Manager) err
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.AmazonCloudWatchAgent{}).
 		Owns(&corev1.ConfigMap{}).
+		WithOptions(controller.Options{RateLimiter: newRateLimiter(r.config.ReconcileRetryBaseDelay(), r.config.ReconcileRetryMaxDelay())}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.DaemonSet{}).
 		Owns(&appsv1.StatefulSet{})
 
+	// the copies of the referenced objects and the certificates follow their changes
+	r.watchReferences(builder)
+
+	// the DcgmExporter and the NeuronMonitor follow the accelerated compute nodes of the cluster
+	if r.config.AcceleratedComputeAutoDeploy() {
+		r.watchAcceleratedCompute(builder)
+	}
+
+	// a change of the config overrides may change the configs of any agent
+	if r.config.ConfigOverrides() {
+		builder.Watches(&v1alpha1.AmazonCloudWatchAgentConfigOverride{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAgents))
+	}
+
+	// the managed resources gauge is refreshed periodically, listing the resources on every reconciliation is wasteful
+	if err := mgr.Add(&managedResourcesCounter{client: mgr.GetClient(), log: r.log}); err != nil {
+		return err
+	}
+
 	return builder.Complete(r)
 }
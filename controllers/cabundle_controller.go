@@ -0,0 +1,285 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+const (
+	// caBundleKey is the key of the CA certificate in the distributed ConfigMaps.
+	caBundleKey = "ca.crt"
+
+	caBundleComponent = "ca-bundle"
+	// caBundleResyncInterval bounds the time it takes for a rotated certificate to reach the namespaces.
+	caBundleResyncInterval = 5 * time.Minute
+)
+
+// CABundleReconciler distributes the CA certificate of the agents serving Application Signals over TLS, as a
+// ConfigMap, into the namespaces of the instrumented workloads so that their SDKs can verify the agent endpoint.
+type CABundleReconciler struct {
+	client.Client
+	// reader reads the certificate Secrets from the API server, to avoid caching all the Secrets of the cluster.
+	reader  client.Reader
+	log     logr.Logger
+	config  config.Config
+	requeue *requeuer
+}
+
+// NewCABundleReconciler creates a new reconciler distributing the CA certificates of AmazonCloudWatchAgent objects.
+func NewCABundleReconciler(p Params, reader client.Reader) *CABundleReconciler {
+	return &CABundleReconciler{
+		Client:  p.Client,
+		reader:  reader,
+		log:     p.Log,
+		config:  p.Config,
+		requeue: newRequeuer(caBundleController, caBundleResyncInterval),
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;statefulsets,verbs=list;watch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch
+
+// Reconcile distributes the CA certificate of an AmazonCloudWatchAgent and removes it from the namespaces
+// which no longer need it.
+func (r *CABundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var instance v1alpha1.AmazonCloudWatchAgent
+	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return r.requeue.result(ctrl.Result{}, err)
+		}
+		instance.ObjectMeta = metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}
+		return ctrl.Result{}, r.pruneCABundles(ctx, instance, nil)
+	}
+
+	result, err := r.reconcileCABundles(ctx, instance)
+	return r.requeue.result(result, err)
+}
+
+func (r *CABundleReconciler) reconcileCABundles(ctx context.Context, instance v1alpha1.AmazonCloudWatchAgent) (ctrl.Result, error) {
+	if !distributesCABundle(instance) {
+		return ctrl.Result{}, r.pruneCABundles(ctx, instance, nil)
+	}
+
+	secretName := collector.TLSSecretName(instance)
+	secret := &corev1.Secret{}
+	if err := r.reader.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: secretName}, secret); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get the certificate Secret %s: %w", secretName, err)
+	}
+	ca := secret.Data[caBundleKey]
+	if len(ca) == 0 {
+		// self-signed certificates are their own CA
+		ca = secret.Data[corev1.TLSCertKey]
+	}
+	if len(ca) == 0 {
+		return ctrl.Result{}, fmt.Errorf("the certificate Secret %s contains neither %s nor %s", secretName, caBundleKey, corev1.TLSCertKey)
+	}
+
+	namespaces, err := r.instrumentedNamespaces(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for namespace := range namespaces {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: naming.CABundleConfigMap(instance.ResourceName()), Namespace: namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+			if cm.Labels == nil {
+				cm.Labels = map[string]string{}
+			}
+			for k, v := range caBundleLabels(instance) {
+				cm.Labels[k] = v
+			}
+			cm.Data = map[string]string{caBundleKey: string(ca)}
+			return nil
+		}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to distribute the CA certificate to namespace %s: %w", namespace, err)
+		}
+	}
+	return ctrl.Result{}, r.pruneCABundles(ctx, instance, namespaces)
+}
+
+// pruneCABundles deletes the CA certificate ConfigMaps of the instance outside the given namespaces.
+func (r *CABundleReconciler) pruneCABundles(ctx context.Context, instance v1alpha1.AmazonCloudWatchAgent, keep map[string]struct{}) error {
+	var configMaps corev1.ConfigMapList
+	if err := r.List(ctx, &configMaps, client.MatchingLabels(caBundleLabels(instance))); err != nil {
+		return fmt.Errorf("failed to list the CA certificate ConfigMaps: %w", err)
+	}
+	for i := range configMaps.Items {
+		cm := &configMaps.Items[i]
+		if _, ok := keep[cm.Namespace]; ok {
+			continue
+		}
+		if err := r.Delete(ctx, cm); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete the CA certificate ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err)
+		}
+	}
+	return nil
+}
+
+// instrumentedNamespaces returns the namespaces which are, or contain workloads which are, annotated to inject
+// an instrumentation.
+func (r *CABundleReconciler) instrumentedNamespaces(ctx context.Context) (map[string]struct{}, error) {
+	var (
+		namespaceList corev1.NamespaceList
+		deployments   appsv1.DeploymentList
+		daemonSets    appsv1.DaemonSetList
+		statefulSets  appsv1.StatefulSetList
+	)
+	for _, list := range []client.ObjectList{&namespaceList, &deployments, &daemonSets, &statefulSets} {
+		if err := r.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("failed to list the instrumented workloads: %w", err)
+		}
+	}
+
+	var objects []client.Object
+	for i := range namespaceList.Items {
+		objects = append(objects, &namespaceList.Items[i])
+	}
+	for i := range deployments.Items {
+		objects = append(objects, &deployments.Items[i])
+	}
+	for i := range daemonSets.Items {
+		objects = append(objects, &daemonSets.Items[i])
+	}
+	for i := range statefulSets.Items {
+		objects = append(objects, &statefulSets.Items[i])
+	}
+
+	namespaces := map[string]struct{}{}
+	for _, obj := range objects {
+		if !objectInjectsInstrumentation(obj) {
+			continue
+		}
+		if _, ok := obj.(*corev1.Namespace); ok {
+			namespaces[obj.GetName()] = struct{}{}
+		} else {
+			namespaces[obj.GetNamespace()] = struct{}{}
+		}
+	}
+	return namespaces, nil
+}
+
+// distributesCABundle tells whether the agent serves Application Signals over TLS.
+func distributesCABundle(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	if collector.TLSSecretName(instance) == "" {
+		return false
+	}
+	if instance.Spec.ApplicationSignals.IsEnabled() {
+		return true
+	}
+	cwaConfig, err := adapters.ConfigStructFromJSONString(instance.Spec.Config)
+	if err != nil || cwaConfig == nil {
+		return false
+	}
+	return cwaConfig.GetApplicationSignalsMetricsConfig() != nil || cwaConfig.GetApplicationSignalsTracesConfig() != nil
+}
+
+// injectsInstrumentation tells whether the annotations request the injection of an instrumentation.
+func injectsInstrumentation(annotations map[string]string) bool {
+	for _, instType := range []instrumentation.Type{
+		instrumentation.TypeJava,
+		instrumentation.TypeNodeJS,
+		instrumentation.TypePython,
+		instrumentation.TypeDotNet,
+		instrumentation.TypeGo,
+	} {
+		if value, ok := annotations[instrumentation.InjectAnnotationKey(instType)]; ok && value != "" && value != "false" {
+			return true
+		}
+	}
+	return false
+}
+
+// objectInjectsInstrumentation tells whether the namespace or the pod template of the workload requests the
+// injection of an instrumentation.
+func objectInjectsInstrumentation(obj client.Object) bool {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return injectsInstrumentation(o.Spec.Template.Annotations)
+	case *appsv1.DaemonSet:
+		return injectsInstrumentation(o.Spec.Template.Annotations)
+	case *appsv1.StatefulSet:
+		return injectsInstrumentation(o.Spec.Template.Annotations)
+	default:
+		return injectsInstrumentation(obj.GetAnnotations())
+	}
+}
+
+// caBundleLabels returns the labels of the CA certificate ConfigMaps of the instance. They leave out the
+// app.kubernetes.io/part-of label, so that the ConfigMap in the namespace of the instance isn't pruned as an
+// object owned by the instance.
+func caBundleLabels(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+		"app.kubernetes.io/instance":   naming.Truncate("%s.%s", 63, instance.Namespace, instance.Name),
+		"app.kubernetes.io/component":  caBundleComponent,
+	}
+}
+
+// enqueueCABundleAgents enqueues the agents distributing their CA certificate.
+func (r *CABundleReconciler) enqueueCABundleAgents(ctx context.Context, _ client.Object) []reconcile.Request {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &agents); err != nil {
+		r.log.Error(err, "failed to list the AmazonCloudWatchAgent objects")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range agents.Items {
+		if distributesCABundle(agents.Items[i]) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&agents.Items[i])})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *CABundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// updates only matter when they add or remove an inject annotation
+	instrumented := builder.WithPredicates(predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return objectInjectsInstrumentation(e.Object) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return objectInjectsInstrumentation(e.ObjectOld) != objectInjectsInstrumentation(e.ObjectNew)
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return objectInjectsInstrumentation(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return objectInjectsInstrumentation(e.Object) },
+	})
+	enqueue := handler.EnqueueRequestsFromMapFunc(r.enqueueCABundleAgents)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cabundle").
+		For(&v1alpha1.AmazonCloudWatchAgent{}).
+		WithOptions(controller.Options{RateLimiter: newRateLimiter(r.config.ReconcileRetryBaseDelay(), r.config.ReconcileRetryMaxDelay())}).
+		Watches(&corev1.Namespace{}, enqueue, instrumented).
+		Watches(&appsv1.Deployment{}, enqueue, instrumented).
+		Watches(&appsv1.DaemonSet{}, enqueue, instrumented).
+		Watches(&appsv1.StatefulSet{}, enqueue, instrumented).
+		Complete(r)
+}
@@ -0,0 +1,108 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestCABundleReconciler(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"logs":{"metrics_collected":{"application_signals":{}}}}`,
+			TLS:    &v1alpha1.TLSSpec{SecretName: "agent-tls"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-tls", Namespace: "amazon-cloudwatch"},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), caBundleKey: []byte("ca")},
+	}
+	annotatedNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "annotated",
+		Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "true"},
+	}}
+	optedOutNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "opted-out",
+		Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "false"},
+	}}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "workloads"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-python": "amazon-cloudwatch/default"},
+		}}},
+	}
+	stale := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "agent-ca-bundle", Namespace: "stale", Labels: caBundleLabels(*agent)}}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(agent, secret, annotatedNamespace, optedOutNamespace, deployment, stale).
+		Build()
+
+	r := NewCABundleReconciler(Params{Client: c, Log: logr.Discard()}, c)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(agent)}
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var configMaps corev1.ConfigMapList
+	require.NoError(t, c.List(ctx, &configMaps, client.MatchingLabels(caBundleLabels(*agent))))
+	namespaces := map[string]string{}
+	for _, cm := range configMaps.Items {
+		assert.Equal(t, "agent-ca-bundle", cm.Name)
+		namespaces[cm.Namespace] = cm.Data[caBundleKey]
+	}
+	assert.Equal(t, map[string]string{"annotated": "ca", "workloads": "ca"}, namespaces)
+
+	// a rotated certificate is propagated
+	secret.Data[caBundleKey] = []byte("rotated")
+	require.NoError(t, c.Update(ctx, secret))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "workloads", Name: "agent-ca-bundle"}, cm))
+	assert.Equal(t, "rotated", cm.Data[caBundleKey])
+
+	// the certificate is removed with the agent
+	require.NoError(t, c.Delete(ctx, agent))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.List(ctx, &configMaps, client.MatchingLabels(caBundleLabels(*agent))))
+	assert.Empty(t, configMaps.Items)
+}
+
+func TestDistributesCABundle(t *testing.T) {
+	appSignals := `{"traces":{"traces_collected":{"application_signals":{}}}}`
+	for _, tt := range []struct {
+		name   string
+		spec   v1alpha1.AmazonCloudWatchAgentSpec
+		expect bool
+	}{
+		{name: "without TLS", spec: v1alpha1.AmazonCloudWatchAgentSpec{Config: appSignals}},
+		{name: "without Application Signals", spec: v1alpha1.AmazonCloudWatchAgentSpec{Config: `{"logs":{}}`, TLS: &v1alpha1.TLSSpec{SecretName: "tls"}}},
+		{name: "with the Application Signals toggle", spec: v1alpha1.AmazonCloudWatchAgentSpec{Config: `{"logs":{}}`, ApplicationSignals: &v1alpha1.ApplicationSignalsSpec{Enabled: true}, TLS: &v1alpha1.TLSSpec{SecretName: "tls"}}, expect: true},
+		{name: "with cert-manager", spec: v1alpha1.AmazonCloudWatchAgentSpec{Config: appSignals, TLS: &v1alpha1.TLSSpec{CertManager: &v1alpha1.CertManagerSpec{}}}, expect: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, distributesCABundle(v1alpha1.AmazonCloudWatchAgent{Spec: tt.spec}))
+		})
+	}
+}
@@ -0,0 +1,540 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+const (
+	defaultCanaryBakeDuration               = 10 * time.Minute
+	defaultCanaryMaxExportFailurePercentage = 5
+	// canaryScrapeTimeout bounds the reads of the internal metrics of a canary agent.
+	canaryScrapeTimeout = 5 * time.Second
+	// canaryRolloutPollInterval is how often the canary agents are checked while a rollout progresses, as their
+	// restarts don't change their daemonset.
+	canaryRolloutPollInterval = 30 * time.Second
+
+	reasonCanaryProgressing = "CanaryProgressing"
+	reasonCanaryRolledBack  = "CanaryRolledBack"
+	reasonConfigRolledOut   = "RolledOut"
+)
+
+// scrapeFunc reads the metrics served at the given URL, in the Prometheus text format.
+type scrapeFunc func(ctx context.Context, url string) ([]byte, error)
+
+// reconcileCanaryRollout moves the canary rollout of the configs of the instance forward, when its changes are
+// rolled out to canary nodes first. The canary nodes are labeled for the canary daemonset to run the change on them,
+// until their agents stayed ready and healthy for the bake duration, and the change is then rolled out to the other
+// nodes, which run the stable configs meanwhile. A change whose canary agents restart, fail to export their
+// telemetry, or don't get ready within the bake duration, is rolled back until the configs change again. The
+// progress is kept in the status, which the returned instance carries for the manifests to be built from, along with
+// the delay after which the rollout must be checked again.
+func reconcileCanaryRollout(ctx context.Context, c client.Client, nodes client.Reader, scrape scrapeFunc, recorder record.EventRecorder, instance v1alpha1.AmazonCloudWatchAgent, now time.Time) (v1alpha1.AmazonCloudWatchAgent, time.Duration, error) {
+	status, requeueAfter, err := nextCanaryRollout(ctx, c, nodes, scrape, instance, now)
+	if err != nil {
+		return instance, 0, err
+	}
+	previous := instance.Status.CanaryRollout
+	if status != nil || previous != nil {
+		if err := syncCanaryNodeLabels(ctx, c, nodes, instance, status); err != nil {
+			return instance, 0, err
+		}
+	}
+	if apiequality.Semantic.DeepEqual(previous, status) {
+		return instance, requeueAfter, nil
+	}
+
+	changed := instance.DeepCopy()
+	changed.Status.CanaryRollout = status
+	if status == nil {
+		meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.ConditionTypeConfigRolledOut)
+	} else {
+		meta.SetStatusCondition(&changed.Status.Conditions, canaryRolloutCondition(status, changed.Generation))
+	}
+	if err := c.Status().Patch(ctx, changed, client.MergeFrom(&instance)); err != nil {
+		return instance, 0, fmt.Errorf("failed to record the canary rollout progress: %w", err)
+	}
+
+	if status != nil && (previous == nil || previous.Phase != status.Phase || previous.Revision != status.Revision) {
+		eventType := corev1.EventTypeNormal
+		if status.Phase == v1alpha1.CanaryRolloutRolledBack {
+			eventType = corev1.EventTypeWarning
+		}
+		recorder.Event(changed, eventType, "CanaryRollout"+string(status.Phase), status.Message)
+	}
+	return *changed, requeueAfter, nil
+}
+
+// nextCanaryRollout returns the next state of the canary rollout of the instance, or nil when its changes aren't
+// rolled out to canary nodes.
+func nextCanaryRollout(ctx context.Context, c client.Client, nodes client.Reader, scrape scrapeFunc, instance v1alpha1.AmazonCloudWatchAgent, now time.Time) (*v1alpha1.CanaryRolloutStatus, time.Duration, error) {
+	strategy := instance.Spec.RolloutStrategy
+	if strategy == nil || strategy.Type != v1alpha1.RolloutStrategyCanary || instance.Spec.Mode != v1alpha1.ModeDaemonSet {
+		return nil, 0, nil
+	}
+	revision := configRevision(instance.Spec.Config, instance.Spec.OtelConfig)
+	previous := instance.Status.CanaryRollout
+
+	switch {
+	case previous != nil && previous.Phase == v1alpha1.CanaryRolloutSucceeded && previous.Revision == revision:
+		return previous, 0, nil
+	case previous == nil:
+		// no agent runs previous configs to compare the change with
+		return rolledOut(instance, revision, "the configs run on all the nodes"), 0, nil
+	case configRevision(previous.StableConfig, previous.StableOtelConfig) == revision:
+		return rolledOut(instance, revision, "the configs were reverted to the stable ones"), 0, nil
+	case previous.Revision == revision && previous.Phase == v1alpha1.CanaryRolloutRolledBack:
+		return previous, 0, nil
+	case previous.Revision == revision && previous.Phase == v1alpha1.CanaryRolloutProgressing:
+		return bakeCanaryRollout(ctx, c, scrape, instance, previous.DeepCopy(), now)
+	}
+
+	// a new change, the nodes keep running the configs which last succeeded while it is rolled out
+	canaryNodes, err := selectCanaryNodes(ctx, nodes, instance)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(canaryNodes) == 0 {
+		return rolledOut(instance, revision, "no node can run the canary agents, the configs run on all the nodes"), 0, nil
+	}
+	startedAt := metav1.NewTime(now)
+	return &v1alpha1.CanaryRolloutStatus{
+		Phase:            v1alpha1.CanaryRolloutProgressing,
+		Revision:         revision,
+		StableConfig:     previous.StableConfig,
+		StableOtelConfig: previous.StableOtelConfig,
+		Nodes:            canaryNodes,
+		StartedAt:        &startedAt,
+		Message:          fmt.Sprintf("the configs run on the canary nodes %s", strings.Join(canaryNodes, ", ")),
+	}, canaryRolloutPollInterval, nil
+}
+
+// bakeCanaryRollout checks the canary agents of a progressing rollout, rolling the change back when they failed, and
+// out to the other nodes once they stayed ready and healthy for the bake duration.
+func bakeCanaryRollout(ctx context.Context, c client.Client, scrape scrapeFunc, instance v1alpha1.AmazonCloudWatchAgent, status *v1alpha1.CanaryRolloutStatus, now time.Time) (*v1alpha1.CanaryRolloutStatus, time.Duration, error) {
+	bakeDuration, maxRestarts, maxExportFailures := canaryRolloutSpec(instance)
+
+	pods, err := canaryPods(ctx, c, instance, status.Revision)
+	if err != nil {
+		return nil, 0, err
+	}
+	if restarts := canaryRestarts(pods); restarts > maxRestarts {
+		return rolledBack(status, fmt.Sprintf("the canary agents restarted %d times", restarts)), 0, nil
+	}
+
+	ready, err := canaryReady(ctx, c, instance, status.Revision)
+	if err != nil {
+		return nil, 0, err
+	}
+	notReady := "the canary agents weren't ready"
+	if port, ok := collector.InternalMetricsPort(instance); ready && ok {
+		// the agents must also export their telemetry, which their readiness doesn't tell
+		totals, readable := canaryExportTotals(ctx, scrape, pods, port)
+		if readable && status.ReadySince == nil {
+			// the failures are counted from the start of the bake, not from the start of the agents
+			status.ExportBaseline = totals
+		} else if failures := canaryExportFailures(totals, status.ExportBaseline); readable && failures > float64(maxExportFailures) {
+			return rolledBack(status, fmt.Sprintf("the canary agents failed to export %.1f%% of their telemetry", failures)), 0, nil
+		}
+		ready = readable
+		notReady = "the internal metrics of the canary agents couldn't be read"
+	}
+	if !ready {
+		status.ReadySince = nil
+		status.ExportBaseline = nil
+		if status.StartedAt != nil && now.Sub(status.StartedAt.Time) >= bakeDuration {
+			return rolledBack(status, fmt.Sprintf("%s within %s", notReady, bakeDuration)), 0, nil
+		}
+		return status, canaryRolloutPollInterval, nil
+	}
+	if status.ReadySince == nil {
+		readySince := metav1.NewTime(now)
+		status.ReadySince = &readySince
+	}
+	baked := now.Sub(status.ReadySince.Time)
+	if baked >= bakeDuration {
+		return rolledOut(instance, status.Revision, fmt.Sprintf("the canary agents stayed ready and healthy for %s, the configs run on all the nodes", bakeDuration)), 0, nil
+	}
+	return status, min(canaryRolloutPollInterval, bakeDuration-baked), nil
+}
+
+// rolledOut returns the status of the configs of the instance running on all the nodes.
+func rolledOut(instance v1alpha1.AmazonCloudWatchAgent, revision, message string) *v1alpha1.CanaryRolloutStatus {
+	return &v1alpha1.CanaryRolloutStatus{
+		Phase:            v1alpha1.CanaryRolloutSucceeded,
+		Revision:         revision,
+		StableConfig:     instance.Spec.Config,
+		StableOtelConfig: instance.Spec.OtelConfig,
+		Message:          message,
+	}
+}
+
+// rolledBack returns the status of a change rolled back, all the nodes running the stable configs.
+func rolledBack(status *v1alpha1.CanaryRolloutStatus, message string) *v1alpha1.CanaryRolloutStatus {
+	return &v1alpha1.CanaryRolloutStatus{
+		Phase:            v1alpha1.CanaryRolloutRolledBack,
+		Revision:         status.Revision,
+		StableConfig:     status.StableConfig,
+		StableOtelConfig: status.StableOtelConfig,
+		StartedAt:        status.StartedAt,
+		Message:          message + ", the change is rolled back",
+	}
+}
+
+// canaryRolloutCondition returns the condition telling whether the configs run on all the agents.
+func canaryRolloutCondition(status *v1alpha1.CanaryRolloutStatus, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionTypeConfigRolledOut,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Message:            status.Message,
+	}
+	switch status.Phase {
+	case v1alpha1.CanaryRolloutSucceeded:
+		condition.Status, condition.Reason = metav1.ConditionTrue, reasonConfigRolledOut
+	case v1alpha1.CanaryRolloutRolledBack:
+		condition.Reason = reasonCanaryRolledBack
+	default:
+		condition.Reason = reasonCanaryProgressing
+	}
+	return condition
+}
+
+// canaryRolloutSpec returns the bake duration, the maximum number of restarts of the canary agents and the maximum
+// share of their telemetry they fail to export, defaulted.
+func canaryRolloutSpec(instance v1alpha1.AmazonCloudWatchAgent) (time.Duration, int32, int32) {
+	canary := instance.Spec.RolloutStrategy.Canary
+	if canary == nil {
+		return defaultCanaryBakeDuration, 0, defaultCanaryMaxExportFailurePercentage
+	}
+	bakeDuration := defaultCanaryBakeDuration
+	if canary.BakeDuration != nil {
+		bakeDuration = canary.BakeDuration.Duration
+	}
+	maxExportFailures := int32(defaultCanaryMaxExportFailurePercentage)
+	if canary.MaxExportFailurePercentage != nil {
+		maxExportFailures = *canary.MaxExportFailurePercentage
+	}
+	return bakeDuration, canary.MaxRestarts, maxExportFailures
+}
+
+// syncCanaryNodeLabels labels the canary nodes of a progressing rollout with the canary node label of the instance,
+// and removes it from the other nodes, once the rollout completes or is rolled back. The labels are set with merge
+// patches of the nodes, which takes the operator to patch the nodes cluster-wide: the nodes can't be granted by
+// name, since any of them may be picked as a canary node.
+func syncCanaryNodeLabels(ctx context.Context, c client.Client, reader client.Reader, instance v1alpha1.AmazonCloudWatchAgent, status *v1alpha1.CanaryRolloutStatus) error {
+	label := collector.CanaryNodeLabel(instance)
+	var canary []string
+	if status != nil && status.Phase == v1alpha1.CanaryRolloutProgressing {
+		canary = status.Nodes
+	}
+	labeled := &corev1.NodeList{}
+	if err := reader.List(ctx, labeled, client.HasLabels{label}); err != nil {
+		return fmt.Errorf("failed to list the canary nodes: %w", err)
+	}
+	isLabeled := map[string]bool{}
+	for _, node := range labeled.Items {
+		isLabeled[node.Name] = true
+		if !slices.Contains(canary, node.Name) {
+			if err := patchNodeLabel(ctx, c, node.Name, label, nil); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range canary {
+		if !isLabeled[name] {
+			if err := patchNodeLabel(ctx, c, name, label, "true"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// releaseCanaryNodes removes the canary node label of a deleted instance from the nodes.
+func releaseCanaryNodes(ctx context.Context, c client.Client, reader client.Reader, name types.NamespacedName) error {
+	instance := v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}}
+	return syncCanaryNodeLabels(ctx, c, reader, instance, nil)
+}
+
+// patchNodeLabel sets the label of the node to the given value, removing it when the value is nil.
+func patchNodeLabel(ctx context.Context, c client.Client, name, label string, value interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{label: value}},
+	})
+	if err != nil {
+		return err
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if err := c.Patch(ctx, node, client.RawPatch(types.MergePatchType, patch)); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to label the canary node %s: %w", name, err)
+	}
+	return nil
+}
+
+// selectCanaryNodes returns the first nodes the agents of the instance can be scheduled on, by name, as many as the
+// canary rollout asks for.
+func selectCanaryNodes(ctx context.Context, reader client.Reader, instance v1alpha1.AmazonCloudWatchAgent) ([]string, error) {
+	nodes := &corev1.NodeList{}
+	if err := reader.List(ctx, nodes, client.MatchingLabels(instance.Spec.NodeSelector)); err != nil {
+		return nil, fmt.Errorf("failed to list the nodes: %w", err)
+	}
+	var names []string
+	for _, node := range nodes.Items {
+		if schedulableAgentNode(node, instance) {
+			names = append(names, node.Name)
+		}
+	}
+	sort.Strings(names)
+	return names[:canaryNodeCount(instance.Spec.RolloutStrategy.Canary, len(names))], nil
+}
+
+// schedulableAgentNode tells whether the agents of the instance can be scheduled on the node: it is schedulable, its
+// taints are tolerated by the agents, and it matches their required node affinity.
+func schedulableAgentNode(node corev1.Node, instance v1alpha1.AmazonCloudWatchAgent) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !slices.ContainsFunc(instance.Spec.Tolerations, func(toleration corev1.Toleration) bool {
+			return toleration.ToleratesTaint(taint)
+		}) {
+			return false
+		}
+	}
+	affinity := instance.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// the terms are ORed
+	return slices.ContainsFunc(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, func(term corev1.NodeSelectorTerm) bool {
+		return nodeSelectorTermMatches(term, node)
+	})
+}
+
+// nodeSelectorTermMatches tells whether the node matches all the requirements of the term, an empty term matching
+// no node.
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	return nodeSelectorRequirementsMatch(term.MatchExpressions, node.Labels) &&
+		nodeSelectorRequirementsMatch(term.MatchFields, map[string]string{"metadata.name": node.Name})
+}
+
+// nodeSelectorRequirementsMatch tells whether the given labels or fields match all the requirements.
+func nodeSelectorRequirementsMatch(requirements []corev1.NodeSelectorRequirement, set map[string]string) bool {
+	for _, requirement := range requirements {
+		var operator selection.Operator
+		switch requirement.Operator {
+		case corev1.NodeSelectorOpIn:
+			operator = selection.In
+		case corev1.NodeSelectorOpNotIn:
+			operator = selection.NotIn
+		case corev1.NodeSelectorOpExists:
+			operator = selection.Exists
+		case corev1.NodeSelectorOpDoesNotExist:
+			operator = selection.DoesNotExist
+		case corev1.NodeSelectorOpGt:
+			operator = selection.GreaterThan
+		case corev1.NodeSelectorOpLt:
+			operator = selection.LessThan
+		}
+		req, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil || !req.Matches(labels.Set(set)) {
+			return false
+		}
+	}
+	return true
+}
+
+// canaryNodeCount returns the number of canary nodes out of the given number of nodes: the percentage of them,
+// rounded up and capped by the maximum, or the maximum, which defaults to 1.
+func canaryNodeCount(canary *v1alpha1.CanaryRolloutSpec, total int) int {
+	count := 1
+	if canary != nil {
+		if canary.Percentage > 0 {
+			count = (total*int(canary.Percentage) + 99) / 100
+			if canary.MaxCanaryNodes > 0 {
+				count = min(count, int(canary.MaxCanaryNodes))
+			}
+		} else if canary.MaxCanaryNodes > 0 {
+			count = int(canary.MaxCanaryNodes)
+		}
+	}
+	return min(count, total)
+}
+
+// canaryPods returns the canary pods running the given revision.
+func canaryPods(ctx context.Context, c client.Client, instance v1alpha1.AmazonCloudWatchAgent, revision string) ([]corev1.Pod, error) {
+	selector := manifestutils.SelectorLabels(instance.ObjectMeta, collector.ComponentAmazonCloudWatchAgent)
+	selector[constants.LabelCohort] = collector.CohortCanary
+	selector[constants.LabelConfigRevision] = revision
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(instance.Namespace), client.MatchingLabels(selector)); err != nil {
+		return nil, fmt.Errorf("failed to list the canary pods: %w", err)
+	}
+	return pods.Items, nil
+}
+
+// canaryRestarts counts the restarts of the agent containers of the canary pods.
+func canaryRestarts(pods []corev1.Pod) int32 {
+	var restarts int32
+	for _, pod := range pods {
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Name == naming.Container() {
+				restarts += container.RestartCount
+			}
+		}
+	}
+	return restarts
+}
+
+// canaryExportTotals returns the telemetry each canary agent sent and failed to send, by pod, read from the exporter
+// metrics they serve on the given port, and whether the metrics of all of them could be read.
+func canaryExportTotals(ctx context.Context, scrape scrapeFunc, pods []corev1.Pod, port int32) (map[string]v1alpha1.CanaryExportTotals, bool) {
+	if len(pods) == 0 {
+		return nil, false
+	}
+	totals := map[string]v1alpha1.CanaryExportTotals{}
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" {
+			return nil, false
+		}
+		metrics, err := scrape(ctx, fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port)))))
+		if err != nil {
+			return nil, false
+		}
+		sent, failed, err := exporterTotals(metrics)
+		if err != nil {
+			return nil, false
+		}
+		totals[pod.Name] = v1alpha1.CanaryExportTotals{Sent: int64(sent), Failed: int64(failed)}
+	}
+	return totals, true
+}
+
+// canaryExportFailures returns the share of the telemetry the canary agents failed to export since the baseline, in
+// percent. The agents started since the baseline, or whose counters were reset by a restart, count from zero.
+func canaryExportFailures(totals, baseline map[string]v1alpha1.CanaryExportTotals) float64 {
+	var sent, failed int64
+	for pod, total := range totals {
+		base, ok := baseline[pod]
+		if !ok || total.Sent < base.Sent || total.Failed < base.Failed {
+			base = v1alpha1.CanaryExportTotals{}
+		}
+		sent, failed = sent+total.Sent-base.Sent, failed+total.Failed-base.Failed
+	}
+	if sent+failed == 0 {
+		return 0
+	}
+	return 100 * float64(failed) / float64(sent+failed)
+}
+
+// exporterTotals sums the telemetry the exporters of an agent sent and failed to send, out of its internal metrics.
+func exporterTotals(metrics []byte) (float64, float64, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(metrics))
+	if err != nil {
+		return 0, 0, err
+	}
+	var sent, failed float64
+	for name, family := range families {
+		var total float64
+		for _, metric := range family.GetMetric() {
+			total += metric.GetCounter().GetValue() + metric.GetUntyped().GetValue()
+		}
+		// the newer collectors suffix their counters with _total
+		switch name = strings.TrimSuffix(name, "_total"); {
+		case strings.HasPrefix(name, "otelcol_exporter_sent_"):
+			sent += total
+		case strings.HasPrefix(name, "otelcol_exporter_send_failed_"):
+			failed += total
+		}
+	}
+	return sent, failed, nil
+}
+
+// canaryScrapeClient reads the internal metrics of the canary agents, giving up on the agents which don't answer.
+var canaryScrapeClient = &http.Client{Timeout: canaryScrapeTimeout}
+
+// scrapeMetrics reads the metrics served at the given URL, until the context of the reconciliation is done.
+func scrapeMetrics(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := canaryScrapeClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// canaryReady tells whether the canary daemonset runs the given revision on all of its nodes, all of its agents
+// ready.
+func canaryReady(ctx context.Context, c client.Client, instance v1alpha1.AmazonCloudWatchAgent, revision string) (bool, error) {
+	ds := &appsv1.DaemonSet{}
+	key := client.ObjectKey{Namespace: instance.Namespace, Name: naming.CanaryCollector(instance.ResourceName())}
+	if err := c.Get(ctx, key, ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			// created by this reconcile
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get the canary daemonSet: %w", err)
+	}
+	return ds.Spec.Template.Labels[constants.LabelConfigRevision] == revision &&
+		ds.Status.ObservedGeneration >= ds.Generation &&
+		ds.Status.DesiredNumberScheduled > 0 &&
+		ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberReady == ds.Status.DesiredNumberScheduled, nil
+}
+
+// configRevision identifies the given configs.
+func configRevision(config, otelConfig string) string {
+	h := sha256.Sum256([]byte(config + "\x00" + otelConfig))
+	return hex.EncodeToString(h[:])[:16]
+}
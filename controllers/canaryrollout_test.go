@@ -0,0 +1,386 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestReconcileCanaryRollout(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	node := func(name string, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/os": "linux"}},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		}
+	}
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:         v1alpha1.ModeDaemonSet,
+			Config:       `{"agent":{"interval":"60s"}}`,
+			NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+			RolloutStrategy: &v1alpha1.RolloutStrategySpec{
+				Type:   v1alpha1.RolloutStrategyCanary,
+				Canary: &v1alpha1.CanaryRolloutSpec{BakeDuration: &metav1.Duration{Duration: 10 * time.Minute}},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.AmazonCloudWatchAgent{}).
+		WithObjects(agent, node("node-c", false), node("node-a", true), node("node-b", false)).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	reconcile := func(now time.Time) (v1alpha1.AmazonCloudWatchAgent, time.Duration) {
+		var instance v1alpha1.AmazonCloudWatchAgent
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), &instance))
+		instance, requeueAfter, err := reconcileCanaryRollout(ctx, c, c, nil, recorder, instance, now)
+		require.NoError(t, err)
+		return instance, requeueAfter
+	}
+	canaryNodes := func() []string {
+		nodes := &corev1.NodeList{}
+		require.NoError(t, c.List(ctx, nodes, client.HasLabels{collector.CanaryNodeLabel(*agent)}))
+		var names []string
+		for _, node := range nodes.Items {
+			names = append(names, node.Name)
+		}
+		return names
+	}
+	changeConfig := func(config string) {
+		var instance v1alpha1.AmazonCloudWatchAgent
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), &instance))
+		instance.Spec.Config = config
+		require.NoError(t, c.Update(ctx, &instance))
+	}
+	canaryRollsOut := func(revision string, ready int32, restarts int32) {
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-canary", Namespace: "amazon-cloudwatch"},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{constants.LabelConfigRevision: revision},
+			}}},
+		}
+		_ = c.Delete(ctx, ds)
+		ds.Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, UpdatedNumberScheduled: 1, NumberReady: ready}
+		require.NoError(t, c.Create(ctx, ds))
+
+		labels := manifestutils.SelectorLabels(agent.ObjectMeta, collector.ComponentAmazonCloudWatchAgent)
+		labels[constants.LabelCohort] = collector.CohortCanary
+		labels[constants.LabelConfigRevision] = revision
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-canary-" + revision, Namespace: "amazon-cloudwatch", Labels: labels},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "otc-container", RestartCount: restarts},
+			}},
+		}
+		_ = c.Delete(ctx, pod)
+		require.NoError(t, c.Create(ctx, pod))
+	}
+
+	// the first configs run on all the nodes
+	instance, requeueAfter := reconcile(start)
+	assert.Zero(t, requeueAfter)
+	assert.Equal(t, v1alpha1.CanaryRolloutSucceeded, instance.Status.CanaryRollout.Phase)
+	assert.Equal(t, `{"agent":{"interval":"60s"}}`, instance.Status.CanaryRollout.StableConfig)
+	assert.True(t, meta.IsStatusConditionTrue(instance.Status.Conditions, v1alpha1.ConditionTypeConfigRolledOut))
+
+	// a change runs on the first schedulable node
+	changeConfig(`{"agent":{"interval":"30s"}}`)
+	instance, requeueAfter = reconcile(start)
+	rollout := instance.Status.CanaryRollout
+	assert.Equal(t, canaryRolloutPollInterval, requeueAfter)
+	assert.Equal(t, v1alpha1.CanaryRolloutProgressing, rollout.Phase)
+	assert.Equal(t, []string{"node-b"}, rollout.Nodes)
+	assert.Equal(t, `{"agent":{"interval":"60s"}}`, rollout.StableConfig)
+	assert.False(t, meta.IsStatusConditionTrue(instance.Status.Conditions, v1alpha1.ConditionTypeConfigRolledOut))
+	revision := rollout.Revision
+	// which carries the canary node label the daemonset of the instance avoids
+	assert.Equal(t, []string{"node-b"}, canaryNodes())
+
+	// the bake starts once the canary agents are ready
+	canaryRollsOut(revision, 0, 0)
+	instance, _ = reconcile(start.Add(time.Minute))
+	assert.Nil(t, instance.Status.CanaryRollout.ReadySince)
+	canaryRollsOut(revision, 1, 0)
+	instance, _ = reconcile(start.Add(2 * time.Minute))
+	require.NotNil(t, instance.Status.CanaryRollout.ReadySince)
+	instance, requeueAfter = reconcile(start.Add(11*time.Minute + 50*time.Second))
+	assert.Equal(t, v1alpha1.CanaryRolloutProgressing, instance.Status.CanaryRollout.Phase)
+	assert.Equal(t, 10*time.Second, requeueAfter)
+
+	// and the change runs on all the nodes after the bake duration
+	instance, _ = reconcile(start.Add(12 * time.Minute))
+	rollout = instance.Status.CanaryRollout
+	assert.Equal(t, v1alpha1.CanaryRolloutSucceeded, rollout.Phase)
+	assert.Equal(t, `{"agent":{"interval":"30s"}}`, rollout.StableConfig)
+	assert.Empty(t, rollout.Nodes)
+	assert.Empty(t, canaryNodes())
+	assert.True(t, meta.IsStatusConditionTrue(instance.Status.Conditions, v1alpha1.ConditionTypeConfigRolledOut))
+
+	// a change whose canary agents restart is rolled back
+	changeConfig(`{"agent":{"interval":"1s"}}`)
+	instance, _ = reconcile(start.Add(20 * time.Minute))
+	revision = instance.Status.CanaryRollout.Revision
+	canaryRollsOut(revision, 0, 1)
+	instance, _ = reconcile(start.Add(21 * time.Minute))
+	rollout = instance.Status.CanaryRollout
+	assert.Equal(t, v1alpha1.CanaryRolloutRolledBack, rollout.Phase)
+	assert.Equal(t, `{"agent":{"interval":"30s"}}`, rollout.StableConfig)
+	assert.Equal(t, "the canary agents restarted 1 times, the change is rolled back", rollout.Message)
+	assert.Empty(t, canaryNodes())
+	condition := meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionTypeConfigRolledOut)
+	assert.Equal(t, reasonCanaryRolledBack, condition.Reason)
+
+	// and held until the configs change again
+	instance, _ = reconcile(start.Add(30 * time.Minute))
+	assert.Equal(t, v1alpha1.CanaryRolloutRolledBack, instance.Status.CanaryRollout.Phase)
+	changeConfig(`{"agent":{"interval":"30s"}}`)
+	instance, _ = reconcile(start.Add(31 * time.Minute))
+	assert.Equal(t, v1alpha1.CanaryRolloutSucceeded, instance.Status.CanaryRollout.Phase)
+
+	// a change whose canary agents don't get ready is rolled back after the bake duration
+	changeConfig(`{"agent":{"interval":"2s"}}`)
+	instance, _ = reconcile(start.Add(40 * time.Minute))
+	canaryRollsOut(instance.Status.CanaryRollout.Revision, 0, 0)
+	instance, _ = reconcile(start.Add(49 * time.Minute))
+	assert.Equal(t, v1alpha1.CanaryRolloutProgressing, instance.Status.CanaryRollout.Phase)
+	instance, _ = reconcile(start.Add(50 * time.Minute))
+	assert.Equal(t, v1alpha1.CanaryRolloutRolledBack, instance.Status.CanaryRollout.Phase)
+
+	// the status is cleared along with the strategy
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), &instance))
+	instance.Spec.RolloutStrategy = nil
+	require.NoError(t, c.Update(ctx, &instance))
+	instance, _ = reconcile(start.Add(time.Hour))
+	assert.Nil(t, instance.Status.CanaryRollout)
+	assert.Nil(t, meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionTypeConfigRolledOut))
+
+	// the canary nodes of a deleted instance are released
+	require.NoError(t, patchNodeLabel(ctx, c, "node-b", collector.CanaryNodeLabel(*agent), "true"))
+	assert.Equal(t, []string{"node-b"}, canaryNodes())
+	require.NoError(t, releaseCanaryNodes(ctx, c, c, client.ObjectKeyFromObject(agent)))
+	assert.Empty(t, canaryNodes())
+}
+
+func TestCanaryRolloutExportFailures(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:            v1alpha1.ModeDaemonSet,
+			OtelConfig:      "service:\n  telemetry:\n    metrics:\n      address: localhost:8899\n",
+			RolloutStrategy: &v1alpha1.RolloutStrategySpec{Type: v1alpha1.RolloutStrategyCanary},
+		},
+	}
+	revision := "0123456789abcdef"
+	labels := manifestutils.SelectorLabels(agent.ObjectMeta, collector.ComponentAmazonCloudWatchAgent)
+	labels[constants.LabelCohort] = collector.CohortCanary
+	labels[constants.LabelConfigRevision] = revision
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-canary", Namespace: "amazon-cloudwatch"},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{constants.LabelConfigRevision: revision},
+			}}},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, UpdatedNumberScheduled: 1, NumberReady: 1},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-canary-a", Namespace: "amazon-cloudwatch", Labels: labels},
+			Status:     corev1.PodStatus{PodIP: "10.0.0.1"},
+		},
+	).Build()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	progressing := func(baseline map[string]v1alpha1.CanaryExportTotals) *v1alpha1.CanaryRolloutStatus {
+		startedAt := metav1.NewTime(start)
+		status := &v1alpha1.CanaryRolloutStatus{Phase: v1alpha1.CanaryRolloutProgressing, Revision: revision, StartedAt: &startedAt}
+		if baseline != nil {
+			status.ReadySince = &startedAt
+			status.ExportBaseline = baseline
+		}
+		return status
+	}
+
+	for _, tt := range []struct {
+		name     string
+		metrics  string
+		err      error
+		baseline map[string]v1alpha1.CanaryExportTotals
+		now      time.Time
+		phase    v1alpha1.CanaryRolloutPhase
+		message  string
+	}{
+		{
+			name:    "baseline taken when the bake starts",
+			metrics: "otelcol_exporter_sent_spans 90\notelcol_exporter_send_failed_spans 10\n",
+			now:     start.Add(time.Minute),
+			phase:   v1alpha1.CanaryRolloutProgressing,
+		},
+		{
+			name:     "healthy",
+			metrics:  "otelcol_exporter_sent_metric_points_total{exporter=\"awsemf\"} 980\notelcol_exporter_send_failed_metric_points_total{exporter=\"awsemf\"} 20\n",
+			baseline: map[string]v1alpha1.CanaryExportTotals{"agent-canary-a": {Sent: 490, Failed: 10}},
+			now:      start.Add(time.Minute),
+			phase:    v1alpha1.CanaryRolloutProgressing,
+		},
+		{
+			name:     "failures before the bake",
+			metrics:  "otelcol_exporter_sent_spans 180\notelcol_exporter_send_failed_spans 10\n",
+			baseline: map[string]v1alpha1.CanaryExportTotals{"agent-canary-a": {Sent: 90, Failed: 10}},
+			now:      start.Add(time.Minute),
+			phase:    v1alpha1.CanaryRolloutProgressing,
+		},
+		{
+			name:     "failing to export during the bake",
+			metrics:  "otelcol_exporter_sent_spans 90\notelcol_exporter_send_failed_spans 10\n",
+			baseline: map[string]v1alpha1.CanaryExportTotals{"agent-canary-a": {Sent: 50}},
+			now:      start.Add(time.Minute),
+			phase:    v1alpha1.CanaryRolloutRolledBack,
+			message:  "the canary agents failed to export 20.0% of their telemetry, the change is rolled back",
+		},
+		{
+			name:     "failing to export after a restart",
+			metrics:  "otelcol_exporter_sent_spans 90\notelcol_exporter_send_failed_spans 10\n",
+			baseline: map[string]v1alpha1.CanaryExportTotals{"agent-canary-a": {Sent: 1000}},
+			now:      start.Add(time.Minute),
+			phase:    v1alpha1.CanaryRolloutRolledBack,
+			message:  "the canary agents failed to export 10.0% of their telemetry, the change is rolled back",
+		},
+		{
+			name:  "unreadable metrics",
+			err:   errors.New("connection refused"),
+			now:   start.Add(time.Minute),
+			phase: v1alpha1.CanaryRolloutProgressing,
+		},
+		{
+			name:    "unreadable metrics after the bake duration",
+			err:     errors.New("connection refused"),
+			now:     start.Add(10 * time.Minute),
+			phase:   v1alpha1.CanaryRolloutRolledBack,
+			message: "the internal metrics of the canary agents couldn't be read within 10m0s, the change is rolled back",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var scraped string
+			scrape := func(_ context.Context, url string) ([]byte, error) {
+				scraped = url
+				return []byte(tt.metrics), tt.err
+			}
+			status, _, err := bakeCanaryRollout(ctx, c, scrape, agent, progressing(tt.baseline), tt.now)
+			require.NoError(t, err)
+			assert.Equal(t, "http://10.0.0.1:8899/metrics", scraped)
+			assert.Equal(t, tt.phase, status.Phase)
+			if tt.message != "" {
+				assert.Equal(t, tt.message, status.Message)
+			}
+			if tt.baseline == nil && tt.err == nil {
+				assert.Equal(t, map[string]v1alpha1.CanaryExportTotals{"agent-canary-a": {Sent: 90, Failed: 10}}, status.ExportBaseline)
+				assert.NotNil(t, status.ReadySince)
+			}
+		})
+	}
+}
+
+func TestSchedulableAgentNode(t *testing.T) {
+	gpu := corev1.Taint{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}
+	zone := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us-west-2a"}}}},
+			{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"pinned"}}}},
+		}},
+	}}
+	for _, tt := range []struct {
+		name        string
+		node        corev1.Node
+		tolerations []corev1.Toleration
+		affinity    *corev1.Affinity
+		want        bool
+	}{
+		{name: "schedulable", want: true},
+		{name: "cordoned", node: corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}}},
+		{name: "tainted", node: corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{gpu}}}},
+		{
+			name:        "taint tolerated",
+			node:        corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{gpu}}},
+			tolerations: []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+			want:        true,
+		},
+		{
+			name: "taint preferably avoided",
+			node: corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}}}},
+			want: true,
+		},
+		{
+			name:     "matching the affinity",
+			node:     corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"topology.kubernetes.io/zone": "us-west-2a"}}},
+			affinity: zone,
+			want:     true,
+		},
+		{
+			name:     "matching the affinity by name",
+			node:     corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "pinned"}},
+			affinity: zone,
+			want:     true,
+		},
+		{
+			name:     "out of the affinity",
+			node:     corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"topology.kubernetes.io/zone": "us-west-2b"}}},
+			affinity: zone,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := v1alpha1.AmazonCloudWatchAgent{Spec: v1alpha1.AmazonCloudWatchAgentSpec{Tolerations: tt.tolerations, Affinity: tt.affinity}}
+			assert.Equal(t, tt.want, schedulableAgentNode(tt.node, instance))
+		})
+	}
+}
+
+func TestCanaryNodeCount(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		canary *v1alpha1.CanaryRolloutSpec
+		total  int
+		want   int
+	}{
+		{name: "default", total: 10, want: 1},
+		{name: "maximum", canary: &v1alpha1.CanaryRolloutSpec{MaxCanaryNodes: 3}, total: 10, want: 3},
+		{name: "percentage rounded up", canary: &v1alpha1.CanaryRolloutSpec{Percentage: 15}, total: 10, want: 2},
+		{name: "percentage capped", canary: &v1alpha1.CanaryRolloutSpec{Percentage: 50, MaxCanaryNodes: 2}, total: 10, want: 2},
+		{name: "more than the nodes", canary: &v1alpha1.CanaryRolloutSpec{MaxCanaryNodes: 5}, total: 2, want: 2},
+		{name: "no node", total: 0, want: 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, canaryNodeCount(tt.canary, tt.total))
+		})
+	}
+}
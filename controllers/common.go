@@ -10,13 +10,15 @@ import (
 	"strings"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
@@ -24,14 +26,25 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/targetallocator"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 )
 
 const (
 	acceleratedComputeMetrics = "accelerated_compute_metrics"
 	amazonCloudWatchNamespace = "amazon-cloudwatch"
 	amazonCloudWatchAgentName = "cloudwatch-agent"
+
+	// controller names used to label the operator metrics
+	amazonCloudWatchAgentController = "AmazonCloudWatchAgent"
+	dcgmExporterController          = "DcgmExporter"
+	neuronMonitorController         = "NeuronMonitor"
+	caBundleController              = "CABundle"
 )
 
+// errTargetAllocatorDisabled is returned when an agent enables the target allocator the operator doesn't deploy.
+var errTargetAllocatorDisabled = errors.New("the target allocator is disabled in the operator")
+
 func isNamespaceScoped(obj client.Object) bool {
 	switch obj.(type) {
 	case *rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding:
@@ -41,15 +54,39 @@ func isNamespaceScoped(obj client.Object) bool {
 	}
 }
 
+// objectKind returns the kind of the given object as registered in the scheme.
+func objectKind(obj client.Object, scheme *runtime.Scheme) string {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return "unknown"
+	}
+	return gvk.Kind
+}
+
+// recordDefaultsApplied patches the given CR with the values defaulted by the reconciler, so that they can be told
+// apart from the user provided ones. The fields map the fields the reconciler defaults to their current values.
+func recordDefaultsApplied(ctx context.Context, kubeClient client.Client, obj client.Object, fields, defaults map[string]string) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	if !v1alpha1.RecordDefaultsApplied(obj, fields, defaults) {
+		return nil
+	}
+	return kubeClient.Patch(ctx, obj, patch)
+}
+
 // BuildCollector returns the generation and collected errors of all manifests for a given instance.
-func BuildCollector(params manifests.Params) ([]client.Object, error) {
-	builders := []manifests.Builder{
-		collector.Build,
-		targetallocator.Build,
+func BuildCollector(ctx context.Context, params manifests.Params) ([]client.Object, error) {
+	// the scrape configs of the control plane are part of the Prometheus configuration of the agent and its
+	// target allocator
+	params.OtelCol = collector.WithControlPlaneMetrics(params.OtelCol)
+	builders := []manifests.Builder{collector.Build}
+	if params.Config.TargetAllocatorEnabled() {
+		builders = append(builders, targetallocator.Build)
+	} else if params.OtelCol.Spec.TargetAllocator.Enabled {
+		return nil, errTargetAllocatorDisabled
 	}
 	var resources []client.Object
 	for _, builder := range builders {
-		objs, err := builder(params)
+		objs, err := builder(ctx, params)
 		if err != nil {
 			return nil, err
 		}
@@ -57,11 +94,13 @@ func BuildCollector(params manifests.Params) ([]client.Object, error) {
 	}
 	return resources, nil
 }
-func reconcileDesiredObjectUIDs(ctx context.Context, kubeClient client.Client, logger logr.Logger,
-	owner metav1.Object, scheme *runtime.Scheme, desiredObjects ...client.Object) (map[types.UID]client.Object, error) {
+func reconcileDesiredObjectUIDs(ctx context.Context, kubeClient client.Client, logger logr.Logger, recorder record.EventRecorder,
+	owner client.Object, scheme *runtime.Scheme, desiredObjects ...client.Object) (map[types.UID]client.Object, error) {
 	var errs []error
 	existingObjectMap := make(map[types.UID]client.Object)
 	var existingObjectList []client.Object
+	// the updates following a change of the owner apply the change, they aren't counted as drift corrections
+	drifted := generationReconciled(owner)
 
 	for _, desired := range desiredObjects {
 		l := logger.WithValues(
@@ -81,38 +120,70 @@ func reconcileDesiredObjectUIDs(ctx context.Context, kubeClient client.Client, l
 		existing := desired.DeepCopyObject().(client.Object)
 		existingObjectList = append(existingObjectList, existing) //uid are not assigned yet
 
-		mutateFn := manifests.MutateFuncFor(existing, desired)
 		var op controllerutil.OperationResult
-		crudErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			result, createOrUpdateErr := ctrl.CreateOrUpdate(ctx, kubeClient, existing, mutateFn)
-			op = result
-			return createOrUpdateErr
-		})
-		if crudErr != nil && errors.Is(crudErr, manifests.ImmutableChangeErr) {
+		var before runtime.Object
+		var crudErr error
+		if featuregate.ServerSideApply.IsEnabled() {
+			op, before, crudErr = applyDesiredObject(ctx, kubeClient, scheme, owner, desired, existing)
+		} else {
+			mutateFn := manifests.MutateFuncFor(existing, desired)
+			crudErr = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				result, createOrUpdateErr := ctrl.CreateOrUpdate(ctx, kubeClient, existing, func() error {
+					// never take over an object with the same name created by another tool
+					if err := manifests.VerifyOwnership(existing, owner); err != nil {
+						return fmt.Errorf("refusing to update %s %s: %w", objectKind(desired, scheme), client.ObjectKeyFromObject(desired), err)
+					}
+					before = existing.DeepCopyObject()
+					return mutateFn()
+				})
+				op = result
+				return createOrUpdateErr
+			})
+		}
+		kind := objectKind(desired, scheme)
+		if crudErr != nil && errors.Is(crudErr, manifests.ErrNotOwned) {
+			l.Error(crudErr, "existing object is not managed by the operator, leaving it untouched")
+			recordEvent(recorder, owner, corev1.EventTypeWarning, "Failed", "Failed to configure %s %s: %v", kind, desired.GetName(), crudErr)
+			errs = append(errs, crudErr)
+			continue
+		} else if crudErr != nil && errors.Is(crudErr, manifests.ImmutableChangeErr) {
 			l.Error(crudErr, "detected immutable field change, trying to delete, new object will be created on next reconcile", "existing", existing.GetName())
 			delErr := kubeClient.Delete(ctx, existing)
 			if delErr != nil {
+				recordEvent(recorder, owner, corev1.EventTypeWarning, "Failed", "Failed to delete %s %s: %v", kind, existing.GetName(), delErr)
 				return nil, delErr
 			}
+			recordEvent(recorder, owner, corev1.EventTypeNormal, "Deleted", "Deleted %s %s to recreate it with a changed immutable field", kind, existing.GetName())
 			continue
 		} else if crudErr != nil {
 			l.Error(crudErr, "failed to configure desired")
+			recordEvent(recorder, owner, corev1.EventTypeWarning, "Failed", "Failed to configure %s %s: %v", kind, desired.GetName(), crudErr)
 			errs = append(errs, crudErr)
 			continue
 		}
 
+		switch op { // nolint:exhaustive
+		case controllerutil.OperationResultCreated:
+			recordEvent(recorder, owner, corev1.EventTypeNormal, "Created", "Created %s %s", kind, existing.GetName())
+		case controllerutil.OperationResultUpdated:
+			if drifted {
+				metrics.DriftCorrections.WithLabelValues(kind).Inc()
+			}
+			recordUpdateDiff(l, recorder, owner, kind, before, existing)
+		}
 		l.V(1).Info(fmt.Sprintf("desired has been %s", op))
 	}
 	if len(errs) > 0 {
 		return nil, fmt.Errorf("failed to create objects for %s: %w", owner.GetName(), errors.Join(errs...))
 	}
+	recordReconciledGeneration(owner)
 	for _, obj := range existingObjectList {
 		existingObjectMap[obj.GetUID()] = obj
 	}
 	return existingObjectMap, nil
 }
 
-func reconcileDesiredObjectsWPrune(ctx context.Context, kubeClient client.Client, logger logr.Logger, owner v1alpha1.AmazonCloudWatchAgent, scheme *runtime.Scheme,
+func reconcileDesiredObjectsWPrune(ctx context.Context, kubeClient client.Client, logger logr.Logger, recorder record.EventRecorder, owner v1alpha1.AmazonCloudWatchAgent, scheme *runtime.Scheme,
 	desiredObjects []client.Object,
 	searchOwnedObjectsFunc func(ctx context.Context, owner v1alpha1.AmazonCloudWatchAgent) (map[types.UID]client.Object, error),
 ) error {
@@ -121,13 +192,13 @@ func reconcileDesiredObjectsWPrune(ctx context.Context, kubeClient client.Client
 		return fmt.Errorf("failed to search owned objects: %w", err)
 	}
 
-	desiredObjectMap, err := reconcileDesiredObjectUIDs(ctx, kubeClient, logger, &owner, scheme, desiredObjects...)
+	desiredObjectMap, err := reconcileDesiredObjectUIDs(ctx, kubeClient, logger, recorder, &owner, scheme, desiredObjects...)
 	if err != nil {
 		return fmt.Errorf("failed to reconcile desired objects: %w", err)
 	}
 
 	// Pruning owned objects in the cluster which are not should not be present after the reconciliation.
-	err = pruneStaleObjects(ctx, kubeClient, logger, previouslyOwnedObjects, desiredObjectMap)
+	err = pruneStaleObjects(ctx, kubeClient, logger, recorder, &owner, scheme, previouslyOwnedObjects, desiredObjectMap)
 	if err != nil {
 		return fmt.Errorf("failed to prune objects for %s: %w", owner.GetName(), err)
 	}
@@ -135,12 +206,40 @@ func reconcileDesiredObjectsWPrune(ctx context.Context, kubeClient client.Client
 }
 
 // reconcileDesiredObjects runs the reconcile process using the mutateFn over the given list of objects.
-func reconcileDesiredObjects(ctx context.Context, kubeClient client.Client, logger logr.Logger, owner metav1.Object, scheme *runtime.Scheme, desiredObjects ...client.Object) error {
-	_, err := reconcileDesiredObjectUIDs(ctx, kubeClient, logger, owner, scheme, desiredObjects...)
+func reconcileDesiredObjects(ctx context.Context, kubeClient client.Client, logger logr.Logger, recorder record.EventRecorder, owner client.Object, scheme *runtime.Scheme, desiredObjects ...client.Object) error {
+	_, err := reconcileDesiredObjectUIDs(ctx, kubeClient, logger, recorder, owner, scheme, desiredObjects...)
 	return err
 }
 
-func pruneStaleObjects(ctx context.Context, kubeClient client.Client, logger logr.Logger, previouslyOwnedMap, desiredMap map[types.UID]client.Object) error {
+// recordUpdateDiff logs the fields changed by the update of an object and records them in an event on its owner, to
+// explain why the operator keeps rewriting an object changed by someone else.
+func recordUpdateDiff(logger logr.Logger, recorder record.EventRecorder, owner client.Object, kind string, before, after runtime.Object) {
+	if before == nil {
+		return
+	}
+	changes, err := objectDiff(before, after)
+	if err != nil {
+		logger.V(1).Info("unable to compute the changes of the update", "err", err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+	logger.V(1).Info("updated the changed fields", "diff", diffDetails(changes))
+	recordEvent(recorder, owner, corev1.EventTypeNormal, "Updated", "Updated %s %s: %s", kind, after.(client.Object).GetName(), diffSummary(changes))
+}
+
+// recordEvent records an event on the owner of the reconciled objects, so that describing the owner tells which of
+// its objects were created, updated, deleted or failed to be.
+func recordEvent(recorder record.EventRecorder, owner client.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if recorder != nil {
+		recorder.Eventf(owner, eventType, reason, messageFmt, args...)
+	}
+}
+
+func pruneStaleObjects(ctx context.Context, kubeClient client.Client, logger logr.Logger, recorder record.EventRecorder, owner client.Object, scheme *runtime.Scheme,
+	previouslyOwnedMap, desiredMap map[types.UID]client.Object,
+) error {
 	// Pruning owned objects in the cluster which should not be present after the reconciliation.
 	var pruneErrs []error
 	for uid, obj := range previouslyOwnedMap {
@@ -156,17 +255,26 @@ func pruneStaleObjects(ctx context.Context, kubeClient client.Client, logger log
 		err := kubeClient.Delete(ctx, obj)
 		if err != nil {
 			l.Error(err, "failed to delete resource")
+			recordEvent(recorder, owner, corev1.EventTypeWarning, "Failed", "Failed to delete %s %s: %v", objectKind(obj, scheme), obj.GetName(), err)
 			pruneErrs = append(pruneErrs, err)
+			continue
 		}
+		recordEvent(recorder, owner, corev1.EventTypeNormal, "Deleted", "Deleted %s %s, which is no longer desired", objectKind(obj, scheme), obj.GetName())
 	}
 	return errors.Join(pruneErrs...)
 }
 
 func enabledAcceleratedComputeByAgentConfig(ctx context.Context, c client.Client, log logr.Logger) bool {
 	agentResource := getAmazonCloudWatchAgentResource(ctx, c)
+	return enabledAcceleratedCompute(agentResource.Spec.Config, log)
+}
+
+// enabledAcceleratedCompute tells whether the agent config collects the accelerated compute metrics of the enhanced
+// Container Insights.
+func enabledAcceleratedCompute(agentConfig string, log logr.Logger) bool {
 	// missing feature flag means it's on by default
-	featureConfigExists := strings.Contains(agentResource.Spec.Config, acceleratedComputeMetrics)
-	conf, err := adapters.ConfigStructFromJSONString(agentResource.Spec.Config)
+	featureConfigExists := strings.Contains(agentConfig, acceleratedComputeMetrics)
+	conf, err := adapters.ConfigStructFromJSONString(agentConfig)
 	if err != nil {
 		log.Error(err, "Failed to unmarshall agent configuration")
 		return false
@@ -7,12 +7,23 @@ import (
 	"context"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
 )
 
 func TestEnabledAcceleratedComputeByAgentConfig(t *testing.T) {
@@ -73,3 +84,159 @@ func TestEnabledAcceleratedComputeByAgentConfig(t *testing.T) {
 		assert.Equal(t, tc.expected, actual)
 	}
 }
+
+func TestBuildCollectorTargetAllocatorDisabled(t *testing.T) {
+	params := manifests.Params{
+		Config: config.New(config.WithTargetAllocatorEnabled(false)),
+		Log:    logf.Log.WithName("unit-tests"),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+			Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeStatefulSet, Config: "{}"},
+		},
+	}
+	objects, err := BuildCollector(context.Background(), params)
+	require.NoError(t, err)
+	assert.NotEmpty(t, objects)
+
+	params.OtelCol.Spec.TargetAllocator.Enabled = true
+	_, err = BuildCollector(context.Background(), params)
+	assert.ErrorIs(t, err, errTargetAllocatorDisabled)
+}
+
+func TestReconcileDesiredObjectsOwnership(t *testing.T) {
+	ctx := context.Background()
+	logger := logf.Log.WithName("unit-tests")
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	owner := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "agent-uid"}}
+	foreign := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "default", Labels: map[string]string{"app.kubernetes.io/managed-by": "Helm"}},
+		Data:       map[string]string{"key": "helm"},
+	}
+	managed := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "default", Labels: map[string]string{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"}},
+		Data:       map[string]string{"key": "old"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign, managed).Build()
+
+	desired := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data:       map[string]string{"key": "desired"},
+		}
+	}
+	err := reconcileDesiredObjects(ctx, c, logger, nil, owner, scheme, desired("foreign"), desired("managed"), desired("new"))
+	assert.ErrorIs(t, err, manifests.ErrNotOwned)
+
+	// the foreign object is left untouched while the others are reconciled
+	for name, expected := range map[string]string{"foreign": "helm", "managed": "desired", "new": "desired"} {
+		actual := &corev1.ConfigMap{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, actual))
+		assert.Equal(t, expected, actual.Data["key"], name)
+	}
+}
+
+func TestReconcileDesiredObjectsRecordsDiff(t *testing.T) {
+	ctx := context.Background()
+	logger := logf.Log.WithName("unit-tests")
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	owner := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "agent-uid"}}
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "default", Labels: map[string]string{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"}},
+		Data:       map[string]string{"key": "changed by hand"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+	recorder := record.NewFakeRecorder(10)
+
+	desired := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "default"},
+		Data:       map[string]string{"key": "desired"},
+	}
+	require.NoError(t, reconcileDesiredObjects(ctx, c, logger, recorder, owner, scheme, desired))
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal Updated Updated ConfigMap managed: data.key, metadata.ownerReferences", <-recorder.Events)
+
+	// nothing is recorded when the object is already up to date
+	require.NoError(t, reconcileDesiredObjects(ctx, c, logger, recorder, owner, scheme, desired.DeepCopy()))
+	assert.Empty(t, recorder.Events)
+}
+
+func TestReconcileDesiredObjectsRecordsEvents(t *testing.T) {
+	ctx := context.Background()
+	logger := logf.Log.WithName("unit-tests")
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	owner := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "agent-uid"}}
+	foreign := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "default", Labels: map[string]string{"app.kubernetes.io/managed-by": "Helm"}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign).Build()
+	recorder := record.NewFakeRecorder(10)
+
+	desired := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+	err := reconcileDesiredObjects(ctx, c, logger, recorder, owner, scheme, desired("foreign"), desired("new"))
+	assert.ErrorIs(t, err, manifests.ErrNotOwned)
+	require.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, "Warning Failed Failed to configure ConfigMap foreign: refusing to update ConfigMap default/foreign")
+	assert.Equal(t, "Normal Created Created ConfigMap new", <-recorder.Events)
+
+	// the objects no longer desired are pruned
+	created := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "new"}, created))
+	owned := map[types.UID]client.Object{created.UID: created}
+	require.NoError(t, pruneStaleObjects(ctx, c, logger, recorder, owner, scheme, owned, nil))
+	assert.Equal(t, "Normal Deleted Deleted ConfigMap new, which is no longer desired", <-recorder.Events)
+}
+
+func TestReconcileDesiredObjectsCountsDrifts(t *testing.T) {
+	ctx := context.Background()
+	logger := logf.Log.WithName("unit-tests")
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	owner := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "drift-uid", Generation: 1}}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	drifts := func() float64 {
+		return testutil.ToFloat64(metrics.DriftCorrections.WithLabelValues("ConfigMap"))
+	}
+	desired := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "drift", Namespace: "default"},
+			Data:       map[string]string{"key": value},
+		}
+	}
+	require.NoError(t, reconcileDesiredObjects(ctx, c, logger, nil, owner, scheme, desired("desired")))
+	start := drifts()
+
+	// the object changed by hand is a drift
+	changed := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "drift"}, changed))
+	changed.Data["key"] = "changed by hand"
+	require.NoError(t, c.Update(ctx, changed))
+	require.NoError(t, reconcileDesiredObjects(ctx, c, logger, nil, owner, scheme, desired("desired")))
+	assert.Equal(t, start+1, drifts())
+
+	// the object updated after a change of its owner isn't
+	owner.Generation = 2
+	require.NoError(t, reconcileDesiredObjects(ctx, c, logger, nil, owner, scheme, desired("changed in the owner")))
+	assert.Equal(t, start+1, drifts())
+	assert.True(t, generationReconciled(owner))
+
+	// an owner recreated with the same name isn't
+	recreated := owner.DeepCopy()
+	recreated.UID = "recreated"
+	assert.False(t, generationReconciled(recreated))
+
+	forgetReconciledGeneration(&v1alpha1.AmazonCloudWatchAgent{}, client.ObjectKeyFromObject(owner))
+	assert.False(t, generationReconciled(owner))
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	configMapHistoryComponent = "configmap-history"
+	// configMapHistorySourceLabel holds the name of the ConfigMap a revision is a copy of.
+	configMapHistorySourceLabel = "cloudwatch.aws.amazon.com/configmap"
+)
+
+// reconcileConfigMapHistory keeps an immutable copy of the current revision of each desired ConfigMap of the
+// instance, and deletes the revisions beyond the given limit. All the revisions are deleted when the limit is zero.
+// The revisions don't carry the part-of label, so they survive the pruning of the owned objects.
+func reconcileConfigMapHistory(ctx context.Context, c client.Client, scheme *runtime.Scheme, instance v1alpha1.AmazonCloudWatchAgent, limit int, desiredObjects []client.Object) error {
+	if limit > 0 {
+		for _, obj := range desiredObjects {
+			cm, ok := obj.(*corev1.ConfigMap)
+			if !ok {
+				continue
+			}
+			if err := createConfigMapRevision(ctx, c, scheme, instance, cm); err != nil {
+				return err
+			}
+		}
+	}
+
+	var revisions corev1.ConfigMapList
+	if err := c.List(ctx, &revisions, client.InNamespace(instance.Namespace), client.MatchingLabels(configMapHistoryLabels(instance))); err != nil {
+		return fmt.Errorf("failed to list the ConfigMap revisions: %w", err)
+	}
+	bySource := map[string][]corev1.ConfigMap{}
+	for _, cm := range revisions.Items {
+		source := cm.Labels[configMapHistorySourceLabel]
+		bySource[source] = append(bySource[source], cm)
+	}
+	for _, items := range bySource {
+		// newest first
+		sort.Slice(items, func(i, j int) bool {
+			ti, tj := items[i].CreationTimestamp, items[j].CreationTimestamp
+			if !ti.Equal(&tj) {
+				return tj.Before(&ti)
+			}
+			return items[i].Name > items[j].Name
+		})
+		for i := limit; i < len(items); i++ {
+			if err := c.Delete(ctx, &items[i]); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete the ConfigMap revision %s: %w", items[i].Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// createConfigMapRevision creates the immutable copy of the given ConfigMap, named after the hash of its data,
+// unless it already exists.
+func createConfigMapRevision(ctx context.Context, c client.Client, scheme *runtime.Scheme, instance v1alpha1.AmazonCloudWatchAgent, cm *corev1.ConfigMap) error {
+	immutable := true
+	revision := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        naming.ConfigMapRevision(cm.Name, configMapDataHash(cm)),
+			Namespace:   instance.Namespace,
+			Labels:      configMapHistoryLabels(instance),
+			Annotations: cm.Annotations,
+		},
+		Immutable:  &immutable,
+		Data:       cm.Data,
+		BinaryData: cm.BinaryData,
+	}
+	revision.Labels[configMapHistorySourceLabel] = cm.Name
+	// the revisions are garbage collected with the instance, without being its controlled objects
+	if err := controllerutil.SetOwnerReference(&instance, revision, scheme); err != nil {
+		return err
+	}
+	if err := c.Create(ctx, revision); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create the ConfigMap revision %s: %w", revision.Name, err)
+	}
+	return nil
+}
+
+// configMapDataHash returns a short hash of the data of the given ConfigMap.
+func configMapDataHash(cm *corev1.ConfigMap) string {
+	h := sha256.New()
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", k, cm.Data[k])
+	}
+	binaryKeys := make([]string, 0, len(cm.BinaryData))
+	for k := range cm.BinaryData {
+		binaryKeys = append(binaryKeys, k)
+	}
+	sort.Strings(binaryKeys)
+	for _, k := range binaryKeys {
+		fmt.Fprintf(h, "%s\x00", k)
+		h.Write(cm.BinaryData[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:10]
+}
+
+func configMapHistoryLabels(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+		"app.kubernetes.io/instance":   naming.Truncate("%s.%s", 63, instance.Namespace, instance.Name),
+		"app.kubernetes.io/component":  configMapHistoryComponent,
+	}
+}
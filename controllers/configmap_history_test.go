@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func TestReconcileConfigMapHistory(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", UID: "uid"},
+	}
+	current := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Data:       map[string]string{"cwagentconfig.json": "{}"},
+	}
+	prometheus := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-prometheus-config", Namespace: "amazon-cloudwatch"},
+		Data:       map[string]string{"prometheus.yaml": "scrape_configs: []"},
+	}
+
+	// the fake client doesn't set the creation timestamps, the existing revisions are seeded with them
+	now := time.Now()
+	revision := func(name string, age time.Duration) *corev1.ConfigMap {
+		labels := configMapHistoryLabels(*agent)
+		labels[configMapHistorySourceLabel] = "agent"
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "amazon-cloudwatch",
+			Labels:            labels,
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+		}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		agent,
+		revision(naming.ConfigMapRevision("agent", configMapDataHash(current)), time.Minute),
+		revision("agent-old", time.Hour),
+		revision("agent-older", 2*time.Hour),
+	).Build()
+
+	err := reconcileConfigMapHistory(ctx, c, scheme, *agent, 2, []client.Object{current, prometheus, &corev1.Service{}})
+	require.NoError(t, err)
+
+	names := func() []string {
+		var revisions corev1.ConfigMapList
+		require.NoError(t, c.List(ctx, &revisions, client.MatchingLabels(configMapHistoryLabels(*agent))))
+		var names []string
+		for _, cm := range revisions.Items {
+			names = append(names, cm.Name)
+		}
+		return names
+	}
+	prometheusRevision := naming.ConfigMapRevision("agent-prometheus-config", configMapDataHash(prometheus))
+	assert.ElementsMatch(t, []string{naming.ConfigMapRevision("agent", configMapDataHash(current)), "agent-old", prometheusRevision}, names())
+
+	created := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "amazon-cloudwatch", Name: prometheusRevision}, created))
+	assert.Equal(t, prometheus.Data, created.Data)
+	require.NotNil(t, created.Immutable)
+	assert.True(t, *created.Immutable)
+	assert.Equal(t, "agent-prometheus-config", created.Labels[configMapHistorySourceLabel])
+	require.Len(t, created.OwnerReferences, 1)
+	assert.Nil(t, created.OwnerReferences[0].Controller)
+
+	// disabling the history deletes the revisions
+	require.NoError(t, reconcileConfigMapHistory(ctx, c, scheme, *agent, 0, []client.Object{current, prometheus}))
+	assert.Empty(t, names())
+}
+
+func TestConfigMapDataHash(t *testing.T) {
+	a := &corev1.ConfigMap{Data: map[string]string{"a": "1", "b": "2"}}
+	b := &corev1.ConfigMap{Data: map[string]string{"b": "2", "a": "1"}}
+	assert.Equal(t, configMapDataHash(a), configMapDataHash(b))
+	assert.Len(t, configMapDataHash(a), 10)
+
+	b.Data["b"] = "3"
+	assert.NotEqual(t, configMapDataHash(a), configMapDataHash(b))
+}
@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// configOverrides returns the AmazonCloudWatchAgentConfigOverrides of the cluster, or none when the operator doesn't
+// layer them onto the agents.
+func (r *AmazonCloudWatchAgentReconciler) configOverrides(ctx context.Context) ([]v1alpha1.AmazonCloudWatchAgentConfigOverride, error) {
+	if !r.config.ConfigOverrides() {
+		return nil, nil
+	}
+	var overrides v1alpha1.AmazonCloudWatchAgentConfigOverrideList
+	if err := r.List(ctx, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to list the config overrides: %w", err)
+	}
+	return overrides.Items, nil
+}
+
+// enqueueAgents enqueues all the agents of the cluster.
+func (r *AmazonCloudWatchAgentReconciler) enqueueAgents(ctx context.Context, _ client.Object) []reconcile.Request {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &agents); err != nil {
+		r.log.Error(err, "failed to list the AmazonCloudWatchAgent objects")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(agents.Items))
+	for i := range agents.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&agents.Items[i])})
+	}
+	return requests
+}
@@ -0,0 +1,107 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/exporterpolicy"
+)
+
+func TestConfigOverrides(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.AmazonCloudWatchAgentConfigOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+			Spec:       v1alpha1.AmazonCloudWatchAgentConfigOverrideSpec{Config: `{"agent":{"region":"us-west-2"}}`},
+		},
+		&v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a"}},
+		&v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team-b"}},
+	).Build()
+	newReconciler := func(cfg config.Config) *AmazonCloudWatchAgentReconciler {
+		return NewReconciler(Params{
+			Client:   c,
+			Log:      logf.Log.WithName("unit-tests"),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+			Config:   cfg,
+		})
+	}
+
+	// the overrides are ignored unless they are enabled
+	overrides, err := newReconciler(config.New()).configOverrides(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	r := newReconciler(config.New(config.WithConfigOverrides(true)))
+	overrides, err = r.configOverrides(ctx)
+	require.NoError(t, err)
+	require.Len(t, overrides, 1)
+	assert.Equal(t, "fleet", overrides[0].Name)
+
+	// a change of an override enqueues all the agents
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: client.ObjectKey{Namespace: "team-a", Name: "a"}},
+		{NamespacedName: client.ObjectKey{Namespace: "team-b", Name: "b"}},
+	}, r.enqueueAgents(ctx, &overrides[0]))
+}
+
+func TestConfigOverridesPolicies(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:       v1alpha1.ModeDeployment,
+			OtelConfig: "receivers:\n  otlp:\n    protocols:\n      grpc:\nexporters:\n  awsemf:\nservice:\n  pipelines:\n    metrics:\n      receivers: [otlp]\n      exporters: [awsemf]\n",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.AmazonCloudWatchAgent{}).WithObjects(
+		agent,
+		&v1alpha1.AmazonCloudWatchAgentConfigOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+			Spec: v1alpha1.AmazonCloudWatchAgentConfigOverrideSpec{
+				OtelConfig: "exporters:\n  otlphttp:\n    endpoint: https://otlp.example.com\nservice:\n  pipelines:\n    metrics:\n      exporters: [awsemf, otlphttp]\n",
+			},
+		},
+	).Build()
+	r := NewReconciler(Params{
+		Client:   c,
+		Log:      logf.Log.WithName("unit-tests"),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Config: config.New(
+			config.WithConfigOverrides(true),
+			config.WithExporterPolicy(&exporterpolicy.Policy{AllowedExporters: []string{"awsemf"}}),
+		),
+	})
+
+	// an override can't add an exporter the exporter policy of the operator doesn't allow
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(agent)})
+	require.NoError(t, err)
+	var instance v1alpha1.AmazonCloudWatchAgent
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), &instance))
+	degraded := meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Contains(t, degraded.Message, `the exporter "otlphttp" is not allowed by the exporter policy`)
+}
@@ -0,0 +1,110 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+// controlPlaneMetricsFinalizer makes sure the service account of an AmazonCloudWatchAgent is removed from the
+// subjects of the binding reading the metrics of the control plane along with it, as the cluster-scoped binding can't
+// be owned by it.
+const controlPlaneMetricsFinalizer = "cloudwatch.aws.amazon.com/control-plane-metrics"
+
+// reconcileControlPlaneMetrics adds the service account of the instance to the subjects of the cluster role binding
+// reading the metrics of the control plane while it scrapes them, holding the deletion of the instance until it is
+// removed. The cluster role and its binding are deployed along with the operator, which only updates the subjects.
+func (r *AmazonCloudWatchAgentReconciler) reconcileControlPlaneMetrics(ctx context.Context, instance *v1alpha1.AmazonCloudWatchAgent) error {
+	if spec := instance.Spec.ControlPlaneMetrics; spec == nil || !spec.Enabled {
+		return r.finalizeControlPlaneMetrics(ctx, instance)
+	}
+	if !controllerutil.ContainsFinalizer(instance, controlPlaneMetricsFinalizer) {
+		patch := client.MergeFrom(instance.DeepCopy())
+		controllerutil.AddFinalizer(instance, controlPlaneMetricsFinalizer)
+		if err := r.Patch(ctx, instance, patch); err != nil {
+			return err
+		}
+	}
+	return r.syncControlPlaneMetricsSubject(ctx, instance, true)
+}
+
+// finalizeControlPlaneMetrics removes the service account of the instance from the subjects of the binding reading
+// the metrics of the control plane, and removes its control plane metrics finalizer.
+func (r *AmazonCloudWatchAgentReconciler) finalizeControlPlaneMetrics(ctx context.Context, instance *v1alpha1.AmazonCloudWatchAgent) error {
+	if !controllerutil.ContainsFinalizer(instance, controlPlaneMetricsFinalizer) {
+		return nil
+	}
+	if err := r.syncControlPlaneMetricsSubject(ctx, instance, false); err != nil {
+		return err
+	}
+	// a patch of the finalizers only, the spec of the instance may no longer be valid
+	patch := client.MergeFrom(instance.DeepCopy())
+	controllerutil.RemoveFinalizer(instance, controlPlaneMetricsFinalizer)
+	return r.Patch(ctx, instance, patch)
+}
+
+// syncControlPlaneMetricsSubject adds the service account of the instance to the subjects of the binding reading the
+// metrics of the control plane, or removes it unless another agent scraping the control plane runs with it. The
+// binding is read from the API server, the operator may only read the one binding.
+func (r *AmazonCloudWatchAgentReconciler) syncControlPlaneMetricsSubject(ctx context.Context, instance *v1alpha1.AmazonCloudWatchAgent, bound bool) error {
+	subject := rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      collector.ServiceAccountName(*instance),
+		Namespace: instance.Namespace,
+	}
+	if !bound {
+		shared, err := r.controlPlaneMetricsServiceAccountShared(ctx, instance, subject.Name)
+		if err != nil || shared {
+			return err
+		}
+	}
+
+	name := r.config.ControlPlaneMetricsRole()
+	binding := &rbacv1.ClusterRoleBinding{}
+	if err := r.reader.Get(ctx, client.ObjectKey{Name: name}, binding); err != nil {
+		if apierrors.IsNotFound(err) && !bound {
+			return nil
+		}
+		return fmt.Errorf("failed to get the cluster role binding %s reading the metrics of the control plane: %w", name, err)
+	}
+	i := slices.Index(binding.Subjects, subject)
+	switch {
+	case bound && i < 0:
+		binding.Subjects = append(binding.Subjects, subject)
+	case !bound && i >= 0:
+		binding.Subjects = slices.Delete(binding.Subjects, i, i+1)
+	default:
+		return nil
+	}
+	return r.Update(ctx, binding)
+}
+
+// controlPlaneMetricsServiceAccountShared tells whether another agent of the namespace of the instance scrapes the
+// control plane with the given service account.
+func (r *AmazonCloudWatchAgentReconciler) controlPlaneMetricsServiceAccountShared(ctx context.Context, instance *v1alpha1.AmazonCloudWatchAgent, serviceAccount string) (bool, error) {
+	agents := &v1alpha1.AmazonCloudWatchAgentList{}
+	if err := r.List(ctx, agents, client.InNamespace(instance.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range agents.Items {
+		agent := &agents.Items[i]
+		if agent.Name == instance.Name || !agent.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if spec := agent.Spec.ControlPlaneMetrics; spec != nil && spec.Enabled && collector.ServiceAccountName(*agent) == serviceAccount {
+			return true, nil
+		}
+	}
+	return false, nil
+}
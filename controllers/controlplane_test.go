@@ -0,0 +1,114 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestReconcileControlPlaneMetrics(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "observability"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ServiceAccount:      "scraper",
+			ControlPlaneMetrics: &v1alpha1.ControlPlaneMetricsSpec{Enabled: true},
+		},
+	}
+	other := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "observability"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ServiceAccount:      "scraper",
+			ControlPlaneMetrics: &v1alpha1.ControlPlaneMetricsSpec{Enabled: true},
+		},
+	}
+	kept := rbacv1.Subject{Kind: "ServiceAccount", Name: "agent", Namespace: "amazon-cloudwatch"}
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-control-plane-metrics"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cloudwatch-control-plane-metrics"},
+		Subjects:   []rbacv1.Subject{kept},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent, other, binding).Build()
+	r := NewReconciler(Params{
+		Client:   c,
+		Log:      logf.Log.WithName("unit-tests"),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Config:   config.New(),
+	})
+	scraper := rbacv1.Subject{Kind: "ServiceAccount", Name: "scraper", Namespace: "observability"}
+
+	// the service account of the agent is added to the subjects of the binding, once
+	require.NoError(t, r.reconcileControlPlaneMetrics(ctx, agent))
+	require.NoError(t, r.reconcileControlPlaneMetrics(ctx, agent))
+	assert.Contains(t, agent.Finalizers, controlPlaneMetricsFinalizer)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(binding), binding))
+	assert.Equal(t, []rbacv1.Subject{kept, scraper}, binding.Subjects)
+
+	// the service account is kept while another agent scrapes the control plane with it
+	agent.Spec.ControlPlaneMetrics.Enabled = false
+	require.NoError(t, c.Update(ctx, agent))
+	require.NoError(t, r.reconcileControlPlaneMetrics(ctx, other))
+	require.NoError(t, r.reconcileControlPlaneMetrics(ctx, agent))
+	assert.NotContains(t, agent.Finalizers, controlPlaneMetricsFinalizer)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(binding), binding))
+	assert.Equal(t, []rbacv1.Subject{kept, scraper}, binding.Subjects)
+
+	// and removed along with the last one
+	other.Spec.ControlPlaneMetrics.Enabled = false
+	require.NoError(t, r.reconcileControlPlaneMetrics(ctx, other))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(binding), binding))
+	assert.Equal(t, []rbacv1.Subject{kept}, binding.Subjects)
+}
+
+func TestReconcileControlPlaneMetricsWithoutBinding(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "observability"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ControlPlaneMetrics: &v1alpha1.ControlPlaneMetricsSpec{Enabled: true},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).Build()
+	r := NewReconciler(Params{
+		Client:   c,
+		Log:      logf.Log.WithName("unit-tests"),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Config:   config.New(),
+	})
+
+	// the binding is deployed along with the operator, which doesn't create it
+	require.Error(t, r.reconcileControlPlaneMetrics(ctx, agent))
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "cloudwatch-control-plane-metrics"}, &rbacv1.ClusterRoleBinding{})))
+
+	// the agent no longer scraping the control plane is released without it
+	agent.Spec.ControlPlaneMetrics.Enabled = false
+	require.NoError(t, r.reconcileControlPlaneMetrics(ctx, agent))
+	assert.NotContains(t, agent.Finalizers, controlPlaneMetricsFinalizer)
+}
@@ -6,6 +6,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
@@ -16,11 +17,13 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/dcgmexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
 	dcgmexporterStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/dcgmexporter"
 )
 
@@ -31,6 +34,7 @@ type DcgmExporterReconciler struct {
 	scheme   *runtime.Scheme
 	log      logr.Logger
 	config   config.Config
+	requeue  *requeuer
 }
 
 func (r *DcgmExporterReconciler) getParams(instance v1alpha1.DcgmExporter) manifests.Params {
@@ -52,6 +56,7 @@ func NewDcgmExporterReconciler(p Params) *DcgmExporterReconciler {
 		scheme:   p.Scheme,
 		config:   p.Config,
 		recorder: p.Recorder,
+		requeue:  newRequeuer(dcgmExporterController, p.Config.ReconcileInterval()),
 	}
 	return r
 }
@@ -75,7 +80,9 @@ func (r *DcgmExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	var instance v1alpha1.DcgmExporter
 	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
-		if !apierrors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
+			forgetReconciledGeneration(&instance, req.NamespacedName)
+		} else {
 			log.Error(err, "unable to fetch DcgmExporter")
 		}
 
@@ -86,13 +93,24 @@ func (r *DcgmExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 	// We have a deletion, short circuit and let the deletion happen
 	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		forgetReconciledGeneration(&instance, req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
+	defaults := map[string]string{}
+	if instance.Spec.Image == "" {
+		defaults["image"] = r.config.DcgmExporterImage()
+	}
+	if err := recordDefaultsApplied(ctx, r.Client, &instance, map[string]string{"image": instance.Spec.Image}, defaults); err != nil {
+		log.V(2).Info("unable to record the applied defaults", "err", err)
+	}
+
 	params := r.getParams(instance)
-	desiredObjects, buildErr := BuildDcgmExporter(params)
+	start := time.Now()
+	desiredObjects, buildErr := BuildDcgmExporter(ctx, params)
+	metrics.ObserveReconcileTask(dcgmExporterController, metrics.TaskBuild, start, buildErr)
 	if buildErr != nil {
-		return ctrl.Result{}, buildErr
+		return r.requeue.result(ctrl.Result{}, buildErr)
 	}
 
 	if !enabledAcceleratedComputeByAgentConfig(ctx, r.Client, log) {
@@ -100,24 +118,34 @@ func (r *DcgmExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		for _, obj := range desiredObjects {
 			if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
 				log.Error(err, "unable to delete resources", "resource", obj)
-				return ctrl.Result{}, err
+				return r.requeue.result(ctrl.Result{}, err)
 			}
 		}
-		return ctrl.Result{}, nil
+		return r.requeue.result(ctrl.Result{}, nil)
+	}
+
+	start = time.Now()
+	err := reconcileDesiredObjects(ctx, r.Client, log, r.recorder, &params.DcgmExp, params.Scheme, desiredObjects...)
+	metrics.ObserveReconcileTask(dcgmExporterController, metrics.TaskApply, start, err)
+	if err != nil {
+		result, err := dcgmexporterStatus.HandleReconcileStatus(ctx, log, params, err)
+		return r.requeue.result(result, err)
 	}
 
-	err := reconcileDesiredObjects(ctx, r.Client, log, &params.DcgmExp, params.Scheme, desiredObjects...)
-	return dcgmexporterStatus.HandleReconcileStatus(ctx, log, params, err)
+	start = time.Now()
+	result, statusErr := dcgmexporterStatus.HandleReconcileStatus(ctx, log, params, nil)
+	metrics.ObserveReconcileTask(dcgmExporterController, metrics.TaskStatus, start, statusErr)
+	return r.requeue.result(result, statusErr)
 }
 
 // BuildDcgmExporter returns the generation and collected errors of all manifests for a given instance.
-func BuildDcgmExporter(params manifests.Params) ([]client.Object, error) {
+func BuildDcgmExporter(ctx context.Context, params manifests.Params) ([]client.Object, error) {
 	builders := []manifests.Builder{
 		dcgmexporter.Build,
 	}
 	var resources []client.Object
 	for _, builder := range builders {
-		objs, err := builder(params)
+		objs, err := builder(ctx, params)
 		if err != nil {
 			return nil, err
 		}
@@ -130,6 +158,7 @@ func BuildDcgmExporter(params manifests.Params) ([]client.Object, error) {
 func (r *DcgmExporterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.DcgmExporter{}).
+		WithOptions(controller.Options{RateLimiter: newRateLimiter(r.config.ReconcileRetryBaseDelay(), r.config.ReconcileRetryMaxDelay())}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
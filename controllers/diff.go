@@ -0,0 +1,149 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// maxDiffPathsInEvent bounds the number of changed fields listed in an event, the full diff is only logged.
+const maxDiffPathsInEvent = 10
+
+// fieldChange is a field changed by an update, identified by its JSON path.
+type fieldChange struct {
+	path     string
+	old, new interface{}
+}
+
+// String renders the change with its old and new values, unless the field may hold secrets, such as the data of a
+// ConfigMap or a Secret and the environment of a container, whose values are never logged.
+func (c fieldChange) String() string {
+	if sensitivePath(c.path) {
+		return fmt.Sprintf("%s: <redacted>", c.path)
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.path, diffValue(c.old), diffValue(c.new))
+}
+
+// sensitivePath tells whether the field of the given path may hold secrets.
+func sensitivePath(path string) bool {
+	for i, segment := range strings.Split(path, ".") {
+		if j := strings.Index(segment, "["); j >= 0 {
+			segment = segment[:j]
+		}
+		if i == 0 && sensitiveDataFields[segment] {
+			return true
+		}
+		if segment == "env" {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveDataFields are the top-level fields holding the data of the ConfigMaps and the Secrets.
+var sensitiveDataFields = map[string]bool{"data": true, "binaryData": true, "stringData": true}
+
+// objectDiff returns the fields changed from before to after, comparing their JSON representation so that only
+// the fields sent to the API server are reported. The metadata managed by the API server is ignored.
+func objectDiff(before, after runtime.Object) ([]fieldChange, error) {
+	beforeMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(before)
+	if err != nil {
+		return nil, err
+	}
+	afterMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(after)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range []map[string]interface{}{beforeMap, afterMap} {
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "managedFields")
+			delete(metadata, "resourceVersion")
+			delete(metadata, "generation")
+		}
+		delete(obj, "status")
+	}
+	var changes []fieldChange
+	diffValues("", beforeMap, afterMap, &changes)
+	return changes, nil
+}
+
+func diffValues(path string, before, after interface{}, changes *[]fieldChange) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			keys := map[string]bool{}
+			for k := range b {
+				keys[k] = true
+			}
+			for k := range a {
+				keys[k] = true
+			}
+			sorted := make([]string, 0, len(keys))
+			for k := range keys {
+				sorted = append(sorted, k)
+			}
+			sort.Strings(sorted)
+			for _, k := range sorted {
+				diffValues(joinPath(path, k), b[k], a[k], changes)
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok && len(a) == len(b) {
+			for i := range b {
+				diffValues(fmt.Sprintf("%s[%d]", path, i), b[i], a[i], changes)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, fieldChange{path: path, old: before, new: after})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func diffValue(value interface{}) string {
+	if value == nil {
+		return "<unset>"
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(out)
+}
+
+// diffSummary lists the paths of the changed fields, up to maxDiffPathsInEvent of them.
+func diffSummary(changes []fieldChange) string {
+	var paths []string
+	for i, change := range changes {
+		if i == maxDiffPathsInEvent {
+			paths = append(paths, fmt.Sprintf("and %d more", len(changes)-maxDiffPathsInEvent))
+			break
+		}
+		paths = append(paths, change.path)
+	}
+	return strings.Join(paths, ", ")
+}
+
+// diffDetails renders every change on its own line.
+func diffDetails(changes []fieldChange) []string {
+	details := make([]string, 0, len(changes))
+	for _, change := range changes {
+		details = append(details, change.String())
+	}
+	return details
+}
@@ -0,0 +1,81 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObjectDiff(t *testing.T) {
+	before := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", ResourceVersion: "1", Labels: map[string]string{"team": "infra"}},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "agent", Image: "agent:1"}},
+				},
+			},
+		},
+	}
+	after := before.DeepCopy()
+	after.ResourceVersion = "2"
+	after.Labels = nil
+	after.Spec.Template.Spec.Containers[0].Image = "agent:2"
+	after.Spec.Template.Spec.Containers[0].Args = []string{"--debug"}
+
+	changes, err := objectDiff(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`metadata.labels: {"team":"infra"} -> <unset>`,
+		`spec.template.spec.containers[0].args: <unset> -> ["--debug"]`,
+		`spec.template.spec.containers[0].image: "agent:1" -> "agent:2"`,
+	}, diffDetails(changes))
+	assert.Equal(t, "metadata.labels, spec.template.spec.containers[0].args, spec.template.spec.containers[0].image", diffSummary(changes))
+
+	changes, err = objectDiff(before, before.DeepCopy())
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestObjectDiffRedactsValues(t *testing.T) {
+	before := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Data:       map[string]string{"cwagentconfig.json": `{"agent":{"credentials":{"role_arn":"a"}}}`},
+	}
+	after := before.DeepCopy()
+	after.Data["cwagentconfig.json"] = `{"agent":{"credentials":{"role_arn":"b"}}}`
+	after.BinaryData = map[string][]byte{"key": []byte("secret")}
+
+	changes, err := objectDiff(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"binaryData: <redacted>",
+		"data.cwagentconfig.json: <redacted>",
+	}, diffDetails(changes))
+
+	daemonSet := &appsv1.DaemonSet{
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "agent", Env: []corev1.EnvVar{{Name: "TOKEN", Value: "a"}}}},
+		}}},
+	}
+	changed := daemonSet.DeepCopy()
+	changed.Spec.Template.Spec.Containers[0].Env[0].Value = "b"
+	changes, err = objectDiff(daemonSet, changed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spec.template.spec.containers[0].env[0].value: <redacted>"}, diffDetails(changes))
+}
+
+func TestDiffSummaryTruncated(t *testing.T) {
+	var changes []fieldChange
+	for i := 0; i < maxDiffPathsInEvent+2; i++ {
+		changes = append(changes, fieldChange{path: "data.key"})
+	}
+	assert.Contains(t, diffSummary(changes), "and 2 more")
+}
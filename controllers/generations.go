@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// generationKey identifies an owner by its Go type and its name, so that its generation can be forgotten once it
+// is no longer found, without its UID.
+type generationKey struct {
+	kind string
+	name types.NamespacedName
+}
+
+// ownerGeneration is the generation an owner was reconciled at, along with its UID to tell apart an owner
+// recreated with the same name.
+type ownerGeneration struct {
+	uid        types.UID
+	generation int64
+}
+
+var (
+	generationsMu sync.Mutex
+	// reconciledGenerations are the generations of the owners whose objects were last reconciled successfully.
+	reconciledGenerations = map[generationKey]ownerGeneration{}
+)
+
+func newGenerationKey(owner client.Object, name types.NamespacedName) generationKey {
+	return generationKey{kind: fmt.Sprintf("%T", owner), name: name}
+}
+
+// generationReconciled tells whether the objects of the owner were already reconciled successfully at its current
+// generation, in which case the updates of its objects correct drifts rather than apply changes of the owner.
+func generationReconciled(owner client.Object) bool {
+	generationsMu.Lock()
+	defer generationsMu.Unlock()
+	reconciled, ok := reconciledGenerations[newGenerationKey(owner, client.ObjectKeyFromObject(owner))]
+	return ok && reconciled.uid == owner.GetUID() && reconciled.generation == owner.GetGeneration()
+}
+
+// recordReconciledGeneration records the objects of the owner were reconciled successfully at its current generation.
+func recordReconciledGeneration(owner client.Object) {
+	generationsMu.Lock()
+	defer generationsMu.Unlock()
+	reconciledGenerations[newGenerationKey(owner, client.ObjectKeyFromObject(owner))] = ownerGeneration{
+		uid:        owner.GetUID(),
+		generation: owner.GetGeneration(),
+	}
+}
+
+// forgetReconciledGeneration forgets the generation of an owner being deleted or no longer found. The owner only
+// gives the type of the owners, its name is the one of the request.
+func forgetReconciledGeneration(owner client.Object, name types.NamespacedName) {
+	generationsMu.Lock()
+	defer generationsMu.Unlock()
+	delete(reconciledGenerations, newGenerationKey(owner, name))
+}
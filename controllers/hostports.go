@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+// hostPort is a port bound on the nodes, which only one pod of a node can bind.
+type hostPort struct {
+	port     int32
+	protocol corev1.Protocol
+}
+
+func (p hostPort) String() string {
+	return fmt.Sprintf("%d/%s", p.port, p.protocol)
+}
+
+// findHostPortConflict looks for the agents and the other daemonsets binding a host port the daemonset of the
+// instance binds too, on nodes both of them can run on. The pods of the daemonset would otherwise silently fail to
+// schedule on these nodes. Between two agents, the one created first keeps its ports. The returned message describes
+// the conflict, and is empty when there is none. The agents and the daemonsets are listed from the API server, since
+// the cache only holds the ones this operator instance reconciles or watches.
+func findHostPortConflict(ctx context.Context, c client.Reader, instance v1alpha1.AmazonCloudWatchAgent) (string, error) {
+	ports := map[hostPort]bool{}
+	for _, p := range collector.HostPorts(instance) {
+		ports[toHostPort(p.ContainerPort, p.Protocol)] = true
+	}
+	if len(ports) == 0 {
+		return "", nil
+	}
+
+	agents := &v1alpha1.AmazonCloudWatchAgentList{}
+	if err := c.List(ctx, agents); err != nil {
+		return "", fmt.Errorf("failed to list the agents: %w", err)
+	}
+	for _, other := range agents.Items {
+		if other.UID == instance.UID || other.DeletionTimestamp != nil || other.Spec.ManagementState == v1alpha1.ManagementStateUnmanaged ||
+			!createdBefore(other, instance) || !nodesOverlap(instance.Spec.NodeSelector, other.Spec.NodeSelector) {
+			continue
+		}
+		for _, p := range collector.HostPorts(other) {
+			if port := toHostPort(p.ContainerPort, p.Protocol); ports[port] {
+				return fmt.Sprintf("the host port %s is already used by the agent %s/%s", port, other.Namespace, other.Name), nil
+			}
+		}
+	}
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := c.List(ctx, daemonSets); err != nil {
+		return "", fmt.Errorf("failed to list the daemonsets: %w", err)
+	}
+	for _, ds := range daemonSets.Items {
+		// the daemonsets of the agents were checked above
+		agent := ds.Labels["app.kubernetes.io/managed-by"] == "amazon-cloudwatch-agent-operator" &&
+			ds.Labels["app.kubernetes.io/component"] == collector.ComponentAmazonCloudWatchAgent
+		if agent || !nodesOverlap(instance.Spec.NodeSelector, ds.Spec.Template.Spec.NodeSelector) {
+			continue
+		}
+		for _, port := range podHostPorts(ds.Spec.Template.Spec) {
+			if ports[port] {
+				return fmt.Sprintf("the host port %s is already used by the daemonset %s/%s", port, ds.Namespace, ds.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// podHostPorts returns the host ports of the containers of a pod, which are all the container ports on the host
+// network.
+func podHostPorts(spec corev1.PodSpec) []hostPort {
+	var ports []hostPort
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, container := range containers {
+			for _, p := range container.Ports {
+				switch {
+				case p.HostPort != 0:
+					ports = append(ports, toHostPort(p.HostPort, p.Protocol))
+				case spec.HostNetwork:
+					ports = append(ports, toHostPort(p.ContainerPort, p.Protocol))
+				}
+			}
+		}
+	}
+	return ports
+}
+
+func toHostPort(port int32, protocol corev1.Protocol) hostPort {
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	return hostPort{port: port, protocol: protocol}
+}
+
+// createdBefore tells whether the agent a was created before the agent b, ordering the agents created in the same
+// second by their namespace and name.
+func createdBefore(a, b v1alpha1.AmazonCloudWatchAgent) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// nodesOverlap tells whether the pods of two node selectors can run on the same nodes, which they can't when they
+// select different values of the same label, such as the Linux and the Windows nodes.
+func nodesOverlap(a, b map[string]string) bool {
+	for key, value := range a {
+		if other, ok := b[key]; ok && other != value {
+			return false
+		}
+	}
+	return true
+}
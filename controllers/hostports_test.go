@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const emfConfig = `{"logs": {"metrics_collected": {"emf": {}}}}`
+
+func TestFindHostPortConflict(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	agent := func(name string, age time.Duration, mutate func(*v1alpha1.AmazonCloudWatchAgent)) *v1alpha1.AmazonCloudWatchAgent {
+		a := &v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "amazon-cloudwatch",
+				UID:               types.UID(name),
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:        v1alpha1.ModeDaemonSet,
+				HostNetwork: true,
+				Config:      emfConfig,
+			},
+		}
+		if mutate != nil {
+			mutate(a)
+		}
+		return a
+	}
+	statsd := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "statsd", Namespace: "monitoring"},
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "statsd", Ports: []corev1.ContainerPort{
+				{ContainerPort: 8125, HostPort: 25888, Protocol: corev1.ProtocolUDP},
+			}}},
+		}}},
+	}
+
+	tests := []struct {
+		name     string
+		instance *v1alpha1.AmazonCloudWatchAgent
+		objects  []client.Object
+		expected string
+	}{
+		{
+			name:     "older agent keeps the ports",
+			instance: agent("new", 0, nil),
+			objects:  []client.Object{agent("old", time.Hour, nil)},
+			expected: "the host port 25888/TCP is already used by the agent amazon-cloudwatch/old",
+		},
+		{
+			name:     "newer agent doesn't take the ports",
+			instance: agent("old", time.Hour, nil),
+			objects:  []client.Object{agent("new", 0, nil)},
+		},
+		{
+			name:     "agent off the host network",
+			instance: agent("new", 0, nil),
+			objects: []client.Object{agent("old", time.Hour, func(a *v1alpha1.AmazonCloudWatchAgent) {
+				a.Spec.HostNetwork = false
+			})},
+		},
+		{
+			name: "agents on different nodes",
+			instance: agent("new", 0, func(a *v1alpha1.AmazonCloudWatchAgent) {
+				a.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+			}),
+			objects: []client.Object{agent("old", time.Hour, func(a *v1alpha1.AmazonCloudWatchAgent) {
+				a.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+			})},
+		},
+		{
+			name:     "unmanaged agent",
+			instance: agent("new", 0, nil),
+			objects: []client.Object{agent("old", time.Hour, func(a *v1alpha1.AmazonCloudWatchAgent) {
+				a.Spec.ManagementState = v1alpha1.ManagementStateUnmanaged
+			})},
+		},
+		{
+			name:     "other daemonset",
+			instance: agent("new", 0, nil),
+			objects:  []client.Object{statsd},
+			expected: "the host port 25888/UDP is already used by the daemonset monitoring/statsd",
+		},
+		{
+			name:     "deployment",
+			instance: agent("new", 0, func(a *v1alpha1.AmazonCloudWatchAgent) { a.Spec.Mode = v1alpha1.ModeDeployment }),
+			objects:  []client.Object{agent("old", time.Hour, nil), statsd},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tt.objects, tt.instance)...).Build()
+			conflict, err := findHostPortConflict(ctx, c, *tt.instance)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, conflict)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/imagedigest"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+// resolveFunc returns the digest the tag of an image points to.
+type resolveFunc func(ctx context.Context, image string) (string, error)
+
+// digestResolver returns the resolver of the tags of the images of the instance through their registry,
+// authenticated with the image pull secrets of the service account of its pods, like the kubelet pulling them.
+func (r *AmazonCloudWatchAgentReconciler) digestResolver(instance v1alpha1.AmazonCloudWatchAgent) resolveFunc {
+	return func(ctx context.Context, image string) (string, error) {
+		if r.digests == nil {
+			return "", errors.New("the operator doesn't resolve image digests")
+		}
+		secrets, err := imagePullSecrets(ctx, r.Client, r.reader, instance)
+		if err != nil {
+			return "", err
+		}
+		return r.digests.Resolve(ctx, image, imagedigest.PullSecretsKeychain(secrets))
+	}
+}
+
+// imagePullSecrets returns the image pull secrets of the service account of the pods of the instance, none while
+// the operator didn't create it yet. Like for the kubelet, the missing Secrets are skipped. The reader reads the
+// Secrets from the API server, to avoid caching all the Secrets of the cluster.
+func imagePullSecrets(ctx context.Context, c client.Client, reader client.Reader, instance v1alpha1.AmazonCloudWatchAgent) ([]corev1.Secret, error) {
+	serviceAccount := &corev1.ServiceAccount{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: collector.ServiceAccountName(instance)}, serviceAccount); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the service account of the agent: %w", err)
+	}
+	var secrets []corev1.Secret
+	for _, ref := range serviceAccount.ImagePullSecrets {
+		secret := corev1.Secret{}
+		if err := reader.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: ref.Name}, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get the image pull secret %s: %w", ref.Name, err)
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+// withPinnedImages returns the instance with its images referenced by digest: the spec.imageDigest when set, or the
+// digest their tag resolves to when the operator requires image digests. The digest the agents run is kept as long
+// as their image doesn't change, so that a tag moved to another image doesn't roll them out.
+func withPinnedImages(ctx context.Context, cfg config.Config, resolve resolveFunc, instance v1alpha1.AmazonCloudWatchAgent) (v1alpha1.AmazonCloudWatchAgent, error) {
+	image := instance.Spec.Image
+	if image == "" {
+		image = cfg.CollectorImage()
+	}
+	pinned := instance.DeepCopy()
+	switch {
+	case instance.Spec.ImageDigest != "":
+		pinned.Spec.Image = imagedigest.Pin(image, instance.Spec.ImageDigest)
+	case !cfg.RequireImageDigest() || imagedigest.HasDigest(image):
+	case strings.HasPrefix(instance.Status.Image, image+"@"):
+		pinned.Spec.Image = instance.Status.Image
+	default:
+		digest, err := resolve(ctx, image)
+		if err != nil {
+			return instance, err
+		}
+		pinned.Spec.Image = imagedigest.Pin(image, digest)
+	}
+
+	if split := pinned.Spec.VersionSplit; split != nil && cfg.RequireImageDigest() && !imagedigest.HasDigest(split.Image) {
+		digest, err := resolve(ctx, split.Image)
+		if err != nil {
+			return instance, err
+		}
+		split.Image = imagedigest.Pin(split.Image, digest)
+	}
+	return *pinned, nil
+}
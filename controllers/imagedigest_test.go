@@ -0,0 +1,101 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestWithPinnedImages(t *testing.T) {
+	const (
+		digest      = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		otherDigest = "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	)
+	ctx := context.Background()
+	resolved := 0
+	resolve := func(_ context.Context, image string) (string, error) {
+		resolved++
+		if image == "unknown:1.0" {
+			return "", errors.New("not found")
+		}
+		return digest, nil
+	}
+	cfg := config.New(config.WithCollectorImage("agent:1.0"))
+	required := config.New(config.WithCollectorImage("agent:1.0"), config.WithRequireImageDigest(true))
+
+	// the images are left as is unless a digest is pinned or required
+	instance, err := withPinnedImages(ctx, cfg, resolve, v1alpha1.AmazonCloudWatchAgent{})
+	require.NoError(t, err)
+	assert.Empty(t, instance.Spec.Image)
+	instance, err = withPinnedImages(ctx, cfg, resolve, v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{ImageDigest: otherDigest},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "agent:1.0@"+otherDigest, instance.Spec.Image)
+	assert.Zero(t, resolved)
+
+	// a required digest is resolved, along with the one of the canary image
+	instance, err = withPinnedImages(ctx, required, resolve, v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{VersionSplit: &v1alpha1.VersionSplitSpec{Image: "agent:2.0"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "agent:1.0@"+digest, instance.Spec.Image)
+	assert.Equal(t, "agent:2.0@"+digest, instance.Spec.VersionSplit.Image)
+	assert.Equal(t, 2, resolved)
+
+	// the digest the agents run is kept while their image doesn't change
+	instance, err = withPinnedImages(ctx, required, resolve, v1alpha1.AmazonCloudWatchAgent{
+		Status: v1alpha1.AmazonCloudWatchAgentStatus{Image: "agent:1.0@" + otherDigest},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "agent:1.0@"+otherDigest, instance.Spec.Image)
+	assert.Equal(t, 2, resolved)
+
+	_, err = withPinnedImages(ctx, required, resolve, v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{Image: "unknown:1.0"},
+	})
+	assert.EqualError(t, err, "not found")
+}
+
+func TestImagePullSecrets(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	instance := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{ServiceAccount: "cloudwatch-agent"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// the service account isn't created yet
+	secrets, err := imagePullSecrets(ctx, c, c, instance)
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+
+	require.NoError(t, c.Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "missing"}, {Name: "ecr"}},
+	}))
+	require.NoError(t, c.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ecr", Namespace: "amazon-cloudwatch"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+	}))
+	secrets, err = imagePullSecrets(ctx, c, c, instance)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, "ecr", secrets[0].Name)
+}
@@ -0,0 +1,141 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// InstrumentationReconciler creates the PodMonitor of the Instrumentations setting spec.java.podMonitor, which
+// scrapes the JMX and application metrics of the pods they instrumented with the javaagent. The pods are selected by
+// the cloudwatch.aws.amazon.com/java-metrics label, which the webhook sets to the UID of the Instrumentation.
+type InstrumentationReconciler struct {
+	client.Client
+	scheme *runtime.Scheme
+	log    logr.Logger
+}
+
+// NewInstrumentationReconciler creates a new reconciler for the Instrumentation objects.
+func NewInstrumentationReconciler(p Params) *InstrumentationReconciler {
+	return &InstrumentationReconciler{
+		Client: p.Client,
+		log:    p.Log,
+		scheme: p.Scheme,
+	}
+}
+
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=instrumentations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates, updates or deletes the PodMonitor of an Instrumentation.
+func (r *InstrumentationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("instrumentation", req.NamespacedName)
+
+	instrumentation := &v1alpha1.Instrumentation{}
+	if err := r.Get(ctx, req.NamespacedName, instrumentation); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch Instrumentation")
+		}
+		// the PodMonitor is garbage collected together with its Instrumentation
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if instrumentation.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	podMonitor := &monitoringv1.PodMonitor{}
+	podMonitor.Name = naming.JavaPodMonitor(instrumentation.Name)
+	podMonitor.Namespace = instrumentation.Namespace
+	if instrumentation.Spec.Java.PodMonitor == nil {
+		return ctrl.Result{}, r.deleteJavaPodMonitor(ctx, instrumentation, podMonitor)
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, podMonitor, func() error {
+		if !podMonitor.CreationTimestamp.IsZero() && !metav1.IsControlledBy(podMonitor, instrumentation) {
+			return fmt.Errorf("PodMonitor %s/%s already exists and is not managed by Instrumentation %s", podMonitor.Namespace, podMonitor.Name, instrumentation.Name)
+		}
+		desired := javaPodMonitor(instrumentation)
+		podMonitor.Labels = desired.Labels
+		podMonitor.Spec = desired.Spec
+		return controllerutil.SetControllerReference(instrumentation, podMonitor, r.scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		log.V(2).Info("reconciled the PodMonitor of the Java metrics", "name", podMonitor.Name, "operation", op)
+	}
+	return ctrl.Result{}, nil
+}
+
+// deleteJavaPodMonitor deletes the PodMonitor of the instrumentation once it no longer sets spec.java.podMonitor.
+// A PodMonitor of the same name which it doesn't control is left untouched.
+func (r *InstrumentationReconciler) deleteJavaPodMonitor(ctx context.Context, instrumentation *v1alpha1.Instrumentation, podMonitor *monitoringv1.PodMonitor) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(podMonitor), podMonitor); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(podMonitor, instrumentation) {
+		return nil
+	}
+	if err := r.Delete(ctx, podMonitor); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete PodMonitor %s/%s: %w", podMonitor.Namespace, podMonitor.Name, err)
+	}
+	return nil
+}
+
+// javaPodMonitor returns the PodMonitor scraping the Java metrics of the pods instrumented by the instrumentation,
+// in any namespace.
+func javaPodMonitor(instrumentation *v1alpha1.Instrumentation) *monitoringv1.PodMonitor {
+	spec := instrumentation.Spec.Java.PodMonitor
+
+	labels := map[string]string{}
+	for k, v := range spec.Labels {
+		labels[k] = v
+	}
+	labels["app.kubernetes.io/name"] = naming.JavaPodMonitor(instrumentation.Name)
+	labels["app.kubernetes.io/managed-by"] = "amazon-cloudwatch-agent-operator"
+
+	endpoint := monitoringv1.PodMetricsEndpoint{Port: constants.JavaMetricsPortName}
+	if spec.Interval != nil {
+		endpoint.Interval = monitoringv1.Duration(model.Duration(spec.Interval.Duration).String())
+	}
+
+	return &monitoringv1.PodMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.JavaPodMonitor(instrumentation.Name),
+			Namespace: instrumentation.Namespace,
+			Labels:    labels,
+		},
+		Spec: monitoringv1.PodMonitorSpec{
+			NamespaceSelector: monitoringv1.NamespaceSelector{Any: true},
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{constants.LabelJavaMetrics: string(instrumentation.UID)},
+			},
+			PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{endpoint},
+		},
+	}
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *InstrumentationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Instrumentation{}).
+		Owns(&monitoringv1.PodMonitor{}).
+		Complete(r)
+}
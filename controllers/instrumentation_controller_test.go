@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestInstrumentationReconcilerJavaPodMonitor(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, monitoringv1.AddToScheme(scheme))
+
+	instrumentation := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "java", Namespace: "apps", UID: "instrumentation-uid"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Java: v1alpha1.Java{
+				PodMonitor: &v1alpha1.JavaPodMonitor{
+					Interval: &metav1.Duration{Duration: time.Minute},
+					Labels:   map[string]string{"release": "prometheus"},
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instrumentation).Build()
+	r := NewInstrumentationReconciler(Params{
+		Client: c,
+		Log:    logf.Log.WithName("unit-tests"),
+		Scheme: scheme,
+	})
+	reconcile := func() error {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(instrumentation)})
+		return err
+	}
+
+	require.NoError(t, reconcile())
+	podMonitor := &monitoringv1.PodMonitor{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "apps", Name: "java-java"}, podMonitor))
+	assert.True(t, metav1.IsControlledBy(podMonitor, instrumentation))
+	assert.Equal(t, "prometheus", podMonitor.Labels["release"])
+	assert.True(t, podMonitor.Spec.NamespaceSelector.Any)
+	assert.Equal(t, map[string]string{constants.LabelJavaMetrics: "instrumentation-uid"}, podMonitor.Spec.Selector.MatchLabels)
+	assert.Equal(t, []monitoringv1.PodMetricsEndpoint{{Port: constants.JavaMetricsPortName, Interval: "1m"}}, podMonitor.Spec.PodMetricsEndpoints)
+
+	// the PodMonitor is deleted once the Instrumentation no longer sets it
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(instrumentation), instrumentation))
+	instrumentation.Spec.Java.PodMonitor = nil
+	require.NoError(t, c.Update(ctx, instrumentation))
+	require.NoError(t, reconcile())
+	err := c.Get(ctx, client.ObjectKeyFromObject(podMonitor), &monitoringv1.PodMonitor{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
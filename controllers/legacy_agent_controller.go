@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// LegacyAgentReconciler mirrors CRs of a legacy, renamed AmazonCloudWatchAgent API into AmazonCloudWatchAgent
+// objects and propagates their status back, so that users can migrate within a deprecation window.
+type LegacyAgentReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	scheme   *runtime.Scheme
+	log      logr.Logger
+	gvk      schema.GroupVersionKind
+}
+
+// NewLegacyAgentReconciler creates a new reconciler mirroring the legacy CRs of the given kind.
+func NewLegacyAgentReconciler(p Params, gvk schema.GroupVersionKind) *LegacyAgentReconciler {
+	return &LegacyAgentReconciler{
+		Client:   p.Client,
+		log:      p.Log,
+		scheme:   p.Scheme,
+		recorder: p.Recorder,
+		gvk:      gvk,
+	}
+}
+
+func (r *LegacyAgentReconciler) newLegacy() *unstructured.Unstructured {
+	legacy := &unstructured.Unstructured{}
+	legacy.SetGroupVersionKind(r.gvk)
+	return legacy
+}
+
+// Reconcile mirrors the legacy CR into an AmazonCloudWatchAgent and propagates the agent status back.
+func (r *LegacyAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("legacy", req.NamespacedName, "kind", r.gvk.Kind)
+
+	legacy := r.newLegacy()
+	if err := r.Get(ctx, req.NamespacedName, legacy); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch legacy object")
+		}
+		// the mirrored agent is garbage collected through its owner reference
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if legacy.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{}
+	agent.Name = legacy.GetName()
+	agent.Namespace = legacy.GetNamespace()
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, agent, func() error {
+		return r.mirror(legacy, agent)
+	})
+	if err != nil {
+		r.recorder.Event(legacy, corev1.EventTypeWarning, "MirrorFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+	if op == controllerutil.OperationResultCreated {
+		r.recorder.Event(legacy, corev1.EventTypeWarning, "Deprecated",
+			fmt.Sprintf("%s is deprecated, the object has been mirrored into AmazonCloudWatchAgent %s, migrate to it before the legacy API is removed", r.gvk.GroupKind(), agent.Name))
+	}
+	if op != controllerutil.OperationResultNone {
+		log.V(2).Info("mirrored legacy object", "operation", op)
+	}
+
+	return ctrl.Result{}, r.propagateStatus(ctx, legacy, agent)
+}
+
+// mirror copies the spec, labels and annotations of the legacy object into the agent. Agents that were not
+// created by this controller are left untouched.
+func (r *LegacyAgentReconciler) mirror(legacy *unstructured.Unstructured, agent *v1alpha1.AmazonCloudWatchAgent) error {
+	if !agent.CreationTimestamp.IsZero() && agent.Labels[constants.LabelMirroredFrom] != string(legacy.GetUID()) {
+		return fmt.Errorf("AmazonCloudWatchAgent %s/%s already exists and is not mirrored from this %s", agent.Namespace, agent.Name, r.gvk.Kind)
+	}
+
+	spec := v1alpha1.AmazonCloudWatchAgentSpec{}
+	if content, ok := legacy.Object["spec"].(map[string]interface{}); ok {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &spec); err != nil {
+			return fmt.Errorf("failed to convert the spec of %s: %w", r.gvk.Kind, err)
+		}
+	}
+	agent.Spec = spec
+
+	labels := map[string]string{}
+	for k, v := range legacy.GetLabels() {
+		labels[k] = v
+	}
+	labels[constants.LabelMirroredFrom] = string(legacy.GetUID())
+	agent.Labels = labels
+
+	annotations := map[string]string{}
+	for k, v := range legacy.GetAnnotations() {
+		if strings.HasPrefix(k, corev1.LastAppliedConfigAnnotation) {
+			continue
+		}
+		annotations[k] = v
+	}
+	// keep the defaults recorded by the webhook on the agent itself
+	if defaults, ok := agent.Annotations[constants.AnnotationDefaultsApplied]; ok {
+		annotations[constants.AnnotationDefaultsApplied] = defaults
+	}
+	agent.Annotations = annotations
+	// the webhook defaults the spec on every update, which must not differ from the stored agent
+	v1alpha1.ApplyDefaults(agent)
+
+	return controllerutil.SetControllerReference(legacy, agent, r.scheme)
+}
+
+// propagateStatus copies the status of the agent into the status of the legacy object.
+func (r *LegacyAgentReconciler) propagateStatus(ctx context.Context, legacy *unstructured.Unstructured, agent *v1alpha1.AmazonCloudWatchAgent) error {
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&agent.Status)
+	if err != nil {
+		return err
+	}
+	current, _, _ := unstructured.NestedMap(legacy.Object, "status")
+	if equality.Semantic.DeepEqual(current, status) {
+		return nil
+	}
+	legacy.Object["status"] = status
+	if err := r.Status().Update(ctx, legacy); err != nil {
+		r.log.Error(err, "failed to propagate the status to the legacy object", "legacy", client.ObjectKeyFromObject(legacy))
+		return err
+	}
+	return nil
+}
+
+// The permissions on the legacy API group are not generated, they have to be granted to the operator together
+// with the legacy CRD.
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=create
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *LegacyAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("legacy-" + strings.ToLower(r.gvk.Kind)).
+		For(r.newLegacy()).
+		Owns(&v1alpha1.AmazonCloudWatchAgent{}).
+		Complete(r)
+}
@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestLegacyAgentMirror(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "legacy.example.com", Version: "v1", Kind: "CloudWatchAgent"}
+	r := NewLegacyAgentReconciler(Params{Scheme: runtime.NewScheme()}, gvk)
+
+	legacy := r.newLegacy()
+	legacy.SetName("agent")
+	legacy.SetNamespace("amazon-cloudwatch")
+	legacy.SetUID(types.UID("legacy-uid"))
+	legacy.SetLabels(map[string]string{"app": "agent"})
+	require.NoError(t, unstructured.SetNestedMap(legacy.Object, map[string]interface{}{
+		"mode":   "daemonset",
+		"image":  "agent:1.0",
+		"config": "{}",
+	}, "spec"))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"}}
+	require.NoError(t, r.mirror(legacy, agent))
+	assert.Equal(t, v1alpha1.ModeDaemonSet, agent.Spec.Mode)
+	assert.Equal(t, "agent:1.0", agent.Spec.Image)
+	assert.Equal(t, "agent", agent.Labels["app"])
+	assert.Equal(t, "legacy-uid", agent.Labels[constants.LabelMirroredFrom])
+	require.Len(t, agent.OwnerReferences, 1)
+	assert.Equal(t, gvk.Kind, agent.OwnerReferences[0].Kind)
+	assert.Equal(t, v1alpha1.ManagementStateManaged, agent.Spec.ManagementState)
+
+	// mirroring again over the defaulted agent doesn't change it
+	mirrored := agent.DeepCopy()
+	require.NoError(t, r.mirror(legacy, mirrored))
+	assert.Equal(t, agent, mirrored)
+
+	// an existing agent which was not mirrored is never taken over
+	existing := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", CreationTimestamp: metav1.Now()}}
+	assert.Error(t, r.mirror(legacy, existing))
+}
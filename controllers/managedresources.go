@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
+)
+
+// managedResourcesInterval is how often the managed AmazonCloudWatchAgent resources are counted.
+const managedResourcesInterval = 30 * time.Second
+
+var _ manager.Runnable = (*managedResourcesCounter)(nil)
+
+// managedResourcesCounter refreshes the managed resources gauge periodically, rather than on every reconciliation.
+type managedResourcesCounter struct {
+	client client.Client
+	log    logr.Logger
+}
+
+// Start counts the managed resources until the context is done.
+func (m *managedResourcesCounter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(managedResourcesInterval)
+	defer ticker.Stop()
+	for {
+		m.count(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection is false, every operator replica reports the resources of its cache.
+func (m *managedResourcesCounter) NeedLeaderElection() bool {
+	return false
+}
+
+// count refreshes the number of managed AmazonCloudWatchAgent resources by mode.
+func (m *managedResourcesCounter) count(ctx context.Context) {
+	var list v1alpha1.AmazonCloudWatchAgentList
+	if err := m.client.List(ctx, &list); err != nil {
+		m.log.V(2).Info("unable to list AmazonCloudWatchAgent resources for metrics", "err", err)
+		return
+	}
+	countByMode := map[string]int{}
+	for _, item := range list.Items {
+		if item.Spec.ManagementState == v1alpha1.ManagementStateUnmanaged || item.GetDeletionTimestamp() != nil {
+			continue
+		}
+		countByMode[string(item.Spec.Mode)]++
+	}
+	metrics.SetManagedResources(countByMode)
+}
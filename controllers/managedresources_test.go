@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
+)
+
+func TestManagedResourcesCounter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	agent := func(name string, mode v1alpha1.Mode, state v1alpha1.ManagementStateType) *v1alpha1.AmazonCloudWatchAgent {
+		return &v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: mode, ManagementState: state},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		agent("first", v1alpha1.ModeDaemonSet, v1alpha1.ManagementStateManaged),
+		agent("second", v1alpha1.ModeDaemonSet, v1alpha1.ManagementStateManaged),
+		agent("third", v1alpha1.ModeDeployment, v1alpha1.ManagementStateManaged),
+		// the unmanaged resources aren't counted
+		agent("unmanaged", v1alpha1.ModeStatefulSet, v1alpha1.ManagementStateUnmanaged),
+	).Build()
+
+	counter := &managedResourcesCounter{client: c, log: logr.Discard()}
+	counter.count(context.Background())
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.ManagedResources.WithLabelValues("daemonset")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ManagedResources.WithLabelValues("deployment")))
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.ManagedResources))
+}
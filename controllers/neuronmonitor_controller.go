@@ -6,6 +6,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
@@ -16,11 +17,13 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/neuronmonitor"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
 	neuronmonitorStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/neuronmonitor"
 )
 
@@ -31,6 +34,7 @@ type NeuronMonitorReconciler struct {
 	scheme   *runtime.Scheme
 	log      logr.Logger
 	config   config.Config
+	requeue  *requeuer
 }
 
 func (r *NeuronMonitorReconciler) getParams(instance v1alpha1.NeuronMonitor) manifests.Params {
@@ -52,6 +56,7 @@ func NewNeuronMonitorReconciler(p Params) *NeuronMonitorReconciler {
 		scheme:   p.Scheme,
 		config:   p.Config,
 		recorder: p.Recorder,
+		requeue:  newRequeuer(neuronMonitorController, p.Config.ReconcileInterval()),
 	}
 	return r
 }
@@ -75,7 +80,9 @@ func (r *NeuronMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	var instance v1alpha1.NeuronMonitor
 	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
-		if !apierrors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
+			forgetReconciledGeneration(&instance, req.NamespacedName)
+		} else {
 			log.Error(err, "unable to fetch NeuronMonitor")
 		}
 
@@ -86,13 +93,24 @@ func (r *NeuronMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 	// We have a deletion, short circuit and let the deletion happen
 	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		forgetReconciledGeneration(&instance, req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
+	defaults := map[string]string{}
+	if instance.Spec.Image == "" {
+		defaults["image"] = r.config.NeuronMonitorImage()
+	}
+	if err := recordDefaultsApplied(ctx, r.Client, &instance, map[string]string{"image": instance.Spec.Image}, defaults); err != nil {
+		log.V(2).Info("unable to record the applied defaults", "err", err)
+	}
+
 	params := r.getParams(instance)
-	desiredObjects, buildErr := BuildNeuronMonitor(params)
+	start := time.Now()
+	desiredObjects, buildErr := BuildNeuronMonitor(ctx, params)
+	metrics.ObserveReconcileTask(neuronMonitorController, metrics.TaskBuild, start, buildErr)
 	if buildErr != nil {
-		return ctrl.Result{}, buildErr
+		return r.requeue.result(ctrl.Result{}, buildErr)
 	}
 
 	if !enabledAcceleratedComputeByAgentConfig(ctx, r.Client, log) {
@@ -100,23 +118,33 @@ func (r *NeuronMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		for _, obj := range desiredObjects {
 			if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
 				log.Error(err, "unable to delete resources", "resource", obj)
-				return ctrl.Result{}, err
+				return r.requeue.result(ctrl.Result{}, err)
 			}
 		}
-		return ctrl.Result{}, nil
+		return r.requeue.result(ctrl.Result{}, nil)
+	}
+	start = time.Now()
+	err := reconcileDesiredObjects(ctx, r.Client, log, r.recorder, &params.NeuronExp, params.Scheme, desiredObjects...)
+	metrics.ObserveReconcileTask(neuronMonitorController, metrics.TaskApply, start, err)
+	if err != nil {
+		result, err := neuronmonitorStatus.HandleReconcileStatus(ctx, log, params, err)
+		return r.requeue.result(result, err)
 	}
-	err := reconcileDesiredObjects(ctx, r.Client, log, &params.NeuronExp, params.Scheme, desiredObjects...)
-	return neuronmonitorStatus.HandleReconcileStatus(ctx, log, params, err)
+
+	start = time.Now()
+	result, statusErr := neuronmonitorStatus.HandleReconcileStatus(ctx, log, params, nil)
+	metrics.ObserveReconcileTask(neuronMonitorController, metrics.TaskStatus, start, statusErr)
+	return r.requeue.result(result, statusErr)
 }
 
 // BuildNeuronMonitor returns the generation and collected errors of all manifests for a given instance.
-func BuildNeuronMonitor(params manifests.Params) ([]client.Object, error) {
+func BuildNeuronMonitor(ctx context.Context, params manifests.Params) ([]client.Object, error) {
 	builders := []manifests.Builder{
 		neuronmonitor.Build,
 	}
 	var resources []client.Object
 	for _, builder := range builders {
-		objs, err := builder(params)
+		objs, err := builder(ctx, params)
 		if err != nil {
 			return nil, err
 		}
@@ -129,6 +157,7 @@ func BuildNeuronMonitor(params manifests.Params) ([]client.Object, error) {
 func (r *NeuronMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.NeuronMonitor{}).
+		WithOptions(controller.Options{RateLimiter: newRateLimiter(r.config.ReconcileRetryBaseDelay(), r.config.ReconcileRetryMaxDelay())}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Service{}).
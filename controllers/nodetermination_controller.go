@@ -0,0 +1,203 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+)
+
+const (
+	// nodeTerminationTaintPrefix is the prefix of the taints the AWS Node Termination Handler puts on the nodes it
+	// drains, for spot interruptions, rebalance recommendations, scheduled events and ASG lifecycle hooks.
+	nodeTerminationTaintPrefix = "aws-node-termination-handler/"
+	// podNodeNameField is the field selector of the pods running on a node.
+	podNodeNameField = "spec.nodeName"
+)
+
+// nodeTerminationTaints are the taints put on the nodes about to be terminated: shut down out of service, scaled
+// down by the Cluster Autoscaler, or disrupted by Karpenter. The nodes merely cordoned aren't terminated.
+var nodeTerminationTaints = sets.New(
+	corev1.TaintNodeOutOfService,
+	"ToBeDeletedByClusterAutoscaler",
+	"karpenter.sh/disruption",
+)
+
+// NodeTerminationReconciler flushes the telemetry buffered by the agents of the nodes about to be terminated, so
+// that it isn't lost with the node. The agent flushes its buffers when it gets a SIGTERM, so the agent pods of a
+// node are deleted with the grace period set in the nodeTermination of their AmazonCloudWatchAgent, once per
+// termination of the node, rather than killed with the node.
+type NodeTerminationReconciler struct {
+	client.Client
+	// reader lists the pods of a node from the API server, to avoid caching all the pods of the cluster.
+	reader   client.Reader
+	log      logr.Logger
+	recorder record.EventRecorder
+
+	mu sync.Mutex
+	// flushed are the agents whose pods were already deleted, by node, forgotten once the node is no longer
+	// terminating. They are tracked by agent rather than by pod, the daemonset may recreate the pods on the node.
+	flushed map[string]sets.Set[types.UID]
+}
+
+// NewNodeTerminationReconciler creates a new reconciler flushing the agents of the nodes about to be terminated.
+func NewNodeTerminationReconciler(p Params, reader client.Reader) *NodeTerminationReconciler {
+	return &NodeTerminationReconciler{
+		Client:   p.Client,
+		reader:   reader,
+		log:      p.Log,
+		recorder: p.Recorder,
+		flushed:  map[string]sets.Set[types.UID]{},
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list;delete
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch
+
+// Reconcile deletes the agent pods of a node about to be terminated, for the agents which weren't flushed yet.
+func (r *NodeTerminationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("node", req.Name)
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, req.NamespacedName, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.forget(req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !nodeTerminating(node) {
+		r.forget(node.Name)
+		return ctrl.Result{}, nil
+	}
+
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &agents); err != nil {
+		return ctrl.Result{}, err
+	}
+	var errs []error
+	for i := range agents.Items {
+		agent := &agents.Items[i]
+		if agent.Spec.NodeTermination == nil || agent.Spec.Mode != v1alpha1.ModeDaemonSet || r.isFlushed(node.Name, agent.UID) {
+			continue
+		}
+		var pods corev1.PodList
+		if err := r.reader.List(ctx, &pods,
+			client.InNamespace(agent.Namespace),
+			client.MatchingLabels(manifestutils.SelectorLabels(agent.ObjectMeta, collector.ComponentAmazonCloudWatchAgent)),
+			client.MatchingFields{podNodeNameField: node.Name},
+		); err != nil {
+			errs = append(errs, fmt.Errorf("failed to list the pods of %s/%s on the node: %w", agent.Namespace, agent.Name, err))
+			continue
+		}
+		failed := false
+		for j := range pods.Items {
+			pod := &pods.Items[j]
+			if !runByDaemonSet(pod) || pod.DeletionTimestamp != nil {
+				continue
+			}
+			if err := r.flush(ctx, pod, agent.Spec.NodeTermination); err != nil {
+				failed = true
+				recordEvent(r.recorder, agent, corev1.EventTypeWarning, "FlushFailed", "Failed to delete the agent pod %s of the node %s being terminated: %v", pod.Name, node.Name, err)
+				errs = append(errs, fmt.Errorf("failed to delete the agent pod %s/%s: %w", pod.Namespace, pod.Name, err))
+				continue
+			}
+			log.Info("deleted the agent pod of the node being terminated", "pod", pod.Name, "amazoncloudwatchagent", agent.Name)
+			recordEvent(r.recorder, agent, corev1.EventTypeNormal, "Flushed", "Deleted the agent pod %s of the node %s being terminated, to flush its telemetry", pod.Name, node.Name)
+		}
+		if !failed {
+			r.markFlushed(node.Name, agent.UID)
+		}
+	}
+	// the pods failing to be deleted are retried with a backoff until the node goes away
+	return ctrl.Result{}, errors.Join(errs...)
+}
+
+// flush deletes the agent pod with the grace period of the spec, the agent flushing its buffers on the SIGTERM.
+func (r *NodeTerminationReconciler) flush(ctx context.Context, pod *corev1.Pod, spec *v1alpha1.NodeTerminationSpec) error {
+	opts := []client.DeleteOption{client.Preconditions{UID: &pod.UID}}
+	if spec.GracePeriodSeconds != nil {
+		opts = append(opts, client.GracePeriodSeconds(*spec.GracePeriodSeconds))
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, pod, opts...))
+}
+
+func (r *NodeTerminationReconciler) isFlushed(node string, uid types.UID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flushed[node].Has(uid)
+}
+
+func (r *NodeTerminationReconciler) markFlushed(node string, uid types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.flushed[node] == nil {
+		r.flushed[node] = sets.New[types.UID]()
+	}
+	r.flushed[node].Insert(uid)
+}
+
+// forget drops the flushed agents of a node which was deleted or is no longer terminating, so that they are flushed
+// again the next time it is.
+func (r *NodeTerminationReconciler) forget(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.flushed, node)
+}
+
+// nodeTerminating returns whether the node has a taint of a node about to be terminated, or of the AWS Node
+// Termination Handler.
+func nodeTerminating(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if nodeTerminationTaints.Has(taint.Key) || strings.HasPrefix(taint.Key, nodeTerminationTaintPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// runByDaemonSet returns whether the pod belongs to a daemonset, the pods of the other workloads of an agent don't
+// run on every node and are rescheduled with the drain instead.
+func runByDaemonSet(pod *corev1.Pod) bool {
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && owner.Kind == "DaemonSet"
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *NodeTerminationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the updates only matter when the node starts or stops terminating
+	terminating := builder.WithPredicates(predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return nodeTerminating(e.Object.(*corev1.Node)) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return nodeTerminating(e.ObjectOld.(*corev1.Node)) != nodeTerminating(e.ObjectNew.(*corev1.Node))
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("nodetermination").
+		For(&corev1.Node{}, terminating).
+		Complete(r)
+}
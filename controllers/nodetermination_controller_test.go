@@ -0,0 +1,171 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+)
+
+func TestNodeTerminationReconciler(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", UID: "agent-uid"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:            v1alpha1.ModeDaemonSet,
+			NodeTermination: &v1alpha1.NodeTerminationSpec{GracePeriodSeconds: ptr.To(int64(30))},
+		},
+	}
+	// the agents without nodeTermination aren't flushed
+	other := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "amazon-cloudwatch", UID: "other-uid"},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeDaemonSet},
+	}
+	pod := func(name, node string, owner *v1alpha1.AmazonCloudWatchAgent) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "amazon-cloudwatch",
+				UID:       types.UID("uid-" + name),
+				Labels:    manifestutils.SelectorLabels(owner.ObjectMeta, collector.ComponentAmazonCloudWatchAgent),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "DaemonSet", Name: owner.Name, UID: "daemonset-uid", Controller: ptr.To(true),
+				}},
+			},
+			Spec: corev1.PodSpec{NodeName: node},
+		}
+	}
+	spot := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "spot"},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{{
+			Key: "aws-node-termination-handler/spot-itn", Value: "interruption", Effect: corev1.TaintEffectNoSchedule,
+		}}},
+	}
+	healthy := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "healthy"}}
+	var gracePeriods []int64
+	var deleteErr error
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(agent, other, spot, healthy,
+			pod("agent-spot", "spot", agent), pod("agent-healthy", "healthy", agent), pod("other-spot", "spot", other)).
+		WithIndex(&corev1.Pod{}, podNodeNameField, func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if deleteErr != nil {
+					return deleteErr
+				}
+				deleteOpts := &client.DeleteOptions{}
+				deleteOpts.ApplyOptions(opts)
+				gracePeriods = append(gracePeriods, *deleteOpts.GracePeriodSeconds)
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	r := NewNodeTerminationReconciler(Params{Client: c, Log: logr.Discard(), Recorder: recorder}, c)
+	exists := func(name string) bool {
+		err := c.Get(ctx, types.NamespacedName{Namespace: "amazon-cloudwatch", Name: name}, &corev1.Pod{})
+		return !apierrors.IsNotFound(err)
+	}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(spot)})
+	require.NoError(t, err)
+	assert.False(t, exists("agent-spot"))
+	assert.True(t, exists("other-spot"))
+	assert.True(t, exists("agent-healthy"))
+	assert.Equal(t, []int64{30}, gracePeriods)
+	assert.Contains(t, <-recorder.Events, "Deleted the agent pod agent-spot of the node spot being terminated")
+
+	// the pods recreated by the daemonset are deleted once per termination
+	require.NoError(t, c.Create(ctx, pod("agent-spot-recreated", "spot", agent)))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(spot)})
+	require.NoError(t, err)
+	assert.True(t, exists("agent-spot-recreated"))
+
+	// the nodes which aren't terminating are left alone, even cordoned
+	healthy.Spec.Unschedulable = true
+	require.NoError(t, c.Update(ctx, healthy))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(healthy)})
+	require.NoError(t, err)
+	assert.True(t, exists("agent-healthy"))
+
+	// a node disrupted by Karpenter is terminating
+	healthy.Spec.Taints = []corev1.Taint{{Key: "karpenter.sh/disruption", Value: "disrupting", Effect: corev1.TaintEffectNoSchedule}}
+	require.NoError(t, c.Update(ctx, healthy))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(healthy)})
+	require.NoError(t, err)
+	assert.False(t, exists("agent-healthy"))
+
+	// the failed deletions are retried
+	deleteErr = errors.New("forbidden")
+	r.forget(spot.Name)
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(spot)})
+	assert.ErrorContains(t, err, "forbidden")
+	assert.False(t, r.isFlushed(spot.Name, agent.UID))
+}
+
+func TestNodeTerminating(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		node     corev1.Node
+		expected bool
+	}{
+		{name: "schedulable", node: corev1.Node{}},
+		{name: "cordoned", node: corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}}},
+		{
+			name:     "tainted by the AWS Node Termination Handler",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "aws-node-termination-handler/rebalance-recommendation"}}}},
+			expected: true,
+		},
+		{
+			name:     "out of service",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "node.kubernetes.io/out-of-service"}}}},
+			expected: true,
+		},
+		{
+			name:     "scaled down by the Cluster Autoscaler",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler"}}}},
+			expected: true,
+		},
+		{
+			name:     "disrupted by Karpenter",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "karpenter.sh/disruption"}}}},
+			expected: true,
+		},
+		{
+			name: "tainted by another controller",
+			node: corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "nvidia.com/gpu"}}}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nodeTerminating(&tt.node))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// OpenTelemetryCollectorGVK is the upstream OpenTelemetry operator API translated into AmazonCloudWatchAgent objects.
+var OpenTelemetryCollectorGVK = schema.GroupVersionKind{Group: "opentelemetry.io", Version: "v1alpha1", Kind: "OpenTelemetryCollector"}
+
+// translatedCollectorFields are the OpenTelemetryCollector spec fields which have the same meaning in the
+// AmazonCloudWatchAgent spec. The collector image is not translated, the agent runs the default agent image.
+var translatedCollectorFields = []string{
+	"mode", "ports", "replicas", "resources", "env", "envFrom", "args", "nodeSelector", "tolerations", "affinity",
+	"serviceAccount", "volumes", "volumeMounts", "podAnnotations", "securityContext", "podSecurityContext",
+	"priorityClassName", "hostNetwork", "topologySpreadConstraints", "terminationGracePeriodSeconds",
+}
+
+// OpenTelemetryCollectorReconciler translates upstream OpenTelemetryCollector CRs into AmazonCloudWatchAgent
+// objects, so that teams can migrate from the OpenTelemetry operator. The agents aren't owned by the collectors
+// and are kept when the collectors are deleted at the end of the migration.
+type OpenTelemetryCollectorReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	log      logr.Logger
+}
+
+// NewOpenTelemetryCollectorReconciler creates a new reconciler translating the OpenTelemetryCollector CRs.
+func NewOpenTelemetryCollectorReconciler(p Params) *OpenTelemetryCollectorReconciler {
+	return &OpenTelemetryCollectorReconciler{
+		Client:   p.Client,
+		log:      p.Log,
+		recorder: p.Recorder,
+	}
+}
+
+func newOpenTelemetryCollector() *unstructured.Unstructured {
+	collector := &unstructured.Unstructured{}
+	collector.SetGroupVersionKind(OpenTelemetryCollectorGVK)
+	return collector
+}
+
+// Reconcile translates the OpenTelemetryCollector into an AmazonCloudWatchAgent of the same name.
+func (r *OpenTelemetryCollectorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("opentelemetrycollector", req.NamespacedName)
+
+	collector := newOpenTelemetryCollector()
+	if err := r.Get(ctx, req.NamespacedName, collector); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch OpenTelemetryCollector")
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if collector.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{}
+	agent.Name = collector.GetName()
+	agent.Namespace = collector.GetNamespace()
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, agent, func() error {
+		return translateCollector(collector, agent)
+	})
+	if err != nil {
+		r.recorder.Event(collector, corev1.EventTypeWarning, "TranslationFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+	if op == controllerutil.OperationResultCreated {
+		r.recorder.Event(collector, corev1.EventTypeNormal, "Translated",
+			fmt.Sprintf("translated into AmazonCloudWatchAgent %s, delete the OpenTelemetryCollector once the agent is running", agent.Name))
+	}
+	if op != controllerutil.OperationResultNone {
+		log.V(2).Info("translated OpenTelemetryCollector", "operation", op)
+	}
+	return ctrl.Result{}, nil
+}
+
+// translateCollector copies the config, the mode, the ports and the other compatible fields of the collector into
+// the agent. Agents that were not translated from this collector are left untouched.
+func translateCollector(collector *unstructured.Unstructured, agent *v1alpha1.AmazonCloudWatchAgent) error {
+	if !agent.CreationTimestamp.IsZero() && agent.Labels[constants.LabelMirroredFrom] != string(collector.GetUID()) {
+		return fmt.Errorf("AmazonCloudWatchAgent %s/%s already exists and is not translated from this OpenTelemetryCollector", agent.Namespace, agent.Name)
+	}
+
+	content, _, _ := unstructured.NestedMap(collector.Object, "spec")
+	translated := map[string]interface{}{}
+	for _, field := range translatedCollectorFields {
+		if value, ok := content[field]; ok {
+			translated[field] = value
+		}
+	}
+	spec := v1alpha1.AmazonCloudWatchAgentSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(translated, &spec); err != nil {
+		return fmt.Errorf("failed to translate the spec of OpenTelemetryCollector %s: %w", collector.GetName(), err)
+	}
+
+	switch config := content["config"].(type) {
+	case string:
+		spec.OtelConfig = config
+	case map[string]interface{}:
+		// the config is structured from the v1beta1 API on
+		out, err := yaml.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("failed to translate the config of OpenTelemetryCollector %s: %w", collector.GetName(), err)
+		}
+		spec.OtelConfig = string(out)
+	}
+	// the agent only runs the OpenTelemetry pipelines
+	spec.Config = "{}"
+	// keep the image and the settings which may have been changed on the agent
+	spec.Image = agent.Spec.Image
+	spec.UpgradeStrategy = agent.Spec.UpgradeStrategy
+	spec.ManagementState = agent.Spec.ManagementState
+	agent.Spec = spec
+
+	labels := map[string]string{}
+	for k, v := range collector.GetLabels() {
+		labels[k] = v
+	}
+	labels[constants.LabelMirroredFrom] = string(collector.GetUID())
+	agent.Labels = labels
+	// the webhook defaults the spec on every update, which must not differ from the stored agent
+	v1alpha1.ApplyDefaults(agent)
+	return nil
+}
+
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=opentelemetrycollectors,verbs=get;list;watch
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *OpenTelemetryCollectorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("opentelemetrycollector-translation").
+		For(newOpenTelemetryCollector()).
+		Complete(r)
+}
@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestTranslateCollector(t *testing.T) {
+	collector := newOpenTelemetryCollector()
+	collector.SetName("collector")
+	collector.SetNamespace("observability")
+	collector.SetUID(types.UID("collector-uid"))
+	require.NoError(t, unstructured.SetNestedMap(collector.Object, map[string]interface{}{
+		"mode":   "statefulset",
+		"image":  "otel/opentelemetry-collector-contrib:0.90.0",
+		"config": "receivers:\n  otlp:\n",
+		"ports": []interface{}{
+			map[string]interface{}{"name": "custom", "port": int64(9999)},
+		},
+		"ingress": map[string]interface{}{"type": "ingress"},
+	}, "spec"))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "collector", Namespace: "observability"}}
+	require.NoError(t, translateCollector(collector, agent))
+	assert.Equal(t, v1alpha1.ModeStatefulSet, agent.Spec.Mode)
+	assert.Equal(t, "receivers:\n  otlp:\n", agent.Spec.OtelConfig)
+	assert.Equal(t, "{}", agent.Spec.Config)
+	assert.Empty(t, agent.Spec.Image)
+	require.Len(t, agent.Spec.Ports, 1)
+	assert.Equal(t, int32(9999), agent.Spec.Ports[0].Port)
+	assert.Equal(t, "collector-uid", agent.Labels[constants.LabelMirroredFrom])
+	assert.Empty(t, agent.OwnerReferences)
+	assert.Equal(t, int32(1), *agent.Spec.Replicas)
+
+	// the structured config of the v1beta1 API is translated into YAML
+	require.NoError(t, unstructured.SetNestedField(collector.Object, map[string]interface{}{
+		"exporters": map[string]interface{}{"awsemf": map[string]interface{}{}},
+	}, "spec", "config"))
+	require.NoError(t, translateCollector(collector, agent))
+	assert.Equal(t, "exporters:\n    awsemf: {}\n", agent.Spec.OtelConfig)
+
+	// an existing agent which was not translated is never taken over
+	existing := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "collector", Namespace: "observability", CreationTimestamp: metav1.Now()}}
+	assert.Error(t, translateCollector(collector, existing))
+}
@@ -0,0 +1,264 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+const referenceCopyComponent = "reference-copy"
+
+// reconcileReferenceCopies copies the ConfigMaps and the Secrets the instance references in other namespaces into
+// its namespace, where its pods can mount them, and deletes the copies it no longer references. The referenced
+// objects must be shared with the namespace of the instance through the shared-with annotation, holding a comma
+// separated list of namespaces or *, so that an instance doesn't give access to any Secret of the cluster. The
+// copies are refreshed on every reconcile, which the changes of the referenced objects trigger. The reader reads the
+// Secrets from the API server, to avoid caching all the Secrets of the cluster.
+func reconcileReferenceCopies(ctx context.Context, c client.Client, reader client.Reader, scheme *runtime.Scheme, instance v1alpha1.AmazonCloudWatchAgent) error {
+	keep := map[string]struct{}{}
+	for _, ref := range collector.CrossNamespaceReferences(instance) {
+		source, copied := referenceObjects(ref)
+		if err := reader.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, source); err != nil {
+			return fmt.Errorf("failed to get the %s %s/%s referenced by the instance: %w", referenceKind(ref), ref.Namespace, ref.Name, err)
+		}
+		if !sharedWith(source, instance.Namespace) {
+			return fmt.Errorf("the %s %s/%s is not shared with namespace %s, annotate it with %s=%s to allow it", referenceKind(ref), ref.Namespace, ref.Name, instance.Namespace, constants.AnnotationSharedWith, instance.Namespace)
+		}
+
+		copied.SetName(ref.CopyName(instance))
+		copied.SetNamespace(instance.Namespace)
+		labels := referenceCopyLabels(instance)
+		labels[constants.LabelMirroredFrom] = string(source.GetUID())
+		copied.SetLabels(labels)
+		switch s := source.(type) {
+		case *corev1.Secret:
+			copied.(*corev1.Secret).Type = s.Type
+			copied.(*corev1.Secret).Data = s.Data
+		case *corev1.ConfigMap:
+			copied.(*corev1.ConfigMap).Data = s.Data
+			copied.(*corev1.ConfigMap).BinaryData = s.BinaryData
+		}
+		// the copies are garbage collected with the instance
+		if err := controllerutil.SetControllerReference(&instance, copied, scheme); err != nil {
+			return err
+		}
+		if err := applyReferenceCopy(ctx, c, reader, instance, copied); err != nil {
+			return err
+		}
+		keep[referenceKind(ref)+"/"+copied.GetName()] = struct{}{}
+	}
+	return pruneReferenceCopies(ctx, c, reader, instance, keep)
+}
+
+// applyReferenceCopy creates the copy, or replaces the existing one when it differs. An existing object is only
+// replaced when it is a copy of the instance, so that an instance can't overwrite the copies of another one.
+func applyReferenceCopy(ctx context.Context, c client.Client, reader client.Reader, instance v1alpha1.AmazonCloudWatchAgent, copied client.Object) error {
+	existing, _ := copied.DeepCopyObject().(client.Object)
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(copied), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the copy %s: %w", copied.GetName(), err)
+		}
+		if err := c.Create(ctx, copied); err != nil {
+			return fmt.Errorf("failed to create the copy %s: %w", copied.GetName(), err)
+		}
+		return nil
+	}
+	labels := existing.GetLabels()
+	owner := metav1.GetControllerOf(existing)
+	if labels["app.kubernetes.io/component"] != referenceCopyComponent ||
+		labels["app.kubernetes.io/instance"] != copied.GetLabels()["app.kubernetes.io/instance"] ||
+		owner == nil || owner.UID != instance.UID {
+		return fmt.Errorf("failed to create the copy %s, an object of the same name already exists", copied.GetName())
+	}
+	if sameReferenceCopy(existing, copied) {
+		return nil
+	}
+	copied.SetResourceVersion(existing.GetResourceVersion())
+	if err := c.Update(ctx, copied); err != nil {
+		return fmt.Errorf("failed to update the copy %s: %w", copied.GetName(), err)
+	}
+	return nil
+}
+
+// sameReferenceCopy tells whether the existing copy already holds the data, the labels and the owner of the copy.
+func sameReferenceCopy(existing, copied client.Object) bool {
+	if !equality.Semantic.DeepEqual(existing.GetLabels(), copied.GetLabels()) ||
+		!equality.Semantic.DeepEqual(existing.GetOwnerReferences(), copied.GetOwnerReferences()) {
+		return false
+	}
+	switch e := existing.(type) {
+	case *corev1.Secret:
+		c := copied.(*corev1.Secret)
+		return e.Type == c.Type && equality.Semantic.DeepEqual(e.Data, c.Data)
+	case *corev1.ConfigMap:
+		c := copied.(*corev1.ConfigMap)
+		return equality.Semantic.DeepEqual(e.Data, c.Data) && equality.Semantic.DeepEqual(e.BinaryData, c.BinaryData)
+	}
+	return false
+}
+
+// enqueueReferencingAgents returns the function enqueueing the agents referencing the given Secret or ConfigMap,
+// as a copied object of another namespace or, for a Secret, as the certificate of their receivers.
+func (r *AmazonCloudWatchAgentReconciler) enqueueReferencingAgents(secret bool) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var agents v1alpha1.AmazonCloudWatchAgentList
+		if err := r.List(ctx, &agents); err != nil {
+			r.log.Error(err, "failed to list the AmazonCloudWatchAgent objects")
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range agents.Items {
+			if references(agents.Items[i], secret, obj) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&agents.Items[i])})
+			}
+		}
+		return requests
+	}
+}
+
+// references tells whether the agent references the given Secret or ConfigMap.
+func references(agent v1alpha1.AmazonCloudWatchAgent, secret bool, obj client.Object) bool {
+	if namespace, name := collector.TLSSecretSource(agent); secret && namespace == obj.GetNamespace() && name == obj.GetName() {
+		return true
+	}
+	for _, ref := range collector.CrossNamespaceReferences(agent) {
+		if ref.Secret == secret && ref.Namespace == obj.GetNamespace() && ref.Name == obj.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
+// watchReferences reconciles the agents when the Secrets and the ConfigMaps they reference change, so that their
+// copies and the certificate of their receivers follow them. Only the metadata of the Secrets is cached, their data
+// being read from the API server. Only the objects shared with other namespaces, or no longer shared, and the
+// Secrets holding the certificate of the receivers of an agent matter.
+func (r *AmazonCloudWatchAgentReconciler) watchReferences(b *builder.Builder) {
+	shared := builder.WithPredicates(predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isShared(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isShared(e.ObjectOld) || isShared(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isShared(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return isShared(e.Object) },
+	})
+	referenced := func(obj client.Object) bool { return isShared(obj) || r.isTLSSecretSource(obj) }
+	referencedSecrets := builder.WithPredicates(predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return referenced(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isShared(e.ObjectOld) || referenced(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return referenced(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return referenced(e.Object) },
+	})
+	b.WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.enqueueReferencingAgents(true)), referencedSecrets).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.enqueueReferencingAgents(false)), shared)
+}
+
+func isShared(obj client.Object) bool {
+	_, ok := obj.GetAnnotations()[constants.AnnotationSharedWith]
+	return ok
+}
+
+// isTLSSecretSource tells whether the Secret holds the certificate of the receivers of an agent. The agents are
+// listed from the cache, and a failure to list them lets the event through rather than miss a rotation.
+func (r *AmazonCloudWatchAgentReconciler) isTLSSecretSource(obj client.Object) bool {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(context.Background(), &agents); err != nil {
+		r.log.Error(err, "failed to list the AmazonCloudWatchAgent objects")
+		return true
+	}
+	for i := range agents.Items {
+		if namespace, name := collector.TLSSecretSource(agents.Items[i]); namespace == obj.GetNamespace() && name == obj.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneReferenceCopies deletes the copies of the instance which are not in keep.
+func pruneReferenceCopies(ctx context.Context, c client.Client, reader client.Reader, instance v1alpha1.AmazonCloudWatchAgent, keep map[string]struct{}) error {
+	var (
+		secrets    corev1.SecretList
+		configMaps corev1.ConfigMapList
+	)
+	opts := []client.ListOption{client.InNamespace(instance.Namespace), client.MatchingLabels(referenceCopyLabels(instance))}
+	if err := reader.List(ctx, &secrets, opts...); err != nil {
+		return fmt.Errorf("failed to list the copies of the referenced Secrets: %w", err)
+	}
+	if err := reader.List(ctx, &configMaps, opts...); err != nil {
+		return fmt.Errorf("failed to list the copies of the referenced ConfigMaps: %w", err)
+	}
+	var copies []client.Object
+	for i := range secrets.Items {
+		copies = append(copies, &secrets.Items[i])
+	}
+	for i := range configMaps.Items {
+		copies = append(copies, &configMaps.Items[i])
+	}
+	for _, obj := range copies {
+		kind := "ConfigMap"
+		if _, ok := obj.(*corev1.Secret); ok {
+			kind = "Secret"
+		}
+		if _, ok := keep[kind+"/"+obj.GetName()]; ok {
+			continue
+		}
+		if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete the copy %s: %w", obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// referenceObjects returns empty objects of the kind of the reference, to get the source into and to build the copy.
+func referenceObjects(ref collector.Reference) (client.Object, client.Object) {
+	if ref.Secret {
+		return &corev1.Secret{}, &corev1.Secret{}
+	}
+	return &corev1.ConfigMap{}, &corev1.ConfigMap{}
+}
+
+func referenceKind(ref collector.Reference) string {
+	if ref.Secret {
+		return "Secret"
+	}
+	return "ConfigMap"
+}
+
+// sharedWith tells whether the shared-with annotation of the object allows the given namespace to reference it.
+func sharedWith(obj metav1.Object, namespace string) bool {
+	for _, allowed := range strings.Split(obj.GetAnnotations()[constants.AnnotationSharedWith], ",") {
+		if allowed = strings.TrimSpace(allowed); allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// referenceCopyLabels returns the labels of the copies of the instance. They leave out the
+// app.kubernetes.io/part-of label, so that the copies aren't pruned as objects owned by the instance.
+func referenceCopyLabels(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+		"app.kubernetes.io/instance":   naming.Truncate("%s.%s", 63, instance.Namespace, instance.Name),
+		"app.kubernetes.io/component":  referenceCopyComponent,
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestReconcileReferenceCopies(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", UID: "uid"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			TLS: &v1alpha1.TLSSpec{SecretName: "agent-tls", SecretNamespace: "certificates"},
+			ExtraMounts: []v1alpha1.ExtraMountSpec{
+				{ConfigMap: "ca", Namespace: "certificates", MountPath: "/etc/ssl/custom"},
+			},
+		},
+	}
+	tls := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-tls", Namespace: "certificates", Annotations: map[string]string{
+			constants.AnnotationSharedWith: "kube-system, amazon-cloudwatch",
+		}},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}
+	ca := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "certificates"},
+		Data:       map[string]string{"ca.crt": "ca"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tls, ca).Build()
+
+	// the ConfigMap isn't shared
+	err := reconcileReferenceCopies(ctx, c, c, scheme, agent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the ConfigMap certificates/ca is not shared with namespace amazon-cloudwatch")
+
+	ca.Annotations = map[string]string{constants.AnnotationSharedWith: "*"}
+	require.NoError(t, c.Update(ctx, ca))
+	require.NoError(t, reconcileReferenceCopies(ctx, c, c, scheme, agent))
+
+	secretCopy := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "amazon-cloudwatch", Name: "agent-certificates-agent-tls"}, secretCopy))
+	assert.Equal(t, corev1.SecretTypeTLS, secretCopy.Type)
+	assert.Equal(t, tls.Data, secretCopy.Data)
+	assert.Equal(t, "uid", string(secretCopy.OwnerReferences[0].UID))
+	configMapCopy := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "amazon-cloudwatch", Name: "agent-certificates-ca"}, configMapCopy))
+	assert.Equal(t, ca.Data, configMapCopy.Data)
+
+	// the copies are only updated when the referenced objects change
+	resourceVersion := configMapCopy.ResourceVersion
+	require.NoError(t, reconcileReferenceCopies(ctx, c, c, scheme, agent))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(configMapCopy), configMapCopy))
+	assert.Equal(t, resourceVersion, configMapCopy.ResourceVersion)
+
+	// the copies follow the changes of the referenced objects
+	ca.Data = map[string]string{"ca.crt": "rotated"}
+	require.NoError(t, c.Update(ctx, ca))
+	require.NoError(t, reconcileReferenceCopies(ctx, c, c, scheme, agent))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(configMapCopy), configMapCopy))
+	assert.Equal(t, "rotated", configMapCopy.Data["ca.crt"])
+
+	// and are deleted once no longer referenced
+	agent.Spec.ExtraMounts = nil
+	require.NoError(t, reconcileReferenceCopies(ctx, c, c, scheme, agent))
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(configMapCopy), configMapCopy)))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(secretCopy), secretCopy))
+}
+
+func TestReconcileReferenceCopiesNameConflict(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ExtraMounts: []v1alpha1.ExtraMountSpec{{Secret: "proxy", Namespace: "network", MountPath: "/etc/proxy"}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: "network", Annotations: map[string]string{constants.AnnotationSharedWith: "*"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "agent-network-proxy", Namespace: "amazon-cloudwatch"}},
+	).Build()
+
+	err := reconcileReferenceCopies(ctx, c, c, scheme, agent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	// nor are the copies of another instance replaced
+	other := v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "amazon-cloudwatch", UID: "other-uid"}}
+	otherCopy := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "amazon-cloudwatch", Name: "agent-network-proxy"}, otherCopy))
+	otherCopy.Labels = referenceCopyLabels(other)
+	require.NoError(t, controllerutil.SetControllerReference(&other, otherCopy, scheme))
+	require.NoError(t, c.Update(ctx, otherCopy))
+	err = reconcileReferenceCopies(ctx, c, c, scheme, agent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestEnqueueReferencingAgents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	agents := []v1alpha1.AmazonCloudWatchAgent{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				TLS:         &v1alpha1.TLSSpec{SecretName: "agent-tls", SecretNamespace: "certificates"},
+				ExtraMounts: []v1alpha1.ExtraMountSpec{{ConfigMap: "ca", Namespace: "certificates", MountPath: "/etc/ssl/custom"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager", Namespace: "certificates"},
+			Spec:       v1alpha1.AmazonCloudWatchAgentSpec{TLS: &v1alpha1.TLSSpec{CertManager: &v1alpha1.CertManagerSpec{}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "certificates"},
+		},
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := range agents {
+		builder = builder.WithObjects(&agents[i])
+	}
+	r := &AmazonCloudWatchAgentReconciler{Client: builder.Build()}
+	ctx := context.Background()
+
+	requests := r.enqueueReferencingAgents(true)(ctx, &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "agent-tls", Namespace: "certificates"}})
+	require.Len(t, requests, 1)
+	assert.Equal(t, "shared", requests[0].Name)
+
+	requests = r.enqueueReferencingAgents(true)(ctx, &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tls", Namespace: "certificates"}})
+	require.Len(t, requests, 1)
+	assert.Equal(t, "cert-manager", requests[0].Name)
+
+	requests = r.enqueueReferencingAgents(false)(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "certificates"}})
+	require.Len(t, requests, 1)
+	assert.Equal(t, "shared", requests[0].Name)
+
+	// a Secret of the same name isn't referenced
+	assert.Empty(t, r.enqueueReferencingAgents(true)(ctx, &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "certificates"}}))
+
+	// only the certificates of the receivers are watched among the Secrets which aren't shared
+	assert.True(t, r.isTLSSecretSource(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-tls", Namespace: "certificates"}}))
+	assert.False(t, r.isTLSSecretSource(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "certificates"}}))
+}
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"math/rand"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
+)
+
+const (
+	// requeueBaseDelay and requeueMaxDelay are the default delays between the retries of a failed reconciliation.
+	requeueBaseDelay = time.Second
+	requeueMaxDelay  = 5 * time.Minute
+	// requeueJitter is the fraction of the resync interval added at random, so that objects resynced together
+	// spread out.
+	requeueJitter = 0.1
+)
+
+// requeuer turns the outcome of a reconciliation into its result: failed reconciliations are returned, for the rate
+// limiter of the controller to retry them with an exponential backoff, and reconciled objects are resynced
+// periodically, to correct the drift caused by changes made outside the operator.
+type requeuer struct {
+	// controller labels the retries in the operator metrics.
+	controller string
+	interval   time.Duration
+}
+
+// newRequeuer creates a requeuer of the given controller resyncing the reconciled objects after the given interval, or
+// never when it is zero.
+func newRequeuer(controller string, interval time.Duration) *requeuer {
+	return &requeuer{
+		controller: controller,
+		interval:   interval,
+	}
+}
+
+// result returns the result of a reconciliation. A failure is returned as is, to be logged and retried by the
+// controller after the delay of its rate limiter, and counted in the operator metrics.
+func (q *requeuer) result(result ctrl.Result, err error) (ctrl.Result, error) {
+	if err != nil {
+		metrics.ReconcileRetries.WithLabelValues(q.controller).Inc()
+		return ctrl.Result{}, err
+	}
+	if result.IsZero() && q.interval > 0 {
+		result.RequeueAfter = withJitter(q.interval)
+	}
+	return result, nil
+}
+
+// newRateLimiter returns the rate limiter of the workqueue of a controller, retrying the failed reconciliations of
+// an object after the base delay, doubled after each consecutive failure up to the max delay. The zero delays are
+// left to their defaults. An object is forgotten by the rate limiter once reconciled successfully or no longer found.
+func newRateLimiter(baseDelay, maxDelay time.Duration) ratelimiter.RateLimiter {
+	if baseDelay <= 0 {
+		baseDelay = requeueBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = requeueMaxDelay
+	}
+	return workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay)
+}
+
+func withJitter(delay time.Duration) time.Duration {
+	return delay + time.Duration(rand.Float64()*requeueJitter*float64(delay)) // #nosec G404 -- the jitter needs no cryptographic randomness
+}
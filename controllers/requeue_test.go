@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
+)
+
+func TestRequeuer(t *testing.T) {
+	q := newRequeuer("test", 10*time.Minute)
+
+	// failures are returned for the rate limiter to retry them, and counted
+	retries := testutil.ToFloat64(metrics.ReconcileRetries.WithLabelValues("test"))
+	result, err := q.result(ctrl.Result{RequeueAfter: time.Second}, errors.New("failed"))
+	assert.EqualError(t, err, "failed")
+	assert.True(t, result.IsZero())
+	assert.Equal(t, retries+1, testutil.ToFloat64(metrics.ReconcileRetries.WithLabelValues("test")))
+
+	// a success resyncs after the interval
+	result, err = q.result(ctrl.Result{}, nil)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, result.RequeueAfter, 10*time.Minute)
+	assert.LessOrEqual(t, result.RequeueAfter, withMaxJitter(10*time.Minute))
+
+	// a result requested by the reconciliation is kept
+	result, _ = q.result(ctrl.Result{RequeueAfter: time.Second}, nil)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+
+	// without an interval, reconciled objects are not resynced
+	result, _ = newRequeuer("test", 0).result(ctrl.Result{}, nil)
+	assert.True(t, result.IsZero())
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(100*time.Millisecond, time.Second)
+	item := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "agent"}}
+
+	// the delay doubles with each consecutive failure of an object, up to the max delay
+	assert.Equal(t, 100*time.Millisecond, limiter.When(item))
+	assert.Equal(t, 200*time.Millisecond, limiter.When(item))
+	for i := 0; i < 10; i++ {
+		limiter.When(item)
+	}
+	assert.Equal(t, time.Second, limiter.When(item))
+
+	// an object reconciled successfully starts over
+	limiter.Forget(item)
+	assert.Equal(t, 100*time.Millisecond, limiter.When(item))
+
+	// the delays left unset keep their defaults
+	limiter = newRateLimiter(0, time.Minute)
+	assert.Equal(t, requeueBaseDelay, limiter.When(item))
+}
+
+func withMaxJitter(delay time.Duration) time.Duration {
+	return delay + time.Duration(requeueJitter*float64(delay))
+}
@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// reconcileRestart rolls the restart requested through the restart annotation of the instance out to the desired
+// workloads, one at a time: a workload is restarted once the previous one is rolled out again, and each one is
+// restarted by its own controller following its update strategy. The progress is kept in the status, so a restart
+// interrupted by the operator resumes where it stopped. A new annotation value starts a new restart.
+func reconcileRestart(ctx context.Context, c client.Client, instance v1alpha1.AmazonCloudWatchAgent, desiredObjects []client.Object) error {
+	requestedAt := instance.Annotations[constants.AnnotationRestart]
+	if requestedAt == "" {
+		return nil
+	}
+	status := &v1alpha1.RestartStatus{RequestedAt: requestedAt}
+	if instance.Status.Restart != nil && instance.Status.Restart.RequestedAt == requestedAt {
+		status = instance.Status.Restart.DeepCopy()
+	}
+	restarted := map[string]bool{}
+	for _, name := range status.Restarted {
+		restarted[name] = true
+	}
+
+	status.Completed = true
+	for _, obj := range desiredObjects {
+		template := podTemplate(obj)
+		if template == nil {
+			continue
+		}
+		name := obj.GetName()
+		if !restarted[name] {
+			status.Restarted = append(status.Restarted, name)
+			status.Completed = false
+			setRestartedAt(template, requestedAt)
+			break
+		}
+		setRestartedAt(template, requestedAt)
+		rolledOut, err := workloadRolledOut(ctx, c, obj, requestedAt)
+		if err != nil {
+			return err
+		}
+		if !rolledOut {
+			status.Completed = false
+			break
+		}
+	}
+	// the workloads after the one rolling out keep their previous restartedAt annotation, the mutation of the
+	// existing objects only adds annotations
+
+	if apiequality.Semantic.DeepEqual(instance.Status.Restart, status) {
+		return nil
+	}
+	changed := instance.DeepCopy()
+	changed.Status.Restart = status
+	if err := c.Status().Patch(ctx, changed, client.MergeFrom(&instance)); err != nil {
+		return fmt.Errorf("failed to record the restart progress: %w", err)
+	}
+	return nil
+}
+
+// podTemplate returns the pod template of a workload, or nil for the other objects.
+func podTemplate(obj client.Object) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *appsv1.DaemonSet:
+		return &o.Spec.Template
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	}
+	return nil
+}
+
+func setRestartedAt(template *corev1.PodTemplateSpec, requestedAt string) {
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[constants.AnnotationRestartedAt] = requestedAt
+}
+
+// workloadRolledOut tells whether the existing workload of the desired one carries the restart, and runs its latest
+// template on all of its pods, all of them available.
+func workloadRolledOut(ctx context.Context, c client.Client, desired client.Object, requestedAt string) (bool, error) {
+	existing, ok := desired.DeepCopyObject().(client.Object)
+	if !ok {
+		return false, fmt.Errorf("unexpected workload type %T", desired)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get the %s workload: %w", desired.GetName(), err)
+	}
+	if podTemplate(existing).Annotations[constants.AnnotationRestartedAt] != requestedAt {
+		return false, nil
+	}
+	switch o := existing.(type) {
+	case *appsv1.DaemonSet:
+		return o.Status.ObservedGeneration >= o.Generation &&
+			o.Status.UpdatedNumberScheduled == o.Status.DesiredNumberScheduled &&
+			o.Status.NumberAvailable == o.Status.DesiredNumberScheduled, nil
+	case *appsv1.Deployment:
+		replicas := int32(1)
+		if o.Spec.Replicas != nil {
+			replicas = *o.Spec.Replicas
+		}
+		return o.Status.ObservedGeneration >= o.Generation &&
+			o.Status.UpdatedReplicas == replicas &&
+			o.Status.Replicas == replicas &&
+			o.Status.AvailableReplicas == replicas, nil
+	case *appsv1.StatefulSet:
+		return o.Status.ObservedGeneration >= o.Generation &&
+			o.Status.CurrentRevision == o.Status.UpdateRevision &&
+			o.Status.ReadyReplicas == o.Status.Replicas, nil
+	}
+	return true, nil
+}
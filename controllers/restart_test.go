@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestReconcileRestart(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	daemonSet := func(name string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "amazon-cloudwatch"},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberAvailable: 2},
+		}
+	}
+	desired := func() []client.Object {
+		return []client.Object{daemonSet("agent"), daemonSet("agent-canary")}
+	}
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", Annotations: map[string]string{
+			constants.AnnotationRestart: "2024-01-01T00:00:00Z",
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&v1alpha1.AmazonCloudWatchAgent{}).
+		WithObjects(agent, daemonSet("agent"), daemonSet("agent-canary")).
+		Build()
+
+	reconcile := func() ([]client.Object, *v1alpha1.RestartStatus) {
+		var instance v1alpha1.AmazonCloudWatchAgent
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), &instance))
+		objects := desired()
+		require.NoError(t, reconcileRestart(ctx, c, instance, objects))
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), &instance))
+		return objects, instance.Status.Restart
+	}
+	restartedAt := func(obj client.Object) string {
+		return obj.(*appsv1.DaemonSet).Spec.Template.Annotations[constants.AnnotationRestartedAt]
+	}
+	rollOut := func(name, requestedAt string) {
+		ds := &appsv1.DaemonSet{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "amazon-cloudwatch", Name: name}, ds))
+		ds.Spec.Template.Annotations = map[string]string{constants.AnnotationRestartedAt: requestedAt}
+		require.NoError(t, c.Update(ctx, ds))
+	}
+
+	// the first workload is restarted
+	objects, status := reconcile()
+	assert.Equal(t, "2024-01-01T00:00:00Z", restartedAt(objects[0]))
+	assert.Empty(t, restartedAt(objects[1]))
+	assert.Equal(t, &v1alpha1.RestartStatus{RequestedAt: "2024-01-01T00:00:00Z", Restarted: []string{"agent"}}, status)
+
+	// the next one waits for the first one to roll out
+	objects, status = reconcile()
+	assert.Empty(t, restartedAt(objects[1]))
+	assert.Equal(t, []string{"agent"}, status.Restarted)
+
+	rollOut("agent", "2024-01-01T00:00:00Z")
+	objects, status = reconcile()
+	assert.Equal(t, "2024-01-01T00:00:00Z", restartedAt(objects[0]))
+	assert.Equal(t, "2024-01-01T00:00:00Z", restartedAt(objects[1]))
+	assert.Equal(t, []string{"agent", "agent-canary"}, status.Restarted)
+	assert.False(t, status.Completed)
+
+	rollOut("agent-canary", "2024-01-01T00:00:00Z")
+	_, status = reconcile()
+	assert.True(t, status.Completed)
+
+	// a new value starts over
+	var instance v1alpha1.AmazonCloudWatchAgent
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), &instance))
+	instance.Annotations[constants.AnnotationRestart] = "2024-01-02T00:00:00Z"
+	require.NoError(t, c.Update(ctx, &instance))
+	objects, status = reconcile()
+	assert.Equal(t, "2024-01-02T00:00:00Z", restartedAt(objects[0]))
+	assert.Empty(t, restartedAt(objects[1]))
+	assert.Equal(t, &v1alpha1.RestartStatus{RequestedAt: "2024-01-02T00:00:00Z", Restarted: []string{"agent"}}, status)
+}
+
+func TestReconcileRestartWithoutAnnotation(t *testing.T) {
+	objects := []client.Object{&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent"}}}
+	require.NoError(t, reconcileRestart(context.Background(), nil, v1alpha1.AmazonCloudWatchAgent{}, objects))
+	assert.Empty(t, objects[0].(*appsv1.DaemonSet).Spec.Template.Annotations)
+}
@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+// fieldManager is the field manager owning the fields the operator applies to the objects it reconciles.
+const fieldManager = "amazon-cloudwatch-agent-operator"
+
+// updateFieldManagers are the field managers of the fields the operator wrote through updates before applying them:
+// the name of its binary, which the API server derives from the user agent, and its own field manager.
+var updateFieldManagers = sets.New("manager", fieldManager)
+
+// applyDesiredObject applies the desired object through server-side apply, hydrating obj, a copy of desired, with
+// the applied object. Only the fields set in the desired object are owned by the operator, so that the fields set
+// by other controllers, such as the replicas of an autoscaler or the sidecars of a service mesh, are left to them.
+// It returns whether the object was created or updated, and the object before it was applied.
+func applyDesiredObject(ctx context.Context, kubeClient client.Client, scheme *runtime.Scheme, owner, desired, obj client.Object) (controllerutil.OperationResult, runtime.Object, error) {
+	kind := objectKind(desired, scheme)
+	current := desired.DeepCopyObject().(client.Object)
+	err := kubeClient.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return controllerutil.OperationResultNone, nil, err
+	}
+	exists := err == nil
+	if exists {
+		// never take over an object with the same name created by another tool
+		if err := manifests.VerifyOwnership(current, owner); err != nil {
+			return controllerutil.OperationResultNone, nil, fmt.Errorf("refusing to update %s %s: %w", kind, client.ObjectKeyFromObject(desired), err)
+		}
+		// the immutable fields can't be applied, the object has to be recreated
+		if err := manifests.MutateFuncFor(current.DeepCopyObject().(client.Object), desired)(); errors.Is(err, manifests.ImmutableChangeErr) {
+			return controllerutil.OperationResultNone, nil, err
+		}
+		if err := upgradeManagedFields(ctx, kubeClient, current); err != nil {
+			return controllerutil.OperationResultNone, nil, fmt.Errorf("failed to move the fields of %s %s to the %s field manager: %w", kind, current.GetName(), fieldManager, err)
+		}
+	}
+
+	gvk, err := apiutil.GVKForObject(desired, scheme)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	if err := kubeClient.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return controllerutil.OperationResultNone, nil, err
+	}
+
+	switch {
+	case !exists:
+		return controllerutil.OperationResultCreated, nil, nil
+	case obj.GetResourceVersion() != current.GetResourceVersion():
+		return controllerutil.OperationResultUpdated, current, nil
+	}
+	return controllerutil.OperationResultNone, current, nil
+}
+
+// upgradeManagedFields moves the fields the operator wrote through updates to the field manager applying them, so
+// that the fields it no longer applies are removed instead of being kept by the field manager of the updates.
+func upgradeManagedFields(ctx context.Context, kubeClient client.Client, obj client.Object) error {
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(obj, updateFieldManagers, fieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+	return kubeClient.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch))
+}
@@ -0,0 +1,113 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
+)
+
+func TestApplyDesiredObject(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	owner := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "agent-uid"}}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}}
+	deployment := func(containers ...corev1.Container) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", Labels: map[string]string{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"}},
+			Spec: appsv1.DeploymentSpec{
+				Selector: selector,
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers}},
+			},
+		}
+	}
+	// the replicas were scaled by an autoscaler and a service mesh injected its sidecar
+	existing := deployment(corev1.Container{Name: "otc-container", Image: "cloudwatch-agent:1.0"}, corev1.Container{Name: "istio-proxy", Image: "istio/proxyv2"})
+	existing.Spec.Replicas = ptr.To(int32(5))
+	immutable := deployment()
+	immutable.Name = "immutable"
+	immutable.CreationTimestamp = metav1.Now()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing, immutable).Build()
+
+	desired := deployment(corev1.Container{Name: "otc-container", Image: "cloudwatch-agent:2.0"})
+	applied := desired.DeepCopy()
+	op, before, err := applyDesiredObject(ctx, c, scheme, owner, desired, applied)
+	require.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultUpdated, op)
+	assert.Equal(t, "cloudwatch-agent:1.0", before.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Image)
+
+	actual := &appsv1.Deployment{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(desired), actual))
+	assert.Equal(t, int32(5), *actual.Spec.Replicas)
+	require.Len(t, actual.Spec.Template.Spec.Containers, 2)
+	assert.Equal(t, "cloudwatch-agent:2.0", actual.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "istio-proxy", actual.Spec.Template.Spec.Containers[1].Name)
+	assert.Equal(t, actual.UID, applied.UID)
+
+	// the immutable fields can't be applied
+	changed := deployment()
+	changed.Name = "immutable"
+	changed.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}}
+	_, _, err = applyDesiredObject(ctx, c, scheme, owner, changed, changed.DeepCopy())
+	assert.ErrorIs(t, err, manifests.ImmutableChangeErr)
+
+	// the objects of other tools are left untouched
+	foreign := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "default", Labels: map[string]string{"app.kubernetes.io/managed-by": "Helm"}}}
+	require.NoError(t, c.Create(ctx, foreign))
+	desiredConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: "default"}}
+	_, _, err = applyDesiredObject(ctx, c, scheme, owner, desiredConfigMap, desiredConfigMap.DeepCopy())
+	assert.ErrorIs(t, err, manifests.ErrNotOwned)
+}
+
+func TestReconcileDesiredObjectsServerSideApply(t *testing.T) {
+	require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.ServerSideApply.ID(), true))
+	defer func() {
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.ServerSideApply.ID(), false))
+	}()
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	owner := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "agent-uid"}}
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "default", Labels: map[string]string{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"}},
+		Data:       map[string]string{"key": "old", "added": "by another controller"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	desired := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "default"},
+		Data:       map[string]string{"key": "desired"},
+	}
+	uids, err := reconcileDesiredObjectUIDs(ctx, c, logf.Log.WithName("unit-tests"), nil, owner, scheme, desired)
+	require.NoError(t, err)
+
+	actual := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(desired), actual))
+	assert.Equal(t, map[string]string{"key": "desired", "added": "by another controller"}, actual.Data)
+	assert.True(t, metav1.IsControlledBy(actual, owner))
+	assert.Contains(t, uids, actual.UID)
+}
@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+// withoutUserManagedServiceAccount leaves the service account of the instance out of the desired objects when it
+// already exists and was not created by the operator for the instance, so that its annotations, such as the IRSA
+// role, are never overwritten.
+func withoutUserManagedServiceAccount(ctx context.Context, c client.Client, instance v1alpha1.AmazonCloudWatchAgent, desired []client.Object) ([]client.Object, error) {
+	name := collector.ServiceAccountName(instance)
+	sa := &corev1.ServiceAccount{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: name}, sa); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get the service account %s: %w", name, err)
+		}
+		return desired, nil
+	}
+	if metav1.IsControlledBy(sa, &instance) {
+		return desired, nil
+	}
+	objects := make([]client.Object, 0, len(desired))
+	for _, obj := range desired {
+		if _, ok := obj.(*corev1.ServiceAccount); ok && obj.GetName() == name {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
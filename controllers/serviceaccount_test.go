@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestWithoutUserManagedServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	instance := v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "agent-uid"}}
+	desired := []client.Object{
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"}},
+	}
+
+	// the service account is created, then kept up to date
+	objects, err := withoutUserManagedServiceAccount(ctx, fake.NewClientBuilder().Build(), instance, desired)
+	require.NoError(t, err)
+	assert.Equal(t, desired, objects)
+	controller := true
+	owned := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:            "agent",
+		Namespace:       "default",
+		OwnerReferences: []metav1.OwnerReference{{Name: "agent", UID: "agent-uid", Controller: &controller}},
+	}}
+	objects, err = withoutUserManagedServiceAccount(ctx, fake.NewClientBuilder().WithObjects(owned).Build(), instance, desired)
+	require.NoError(t, err)
+	assert.Equal(t, desired, objects)
+
+	// an existing service account annotated with an IRSA role is left untouched
+	existing := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:        "agent",
+		Namespace:   "default",
+		Labels:      map[string]string{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"},
+		Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/agent"},
+	}}
+	objects, err = withoutUserManagedServiceAccount(ctx, fake.NewClientBuilder().WithObjects(existing).Build(), instance, desired)
+	require.NoError(t, err)
+	assert.Equal(t, desired[1:], objects)
+}
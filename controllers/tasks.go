@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+// Task is a custom step of the reconciliation of the AmazonCloudWatchAgent objects. Downstream builds of the operator
+// register tasks to extend the reconciliation, for instance to sync company-specific Secrets, without forking the
+// reconciler.
+type Task struct {
+	// Name identifies the task in the logs, the events, the metrics and --disable-reconcile-tasks.
+	Name string
+	// Priority orders the tasks, the lower ones first. The tasks of the same priority run in their registration order.
+	Priority int
+	// Do runs the task for an agent, once the operator reconciled its objects.
+	Do func(ctx context.Context, params manifests.Params) error
+	// BailOnError stops the reconciliation of the agent when the task fails, which is otherwise only reported
+	// through an event.
+	BailOnError bool
+}
+
+var (
+	tasksMu         sync.Mutex
+	registeredTasks []Task
+)
+
+// RegisterTask registers a task run by the reconcilers created afterwards, which is meant to be called from the init
+// functions of the packages defining tasks. The names of the tasks must be unique.
+func RegisterTask(task Task) error {
+	if task.Name == "" || task.Do == nil {
+		return fmt.Errorf("a task needs a name and a function")
+	}
+	tasksMu.Lock()
+	defer tasksMu.Unlock()
+	for _, registered := range registeredTasks {
+		if registered.Name == task.Name {
+			return fmt.Errorf("the task %s is already registered", task.Name)
+		}
+	}
+	registeredTasks = append(registeredTasks, task)
+	return nil
+}
+
+// enabledTasks returns the registered tasks in the order they run, without the disabled ones.
+func enabledTasks(disabled []string) []Task {
+	tasksMu.Lock()
+	defer tasksMu.Unlock()
+	skipped := map[string]bool{}
+	for _, name := range disabled {
+		skipped[name] = true
+	}
+	var tasks []Task
+	for _, task := range registeredTasks {
+		if !skipped[task.Name] {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Priority < tasks[j].Priority
+	})
+	return tasks
+}
@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestRegisterTask(t *testing.T) {
+	t.Cleanup(func() { registeredTasks = nil })
+	do := func(context.Context, manifests.Params) error { return nil }
+
+	require.NoError(t, RegisterTask(Task{Name: "sync-secrets", Priority: 10, Do: do}))
+	require.NoError(t, RegisterTask(Task{Name: "tag-resources", Priority: 0, Do: do}))
+	require.NoError(t, RegisterTask(Task{Name: "notify", Priority: 10, Do: do}))
+	assert.Error(t, RegisterTask(Task{Name: "notify", Do: do}))
+	assert.Error(t, RegisterTask(Task{Name: "no-function"}))
+
+	names := func(tasks []Task) []string {
+		var result []string
+		for _, task := range tasks {
+			result = append(result, task.Name)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"tag-resources", "sync-secrets", "notify"}, names(enabledTasks(nil)))
+	assert.Equal(t, []string{"tag-resources", "notify"}, names(enabledTasks([]string{"sync-secrets"})))
+}
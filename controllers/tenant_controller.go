@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+const (
+	defaultTenantAgentName = "cloudwatch-agent"
+	// tenantNamespaceVariable is replaced with the tenant namespace in the strings of the template agent spec.
+	tenantNamespaceVariable = "{namespace}"
+)
+
+// TenantReconciler stamps out an AmazonCloudWatchAgent in each namespace labeled cloudwatch.aws.amazon.com/tenant=true
+// from a cluster-scoped AmazonCloudWatchAgentTemplate, so that each team gets its own agent without writing it. The
+// namespaces select their template through the cloudwatch.aws.amazon.com/tenant-template annotation, and otherwise
+// get the default one. The agents are deleted once their namespace is no longer a tenant.
+type TenantReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	scheme   *runtime.Scheme
+	log      logr.Logger
+}
+
+// NewTenantReconciler creates a new reconciler stamping out the agents of the tenant namespaces.
+func NewTenantReconciler(p Params) *TenantReconciler {
+	return &TenantReconciler{
+		Client:   p.Client,
+		log:      p.Log,
+		scheme:   p.Scheme,
+		recorder: p.Recorder,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagenttemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=create;delete
+
+// Reconcile stamps out the agent of a tenant namespace, and deletes the agents stamped out in the namespace which
+// it no longer needs.
+func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("namespace", req.Name)
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch namespace")
+		}
+		// the agents are deleted together with their namespace
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if namespace.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	if !isTenant(namespace) {
+		return ctrl.Result{}, r.pruneTenantAgents(ctx, namespace.Name, nil)
+	}
+
+	template := &v1alpha1.AmazonCloudWatchAgentTemplate{}
+	if err := r.Get(ctx, client.ObjectKey{Name: tenantTemplateName(namespace)}, template); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		recordEvent(r.recorder, namespace, corev1.EventTypeWarning, "TemplateNotFound", "AmazonCloudWatchAgentTemplate %s doesn't exist", tenantTemplateName(namespace))
+		return ctrl.Result{}, r.pruneTenantAgents(ctx, namespace.Name, nil)
+	}
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{}
+	agent.Name = tenantAgentName(template)
+	agent.Namespace = namespace.Name
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, agent, func() error {
+		return r.stampOut(template, agent)
+	})
+	if err != nil {
+		recordEvent(r.recorder, namespace, corev1.EventTypeWarning, "StampOutFailed", "%s", err)
+		return ctrl.Result{}, err
+	}
+	if op == controllerutil.OperationResultCreated {
+		recordEvent(r.recorder, namespace, corev1.EventTypeNormal, "Created", "Created AmazonCloudWatchAgent %s from AmazonCloudWatchAgentTemplate %s", agent.Name, template.Name)
+	}
+	if op != controllerutil.OperationResultNone {
+		log.V(2).Info("stamped out tenant agent", "template", template.Name, "operation", op)
+	}
+
+	return ctrl.Result{}, r.pruneTenantAgents(ctx, namespace.Name, agent)
+}
+
+// stampOut renders the template into the agent. Agents that were not stamped out of this template are left
+// untouched.
+func (r *TenantReconciler) stampOut(template *v1alpha1.AmazonCloudWatchAgentTemplate, agent *v1alpha1.AmazonCloudWatchAgent) error {
+	if !agent.CreationTimestamp.IsZero() && agent.Labels[constants.LabelTenantTemplate] != template.Name {
+		return fmt.Errorf("AmazonCloudWatchAgent %s/%s already exists and is not stamped out of AmazonCloudWatchAgentTemplate %s", agent.Namespace, agent.Name, template.Name)
+	}
+
+	spec, err := tenantAgentSpec(template.Spec.Agent, agent.Namespace)
+	if err != nil {
+		return err
+	}
+	agent.Spec = spec
+
+	labels := map[string]string{}
+	for k, v := range template.Spec.Labels {
+		labels[k] = v
+	}
+	labels[constants.LabelTenantTemplate] = template.Name
+	agent.Labels = labels
+
+	annotations := map[string]string{}
+	for k, v := range template.Spec.Annotations {
+		annotations[k] = v
+	}
+	// keep the defaults recorded by the webhook on the agent itself
+	if defaults, ok := agent.Annotations[constants.AnnotationDefaultsApplied]; ok {
+		annotations[constants.AnnotationDefaultsApplied] = defaults
+	}
+	agent.Annotations = annotations
+	// the webhook defaults the spec on every update, which must not differ from the stored agent
+	v1alpha1.ApplyDefaults(agent)
+
+	return controllerutil.SetControllerReference(template, agent, r.scheme)
+}
+
+// pruneTenantAgents deletes the agents stamped out in the namespace, except the given one.
+func (r *TenantReconciler) pruneTenantAgents(ctx context.Context, namespace string, keep *v1alpha1.AmazonCloudWatchAgent) error {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &agents, client.InNamespace(namespace), client.HasLabels{constants.LabelTenantTemplate}); err != nil {
+		return fmt.Errorf("failed to list the tenant agents: %w", err)
+	}
+	for i := range agents.Items {
+		agent := &agents.Items[i]
+		if keep != nil && agent.Name == keep.Name {
+			continue
+		}
+		if err := r.Delete(ctx, agent); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete the tenant agent %s/%s: %w", agent.Namespace, agent.Name, err)
+		}
+		r.log.V(2).Info("deleted tenant agent", "namespace", agent.Namespace, "name", agent.Name)
+	}
+	return nil
+}
+
+// tenantAgentSpec returns the spec of the template with the {namespace} variable replaced in all its strings.
+func tenantAgentSpec(template v1alpha1.AmazonCloudWatchAgentSpec, namespace string) (v1alpha1.AmazonCloudWatchAgentSpec, error) {
+	spec := v1alpha1.AmazonCloudWatchAgentSpec{}
+	raw, err := json.Marshal(template)
+	if err != nil {
+		return spec, fmt.Errorf("failed to render the agent spec of the template: %w", err)
+	}
+	// the namespace names are DNS labels, which never need to be escaped in JSON strings
+	raw = []byte(strings.ReplaceAll(string(raw), tenantNamespaceVariable, namespace))
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return spec, fmt.Errorf("failed to render the agent spec of the template: %w", err)
+	}
+	return spec, nil
+}
+
+func isTenant(namespace *corev1.Namespace) bool {
+	return namespace.Labels[constants.LabelTenant] == "true"
+}
+
+func tenantTemplateName(namespace *corev1.Namespace) string {
+	if name := namespace.Annotations[constants.AnnotationTenantTemplate]; name != "" {
+		return name
+	}
+	return v1alpha1.DefaultTenantTemplate
+}
+
+func tenantAgentName(template *v1alpha1.AmazonCloudWatchAgentTemplate) string {
+	if template.Spec.AgentName != "" {
+		return template.Spec.AgentName
+	}
+	return defaultTenantAgentName
+}
+
+// tenantNamespaces enqueues the tenant namespaces, for a change of a template to reach the namespaces using it.
+func (r *TenantReconciler) tenantNamespaces(ctx context.Context, _ client.Object) []reconcile.Request {
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabels{constants.LabelTenant: "true"}); err != nil {
+		r.log.Error(err, "failed to list the namespaces")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, namespace := range namespaces.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: namespace.Name}})
+	}
+	return requests
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("tenant").
+		For(&corev1.Namespace{}, builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&v1alpha1.AmazonCloudWatchAgentTemplate{}, handler.EnqueueRequestsFromMapFunc(r.tenantNamespaces)).
+		Watches(&v1alpha1.AmazonCloudWatchAgent{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+			if _, ok := obj.GetLabels()[constants.LabelTenantTemplate]; !ok {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: obj.GetNamespace()}}}
+		})).
+		Complete(r)
+}
@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestTenantReconciler(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	template := &v1alpha1.AmazonCloudWatchAgentTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.DefaultTenantTemplate, UID: "template-uid"},
+		Spec: v1alpha1.AmazonCloudWatchAgentTemplateSpec{
+			Labels: map[string]string{"team": "platform"},
+			Agent: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:   v1alpha1.ModeDeployment,
+				Config: `{"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app.log","log_group_name":"/tenants/{namespace}"}]}}}}`,
+			},
+		},
+	}
+	tenant := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{constants.LabelTenant: "true"}}}
+	// an agent the team wrote itself is left to it
+	other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "search", Labels: map[string]string{constants.LabelTenant: "true"}}}
+	otherAgent := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: defaultTenantAgentName, Namespace: other.Name, CreationTimestamp: metav1.Now()}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, tenant, other, otherAgent).Build()
+	r := NewTenantReconciler(Params{
+		Client:   c,
+		Log:      logf.Log.WithName("unit-tests"),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	})
+	reconcileNamespace := func(name string) error {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+		return err
+	}
+
+	require.NoError(t, reconcileNamespace(tenant.Name))
+	agent := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: tenant.Name, Name: defaultTenantAgentName}, agent))
+	assert.True(t, metav1.IsControlledBy(agent, template))
+	assert.Equal(t, v1alpha1.ModeDeployment, agent.Spec.Mode)
+	assert.Contains(t, agent.Spec.Config, `"log_group_name":"/tenants/payments"`)
+	assert.Equal(t, map[string]string{
+		"team":                         "platform",
+		constants.LabelTenantTemplate:  v1alpha1.DefaultTenantTemplate,
+		"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+	}, agent.Labels)
+	// the stamped out spec is defaulted like the webhook does, the agent isn't updated again
+	assert.Equal(t, int32(1), *agent.Spec.Replicas)
+	require.NoError(t, reconcileNamespace(tenant.Name))
+	unchanged := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), unchanged))
+	assert.Equal(t, agent.ResourceVersion, unchanged.ResourceVersion)
+
+	assert.Error(t, reconcileNamespace(other.Name))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(otherAgent), otherAgent))
+	assert.Empty(t, otherAgent.OwnerReferences)
+
+	// the agent is deleted once the namespace is no longer a tenant
+	tenant.Labels = nil
+	require.NoError(t, c.Update(ctx, tenant))
+	require.NoError(t, reconcileNamespace(tenant.Name))
+	err := c.Get(ctx, client.ObjectKeyFromObject(agent), &v1alpha1.AmazonCloudWatchAgent{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
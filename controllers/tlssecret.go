@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+// withTLSHash returns the rendered instance annotated with the hash of the Secret holding the certificate of its
+// receivers, so that its pods roll out when the certificate is renewed, the agent reading it only on start. A
+// Secret that doesn't exist yet, such as one cert-manager didn't issue, isn't hashed: the pods wait for it, and
+// roll out once it is issued. The reader reads the Secret from the API server, to avoid caching all the Secrets of
+// the cluster.
+func withTLSHash(ctx context.Context, reader client.Reader, instance v1alpha1.AmazonCloudWatchAgent) (v1alpha1.AmazonCloudWatchAgent, error) {
+	namespace, name := collector.TLSSecretSource(instance)
+	if name == "" {
+		return instance, nil
+	}
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return instance, nil
+		}
+		return instance, fmt.Errorf("failed to get the TLS Secret %s/%s: %w", namespace, name, err)
+	}
+	return collector.WithTLSHash(instance, secret), nil
+}
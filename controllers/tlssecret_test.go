@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+func TestWithTLSHash(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			TLS:            &v1alpha1.TLSSpec{SecretName: "agent-tls", SecretNamespace: "certificates"},
+			PodAnnotations: map[string]string{"team": "observability"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// the pods wait for a Secret that isn't issued yet
+	rendered, err := withTLSHash(ctx, c, agent)
+	require.NoError(t, err)
+	assert.NotContains(t, rendered.Spec.PodAnnotations, collector.TLSHashAnnotation)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-tls", Namespace: "certificates"},
+		Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}
+	require.NoError(t, c.Create(ctx, secret))
+	rendered, err = withTLSHash(ctx, c, agent)
+	require.NoError(t, err)
+	issued := rendered.Spec.PodAnnotations[collector.TLSHashAnnotation]
+	assert.NotEmpty(t, issued)
+	assert.Equal(t, "observability", rendered.Spec.PodAnnotations["team"])
+	assert.NotContains(t, agent.Spec.PodAnnotations, collector.TLSHashAnnotation)
+
+	// a renewed certificate rolls out the pods
+	secret.Data["tls.crt"] = []byte("renewed")
+	require.NoError(t, c.Update(ctx, secret))
+	rendered, err = withTLSHash(ctx, c, agent)
+	require.NoError(t, err)
+	assert.NotEqual(t, issued, rendered.Spec.PodAnnotations[collector.TLSHashAnnotation])
+}
@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package agentgates holds the feature gates of the agent known to the operator, and builds the flag passing them
+// to the agent.
+package agentgates
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// known are the feature gates of the agent known to the operator, sorted by identifier.
+var known = []string{
+	"exporter.xray.allowDot",
+	"pkg.translator.prometheus.NormalizeName",
+	"receiver.prometheusreceiver.EnableNativeHistograms",
+}
+
+// Check returns a warning naming the feature gates unknown to the operator, which the agent version may or may not
+// support, or an empty string when they are all known.
+func Check(featureGates map[string]bool) string {
+	var unknown []string
+	for _, id := range sortedIDs(featureGates) {
+		if !slices.Contains(known, id) {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("the feature gates %s are unknown to the operator, the agent fails to start if its version doesn't support them, the known ones are %s",
+		strings.Join(unknown, ", "), strings.Join(known, ", "))
+}
+
+// Flag returns the value of the --feature-gates flag of the agent, sorted so that the flag is stable across
+// reconciliations, or an empty string when there are no feature gates.
+func Flag(featureGates map[string]bool) string {
+	values := make([]string, 0, len(featureGates))
+	for _, id := range sortedIDs(featureGates) {
+		if featureGates[id] {
+			values = append(values, "+"+id)
+		} else {
+			values = append(values, "-"+id)
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+func sortedIDs(featureGates map[string]bool) []string {
+	ids := make([]string, 0, len(featureGates))
+	for id := range featureGates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agentgates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	assert.Empty(t, Check(nil))
+	assert.Empty(t, Check(map[string]bool{"exporter.xray.allowDot": false, "pkg.translator.prometheus.NormalizeName": true}))
+	assert.Equal(t, "the feature gates exporter.awsemf.unknown, receiver.otlp.grpc are unknown to the operator, the agent fails to start if its version doesn't support them, the known ones are "+
+		"exporter.xray.allowDot, pkg.translator.prometheus.NormalizeName, receiver.prometheusreceiver.EnableNativeHistograms",
+		Check(map[string]bool{"receiver.otlp.grpc": true, "exporter.xray.allowDot": true, "exporter.awsemf.unknown": false}))
+}
+
+func TestFlag(t *testing.T) {
+	assert.Empty(t, Flag(nil))
+	assert.Equal(t, "-exporter.xray.allowDot,+pkg.translator.prometheus.NormalizeName,+receiver.prometheusreceiver.EnableNativeHistograms",
+		Flag(map[string]bool{"receiver.prometheusreceiver.EnableNativeHistograms": true, "exporter.xray.allowDot": false, "pkg.translator.prometheus.NormalizeName": true}))
+}
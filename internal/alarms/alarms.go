@@ -0,0 +1,283 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package alarms manages the CloudWatch alarms monitoring the health of the agents.
+package alarms
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	defaultHeartbeatNamespace  = "ContainerInsights"
+	defaultHeartbeatMetricName = "node_cpu_utilization"
+	defaultPeriodSeconds       = int32(300)
+	defaultEvaluationPeriods   = int32(3)
+
+	clusterNameDimension = "ClusterName"
+
+	heartbeatAlarm    = "heartbeat"
+	exportErrorsAlarm = "export-errors"
+
+	// resyncPeriod is the period after which the alarms of an agent are described again while it doesn't change
+	// them, to restore the alarms changed or deleted out of band without calling the CloudWatch API on every
+	// reconciliation.
+	resyncPeriod = time.Hour
+)
+
+// Reconciler creates, updates and deletes the alarms of the agents through the CloudWatch API.
+type Reconciler struct {
+	mu     sync.Mutex
+	api    cloudwatchiface.CloudWatchAPI
+	newAPI func() (cloudwatchiface.CloudWatchAPI, error)
+
+	syncMu sync.Mutex
+	// synced holds the hash of the alarms last reconciled for each agent, and when they were
+	synced map[types.UID]syncState
+	now    func() time.Time
+}
+
+// syncState is the last successful reconciliation of the alarms of an agent.
+type syncState struct {
+	hash string
+	at   time.Time
+}
+
+// NewReconciler creates a Reconciler calling the given CloudWatch API.
+func NewReconciler(api cloudwatchiface.CloudWatchAPI) *Reconciler {
+	return &Reconciler{api: api, synced: map[types.UID]syncState{}, now: time.Now}
+}
+
+// NewLazyReconciler creates a Reconciler which creates its CloudWatch API client on the first agent defining
+// alarms, so that the operator doesn't hold an AWS session while no agent needs it. A failed creation is retried on
+// the next reconciliation.
+func NewLazyReconciler(newAPI func() (cloudwatchiface.CloudWatchAPI, error)) *Reconciler {
+	return &Reconciler{newAPI: newAPI, synced: map[types.UID]syncState{}, now: time.Now}
+}
+
+// client returns the CloudWatch API client, creating it on the first call of a lazy Reconciler.
+func (r *Reconciler) client() (cloudwatchiface.CloudWatchAPI, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.api == nil {
+		api, err := r.newAPI()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the CloudWatch client: %w", err)
+		}
+		r.api = api
+	}
+	return r.api, nil
+}
+
+// Reconcile creates or updates the alarms defined by the agent and deletes the ones it no longer defines. The
+// alarms which are already up to date are not put again, and the alarms of an agent which didn't change them since
+// they were last reconciled are only described again after the resync period.
+func (r *Reconciler) Reconcile(ctx context.Context, agent v1alpha1.AmazonCloudWatchAgent) error {
+	desired := Alarms(agent)
+	hash, err := alarmsHash(desired)
+	if err != nil {
+		return err
+	}
+	r.syncMu.Lock()
+	state, ok := r.synced[agent.UID]
+	r.syncMu.Unlock()
+	if ok && state.hash == hash && r.now().Sub(state.at) < resyncPeriod {
+		return nil
+	}
+
+	api, err := r.client()
+	if err != nil {
+		return err
+	}
+	existing, err := describeAlarms(ctx, api, agent)
+	if err != nil {
+		return err
+	}
+	for _, alarm := range desired {
+		name := aws.StringValue(alarm.AlarmName)
+		if current, ok := existing[name]; ok && upToDate(current, alarm) {
+			delete(existing, name)
+			continue
+		}
+		delete(existing, name)
+		if _, err := api.PutMetricAlarmWithContext(ctx, alarm); err != nil {
+			return fmt.Errorf("failed to put the alarm %s: %w", name, err)
+		}
+	}
+	if err := deleteAlarms(ctx, api, existing); err != nil {
+		return err
+	}
+	r.syncMu.Lock()
+	r.synced[agent.UID] = syncState{hash: hash, at: r.now()}
+	r.syncMu.Unlock()
+	return nil
+}
+
+// Delete deletes all the alarms of the agent.
+func (r *Reconciler) Delete(ctx context.Context, agent v1alpha1.AmazonCloudWatchAgent) error {
+	api, err := r.client()
+	if err != nil {
+		return err
+	}
+	existing, err := describeAlarms(ctx, api, agent)
+	if err != nil {
+		return err
+	}
+	if err := deleteAlarms(ctx, api, existing); err != nil {
+		return err
+	}
+	r.syncMu.Lock()
+	delete(r.synced, agent.UID)
+	r.syncMu.Unlock()
+	return nil
+}
+
+// alarmsHash returns the hash of the given alarms.
+func alarmsHash(alarms []*cloudwatch.PutMetricAlarmInput) (string, error) {
+	data, err := json.Marshal(alarms)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the alarms: %w", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// describeAlarms returns the existing alarms of the agent by name.
+func describeAlarms(ctx context.Context, api cloudwatchiface.CloudWatchAPI, agent v1alpha1.AmazonCloudWatchAgent) (map[string]*cloudwatch.MetricAlarm, error) {
+	existing := map[string]*cloudwatch.MetricAlarm{}
+	err := api.DescribeAlarmsPagesWithContext(ctx, &cloudwatch.DescribeAlarmsInput{
+		AlarmNamePrefix: aws.String(namePrefix(agent)),
+		AlarmTypes:      aws.StringSlice([]string{cloudwatch.AlarmTypeMetricAlarm}),
+	}, func(page *cloudwatch.DescribeAlarmsOutput, _ bool) bool {
+		for _, alarm := range page.MetricAlarms {
+			existing[aws.StringValue(alarm.AlarmName)] = alarm
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the alarms: %w", err)
+	}
+	return existing, nil
+}
+
+// deleteAlarms deletes the given alarms.
+func deleteAlarms(ctx context.Context, api cloudwatchiface.CloudWatchAPI, stale map[string]*cloudwatch.MetricAlarm) error {
+	if len(stale) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(stale))
+	for name := range stale {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if _, err := api.DeleteAlarmsWithContext(ctx, &cloudwatch.DeleteAlarmsInput{AlarmNames: aws.StringSlice(names)}); err != nil {
+		return fmt.Errorf("failed to delete the alarms: %w", err)
+	}
+	return nil
+}
+
+// upToDate tells whether the existing alarm already has the settings the operator puts.
+func upToDate(current *cloudwatch.MetricAlarm, desired *cloudwatch.PutMetricAlarmInput) bool {
+	return aws.StringValue(current.AlarmDescription) == aws.StringValue(desired.AlarmDescription) &&
+		aws.StringValue(current.Namespace) == aws.StringValue(desired.Namespace) &&
+		aws.StringValue(current.MetricName) == aws.StringValue(desired.MetricName) &&
+		reflect.DeepEqual(dimensionsOf(current.Dimensions), dimensionsOf(desired.Dimensions)) &&
+		aws.StringValue(current.Statistic) == aws.StringValue(desired.Statistic) &&
+		aws.Int64Value(current.Period) == aws.Int64Value(desired.Period) &&
+		aws.Int64Value(current.EvaluationPeriods) == aws.Int64Value(desired.EvaluationPeriods) &&
+		aws.StringValue(current.ComparisonOperator) == aws.StringValue(desired.ComparisonOperator) &&
+		aws.Float64Value(current.Threshold) == aws.Float64Value(desired.Threshold) &&
+		aws.StringValue(current.TreatMissingData) == aws.StringValue(desired.TreatMissingData) &&
+		reflect.DeepEqual(sortedStrings(current.AlarmActions), sortedStrings(desired.AlarmActions))
+}
+
+func dimensionsOf(dimensions []*cloudwatch.Dimension) map[string]string {
+	values := map[string]string{}
+	for _, dimension := range dimensions {
+		values[aws.StringValue(dimension.Name)] = aws.StringValue(dimension.Value)
+	}
+	return values
+}
+
+func sortedStrings(values []*string) []string {
+	sorted := aws.StringValueSlice(values)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Alarms returns the alarms defined by the agent.
+func Alarms(agent v1alpha1.AmazonCloudWatchAgent) []*cloudwatch.PutMetricAlarmInput {
+	spec := agent.Spec.Alarms
+	if spec == nil {
+		return nil
+	}
+	dimensions := []*cloudwatch.Dimension{{Name: aws.String(clusterNameDimension), Value: aws.String(spec.ClusterName)}}
+
+	var alarms []*cloudwatch.PutMetricAlarmInput
+	if heartbeat := spec.Heartbeat; !heartbeat.Disabled {
+		namespace, metricName := heartbeat.Namespace, heartbeat.MetricName
+		if namespace == "" {
+			namespace = defaultHeartbeatNamespace
+		}
+		if metricName == "" {
+			metricName = defaultHeartbeatMetricName
+		}
+		alarms = append(alarms, &cloudwatch.PutMetricAlarmInput{
+			AlarmName:          aws.String(namePrefix(agent) + heartbeatAlarm),
+			AlarmDescription:   aws.String(fmt.Sprintf("The agents of AmazonCloudWatchAgent %s/%s in cluster %s stopped publishing %s/%s.", agent.Namespace, agent.Name, spec.ClusterName, namespace, metricName)),
+			Namespace:          aws.String(namespace),
+			MetricName:         aws.String(metricName),
+			Dimensions:         dimensions,
+			Statistic:          aws.String(cloudwatch.StatisticSampleCount),
+			Period:             aws.Int64(int64(valueOrDefault(heartbeat.PeriodSeconds, defaultPeriodSeconds))),
+			EvaluationPeriods:  aws.Int64(int64(valueOrDefault(heartbeat.EvaluationPeriods, defaultEvaluationPeriods))),
+			ComparisonOperator: aws.String(cloudwatch.ComparisonOperatorLessThanThreshold),
+			Threshold:          aws.Float64(1),
+			TreatMissingData:   aws.String("breaching"),
+			AlarmActions:       aws.StringSlice(spec.ActionARNs),
+		})
+	}
+	if exportErrors := spec.ExportErrors; exportErrors != nil {
+		alarms = append(alarms, &cloudwatch.PutMetricAlarmInput{
+			AlarmName:          aws.String(namePrefix(agent) + exportErrorsAlarm),
+			AlarmDescription:   aws.String(fmt.Sprintf("The agents of AmazonCloudWatchAgent %s/%s in cluster %s report more than %d %s/%s.", agent.Namespace, agent.Name, spec.ClusterName, exportErrors.Threshold, exportErrors.Namespace, exportErrors.MetricName)),
+			Namespace:          aws.String(exportErrors.Namespace),
+			MetricName:         aws.String(exportErrors.MetricName),
+			Dimensions:         dimensions,
+			Statistic:          aws.String(cloudwatch.StatisticSum),
+			Period:             aws.Int64(int64(valueOrDefault(exportErrors.PeriodSeconds, defaultPeriodSeconds))),
+			EvaluationPeriods:  aws.Int64(int64(valueOrDefault(exportErrors.EvaluationPeriods, defaultEvaluationPeriods))),
+			ComparisonOperator: aws.String(cloudwatch.ComparisonOperatorGreaterThanThreshold),
+			Threshold:          aws.Float64(float64(exportErrors.Threshold)),
+			TreatMissingData:   aws.String("notBreaching"),
+			AlarmActions:       aws.StringSlice(spec.ActionARNs),
+		})
+	}
+	return alarms
+}
+
+// namePrefix is the prefix of the names of the alarms of the agent. It contains the UID of the agent, so that
+// the alarms of agents of the same name in different clusters don't collide.
+func namePrefix(agent v1alpha1.AmazonCloudWatchAgent) string {
+	return fmt.Sprintf("amazon-cloudwatch-agent/%s/%s/%s/", agent.Namespace, agent.Name, agent.UID)
+}
+
+func valueOrDefault(value *int32, defaultValue int32) int32 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
@@ -0,0 +1,202 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package alarms
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// fakeCloudWatch stores the alarms in memory.
+type fakeCloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+	alarms    map[string]*cloudwatch.PutMetricAlarmInput
+	puts      int
+	describes int
+}
+
+func (f *fakeCloudWatch) PutMetricAlarmWithContext(_ aws.Context, input *cloudwatch.PutMetricAlarmInput, _ ...request.Option) (*cloudwatch.PutMetricAlarmOutput, error) {
+	f.alarms[aws.StringValue(input.AlarmName)] = input
+	f.puts++
+	return &cloudwatch.PutMetricAlarmOutput{}, nil
+}
+
+func (f *fakeCloudWatch) DescribeAlarmsPagesWithContext(_ aws.Context, input *cloudwatch.DescribeAlarmsInput, fn func(*cloudwatch.DescribeAlarmsOutput, bool) bool, _ ...request.Option) error {
+	f.describes++
+	page := &cloudwatch.DescribeAlarmsOutput{}
+	for name, alarm := range f.alarms {
+		if strings.HasPrefix(name, aws.StringValue(input.AlarmNamePrefix)) {
+			page.MetricAlarms = append(page.MetricAlarms, &cloudwatch.MetricAlarm{
+				AlarmName:          aws.String(name),
+				AlarmDescription:   alarm.AlarmDescription,
+				Namespace:          alarm.Namespace,
+				MetricName:         alarm.MetricName,
+				Dimensions:         alarm.Dimensions,
+				Statistic:          alarm.Statistic,
+				Period:             alarm.Period,
+				EvaluationPeriods:  alarm.EvaluationPeriods,
+				ComparisonOperator: alarm.ComparisonOperator,
+				Threshold:          alarm.Threshold,
+				TreatMissingData:   alarm.TreatMissingData,
+				AlarmActions:       alarm.AlarmActions,
+			})
+		}
+	}
+	fn(page, true)
+	return nil
+}
+
+func (f *fakeCloudWatch) DeleteAlarmsWithContext(_ aws.Context, input *cloudwatch.DeleteAlarmsInput, _ ...request.Option) (*cloudwatch.DeleteAlarmsOutput, error) {
+	for _, name := range input.AlarmNames {
+		delete(f.alarms, aws.StringValue(name))
+	}
+	return &cloudwatch.DeleteAlarmsOutput{}, nil
+}
+
+func (f *fakeCloudWatch) names() []string {
+	var names []string
+	for name := range f.alarms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestAlarms(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", UID: "uid"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Alarms: &v1alpha1.AlarmsSpec{
+				ClusterName: "cluster",
+				ActionARNs:  []string{"arn:aws:sns:us-west-2:123456789012:topic"},
+				ExportErrors: &v1alpha1.ExportErrorsAlarmSpec{
+					Namespace:     "CWAgent",
+					MetricName:    "exporter_send_failed_metric_points",
+					Threshold:     10,
+					PeriodSeconds: aws.Int32(60),
+				},
+			},
+		},
+	}
+
+	alarms := Alarms(agent)
+	require.Len(t, alarms, 2)
+
+	heartbeat := alarms[0]
+	assert.Equal(t, "amazon-cloudwatch-agent/amazon-cloudwatch/agent/uid/heartbeat", aws.StringValue(heartbeat.AlarmName))
+	assert.Equal(t, "ContainerInsights", aws.StringValue(heartbeat.Namespace))
+	assert.Equal(t, "node_cpu_utilization", aws.StringValue(heartbeat.MetricName))
+	assert.Equal(t, cloudwatch.StatisticSampleCount, aws.StringValue(heartbeat.Statistic))
+	assert.Equal(t, cloudwatch.ComparisonOperatorLessThanThreshold, aws.StringValue(heartbeat.ComparisonOperator))
+	assert.Equal(t, "breaching", aws.StringValue(heartbeat.TreatMissingData))
+	assert.Equal(t, int64(300), aws.Int64Value(heartbeat.Period))
+	assert.Equal(t, int64(3), aws.Int64Value(heartbeat.EvaluationPeriods))
+	assert.Equal(t, []string{"arn:aws:sns:us-west-2:123456789012:topic"}, aws.StringValueSlice(heartbeat.AlarmActions))
+	assert.Equal(t, "cluster", aws.StringValue(heartbeat.Dimensions[0].Value))
+
+	exportErrors := alarms[1]
+	assert.Equal(t, "amazon-cloudwatch-agent/amazon-cloudwatch/agent/uid/export-errors", aws.StringValue(exportErrors.AlarmName))
+	assert.Equal(t, cloudwatch.StatisticSum, aws.StringValue(exportErrors.Statistic))
+	assert.Equal(t, cloudwatch.ComparisonOperatorGreaterThanThreshold, aws.StringValue(exportErrors.ComparisonOperator))
+	assert.Equal(t, float64(10), aws.Float64Value(exportErrors.Threshold))
+	assert.Equal(t, int64(60), aws.Int64Value(exportErrors.Period))
+	assert.Equal(t, "notBreaching", aws.StringValue(exportErrors.TreatMissingData))
+
+	agent.Spec.Alarms.Heartbeat.Disabled = true
+	assert.Len(t, Alarms(agent), 1)
+
+	agent.Spec.Alarms = nil
+	assert.Empty(t, Alarms(agent))
+}
+
+func TestReconcile(t *testing.T) {
+	other := &cloudwatch.PutMetricAlarmInput{AlarmName: aws.String("amazon-cloudwatch-agent/default/other/uid/heartbeat")}
+	api := &fakeCloudWatch{alarms: map[string]*cloudwatch.PutMetricAlarmInput{aws.StringValue(other.AlarmName): other}}
+	r := NewReconciler(api)
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "uid"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Alarms: &v1alpha1.AlarmsSpec{
+				ClusterName:  "cluster",
+				ExportErrors: &v1alpha1.ExportErrorsAlarmSpec{Namespace: "CWAgent", MetricName: "errors"},
+			},
+		},
+	}
+
+	require.NoError(t, r.Reconcile(context.Background(), agent))
+	assert.Equal(t, []string{
+		"amazon-cloudwatch-agent/default/agent/uid/export-errors",
+		"amazon-cloudwatch-agent/default/agent/uid/heartbeat",
+		"amazon-cloudwatch-agent/default/other/uid/heartbeat",
+	}, api.names())
+	assert.Equal(t, 2, api.puts)
+
+	// the alarms which didn't change aren't described again until the resync period elapses
+	require.NoError(t, r.Reconcile(context.Background(), agent))
+	assert.Equal(t, 1, api.describes)
+	now := time.Now().Add(resyncPeriod)
+	r.now = func() time.Time { return now }
+
+	// the alarms which are up to date are not put again
+	delete(api.alarms, "amazon-cloudwatch-agent/default/agent/uid/heartbeat")
+	require.NoError(t, r.Reconcile(context.Background(), agent))
+	assert.Equal(t, 2, api.describes)
+	assert.Equal(t, 3, api.puts)
+	require.Len(t, api.names(), 3)
+	agent.Spec.Alarms.ExportErrors.Threshold = 5
+	require.NoError(t, r.Reconcile(context.Background(), agent))
+	assert.Equal(t, 4, api.puts)
+
+	// the alarms no longer defined are deleted
+	agent.Spec.Alarms.ExportErrors = nil
+	require.NoError(t, r.Reconcile(context.Background(), agent))
+	assert.Equal(t, []string{
+		"amazon-cloudwatch-agent/default/agent/uid/heartbeat",
+		"amazon-cloudwatch-agent/default/other/uid/heartbeat",
+	}, api.names())
+
+	require.NoError(t, r.Delete(context.Background(), agent))
+	assert.Equal(t, []string{"amazon-cloudwatch-agent/default/other/uid/heartbeat"}, api.names())
+}
+
+func TestLazyReconciler(t *testing.T) {
+	api := &fakeCloudWatch{alarms: map[string]*cloudwatch.PutMetricAlarmInput{}}
+	calls := 0
+	failing := true
+	r := NewLazyReconciler(func() (cloudwatchiface.CloudWatchAPI, error) {
+		calls++
+		if failing {
+			return nil, errors.New("no credentials")
+		}
+		return api, nil
+	})
+	assert.Zero(t, calls)
+
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "uid"},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Alarms: &v1alpha1.AlarmsSpec{ClusterName: "cluster"}},
+	}
+	assert.ErrorContains(t, r.Reconcile(context.Background(), agent), "failed to create the CloudWatch client: no credentials")
+
+	// the client is created once it succeeds, and reused afterwards
+	failing = false
+	require.NoError(t, r.Reconcile(context.Background(), agent))
+	require.NoError(t, r.Delete(context.Background(), agent))
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, api.names())
+}
@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bootstrap installs the default Application Signals stack of the self-managed operators, the way the
+// Amazon CloudWatch Observability EKS add-on does: an AmazonCloudWatchAgent and an Instrumentation in a namespace
+// which has none of them.
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents;instrumentations,verbs=list;create
+
+const (
+	// AgentName is the name of the AmazonCloudWatchAgent created by the bootstrap, which the pod mutation
+	// webhook reads the Application Signals config of.
+	AgentName = "cloudwatch-agent"
+	// InstrumentationName is the name of the Instrumentation created by the bootstrap.
+	InstrumentationName = "default-instrumentation"
+	// DefaultAgentServiceAccount is the service account the operator manifests bind to the agent ClusterRole in the
+	// amazon-cloudwatch namespace, after the name prefix of the manifests.
+	DefaultAgentServiceAccount = "cloudwatch-agent"
+
+	retryInterval = 10 * time.Second
+)
+
+var _ manager.Runnable = (*Bootstrapper)(nil)
+
+// Bootstrapper creates the default AmazonCloudWatchAgent and Instrumentation of a namespace once the operator
+// starts. The objects of a kind are only created when the namespace has none, so the objects of the users, or the
+// ones they edited since a previous bootstrap, are never touched.
+type Bootstrapper struct {
+	client client.Client
+	// reader lists the objects from the API server, as the cache may be restricted to some labels or namespaces.
+	reader    client.Reader
+	logger    logr.Logger
+	namespace string
+	// serviceAccount is the service account of the agent, or empty for the operator to create one.
+	serviceAccount string
+	clusterName    string
+	region         string
+}
+
+// New creates a Bootstrapper of the given namespace, whose agent runs as the given service account. The config of the
+// agent names the cluster and the region through the ${cluster_name} and ${region} variables when they are known to
+// the operator, and leaves the agent to detect them from the EC2 instance metadata otherwise.
+func New(c client.Client, reader client.Reader, logger logr.Logger, namespace, serviceAccount, clusterName, region string) *Bootstrapper {
+	return &Bootstrapper{
+		client:         c,
+		reader:         reader,
+		logger:         logger,
+		namespace:      namespace,
+		serviceAccount: serviceAccount,
+		clusterName:    clusterName,
+		region:         region,
+	}
+}
+
+// Start bootstraps the namespace, retrying until it succeeds or the context is done, as the webhooks validating the
+// objects may not serve yet.
+func (b *Bootstrapper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	for {
+		err := b.Bootstrap(ctx)
+		if err == nil {
+			return nil
+		}
+		b.logger.Error(err, "failed to bootstrap the namespace, retrying", "namespace", b.namespace)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection makes sure a single operator replica creates the objects.
+func (b *Bootstrapper) NeedLeaderElection() bool {
+	return true
+}
+
+// Bootstrap creates the default AmazonCloudWatchAgent and Instrumentation of the namespace when it has none.
+func (b *Bootstrapper) Bootstrap(ctx context.Context) error {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := b.reader.List(ctx, &agents, client.InNamespace(b.namespace)); err != nil {
+		return fmt.Errorf("failed to list the AmazonCloudWatchAgents: %w", err)
+	}
+	if len(agents.Items) == 0 {
+		agent, err := b.agent()
+		if err != nil {
+			return err
+		}
+		if err := b.client.Create(ctx, agent); client.IgnoreAlreadyExists(err) != nil {
+			return fmt.Errorf("failed to create the AmazonCloudWatchAgent %s/%s: %w", agent.Namespace, agent.Name, err)
+		}
+		b.logger.Info("created the default AmazonCloudWatchAgent", "namespace", agent.Namespace, "name", agent.Name)
+	}
+
+	var insts v1alpha1.InstrumentationList
+	if err := b.reader.List(ctx, &insts, client.InNamespace(b.namespace)); err != nil {
+		return fmt.Errorf("failed to list the Instrumentations: %w", err)
+	}
+	if len(insts.Items) == 0 {
+		// the Service of the agent follows the naming of the objects of the agents, such as the resource name prefix
+		agent := v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: AgentName}}
+		inst, err := instrumentation.NewBootstrapInstrumentation(b.namespace, InstrumentationName, naming.Service(agent.ResourceName()))
+		if err != nil {
+			return fmt.Errorf("failed to build the default Instrumentation: %w", err)
+		}
+		if err := b.client.Create(ctx, inst); client.IgnoreAlreadyExists(err) != nil {
+			return fmt.Errorf("failed to create the Instrumentation %s/%s: %w", inst.Namespace, inst.Name, err)
+		}
+		b.logger.Info("created the default Instrumentation", "namespace", inst.Namespace, "name", inst.Name)
+	}
+	return nil
+}
+
+// agent returns the default AmazonCloudWatchAgent, a daemonset collecting the enhanced Container Insights and
+// Application Signals.
+func (b *Bootstrapper) agent() (*v1alpha1.AmazonCloudWatchAgent, error) {
+	kubernetes := map[string]interface{}{"enhanced_container_insights": true}
+	if b.clusterName != "" {
+		kubernetes["cluster_name"] = "${cluster_name}"
+	}
+	config := map[string]interface{}{
+		"logs": map[string]interface{}{
+			"metrics_collected": map[string]interface{}{"kubernetes": kubernetes},
+		},
+	}
+	if b.region != "" {
+		config["agent"] = map[string]interface{}{"region": "${region}"}
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: AgentName, Namespace: b.namespace},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:               v1alpha1.ModeDaemonSet,
+			ServiceAccount:     b.serviceAccount,
+			Config:             string(configJSON),
+			ApplicationSignals: &v1alpha1.ApplicationSignalsSpec{Enabled: true},
+		},
+	}, nil
+}
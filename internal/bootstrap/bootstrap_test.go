@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func setInstrumentationImages(t *testing.T) {
+	t.Setenv("AUTO_INSTRUMENTATION_JAVA", "java:v1")
+	t.Setenv("AUTO_INSTRUMENTATION_PYTHON", "python:v1")
+	t.Setenv("AUTO_INSTRUMENTATION_DOTNET", "dotnet:v1")
+	t.Setenv("AUTO_INSTRUMENTATION_NODEJS", "nodejs:v1")
+}
+
+func newClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestBootstrap(t *testing.T) {
+	setInstrumentationImages(t)
+	ctx := context.Background()
+	c := newClient(t)
+	b := New(c, c, logr.Discard(), "observability", DefaultAgentServiceAccount, "demo", "us-west-2")
+	require.NoError(t, b.Bootstrap(ctx))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "observability", Name: AgentName}, agent))
+	assert.Equal(t, v1alpha1.ModeDaemonSet, agent.Spec.Mode)
+	assert.Equal(t, DefaultAgentServiceAccount, agent.Spec.ServiceAccount)
+	assert.True(t, agent.Spec.ApplicationSignals.IsEnabled())
+	assert.JSONEq(t, `{"agent":{"region":"${region}"},"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"${cluster_name}","enhanced_container_insights":true}}}}`, agent.Spec.Config)
+
+	inst := &v1alpha1.Instrumentation{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "observability", Name: InstrumentationName}, inst))
+	assert.Equal(t, "java:v1", inst.Spec.Java.Image)
+	assert.Contains(t, inst.Spec.Java.Env, corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent.observability:4316/v1/traces"})
+
+	// bootstrapping again leaves the objects alone
+	agent.Spec.Config = `{}`
+	require.NoError(t, c.Update(ctx, agent))
+	require.NoError(t, b.Bootstrap(ctx))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(agent), agent))
+	assert.Equal(t, `{}`, agent.Spec.Config)
+}
+
+func TestBootstrapResourceNamePrefix(t *testing.T) {
+	setInstrumentationImages(t)
+	naming.SetResourceNamePrefix("team-a-")
+	defer naming.SetResourceNamePrefix("")
+	ctx := context.Background()
+	c := newClient(t)
+	require.NoError(t, New(c, c, logr.Discard(), "observability", "", "", "").Bootstrap(ctx))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "observability", Name: AgentName}, agent))
+	// the operator creates a service account for the agent
+	assert.Empty(t, agent.Spec.ServiceAccount)
+
+	inst := &v1alpha1.Instrumentation{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "observability", Name: InstrumentationName}, inst))
+	assert.Contains(t, inst.Spec.Java.Env, corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://team-a-cloudwatch-agent.observability:4316/v1/traces"})
+}
+
+func TestBootstrapExistingObjects(t *testing.T) {
+	setInstrumentationImages(t)
+	ctx := context.Background()
+	c := newClient(t,
+		&v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "amazon-cloudwatch", Name: "custom"}},
+	)
+	require.NoError(t, New(c, c, logr.Discard(), "amazon-cloudwatch", DefaultAgentServiceAccount, "", "").Bootstrap(ctx))
+
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	require.NoError(t, c.List(ctx, &agents))
+	require.Len(t, agents.Items, 1)
+	assert.Equal(t, "custom", agents.Items[0].Name)
+	var insts v1alpha1.InstrumentationList
+	require.NoError(t, c.List(ctx, &insts))
+	assert.Len(t, insts.Items, 1)
+}
+
+func TestBootstrapUnknownClusterInfo(t *testing.T) {
+	agent, err := New(nil, nil, logr.Discard(), "amazon-cloudwatch", DefaultAgentServiceAccount, "", "").agent()
+	require.NoError(t, err)
+	// the agent detects the cluster name and the region itself
+	assert.JSONEq(t, `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`, agent.Spec.Config)
+}
@@ -0,0 +1,143 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clusterinfo discovers the name and the region of the cluster the operator runs in, so that they don't
+// have to be set on every agent.
+package clusterinfo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ClusterNameKey and RegionKey are the keys of the cluster info ConfigMap.
+	ClusterNameKey = "cluster_name"
+	RegionKey      = "region"
+
+	// regionLabel is the well-known label of the region of the nodes, set by the cloud provider.
+	regionLabel = "topology.kubernetes.io/region"
+	// eksctlClusterNameLabel is set on the nodes of the clusters created by eksctl.
+	eksctlClusterNameLabel = "alpha.eksctl.io/cluster-name"
+	// instanceClusterNameTag is the instance tag EKS sets on the nodes of its node groups, which the instance
+	// metadata serves when the instance tags are allowed in it.
+	instanceClusterNameTag = "tags/instance/eks:cluster-name"
+)
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=list
+
+// Info is the name and the region of the cluster. Either is empty when it is unknown.
+type Info struct {
+	ClusterName string
+	Region      string
+}
+
+func (i Info) complete() bool {
+	return i.ClusterName != "" && i.Region != ""
+}
+
+// merge fills the unknown fields of the info with the ones of other.
+func (i Info) merge(other Info) Info {
+	if i.ClusterName == "" {
+		i.ClusterName = other.ClusterName
+	}
+	if i.Region == "" {
+		i.Region = other.Region
+	}
+	return i
+}
+
+// Source discovers what it can of the cluster info, leaving the fields it can't determine empty.
+type Source struct {
+	Name     string
+	Discover func(ctx context.Context) (Info, error)
+}
+
+// Discover fills the unknown fields of the given info from the sources, in order, until both are known. A source
+// failing is logged and skipped, so that the operator still starts outside of EC2 or without the permissions of a
+// source.
+func Discover(ctx context.Context, logger logr.Logger, known Info, sources ...Source) Info {
+	info := known
+	for _, source := range sources {
+		if info.complete() {
+			break
+		}
+		discovered, err := source.Discover(ctx)
+		if err != nil {
+			logger.V(1).Info("unable to discover the cluster info", "source", source.Name, "error", err.Error())
+			continue
+		}
+		if merged := info.merge(discovered); merged != info {
+			logger.Info("discovered the cluster info", "source", source.Name, "cluster-name", merged.ClusterName, "region", merged.Region)
+			info = merged
+		}
+	}
+	return info
+}
+
+// ConfigMap reads the cluster info from the cluster_name and region keys of a ConfigMap, which lets the cluster
+// administrator set them once for all the agents. A missing ConfigMap is not an error.
+func ConfigMap(c client.Reader, key types.NamespacedName) Source {
+	return Source{
+		Name: "configmap",
+		Discover: func(ctx context.Context) (Info, error) {
+			cm := &corev1.ConfigMap{}
+			if err := c.Get(ctx, key, cm); err != nil {
+				if apierrors.IsNotFound(err) {
+					return Info{}, nil
+				}
+				return Info{}, fmt.Errorf("failed to get the ConfigMap %s: %w", key, err)
+			}
+			return Info{ClusterName: cm.Data[ClusterNameKey], Region: cm.Data[RegionKey]}, nil
+		},
+	}
+}
+
+// Nodes reads the cluster info from the labels of a node: the region from the well-known topology label, and the
+// cluster name from the label of the clusters created by eksctl.
+func Nodes(c client.Reader) Source {
+	return Source{
+		Name: "nodes",
+		Discover: func(ctx context.Context) (Info, error) {
+			nodes := &corev1.NodeList{}
+			if err := c.List(ctx, nodes, client.Limit(1)); err != nil {
+				return Info{}, fmt.Errorf("failed to list the nodes: %w", err)
+			}
+			if len(nodes.Items) == 0 {
+				return Info{}, nil
+			}
+			labels := nodes.Items[0].Labels
+			return Info{ClusterName: labels[eksctlClusterNameLabel], Region: labels[regionLabel]}, nil
+		},
+	}
+}
+
+// MetadataAPI is the part of the EC2 instance metadata client the discovery uses.
+type MetadataAPI interface {
+	RegionWithContext(ctx context.Context) (string, error)
+	GetMetadataWithContext(ctx context.Context, path string) (string, error)
+}
+
+// InstanceMetadata reads the cluster info from the metadata of the EC2 instance the operator runs on: the region of
+// the instance, and the cluster name from the eks:cluster-name tag of the instance. The pods off the host network
+// only reach the instance metadata when its hop limit allows it.
+func InstanceMetadata(api MetadataAPI) Source {
+	return Source{
+		Name: "instance-metadata",
+		Discover: func(ctx context.Context) (Info, error) {
+			region, err := api.RegionWithContext(ctx)
+			if err != nil {
+				return Info{}, fmt.Errorf("failed to get the region of the instance: %w", err)
+			}
+			// the tags are only served when the instance allows it
+			clusterName, _ := api.GetMetadataWithContext(ctx, instanceClusterNameTag)
+			return Info{ClusterName: clusterName, Region: region}, nil
+		},
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterinfo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeMetadata struct {
+	region string
+	tags   map[string]string
+}
+
+func (m fakeMetadata) RegionWithContext(context.Context) (string, error) {
+	if m.region == "" {
+		return "", errors.New("not running on EC2")
+	}
+	return m.region, nil
+}
+
+func (m fakeMetadata) GetMetadataWithContext(_ context.Context, path string) (string, error) {
+	if tag, ok := m.tags[path]; ok {
+		return tag, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestDiscover(t *testing.T) {
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "cluster-info"}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Data:       map[string]string{ClusterNameKey: "from-configmap"},
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{
+		regionLabel:            "us-west-2",
+		eksctlClusterNameLabel: "from-node",
+	}}}
+	metadata := fakeMetadata{region: "eu-west-1", tags: map[string]string{instanceClusterNameTag: "from-metadata"}}
+
+	tests := []struct {
+		name     string
+		known    Info
+		objects  []client.Object
+		metadata fakeMetadata
+		expected Info
+	}{
+		{
+			name:     "known",
+			known:    Info{ClusterName: "flag", Region: "us-east-1"},
+			objects:  []client.Object{configMap, node},
+			metadata: metadata,
+			expected: Info{ClusterName: "flag", Region: "us-east-1"},
+		},
+		{
+			name:     "configmap then nodes",
+			objects:  []client.Object{configMap, node},
+			metadata: metadata,
+			expected: Info{ClusterName: "from-configmap", Region: "us-west-2"},
+		},
+		{
+			name:     "flag then nodes",
+			known:    Info{ClusterName: "flag"},
+			objects:  []client.Object{node},
+			expected: Info{ClusterName: "flag", Region: "us-west-2"},
+		},
+		{
+			name:     "instance metadata",
+			metadata: metadata,
+			expected: Info{ClusterName: "from-metadata", Region: "eu-west-1"},
+		},
+		{
+			name: "nothing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			info := Discover(ctx, logr.Discard(), tt.known, ConfigMap(c, key), Nodes(c), InstanceMetadata(tt.metadata))
+			assert.Equal(t, tt.expected, info)
+		})
+	}
+}
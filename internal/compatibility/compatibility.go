@@ -0,0 +1,235 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package compatibility checks the versions of the operator, of the agent images and of Kubernetes against a
+// support matrix, so that unsupported combinations are caught before the agents run.
+package compatibility
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/discovery"
+)
+
+//go:embed matrix.yaml
+var embeddedMatrix []byte
+
+// Range bounds versions, both bounds being inclusive. An empty bound is not checked.
+type Range struct {
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
+}
+
+// Entry lists the versions of the agent and of Kubernetes supported by a range of operator versions.
+type Entry struct {
+	Operator   Range `json:"operator"`
+	Agent      Range `json:"agent"`
+	Kubernetes Range `json:"kubernetes"`
+}
+
+// Matrix is the support matrix of the operator.
+type Matrix struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Checker checks the agent images against the support matrix, for the versions of the operator and of Kubernetes
+// it runs with.
+type Checker struct {
+	// Enforce rejects the unsupported agents, which are only warned about otherwise.
+	Enforce bool
+	// Matrix is the support matrix the versions are checked against.
+	Matrix *Matrix
+	// Operator is the version of the operator, the matrix isn't checked when empty, as for development builds.
+	Operator string
+	// Kubernetes is the version of the Kubernetes API server, not checked when empty.
+	Kubernetes string
+	// KubernetesVersion, when set, returns the version of the Kubernetes API server in place of Kubernetes, as it
+	// changes when the cluster is upgraded.
+	KubernetesVersion func() string
+}
+
+// Default returns the support matrix embedded in the operator.
+func Default() (*Matrix, error) {
+	return Parse(embeddedMatrix)
+}
+
+// Load reads a support matrix from the given YAML or JSON file.
+func Load(path string) (*Matrix, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the support matrix: %w", err)
+	}
+	return Parse(content)
+}
+
+// Parse reads a support matrix from YAML or JSON.
+func Parse(content []byte) (*Matrix, error) {
+	matrix := &Matrix{}
+	if err := yaml.Unmarshal(content, matrix); err != nil {
+		return nil, fmt.Errorf("failed to parse the support matrix: %w", err)
+	}
+	for i, entry := range matrix.Entries {
+		for _, r := range []Range{entry.Operator, entry.Agent, entry.Kubernetes} {
+			for _, bound := range []string{r.Min, r.Max} {
+				if _, ok := parseVersion(bound); bound != "" && !ok {
+					return nil, fmt.Errorf("invalid version %q in the entry %d of the support matrix", bound, i)
+				}
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// Check returns the reasons the versions of the operator, of Kubernetes and of the agent running the given image
+// are not supported together. The version of the agent is the tag of the image, which isn't checked when it doesn't
+// hold a version, such as latest.
+func (c *Checker) Check(image string) []string {
+	if c == nil || c.Matrix == nil || c.Operator == "" {
+		return nil
+	}
+	entry, ok := c.Matrix.entry(c.Operator)
+	if !ok {
+		return []string{fmt.Sprintf("the operator version %s is not in the support matrix", c.Operator)}
+	}
+	var problems []string
+	if kubernetes := c.kubernetes(); kubernetes != "" && !entry.Kubernetes.holds(kubernetes) {
+		problems = append(problems, fmt.Sprintf("the Kubernetes version %s is not supported by the operator version %s, which supports %s", kubernetes, c.Operator, entry.Kubernetes))
+	}
+	if agent := imageVersion(image); agent != "" && !entry.Agent.holds(agent) {
+		problems = append(problems, fmt.Sprintf("the agent version %s is not supported by the operator version %s, which supports %s", agent, c.Operator, entry.Agent))
+	}
+	return problems
+}
+
+func (c *Checker) kubernetes() string {
+	if c.KubernetesVersion != nil {
+		return c.KubernetesVersion()
+	}
+	return c.Kubernetes
+}
+
+// ServerVersion returns the version of the Kubernetes API server, read again once the given period has passed since
+// it was last read, so that the upgrades of the cluster are checked without restarting the operator. The last version
+// read is kept while the API server can't tell its version.
+func ServerVersion(client discovery.ServerVersionInterface, period time.Duration, logger logr.Logger) func() string {
+	var (
+		mu      sync.Mutex
+		version string
+		read    time.Time
+	)
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		if !read.IsZero() && time.Since(read) < period {
+			return version
+		}
+		info, err := client.ServerVersion()
+		if err != nil {
+			logger.Error(err, "failed to get the version of the Kubernetes API server")
+			return version
+		}
+		if info.GitVersion != version && version != "" {
+			logger.Info("the version of the Kubernetes API server changed", "previous", version, "kubernetes", info.GitVersion)
+		}
+		version, read = info.GitVersion, time.Now()
+		return version
+	}
+}
+
+// entry returns the first entry of the matrix holding the given operator version.
+func (m *Matrix) entry(operator string) (Entry, bool) {
+	for _, entry := range m.Entries {
+		if entry.Operator.holds(operator) {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// holds tells whether the version is within the range. A version which can't be parsed is out of any range.
+func (r Range) holds(version string) bool {
+	v, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	if bound, ok := parseVersion(r.Min); ok && compare(v, bound) < 0 {
+		return false
+	}
+	if bound, ok := parseVersion(r.Max); ok && compare(v, bound) > 0 {
+		return false
+	}
+	return true
+}
+
+func (r Range) String() string {
+	switch {
+	case r.Min != "" && r.Max != "":
+		return fmt.Sprintf("versions %s to %s", r.Min, r.Max)
+	case r.Min != "":
+		return fmt.Sprintf("versions %s and later", r.Min)
+	case r.Max != "":
+		return fmt.Sprintf("versions up to %s", r.Max)
+	}
+	return "every version"
+}
+
+// imageVersion returns the tag of the image, or an empty string when it doesn't start with a version.
+func imageVersion(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+	if _, ok := parseVersion(tag); !ok {
+		return ""
+	}
+	return tag
+}
+
+// parseVersion returns the numeric components leading the version, such as 1, 300045 and 0 for 1.300045.0b810, or
+// 1, 29 and 3 for v1.29.3-eks-adc7111.
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	var components []int
+	for _, part := range strings.Split(version, ".") {
+		digits := part
+		if i := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+			digits = part[:i]
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			break
+		}
+		components = append(components, n)
+		if len(digits) < len(part) {
+			break
+		}
+	}
+	return components, len(components) > 0
+}
+
+// compare compares the version with the bound on the components of the bound only, so that a bound matches the
+// versions sharing its components.
+func compare(version, bound []int) int {
+	for i, b := range bound {
+		v := 0
+		if i < len(version) {
+			v = version[i]
+		}
+		if v != b {
+			if v < b {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
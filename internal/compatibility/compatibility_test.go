@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package compatibility
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestDefault(t *testing.T) {
+	matrix, err := Default()
+	require.NoError(t, err)
+	assert.NotEmpty(t, matrix.Entries)
+}
+
+func TestDefaultHasNoUpperBound(t *testing.T) {
+	matrix, err := Default()
+	require.NoError(t, err)
+	checker := &Checker{Matrix: matrix, Operator: "9.0.0", Kubernetes: "v1.99.0"}
+	assert.Empty(t, checker.Check("cloudwatch-agent:9.999999.0"))
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "matrix.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("entries:\n- operator: {min: \"2.0.0\"}\n  agent: {min: \"1.300040.0\"}\n"), 0600))
+	matrix, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, matrix.Entries, 1)
+	assert.Equal(t, "1.300040.0", matrix.Entries[0].Agent.Min)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read the support matrix")
+}
+
+type fakeServerVersion struct {
+	info  *version.Info
+	err   error
+	calls int
+}
+
+func (f *fakeServerVersion) ServerVersion() (*version.Info, error) {
+	f.calls++
+	return f.info, f.err
+}
+
+func TestServerVersion(t *testing.T) {
+	server := &fakeServerVersion{info: &version.Info{GitVersion: "v1.29.3"}}
+	get := ServerVersion(server, time.Hour, logr.Discard())
+	assert.Equal(t, "v1.29.3", get())
+	// the version is cached for the period
+	server.info = &version.Info{GitVersion: "v1.30.0"}
+	assert.Equal(t, "v1.29.3", get())
+	assert.Equal(t, 1, server.calls)
+
+	// the upgrade of the cluster is seen once the period has passed
+	get = ServerVersion(server, 0, logr.Discard())
+	assert.Equal(t, "v1.30.0", get())
+	server.info, server.err = nil, errors.New("unavailable")
+	assert.Equal(t, "v1.30.0", get())
+
+	checker := &Checker{Matrix: &Matrix{Entries: []Entry{{Kubernetes: Range{Max: "1.29"}}}}, Operator: "2.0.0", KubernetesVersion: get}
+	assert.Equal(t, []string{"the Kubernetes version v1.30.0 is not supported by the operator version 2.0.0, which supports versions up to 1.29"}, checker.Check("cloudwatch-agent:latest"))
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse([]byte("entries:\n- operator: {min: latest}\n"))
+	assert.ErrorContains(t, err, `invalid version "latest" in the entry 0`)
+}
+
+func TestCheck(t *testing.T) {
+	matrix, err := Parse([]byte(`
+entries:
+- operator: {min: "2.0.0"}
+  agent: {min: "1.300040.0"}
+  kubernetes: {min: "1.25", max: "1.31"}
+- operator: {min: "1.0.0", max: "1"}
+  agent: {min: "1.300032.0", max: "1.300039"}
+  kubernetes: {min: "1.23", max: "1.29"}
+`))
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		checker  *Checker
+		image    string
+		expected []string
+	}{
+		{
+			name:    "supported",
+			checker: &Checker{Matrix: matrix, Operator: "2.0.1", Kubernetes: "v1.31.2-eks-7f9249a"},
+			image:   "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300045.0b810",
+		},
+		{
+			name:    "older operator",
+			checker: &Checker{Matrix: matrix, Operator: "1.7.0", Kubernetes: "v1.29.0"},
+			image:   "cloudwatch-agent:1.300039.1",
+		},
+		{
+			name:    "unsupported kubernetes",
+			checker: &Checker{Matrix: matrix, Operator: "2.0.1", Kubernetes: "v1.32.0"},
+			image:   "cloudwatch-agent:1.300045.0",
+			expected: []string{
+				"the Kubernetes version v1.32.0 is not supported by the operator version 2.0.1, which supports versions 1.25 to 1.31",
+			},
+		},
+		{
+			name:    "unsupported agent",
+			checker: &Checker{Matrix: matrix, Operator: "1.7.0"},
+			image:   "registry:5000/cloudwatch-agent:1.300040.0@sha256:abc",
+			expected: []string{
+				"the agent version 1.300040.0 is not supported by the operator version 1.7.0, which supports versions 1.300032.0 to 1.300039",
+			},
+		},
+		{
+			name:    "image without a version",
+			checker: &Checker{Matrix: matrix, Operator: "2.0.1"},
+			image:   "registry:5000/cloudwatch-agent:latest",
+		},
+		{
+			name:     "operator out of the matrix",
+			checker:  &Checker{Matrix: matrix, Operator: "0.9.0"},
+			image:    "cloudwatch-agent:1.300045.0",
+			expected: []string{"the operator version 0.9.0 is not in the support matrix"},
+		},
+		{
+			name:    "development build",
+			checker: &Checker{Matrix: matrix, Kubernetes: "v1.20.0"},
+			image:   "cloudwatch-agent:1.0.0",
+		},
+		{
+			name:  "disabled",
+			image: "cloudwatch-agent:1.0.0",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.checker.Check(tt.image))
+		})
+	}
+}
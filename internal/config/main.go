@@ -5,9 +5,15 @@
 package config
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/compatibility"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/exporterpolicy"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/promguardrails"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/translator"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 )
 
@@ -16,6 +22,7 @@ const (
 	defaultOtelCollectorConfigMapEntry   = "cwagentotelconfig.yaml"
 	defaultTargetAllocatorConfigMapEntry = "targetallocator.yaml"
 	defaultPrometheusConfigMapEntry      = "prometheus.yaml"
+	defaultControlPlaneMetricsRole       = "cloudwatch-control-plane-metrics"
 )
 
 // Config holds the static configuration for this operator.
@@ -34,9 +41,28 @@ type Config struct {
 	dcgmExporterImage                   string
 	neuronMonitorImage                  string
 	targetAllocatorImage                string
+	prometheusReloaderImage             string
 	targetAllocatorConfigMapEntry       string
 	prometheusConfigMapEntry            string
 	labelsFilter                        []string
+	exporterPolicy                      *exporterpolicy.Policy
+	reconcileInterval                   time.Duration
+	reconcileRetryBaseDelay             time.Duration
+	reconcileRetryMaxDelay              time.Duration
+	configMapHistory                    int
+	prometheusGuardrails                *promguardrails.Guardrails
+	targetAllocatorEnabled              bool
+	clusterName                         string
+	region                              string
+	acceleratedComputeAutoDeploy        bool
+	nodeLocalExport                     bool
+	requireImageDigest                  bool
+	configOverrides                     bool
+	injectionAudit                      bool
+	configTranslator                    translator.Translator
+	compatibilityChecker                *compatibility.Checker
+	kubernetesVersion                   func() string
+	controlPlaneMetricsRole             string
 }
 
 // New constructs a new configuration based on the given options.
@@ -49,6 +75,8 @@ func New(opts ...Option) Config {
 		prometheusConfigMapEntry:      defaultPrometheusConfigMapEntry,
 		logger:                        logf.Log.WithName("config"),
 		version:                       version.Get(),
+		targetAllocatorEnabled:        true,
+		controlPlaneMetricsRole:       defaultControlPlaneMetricsRole,
 	}
 	for _, opt := range opts {
 		opt(&o)
@@ -69,9 +97,28 @@ func New(opts ...Option) Config {
 		dcgmExporterImage:                   o.dcgmExporterImage,
 		neuronMonitorImage:                  o.neuronMonitorImage,
 		targetAllocatorImage:                o.targetAllocatorImage,
+		prometheusReloaderImage:             o.prometheusReloaderImage,
 		targetAllocatorConfigMapEntry:       o.targetAllocatorConfigMapEntry,
 		prometheusConfigMapEntry:            o.prometheusConfigMapEntry,
 		labelsFilter:                        o.labelsFilter,
+		exporterPolicy:                      o.exporterPolicy,
+		reconcileInterval:                   o.reconcileInterval,
+		reconcileRetryBaseDelay:             o.reconcileRetryBaseDelay,
+		reconcileRetryMaxDelay:              o.reconcileRetryMaxDelay,
+		configMapHistory:                    o.configMapHistory,
+		prometheusGuardrails:                o.prometheusGuardrails,
+		targetAllocatorEnabled:              o.targetAllocatorEnabled,
+		clusterName:                         o.clusterName,
+		region:                              o.region,
+		acceleratedComputeAutoDeploy:        o.acceleratedComputeAutoDeploy,
+		nodeLocalExport:                     o.nodeLocalExport,
+		requireImageDigest:                  o.requireImageDigest,
+		configOverrides:                     o.configOverrides,
+		injectionAudit:                      o.injectionAudit,
+		configTranslator:                    o.configTranslator,
+		compatibilityChecker:                o.compatibilityChecker,
+		kubernetesVersion:                   o.kubernetesVersion,
+		controlPlaneMetricsRole:             o.controlPlaneMetricsRole,
 	}
 }
 
@@ -140,6 +187,17 @@ func (c *Config) TargetAllocatorImage() string {
 	return c.targetAllocatorImage
 }
 
+// PrometheusReloaderImage returns the default image of the sidecars reloading the prometheus configuration and the
+// agent config.
+func (c *Config) PrometheusReloaderImage() string {
+	return c.prometheusReloaderImage
+}
+
+// TargetAllocatorEnabled tells whether the operator deploys the target allocators of the agents.
+func (c *Config) TargetAllocatorEnabled() bool {
+	return c.targetAllocatorEnabled
+}
+
 // TargetAllocatorConfigMapEntry represents the configuration file name for the TargetAllocator. Immutable.
 func (c *Config) TargetAllocatorConfigMapEntry() string {
 	return c.targetAllocatorConfigMapEntry
@@ -152,3 +210,104 @@ func (c *Config) PrometheusConfigMapEntry() string { return c.prometheusConfigMa
 func (c *Config) LabelsFilter() []string {
 	return c.labelsFilter
 }
+
+// ExporterPolicy returns the policy restricting the exporters and endpoints allowed in the agent configs, or nil
+// when every exporter is allowed.
+func (c *Config) ExporterPolicy() *exporterpolicy.Policy {
+	return c.exporterPolicy
+}
+
+// ReconcileInterval returns the interval after which the reconciled objects are reconciled again to correct
+// drift, or zero when they are only reconciled on changes.
+func (c *Config) ReconcileInterval() time.Duration {
+	return c.reconcileInterval
+}
+
+// ReconcileRetryBaseDelay returns the delay before retrying a failed reconciliation of an object, doubled after each
+// consecutive failure, or zero for the default.
+func (c *Config) ReconcileRetryBaseDelay() time.Duration {
+	return c.reconcileRetryBaseDelay
+}
+
+// ReconcileRetryMaxDelay returns the maximum delay before retrying a failed reconciliation of an object, or zero for
+// the default.
+func (c *Config) ReconcileRetryMaxDelay() time.Duration {
+	return c.reconcileRetryMaxDelay
+}
+
+// ConfigMapHistory returns the number of previous revisions of the agent ConfigMaps kept for rollbacks, or zero
+// when the history is disabled.
+func (c *Config) ConfigMapHistory() int {
+	return c.configMapHistory
+}
+
+// PrometheusGuardrails returns the limits of the Prometheus scrape configs of the agents, or nil when they are not
+// limited.
+func (c *Config) PrometheusGuardrails() *promguardrails.Guardrails {
+	return c.prometheusGuardrails
+}
+
+// ClusterName returns the name of the cluster the operator runs in, or an empty string when it is unknown.
+func (c *Config) ClusterName() string {
+	return c.clusterName
+}
+
+// Region returns the AWS region of the cluster the operator runs in, or an empty string when it is unknown.
+func (c *Config) Region() string {
+	return c.region
+}
+
+// AcceleratedComputeAutoDeploy tells whether the operator deploys the DCGM exporter and the Neuron monitor on the
+// nodes with GPUs and Neuron devices, when the enhanced Container Insights collect the accelerated compute metrics.
+func (c *Config) AcceleratedComputeAutoDeploy() bool {
+	return c.acceleratedComputeAutoDeploy
+}
+
+// NodeLocalExport tells whether the instrumented pods export to the agent of their own node by default.
+func (c *Config) NodeLocalExport() bool {
+	return c.nodeLocalExport
+}
+
+// RequireImageDigest tells whether the agents are only deployed by image digest, the tags of their images being
+// resolved through the registries.
+func (c *Config) RequireImageDigest() bool {
+	return c.requireImageDigest
+}
+
+// ConfigOverrides tells whether the AmazonCloudWatchAgentConfigOverrides are layered onto the configs of the agents
+// they select when the agents are rendered.
+func (c *Config) ConfigOverrides() bool {
+	return c.configOverrides
+}
+
+// InjectionAudit tells whether the pod webhook only logs and records as Events the mutations of all the pods, rather
+// than applying them.
+func (c *Config) InjectionAudit() bool {
+	return c.injectionAudit
+}
+
+// ConfigTranslator returns the translator of the JSON configs of the agents, whose TOML and YAML translations are
+// projected to the agent ConfigMaps, or nil when the agents translate their configs at start.
+func (c *Config) ConfigTranslator() translator.Translator {
+	return c.configTranslator
+}
+
+// CompatibilityChecker returns the checker of the agent versions against the support matrix of the operator, or nil
+// when the versions aren't checked.
+func (c *Config) CompatibilityChecker() *compatibility.Checker {
+	return c.compatibilityChecker
+}
+
+// KubernetesVersion returns the version of the Kubernetes API server, or an empty version when it isn't known.
+func (c *Config) KubernetesVersion() string {
+	if c.kubernetesVersion == nil {
+		return ""
+	}
+	return c.kubernetesVersion()
+}
+
+// ControlPlaneMetricsRole returns the name of the ClusterRole reading the metrics of the control plane, and of the
+// ClusterRoleBinding whose subjects are the service accounts of the agents scraping them.
+func (c *Config) ControlPlaneMetricsRole() string {
+	return c.controlPlaneMetricsRole
+}
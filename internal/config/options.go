@@ -6,9 +6,14 @@ package config
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/compatibility"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/exporterpolicy"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/promguardrails"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/translator"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 )
 
@@ -31,9 +36,28 @@ type options struct {
 	dcgmExporterImage                   string
 	neuronMonitorImage                  string
 	targetAllocatorImage                string
+	prometheusReloaderImage             string
 	targetAllocatorConfigMapEntry       string
 	prometheusConfigMapEntry            string
 	labelsFilter                        []string
+	exporterPolicy                      *exporterpolicy.Policy
+	reconcileInterval                   time.Duration
+	reconcileRetryBaseDelay             time.Duration
+	reconcileRetryMaxDelay              time.Duration
+	configMapHistory                    int
+	prometheusGuardrails                *promguardrails.Guardrails
+	targetAllocatorEnabled              bool
+	clusterName                         string
+	region                              string
+	acceleratedComputeAutoDeploy        bool
+	nodeLocalExport                     bool
+	requireImageDigest                  bool
+	configOverrides                     bool
+	injectionAudit                      bool
+	configTranslator                    translator.Translator
+	compatibilityChecker                *compatibility.Checker
+	kubernetesVersion                   func() string
+	controlPlaneMetricsRole             string
 }
 
 func WithCollectorImage(s string) Option {
@@ -132,6 +156,13 @@ func WithTargetAllocatorImage(s string) Option {
 	}
 }
 
+// WithPrometheusReloaderImage sets the default image of the sidecar reloading the prometheus configuration.
+func WithPrometheusReloaderImage(s string) Option {
+	return func(o *options) {
+		o.prometheusReloaderImage = s
+	}
+}
+
 func WithLabelFilters(labelFilters []string) Option {
 	return func(o *options) {
 
@@ -156,3 +187,125 @@ func WithLabelFilters(labelFilters []string) Option {
 		o.labelsFilter = filters
 	}
 }
+
+// WithExporterPolicy sets the policy restricting the exporters and endpoints allowed in the agent configs.
+func WithExporterPolicy(policy *exporterpolicy.Policy) Option {
+	return func(o *options) {
+		o.exporterPolicy = policy
+	}
+}
+
+// WithReconcileInterval sets the interval after which the reconciled objects are reconciled again.
+func WithReconcileInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.reconcileInterval = interval
+	}
+}
+
+// WithReconcileRetryDelays sets the base and the maximum delays before retrying a failed reconciliation of an object.
+func WithReconcileRetryDelays(baseDelay, maxDelay time.Duration) Option {
+	return func(o *options) {
+		o.reconcileRetryBaseDelay = baseDelay
+		o.reconcileRetryMaxDelay = maxDelay
+	}
+}
+
+// WithConfigMapHistory sets the number of previous revisions of the agent ConfigMaps kept for rollbacks.
+func WithConfigMapHistory(revisions int) Option {
+	return func(o *options) {
+		o.configMapHistory = revisions
+	}
+}
+
+// WithPrometheusGuardrails sets the limits of the Prometheus scrape configs of the agents.
+func WithPrometheusGuardrails(guardrails *promguardrails.Guardrails) Option {
+	return func(o *options) {
+		o.prometheusGuardrails = guardrails
+	}
+}
+
+// WithTargetAllocatorEnabled sets whether the operator deploys the target allocators of the agents.
+func WithTargetAllocatorEnabled(enabled bool) Option {
+	return func(o *options) {
+		o.targetAllocatorEnabled = enabled
+	}
+}
+
+// WithClusterName sets the name of the cluster the operator runs in.
+func WithClusterName(name string) Option {
+	return func(o *options) {
+		o.clusterName = name
+	}
+}
+
+// WithRegion sets the AWS region of the cluster the operator runs in.
+func WithRegion(region string) Option {
+	return func(o *options) {
+		o.region = region
+	}
+}
+
+// WithAcceleratedComputeAutoDeploy sets whether the operator deploys the DCGM exporter and the Neuron monitor on the
+// nodes with GPUs and Neuron devices.
+func WithAcceleratedComputeAutoDeploy(enabled bool) Option {
+	return func(o *options) {
+		o.acceleratedComputeAutoDeploy = enabled
+	}
+}
+
+// WithNodeLocalExport sets whether the instrumented pods export to the agent of their own node by default.
+func WithNodeLocalExport(enabled bool) Option {
+	return func(o *options) {
+		o.nodeLocalExport = enabled
+	}
+}
+
+// WithRequireImageDigest sets whether the agents are only deployed by image digest.
+func WithRequireImageDigest(required bool) Option {
+	return func(o *options) {
+		o.requireImageDigest = required
+	}
+}
+
+// WithConfigOverrides sets whether the AmazonCloudWatchAgentConfigOverrides are layered onto the configs of the agents.
+func WithConfigOverrides(enabled bool) Option {
+	return func(o *options) {
+		o.configOverrides = enabled
+	}
+}
+
+// WithInjectionAudit sets whether the pod webhook only audits the mutations of all the pods.
+func WithInjectionAudit(enabled bool) Option {
+	return func(o *options) {
+		o.injectionAudit = enabled
+	}
+}
+
+// WithConfigTranslator sets the translator of the JSON configs of the agents.
+func WithConfigTranslator(t translator.Translator) Option {
+	return func(o *options) {
+		o.configTranslator = t
+	}
+}
+
+// WithCompatibilityChecker sets the checker of the agent versions against the support matrix of the operator.
+func WithCompatibilityChecker(checker *compatibility.Checker) Option {
+	return func(o *options) {
+		o.compatibilityChecker = checker
+	}
+}
+
+// WithKubernetesVersion sets the function returning the version of the Kubernetes API server.
+func WithKubernetesVersion(version func() string) Option {
+	return func(o *options) {
+		o.kubernetesVersion = version
+	}
+}
+
+// WithControlPlaneMetricsRole sets the name of the ClusterRole reading the metrics of the control plane, and of its
+// ClusterRoleBinding.
+func WithControlPlaneMetricsRole(name string) Option {
+	return func(o *options) {
+		o.controlPlaneMetricsRole = name
+	}
+}
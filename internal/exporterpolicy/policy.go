@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package exporterpolicy restricts the exporters and the endpoints which the agents are allowed to send
+// telemetry to, so that cluster administrators can allow self-service agent CRs.
+package exporterpolicy
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// endpointKeys are the exporter settings holding the address telemetry is sent to.
+var endpointKeys = []string{"endpoint", "traces_endpoint", "metrics_endpoint", "logs_endpoint"}
+
+// endpointOverrideKey is the agent config setting overriding the address of the AWS service telemetry is sent to.
+const endpointOverrideKey = "endpoint_override"
+
+// Policy lists the exporters and endpoints allowed in the agent configs. An empty list allows everything.
+type Policy struct {
+	// AllowedExporters are the types of the OpenTelemetry exporters the agents may use, e.g. awsemf or awsxray.
+	AllowedExporters []string `json:"allowedExporters,omitempty"`
+	// AllowedEndpoints are the hosts the exporters may send telemetry to. Patterns use the path.Match
+	// syntax, e.g. *.amazonaws.com.
+	AllowedEndpoints []string `json:"allowedEndpoints,omitempty"`
+}
+
+// Load reads the policy from the YAML or JSON file at the given path.
+func Load(file string) (*Policy, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the exporter policy: %w", err)
+	}
+	policy := &Policy{}
+	if err := yaml.Unmarshal(content, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse the exporter policy %s: %w", file, err)
+	}
+	for _, pattern := range policy.AllowedEndpoints {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid allowed endpoint %q in the exporter policy %s: %w", pattern, file, err)
+		}
+	}
+	return policy, nil
+}
+
+// ValidateOtelConfig returns an error when the given OpenTelemetry config uses an exporter or an endpoint
+// which is not allowed.
+func (p *Policy) ValidateOtelConfig(config map[interface{}]interface{}) error {
+	if p == nil {
+		return nil
+	}
+	exporters, ok := config["exporters"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(exporters))
+	for k := range exporters {
+		if name, ok := k.(string); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		exporterType, _, _ := strings.Cut(name, "/")
+		if len(p.AllowedExporters) > 0 && !contains(p.AllowedExporters, exporterType) {
+			return fmt.Errorf("the exporter %q is not allowed by the exporter policy, allowed exporters are %v", name, p.AllowedExporters)
+		}
+		settings, ok := exporters[name].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range endpointKeys {
+			if endpoint, ok := settings[key].(string); ok {
+				if err := p.validateEndpoint(endpoint); err != nil {
+					return fmt.Errorf("the exporter %q is not allowed by the exporter policy, %w", name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateConfig returns an error when the given agent config overrides the endpoint of an AWS service with
+// an endpoint which is not allowed.
+func (p *Policy) ValidateConfig(config map[string]interface{}) error {
+	if p == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch value := config[key].(type) {
+		case string:
+			if key == endpointOverrideKey {
+				if err := p.validateEndpoint(value); err != nil {
+					return fmt.Errorf("the %s setting is not allowed by the exporter policy, %w", endpointOverrideKey, err)
+				}
+			}
+		case map[string]interface{}:
+			if err := p.ValidateConfig(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateEndpoint returns an error when the given endpoint, which telemetry is sent to, is not allowed.
+func (p *Policy) ValidateEndpoint(endpoint string) error {
+	if p == nil {
+		return nil
+	}
+	return p.validateEndpoint(endpoint)
+}
+
+func (p *Policy) validateEndpoint(endpoint string) error {
+	if len(p.AllowedEndpoints) == 0 {
+		return nil
+	}
+	host := endpointHost(endpoint)
+	for _, pattern := range p.AllowedEndpoints {
+		if matched, _ := path.Match(strings.ToLower(pattern), host); matched {
+			return nil
+		}
+	}
+	return fmt.Errorf("the endpoint %q is not allowed, allowed endpoints are %v", endpoint, p.AllowedEndpoints)
+}
+
+// endpointHost returns the lower case host of an endpoint given either as a URL or as a host and port.
+func endpointHost(endpoint string) string {
+	host := endpoint
+	if strings.Contains(endpoint, "://") {
+		if u, err := url.Parse(endpoint); err == nil {
+			host = u.Host
+		}
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "policy.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("allowedExporters: [awsemf]\nallowedEndpoints: ['*.amazonaws.com']\n"), 0600))
+
+	policy, err := Load(file)
+	require.NoError(t, err)
+	assert.Equal(t, &Policy{AllowedExporters: []string{"awsemf"}, AllowedEndpoints: []string{"*.amazonaws.com"}}, policy)
+
+	require.NoError(t, os.WriteFile(file, []byte("allowedEndpoints: ['[']\n"), 0600))
+	_, err = Load(file)
+	assert.ErrorContains(t, err, "invalid allowed endpoint")
+
+	_, err = Load(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestValidateOtelConfig(t *testing.T) {
+	policy := &Policy{
+		AllowedExporters: []string{"awsemf", "otlp"},
+		AllowedEndpoints: []string{"*.amazonaws.com", "collector.observability"},
+	}
+
+	tests := []struct {
+		name        string
+		exporters   map[interface{}]interface{}
+		expectedErr string
+	}{
+		{
+			name:      "no endpoint",
+			exporters: map[interface{}]interface{}{"awsemf": nil},
+		},
+		{
+			name: "allowed endpoints",
+			exporters: map[interface{}]interface{}{
+				"awsemf":   map[interface{}]interface{}{"endpoint": "https://logs.us-east-1.amazonaws.com"},
+				"otlp/int": map[interface{}]interface{}{"endpoint": "Collector.Observability:4317"},
+			},
+		},
+		{
+			name:        "exporter not allowed",
+			exporters:   map[interface{}]interface{}{"datadog": nil},
+			expectedErr: `the exporter "datadog" is not allowed`,
+		},
+		{
+			name: "signal endpoint not allowed",
+			exporters: map[interface{}]interface{}{
+				"otlp": map[interface{}]interface{}{"traces_endpoint": "https://api.honeycomb.io/v1/traces"},
+			},
+			expectedErr: `the endpoint "https://api.honeycomb.io/v1/traces" is not allowed`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := policy.ValidateOtelConfig(map[interface{}]interface{}{"exporters": test.exporters})
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	policy := &Policy{AllowedEndpoints: []string{"*.amazonaws.com"}}
+	config := map[string]interface{}{
+		"metrics": map[string]interface{}{"endpoint_override": "monitoring.us-east-1.amazonaws.com"},
+	}
+	assert.NoError(t, policy.ValidateConfig(config))
+
+	config["logs"] = map[string]interface{}{"endpoint_override": "https://logs.example.com"}
+	assert.ErrorContains(t, policy.ValidateConfig(config), `the endpoint "https://logs.example.com" is not allowed`)
+
+	var nilPolicy *Policy
+	assert.NoError(t, nilPolicy.ValidateConfig(config))
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	policy := &Policy{AllowedEndpoints: []string{"*.amazonaws.com"}}
+	assert.NoError(t, policy.ValidateEndpoint("https://logs.us-gov-west-1.amazonaws.com"))
+	assert.ErrorContains(t, policy.ValidateEndpoint("https://logs.example.com"), `the endpoint "https://logs.example.com" is not allowed`)
+
+	var nilPolicy *Policy
+	assert.NoError(t, nilPolicy.ValidateEndpoint("https://logs.example.com"))
+}
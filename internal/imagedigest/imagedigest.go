@@ -0,0 +1,332 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package imagedigest resolves the tags of container images to the digests of the manifests they point to, so that
+// the agents run immutable image references.
+package imagedigest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	dockerHub         = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+)
+
+// manifestMediaTypes are the manifests accepted from the registries, the multi-platform indexes first so that the
+// digest covers every platform of the image.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// tokenServices are the hosts serving the tokens of the known registries whose token service isn't the registry
+// itself.
+var tokenServices = map[string][]string{
+	dockerHubRegistry: {"auth.docker.io"},
+}
+
+var (
+	digestPattern    = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+	challengePattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// Reference is a container image reference, split into the parts addressing its manifest in a registry.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an image reference such as public.ecr.aws/cloudwatch-agent/cloudwatch-agent:latest. Like
+// the container runtimes, an image without registry is pulled from the Docker Hub, and one without tag nor digest
+// is the latest.
+func ParseReference(image string) (Reference, error) {
+	ref := Reference{}
+	name, digest, found := strings.Cut(image, "@")
+	if found {
+		if !digestPattern.MatchString(digest) {
+			return ref, fmt.Errorf("invalid digest in the image %s", image)
+		}
+		ref.Digest = digest
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		if name[i+1:] == "" {
+			return ref, fmt.Errorf("invalid tag in the image %s", image)
+		}
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if name == "" {
+		return ref, fmt.Errorf("invalid image %s", image)
+	}
+	if first, rest, found := strings.Cut(name, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, ref.Repository = first, rest
+	} else {
+		ref.Registry, ref.Repository = dockerHub, name
+	}
+	if ref.Registry == dockerHub && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// HasDigest tells whether the image is referenced by digest.
+func HasDigest(image string) bool {
+	return Digest(image) != ""
+}
+
+// Digest returns the digest of the image reference, or an empty string when it is referenced by tag only.
+func Digest(image string) string {
+	if _, digest, found := strings.Cut(image, "@"); found {
+		return digest
+	}
+	return ""
+}
+
+// Pin returns the image referenced by the given digest. The tag is kept, for the reference to tell which version
+// runs, but the container runtimes pull the digest.
+func Pin(image, digest string) string {
+	name, _, _ := strings.Cut(image, "@")
+	return name + "@" + digest
+}
+
+// Credentials are the username and password a registry is authenticated with.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Keychain returns the credentials of the given registry host, or nil when it has none for it.
+type Keychain func(ctx context.Context, registry string) (*Credentials, error)
+
+// Resolver resolves the tags of images to digests through the registry API. The registries are authenticated with
+// the credentials of the first keychain having some for them, or queried anonymously, following their bearer token
+// challenge.
+type Resolver struct {
+	client    *http.Client
+	keychains []Keychain
+
+	mu sync.Mutex
+	// digests caches the resolved digests for the lifetime of the operator, for a tag moved to another image not to
+	// roll the agents out on their next reconciliation.
+	digests map[string]string
+}
+
+// NewResolver creates a Resolver sending its requests through the given client, and looking up the credentials of
+// the registries in the given keychains after the ones given to Resolve.
+func NewResolver(client *http.Client, keychains ...Keychain) *Resolver {
+	return &Resolver{client: client, keychains: keychains, digests: map[string]string{}}
+}
+
+// Resolve returns the digest the image points to, which is the one of its reference when it has one. The
+// credentials of the registry are looked up in the given keychains first, such as the one of the image pull
+// secrets of the pods.
+func (r *Resolver) Resolve(ctx context.Context, image string, keychains ...Keychain) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	r.mu.Lock()
+	digest, ok := r.digests[image]
+	r.mu.Unlock()
+	if ok {
+		return digest, nil
+	}
+
+	credentials, err := r.credentials(ctx, ref, append(keychains, r.keychains...))
+	if err != nil {
+		return "", fmt.Errorf("unable to get the credentials of the registry of the image %s: %w", image, err)
+	}
+	digest, err = r.head(ctx, ref, credentials)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the digest of the image %s: %w", image, err)
+	}
+	r.mu.Lock()
+	r.digests[image] = digest
+	r.mu.Unlock()
+	return digest, nil
+}
+
+// credentials returns the credentials of the registry of the reference from the first keychain having some.
+func (r *Resolver) credentials(ctx context.Context, ref Reference, keychains []Keychain) (*Credentials, error) {
+	for _, keychain := range keychains {
+		credentials, err := keychain(ctx, ref.Registry)
+		if err != nil || credentials != nil {
+			return credentials, err
+		}
+	}
+	return nil, nil
+}
+
+// registryHost returns the host serving the registry API of the reference.
+func registryHost(ref Reference) string {
+	if ref.Registry == dockerHub {
+		return dockerHubRegistry
+	}
+	return ref.Registry
+}
+
+// head requests the manifest of the reference, authenticating once when the registry asks for it.
+func (r *Resolver) head(ctx context.Context, ref Reference, credentials *Credentials) (string, error) {
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registryHost(ref), ref.Repository, ref.Tag)
+
+	resp, err := r.do(ctx, http.MethodHead, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := r.authorization(ctx, resp.Header.Get("WWW-Authenticate"), ref, credentials)
+		if err != nil {
+			return "", err
+		}
+		if resp, err = r.do(ctx, http.MethodHead, manifestURL, authorization); err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the registry answered %s", resp.Status)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if !digestPattern.MatchString(digest) {
+		return "", fmt.Errorf("the registry answered an invalid digest %q", digest)
+	}
+	return digest, nil
+}
+
+// authorization returns the Authorization header answering the challenge of the registry: the basic credentials,
+// or a bearer token fetched from the realm of the challenge, with the credentials when there are some.
+func (r *Resolver) authorization(ctx context.Context, challenge string, ref Reference, credentials *Credentials) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch {
+	case strings.EqualFold(scheme, "Basic"):
+		if credentials == nil {
+			return "", errors.New("the registry requires credentials, add an image pull secret to the service account of the agent")
+		}
+		return "Basic " + basicAuth(credentials), nil
+	case strings.EqualFold(scheme, "Bearer"):
+		token, err := r.token(ctx, params, ref, credentials)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("the registry requires the unsupported authentication scheme %q", scheme)
+	}
+}
+
+// token fetches a pull token from the realm of the bearer challenge of the registry. The realm must be served over
+// https by the registry itself, or by the token service of a known registry, so that a registry can't make the
+// operator send requests, or the credentials of the registry, anywhere else.
+func (r *Resolver) token(ctx context.Context, params string, ref Reference, credentials *Credentials) (string, error) {
+	values := map[string]string{}
+	for _, match := range challengePattern.FindAllStringSubmatch(params, -1) {
+		values[match[1]] = match[2]
+	}
+	if values["realm"] == "" {
+		return "", errors.New("the registry didn't tell where to get a token")
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %w", values["realm"], err)
+	}
+	if !trustedRealm(ref, realm) {
+		return "", fmt.Errorf("the token realm %q is neither served over https by the registry nor by its token service", values["realm"])
+	}
+	if values["scope"] == "" {
+		values["scope"] = fmt.Sprintf("repository:%s:pull", ref.Repository)
+	}
+	query := realm.Query()
+	query.Set("scope", values["scope"])
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+	realm.RawQuery = query.Encode()
+
+	authorization := ""
+	if credentials != nil {
+		authorization = "Basic " + basicAuth(credentials)
+	}
+	resp, err := r.do(ctx, http.MethodGet, realm.String(), authorization)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the registry denied a token: %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// trustedRealm tells whether the token realm is served over https by the registry of the reference, or by the token
+// service of a known registry.
+func trustedRealm(ref Reference, realm *url.URL) bool {
+	if realm.Scheme != "https" || realm.User != nil {
+		return false
+	}
+	host := registryHost(ref)
+	if strings.EqualFold(realm.Host, host) {
+		return true
+	}
+	for _, tokenHost := range tokenServices[host] {
+		if strings.EqualFold(realm.Host, tokenHost) {
+			return true
+		}
+	}
+	return false
+}
+
+func basicAuth(credentials *Credentials) string {
+	return base64.StdEncoding.EncodeToString([]byte(credentials.Username + ":" + credentials.Password))
+}
+
+// do sends a request to the registry, closing the body of the answers to HEAD requests.
+func (r *Resolver) do(ctx context.Context, method, target, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodHead {
+		req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodHead {
+		resp.Body.Close()
+	}
+	return resp, nil
+}
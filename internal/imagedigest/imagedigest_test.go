@@ -0,0 +1,145 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagedigest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParseReference(t *testing.T) {
+	for image, expected := range map[string]Reference{
+		"public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300032": {Registry: "public.ecr.aws", Repository: "cloudwatch-agent/cloudwatch-agent", Tag: "1.300032"},
+		"localhost:5000/agent": {Registry: "localhost:5000", Repository: "agent", Tag: "latest"},
+		"ubuntu":               {Registry: "docker.io", Repository: "library/ubuntu", Tag: "latest"},
+		"amazon/cloudwatch-agent:latest@" + testDigest: {Registry: "docker.io", Repository: "amazon/cloudwatch-agent", Tag: "latest", Digest: testDigest},
+	} {
+		ref, err := ParseReference(image)
+		require.NoError(t, err, image)
+		assert.Equal(t, expected, ref, image)
+	}
+
+	for _, image := range []string{"agent:", "agent@sha256:abc", ""} {
+		_, err := ParseReference(image)
+		assert.Error(t, err, image)
+	}
+}
+
+func TestPin(t *testing.T) {
+	assert.Equal(t, "agent:1.0@"+testDigest, Pin("agent:1.0", testDigest))
+	assert.Equal(t, "agent:1.0@"+testDigest, Pin("agent:1.0@sha256:other", testDigest))
+	assert.Equal(t, testDigest, Digest("agent:1.0@"+testDigest))
+	assert.False(t, HasDigest("agent:1.0"))
+}
+
+func TestResolve(t *testing.T) {
+	heads := 0
+	var registry *httptest.Server
+	registry = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			assert.Equal(t, "repository:cloudwatch-agent/cloudwatch-agent:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+		case "/v2/cloudwatch-agent/cloudwatch-agent/manifests/1.0":
+			assert.Equal(t, http.MethodHead, r.Method)
+			assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json")
+			if r.Header.Get("Authorization") != "Bearer anonymous" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+registry.URL+`/token",service="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			heads++
+			w.Header().Set("Docker-Content-Digest", testDigest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+
+	resolver := NewResolver(registry.Client())
+	ctx := context.Background()
+	digest, err := resolver.Resolve(ctx, host+"/cloudwatch-agent/cloudwatch-agent:1.0")
+	require.NoError(t, err)
+	assert.Equal(t, testDigest, digest)
+
+	// the digest is cached
+	_, err = resolver.Resolve(ctx, host+"/cloudwatch-agent/cloudwatch-agent:1.0")
+	require.NoError(t, err)
+	assert.Equal(t, 1, heads)
+
+	_, err = resolver.Resolve(ctx, host+"/cloudwatch-agent/cloudwatch-agent:2.0")
+	assert.ErrorContains(t, err, "404 Not Found")
+
+	// an image referenced by digest isn't resolved
+	digest, err = resolver.Resolve(ctx, "unknown.example.com/agent@"+testDigest)
+	require.NoError(t, err)
+	assert.Equal(t, testDigest, digest)
+}
+
+func TestResolveWithCredentials(t *testing.T) {
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "AWS" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="ecr"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", testDigest)
+	}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+	keychain := func(_ context.Context, registry string) (*Credentials, error) {
+		if registry != host {
+			return nil, nil
+		}
+		return &Credentials{Username: "AWS", Password: "secret"}, nil
+	}
+
+	_, err := NewResolver(registry.Client()).Resolve(context.Background(), host+"/private:1.0")
+	assert.ErrorContains(t, err, "the registry requires credentials")
+
+	digest, err := NewResolver(registry.Client()).Resolve(context.Background(), host+"/private:1.0", keychain)
+	require.NoError(t, err)
+	assert.Equal(t, testDigest, digest)
+
+	digest, err = NewResolver(registry.Client(), keychain).Resolve(context.Background(), host+"/private:1.0")
+	require.NoError(t, err)
+	assert.Equal(t, testDigest, digest)
+}
+
+func TestResolveUntrustedRealm(t *testing.T) {
+	for _, realm := range []string{"http://%s/token", "https://169.254.169.254/latest/meta-data", "file:///etc/passwd", "https://user@%s/token"} {
+		tokens := 0
+		var registry *httptest.Server
+		registry = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				tokens++
+				_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+				return
+			}
+			challengeRealm := realm
+			if strings.Contains(realm, "%s") {
+				challengeRealm = fmt.Sprintf(realm, strings.TrimPrefix(registry.URL, "https://"))
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+challengeRealm+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		host := strings.TrimPrefix(registry.URL, "https://")
+
+		_, err := NewResolver(registry.Client()).Resolve(context.Background(), host+"/agent:1.0")
+		assert.ErrorContains(t, err, "is neither served over https by the registry nor by its token service", realm)
+		assert.Zero(t, tokens, realm)
+		registry.Close()
+	}
+}
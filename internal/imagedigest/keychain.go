@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagedigest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ecrRegistryPattern matches the hosts of the private Amazon ECR registries, capturing their region.
+var ecrRegistryPattern = regexp.MustCompile(`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// dockerHubKeys are the keys the Docker Hub credentials are stored under in the Docker configs.
+var dockerHubKeys = []string{"https://index.docker.io/v1/", "index.docker.io", dockerHub, dockerHubRegistry}
+
+// dockerConfig is the content of the kubernetes.io/dockerconfigjson Secrets, or the auths of the
+// kubernetes.io/dockercfg ones.
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// PullSecretsKeychain returns the keychain of the credentials of the given image pull secrets, the first Secret
+// having credentials for a registry winning like for the kubelet.
+func PullSecretsKeychain(secrets []corev1.Secret) Keychain {
+	return func(_ context.Context, registry string) (*Credentials, error) {
+		keys := []string{registry, "https://" + registry, "http://" + registry}
+		if registry == dockerHub {
+			keys = dockerHubKeys
+		}
+		for _, secret := range secrets {
+			var config dockerConfig
+			switch secret.Type {
+			case corev1.SecretTypeDockerConfigJson:
+				if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+					return nil, fmt.Errorf("invalid image pull secret %s: %w", secret.Name, err)
+				}
+			case corev1.SecretTypeDockercfg:
+				if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &config.Auths); err != nil {
+					return nil, fmt.Errorf("invalid image pull secret %s: %w", secret.Name, err)
+				}
+			default:
+				continue
+			}
+			for _, key := range keys {
+				entry, ok := config.Auths[key]
+				if !ok {
+					continue
+				}
+				if entry.Auth != "" {
+					decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+					if err != nil {
+						return nil, fmt.Errorf("invalid auth of %s in the image pull secret %s: %w", key, secret.Name, err)
+					}
+					entry.Username, entry.Password, _ = strings.Cut(string(decoded), ":")
+				}
+				return &Credentials{Username: entry.Username, Password: entry.Password}, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+// ecrToken is an authorization token of a private Amazon ECR registry.
+type ecrToken struct {
+	credentials *Credentials
+	expiresAt   time.Time
+}
+
+// ECRKeychain returns the keychain of the private Amazon ECR registries, authenticated with the authorization
+// tokens the operator gets with its own AWS credentials, through the ECR API client of the region of the registry.
+// The tokens are cached until shortly before they expire.
+func ECRKeychain(newAPI func(region string) (ecriface.ECRAPI, error)) Keychain {
+	var mu sync.Mutex
+	tokens := map[string]ecrToken{}
+	return func(ctx context.Context, registry string) (*Credentials, error) {
+		match := ecrRegistryPattern.FindStringSubmatch(registry)
+		if match == nil {
+			return nil, nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if token, ok := tokens[registry]; ok && time.Now().Before(token.expiresAt) {
+			return token.credentials, nil
+		}
+
+		api, err := newAPI(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the ECR client: %w", err)
+		}
+		output, err := api.GetAuthorizationTokenWithContext(ctx, &ecr.GetAuthorizationTokenInput{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get an ECR authorization token: %w", err)
+		}
+		if len(output.AuthorizationData) == 0 {
+			return nil, fmt.Errorf("ECR returned no authorization token")
+		}
+		data := output.AuthorizationData[0]
+		decoded, err := base64.StdEncoding.DecodeString(aws.StringValue(data.AuthorizationToken))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ECR authorization token: %w", err)
+		}
+		username, password, _ := strings.Cut(string(decoded), ":")
+		token := ecrToken{credentials: &Credentials{Username: username, Password: password}, expiresAt: time.Now().Add(time.Hour)}
+		if data.ExpiresAt != nil {
+			token.expiresAt = data.ExpiresAt.Add(-5 * time.Minute)
+		}
+		tokens[registry] = token
+		return token.credentials, nil
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package imagedigest
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPullSecretsKeychain(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:password"))
+	keychain := PullSecretsKeychain([]corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "opaque"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{"registry.example.com":{"username":"opaque"}}}`)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "dockerconfigjson"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{` +
+				`"https://registry.example.com":{"auth":"` + auth + `"},` +
+				`"https://index.docker.io/v1/":{"username":"hub","password":"token"}}}`)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "dockercfg"},
+			Type:       corev1.SecretTypeDockercfg,
+			Data:       map[string][]byte{corev1.DockerConfigKey: []byte(`{"registry.example.com":{"username":"legacy"}}`)},
+		},
+	})
+	ctx := context.Background()
+
+	credentials, err := keychain(ctx, "registry.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: "user", Password: "password"}, credentials)
+	credentials, err = keychain(ctx, dockerHub)
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: "hub", Password: "token"}, credentials)
+	credentials, err = keychain(ctx, "public.ecr.aws")
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+}
+
+type fakeECR struct {
+	ecriface.ECRAPI
+	calls int
+}
+
+func (f *fakeECR) GetAuthorizationTokenWithContext(aws.Context, *ecr.GetAuthorizationTokenInput, ...request.Option) (*ecr.GetAuthorizationTokenOutput, error) {
+	f.calls++
+	return &ecr.GetAuthorizationTokenOutput{AuthorizationData: []*ecr.AuthorizationData{{
+		AuthorizationToken: aws.String(base64.StdEncoding.EncodeToString([]byte("AWS:secret"))),
+		ExpiresAt:          aws.Time(time.Now().Add(12 * time.Hour)),
+	}}}, nil
+}
+
+func TestECRKeychain(t *testing.T) {
+	api := &fakeECR{}
+	var regions []string
+	keychain := ECRKeychain(func(region string) (ecriface.ECRAPI, error) {
+		regions = append(regions, region)
+		return api, nil
+	})
+	ctx := context.Background()
+
+	credentials, err := keychain(ctx, "111111111111.dkr.ecr.us-west-2.amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, &Credentials{Username: "AWS", Password: "secret"}, credentials)
+	assert.Equal(t, []string{"us-west-2"}, regions)
+
+	// the token is cached until it expires
+	_, err = keychain(ctx, "111111111111.dkr.ecr.us-west-2.amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, api.calls)
+
+	// the other registries aren't authenticated with the credentials of the operator
+	credentials, err = keychain(ctx, "public.ecr.aws")
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+	assert.Equal(t, 1, api.calls)
+}
@@ -0,0 +1,227 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package injectionstats counts the pods the pod webhook injects each Instrumentation into, and flushes the counts
+// to the status of the Instrumentations, so that users can verify the auto-instrumentation happens.
+package injectionstats
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// flushInterval is the interval the counts are flushed to the status of the Instrumentations at.
+const flushInterval = 30 * time.Second
+
+// Pod collects the injections into a single pod, which counts once per Instrumentation and language, however many
+// of its containers are injected.
+type Pod struct {
+	results map[injection]error
+}
+
+type injection struct {
+	instrumentation types.NamespacedName
+	language        string
+}
+
+// NewPod returns the collector of the injections into a pod.
+func NewPod() *Pod {
+	return &Pod{results: map[injection]error{}}
+}
+
+// Record records the injection of the instrumentation of the language into a container of the pod, which failed
+// when err is set. The pod counts as injected once any of its containers is.
+func (p *Pod) Record(instrumentation types.NamespacedName, language string, err error) {
+	key := injection{instrumentation: instrumentation, language: language}
+	if previous, ok := p.results[key]; ok && previous == nil {
+		return
+	}
+	p.results[key] = err
+}
+
+// Stats accumulates the injections of the pod webhook until they are added to the counts in the status of the
+// Instrumentations. Only the elected operator replica writes the status, so the injections served by the other
+// replicas aren't counted.
+type Stats struct {
+	client  client.Client
+	logger  logr.Logger
+	now     func() time.Time
+	elected atomic.Bool
+
+	mu      sync.Mutex
+	pending map[types.NamespacedName]*counts
+}
+
+// counts are the injections of an Instrumentation not flushed yet.
+type counts struct {
+	injected      map[string]int64
+	failed        map[string]int64
+	lastInjection time.Time
+	lastError     string
+}
+
+var _ manager.LeaderElectionRunnable = &Stats{}
+
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=instrumentations/status,verbs=get;update
+
+// New returns the statistics of the injections, flushed through the given client.
+func New(c client.Client, logger logr.Logger) *Stats {
+	return &Stats{
+		client:  c,
+		logger:  logger,
+		now:     time.Now,
+		pending: map[types.NamespacedName]*counts{},
+	}
+}
+
+// Add counts the injections into the pod. The statistics of a nil Stats, or of a replica which isn't elected, aren't
+// kept.
+func (s *Stats) Add(pod *Pod) {
+	if s == nil || len(pod.results) == 0 || !s.elected.Load() {
+		return
+	}
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, err := range pod.results {
+		c := s.countsOf(key.instrumentation)
+		if err != nil {
+			c.failed[key.language]++
+			c.lastError = err.Error()
+			continue
+		}
+		c.injected[key.language]++
+		c.lastInjection = now
+	}
+}
+
+// countsOf returns the pending counts of the instrumentation, the lock being held.
+func (s *Stats) countsOf(instrumentation types.NamespacedName) *counts {
+	c, ok := s.pending[instrumentation]
+	if !ok {
+		c = &counts{injected: map[string]int64{}, failed: map[string]int64{}}
+		s.pending[instrumentation] = c
+	}
+	return c
+}
+
+// Start counts the injections once elected, and flushes the counts at regular intervals, and once more when the
+// operator stops.
+func (s *Stats) Start(ctx context.Context) error {
+	s.elected.Store(true)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := s.Flush(flushCtx); err != nil {
+				s.logger.Error(err, "failed to flush the injection statistics")
+			}
+			return nil
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				s.logger.Error(err, "failed to flush the injection statistics")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection is true, a single replica updates the status of the Instrumentations.
+func (s *Stats) NeedLeaderElection() bool {
+	return true
+}
+
+// Flush adds the pending counts to the status of the Instrumentations. The counts of an Instrumentation which
+// no longer exists are dropped, while the ones failing to be added are kept for the next flush.
+func (s *Stats) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = map[types.NamespacedName]*counts{}
+	s.mu.Unlock()
+
+	var errs []error
+	for name, c := range pending {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			instrumentation := &v1alpha1.Instrumentation{}
+			if err := s.client.Get(ctx, name, instrumentation); err != nil {
+				return err
+			}
+			c.addTo(&instrumentation.Status)
+			return s.client.Status().Update(ctx, instrumentation)
+		})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			s.restore(name, c)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restore returns the counts which failed to be flushed to the pending ones.
+func (s *Stats) restore(name types.NamespacedName, c *counts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.countsOf(name)
+	for language, n := range c.injected {
+		pending.injected[language] += n
+	}
+	for language, n := range c.failed {
+		pending.failed[language] += n
+	}
+	if c.lastInjection.After(pending.lastInjection) {
+		pending.lastInjection = c.lastInjection
+	}
+	if pending.lastError == "" {
+		pending.lastError = c.lastError
+	}
+}
+
+// addTo adds the counts to the status of an Instrumentation.
+func (c *counts) addTo(status *v1alpha1.InstrumentationStatus) {
+	languages := map[string]bool{}
+	for language := range c.injected {
+		languages[language] = true
+	}
+	for language := range c.failed {
+		languages[language] = true
+	}
+	for language := range languages {
+		i := 0
+		for i < len(status.Languages) && status.Languages[i].Language != language {
+			i++
+		}
+		if i == len(status.Languages) {
+			status.Languages = append(status.Languages, v1alpha1.InstrumentationLanguageStatus{Language: language})
+		}
+		status.Languages[i].InjectedPods += c.injected[language]
+		status.Languages[i].FailedPods += c.failed[language]
+	}
+	sort.Slice(status.Languages, func(i, j int) bool {
+		return status.Languages[i].Language < status.Languages[j].Language
+	})
+	if !c.lastInjection.IsZero() && (status.LastInjectionTime == nil || status.LastInjectionTime.Time.Before(c.lastInjection)) {
+		status.LastInjectionTime = &metav1.Time{Time: c.lastInjection}
+	}
+	if c.lastError != "" {
+		status.LastError = c.lastError
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package injectionstats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestFlush(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	instrumentation := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "app"},
+		Status: v1alpha1.InstrumentationStatus{
+			Languages: []v1alpha1.InstrumentationLanguageStatus{{Language: "python", InjectedPods: 2}},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instrumentation).WithStatusSubresource(instrumentation).Build()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stats := New(cli, logr.Discard())
+	stats.now = func() time.Time { return now }
+	stats.elected.Store(true)
+
+	name := client.ObjectKeyFromObject(instrumentation)
+	// a pod whose two containers are injected with java counts once
+	pod := NewPod()
+	pod.Record(name, "java", nil)
+	pod.Record(name, "java", errors.New("the container already sets JAVA_TOOL_OPTIONS"))
+	stats.Add(pod)
+	pod = NewPod()
+	pod.Record(name, "python", errors.New("multiple injection annotations present"))
+	stats.Add(pod)
+	// the injections of a deleted instrumentation are dropped
+	pod = NewPod()
+	pod.Record(types.NamespacedName{Name: "deleted", Namespace: "app"}, "java", nil)
+	stats.Add(pod)
+
+	require.NoError(t, stats.Flush(ctx))
+	require.NoError(t, cli.Get(ctx, name, instrumentation))
+	assert.Equal(t, []v1alpha1.InstrumentationLanguageStatus{
+		{Language: "java", InjectedPods: 1},
+		{Language: "python", InjectedPods: 2, FailedPods: 1},
+	}, instrumentation.Status.Languages)
+	require.NotNil(t, instrumentation.Status.LastInjectionTime)
+	assert.True(t, now.Equal(instrumentation.Status.LastInjectionTime.Time))
+	assert.Equal(t, "multiple injection annotations present", instrumentation.Status.LastError)
+	assert.Empty(t, stats.pending)
+
+	// the counts add up across flushes
+	pod = NewPod()
+	pod.Record(name, "java", nil)
+	stats.Add(pod)
+	require.NoError(t, stats.Flush(ctx))
+	require.NoError(t, cli.Get(ctx, name, instrumentation))
+	assert.Equal(t, int64(2), instrumentation.Status.Languages[0].InjectedPods)
+}
+
+func TestAddWithoutStats(t *testing.T) {
+	var stats *Stats
+	pod := NewPod()
+	pod.Record(types.NamespacedName{Name: "default", Namespace: "app"}, "java", nil)
+	stats.Add(pod)
+}
+
+func TestAddNotElected(t *testing.T) {
+	stats := New(fake.NewClientBuilder().Build(), logr.Discard())
+	pod := NewPod()
+	pod.Record(types.NamespacedName{Name: "default", Namespace: "app"}, "java", nil)
+	stats.Add(pod)
+	assert.Empty(t, stats.pending)
+}
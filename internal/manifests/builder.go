@@ -4,12 +4,14 @@
 package manifests
 
 import (
+	"context"
 	"reflect"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-type Builder func(params Params) ([]client.Object, error)
+// Builder builds the manifests of a resource, the context bounding the external commands some of them run.
+type Builder func(ctx context.Context, params Params) ([]client.Object, error)
 
 type ManifestFactory[T client.Object] func(params Params) (T, error)
 type SimpleManifestFactory[T client.Object] func(params Params) T
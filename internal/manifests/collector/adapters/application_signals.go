@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// applicationSignalsSections are the sections of the agent config collecting Application Signals.
+var applicationSignalsSections = [][]string{
+	{"logs", "metrics_collected"},
+	{"traces", "traces_collected"},
+}
+
+// ConfigWithApplicationSignals adds the application_signals sections to the given agent config, unless they are
+// already defined, under their current or their former app_signals name.
+func ConfigWithApplicationSignals(configStr string) (string, error) {
+	config := map[string]interface{}{}
+	if strings.TrimSpace(configStr) != "" {
+		var err error
+		if config, err = ConfigFromJSONString(configStr); err != nil {
+			return "", err
+		}
+	}
+	for _, path := range applicationSignalsSections {
+		section := config
+		for _, key := range path {
+			next, ok := section[key].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				section[key] = next
+			}
+			section = next
+		}
+		_, hasApplicationSignals := section["application_signals"]
+		_, hasAppSignals := section["app_signals"]
+		if !hasApplicationSignals && !hasAppSignals {
+			section["application_signals"] = map[string]interface{}{}
+		}
+	}
+	out, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigWithApplicationSignals(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		config string
+		expect string
+	}{
+		{
+			name:   "empty config",
+			config: "",
+			expect: `{"logs":{"metrics_collected":{"application_signals":{}}},"traces":{"traces_collected":{"application_signals":{}}}}`,
+		},
+		{
+			name:   "existing sections",
+			config: `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`,
+			expect: `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"application_signals":{},"kubernetes":{"enhanced_container_insights":true}}},"traces":{"traces_collected":{"application_signals":{}}}}`,
+		},
+		{
+			name:   "configured application signals",
+			config: `{"logs":{"metrics_collected":{"application_signals":{"hosted_in":"cluster"}}},"traces":{"traces_collected":{"app_signals":{}}}}`,
+			expect: `{"logs":{"metrics_collected":{"application_signals":{"hosted_in":"cluster"}}},"traces":{"traces_collected":{"app_signals":{}}}}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ConfigWithApplicationSignals(tt.config)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expect, config)
+		})
+	}
+
+	_, err := ConfigWithApplicationSignals("not json")
+	assert.ErrorIs(t, err, ErrInvalidJSON)
+}
@@ -7,6 +7,8 @@ package adapters
 import (
 	"encoding/json"
 	"errors"
+	"path"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
@@ -49,6 +51,7 @@ type Metrics struct {
 
 type Logs struct {
 	LogMetricsCollected *LogMetricsCollected `json:"metrics_collected,omitempty"`
+	LogsCollected       *LogsCollected       `json:"logs_collected,omitempty"`
 }
 
 type Traces struct {
@@ -70,6 +73,11 @@ type LogMetricsCollected struct {
 	OTLP               *otlp       `json:"otlp,omitempty"`
 }
 
+type LogsCollected struct {
+	Files         *files         `json:"files,omitempty"`
+	WindowsEvents *windowsEvents `json:"windows_events,omitempty"`
+}
+
 type TracesCollected struct {
 	XRay               *xray       `json:"xray,omitempty"`
 	OTLP               *otlp       `json:"otlp,omitempty"`
@@ -95,9 +103,18 @@ type emf struct {
 type jmx struct{}
 
 type kubernetes struct {
-	EnhancedContainerInsights bool `json:"enhanced_container_insights,omitempty"`
-	AcceleratedComputeMetrics bool `json:"accelerated_compute_metrics,omitempty"`
-	JMXContainerInsights      bool `json:"jmx_container_insights,omitempty"`
+	ClusterName               string `json:"cluster_name,omitempty"`
+	EnhancedContainerInsights bool   `json:"enhanced_container_insights,omitempty"`
+	AcceleratedComputeMetrics bool   `json:"accelerated_compute_metrics,omitempty"`
+	JMXContainerInsights      bool   `json:"jmx_container_insights,omitempty"`
+}
+
+type files struct {
+	CollectList []collectList `json:"collect_list,omitempty"`
+}
+
+type collectList struct {
+	FilePath string `json:"file_path,omitempty"`
 }
 
 type xray struct {
@@ -159,3 +176,46 @@ func (c *CwaConfig) GetApplicationSignalsTracesConfig() *AppSignals {
 	}
 	return nil
 }
+
+// GetLogFilePaths returns the file paths (which may contain wildcards) collected by the logs.logs_collected.files section.
+func (c *CwaConfig) GetLogFilePaths() []string {
+	if c.Logs == nil || c.Logs.LogsCollected == nil || c.Logs.LogsCollected.Files == nil {
+		return nil
+	}
+	var paths []string
+	for _, entry := range c.Logs.LogsCollected.Files.CollectList {
+		if entry.FilePath != "" {
+			paths = append(paths, entry.FilePath)
+		}
+	}
+	return paths
+}
+
+// minLogDirectoryDepth is the minimum number of levels of the host directories mounted for the log files, so that a
+// file path with wildcards close to the root, such as /*.log, /{a,b}/x.log or /**/app.log, doesn't mount the root
+// of the host, or one of its top-level directories.
+const minLogDirectoryDepth = 2
+
+// LogDirectory returns the deepest directory of the given file path that does not contain wildcards, or an empty
+// string when the path is not an absolute Linux path or when the directory is less than two levels deep.
+func LogDirectory(filePath string) string {
+	if !strings.HasPrefix(filePath, "/") {
+		return ""
+	}
+	dir := path.Dir(path.Clean(filePath))
+	for strings.ContainsAny(dir, "*?[{") {
+		dir = path.Dir(dir)
+	}
+	if dir == "/" || strings.Count(dir, "/") < minLogDirectoryDepth {
+		return ""
+	}
+	return dir
+}
+
+// GetClusterName returns the cluster_name of the logs.metrics_collected.kubernetes section.
+func (c *CwaConfig) GetClusterName() string {
+	if c.Logs == nil || c.Logs.LogMetricsCollected == nil || c.Logs.LogMetricsCollected.Kubernetes == nil {
+		return ""
+	}
+	return c.Logs.LogMetricsCollected.Kubernetes.ClusterName
+}
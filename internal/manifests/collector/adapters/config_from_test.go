@@ -26,3 +26,41 @@ func TestEmptyString(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, res, 0)
 }
+
+func TestLogDirectory(t *testing.T) {
+	for filePath, expected := range map[string]string{
+		"/var/log/containers/*.log": "/var/log/containers",
+		"/var/log/app.log":          "/var/log",
+		"/var/log/*/app.log":        "/var/log",
+		"/opt/app/**/logs/*.log":    "/opt/app",
+		"/data/app.log":             "",
+		"/*.log":                    "",
+		"/{a,b}/x.log":              "",
+		"/**/app.log":               "",
+		"C:\\ProgramData\\app.log":  "",
+	} {
+		assert.Equal(t, expected, adapters.LogDirectory(filePath), filePath)
+	}
+}
+
+func TestGetLogFilePaths(t *testing.T) {
+	// prepare
+	config, err := adapters.ConfigStructFromJSONString(`{
+  "logs": {
+    "logs_collected": {
+      "files": {
+        "collect_list": [
+          {"file_path": "/var/log/containers/*.log"},
+          {"log_group_name": "missing-path"},
+          {"file_path": "/var/log/app.log"}
+        ]
+      }
+    }
+  }
+}`)
+	assert.NoError(t, err)
+
+	// test and verify
+	assert.Equal(t, []string{"/var/log/containers/*.log", "/var/log/app.log"}, config.GetLogFilePaths())
+	assert.Nil(t, (&adapters.CwaConfig{}).GetLogFilePaths())
+}
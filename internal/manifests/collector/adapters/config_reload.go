@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"encoding/json"
+)
+
+// hotReloadablePaths are the sections of the agent config whose changes the config reloader sidecar applies by
+// making the agent reload its translated configs, without rolling out the pods.
+var hotReloadablePaths = [][]string{
+	{"agent", "debug"},
+	{"logs", "logs_collected", "files"},
+	{"logs", "force_flush_interval"},
+}
+
+// RestartRequiredConfig returns the part of the given agent config whose changes require restarting the agent,
+// in a canonical form. A config that can't be parsed is returned as is, so that any change to it requires a restart.
+func RestartRequiredConfig(configStr string) string {
+	config, err := ConfigFromJSONString(configStr)
+	if err != nil {
+		return configStr
+	}
+	for _, path := range hotReloadablePaths {
+		removePath(config, path)
+	}
+	// encoding/json sorts the map keys, so equivalent configs result in the same string
+	canonical, err := json.Marshal(config)
+	if err != nil {
+		return configStr
+	}
+	return string(canonical)
+}
+
+func removePath(config map[string]interface{}, path []string) {
+	for i, key := range path {
+		if i == len(path)-1 {
+			delete(config, key)
+			return
+		}
+		next, ok := config[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		config = next
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartRequiredConfig(t *testing.T) {
+	tests := []struct {
+		desc     string
+		config   string
+		expected string
+	}{
+		{
+			desc:     "hot-reloadable sections removed",
+			config:   `{"agent":{"debug":true,"region":"us-west-2"},"logs":{"force_flush_interval":5,"logs_collected":{"files":{"collect_list":[]}}}}`,
+			expected: `{"agent":{"region":"us-west-2"},"logs":{"logs_collected":{}}}`,
+		},
+		{
+			desc:     "canonical form",
+			config:   `{ "traces": {}, "metrics": {} }`,
+			expected: `{"metrics":{},"traces":{}}`,
+		},
+		{
+			desc:     "invalid config",
+			config:   `{"agent":`,
+			expected: `{"agent":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RestartRequiredConfig(tt.config))
+		})
+	}
+}
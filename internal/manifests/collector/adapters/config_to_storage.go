@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	fileStorageExtension           = "file_storage"
+	persistentFileStorageExtension = fileStorageExtension + "/persistence"
+
+	// DefaultFileStorageDirectory is the directory used by a file_storage extension without directory.
+	DefaultFileStorageDirectory = "/var/lib/otelcol/file_storage"
+)
+
+// ConfigToSpoolDirectories returns the sorted list of directories used by the file_storage extensions
+// found in the given configuration, which need to be backed by a volume in the agent's pod.
+func ConfigToSpoolDirectories(config map[interface{}]interface{}) []string {
+	extensions, ok := config["extensions"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+
+	found := map[string]struct{}{}
+	for k, v := range extensions {
+		name, ok := k.(string)
+		if !ok || (name != fileStorageExtension && !strings.HasPrefix(name, fileStorageExtension+"/")) {
+			continue
+		}
+		directory := DefaultFileStorageDirectory
+		if extension, ok := v.(map[interface{}]interface{}); ok {
+			if dir, ok := extension["directory"].(string); ok && dir != "" {
+				directory = dir
+			}
+		}
+		found[directory] = struct{}{}
+	}
+
+	var directories []string
+	for dir := range found {
+		directories = append(directories, dir)
+	}
+	sort.Strings(directories)
+	return directories
+}
+
+// ConfigWithPersistentQueue makes the sending queues of the exporters in the given configuration use a file_storage
+// extension storing its data in the given directory, adding the extension when none of the configured ones does.
+// An exporter queue already bound to another storage is an error, as it would not survive a restart.
+func ConfigWithPersistentQueue(config map[interface{}]interface{}, directory string) error {
+	extensions, ok := config["extensions"].(map[interface{}]interface{})
+	if !ok {
+		extensions = map[interface{}]interface{}{}
+		config["extensions"] = extensions
+	}
+
+	storageID := ""
+	for k, v := range extensions {
+		name, ok := k.(string)
+		if !ok || (name != fileStorageExtension && !strings.HasPrefix(name, fileStorageExtension+"/")) {
+			continue
+		}
+		dir := DefaultFileStorageDirectory
+		if extension, ok := v.(map[interface{}]interface{}); ok {
+			if d, ok := extension["directory"].(string); ok && d != "" {
+				dir = d
+			}
+		}
+		// pick the first matching extension in a stable order
+		if dir == directory && (storageID == "" || name < storageID) {
+			storageID = name
+		}
+	}
+	if storageID == "" {
+		storageID = persistentFileStorageExtension
+		extensions[storageID] = map[interface{}]interface{}{"directory": directory}
+	}
+
+	service, ok := config["service"].(map[interface{}]interface{})
+	if !ok {
+		service = map[interface{}]interface{}{}
+		config["service"] = service
+	}
+	serviceExtensions, _ := service["extensions"].([]interface{})
+	enabled := false
+	for _, id := range serviceExtensions {
+		if id == storageID {
+			enabled = true
+		}
+	}
+	if !enabled {
+		service["extensions"] = append(serviceExtensions, storageID)
+	}
+
+	exporters, _ := config["exporters"].(map[interface{}]interface{})
+	for k, v := range exporters {
+		exporter, ok := v.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		queue, ok := exporter["sending_queue"].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		if storage, ok := queue["storage"]; ok && storage != storageID {
+			return fmt.Errorf("the sending queue of exporter %v uses storage %v instead of %s, which is not persisted", k, storage, storageID)
+		}
+		queue["storage"] = storageID
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigToSpoolDirectories(t *testing.T) {
+	tests := []struct {
+		desc     string
+		config   string
+		expected []string
+	}{
+		{
+			desc: "NoExtensions",
+			config: `receivers:
+  otlp:`,
+			expected: nil,
+		}, {
+			desc: "DefaultDirectory",
+			config: `extensions:
+  file_storage:`,
+			expected: []string{"/var/lib/otelcol/file_storage"},
+		}, {
+			desc: "NamedExtensionsWithDirectories",
+			config: `extensions:
+  health_check:
+  file_storage/b:
+    directory: /var/spool/b
+  file_storage/a:
+    directory: /var/spool/a
+  file_storage/c:
+    directory: /var/spool/a`,
+			expected: []string{"/var/spool/a", "/var/spool/b"},
+		}, {
+			desc: "SimilarlyNamedExtension",
+			config: `extensions:
+  file_storage_other:
+    directory: /var/spool/other`,
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			config, err := ConfigFromString(test.config)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, ConfigToSpoolDirectories(config))
+		})
+	}
+}
+
+func TestConfigWithPersistentQueue(t *testing.T) {
+	tests := []struct {
+		desc      string
+		config    string
+		storageID string
+		expectErr bool
+	}{
+		{
+			desc: "InjectedExtension",
+			config: `exporters:
+  otlp:
+    sending_queue:
+      enabled: true
+  debug:
+service:
+  extensions: [health_check]`,
+			storageID: "file_storage/persistence",
+		}, {
+			desc: "ExistingExtension",
+			config: `extensions:
+  file_storage/queue:
+    directory: /var/lib/persistence
+exporters:
+  otlp:
+    sending_queue:
+      storage: file_storage/queue`,
+			storageID: "file_storage/queue",
+		}, {
+			desc: "OtherStorage",
+			config: `extensions:
+  file_storage:
+exporters:
+  otlp:
+    sending_queue:
+      storage: file_storage`,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			config, err := ConfigFromString(test.config)
+			require.NoError(t, err)
+
+			err = ConfigWithPersistentQueue(config, "/var/lib/persistence")
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			extensions := config["extensions"].(map[interface{}]interface{})
+			assert.Equal(t, "/var/lib/persistence", extensions[test.storageID].(map[interface{}]interface{})["directory"])
+			assert.Contains(t, config["service"].(map[interface{}]interface{})["extensions"], test.storageID)
+			otlp := config["exporters"].(map[interface{}]interface{})["otlp"].(map[interface{}]interface{})
+			assert.Equal(t, test.storageID, otlp["sending_queue"].(map[interface{}]interface{})["storage"])
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	"fmt"
+)
+
+var (
+	windowsEventLevels  = map[string]bool{"VERBOSE": true, "INFORMATION": true, "WARNING": true, "ERROR": true, "CRITICAL": true}
+	windowsEventFormats = map[string]bool{"": true, "xml": true, "text": true}
+)
+
+type windowsEvents struct {
+	CollectList []WindowsEvent `json:"collect_list,omitempty"`
+}
+
+// WindowsEvent is an entry of the logs.logs_collected.windows_events.collect_list section of the agent config.
+type WindowsEvent struct {
+	EventName    string   `json:"event_name,omitempty"`
+	EventLevels  []string `json:"event_levels,omitempty"`
+	EventIDs     []int    `json:"event_ids,omitempty"`
+	EventFormat  string   `json:"event_format,omitempty"`
+	LogGroupName string   `json:"log_group_name,omitempty"`
+}
+
+// GetWindowsEvents returns the Windows event logs collected by the logs.logs_collected.windows_events section,
+// or nil when the section is absent.
+func (c *CwaConfig) GetWindowsEvents() []WindowsEvent {
+	if c.Logs == nil || c.Logs.LogsCollected == nil || c.Logs.LogsCollected.WindowsEvents == nil {
+		return nil
+	}
+	return c.Logs.LogsCollected.WindowsEvents.CollectList
+}
+
+// ValidateWindowsEvents checks the logs.logs_collected.windows_events section of the agent config.
+func (c *CwaConfig) ValidateWindowsEvents() error {
+	if c.Logs == nil || c.Logs.LogsCollected == nil || c.Logs.LogsCollected.WindowsEvents == nil {
+		return nil
+	}
+	events := c.Logs.LogsCollected.WindowsEvents.CollectList
+	if len(events) == 0 {
+		return fmt.Errorf("windows_events requires a non empty collect_list")
+	}
+	for i, event := range events {
+		if event.EventName == "" {
+			return fmt.Errorf("windows_events collect_list[%d] requires an event_name", i)
+		}
+		if event.LogGroupName == "" {
+			return fmt.Errorf("windows_events collect_list[%d] requires a log_group_name", i)
+		}
+		if len(event.EventLevels) == 0 && len(event.EventIDs) == 0 {
+			return fmt.Errorf("windows_events collect_list[%d] requires event_levels or event_ids", i)
+		}
+		for _, level := range event.EventLevels {
+			if !windowsEventLevels[level] {
+				return fmt.Errorf("windows_events collect_list[%d] has an invalid event level %q", i, level)
+			}
+		}
+		if !windowsEventFormats[event.EventFormat] {
+			return fmt.Errorf("windows_events collect_list[%d] has an invalid event_format %q, expected xml or text", i, event.EventFormat)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestValidateWindowsEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		expectedErr string
+	}{
+		{
+			name:   "no windows events",
+			config: `{"logs": {"logs_collected": {"files": {"collect_list": [{"file_path": "C:\\logs\\app.log"}]}}}}`,
+		},
+		{
+			name: "valid",
+			config: `{"logs": {"logs_collected": {"windows_events": {"collect_list": [
+  {"event_name": "System", "event_levels": ["ERROR", "WARNING"], "event_format": "xml", "log_group_name": "system"},
+  {"event_name": "Application", "event_ids": [1000], "log_group_name": "application"}
+]}}}}`,
+		},
+		{
+			name:        "empty collect list",
+			config:      `{"logs": {"logs_collected": {"windows_events": {}}}}`,
+			expectedErr: "non empty collect_list",
+		},
+		{
+			name:        "missing event name",
+			config:      `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_levels": ["ERROR"], "log_group_name": "system"}]}}}}`,
+			expectedErr: "requires an event_name",
+		},
+		{
+			name:        "missing log group name",
+			config:      `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["ERROR"]}]}}}}`,
+			expectedErr: "requires a log_group_name",
+		},
+		{
+			name:        "invalid event level",
+			config:      `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["error"], "log_group_name": "system"}]}}}}`,
+			expectedErr: `invalid event level "error"`,
+		},
+		{
+			name:        "invalid event format",
+			config:      `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["ERROR"], "event_format": "json", "log_group_name": "system"}]}}}}`,
+			expectedErr: `invalid event_format "json"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := adapters.ConfigStructFromJSONString(tt.config)
+			require.NoError(t, err)
+			err = config.ValidateWindowsEvents()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
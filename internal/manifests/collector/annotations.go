@@ -8,10 +8,12 @@ import (
 	"fmt"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 )
 
 // Annotations return the annotations for AmazonCloudWatchAgent pod.
-func Annotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
+func Annotations(cfg config.Config, instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 	// new map every time, so that we don't touch the instance's annotations
 	annotations := map[string]string{}
 
@@ -27,13 +29,13 @@ func Annotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 		}
 	}
 	// make sure sha256 for configMap is always calculated
-	annotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance.Spec.Config)
+	annotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(restartRequiredConfig(cfg, instance))
 
 	return annotations
 }
 
 // PodAnnotations return the spec annotations for AmazonCloudWatchAgent pod.
-func PodAnnotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
+func PodAnnotations(cfg config.Config, instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 	// new map every time, so that we don't touch the instance's annotations
 	podAnnotations := map[string]string{}
 
@@ -43,18 +45,39 @@ func PodAnnotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
 	}
 
 	// propagating annotations from metadata.annotations
-	for kMeta, vMeta := range Annotations(instance) {
+	for kMeta, vMeta := range Annotations(cfg, instance) {
 		if _, found := podAnnotations[kMeta]; !found {
 			podAnnotations[kMeta] = vMeta
 		}
 	}
 
 	// make sure sha256 for configMap is always calculated
-	podAnnotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance.Spec.Config)
+	podAnnotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(restartRequiredConfig(cfg, instance))
 
 	return podAnnotations
 }
 
+// restartRequiredConfig returns the part of the rendered agent configs whose changes roll out the agent pods, so
+// that the settings of the spec rendered into the configs roll them out too. The changes of the hot-reloadable
+// sections are applied by the config reloader sidecar. A config that can't be rendered is hashed as is.
+func restartRequiredConfig(cfg config.Config, instance v1alpha1.AmazonCloudWatchAgent) string {
+	config := instance.Spec.Config
+	if replaced, err := ReplaceConfig(instance); err == nil {
+		config = replaced
+	}
+	if hotReloadEnabled(cfg, instance) {
+		config = adapters.RestartRequiredConfig(config)
+	}
+	if instance.Spec.OtelConfig == "" {
+		return config
+	}
+	otelConfig := instance.Spec.OtelConfig
+	if replaced, err := ReplaceOtelConfig(instance); err == nil {
+		otelConfig = replaced
+	}
+	return config + "\n---\n" + otelConfig
+}
+
 func getConfigMapSHA(config string) string {
 	h := sha256.Sum256([]byte(config))
 	return fmt.Sprintf("%x", h)
@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 )
 
 func TestDefaultAnnotations(t *testing.T) {
@@ -25,8 +26,8 @@ func TestDefaultAnnotations(t *testing.T) {
 	}
 
 	// test
-	annotations := Annotations(otelcol)
-	podAnnotations := PodAnnotations(otelcol)
+	annotations := Annotations(config.New(), otelcol)
+	podAnnotations := PodAnnotations(config.New(), otelcol)
 
 	//verify
 	assert.Equal(t, "true", annotations["prometheus.io/scrape"])
@@ -58,8 +59,8 @@ func TestUserAnnotations(t *testing.T) {
 	}
 
 	// test
-	annotations := Annotations(otelcol)
-	podAnnotations := PodAnnotations(otelcol)
+	annotations := Annotations(config.New(), otelcol)
+	podAnnotations := PodAnnotations(config.New(), otelcol)
 
 	//verify
 	assert.Equal(t, "false", annotations["prometheus.io/scrape"])
@@ -81,8 +82,8 @@ func TestAnnotationsPropagateDown(t *testing.T) {
 	}
 
 	// test
-	annotations := Annotations(otelcol)
-	podAnnotations := PodAnnotations(otelcol)
+	annotations := Annotations(config.New(), otelcol)
+	podAnnotations := PodAnnotations(config.New(), otelcol)
 
 	// verify
 	assert.Len(t, annotations, 5)
@@ -90,3 +91,31 @@ func TestAnnotationsPropagateDown(t *testing.T) {
 	assert.Equal(t, "mycomponent", podAnnotations["myapp"])
 	assert.Equal(t, "pod_annotation_value", podAnnotations["pod_annotation"])
 }
+
+func TestAnnotationsHotReload(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config:       `{"agent":{"region":"us-west-2"},"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/a.log"}]}}}}`,
+			ConfigReload: v1alpha1.ConfigReloadHotReload,
+		},
+	}
+	hotReloadable := otelcol.DeepCopy()
+	hotReloadable.Spec.Config = `{"agent":{"region":"us-west-2"},"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/b.log"}]}}}}`
+	restartRequired := otelcol.DeepCopy()
+	restartRequired.Spec.Config = `{"agent":{"region":"us-east-1"},"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/a.log"}]}}}}`
+	restart := hotReloadable.DeepCopy()
+	restart.Spec.ConfigReload = v1alpha1.ConfigReloadRestart
+
+	cfg := config.New(config.WithConfigTranslator(&staticTranslator{}))
+
+	// test
+	hash := PodAnnotations(cfg, otelcol)["amazon-cloudwatch-agent-operator-config/sha256"]
+
+	// verify
+	assert.Equal(t, hash, PodAnnotations(cfg, *hotReloadable)["amazon-cloudwatch-agent-operator-config/sha256"])
+	assert.NotEqual(t, hash, PodAnnotations(cfg, *restartRequired)["amazon-cloudwatch-agent-operator-config/sha256"])
+	assert.NotEqual(t, hash, PodAnnotations(cfg, *restart)["amazon-cloudwatch-agent-operator-config/sha256"])
+	// the agents whose configs aren't translated can't reload them
+	assert.NotEqual(t, hash, PodAnnotations(config.New(), *hotReloadable)["amazon-cloudwatch-agent-operator-config/sha256"])
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// defaultBufferSizeLimit bounds the spool directories when no size limit is given, so that buffered
+// telemetry cannot fill up the node's disk.
+var defaultBufferSizeLimit = resource.MustParse("1Gi")
+
+// spoolDirectories returns the directories the agent buffers telemetry to, based on its configuration,
+// which are not persisted.
+func spoolDirectories(agent v1alpha1.AmazonCloudWatchAgent) []string {
+	if agent.Spec.OtelConfig == "" {
+		return nil
+	}
+	config, err := adapters.ConfigFromString(agent.Spec.OtelConfig)
+	if err != nil {
+		return nil
+	}
+	// the persisted directory is backed by its own volume
+	var directories []string
+	for _, dir := range adapters.ConfigToSpoolDirectories(config) {
+		if dir != persistenceDirectory(agent) {
+			directories = append(directories, dir)
+		}
+	}
+	return directories
+}
+
+// bufferVolumes returns the emptyDir volumes backing the agent's spool directories.
+func bufferVolumes(agent v1alpha1.AmazonCloudWatchAgent) []corev1.Volume {
+	storage := agent.Spec.Buffer.Storage
+	sizeLimit := defaultBufferSizeLimit
+	if storage.SizeLimit != nil {
+		sizeLimit = *storage.SizeLimit
+	}
+
+	var volumes []corev1.Volume
+	for i := range spoolDirectories(agent) {
+		limit := sizeLimit.DeepCopy()
+		volumes = append(volumes, corev1.Volume{
+			Name: naming.BufferVolume(i),
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium:    storage.Medium,
+					SizeLimit: &limit,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// bufferVolumeMounts returns the mounts of the volumes returned by bufferVolumes.
+func bufferVolumeMounts(agent v1alpha1.AmazonCloudWatchAgent) []corev1.VolumeMount {
+	var volumeMounts []corev1.VolumeMount
+	for i, dir := range spoolDirectories(agent) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      naming.BufferVolume(i),
+			MountPath: dir,
+		})
+	}
+	return volumeMounts
+}
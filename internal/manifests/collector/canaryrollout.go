@@ -0,0 +1,159 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// canaryNodeLabelPrefix prefixes the labels the operator puts on the canary nodes of the agents.
+const canaryNodeLabelPrefix = "canary.cloudwatch.aws.amazon.com/"
+
+// CanaryNodeLabel returns the label the operator puts on the canary nodes of the canary rollouts of the instance.
+// The daemonset of the instance always avoids the nodes carrying it, so that its pod template doesn't change with
+// the canary nodes of each rollout.
+func CanaryNodeLabel(agent v1alpha1.AmazonCloudWatchAgent) string {
+	return canaryNodeLabelPrefix + naming.Truncate("%s.%s", 63, agent.Namespace, agent.Name)
+}
+
+// canaryRolloutNodes returns the canary nodes of the canary rollout of the instance while it progresses, or nil.
+func canaryRolloutNodes(agent v1alpha1.AmazonCloudWatchAgent) []string {
+	rollout := agent.Status.CanaryRollout
+	if !usesCanaryRollout(agent) || rollout == nil || rollout.Phase != v1alpha1.CanaryRolloutProgressing {
+		return nil
+	}
+	return rollout.Nodes
+}
+
+// usesCanaryRollout tells whether the changes of the configs of the instance are rolled out to canary nodes first.
+func usesCanaryRollout(agent v1alpha1.AmazonCloudWatchAgent) bool {
+	strategy := agent.Spec.RolloutStrategy
+	return strategy != nil && strategy.Type == v1alpha1.RolloutStrategyCanary && agent.Spec.Mode == v1alpha1.ModeDaemonSet
+}
+
+// InternalMetricsPort returns the port the agents of the instance serve their internal metrics on, the health of the
+// canary agents being read from their export failures, and whether they serve them, which only the agents running
+// an OtelConfig do.
+func InternalMetricsPort(agent v1alpha1.AmazonCloudWatchAgent) (int32, bool) {
+	if agent.Spec.OtelConfig == "" {
+		return 0, false
+	}
+	if selfTelemetry := agent.Spec.Observability.SelfTelemetry; selfTelemetry != nil {
+		return selfTelemetryPort(selfTelemetry), true
+	}
+	config, err := adapters.ConfigFromString(agent.Spec.OtelConfig)
+	if err != nil {
+		return 0, false
+	}
+	port, err := adapters.ConfigToMetricsPort(logr.Discard(), config)
+	if err != nil {
+		return 0, false
+	}
+	return port, true
+}
+
+// otelConfigWithCanaryTelemetry serves the internal metrics of the agents rolled out to canary nodes first on the
+// pod network, for the operator to read the health of the canary agents from.
+func otelConfigWithCanaryTelemetry(config map[interface{}]interface{}, instance v1alpha1.AmazonCloudWatchAgent) {
+	if !usesCanaryRollout(instance) || instance.Spec.Observability.SelfTelemetry != nil {
+		return
+	}
+	port, err := adapters.ConfigToMetricsPort(logr.Discard(), config)
+	if err != nil {
+		return
+	}
+	childMap(childMap(childMap(config, "service"), "telemetry"), "metrics")["address"] = fmt.Sprintf("0.0.0.0:%d", port)
+}
+
+// CanaryRollout builds the config map and the daemonset running the configs of the instance on the canary nodes of
+// its canary rollout, while it progresses. The canary daemonset selects the nodes carrying the canary node label,
+// which the daemonset of the instance avoids.
+func CanaryRollout(ctx context.Context, params manifests.Params) ([]client.Object, error) {
+	nodes := canaryRolloutNodes(params.OtelCol)
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	revision := params.OtelCol.Status.CanaryRollout.Revision
+
+	canaryParams := params
+	canaryParams.OtelCol = *params.OtelCol.DeepCopy()
+	canaryParams.OtelCol.Status.CanaryRollout = nil
+	canaryParams, err := withApplicationSignals(canaryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	configMaps, err := ConfigMaps(ctx, canaryParams)
+	if err != nil {
+		return nil, err
+	}
+	// the other config maps, such as the prometheus one, are shared with the daemonset of the instance
+	cm := configMaps[0]
+	cm.Name = naming.CanaryConfigMap(params.OtelCol.ResourceName())
+	cm.Labels[constants.LabelCohort] = CohortCanary
+
+	ds := DaemonSet(canaryParams)
+	ds.Name = naming.CanaryCollector(params.OtelCol.ResourceName())
+	ds.Labels[constants.LabelCohort] = CohortCanary
+	// the label tells the pods of the two daemonsets apart, while the services keep selecting both of them
+	ds.Spec.Selector.MatchLabels[constants.LabelCohort] = CohortCanary
+	ds.Spec.Template.Labels[constants.LabelCohort] = CohortCanary
+	// the revision tells the pods running the change apart from the ones it replaces
+	ds.Spec.Template.Labels[constants.LabelConfigRevision] = revision
+	for i := range ds.Spec.Template.Spec.Volumes {
+		if v := &ds.Spec.Template.Spec.Volumes[i]; v.Name == naming.ConfigMapVolume() {
+			v.ConfigMap.Name = cm.Name
+		}
+	}
+	ds.Spec.Template.Spec.Affinity = onCanaryNodes(ds.Spec.Template.Spec.Affinity, CanaryNodeLabel(params.OtelCol))
+	return []client.Object{cm, ds}, nil
+}
+
+// withStableConfigs returns the params of the agents out of the canary nodes of a canary rollout, which run the
+// stable configs until the change is rolled out to all the nodes.
+func withStableConfigs(params manifests.Params) manifests.Params {
+	rollout := params.OtelCol.Status.CanaryRollout
+	if !usesCanaryRollout(params.OtelCol) || rollout == nil || rollout.Phase == v1alpha1.CanaryRolloutSucceeded {
+		return params
+	}
+	params.OtelCol = *params.OtelCol.DeepCopy()
+	params.OtelCol.Spec.Config = rollout.StableConfig
+	params.OtelCol.Spec.OtelConfig = rollout.StableOtelConfig
+	return params
+}
+
+// withoutCanaryNodes returns a copy of the affinity avoiding the canary nodes of the instance, whatever the phase of
+// its rollout.
+func withoutCanaryNodes(affinity *corev1.Affinity, agent v1alpha1.AmazonCloudWatchAgent) *corev1.Affinity {
+	if !usesCanaryRollout(agent) {
+		return affinity
+	}
+	return withNodeRequirement(affinity, corev1.NodeSelectorRequirement{
+		Key:      CanaryNodeLabel(agent),
+		Operator: corev1.NodeSelectorOpDoesNotExist,
+	})
+}
+
+// onCanaryNodes returns a copy of the affinity of the daemonset of the instance selecting the canary nodes it
+// avoids instead.
+func onCanaryNodes(affinity *corev1.Affinity, label string) *corev1.Affinity {
+	return withEachNodeTerm(affinity, func(term *corev1.NodeSelectorTerm) {
+		for i := range term.MatchExpressions {
+			if requirement := &term.MatchExpressions[i]; requirement.Key == label {
+				requirement.Operator = corev1.NodeSelectorOpExists
+			}
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestCanaryRollout(t *testing.T) {
+	zone := corev1.NodeSelectorRequirement{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us-west-2a"}}
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:   v1alpha1.ModeDaemonSet,
+				Config: `{"agent":{"interval":"30s"}}`,
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zone}}},
+					},
+				}},
+				RolloutStrategy: &v1alpha1.RolloutStrategySpec{Type: v1alpha1.RolloutStrategyCanary},
+			},
+			Status: v1alpha1.AmazonCloudWatchAgentStatus{CanaryRollout: &v1alpha1.CanaryRolloutStatus{
+				Phase:        v1alpha1.CanaryRolloutProgressing,
+				Revision:     "0123456789abcdef",
+				StableConfig: `{"agent":{"interval":"60s"}}`,
+				Nodes:        []string{"node-a", "node-b"},
+			}},
+		},
+	}
+	byName := func(objects []client.Object) map[string]client.Object {
+		named := map[string]client.Object{}
+		for _, obj := range objects {
+			named[fmt.Sprintf("%T/%s", obj, obj.GetName())] = obj
+		}
+		return named
+	}
+
+	objects, err := Build(context.Background(), params)
+	require.NoError(t, err)
+	named := byName(objects)
+
+	// the other nodes keep running the stable config
+	stableConfig := named["*v1.ConfigMap/agent"].(*corev1.ConfigMap)
+	assert.Equal(t, `{"agent":{"interval":"60s"}}`, stableConfig.Data["cwagentconfig.json"])
+	label := "canary.cloudwatch.aws.amazon.com/amazon-cloudwatch.agent"
+	assert.Equal(t, label, CanaryNodeLabel(params.OtelCol))
+	stable := named["*v1.DaemonSet/agent"].(*appsv1.DaemonSet)
+	assert.Equal(t, []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{zone, {Key: label, Operator: corev1.NodeSelectorOpDoesNotExist}},
+	}}, stable.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	// the canary nodes run the change
+	canaryConfig := named["*v1.ConfigMap/agent-canary"].(*corev1.ConfigMap)
+	assert.Equal(t, `{"agent":{"interval":"30s"}}`, canaryConfig.Data["cwagentconfig.json"])
+	assert.Equal(t, CohortCanary, canaryConfig.Labels[constants.LabelCohort])
+	canary := named["*v1.DaemonSet/agent-canary"].(*appsv1.DaemonSet)
+	assert.Equal(t, CohortCanary, canary.Spec.Selector.MatchLabels[constants.LabelCohort])
+	assert.Equal(t, "0123456789abcdef", canary.Spec.Template.Labels[constants.LabelConfigRevision])
+	assert.NotContains(t, stable.Spec.Template.Labels, constants.LabelConfigRevision)
+	for _, volume := range canary.Spec.Template.Spec.Volumes {
+		if volume.ConfigMap != nil && volume.Name == "otc-internal" {
+			assert.Equal(t, "agent-canary", volume.ConfigMap.Name)
+		}
+	}
+	assert.Equal(t, []corev1.NodeSelectorTerm{{
+		MatchExpressions: []corev1.NodeSelectorRequirement{zone, {Key: label, Operator: corev1.NodeSelectorOpExists}},
+	}}, canary.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	// a rolled back change leaves all the nodes on the stable config
+	params.OtelCol.Status.CanaryRollout.Phase = v1alpha1.CanaryRolloutRolledBack
+	objects, err = Build(context.Background(), params)
+	require.NoError(t, err)
+	named = byName(objects)
+	assert.NotContains(t, named, "*v1.DaemonSet/agent-canary")
+	assert.Equal(t, `{"agent":{"interval":"60s"}}`, named["*v1.ConfigMap/agent"].(*corev1.ConfigMap).Data["cwagentconfig.json"])
+	// the pod template of the daemonset of the instance doesn't change with the phase or the canary nodes
+	assert.Equal(t, stable.Spec.Template.Spec.Affinity, named["*v1.DaemonSet/agent"].(*appsv1.DaemonSet).Spec.Template.Spec.Affinity)
+
+	// a succeeded change runs on all the nodes
+	params.OtelCol.Status.CanaryRollout.Phase = v1alpha1.CanaryRolloutSucceeded
+	objects, err = Build(context.Background(), params)
+	require.NoError(t, err)
+	named = byName(objects)
+	assert.NotContains(t, named, "*v1.DaemonSet/agent-canary")
+	assert.Equal(t, `{"agent":{"interval":"30s"}}`, named["*v1.ConfigMap/agent"].(*corev1.ConfigMap).Data["cwagentconfig.json"])
+}
+
+func TestInternalMetricsPort(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+		Mode:            v1alpha1.ModeDaemonSet,
+		RolloutStrategy: &v1alpha1.RolloutStrategySpec{Type: v1alpha1.RolloutStrategyCanary},
+	}}
+	// only the agents running an OtelConfig serve their internal metrics
+	_, ok := InternalMetricsPort(agent)
+	assert.False(t, ok)
+
+	agent.Spec.OtelConfig = "service:\n  telemetry:\n    metrics:\n      address: localhost:8899\n"
+	port, ok := InternalMetricsPort(agent)
+	assert.True(t, ok)
+	assert.Equal(t, int32(8899), port)
+
+	// and serve them on the pod network for the canary agents to be checked
+	otelConfig, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	assert.Contains(t, otelConfig, "address: 0.0.0.0:8899")
+
+	agent.Spec.Observability.SelfTelemetry = &v1alpha1.SelfTelemetrySpec{MetricsPort: 9999}
+	port, _ = InternalMetricsPort(agent)
+	assert.Equal(t, int32(9999), port)
+}
@@ -4,10 +4,13 @@
 package collector
 
 import (
+	"context"
+
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 )
 
@@ -16,7 +19,28 @@ const (
 )
 
 // Build creates the manifest for the collector resource.
-func Build(params manifests.Params) ([]client.Object, error) {
+func Build(ctx context.Context, params manifests.Params) ([]client.Object, error) {
+	// the agents out of the canary nodes of a canary rollout keep running the stable configs
+	canary, err := CanaryRollout(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	params, err = withApplicationSignals(withStableConfigs(params))
+	if err != nil {
+		return nil, err
+	}
+
+	// the signals split out of the daemonset derive from the complete configs, the daemonset keeps the others
+	pipelines, err := PipelineSplit(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if pipelines != nil {
+		if params, err = withoutSplitSignals(params); err != nil {
+			return nil, err
+		}
+	}
+
 	var resourceManifests []client.Object
 	var manifestFactories []manifests.K8sManifestFactory
 	switch params.OtelCol.Spec.Mode {
@@ -28,16 +52,20 @@ func Build(params manifests.Params) ([]client.Object, error) {
 		manifestFactories = append(manifestFactories, manifests.FactoryWithoutError(PodDisruptionBudget))
 	case v1alpha1.ModeDaemonSet:
 		manifestFactories = append(manifestFactories, manifests.FactoryWithoutError(DaemonSet))
+		manifestFactories = append(manifestFactories, manifests.FactoryWithoutError(CanaryDaemonSet))
 	case v1alpha1.ModeSidecar:
 		params.Log.V(5).Info("not building sidecar...")
 	}
 	manifestFactories = append(manifestFactories, []manifests.K8sManifestFactory{
 		manifests.FactoryWithoutError(HorizontalPodAutoscaler),
+		manifests.FactoryWithoutError(VerticalPodAutoscaler),
 		manifests.FactoryWithoutError(ServiceAccount),
 		manifests.Factory(Service),
 		manifests.Factory(HeadlessService),
 		manifests.Factory(MonitoringService),
+		manifests.Factory(DebugService),
 		manifests.Factory(Ingress),
+		manifests.FactoryWithoutError(Certificate),
 	}...)
 	if params.OtelCol.Spec.Observability.Metrics.EnableMetrics && featuregate.PrometheusOperatorIsAvailable.IsEnabled() {
 		if params.OtelCol.Spec.Mode == v1alpha1.ModeSidecar {
@@ -54,13 +82,21 @@ func Build(params manifests.Params) ([]client.Object, error) {
 			resourceManifests = append(resourceManifests, res)
 		}
 	}
-	configmaps, err := ConfigMaps(params)
+	configmaps, err := ConfigMaps(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	for _, configmap := range configmaps {
 		resourceManifests = append(resourceManifests, configmap)
 	}
+	nodeGroups, err := NodeGroups(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	resourceManifests = append(resourceManifests, nodeGroups...)
+	resourceManifests = append(resourceManifests, pipelines...)
+	resourceManifests = routeSplitTraces(params, resourceManifests, pipelines)
+	resourceManifests = append(resourceManifests, canary...)
 	routes, err := Routes(params)
 	if err != nil {
 		return nil, err
@@ -71,3 +107,18 @@ func Build(params manifests.Params) ([]client.Object, error) {
 	}
 	return resourceManifests, nil
 }
+
+// withApplicationSignals returns the params with the sections of Application Signals added to the config when it is
+// enabled. Every manifest, such as the container ports and the services, derives from the complete config.
+func withApplicationSignals(params manifests.Params) (manifests.Params, error) {
+	if !params.OtelCol.Spec.ApplicationSignals.IsEnabled() {
+		return params, nil
+	}
+	config, err := adapters.ConfigWithApplicationSignals(params.OtelCol.Spec.Config)
+	if err != nil {
+		return params, err
+	}
+	params.OtelCol = *params.OtelCol.DeepCopy()
+	params.OtelCol.Spec.Config = config
+	return params, nil
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestBuildWithApplicationSignals(t *testing.T) {
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:               v1alpha1.ModeDaemonSet,
+				Config:             `{"logs":{"metrics_collected":{"kubernetes":{}}}}`,
+				ApplicationSignals: &v1alpha1.ApplicationSignalsSpec{Enabled: true},
+			},
+		},
+	}
+
+	objects, err := Build(context.Background(), params)
+	require.NoError(t, err)
+
+	var containerPorts, servicePorts []int32
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *appsv1.DaemonSet:
+			for _, p := range o.Spec.Template.Spec.Containers[0].Ports {
+				containerPorts = append(containerPorts, p.ContainerPort)
+			}
+		case *corev1.Service:
+			if o.Name == "cloudwatch-agent" {
+				for _, p := range o.Spec.Ports {
+					servicePorts = append(servicePorts, p.Port)
+				}
+			}
+		case *corev1.ConfigMap:
+			assert.Contains(t, o.Data["cwagentconfig.json"], "application_signals")
+		}
+	}
+	assert.Subset(t, containerPorts, []int32{4315, 4316})
+	assert.Subset(t, servicePorts, []int32{4315, 4316})
+	// the instance of the caller is left as is
+	assert.NotContains(t, params.OtelCol.Spec.Config, "application_signals")
+}
@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/translator"
+)
+
+const (
+	configReloaderContainer = "config-reloader"
+	// configReloadSignal makes the agent reload the translated configs it is started on in place, without
+	// restarting its process.
+	configReloadSignal   = "HUP"
+	configReloadInterval = int32(10)
+	// configHashEntry is the entry of the agent ConfigMap holding the hash of the part of the config whose changes
+	// roll out the pods, which the config reloader compares to the one of its pod before signaling the agent.
+	configHashEntry = "config-hash"
+)
+
+// hotReloadEnabled tells whether the changes of the hot-reloadable sections of the agent config are applied by the
+// config reloader sidecar instead of rolling out the pods. The agent only reloads the translated configs it is
+// started on, so the changes of the agents whose configs the operator doesn't translate roll out their pods.
+func hotReloadEnabled(cfg config.Config, agent v1alpha1.AmazonCloudWatchAgent) bool {
+	return agent.HotReloadEnabled() && configTranslated(cfg, agent)
+}
+
+// ConfigReloaderContainer builds the sidecar making the agent reload its translated configs when they change, unless
+// the change also rolls out the pod.
+func ConfigReloaderContainer(cfg config.Config, agent v1alpha1.AmazonCloudWatchAgent) corev1.Container {
+	image := cfg.PrometheusReloaderImage()
+	if agent.Spec.PrometheusReload != nil && agent.Spec.PrometheusReload.Image != "" {
+		image = agent.Spec.PrometheusReload.Image
+	}
+	mount := getVolumeMounts(agent.Spec.NodeSelector["kubernetes.io/os"])
+	return reloaderContainer(configReloaderContainer, image, path.Join(mount.MountPath, translator.TOMLEntry), configReloadInterval, configReloadSignal, mount,
+		corev1.EnvVar{Name: "CONFIG_HASH_FILE", Value: path.Join(mount.MountPath, configHashEntry)},
+		corev1.EnvVar{Name: "CONFIG_HASH", Value: ConfigHash(cfg, agent)},
+	)
+}
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/translator"
+)
+
+func TestConfigReload(t *testing.T) {
+	params := manifests.Params{
+		Config: config.New(config.WithPrometheusReloaderImage("busybox:default"), config.WithConfigTranslator(&staticTranslator{result: translator.Result{TOML: "[outputs]\n  [[outputs.cloudwatchlogs]]\n"}})),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:         v1alpha1.ModeDaemonSet,
+				Config:       `{"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/a.log"}]}}}}`,
+				ConfigReload: v1alpha1.ConfigReloadHotReload,
+			},
+		},
+	}
+
+	pod := DaemonSet(params).Spec.Template.Spec
+	require.Len(t, pod.Containers, 2)
+	reloader := pod.Containers[1]
+	assert.Equal(t, "config-reloader", reloader.Name)
+	assert.Equal(t, "busybox:default", reloader.Image)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "CONFIG_FILE", Value: "/etc/cwagentconfig/cwagentconfig.toml"},
+		{Name: "INTERVAL_SECONDS", Value: "10"},
+		{Name: "SIGNAL", Value: "HUP"},
+		{Name: "AGENT_PROCESS", Value: "/opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent"},
+		{Name: "CONFIG_HASH_FILE", Value: "/etc/cwagentconfig/config-hash"},
+		{Name: "CONFIG_HASH", Value: ConfigHash(params.Config, params.OtelCol)},
+	}, reloader.Env)
+	assert.Equal(t, []corev1.VolumeMount{{Name: naming.ConfigMapVolume(), MountPath: "/etc/cwagentconfig", ReadOnly: true}}, reloader.VolumeMounts)
+	assert.Equal(t, &[]bool{true}[0], pod.ShareProcessNamespace)
+
+	// the reloader compares the hash of its pod to the one of the ConfigMap
+	configMaps, err := ConfigMaps(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, ConfigHash(params.Config, params.OtelCol), configMaps[0].Data["config-hash"])
+
+	// the agents whose configs the operator doesn't translate can't reload them
+	untranslated := params
+	untranslated.Config = config.New()
+	assert.Len(t, DaemonSet(untranslated).Spec.Template.Spec.Containers, 1)
+	assert.Equal(t, params.OtelCol.Spec.Config, restartRequiredConfig(untranslated.Config, params.OtelCol))
+
+	// the changes of Windows agents always roll out the pods
+	windows := params.OtelCol.DeepCopy()
+	windows.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+	assert.Equal(t, windows.Spec.Config, restartRequiredConfig(params.Config, *windows))
+	params.OtelCol = *windows
+	pod = DaemonSet(params).Spec.Template.Spec
+	assert.Len(t, pod.Containers, 1)
+	assert.Nil(t, pod.ShareProcessNamespace)
+}
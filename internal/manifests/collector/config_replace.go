@@ -40,6 +40,18 @@ func ReplaceConfig(instance v1alpha1.AmazonCloudWatchAgent) (string, error) {
 		return "", err
 	}
 
+	if TLSSecretName(instance) != "" {
+		certFile, keyFile := tlsFiles(instance)
+		configWithTLS(config, certFile, keyFile)
+	}
+	configWithEndpointOverrides(config, instance.Spec.AWSEndpointOverrides)
+	configWithRoleArn(config, instance.Spec.RoleArn)
+	configWithXRay(config, instance.Spec.XRay)
+	configWithDestinations(config, instance.Spec.Destinations)
+	configWithControlPlaneMetrics(config, instance.Spec.ControlPlaneMetrics)
+	configWithLogGroupName(config, instance)
+	configWithSelfTelemetry(config, instance)
+
 	conf := confmap.NewFromStringMap(config)
 
 	prometheusFilePath := conf.Get("logs::metrics_collected::prometheus::prometheus_config_path")
@@ -98,6 +110,26 @@ func ReplaceOtelConfig(instance v1alpha1.AmazonCloudWatchAgent) (string, error)
 		return "", err
 	}
 
+	configWithOTLPReceiverSettings(config, instance.Spec.OTLPReceiver)
+	otelConfigWithDestinations(config, instance.Spec.Destinations)
+	otelConfigWithLogGroupName(config, instance)
+	otelConfigWithEndpointOverrides(config, instance.Spec.AWSEndpointOverrides)
+	otelConfigWithRoleArn(config, instance.Spec.RoleArn)
+	otelConfigWithDiagnostics(config, instance.Spec.Diagnostics)
+	otelConfigWithDebug(config, instance.Spec.Debug)
+	otelConfigWithSelfTelemetry(config, instance)
+	otelConfigWithCanaryTelemetry(config, instance)
+	if TLSSecretName(instance) != "" {
+		certFile, keyFile := tlsFiles(instance)
+		otelConfigWithTLS(config, certFile, keyFile)
+	}
+
+	if directory := persistenceDirectory(instance); directory != "" {
+		if err := adapters.ConfigWithPersistentQueue(config, directory); err != nil {
+			return "", err
+		}
+	}
+
 	out, err := yaml.Marshal(config)
 	if err != nil {
 		return "", err
@@ -140,7 +172,7 @@ func ReplacePrometheusConfig(instance v1alpha1.AmazonCloudWatchAgent) (string, e
 	}
 
 	if featuregate.EnableTargetAllocatorRewrite.IsEnabled() {
-		updPromCfgMap, getCfgPromErr := ta.AddTAConfigToPromConfig(promCfgMap, naming.TAService(instance.Name))
+		updPromCfgMap, getCfgPromErr := ta.AddTAConfigToPromConfig(promCfgMap, naming.TAService(instance.ResourceName()))
 		if getCfgPromErr != nil {
 			return "", getCfgPromErr
 		}
@@ -153,7 +185,7 @@ func ReplacePrometheusConfig(instance v1alpha1.AmazonCloudWatchAgent) (string, e
 		return string(out), nil
 	}
 
-	updPromCfgMap, err := ta.AddHTTPSDConfigToPromConfig(promCfgMap, naming.TAService(instance.Name))
+	updPromCfgMap, err := ta.AddHTTPSDConfigToPromConfig(promCfgMap, naming.TAService(instance.ResourceName()))
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+// configVariableRegexp matches the variables of the configs substituted by the operator. The other ${...}
+// expressions, such as the ${env:VAR} of the OpenTelemetry configs, are left to the agent.
+var configVariableRegexp = regexp.MustCompile(`\$\{(cluster_name|region|namespace|name)\}`)
+
+// WithConfigVariables returns a copy of the instance whose config and otelConfig have their ${cluster_name},
+// ${region}, ${namespace} and ${name} variables substituted, so that the same AmazonCloudWatchAgent can be applied
+// to several clusters. The cluster name and the region are the ones of the operator, and the namespace and the name
+// are the ones of the instance. Using a variable the operator has no value for is an error. The ${cluster_name} of
+// the log group name template defaults to the cluster name of the operator.
+func WithConfigVariables(cfg config.Config, instance v1alpha1.AmazonCloudWatchAgent) (v1alpha1.AmazonCloudWatchAgent, error) {
+	values := map[string]string{
+		"cluster_name": cfg.ClusterName(),
+		"region":       cfg.Region(),
+		"namespace":    instance.Namespace,
+		"name":         instance.Name,
+	}
+	var missing string
+	substitute := func(s string) string {
+		return configVariableRegexp.ReplaceAllStringFunc(s, func(variable string) string {
+			name := configVariableRegexp.FindStringSubmatch(variable)[1]
+			if values[name] == "" && missing == "" {
+				missing = variable
+			}
+			return values[name]
+		})
+	}
+
+	result := *instance.DeepCopy()
+	result.Spec.Config = substitute(instance.Spec.Config)
+	result.Spec.OtelConfig = substitute(instance.Spec.OtelConfig)
+	if missing != "" {
+		return instance, fmt.Errorf("the config uses the variable %s, which the operator has no value for", missing)
+	}
+	// the log group name template falls back to the cluster name of the operator
+	if result.Spec.Logs != nil && logGroupClusterName(result) == "" {
+		result.Spec.Logs.ClusterName = cfg.ClusterName()
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestWithConfigVariables(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config:     `{"agent": {"region": "${region}"}, "logs": {"metrics_collected": {"kubernetes": {"cluster_name": "${cluster_name}"}}}}`,
+			OtelConfig: "exporters:\n  awsemf:\n    namespace: ${namespace}/${name}\n    region: ${env:AWS_REGION}\n",
+		},
+	}
+	cfg := config.New(config.WithClusterName("production"), config.WithRegion("us-west-2"))
+
+	templated, err := WithConfigVariables(cfg, agent)
+	require.NoError(t, err)
+	assert.Equal(t, `{"agent": {"region": "us-west-2"}, "logs": {"metrics_collected": {"kubernetes": {"cluster_name": "production"}}}}`, templated.Spec.Config)
+	// the variables of the agent are left untouched
+	assert.Equal(t, "exporters:\n  awsemf:\n    namespace: amazon-cloudwatch/cloudwatch-agent\n    region: ${env:AWS_REGION}\n", templated.Spec.OtelConfig)
+	assert.Contains(t, agent.Spec.Config, "${region}", "the instance must not be modified")
+
+	_, err = WithConfigVariables(config.New(config.WithRegion("us-west-2")), agent)
+	assert.ErrorContains(t, err, "the config uses the variable ${cluster_name}, which the operator has no value for")
+
+	// the log group name template falls back to the cluster name of the operator
+	agent.Spec.Config = "{}"
+	agent.Spec.Logs = &v1alpha1.LogsSpec{GroupNameTemplate: "/aws/${cluster_name}/${namespace}"}
+	templated, err = WithConfigVariables(cfg, agent)
+	require.NoError(t, err)
+	assert.Equal(t, "/aws/production/amazon-cloudwatch", logGroupName(templated, "{hostname}"))
+	assert.Empty(t, agent.Spec.Logs.ClusterName, "the instance must not be modified")
+}
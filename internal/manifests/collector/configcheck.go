@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+// otelPipelineComponents are the sections of the OtelConfig the components of the pipelines are defined in.
+var otelPipelineComponents = []string{"receivers", "processors", "exporters"}
+
+// ValidateConfig dry-runs the configs the agents of the instance would load, to keep the pods running the last
+// good configs rather than rolling out configs they fail to start with. The agent JSON config must decode into
+// the agent config, the pipelines of the OtelConfig must only use the components it defines, and the scrape
+// configs of the Prometheus config must have distinct job names.
+func ValidateConfig(instance v1alpha1.AmazonCloudWatchAgent) error {
+	if instance.Spec.Config != "" {
+		config, err := ReplaceConfig(instance)
+		if err != nil {
+			return fmt.Errorf("the config is invalid: %w", err)
+		}
+		if _, err := adapters.ConfigStructFromJSONString(config); err != nil {
+			return fmt.Errorf("the config is invalid: %w", err)
+		}
+	}
+	if instance.Spec.OtelConfig != "" {
+		otelConfig, err := ReplaceOtelConfig(instance)
+		if err != nil {
+			return fmt.Errorf("the otelConfig is invalid: %w", err)
+		}
+		parsed, err := adapters.ConfigFromString(otelConfig)
+		if err != nil {
+			return fmt.Errorf("the otelConfig is invalid: %w", err)
+		}
+		if err := validateOtelPipelines(parsed); err != nil {
+			return fmt.Errorf("the otelConfig is invalid: %w", err)
+		}
+	}
+	if !instance.Spec.Prometheus.IsEmpty() {
+		promConfig, err := ReplacePrometheusConfig(instance)
+		if err != nil {
+			return fmt.Errorf("the prometheus config is invalid: %w", err)
+		}
+		parsed, err := adapters.ConfigFromString(promConfig)
+		if err != nil {
+			return fmt.Errorf("the prometheus config is invalid: %w", err)
+		}
+		if err := validateScrapeJobs(parsed); err != nil {
+			return fmt.Errorf("the prometheus config is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateOtelPipelines checks that the pipelines and the extensions of the service are defined in the config.
+func validateOtelPipelines(config map[interface{}]interface{}) error {
+	service, _ := config["service"].(map[interface{}]interface{})
+	extensions, _ := config["extensions"].(map[interface{}]interface{})
+	for _, extension := range toList(service["extensions"]) {
+		if _, ok := extensions[extension]; !ok {
+			return fmt.Errorf("the service uses the extension %v, which is not defined", extension)
+		}
+	}
+
+	pipelines, _ := service["pipelines"].(map[interface{}]interface{})
+	names := make([]string, 0, len(pipelines))
+	for k := range pipelines {
+		if name, ok := k.(string); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	connectors, _ := config["connectors"].(map[interface{}]interface{})
+	for _, name := range names {
+		pipeline, ok := pipelines[name].(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("the pipeline %s is empty", name)
+		}
+		for _, section := range otelPipelineComponents {
+			defined, _ := config[section].(map[interface{}]interface{})
+			for _, component := range toList(pipeline[section]) {
+				_, isDefined := defined[component]
+				_, isConnector := connectors[component]
+				if !isDefined && !(isConnector && section != "processors") {
+					return fmt.Errorf("the pipeline %s uses the %s %v, which is not defined", name, section[:len(section)-1], component)
+				}
+			}
+		}
+		if len(toList(pipeline["receivers"])) == 0 || len(toList(pipeline["exporters"])) == 0 {
+			return fmt.Errorf("the pipeline %s needs at least a receiver and an exporter", name)
+		}
+	}
+	return nil
+}
+
+// validateScrapeJobs checks that each scrape config of the Prometheus config has a distinct job name.
+func validateScrapeJobs(promConfig map[interface{}]interface{}) error {
+	config, _ := promConfig["config"].(map[interface{}]interface{})
+	jobs := map[string]bool{}
+	for i, item := range toList(config["scrape_configs"]) {
+		job, _ := item.(map[interface{}]interface{})
+		name, _ := job["job_name"].(string)
+		if name == "" {
+			return fmt.Errorf("the scrape config #%d has no job_name", i)
+		}
+		if jobs[name] {
+			return fmt.Errorf("the job_name %s is used by several scrape configs", name)
+		}
+		jobs[name] = true
+	}
+	return nil
+}
+
+func toList(value interface{}) []interface{} {
+	items, _ := value.([]interface{})
+	return items
+}
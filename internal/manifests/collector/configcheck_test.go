@@ -0,0 +1,132 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestValidateConfig(t *testing.T) {
+	otelConfig := `
+receivers:
+  otlp:
+    protocols:
+      grpc:
+processors:
+  batch:
+exporters:
+  awsemf:
+extensions:
+  health_check:
+service:
+  extensions: [%s]
+  pipelines:
+    metrics:
+      receivers: [otlp]
+      processors: [%s]
+      exporters: [%s]
+`
+	scrapeConfigs := func(names ...string) v1alpha1.PrometheusConfig {
+		var jobs []interface{}
+		for _, name := range names {
+			jobs = append(jobs, map[string]interface{}{"job_name": name})
+		}
+		return v1alpha1.PrometheusConfig{
+			Config: &v1alpha1.AnyConfig{Object: map[string]interface{}{"scrape_configs": jobs}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		spec        v1alpha1.AmazonCloudWatchAgentSpec
+		expectedErr string
+	}{
+		{
+			name: "empty configs",
+			spec: v1alpha1.AmazonCloudWatchAgentSpec{},
+		},
+		{
+			name: "valid configs",
+			spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Config:     `{"agent": {"region": "us-west-2"}}`,
+				OtelConfig: fmt.Sprintf(otelConfig, "health_check", "batch", "awsemf"),
+				Prometheus: scrapeConfigs("pods", "nodes"),
+			},
+		},
+		{
+			name:        "config is not JSON",
+			spec:        v1alpha1.AmazonCloudWatchAgentSpec{Config: `{"agent": `},
+			expectedErr: "the config is invalid",
+		},
+		{
+			name:        "otelConfig is not YAML",
+			spec:        v1alpha1.AmazonCloudWatchAgentSpec{OtelConfig: "receivers: ["},
+			expectedErr: "the otelConfig is invalid",
+		},
+		{
+			name:        "undefined extension",
+			spec:        v1alpha1.AmazonCloudWatchAgentSpec{OtelConfig: fmt.Sprintf(otelConfig, "pprof", "batch", "awsemf")},
+			expectedErr: "the service uses the extension pprof, which is not defined",
+		},
+		{
+			name:        "undefined processor",
+			spec:        v1alpha1.AmazonCloudWatchAgentSpec{OtelConfig: fmt.Sprintf(otelConfig, "health_check", "memory_limiter", "awsemf")},
+			expectedErr: "the pipeline metrics uses the processor memory_limiter, which is not defined",
+		},
+		{
+			name:        "undefined exporter",
+			spec:        v1alpha1.AmazonCloudWatchAgentSpec{OtelConfig: fmt.Sprintf(otelConfig, "health_check", "batch", "awsxray")},
+			expectedErr: "the pipeline metrics uses the exporter awsxray, which is not defined",
+		},
+		{
+			name:        "pipeline without exporter",
+			spec:        v1alpha1.AmazonCloudWatchAgentSpec{OtelConfig: fmt.Sprintf(otelConfig, "health_check", "batch", "")},
+			expectedErr: "the pipeline metrics needs at least a receiver and an exporter",
+		},
+		{
+			name: "connector between pipelines",
+			spec: v1alpha1.AmazonCloudWatchAgentSpec{OtelConfig: `
+receivers:
+  otlp:
+exporters:
+  awsemf:
+connectors:
+  spanmetrics:
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [spanmetrics]
+    metrics:
+      receivers: [spanmetrics]
+      exporters: [awsemf]
+`},
+		},
+		{
+			name:        "scrape config without job name",
+			spec:        v1alpha1.AmazonCloudWatchAgentSpec{Prometheus: scrapeConfigs("pods", "")},
+			expectedErr: "the scrape config #1 has no job_name",
+		},
+		{
+			name:        "duplicate job names",
+			spec:        v1alpha1.AmazonCloudWatchAgentSpec{Prometheus: scrapeConfigs("pods", "pods")},
+			expectedErr: "the job_name pods is used by several scrape configs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConfig(v1alpha1.AmazonCloudWatchAgent{Spec: tt.spec})
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package collector
 
 import (
+	"context"
 	"fmt"
 
 	"gopkg.in/yaml.v2"
@@ -14,15 +15,20 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/translator"
 )
 
-func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
+func ConfigMaps(ctx context.Context, params manifests.Params) ([]*corev1.ConfigMap, error) {
 	var configmaps []*corev1.ConfigMap
 
-	name := naming.ConfigMap(params.OtelCol.Name)
+	name := naming.ConfigMap(params.OtelCol.ResourceName())
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
 
-	replacedConf, err := ReplaceConfig(params.OtelCol)
+	instance, err := WithConfigVariables(params.Config, params.OtelCol)
+	if err != nil {
+		return nil, err
+	}
+	replacedConf, err := ReplaceConfig(instance)
 	if err != nil {
 		params.Log.V(2).Info("failed to update config: ", "err", err)
 		return nil, err
@@ -33,7 +39,7 @@ func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
 	}
 
 	if params.OtelCol.Spec.OtelConfig != "" {
-		replacedOtelConfig, err := ReplaceOtelConfig(params.OtelCol)
+		replacedOtelConfig, err := ReplaceOtelConfig(instance)
 		if err != nil {
 			params.Log.V(2).Info("failed to update otel config: ", "err", err)
 			return nil, err
@@ -41,6 +47,13 @@ func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
 		sourceDataMap[params.Config.OtelCollectorConfigMapEntry()] = replacedOtelConfig
 	}
 
+	if err := addTranslatedConfigs(ctx, params, sourceDataMap); err != nil {
+		return nil, err
+	}
+	if hotReloadEnabled(params.Config, params.OtelCol) {
+		sourceDataMap[configHashEntry] = ConfigHash(params.Config, params.OtelCol)
+	}
+
 	configmaps = append(configmaps, &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
@@ -52,7 +65,7 @@ func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
 	})
 
 	if !params.OtelCol.Spec.Prometheus.IsEmpty() {
-		promName := naming.PrometheusConfigMap(params.OtelCol.Name)
+		promName := naming.PrometheusConfigMap(params.OtelCol.ResourceName())
 		promLabels := manifestutils.Labels(params.OtelCol.ObjectMeta, promName, "", ComponentAmazonCloudWatchAgent, []string{})
 
 		replacedPrometheusConf, err := ReplacePrometheusConfig(params.OtelCol)
@@ -95,3 +108,31 @@ func ConfigMaps(params manifests.Params) ([]*corev1.ConfigMap, error) {
 
 	return configmaps, nil
 }
+
+// addTranslatedConfigs projects the TOML and YAML translations of the JSON config of the agent to the entries of its
+// ConfigMap, which the agent is started on, when the operator has a config translator.
+func addTranslatedConfigs(ctx context.Context, params manifests.Params, data map[string]string) error {
+	configTranslator := params.Config.ConfigTranslator()
+	if configTranslator == nil || params.OtelCol.Spec.Config == "" {
+		return nil
+	}
+	goos := params.OtelCol.Spec.NodeSelector["kubernetes.io/os"]
+	if goos == "" {
+		goos = "linux"
+	}
+	result, err := configTranslator.Translate(ctx, data[params.Config.CollectorConfigMapEntry()], goos)
+	if err != nil {
+		return fmt.Errorf("failed to translate the config: %w", err)
+	}
+	if err := translator.Validate(data[params.Config.CollectorConfigMapEntry()], result, data[params.Config.OtelCollectorConfigMapEntry()]); err != nil {
+		return err
+	}
+	data[translator.TOMLEntry] = result.TOML
+	if result.YAML != "" {
+		data[translator.YAMLEntry] = result.YAML
+	}
+	if result.EnvConfig != "" {
+		data[translator.EnvConfigEntry] = result.EnvConfig
+	}
+	return nil
+}
@@ -4,6 +4,8 @@
 package collector
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -16,6 +18,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/translator"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 )
 
@@ -37,7 +40,7 @@ func TestDesiredConfigMap(t *testing.T) {
 		}
 
 		param := deploymentParams()
-		actual, err := ConfigMaps(param)
+		actual, err := ConfigMaps(context.Background(), param)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "test", actual[0].Name)
@@ -117,7 +120,7 @@ func TestDesiredPrometheusConfigMap(t *testing.T) {
 				},
 			},
 		}
-		actual, err := ConfigMaps(param)
+		actual, err := ConfigMaps(context.Background(), param)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "test-prometheus-config", actual[1].Name)
@@ -165,7 +168,7 @@ func TestDesiredPrometheusConfigMap(t *testing.T) {
 			},
 		}
 		param.OtelCol.Spec.TargetAllocator.Enabled = true
-		actual, err := ConfigMaps(param)
+		actual, err := ConfigMaps(context.Background(), param)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "test-prometheus-config", actual[1].GetName())
@@ -223,7 +226,7 @@ target_allocator:
 		}
 		assert.NoError(t, err)
 		param.OtelCol.Spec.TargetAllocator.Enabled = true
-		actual, err := ConfigMaps(param)
+		actual, err := ConfigMaps(context.Background(), param)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "test-prometheus-config", actual[1].Name)
@@ -269,7 +272,7 @@ target_allocator:
 		}
 		assert.NoError(t, err)
 		param.OtelCol.Spec.TargetAllocator.Enabled = true
-		actual, err := ConfigMaps(param)
+		actual, err := ConfigMaps(context.Background(), param)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "test-prometheus-config", actual[1].Name)
@@ -317,7 +320,7 @@ service:
 		}
 
 		param := otelConfigParams()
-		actual, err := ConfigMaps(param)
+		actual, err := ConfigMaps(context.Background(), param)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "test", actual[0].Name)
@@ -326,3 +329,64 @@ service:
 		assert.YAMLEq(t, expectedData["cwagentotelconfig.yaml"], actual[0].Data["cwagentotelconfig.yaml"])
 	})
 }
+
+type staticTranslator struct {
+	result translator.Result
+	err    error
+	os     string
+}
+
+func (s *staticTranslator) Translate(_ context.Context, _ string, os string) (translator.Result, error) {
+	s.os = os
+	return s.result, s.err
+}
+
+func TestDesiredConfigMapWithTranslatedConfigs(t *testing.T) {
+	// the test config has logs and traces sections
+	tomlConfig := "[outputs]\n  [[outputs.cloudwatchlogs]]\n"
+	yamlConfig := "service:\n  pipelines:\n    traces/xray: {}\n"
+	withTranslator := func(params manifests.Params, configTranslator translator.Translator) manifests.Params {
+		params.Config = config.New(config.WithCollectorImage(defaultCollectorImage), config.WithConfigTranslator(configTranslator))
+		return params
+	}
+
+	t.Run("should project the translated configs", func(t *testing.T) {
+		configTranslator := &staticTranslator{result: translator.Result{TOML: tomlConfig, YAML: yamlConfig, EnvConfig: "{}"}}
+		params := withTranslator(otelConfigParams(), configTranslator)
+		params.OtelCol.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+
+		actual, err := ConfigMaps(context.Background(), params)
+		assert.NoError(t, err)
+		assert.Equal(t, "windows", configTranslator.os)
+		assert.Equal(t, tomlConfig, actual[0].Data["cwagentconfig.toml"])
+		assert.Equal(t, yamlConfig, actual[0].Data["cwagentconfig.yaml"])
+		assert.Equal(t, "{}", actual[0].Data["env-config.json"])
+		assert.Contains(t, actual[0].Data, "cwagentconfig.json")
+		assert.Contains(t, actual[0].Data, "cwagentotelconfig.yaml")
+	})
+
+	t.Run("should translate for linux by default", func(t *testing.T) {
+		configTranslator := &staticTranslator{result: translator.Result{TOML: tomlConfig, YAML: yamlConfig}}
+		actual, err := ConfigMaps(context.Background(), withTranslator(deploymentParams(), configTranslator))
+		assert.NoError(t, err)
+		assert.Equal(t, "linux", configTranslator.os)
+		assert.NotContains(t, actual[0].Data, "env-config.json")
+	})
+
+	t.Run("should fail on translation errors", func(t *testing.T) {
+		_, err := ConfigMaps(context.Background(), withTranslator(deploymentParams(), &staticTranslator{err: errors.New("unknown section")}))
+		assert.ErrorContains(t, err, "unknown section")
+	})
+
+	t.Run("should fail on untranslated sections", func(t *testing.T) {
+		configTranslator := &staticTranslator{result: translator.Result{TOML: tomlConfig}}
+		_, err := ConfigMaps(context.Background(), withTranslator(deploymentParams(), configTranslator))
+		assert.ErrorContains(t, err, "the traces section of the config isn't translated")
+	})
+
+	t.Run("should fail on inconsistent configs", func(t *testing.T) {
+		configTranslator := &staticTranslator{result: translator.Result{TOML: tomlConfig, YAML: yamlConfig + "    metrics: {}\n"}}
+		_, err := ConfigMaps(context.Background(), withTranslator(otelConfigParams(), configTranslator))
+		assert.ErrorContains(t, err, "the otelConfig defines the pipeline metrics")
+	})
+}
@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// WithConfigOverrides returns a copy of the instance whose config and otelConfig have the patches of the overrides
+// selecting it layered onto them. The overrides are applied by increasing priority, then by name, so that the one
+// with the highest priority wins. The otelConfig patches are skipped for the instances without an otelConfig, and
+// an override that can't be applied is an error naming it.
+func WithConfigOverrides(instance v1alpha1.AmazonCloudWatchAgent, overrides []v1alpha1.AmazonCloudWatchAgentConfigOverride) (v1alpha1.AmazonCloudWatchAgent, error) {
+	selected := make([]v1alpha1.AmazonCloudWatchAgentConfigOverride, 0, len(overrides))
+	for _, override := range overrides {
+		// LabelSelectorAsSelector matches nothing with a nil selector, unlike an empty one
+		selector := labels.Everything()
+		if override.Spec.Selector != nil {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(override.Spec.Selector); err != nil {
+				return instance, fmt.Errorf("the selector of the config override %s is invalid: %w", override.Name, err)
+			}
+		}
+		if selector.Matches(labels.Set(instance.Labels)) {
+			selected = append(selected, override)
+		}
+	}
+	if len(selected) == 0 {
+		return instance, nil
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		if selected[i].Spec.Priority != selected[j].Spec.Priority {
+			return selected[i].Spec.Priority < selected[j].Spec.Priority
+		}
+		return selected[i].Name < selected[j].Name
+	})
+
+	result := *instance.DeepCopy()
+	for _, override := range selected {
+		if override.Spec.Config != "" {
+			config := result.Spec.Config
+			if config == "" {
+				config = "{}"
+			}
+			patched, err := jsonpatch.MergePatch([]byte(config), []byte(override.Spec.Config))
+			if err != nil {
+				return instance, fmt.Errorf("the config of the config override %s can't be applied: %w", override.Name, err)
+			}
+			result.Spec.Config = string(patched)
+		}
+		if override.Spec.OtelConfig != "" && result.Spec.OtelConfig != "" {
+			patched, err := yamlMergePatch(result.Spec.OtelConfig, override.Spec.OtelConfig)
+			if err != nil {
+				return instance, fmt.Errorf("the otelConfig of the config override %s can't be applied: %w", override.Name, err)
+			}
+			result.Spec.OtelConfig = patched
+		}
+	}
+	return result, nil
+}
+
+// yamlMergePatch applies the merge patch of the patch document to the given document, both being YAML.
+func yamlMergePatch(doc, patch string) (string, error) {
+	docJSON, err := yaml.YAMLToJSON([]byte(doc))
+	if err != nil {
+		return "", err
+	}
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch))
+	if err != nil {
+		return "", err
+	}
+	patched, err := jsonpatch.MergePatch(docJSON, patchJSON)
+	if err != nil {
+		return "", err
+	}
+	patchedYAML, err := yaml.JSONToYAML(patched)
+	if err != nil {
+		return "", err
+	}
+	return string(patchedYAML), nil
+}
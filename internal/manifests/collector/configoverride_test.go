@@ -0,0 +1,110 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func configOverride(name string, priority int32, selector *metav1.LabelSelector, config, otelConfig string) v1alpha1.AmazonCloudWatchAgentConfigOverride {
+	return v1alpha1.AmazonCloudWatchAgentConfigOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.AmazonCloudWatchAgentConfigOverrideSpec{
+			Priority:   priority,
+			Selector:   selector,
+			Config:     config,
+			OtelConfig: otelConfig,
+		},
+	}
+}
+
+func TestWithConfigOverrides(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Labels: map[string]string{"env": "prod"}},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"agent":{"region":"us-west-2","debug":true},"logs":{"force_flush_interval":5}}`,
+		},
+	}
+	overrides := []v1alpha1.AmazonCloudWatchAgentConfigOverride{
+		// the highest priority is applied last, whatever the order of the overrides
+		configOverride("fleet-high", 10, nil, `{"agent":{"region":"eu-west-1"}}`, ""),
+		configOverride("fleet-low", 1, &metav1.LabelSelector{}, `{"agent":{"region":"us-east-1","debug":null}}`, ""),
+		configOverride("prod", 5, &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}, `{"logs":{"force_flush_interval":15}}`, ""),
+		configOverride("dev", 20, &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}, `{"logs":{"force_flush_interval":1}}`, ""),
+	}
+
+	rendered, err := WithConfigOverrides(agent, overrides)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"agent":{"region":"eu-west-1"},"logs":{"force_flush_interval":15}}`, rendered.Spec.Config)
+	assert.Contains(t, agent.Spec.Config, `"debug":true`, "the instance is left untouched")
+
+	// the overrides with the same priority are applied by name
+	rendered, err = WithConfigOverrides(agent, []v1alpha1.AmazonCloudWatchAgentConfigOverride{
+		configOverride("b", 0, nil, `{"agent":{"region":"b"}}`, ""),
+		configOverride("a", 0, nil, `{"agent":{"region":"a"}}`, ""),
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"agent":{"region":"b","debug":true},"logs":{"force_flush_interval":5}}`, rendered.Spec.Config)
+}
+
+func TestWithConfigOverridesOtelConfig(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `
+receivers:
+  otlp:
+    protocols:
+      grpc: {}
+exporters:
+  debug:
+    verbosity: basic
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+		},
+	}
+	override := configOverride("verbose", 0, nil, `{"logs":{"force_flush_interval":15}}`, `
+exporters:
+  debug:
+    verbosity: detailed
+`)
+
+	rendered, err := WithConfigOverrides(agent, []v1alpha1.AmazonCloudWatchAgentConfigOverride{override})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"logs":{"force_flush_interval":15}}`, rendered.Spec.Config)
+	config, err := adapters.ConfigFromString(rendered.Spec.OtelConfig)
+	require.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{"verbosity": "detailed"}, config["exporters"].(map[interface{}]interface{})["debug"])
+	assert.Contains(t, config, "receivers")
+
+	// the agents without an otelConfig are left without one
+	agent.Spec.OtelConfig = ""
+	rendered, err = WithConfigOverrides(agent, []v1alpha1.AmazonCloudWatchAgentConfigOverride{override})
+	require.NoError(t, err)
+	assert.Empty(t, rendered.Spec.OtelConfig)
+}
+
+func TestWithConfigOverridesInvalid(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{Spec: v1alpha1.AmazonCloudWatchAgentSpec{Config: `{}`}}
+
+	_, err := WithConfigOverrides(agent, []v1alpha1.AmazonCloudWatchAgentConfigOverride{
+		configOverride("broken", 0, nil, `{"agent":`, ""),
+	})
+	assert.ErrorContains(t, err, "config override broken")
+
+	_, err = WithConfigOverrides(agent, []v1alpha1.AmazonCloudWatchAgentConfigOverride{
+		configOverride("selector", 0, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Near"}}}, `{}`, ""),
+	})
+	assert.ErrorContains(t, err, "the selector of the config override selector is invalid")
+}
@@ -12,15 +12,37 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/agentgates"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/translator"
+)
+
+// The paths of the agent binary in the Linux and Windows agent images.
+const (
+	linuxAgentBinary   = "/opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent"
+	windowsAgentBinary = "C:\\Program Files\\Amazon\\AmazonCloudWatchAgent\\amazon-cloudwatch-agent.exe"
 )
 
 // maxPortLen allows us to truncate a port name according to what is considered valid port syntax:
 // https://pkg.go.dev/k8s.io/apimachinery/pkg/util/validation#IsValidPortName
 const maxPortLen = 15
 
+// podContainers returns the containers of the agent pods: the additional containers, the agent and its sidecars.
+func podContainers(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloudWatchAgent) []corev1.Container {
+	containers := make([]corev1.Container, 0, len(agent.Spec.AdditionalContainers)+3)
+	containers = append(containers, agent.Spec.AdditionalContainers...)
+	containers = append(containers, Container(cfg, logger, agent, true))
+	if agent.PrometheusReloadEnabled() {
+		containers = append(containers, PrometheusReloaderContainer(cfg, agent))
+	}
+	if hotReloadEnabled(cfg, agent) {
+		containers = append(containers, ConfigReloaderContainer(cfg, agent))
+	}
+	return containers
+}
+
 // Container builds a container for the given collector.
 func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloudWatchAgent, addConfig bool) corev1.Container {
 	image := agent.Spec.Image
@@ -29,11 +51,17 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 	}
 
 	ports := getContainerPorts(logger, agent.Spec.Config, agent.Spec.OtelConfig, agent.Spec.Ports)
+	// the debug ports stay off the agent services, they are only exposed through the debug service
+	for _, p := range debugContainerPorts(agent.Spec.Debug) {
+		if _, ok := ports[p.Name]; !ok {
+			ports[p.Name] = p
+		}
+	}
 
 	var volumeMounts []corev1.VolumeMount
-	argsMap := agent.Spec.Args
-	if argsMap == nil {
-		argsMap = map[string]string{}
+	specArgs := v1alpha1.Args{}
+	if agent.Spec.Args != nil {
+		specArgs = *agent.Spec.Args
 	}
 	// defines the output (sorted) array for final output
 	var args []string
@@ -50,18 +78,30 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		if !agent.Spec.Prometheus.IsEmpty() {
 			volumeMounts = append(volumeMounts, getPrometheusVolumeMounts(agent.Spec.NodeSelector["kubernetes.io/os"]))
 		}
+
+		volumeMounts = append(volumeMounts, bufferVolumeMounts(agent)...)
+		volumeMounts = append(volumeMounts, logFileVolumeMounts(agent)...)
+		volumeMounts = append(volumeMounts, persistenceVolumeMounts(agent)...)
+		volumeMounts = append(volumeMounts, tlsVolumeMounts(agent)...)
+		volumeMounts = append(volumeMounts, trustedCAVolumeMounts(agent)...)
 	}
 
-	// ensure that the v1alpha1.AmazonCloudWatchAgentSpec.Args are ordered when moved to container.Args,
+	// ensure that the flags of v1alpha1.AmazonCloudWatchAgentSpec.Args are ordered when moved to container.Args,
 	// where iterating over a map does not guarantee, so that reconcile will not be fooled by different
 	// ordering in args.
 	var sortedArgs []string
-	for k, v := range argsMap {
+	for k, v := range specArgs.Flags {
 		sortedArgs = append(sortedArgs, fmt.Sprintf("--%s=%s", k, v))
 	}
 	sort.Strings(sortedArgs)
 	args = append(args, sortedArgs...)
+	if featureGates := agentgates.Flag(agent.Spec.FeatureGates); featureGates != "" {
+		args = append(args, "--feature-gates="+featureGates)
+	}
+	// the list form is passed as is, as the order of positional arguments and repeated flags matters
+	args = append(args, specArgs.List...)
 
+	volumeMounts = append(volumeMounts, extraMountVolumeMounts(agent)...)
 	if len(agent.Spec.VolumeMounts) > 0 {
 		volumeMounts = append(volumeMounts, agent.Spec.VolumeMounts...)
 	}
@@ -71,14 +111,19 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		envVars = []corev1.EnvVar{}
 	}
 
-	envVars = append(envVars, corev1.EnvVar{
+	injectedEnvVars := []corev1.EnvVar{{
 		Name: "POD_NAME",
 		ValueFrom: &corev1.EnvVarSource{
 			FieldRef: &corev1.ObjectFieldSelector{
 				FieldPath: "metadata.name",
 			},
 		},
-	})
+	}}
+
+	injectedEnvVars = append(injectedEnvVars, proxyEnvVars(agent.Spec.Proxy)...)
+	injectedEnvVars = append(injectedEnvVars, endpointOverridesEnvVars(agent.Spec.AWSEndpointOverrides)...)
+	injectedEnvVars = append(injectedEnvVars, trustedCAEnvVars(agent)...)
+	injectedEnvVars = append(injectedEnvVars, goRuntimeEnvVars(agent)...)
 
 	if agent.Spec.TargetAllocator.Enabled {
 		// We need to add a SHARD here so the collector is able to keep targets after the hashmod operation which is
@@ -86,16 +131,23 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		// All collector instances use SHARD == 0 as they only receive targets
 		// allocated to them and should not use the Prometheus hashmod-based
 		// allocation.
-		envVars = append(envVars, corev1.EnvVar{
+		injectedEnvVars = append(injectedEnvVars, corev1.EnvVar{
 			Name:  "SHARD",
 			Value: "0",
 		})
 	}
 
+	envVars = injectEnvVars(agent.Spec.InjectedEnvPolicy, envVars, injectedEnvVars)
+
 	if _, err := adapters.ConfigFromJSONString(agent.Spec.Config); err != nil {
 		logger.Error(err, "error parsing config")
 	}
 
+	command := agent.Spec.Command
+	if len(command) == 0 && addConfig && configTranslated(cfg, agent) {
+		command = translatedConfigCommand(cfg, agent)
+	}
+
 	var livenessProbe *corev1.Probe
 	if configFromString, err := adapters.ConfigFromString(agent.Spec.OtelConfig); err == nil {
 		if probe, err := getLivenessProbe(configFromString, agent.Spec.LivenessProbe); err == nil {
@@ -109,10 +161,11 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		}
 	}
 
-	return corev1.Container{
+	container := corev1.Container{
 		Name:            naming.Container(),
 		Image:           image,
 		ImagePullPolicy: agent.Spec.ImagePullPolicy,
+		Command:         command,
 		WorkingDir:      agent.Spec.WorkingDir,
 		VolumeMounts:    volumeMounts,
 		Args:            args,
@@ -124,6 +177,60 @@ func Container(cfg config.Config, logger logr.Logger, agent v1alpha1.AmazonCloud
 		LivenessProbe:   livenessProbe,
 		Lifecycle:       agent.Spec.Lifecycle,
 	}
+
+	if agent.Spec.ContainerOverride != nil {
+		overridden, err := containerWithOverride(container, agent.Spec.ContainerOverride.Raw)
+		if err != nil {
+			logger.Error(err, "cannot apply the container override")
+		} else {
+			container = overridden
+		}
+	}
+	return container
+}
+
+// proxyEnvVars returns the proxy environment variables for the given proxy spec.
+func proxyEnvVars(proxy v1alpha1.ProxySpec) []corev1.EnvVar {
+	var proxyVars []corev1.EnvVar
+	for _, env := range []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: proxy.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: proxy.HTTPSProxy},
+		{Name: "NO_PROXY", Value: proxy.NoProxy},
+	} {
+		if env.Value != "" {
+			proxyVars = append(proxyVars, env)
+		}
+	}
+	return proxyVars
+}
+
+// injectEnvVars appends the operator computed environment variables to the user defined ones, following the
+// given policy. Variables already defined by the user are never injected, as duplicates fail the pod validation.
+func injectEnvVars(policy v1alpha1.InjectedEnvPolicy, envVars []corev1.EnvVar, injected []corev1.EnvVar) []corev1.EnvVar {
+	disabled := map[string]bool{}
+	for _, name := range policy.Disabled {
+		disabled[name] = true
+	}
+	defined := map[string]bool{}
+	for _, env := range envVars {
+		defined[env.Name] = true
+	}
+
+	result := append([]corev1.EnvVar{}, envVars...)
+	for _, env := range injected {
+		if disabled[env.Name] {
+			continue
+		}
+		if name, ok := policy.Rename[env.Name]; ok && name != "" {
+			env.Name = name
+		}
+		if defined[env.Name] {
+			continue
+		}
+		defined[env.Name] = true
+		result = append(result, env)
+	}
+	return result
 }
 
 func getVolumeMounts(os string) corev1.VolumeMount {
@@ -142,6 +249,32 @@ func getVolumeMounts(os string) corev1.VolumeMount {
 	return volumeMount
 }
 
+// configTranslated tells whether the translations of the config of the agent are projected to its ConfigMap.
+func configTranslated(cfg config.Config, agent v1alpha1.AmazonCloudWatchAgent) bool {
+	return cfg.ConfigTranslator() != nil && agent.Spec.Config != ""
+}
+
+// translatedConfigCommand returns the command starting the agent binary directly on the translated configs projected
+// to its ConfigMap, in place of the start-amazon-cloudwatch-agent entrypoint of the image, which translates the JSON
+// config again. The agent skips the OpenTelemetry and environment configs the translator didn't write.
+func translatedConfigCommand(cfg config.Config, agent v1alpha1.AmazonCloudWatchAgent) []string {
+	goos := agent.Spec.NodeSelector["kubernetes.io/os"]
+	binary, dir, separator := linuxAgentBinary, getVolumeMounts(goos).MountPath, "/"
+	if goos == "windows" {
+		binary, separator = windowsAgentBinary, "\\"
+	}
+	command := []string{
+		binary,
+		"-config", dir + separator + translator.TOMLEntry,
+		"-envconfig", dir + separator + translator.EnvConfigEntry,
+		"-otelconfig", dir + separator + translator.YAMLEntry,
+	}
+	if agent.Spec.OtelConfig != "" {
+		command = append(command, "-otelconfig", dir+separator+cfg.OtelCollectorConfigMapEntry())
+	}
+	return command
+}
+
 func getPrometheusVolumeMounts(os string) corev1.VolumeMount {
 	var volumeMount corev1.VolumeMount
 	if os == "windows" {
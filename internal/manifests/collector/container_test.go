@@ -10,6 +10,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -217,3 +218,188 @@ service:
 	assert.Equal(t, int32(13133), c.LivenessProbe.HTTPGet.Port.IntVal)
 	assert.Equal(t, "", c.LivenessProbe.HTTPGet.Host)
 }
+
+func TestContainerBufferVolumeMounts(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `extensions:
+  file_storage:
+    directory: /var/spool/otel`,
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Len(t, c.VolumeMounts, 2)
+	assert.Equal(t, naming.BufferVolume(0), c.VolumeMounts[1].Name)
+	assert.Equal(t, "/var/spool/otel", c.VolumeMounts[1].MountPath)
+}
+
+func TestContainerProxyEnvVars(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Env: []corev1.EnvVar{
+				{Name: "NO_PROXY", Value: "localhost"},
+			},
+			Proxy: v1alpha1.ProxySpec{
+				HTTPSProxy: "http://proxy:3128",
+				NoProxy:    "169.254.169.254",
+			},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"})
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "NO_PROXY", Value: "localhost"})
+	assert.NotContains(t, c.Env, corev1.EnvVar{Name: "NO_PROXY", Value: "169.254.169.254"})
+	for _, env := range c.Env {
+		assert.NotEqual(t, "HTTP_PROXY", env.Name)
+	}
+}
+
+func TestContainerCommand(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Command: []string{"/opt/aws/amazon-cloudwatch-agent/bin/start-amazon-cloudwatch-agent", "-otelconfig", "/etc/extra.yaml", "-otelconfig", "/etc/other.yaml"},
+			Args:    &v1alpha1.Args{Flags: map[string]string{"log-level": "debug"}},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	assert.Equal(t, otelcol.Spec.Command, c.Command)
+	assert.Equal(t, []string{"--log-level=debug"}, c.Args)
+	assert.Empty(t, Container(cfg, logger, v1alpha1.AmazonCloudWatchAgent{}, true).Command)
+}
+
+func TestContainerTranslatedConfigCommand(t *testing.T) {
+	cfg := config.New(config.WithConfigTranslator(&staticTranslator{}))
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config:     `{"logs":{}}`,
+			OtelConfig: "service: {}",
+		},
+	}
+
+	assert.Equal(t, []string{
+		"/opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent",
+		"-config", "/etc/cwagentconfig/cwagentconfig.toml",
+		"-envconfig", "/etc/cwagentconfig/env-config.json",
+		"-otelconfig", "/etc/cwagentconfig/cwagentconfig.yaml",
+		"-otelconfig", "/etc/cwagentconfig/cwagentotelconfig.yaml",
+	}, Container(cfg, logger, otelcol, true).Command)
+
+	windows := otelcol.DeepCopy()
+	windows.Spec.OtelConfig = ""
+	windows.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+	assert.Equal(t, []string{
+		"C:\\Program Files\\Amazon\\AmazonCloudWatchAgent\\amazon-cloudwatch-agent.exe",
+		"-config", "C:\\Program Files\\Amazon\\AmazonCloudWatchAgent\\cwagentconfig\\cwagentconfig.toml",
+		"-envconfig", "C:\\Program Files\\Amazon\\AmazonCloudWatchAgent\\cwagentconfig\\env-config.json",
+		"-otelconfig", "C:\\Program Files\\Amazon\\AmazonCloudWatchAgent\\cwagentconfig\\cwagentconfig.yaml",
+	}, Container(cfg, logger, *windows, true).Command)
+
+	// the command of the spec and the agents without a config keep the entrypoint of the image
+	custom := otelcol.DeepCopy()
+	custom.Spec.Command = []string{"/custom"}
+	assert.Equal(t, []string{"/custom"}, Container(cfg, logger, *custom, true).Command)
+	assert.Empty(t, Container(cfg, logger, v1alpha1.AmazonCloudWatchAgent{}, true).Command)
+	assert.Nil(t, Volumes(cfg, otelcol)[0].ConfigMap.Items)
+}
+
+func TestContainerArgs(t *testing.T) {
+	cfg := config.New()
+
+	for _, tt := range []struct {
+		desc     string
+		args     *v1alpha1.Args
+		expected []string
+	}{
+		{
+			desc:     "no args",
+			expected: nil,
+		},
+		{
+			desc:     "flags sorted by key",
+			args:     &v1alpha1.Args{Flags: map[string]string{"mode": "ec2", "log-level": "debug"}},
+			expected: []string{"--log-level=debug", "--mode=ec2"},
+		},
+		{
+			desc:     "list passed as is",
+			args:     &v1alpha1.Args{List: []string{"--feature-gates=a", "--feature-gates=b", "run"}},
+			expected: []string{"--feature-gates=a", "--feature-gates=b", "run"},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			otelcol := v1alpha1.AmazonCloudWatchAgent{
+				Spec: v1alpha1.AmazonCloudWatchAgentSpec{Args: tt.args},
+			}
+
+			c := Container(cfg, logger, otelcol, true)
+
+			assert.Equal(t, tt.expected, c.Args)
+		})
+	}
+}
+
+func TestContainerFeatureGates(t *testing.T) {
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config:       "{}",
+			Args:         &v1alpha1.Args{Flags: map[string]string{"log-level": "debug"}},
+			FeatureGates: map[string]bool{"pkg.translator.prometheus.NormalizeName": true, "exporter.xray.allowDot": false},
+		},
+	}
+
+	c := Container(config.New(), logger, otelcol, true)
+
+	assert.Equal(t, []string{"--log-level=debug", "--feature-gates=-exporter.xray.allowDot,+pkg.translator.prometheus.NormalizeName"}, c.Args)
+}
+
+func TestContainerInjectedEnvPolicy(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Env: []corev1.EnvVar{
+				{Name: "SHARD", Value: "1"},
+			},
+			Proxy: v1alpha1.ProxySpec{
+				HTTPProxy:  "http://proxy:3128",
+				HTTPSProxy: "http://proxy:3128",
+			},
+			TargetAllocator: v1alpha1.AmazonCloudWatchAgentTargetAllocator{
+				Enabled: true,
+			},
+			InjectedEnvPolicy: v1alpha1.InjectedEnvPolicy{
+				Disabled: []string{"HTTP_PROXY"},
+				Rename:   map[string]string{"POD_NAME": "K8S_POD_NAME"},
+			},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	var names []string
+	for _, env := range c.Env {
+		names = append(names, env.Name)
+	}
+	assert.Equal(t, []string{"SHARD", "K8S_POD_NAME", "HTTPS_PROXY"}, names)
+	assert.Equal(t, "1", c.Env[0].Value)
+	assert.Equal(t, "metadata.name", c.Env[1].ValueFrom.FieldRef.FieldPath)
+}
@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// containerWithOverride strategically merges the container override of the agent onto the generated container, so
+// that the lists such as the env vars, the ports and the volume mounts are merged by their keys. The name of the
+// container is kept, as the operator looks the container up by it.
+func containerWithOverride(container corev1.Container, override []byte) (corev1.Container, error) {
+	original, err := json.Marshal(container)
+	if err != nil {
+		return container, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, override, corev1.Container{})
+	if err != nil {
+		return container, fmt.Errorf("failed to merge the container override: %w", err)
+	}
+	result := corev1.Container{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return container, fmt.Errorf("failed to merge the container override: %w", err)
+	}
+	result.Name = container.Name
+	return result, nil
+}
@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestContainerOverride(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Env: []corev1.EnvVar{
+				{Name: "RUN_WITH_IRSA", Value: "true"},
+				{Name: "DEBUG", Value: "false"},
+			},
+			ContainerOverride: &runtime.RawExtension{Raw: []byte(`{
+				"name": "renamed",
+				"stdin": true,
+				"tty": true,
+				"env": [{"name": "DEBUG", "value": "true"}, {"name": "RUN_WITH_IRSA", "$patch": "delete"}]
+			}`)},
+		},
+	}
+
+	c := Container(config.New(), logger, agent, true)
+
+	assert.Equal(t, "otc-container", c.Name)
+	assert.True(t, c.Stdin)
+	assert.True(t, c.TTY)
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "DEBUG", Value: "true"})
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}})
+	for _, env := range c.Env {
+		assert.NotEqual(t, "RUN_WITH_IRSA", env.Name)
+	}
+	assert.NotEmpty(t, c.VolumeMounts)
+}
+
+func TestContainerOverrideInvalid(t *testing.T) {
+	container := corev1.Container{Name: "otc-container", Image: "agent:1"}
+
+	result, err := containerWithOverride(container, []byte(`{"env": "DEBUG=true"}`))
+	require.Error(t, err)
+	assert.Equal(t, container, result)
+}
@@ -0,0 +1,123 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"github.com/prometheus/common/model"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	serviceAccountCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// controlPlaneMetricsPaths are the paths of the metrics of the control plane components on the API server. Amazon
+// EKS exposes the metrics of the controller manager and the scheduler, which run out of reach of the cluster,
+// through the API server.
+var controlPlaneMetricsPaths = map[v1alpha1.ControlPlaneComponent]string{
+	v1alpha1.ControlPlaneAPIServer:         "/metrics",
+	v1alpha1.ControlPlaneControllerManager: "/apis/metrics.eks.amazonaws.com/v1/kcm/container/metrics",
+	v1alpha1.ControlPlaneScheduler:         "/apis/metrics.eks.amazonaws.com/v1/ksh/container/metrics",
+}
+
+// allControlPlaneComponents are the components scraped when the spec doesn't list any.
+var allControlPlaneComponents = []v1alpha1.ControlPlaneComponent{
+	v1alpha1.ControlPlaneAPIServer,
+	v1alpha1.ControlPlaneControllerManager,
+	v1alpha1.ControlPlaneScheduler,
+}
+
+// controlPlaneMetricSelectors are the metrics of each control plane component the agent publishes to CloudWatch.
+var controlPlaneMetricSelectors = map[v1alpha1.ControlPlaneComponent][]interface{}{
+	v1alpha1.ControlPlaneAPIServer: {
+		"^apiserver_request_total$",
+		"^apiserver_current_inflight_requests$",
+		"^apiserver_storage_objects$",
+		"^(etcd_db_total_size_in_bytes|apiserver_storage_db_total_size_in_bytes)$",
+	},
+	v1alpha1.ControlPlaneControllerManager: {"^workqueue_depth$", "^workqueue_adds_total$"},
+	v1alpha1.ControlPlaneScheduler:         {"^scheduler_pending_pods$", "^scheduler_schedule_attempts_total$"},
+}
+
+// controlPlaneComponents returns the components of the spec, or all of them when it doesn't list any.
+func controlPlaneComponents(spec *v1alpha1.ControlPlaneMetricsSpec) []v1alpha1.ControlPlaneComponent {
+	if len(spec.Components) == 0 {
+		return allControlPlaneComponents
+	}
+	return spec.Components
+}
+
+// WithControlPlaneMetrics returns a copy of the instance whose Prometheus configuration scrapes the control plane
+// components of its controlPlaneMetrics, through the endpoints of the kubernetes Service of the API server, with
+// the token and the CA of the service account of the agent. The instance is returned as is when they are disabled.
+func WithControlPlaneMetrics(instance v1alpha1.AmazonCloudWatchAgent) v1alpha1.AmazonCloudWatchAgent {
+	spec := instance.Spec.ControlPlaneMetrics
+	if spec == nil || !spec.Enabled {
+		return instance
+	}
+	result := *instance.DeepCopy()
+	for _, component := range controlPlaneComponents(spec) {
+		scrapeConfig := map[string]interface{}{
+			"job_name":     v1alpha1.ControlPlaneJobName(component),
+			"metrics_path": controlPlaneMetricsPaths[component],
+			"scheme":       "https",
+			"kubernetes_sd_configs": []interface{}{map[string]interface{}{
+				"role":       "endpoints",
+				"namespaces": map[string]interface{}{"names": []interface{}{"default"}},
+			}},
+			"tls_config":        map[string]interface{}{"ca_file": serviceAccountCAFile},
+			"bearer_token_file": serviceAccountTokenFile,
+			"relabel_configs": []interface{}{map[string]interface{}{
+				"source_labels": []interface{}{"__meta_kubernetes_namespace", "__meta_kubernetes_service_name", "__meta_kubernetes_endpoint_port_name"},
+				"action":        "keep",
+				"regex":         "default;kubernetes;https",
+			}},
+		}
+		if spec.ScrapeInterval != nil {
+			scrapeConfig["scrape_interval"] = model.Duration(spec.ScrapeInterval.Duration).String()
+		}
+		result.Spec.Prometheus.ExtraScrapeConfigs = append(result.Spec.Prometheus.ExtraScrapeConfigs, v1alpha1.AnyConfig{Object: scrapeConfig})
+	}
+	return result
+}
+
+// configWithControlPlaneMetrics adds the prometheus section of the logs to the given agent config when it has no
+// prometheus section, so that the agent scrapes the control plane components and publishes their metrics through
+// the embedded metric format, per component job.
+func configWithControlPlaneMetrics(config map[string]interface{}, spec *v1alpha1.ControlPlaneMetricsSpec) {
+	if spec == nil || !spec.Enabled {
+		return
+	}
+	for _, section := range []string{"logs", "metrics"} {
+		s, _ := config[section].(map[string]interface{})
+		collected, _ := s["metrics_collected"].(map[string]interface{})
+		if _, ok := collected["prometheus"]; ok {
+			return
+		}
+	}
+	logs, ok := config["logs"].(map[string]interface{})
+	if !ok {
+		logs = map[string]interface{}{}
+		config["logs"] = logs
+	}
+	collected, ok := logs["metrics_collected"].(map[string]interface{})
+	if !ok {
+		collected = map[string]interface{}{}
+		logs["metrics_collected"] = collected
+	}
+	var declarations []interface{}
+	for _, component := range controlPlaneComponents(spec) {
+		declarations = append(declarations, map[string]interface{}{
+			"source_labels":    []interface{}{"job"},
+			"label_matcher":    "^" + v1alpha1.ControlPlaneJobName(component) + "$",
+			"dimensions":       []interface{}{[]interface{}{"ClusterName", "job"}},
+			"metric_selectors": controlPlaneMetricSelectors[component],
+		})
+	}
+	collected["prometheus"] = map[string]interface{}{
+		"emf_processor": map[string]interface{}{"metric_declaration": declarations},
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestWithControlPlaneMetrics(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:   v1alpha1.ModeDeployment,
+			Config: `{"agent":{"region":"us-west-2"}}`,
+			ControlPlaneMetrics: &v1alpha1.ControlPlaneMetricsSpec{
+				Enabled:        true,
+				Components:     []v1alpha1.ControlPlaneComponent{v1alpha1.ControlPlaneAPIServer, v1alpha1.ControlPlaneScheduler},
+				ScrapeInterval: &metav1.Duration{Duration: 90 * time.Second},
+			},
+		},
+	}
+
+	withMetrics := WithControlPlaneMetrics(agent)
+	assert.True(t, agent.Spec.Prometheus.IsEmpty(), "the instance is left untouched")
+	require.Len(t, withMetrics.Spec.Prometheus.ExtraScrapeConfigs, 2)
+	apiserver := withMetrics.Spec.Prometheus.ExtraScrapeConfigs[0].Object
+	assert.Equal(t, "kubernetes-control-plane-apiserver", apiserver["job_name"])
+	assert.Equal(t, "/metrics", apiserver["metrics_path"])
+	assert.Equal(t, "https", apiserver["scheme"])
+	assert.Equal(t, "1m30s", apiserver["scrape_interval"])
+	assert.Equal(t, "/var/run/secrets/kubernetes.io/serviceaccount/token", apiserver["bearer_token_file"])
+	scheduler := withMetrics.Spec.Prometheus.ExtraScrapeConfigs[1].Object
+	assert.Equal(t, "kubernetes-control-plane-scheduler", scheduler["job_name"])
+	assert.Equal(t, "/apis/metrics.eks.amazonaws.com/v1/ksh/container/metrics", scheduler["metrics_path"])
+
+	// the agent config gets a prometheus section pointing at the Prometheus configuration
+	replaced, err := ReplaceConfig(withMetrics)
+	require.NoError(t, err)
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(replaced), &config))
+	prometheus := config["logs"].(map[string]interface{})["metrics_collected"].(map[string]interface{})["prometheus"].(map[string]interface{})
+	assert.Equal(t, "/etc/prometheusconfig/prometheus.yaml", prometheus["prometheus_config_path"])
+	declarations := prometheus["emf_processor"].(map[string]interface{})["metric_declaration"].([]interface{})
+	require.Len(t, declarations, 2)
+	assert.Equal(t, "^kubernetes-control-plane-apiserver$", declarations[0].(map[string]interface{})["label_matcher"])
+	assert.Equal(t, "^kubernetes-control-plane-scheduler$", declarations[1].(map[string]interface{})["label_matcher"])
+
+	promConfig, err := ReplacePrometheusConfig(withMetrics)
+	require.NoError(t, err)
+	parsed, err := adapters.ConfigFromString(promConfig)
+	require.NoError(t, err)
+	scrapeConfigs := parsed["config"].(map[interface{}]interface{})["scrape_configs"].([]interface{})
+	require.Len(t, scrapeConfigs, 2)
+	assert.Equal(t, "kubernetes-control-plane-apiserver", scrapeConfigs[0].(map[interface{}]interface{})["job_name"])
+
+	// all the components are scraped by default
+	agent.Spec.ControlPlaneMetrics = &v1alpha1.ControlPlaneMetricsSpec{Enabled: true}
+	assert.Len(t, WithControlPlaneMetrics(agent).Spec.Prometheus.ExtraScrapeConfigs, 3)
+
+	agent.Spec.ControlPlaneMetrics.Enabled = false
+	assert.Equal(t, agent, WithControlPlaneMetrics(agent))
+}
+
+func TestConfigWithControlPlaneMetrics(t *testing.T) {
+	enabled := &v1alpha1.ControlPlaneMetricsSpec{Enabled: true}
+	controllerManager := &v1alpha1.ControlPlaneMetricsSpec{Enabled: true, Components: []v1alpha1.ControlPlaneComponent{v1alpha1.ControlPlaneControllerManager}}
+	declaration := `{"source_labels":["job"],"label_matcher":"^kubernetes-control-plane-controller-manager$",` +
+		`"dimensions":[["ClusterName","job"]],"metric_selectors":["^workqueue_depth$","^workqueue_adds_total$"]}`
+	for _, tt := range []struct {
+		name     string
+		config   string
+		spec     *v1alpha1.ControlPlaneMetricsSpec
+		expected string
+	}{
+		{
+			name:     "disabled",
+			config:   `{"logs":{"logs_collected":{}}}`,
+			expected: `{"logs":{"logs_collected":{}}}`,
+		},
+		{
+			name:     "without prometheus section",
+			config:   `{"logs":{"logs_collected":{}}}`,
+			spec:     controllerManager,
+			expected: `{"logs":{"logs_collected":{},"metrics_collected":{"prometheus":{"emf_processor":{"metric_declaration":[` + declaration + `]}}}}}`,
+		},
+		{
+			name:     "with a prometheus section of the metrics",
+			config:   `{"metrics":{"metrics_collected":{"prometheus":{"prometheus_config_path":"/etc/prometheus.yaml"}}}}`,
+			spec:     enabled,
+			expected: `{"metrics":{"metrics_collected":{"prometheus":{"prometheus_config_path":"/etc/prometheus.yaml"}}}}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var config map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.config), &config))
+			configWithControlPlaneMetrics(config, tt.spec)
+			out, err := json.Marshal(config)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(out))
+		})
+	}
+}
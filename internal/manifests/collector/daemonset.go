@@ -15,14 +15,31 @@ import (
 
 // DaemonSet builds the deployment for the given instance.
 func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
-	name := naming.Collector(params.OtelCol.Name)
+	name := naming.Collector(params.OtelCol.ResourceName())
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter())
 
-	annotations := Annotations(params.OtelCol)
-	podAnnotations := PodAnnotations(params.OtelCol)
+	annotations := Annotations(params.Config, params.OtelCol)
+	containers := podContainers(params.Config, params.Log, params.OtelCol)
+	podAnnotations := withAppArmorProfile(params.OtelCol, PodAnnotations(params.Config, params.OtelCol), params.OtelCol.Spec.InitContainers, containers)
+
+	affinity := params.OtelCol.Spec.Affinity
+	if split := params.OtelCol.Spec.VersionSplit; split != nil {
+		// leave the nodes of the canary cohort to the canary daemonset
+		affinity = withNodeRequirement(affinity, corev1.NodeSelectorRequirement{
+			Key:      split.NodeLabel.Key,
+			Operator: corev1.NodeSelectorOpNotIn,
+			Values:   []string{split.NodeLabel.Value},
+		})
+	}
+	// leave the canary nodes of a canary rollout to the canary daemonset
+	affinity = withoutCanaryNodes(affinity, params.OtelCol)
+	for _, group := range nodeGroups(params.OtelCol) {
+		// leave the nodes of the node groups to their daemonsets
+		affinity = withoutNodes(affinity, group.requirements)
+	}
 	return &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        naming.Collector(params.OtelCol.Name),
+			Name:        naming.Collector(params.OtelCol.ResourceName()),
 			Namespace:   params.OtelCol.Namespace,
 			Labels:      labels,
 			Annotations: annotations,
@@ -33,21 +50,25 @@ func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      labels,
+					Labels:      podLabels(params.Config, params.OtelCol, labels),
 					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: ServiceAccountName(params.OtelCol),
-					InitContainers:     params.OtelCol.Spec.InitContainers,
-					Containers:         append(params.OtelCol.Spec.AdditionalContainers, Container(params.Config, params.Log, params.OtelCol, true)),
-					Volumes:            Volumes(params.Config, params.OtelCol),
-					Tolerations:        params.OtelCol.Spec.Tolerations,
-					NodeSelector:       params.OtelCol.Spec.NodeSelector,
-					HostNetwork:        params.OtelCol.Spec.HostNetwork,
-					DNSPolicy:          getDNSPolicy(params.OtelCol),
-					SecurityContext:    params.OtelCol.Spec.PodSecurityContext,
-					PriorityClassName:  params.OtelCol.Spec.PriorityClassName,
-					Affinity:           params.OtelCol.Spec.Affinity,
+					ServiceAccountName:            ServiceAccountName(params.OtelCol),
+					InitContainers:                params.OtelCol.Spec.InitContainers,
+					Containers:                    containers,
+					Volumes:                       Volumes(params.Config, params.OtelCol),
+					Tolerations:                   params.OtelCol.Spec.Tolerations,
+					NodeSelector:                  params.OtelCol.Spec.NodeSelector,
+					HostNetwork:                   hostNetwork(params.OtelCol),
+					HostPID:                       params.OtelCol.Spec.HostPID,
+					HostIPC:                       params.OtelCol.Spec.HostIPC,
+					DNSPolicy:                     getDNSPolicy(params.OtelCol),
+					SecurityContext:               podSecurityContext(params.OtelCol),
+					ShareProcessNamespace:         params.OtelCol.SharedProcessNamespace(),
+					PriorityClassName:             params.OtelCol.Spec.PriorityClassName,
+					Affinity:                      affinity,
+					TerminationGracePeriodSeconds: params.OtelCol.Spec.TerminationGracePeriodSeconds,
 				},
 			},
 			UpdateStrategy: params.OtelCol.Spec.UpdateStrategy,
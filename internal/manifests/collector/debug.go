@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const pprofExtension = "pprof"
+
+// debugPorts returns the pprof and the zpages ports of the debug spec, with their defaults.
+func debugPorts(debug *v1alpha1.DebugSpec) (pprof, zpages int32) {
+	pprof, zpages = v1alpha1.DefaultDebugPprofPort, v1alpha1.DefaultDebugZPagesPort
+	if debug.PprofPort != 0 {
+		pprof = debug.PprofPort
+	}
+	if debug.ZPagesPort != 0 {
+		zpages = debug.ZPagesPort
+	}
+	return pprof, zpages
+}
+
+// debugContainerPorts returns the container ports of the debug endpoints of the agent, when they are exposed.
+func debugContainerPorts(debug *v1alpha1.DebugSpec) []corev1.ContainerPort {
+	if !debug.IsEnabled() {
+		return nil
+	}
+	pprof, zpages := debugPorts(debug)
+	return []corev1.ContainerPort{
+		{Name: pprofExtension, ContainerPort: pprof, Protocol: corev1.ProtocolTCP},
+		{Name: zPagesExtension, ContainerPort: zpages, Protocol: corev1.ProtocolTCP},
+	}
+}
+
+// DebugService builds the ClusterIP service exposing the debug endpoints of the agent pods to the cluster. It is
+// kept apart from the agent service, so that the serviceType of the agent never exposes them outside the cluster.
+func DebugService(params manifests.Params) (*corev1.Service, error) {
+	if !params.OtelCol.Spec.Debug.IsEnabled() {
+		return nil, nil
+	}
+	name := naming.DebugService(params.OtelCol.ResourceName())
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	var ports []corev1.ServicePort
+	for _, p := range debugContainerPorts(params.OtelCol.Spec.Debug) {
+		ports = append(ports, corev1.ServicePort{
+			Name:       p.Name,
+			Port:       p.ContainerPort,
+			TargetPort: intstr.FromInt32(p.ContainerPort),
+			Protocol:   p.Protocol,
+		})
+	}
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   params.OtelCol.Namespace,
+			Labels:      labels,
+			Annotations: params.OtelCol.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:           corev1.ServiceTypeClusterIP,
+			Selector:       manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, ComponentAmazonCloudWatchAgent),
+			Ports:          ports,
+			IPFamilies:     params.OtelCol.Spec.IPFamilies,
+			IPFamilyPolicy: params.OtelCol.Spec.IPFamilyPolicy,
+		},
+	}, nil
+}
+
+// otelConfigWithDebug adds the pprof and the zpages extensions listening on the debug ports to the given
+// configuration, when the debug spec injects them. Unlike the diagnostics, they listen on all the interfaces of the
+// pod, to be reached through the debug service. The endpoints set in the configuration for these extensions are
+// replaced.
+func otelConfigWithDebug(config map[interface{}]interface{}, debug *v1alpha1.DebugSpec) {
+	if !debug.IsEnabled() || !debug.InjectConfig {
+		return
+	}
+	service, ok := config["service"].(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	pprof, zpages := debugPorts(debug)
+	extensions := childMap(config, "extensions")
+	extensions[pprofExtension] = map[interface{}]interface{}{"endpoint": fmt.Sprintf("0.0.0.0:%d", pprof)}
+	extensions[zPagesExtension] = map[interface{}]interface{}{"endpoint": fmt.Sprintf("0.0.0.0:%d", zpages)}
+	service["extensions"] = appendComponent(appendComponent(service["extensions"], pprofExtension), zPagesExtension)
+}
@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestDebugService(t *testing.T) {
+	params := deploymentParams()
+	params.OtelCol.Spec.ServiceType = corev1.ServiceTypeLoadBalancer
+
+	service, err := DebugService(params)
+	require.NoError(t, err)
+	assert.Nil(t, service, "no service without debug")
+
+	params.OtelCol.Spec.Debug = &v1alpha1.DebugSpec{Enabled: true, ZPagesPort: 55680}
+	service, err = DebugService(params)
+	require.NoError(t, err)
+	assert.Equal(t, "test-debug", service.Name)
+	assert.Equal(t, corev1.ServiceTypeClusterIP, service.Spec.Type)
+	assert.Equal(t, []corev1.ServicePort{
+		{Name: "pprof", Port: 1777, TargetPort: intstr.FromInt32(1777), Protocol: corev1.ProtocolTCP},
+		{Name: "zpages", Port: 55680, TargetPort: intstr.FromInt32(55680), Protocol: corev1.ProtocolTCP},
+	}, service.Spec.Ports)
+
+	// the debug ports are on the agent container, but not on the agent service
+	container := Container(params.Config, logger, params.OtelCol, true)
+	assert.Contains(t, container.Ports, corev1.ContainerPort{Name: "pprof", ContainerPort: 1777, Protocol: corev1.ProtocolTCP})
+	assert.Contains(t, container.Ports, corev1.ContainerPort{Name: "zpages", ContainerPort: 55680, Protocol: corev1.ProtocolTCP})
+	agentService, err := Service(params)
+	require.NoError(t, err)
+	for _, port := range agentService.Spec.Ports {
+		assert.NotContains(t, []string{"pprof", "zpages"}, port.Name)
+	}
+}
+
+func TestOtelConfigWithDebug(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `
+extensions:
+  pprof:
+    endpoint: localhost:1777
+service:
+  extensions: [pprof]
+  pipelines: {}
+`,
+			Debug: &v1alpha1.DebugSpec{Enabled: true, PprofPort: 6060, InjectConfig: true},
+		},
+	}
+
+	replaced, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	config, err := adapters.ConfigFromString(replaced)
+	require.NoError(t, err)
+	extensions := config["extensions"].(map[interface{}]interface{})
+	assert.Equal(t, map[interface{}]interface{}{"endpoint": "0.0.0.0:6060"}, extensions["pprof"])
+	assert.Equal(t, map[interface{}]interface{}{"endpoint": "0.0.0.0:55679"}, extensions["zpages"])
+	assert.Equal(t, []interface{}{"pprof", "zpages"}, config["service"].(map[interface{}]interface{})["extensions"])
+
+	// the config is left untouched unless it is injected
+	agent.Spec.Debug.InjectConfig = false
+	replaced, err = ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	config, err = adapters.ConfigFromString(replaced)
+	require.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{"endpoint": "localhost:1777"}, config["extensions"].(map[interface{}]interface{})["pprof"])
+}
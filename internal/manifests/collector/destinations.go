@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// configWithDestinations renders the destinations of the sections of the given agent config: the region into the
+// agent section, the metrics namespace into the metrics section and the log group into all the collected logs.
+func configWithDestinations(config map[string]interface{}, destinations []v1alpha1.DestinationSpec) {
+	for _, destination := range destinations {
+		if destination.Pipeline != "" {
+			continue
+		}
+		if destination.Region != "" {
+			agent, ok := config["agent"].(map[string]interface{})
+			if !ok {
+				agent = map[string]interface{}{}
+				config["agent"] = agent
+			}
+			agent["region"] = destination.Region
+		}
+		switch destination.Signal {
+		case "metrics":
+			if metrics, ok := config["metrics"].(map[string]interface{}); ok && destination.MetricsNamespace != "" {
+				metrics["namespace"] = destination.MetricsNamespace
+			}
+		case "logs":
+			if destination.LogGroup != "" {
+				for _, entry := range collectedLogs(config) {
+					entry["log_group_name"] = destination.LogGroup
+				}
+			}
+		}
+	}
+}
+
+// otelConfigWithDestinations renders the destinations of the pipelines of the given configuration into their AWS
+// exporters. An exporter shared with other pipelines is copied for the pipeline of the destination, so that the
+// other pipelines keep sending where they did.
+func otelConfigWithDestinations(config map[interface{}]interface{}, destinations []v1alpha1.DestinationSpec) {
+	service, _ := config["service"].(map[interface{}]interface{})
+	pipelines, _ := service["pipelines"].(map[interface{}]interface{})
+	for _, destination := range destinations {
+		pipeline, ok := pipelines[destination.Pipeline].(map[interface{}]interface{})
+		if destination.Pipeline == "" || !ok {
+			continue
+		}
+		ids, _ := pipeline["exporters"].([]interface{})
+		for i, v := range ids {
+			id, ok := v.(string)
+			if !ok {
+				continue
+			}
+			settings := destinationSettings(strings.SplitN(id, "/", 2)[0], destination)
+			if len(settings) == 0 {
+				continue
+			}
+			exporters := childMap(config, "exporters")
+			exporter := map[interface{}]interface{}{}
+			if current, ok := exporters[id].(map[interface{}]interface{}); ok {
+				for k, v := range current {
+					exporter[k] = v
+				}
+			}
+			for k, v := range settings {
+				exporter[k] = v
+			}
+			if exporterShared(pipelines, id, destination.Pipeline) {
+				id = destinationExporterID(id, destination.Pipeline)
+				ids[i] = id
+			}
+			exporters[id] = exporter
+		}
+	}
+}
+
+// destinationSettings returns the settings of the exporter of the given type rendering the destination.
+func destinationSettings(exporterType string, destination v1alpha1.DestinationSpec) map[interface{}]interface{} {
+	settings := map[interface{}]interface{}{}
+	switch exporterType {
+	case awsEMFExporter:
+		if destination.LogGroup != "" {
+			settings["log_group_name"] = destination.LogGroup
+		}
+		if destination.MetricsNamespace != "" {
+			settings["namespace"] = destination.MetricsNamespace
+		}
+	case awsCloudWatchLogsExporter:
+		if destination.LogGroup != "" {
+			settings["log_group_name"] = destination.LogGroup
+		}
+	case awsXRayExporter:
+	default:
+		return nil
+	}
+	if destination.Region != "" {
+		settings["region"] = destination.Region
+	}
+	return settings
+}
+
+// exporterShared returns whether a pipeline other than the given one uses the exporter.
+func exporterShared(pipelines map[interface{}]interface{}, id string, pipelineID string) bool {
+	for k, v := range pipelines {
+		pipeline, _ := v.(map[interface{}]interface{})
+		if k == pipelineID || pipeline == nil {
+			continue
+		}
+		exporters, _ := pipeline["exporters"].([]interface{})
+		for _, exporter := range exporters {
+			if exporter == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// destinationExporterID returns the ID of the copy of the exporter for the pipeline, named after the pipeline.
+func destinationExporterID(id string, pipelineID string) string {
+	name := strings.ReplaceAll(pipelineID, "/", "_")
+	if strings.Contains(id, "/") {
+		return id + "_" + name
+	}
+	return id + "/" + name
+}
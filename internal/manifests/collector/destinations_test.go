@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestDestinations(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{
+				"agent":{"region":"us-west-2"},
+				"metrics":{"namespace":"CWAgent","metrics_collected":{"statsd":{}}},
+				"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app.log"},{"file_path":"/var/log/audit.log","log_group_name":"audit"}]}}}
+			}`,
+			OtelConfig: `
+exporters:
+  awsemf:
+    namespace: App
+  awscloudwatchlogs/app:
+    log_group_name: app
+  debug: {}
+service:
+  pipelines:
+    metrics/team-a:
+      exporters: [awsemf, debug]
+    metrics/team-b:
+      exporters: [awsemf]
+    logs/app:
+      exporters: [awscloudwatchlogs/app]
+`,
+			Destinations: []v1alpha1.DestinationSpec{
+				{Signal: "metrics", Region: "eu-west-1", MetricsNamespace: "Cluster"},
+				{Signal: "logs", LogGroup: "/eks/cluster"},
+				{Signal: "metrics", Pipeline: "metrics/team-b", Region: "us-east-1", MetricsNamespace: "TeamB"},
+				{Signal: "logs", Pipeline: "logs/app", LogGroup: "/eks/app"},
+			},
+		},
+	}
+
+	replaced, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"agent":{"region":"eu-west-1"},
+		"metrics":{"namespace":"Cluster","metrics_collected":{"statsd":{}}},
+		"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app.log","log_group_name":"/eks/cluster"},{"file_path":"/var/log/audit.log","log_group_name":"/eks/cluster"}]}}}
+	}`, replaced)
+
+	replaced, err = ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	config, err := adapters.ConfigFromString(replaced)
+	require.NoError(t, err)
+	exporters := config["exporters"].(map[interface{}]interface{})
+	// the exporter shared with team-a is copied for team-b
+	assert.Equal(t, map[interface{}]interface{}{"namespace": "App"}, exporters["awsemf"])
+	assert.Equal(t, map[interface{}]interface{}{"namespace": "TeamB", "region": "us-east-1"}, exporters["awsemf/metrics_team-b"])
+	assert.Equal(t, map[interface{}]interface{}{"log_group_name": "/eks/app"}, exporters["awscloudwatchlogs/app"])
+	pipelines := config["service"].(map[interface{}]interface{})["pipelines"].(map[interface{}]interface{})
+	assert.Equal(t, []interface{}{"awsemf", "debug"}, pipelines["metrics/team-a"].(map[interface{}]interface{})["exporters"])
+	assert.Equal(t, []interface{}{"awsemf/metrics_team-b"}, pipelines["metrics/team-b"].(map[interface{}]interface{})["exporters"])
+	assert.Equal(t, []interface{}{"awscloudwatchlogs/app"}, pipelines["logs/app"].(map[interface{}]interface{})["exporters"])
+}
+
+func TestDestinationExporterID(t *testing.T) {
+	assert.Equal(t, "awsemf/metrics_app", destinationExporterID("awsemf", "metrics/app"))
+	assert.Equal(t, "awsemf/shared_metrics", destinationExporterID("awsemf/shared", "metrics"))
+}
@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// The diagnostics endpoints listen on localhost only, they are reached with kubectl port-forward.
+const (
+	zPagesExtension    = "zpages"
+	zPagesEndpoint     = "localhost:55679"
+	remoteTapProcessor = "remotetap"
+	remoteTapEndpoint  = "localhost:12001"
+)
+
+// otelConfigWithDiagnostics renders the diagnostics of the instance into the given configuration. The endpoints
+// set in the configuration for these components are replaced, so that they are never exposed outside of the pod.
+func otelConfigWithDiagnostics(config map[interface{}]interface{}, diagnostics *v1alpha1.DiagnosticsSpec) {
+	if diagnostics == nil {
+		return
+	}
+	service, ok := config["service"].(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	if diagnostics.ZPages {
+		extensions := childMap(config, "extensions")
+		extensions[zPagesExtension] = map[interface{}]interface{}{"endpoint": zPagesEndpoint}
+		service["extensions"] = appendComponent(service["extensions"], zPagesExtension)
+	}
+	if diagnostics.Tap {
+		processors := childMap(config, "processors")
+		processors[remoteTapProcessor] = map[interface{}]interface{}{"endpoint": remoteTapEndpoint}
+		pipelines, ok := service["pipelines"].(map[interface{}]interface{})
+		if !ok {
+			return
+		}
+		for _, v := range pipelines {
+			pipeline, ok := v.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			pipeline["processors"] = appendComponent(pipeline["processors"], remoteTapProcessor)
+		}
+	}
+}
+
+// childMap returns the map under the given key of the configuration, creating it if needed.
+func childMap(config map[interface{}]interface{}, key string) map[interface{}]interface{} {
+	child, ok := config[key].(map[interface{}]interface{})
+	if !ok {
+		child = map[interface{}]interface{}{}
+		config[key] = child
+	}
+	return child
+}
+
+// appendComponent appends the given component ID to the list of components, unless it is already in it.
+func appendComponent(list interface{}, id string) []interface{} {
+	components, _ := list.([]interface{})
+	for _, c := range components {
+		if c == id {
+			return components
+		}
+	}
+	return append(components, id)
+}
@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestOtelConfigWithDiagnostics(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `
+extensions:
+  zpages:
+    endpoint: 0.0.0.0:55679
+processors:
+  batch: {}
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      processors: [batch]
+    metrics: {}
+`,
+			Diagnostics: &v1alpha1.DiagnosticsSpec{ZPages: true, Tap: true},
+		},
+	}
+
+	replaced, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+
+	config, err := adapters.ConfigFromString(replaced)
+	require.NoError(t, err)
+	extensions := config["extensions"].(map[interface{}]interface{})
+	assert.Equal(t, map[interface{}]interface{}{"endpoint": "localhost:55679"}, extensions["zpages"])
+	processors := config["processors"].(map[interface{}]interface{})
+	assert.Equal(t, map[interface{}]interface{}{"endpoint": "localhost:12001"}, processors["remotetap"])
+	service := config["service"].(map[interface{}]interface{})
+	assert.Equal(t, []interface{}{"health_check", "zpages"}, service["extensions"])
+	pipelines := service["pipelines"].(map[interface{}]interface{})
+	assert.Equal(t, []interface{}{"batch", "remotetap"}, pipelines["traces"].(map[interface{}]interface{})["processors"])
+	assert.Equal(t, []interface{}{"remotetap"}, pipelines["metrics"].(map[interface{}]interface{})["processors"])
+
+	// rendering is idempotent
+	agent.Spec.OtelConfig = replaced
+	again, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	assert.Equal(t, replaced, again)
+
+	// configs are left untouched without diagnostics
+	agent.Spec.Diagnostics = nil
+	agent.Spec.OtelConfig = "service:\n  pipelines: {}\n"
+	replaced, err = ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	assert.NotContains(t, replaced, "zpages")
+	assert.NotContains(t, replaced, "remotetap")
+}
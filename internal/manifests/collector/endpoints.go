@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// The exporters of the OtelConfig sending to the AWS services.
+const (
+	awsEMFExporter  = "awsemf"
+	awsXRayExporter = "awsxray"
+)
+
+// endpointOverridesEnvVars returns the environment variables pointing the AWS SDKs of the agent to the endpoint
+// overrides.
+func endpointOverridesEnvVars(overrides v1alpha1.AWSEndpointOverridesSpec) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	for _, env := range []corev1.EnvVar{
+		{Name: "AWS_ENDPOINT_URL_CLOUDWATCH", Value: overrides.CloudWatch},
+		{Name: "AWS_ENDPOINT_URL_CLOUDWATCH_LOGS", Value: overrides.Logs},
+		{Name: "AWS_ENDPOINT_URL_XRAY", Value: overrides.XRay},
+		{Name: "AWS_ENDPOINT_URL_STS", Value: overrides.STS},
+	} {
+		if env.Value != "" {
+			envVars = append(envVars, env)
+		}
+	}
+	if overrides.STS != "" || overrides.STSRegionalEndpoints {
+		envVars = append(envVars, corev1.EnvVar{Name: "AWS_STS_REGIONAL_ENDPOINTS", Value: "regional"})
+	}
+	return envVars
+}
+
+// configWithEndpointOverrides renders the endpoint overrides into the metrics, logs and traces sections of the given
+// agent config, overriding the ones set there. Sections missing from the config are left out.
+func configWithEndpointOverrides(config map[string]interface{}, overrides v1alpha1.AWSEndpointOverridesSpec) {
+	for section, endpoint := range map[string]string{
+		"metrics": overrides.CloudWatch,
+		"logs":    overrides.Logs,
+		"traces":  overrides.XRay,
+	} {
+		s, ok := config[section].(map[string]interface{})
+		if !ok || endpoint == "" {
+			continue
+		}
+		s["endpoint_override"] = endpoint
+	}
+}
+
+// otelConfigWithEndpointOverrides renders the endpoint overrides into the AWS exporters of the given configuration,
+// overriding the ones set there.
+func otelConfigWithEndpointOverrides(config map[interface{}]interface{}, overrides v1alpha1.AWSEndpointOverridesSpec) {
+	exporters, ok := config["exporters"].(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	endpoints := map[string]string{
+		awsEMFExporter:            overrides.Logs,
+		awsCloudWatchLogsExporter: overrides.Logs,
+		awsXRayExporter:           overrides.XRay,
+	}
+	for k, v := range exporters {
+		id, ok := k.(string)
+		if !ok {
+			continue
+		}
+		endpoint := endpoints[strings.SplitN(id, "/", 2)[0]]
+		if endpoint == "" {
+			continue
+		}
+		exporter, ok := v.(map[interface{}]interface{})
+		if !ok {
+			exporter = map[interface{}]interface{}{}
+			exporters[k] = exporter
+		}
+		exporter["endpoint"] = endpoint
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestEndpointOverrides(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"metrics":{"endpoint_override":"https://monitoring.us-east-1.amazonaws.com"},"logs":{},"traces":{}}`,
+			OtelConfig: `
+exporters:
+  awsemf/app: {}
+  awsxray:
+    endpoint: https://xray.us-east-1.amazonaws.com
+  debug: {}
+`,
+			AWSEndpointOverrides: v1alpha1.AWSEndpointOverridesSpec{
+				CloudWatch: "https://monitoring.us-gov-west-1.amazonaws.com",
+				Logs:       "https://logs.us-gov-west-1.amazonaws.com",
+				XRay:       "https://xray.us-gov-west-1.amazonaws.com",
+				STS:        "https://sts.us-gov-west-1.amazonaws.com",
+			},
+			XRay: &v1alpha1.XRaySpec{EndpointOverride: "https://vpce-xray.example.com"},
+		},
+	}
+
+	replaced, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"metrics":{"endpoint_override":"https://monitoring.us-gov-west-1.amazonaws.com"},
+		"logs":{"endpoint_override":"https://logs.us-gov-west-1.amazonaws.com"},
+		"traces":{"endpoint_override":"https://vpce-xray.example.com"}
+	}`, replaced)
+
+	replaced, err = ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	config, err := adapters.ConfigFromString(replaced)
+	require.NoError(t, err)
+	exporters := config["exporters"].(map[interface{}]interface{})
+	assert.Equal(t, map[interface{}]interface{}{"endpoint": "https://logs.us-gov-west-1.amazonaws.com"}, exporters["awsemf/app"])
+	assert.Equal(t, map[interface{}]interface{}{"endpoint": "https://xray.us-gov-west-1.amazonaws.com"}, exporters["awsxray"])
+	assert.Equal(t, map[interface{}]interface{}{}, exporters["debug"])
+
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "AWS_ENDPOINT_URL_CLOUDWATCH", Value: "https://monitoring.us-gov-west-1.amazonaws.com"},
+		{Name: "AWS_ENDPOINT_URL_CLOUDWATCH_LOGS", Value: "https://logs.us-gov-west-1.amazonaws.com"},
+		{Name: "AWS_ENDPOINT_URL_XRAY", Value: "https://xray.us-gov-west-1.amazonaws.com"},
+		{Name: "AWS_ENDPOINT_URL_STS", Value: "https://sts.us-gov-west-1.amazonaws.com"},
+		{Name: "AWS_STS_REGIONAL_ENDPOINTS", Value: "regional"},
+	}, endpointOverridesEnvVars(agent.Spec.AWSEndpointOverrides))
+	assert.Equal(t, []corev1.EnvVar{{Name: "AWS_STS_REGIONAL_ENDPOINTS", Value: "regional"}},
+		endpointOverridesEnvVars(v1alpha1.AWSEndpointOverridesSpec{STSRegionalEndpoints: true}))
+	assert.Empty(t, endpointOverridesEnvVars(v1alpha1.AWSEndpointOverridesSpec{}))
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// extraMountVolumes returns a volume for each of the extra mounts of the given instance.
+func extraMountVolumes(agent v1alpha1.AmazonCloudWatchAgent) []corev1.Volume {
+	var volumes []corev1.Volume
+	for i, m := range agent.Spec.ExtraMounts {
+		var source corev1.VolumeSource
+		if m.Secret != "" {
+			source.Secret = &corev1.SecretVolumeSource{SecretName: referenceName(agent, m.Namespace, m.Secret), Items: m.Items}
+		} else {
+			source.ConfigMap = &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: referenceName(agent, m.Namespace, m.ConfigMap)},
+				Items:                m.Items,
+			}
+		}
+		volumes = append(volumes, corev1.Volume{Name: naming.ExtraMountVolume(i), VolumeSource: source})
+	}
+	return volumes
+}
+
+// extraMountVolumeMounts returns the mounts of the volumes returned by extraMountVolumes.
+func extraMountVolumeMounts(agent v1alpha1.AmazonCloudWatchAgent) []corev1.VolumeMount {
+	var volumeMounts []corev1.VolumeMount
+	for i, m := range agent.Spec.ExtraMounts {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      naming.ExtraMountVolume(i),
+			MountPath: m.MountPath,
+			SubPath:   m.SubPath,
+			ReadOnly:  true,
+		})
+	}
+	return volumeMounts
+}
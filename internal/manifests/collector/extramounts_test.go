@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestExtraMounts(t *testing.T) {
+	items := []corev1.KeyToPath{{Key: "ca.crt", Path: "bundle.pem"}}
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ExtraMounts: []v1alpha1.ExtraMountSpec{
+				{ConfigMap: "ca", Items: items, MountPath: "/etc/ssl/custom"},
+				{Secret: "credentials", MountPath: "/root/.aws/credentials", SubPath: "credentials"},
+			},
+		},
+	}
+
+	volumes := Volumes(config.New(), agent)
+	assert.Contains(t, volumes, corev1.Volume{
+		Name: "extra-mount-0",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "ca"},
+			Items:                items,
+		}},
+	})
+	assert.Contains(t, volumes, corev1.Volume{
+		Name:         "extra-mount-1",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "credentials"}},
+	})
+
+	c := Container(config.New(), logr.Discard(), agent, true)
+	assert.Contains(t, c.VolumeMounts, corev1.VolumeMount{Name: "extra-mount-0", MountPath: "/etc/ssl/custom", ReadOnly: true})
+	assert.Contains(t, c.VolumeMounts, corev1.VolumeMount{Name: "extra-mount-1", MountPath: "/root/.aws/credentials", SubPath: "credentials", ReadOnly: true})
+
+	agent.Spec.ExtraMounts = nil
+	assert.Empty(t, extraMountVolumes(agent))
+	assert.Empty(t, extraMountVolumeMounts(agent))
+}
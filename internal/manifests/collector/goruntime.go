@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// goRuntimeEnvVars returns the GOMAXPROCS and GOMEMLIMIT environment variables of the agent. Without them, the Go
+// runtime of the agent sizes itself after the node rather than after the limits of its container: it runs as many
+// threads as the node has CPUs, which the CPU quota throttles, and only collects its garbage once the heap doubles,
+// past the memory limit. They default to the CPU limit, rounded up, and to a share of the memory limit, and are left
+// to the runtime when the container has no limit.
+func goRuntimeEnvVars(agent v1alpha1.AmazonCloudWatchAgent) []corev1.EnvVar {
+	goRuntime := agent.Spec.GoRuntime
+	if goRuntime == nil {
+		goRuntime = &v1alpha1.GoRuntimeSpec{}
+	}
+	var envVars []corev1.EnvVar
+
+	if goRuntime.MaxProcs != nil {
+		envVars = append(envVars, corev1.EnvVar{Name: "GOMAXPROCS", Value: strconv.Itoa(int(*goRuntime.MaxProcs))})
+	} else if cpu, ok := agent.Spec.Resources.Limits[corev1.ResourceCPU]; ok && cpu.Sign() > 0 {
+		maxProcs := (cpu.MilliValue() + 999) / 1000
+		envVars = append(envVars, corev1.EnvVar{Name: "GOMAXPROCS", Value: strconv.FormatInt(maxProcs, 10)})
+	}
+
+	if goRuntime.MemoryLimit != nil {
+		envVars = append(envVars, corev1.EnvVar{Name: "GOMEMLIMIT", Value: strconv.FormatInt(goRuntime.MemoryLimit.Value(), 10)})
+	} else if memory, ok := agent.Spec.Resources.Limits[corev1.ResourceMemory]; ok && memory.Sign() > 0 {
+		percent := v1alpha1.DefaultGoMemoryLimitPercent
+		if goRuntime.MemoryLimitPercent != nil {
+			percent = *goRuntime.MemoryLimitPercent
+		}
+		envVars = append(envVars, corev1.EnvVar{Name: "GOMEMLIMIT", Value: strconv.FormatInt(memory.Value()*int64(percent)/100, 10)})
+	}
+	return envVars
+}
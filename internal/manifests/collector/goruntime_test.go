@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestGoRuntimeEnvVars(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	memoryLimit := resource.MustParse("1Gi")
+	limits := corev1.ResourceRequirements{Limits: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1500m"),
+		corev1.ResourceMemory: resource.MustParse("500Mi"),
+	}}
+
+	for _, tt := range []struct {
+		name      string
+		resources corev1.ResourceRequirements
+		goRuntime *v1alpha1.GoRuntimeSpec
+		expected  []corev1.EnvVar
+	}{
+		{
+			name: "no limits",
+		},
+		{
+			name:      "derived from the limits",
+			resources: limits,
+			expected: []corev1.EnvVar{
+				{Name: "GOMAXPROCS", Value: "2"},
+				{Name: "GOMEMLIMIT", Value: "471859200"},
+			},
+		},
+		{
+			name: "less than a CPU",
+			resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("200m"),
+			}},
+			expected: []corev1.EnvVar{{Name: "GOMAXPROCS", Value: "1"}},
+		},
+		{
+			name:      "percentage of the memory limit",
+			resources: limits,
+			goRuntime: &v1alpha1.GoRuntimeSpec{MemoryLimitPercent: int32Ptr(50)},
+			expected: []corev1.EnvVar{
+				{Name: "GOMAXPROCS", Value: "2"},
+				{Name: "GOMEMLIMIT", Value: "262144000"},
+			},
+		},
+		{
+			name:      "overridden",
+			resources: limits,
+			goRuntime: &v1alpha1.GoRuntimeSpec{MaxProcs: int32Ptr(4), MemoryLimit: &memoryLimit},
+			expected: []corev1.EnvVar{
+				{Name: "GOMAXPROCS", Value: "4"},
+				{Name: "GOMEMLIMIT", Value: "1073741824"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := v1alpha1.AmazonCloudWatchAgent{Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Resources: tt.resources,
+				GoRuntime: tt.goRuntime,
+			}}
+			assert.Equal(t, tt.expected, goRuntimeEnvVars(agent))
+		})
+	}
+}
+
+func TestGoRuntimeEnvVarsInjection(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+		Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		}},
+		Env:               []corev1.EnvVar{{Name: "GOMAXPROCS", Value: "8"}},
+		InjectedEnvPolicy: v1alpha1.InjectedEnvPolicy{Disabled: []string{"GOMEMLIMIT"}},
+	}}
+
+	// the variables of the users take precedence, and the injection can be disabled
+	container := Container(config.New(), logger, agent, true)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "GOMAXPROCS", Value: "8"})
+	assert.NotContains(t, container.Env, corev1.EnvVar{Name: "GOMAXPROCS", Value: "2"})
+	for _, env := range container.Env {
+		assert.NotEqual(t, "GOMEMLIMIT", env.Name)
+	}
+}
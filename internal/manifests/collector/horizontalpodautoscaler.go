@@ -16,13 +16,13 @@ import (
 )
 
 func HorizontalPodAutoscaler(params manifests.Params) client.Object {
-	name := naming.Collector(params.OtelCol.Name)
+	name := naming.Collector(params.OtelCol.ResourceName())
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter())
-	annotations := Annotations(params.OtelCol)
+	annotations := Annotations(params.Config, params.OtelCol)
 	var result client.Object
 
 	objectMeta := metav1.ObjectMeta{
-		Name:        naming.HorizontalPodAutoscaler(params.OtelCol.Name),
+		Name:        naming.HorizontalPodAutoscaler(params.OtelCol.ResourceName()),
 		Namespace:   params.OtelCol.Namespace,
 		Labels:      labels,
 		Annotations: annotations,
@@ -38,6 +38,11 @@ func HorizontalPodAutoscaler(params manifests.Params) client.Object {
 		params.OtelCol.Spec.Autoscaler.MaxReplicas = params.OtelCol.Spec.MaxReplicas
 	}
 
+	// the autoscaler may only define the vertical autoscaling
+	if params.OtelCol.Spec.Autoscaler.MaxReplicas == nil {
+		return nil
+	}
+
 	if params.OtelCol.Spec.Autoscaler.MinReplicas == nil {
 		if params.OtelCol.Spec.MinReplicas != nil {
 			params.OtelCol.Spec.Autoscaler.MinReplicas = params.OtelCol.Spec.MinReplicas
@@ -91,5 +91,21 @@ func TestHPA(t *testing.T) {
 			})
 		}
 	}
+}
+
+func TestHPAScaleTargetWithNameOverride(t *testing.T) {
+	maxReplicas := int32(5)
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-instance"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			NameOverride: "agent",
+			Autoscaler:   &v1alpha1.AutoscalerSpec{MaxReplicas: &maxReplicas},
+		},
+	}
+	hpa := HorizontalPodAutoscaler(manifests.Params{Config: config.New(), OtelCol: otelcol, Log: logger}).(*autoscalingv2.HorizontalPodAutoscaler)
 
+	// the HPA is named after the name override, and scales the AmazonCloudWatchAgent itself
+	assert.Equal(t, "agent", hpa.Name)
+	assert.Equal(t, "AmazonCloudWatchAgent", hpa.Spec.ScaleTargetRef.Kind)
+	assert.Equal(t, "my-instance", hpa.Spec.ScaleTargetRef.Name)
 }
@@ -36,18 +36,18 @@ func Ingress(params manifests.Params) (*networkingv1.Ingress, error) {
 	var rules []networkingv1.IngressRule
 	switch params.OtelCol.Spec.Ingress.RuleType {
 	case v1alpha1.IngressRuleTypePath, "":
-		rules = []networkingv1.IngressRule{createPathIngressRules(params.OtelCol.Name, params.OtelCol.Spec.Ingress.Hostname, ports)}
+		rules = []networkingv1.IngressRule{createPathIngressRules(params.OtelCol.ResourceName(), params.OtelCol.Spec.Ingress.Hostname, ports)}
 	case v1alpha1.IngressRuleTypeSubdomain:
-		rules = createSubdomainIngressRules(params.OtelCol.Name, params.OtelCol.Spec.Ingress.Hostname, ports)
+		rules = createSubdomainIngressRules(params.OtelCol.ResourceName(), params.OtelCol.Spec.Ingress.Hostname, ports)
 	}
 
 	return &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        naming.Ingress(params.OtelCol.Name),
+			Name:        naming.Ingress(params.OtelCol.ResourceName()),
 			Namespace:   params.OtelCol.Namespace,
 			Annotations: params.OtelCol.Spec.Ingress.Annotations,
 			Labels: map[string]string{
-				"app.kubernetes.io/name":       naming.Ingress(params.OtelCol.Name),
+				"app.kubernetes.io/name":       naming.Ingress(params.OtelCol.ResourceName()),
 				"app.kubernetes.io/instance":   fmt.Sprintf("%s.%s", params.OtelCol.Namespace, params.OtelCol.Name),
 				"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 			},
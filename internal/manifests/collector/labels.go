@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// legacyNameLabel is the label the agent pods were selected by in the manifests the agent was deployed with before
+// the operator.
+const legacyNameLabel = "name"
+
+// podLabels returns the labels of the agent pods: the given labels, the hash of the config the pods are started with,
+// and the legacy labels when the CR selects the Legacy labels policy. The given labels are left untouched.
+func podLabels(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent, labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+2)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[constants.LabelConfigHash] = ConfigHash(cfg, otelcol)
+	if otelcol.Spec.LabelsPolicy != v1alpha1.LabelsPolicyLegacy {
+		return result
+	}
+	if _, ok := result[legacyNameLabel]; !ok {
+		result[legacyNameLabel] = otelcol.Name
+	}
+	return result
+}
+
+// ConfigHash returns the hash of the config the agent pods of the instance are started with, which the pods are
+// labeled with to tell the ones running a stale config apart during a rollout. It is the prefix of the hash
+// annotation rolling the pods out, label values being limited to 63 characters.
+func ConfigHash(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) string {
+	return getConfigMapSHA(restartRequiredConfig(cfg, otelcol))[:16]
+}
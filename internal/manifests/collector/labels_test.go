@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func labelsParams(policy v1alpha1.LabelsPolicy) manifests.Params {
+	return manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:         v1alpha1.ModeDaemonSet,
+				Image:        "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:1.300040.0b650",
+				LabelsPolicy: policy,
+			},
+		},
+	}
+}
+
+// TestStableLabels guards the labels and the container name of the agent pods, which the recording rules and the
+// dashboards of the users select the agents by. They must not change across operator versions.
+func TestStableLabels(t *testing.T) {
+	ds := DaemonSet(labelsParams(""))
+
+	assert.Equal(t, map[string]string{
+		"app.kubernetes.io/component":           "amazon-cloudwatch-agent",
+		"app.kubernetes.io/instance":            "amazon-cloudwatch.cloudwatch-agent",
+		"app.kubernetes.io/managed-by":          "amazon-cloudwatch-agent-operator",
+		"app.kubernetes.io/name":                "cloudwatch-agent",
+		"app.kubernetes.io/part-of":             "amazon-cloudwatch-agent",
+		"app.kubernetes.io/version":             "1.300040.0b650",
+		"cloudwatch.aws.amazon.com/config-hash": "e3b0c44298fc1c14",
+	}, ds.Spec.Template.Labels)
+	assert.Equal(t, map[string]string{
+		"app.kubernetes.io/component":  "amazon-cloudwatch-agent",
+		"app.kubernetes.io/instance":   "amazon-cloudwatch.cloudwatch-agent",
+		"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+		"app.kubernetes.io/part-of":    "amazon-cloudwatch-agent",
+	}, ds.Spec.Selector.MatchLabels)
+	assert.Equal(t, "otc-container", ds.Spec.Template.Spec.Containers[0].Name)
+}
+
+func TestLegacyLabels(t *testing.T) {
+	ds := DaemonSet(labelsParams(v1alpha1.LabelsPolicyLegacy))
+
+	assert.Equal(t, "cloudwatch-agent", ds.Spec.Template.Labels["name"])
+	assert.Equal(t, "amazon-cloudwatch-agent", ds.Spec.Template.Labels["app.kubernetes.io/component"])
+	// the selector is immutable, the legacy labels are only put on the pods
+	assert.NotContains(t, ds.Spec.Selector.MatchLabels, "name")
+	assert.NotContains(t, ds.Labels, "name")
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// logDirectories returns the host directories containing the files collected through the logs.logs_collected.files
+// section of the agent config. Only DaemonSet agents read log files from the host, and directories already mounted
+// through the spec's volumeMounts are left to the user. The file paths whose directory is too close to the root of
+// the host, such as /*.log, aren't mounted.
+func logDirectories(agent v1alpha1.AmazonCloudWatchAgent) []string {
+	if agent.Spec.Mode != v1alpha1.ModeDaemonSet || agent.Spec.Config == "" {
+		return nil
+	}
+	config, err := adapters.ConfigStructFromJSONString(agent.Spec.Config)
+	if err != nil || config == nil {
+		return nil
+	}
+
+	var mounted []string
+	for _, volumeMount := range agent.Spec.VolumeMounts {
+		mounted = append(mounted, volumeMount.MountPath)
+	}
+
+	var directories []string
+	for _, filePath := range config.GetLogFilePaths() {
+		dir := adapters.LogDirectory(filePath)
+		if dir == "" || isWithin(dir, mounted) || isWithin(dir, directories) {
+			continue
+		}
+		// drop the directories nested in the new one, the new mount covers them
+		var kept []string
+		for _, d := range directories {
+			if !isWithin(d, []string{dir}) {
+				kept = append(kept, d)
+			}
+		}
+		directories = append(kept, dir)
+	}
+	sort.Strings(directories)
+	return directories
+}
+
+// isWithin returns whether dir is one of the given directories or one of their subdirectories.
+func isWithin(dir string, directories []string) bool {
+	for _, d := range directories {
+		if dir == d || d == "/" || strings.HasPrefix(dir, strings.TrimSuffix(d, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// logFileVolumes returns the hostPath volumes for the directories returned by logDirectories.
+func logFileVolumes(agent v1alpha1.AmazonCloudWatchAgent) []corev1.Volume {
+	var volumes []corev1.Volume
+	for i, dir := range logDirectories(agent) {
+		volumes = append(volumes, corev1.Volume{
+			Name: naming.LogFileVolume(i),
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: dir,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// logFileVolumeMounts returns the mounts of the volumes returned by logFileVolumes.
+func logFileVolumeMounts(agent v1alpha1.AmazonCloudWatchAgent) []corev1.VolumeMount {
+	readOnly := true
+	if agent.Spec.LogVolumes.ReadOnly != nil {
+		readOnly = *agent.Spec.LogVolumes.ReadOnly
+	}
+
+	var volumeMounts []corev1.VolumeMount
+	for i, dir := range logDirectories(agent) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      naming.LogFileVolume(i),
+			MountPath: dir,
+			ReadOnly:  readOnly,
+		})
+	}
+	return volumeMounts
+}
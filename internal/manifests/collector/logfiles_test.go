@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const logFilesConfig = `{
+  "logs": {
+    "logs_collected": {
+      "files": {
+        "collect_list": [
+          {"file_path": "/var/log/app/*.log"},
+          {"file_path": "/var/log/app/nested/app.log"},
+          {"file_path": "/opt/app/**/logs/*.log"},
+          {"file_path": "/data/app/app.log"},
+          {"file_path": "/*.log"},
+          {"file_path": "/{a,b}/x.log"},
+          {"file_path": "/**/app.log"},
+          {"file_path": "/data/app.log"},
+          {"file_path": "C:\\ProgramData\\app.log"}
+        ]
+      }
+    }
+  }
+}`
+
+func TestLogDirectories(t *testing.T) {
+	tests := []struct {
+		desc         string
+		mode         v1alpha1.Mode
+		volumeMounts []corev1.VolumeMount
+		expected     []string
+	}{
+		{
+			desc:     "daemonset",
+			mode:     v1alpha1.ModeDaemonSet,
+			expected: []string{"/data/app", "/opt/app", "/var/log/app"},
+		},
+		{
+			desc:         "already mounted",
+			mode:         v1alpha1.ModeDaemonSet,
+			volumeMounts: []corev1.VolumeMount{{Name: "varlog", MountPath: "/var/log"}},
+			expected:     []string{"/data/app", "/opt/app"},
+		},
+		{
+			desc:     "deployment",
+			mode:     v1alpha1.ModeDeployment,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			agent := v1alpha1.AmazonCloudWatchAgent{
+				Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+					Mode:         tt.mode,
+					Config:       logFilesConfig,
+					VolumeMounts: tt.volumeMounts,
+				},
+			}
+			assert.Equal(t, tt.expected, logDirectories(agent))
+		})
+	}
+}
+
+func TestLogFileVolumes(t *testing.T) {
+	readOnly := false
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:   v1alpha1.ModeDaemonSet,
+			Config: `{"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app/*.log"}]}}}}`,
+		},
+	}
+
+	volumes := logFileVolumes(agent)
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, naming.LogFileVolume(0), volumes[0].Name)
+	assert.Equal(t, "/var/log/app", volumes[0].HostPath.Path)
+
+	volumeMounts := logFileVolumeMounts(agent)
+	assert.Len(t, volumeMounts, 1)
+	assert.Equal(t, naming.LogFileVolume(0), volumeMounts[0].Name)
+	assert.Equal(t, "/var/log/app", volumeMounts[0].MountPath)
+	assert.True(t, volumeMounts[0].ReadOnly)
+
+	agent.Spec.LogVolumes.ReadOnly = &readOnly
+	volumeMounts = logFileVolumeMounts(agent)
+	assert.False(t, volumeMounts[0].ReadOnly)
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+const awsCloudWatchLogsExporter = "awscloudwatchlogs"
+
+// Variables of the log group name template, in the syntax of the config variables.
+const (
+	logGroupVariableCluster   = "${cluster_name}"
+	logGroupVariableNamespace = "${namespace}"
+	logGroupVariablePod       = "${pod}"
+)
+
+// logGroupClusterName returns the value of the ${cluster_name} variable of the log group name template, or an empty
+// string if neither the logs spec nor the agent config defines the cluster name. WithConfigVariables defaults the
+// cluster name of the logs spec to the one of the operator.
+func logGroupClusterName(instance v1alpha1.AmazonCloudWatchAgent) string {
+	if instance.Spec.Logs != nil && instance.Spec.Logs.ClusterName != "" {
+		return instance.Spec.Logs.ClusterName
+	}
+	cwaConfig, err := adapters.ConfigStructFromJSONString(instance.Spec.Config)
+	if err != nil || cwaConfig == nil {
+		return ""
+	}
+	return cwaConfig.GetClusterName()
+}
+
+// logGroupName expands the log group name template of the instance, rendering {pod} with the given expression
+// for the pod's hostname.
+func logGroupName(instance v1alpha1.AmazonCloudWatchAgent, hostname string) string {
+	return strings.NewReplacer(
+		logGroupVariableCluster, logGroupClusterName(instance),
+		logGroupVariableNamespace, instance.Namespace,
+		logGroupVariablePod, hostname,
+	).Replace(instance.Spec.Logs.GroupNameTemplate)
+}
+
+// configWithLogGroupName sets the log group name of the logs.logs_collected entries of the given agent config
+// which don't set one. The agent expands the {hostname} placeholder itself.
+func configWithLogGroupName(config map[string]interface{}, instance v1alpha1.AmazonCloudWatchAgent) {
+	if instance.Spec.Logs == nil {
+		return
+	}
+	configWithDefaultLogGroupName(config, logGroupName(instance, "{hostname}"))
+}
+
+// configWithDefaultLogGroupName sets the given log group name on the logs.logs_collected entries of the given agent
+// config which don't set one.
+func configWithDefaultLogGroupName(config map[string]interface{}, name string) {
+	for _, entry := range collectedLogs(config) {
+		if _, ok := entry["log_group_name"]; !ok {
+			entry["log_group_name"] = name
+		}
+	}
+}
+
+// collectedLogs returns the entries of the collected log files and Windows events of the given agent config.
+func collectedLogs(config map[string]interface{}) []map[string]interface{} {
+	logs, ok := config["logs"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	logsCollected, ok := logs["logs_collected"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var entries []map[string]interface{}
+	for _, section := range []string{"files", "windows_events"} {
+		s, ok := logsCollected[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		collectList, ok := s["collect_list"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, v := range collectList {
+			if entry, ok := v.(map[string]interface{}); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+// otelConfigWithLogGroupName sets the log group name of the awscloudwatchlogs exporters of the given
+// configuration which don't set one. The collector expands the HOSTNAME environment variable.
+func otelConfigWithLogGroupName(config map[interface{}]interface{}, instance v1alpha1.AmazonCloudWatchAgent) {
+	if instance.Spec.Logs == nil {
+		return
+	}
+	exporters, ok := config["exporters"].(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	name := logGroupName(instance, "${env:HOSTNAME}")
+	for k, v := range exporters {
+		id, ok := k.(string)
+		if !ok || (id != awsCloudWatchLogsExporter && !strings.HasPrefix(id, awsCloudWatchLogsExporter+"/")) {
+			continue
+		}
+		exporter, ok := v.(map[interface{}]interface{})
+		if !ok {
+			exporter = map[interface{}]interface{}{}
+			exporters[k] = exporter
+		}
+		if _, ok := exporter["log_group_name"]; !ok {
+			exporter["log_group_name"] = name
+		}
+	}
+}
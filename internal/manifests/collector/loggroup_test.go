@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestConfigWithLogGroupName(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"from-config"}},"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/a.log"},{"file_path":"/var/log/b.log","log_group_name":"custom"}]},"windows_events":{"collect_list":[{"event_name":"System"}]}}}}`,
+			Logs:   &v1alpha1.LogsSpec{GroupNameTemplate: "/aws/${cluster_name}/${namespace}/${pod}"},
+		},
+	}
+
+	replaced, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+
+	cwaConfig, err := adapters.ConfigFromJSONString(replaced)
+	require.NoError(t, err)
+	logsCollected := cwaConfig["logs"].(map[string]interface{})["logs_collected"].(map[string]interface{})
+	files := logsCollected["files"].(map[string]interface{})["collect_list"].([]interface{})
+	assert.Equal(t, "/aws/from-config/amazon-cloudwatch/{hostname}", files[0].(map[string]interface{})["log_group_name"])
+	assert.Equal(t, "custom", files[1].(map[string]interface{})["log_group_name"])
+	events := logsCollected["windows_events"].(map[string]interface{})["collect_list"].([]interface{})
+	assert.Equal(t, "/aws/from-config/amazon-cloudwatch/{hostname}", events[0].(map[string]interface{})["log_group_name"])
+
+	// the cluster name of the logs spec takes precedence over the one of the config
+	agent.Spec.Logs.ClusterName = "from-spec"
+	replaced, err = ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.Contains(t, replaced, "/aws/from-spec/amazon-cloudwatch/{hostname}")
+
+	// configs are left untouched without a logs spec
+	agent.Spec.Logs = nil
+	replaced, err = ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.NotContains(t, replaced, "{hostname}")
+}
+
+func TestOtelConfigWithLogGroupName(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `
+exporters:
+  awscloudwatchlogs:
+  awscloudwatchlogs/custom:
+    log_group_name: custom
+  debug: {}
+`,
+			Logs: &v1alpha1.LogsSpec{GroupNameTemplate: "/aws/${cluster_name}/${namespace}/${pod}", ClusterName: "cluster"},
+		},
+	}
+
+	replaced, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+
+	config, err := adapters.ConfigFromString(replaced)
+	require.NoError(t, err)
+	exporters := config["exporters"].(map[interface{}]interface{})
+	assert.Equal(t, "/aws/cluster/amazon-cloudwatch/${env:HOSTNAME}", exporters["awscloudwatchlogs"].(map[interface{}]interface{})["log_group_name"])
+	assert.Equal(t, "custom", exporters["awscloudwatchlogs/custom"].(map[interface{}]interface{})["log_group_name"])
+	assert.NotContains(t, exporters["debug"], "log_group_name")
+}
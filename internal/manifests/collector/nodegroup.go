@@ -0,0 +1,162 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/collector/confmap"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// nodeGroup is a node group or a node config override of an instance, running in a daemonset of its own.
+type nodeGroup struct {
+	name string
+	// requirements select the nodes of the group, all of them must match.
+	requirements []corev1.NodeSelectorRequirement
+	config       string
+}
+
+// nodeGroups returns the node groups of the instance followed by its node config overrides, in the order the
+// nodes are assigned to them.
+func nodeGroups(agent v1alpha1.AmazonCloudWatchAgent) []nodeGroup {
+	var groups []nodeGroup
+	for _, group := range agent.Spec.NodeGroups {
+		groups = append(groups, nodeGroup{
+			name:         group.Name,
+			requirements: []corev1.NodeSelectorRequirement{nodeGroupRequirement(group, corev1.NodeSelectorOpIn)},
+			config:       group.Config,
+		})
+	}
+	for _, override := range agent.Spec.NodeConfigOverrides {
+		keys := make([]string, 0, len(override.NodeSelector))
+		for key := range override.NodeSelector {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		group := nodeGroup{name: override.Name, config: override.Config}
+		for _, key := range keys {
+			group.requirements = append(group.requirements, corev1.NodeSelectorRequirement{
+				Key:      key,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{override.NodeSelector[key]},
+			})
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// NodeGroups builds the daemonset and the config map of each node group and node config override of the instance.
+// The daemonset of the instance avoids their nodes.
+func NodeGroups(ctx context.Context, params manifests.Params) ([]client.Object, error) {
+	if params.OtelCol.Spec.Mode != v1alpha1.ModeDaemonSet {
+		return nil, nil
+	}
+
+	groups := nodeGroups(params.OtelCol)
+	var objects []client.Object
+	for i, group := range groups {
+		config, err := nodeGroupConfig(params.OtelCol.Spec.Config, group.config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge the config of node group %s: %w", group.name, err)
+		}
+		groupParams := params
+		groupParams.OtelCol = *params.OtelCol.DeepCopy()
+		groupParams.OtelCol.Spec.Config = config
+		groupParams.OtelCol.Spec.NodeGroups = nil
+		groupParams.OtelCol.Spec.NodeConfigOverrides = nil
+
+		configMaps, err := ConfigMaps(ctx, groupParams)
+		if err != nil {
+			return nil, err
+		}
+		// the other config maps, such as the prometheus one, are shared with the daemonset of the instance
+		cm := configMaps[0]
+		cm.Name = naming.NodeGroupConfigMap(params.OtelCol.ResourceName(), group.name)
+		cm.Labels[constants.LabelNodeGroup] = group.name
+
+		ds := DaemonSet(groupParams)
+		ds.Name = naming.NodeGroupCollector(params.OtelCol.ResourceName(), group.name)
+		ds.Labels[constants.LabelNodeGroup] = group.name
+		ds.Spec.Selector.MatchLabels[constants.LabelNodeGroup] = group.name
+		ds.Spec.Template.Labels[constants.LabelNodeGroup] = group.name
+		for j := range ds.Spec.Template.Spec.Volumes {
+			if v := &ds.Spec.Template.Spec.Volumes[j]; v.Name == naming.ConfigMapVolume() {
+				v.ConfigMap.Name = cm.Name
+			}
+		}
+		affinity := ds.Spec.Template.Spec.Affinity
+		for _, requirement := range group.requirements {
+			affinity = withNodeRequirement(affinity, requirement)
+		}
+		// a node belonging to several groups belongs to the first of them
+		for _, previous := range groups[:i] {
+			affinity = withoutNodes(affinity, previous.requirements)
+		}
+		ds.Spec.Template.Spec.Affinity = affinity
+
+		objects = append(objects, cm, ds)
+	}
+	return objects, nil
+}
+
+// nodeGroupRequirement returns the requirement selecting, or avoiding, the nodes of the given group.
+func nodeGroupRequirement(group v1alpha1.NodeGroupSpec, operator corev1.NodeSelectorOperator) corev1.NodeSelectorRequirement {
+	return corev1.NodeSelectorRequirement{
+		Key:      group.NodeLabel.Key,
+		Operator: operator,
+		Values:   []string{group.NodeLabel.Value},
+	}
+}
+
+// withoutNodes returns a copy of the affinity avoiding the nodes matching all the given In requirements. A node
+// avoids them by failing any one of them, and the terms are ORed, so each term is repeated for each requirement.
+func withoutNodes(affinity *corev1.Affinity, requirements []corev1.NodeSelectorRequirement) *corev1.Affinity {
+	var (
+		result *corev1.Affinity
+		terms  []corev1.NodeSelectorTerm
+	)
+	for _, requirement := range requirements {
+		requirement.Operator = corev1.NodeSelectorOpNotIn
+		result = withNodeRequirement(affinity, requirement)
+		terms = append(terms, result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms...)
+	}
+	if result == nil {
+		return affinity
+	}
+	result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = terms
+	return result
+}
+
+// nodeGroupConfig merges the config fragment of a node group into the agent config.
+func nodeGroupConfig(config, fragment string) (string, error) {
+	base, err := adapters.ConfigFromJSONString(config)
+	if err != nil {
+		return "", err
+	}
+	overrides, err := adapters.ConfigFromJSONString(fragment)
+	if err != nil {
+		return "", err
+	}
+	conf := confmap.NewFromStringMap(base)
+	if err := conf.Merge(confmap.NewFromStringMap(overrides)); err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(conf.ToStringMap())
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,138 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestNodeGroups(t *testing.T) {
+	gpu := v1alpha1.NodeGroupSpec{
+		Name:      "gpu",
+		NodeLabel: v1alpha1.NodeLabel{Key: "nvidia.com/gpu.present", Value: "true"},
+		Config:    `{"logs":{"metrics_collected":{"kubernetes":{"accelerated_compute_metrics":true}}}}`,
+	}
+	neuron := v1alpha1.NodeGroupSpec{
+		Name:      "neuron",
+		NodeLabel: v1alpha1.NodeLabel{Key: "aws.amazon.com/neuron.present", Value: "true"},
+		Config:    `{"agent":{"debug":true}}`,
+	}
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:       v1alpha1.ModeDaemonSet,
+				Config:     `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"cluster"}}}}`,
+				NodeGroups: []v1alpha1.NodeGroupSpec{gpu, neuron},
+			},
+		},
+	}
+
+	notGPU := corev1.NodeSelectorRequirement{Key: "nvidia.com/gpu.present", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}}
+	notNeuron := corev1.NodeSelectorRequirement{Key: "aws.amazon.com/neuron.present", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}}
+	ds := DaemonSet(params)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{notGPU, notNeuron},
+		ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+
+	objects, err := NodeGroups(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, objects, 4)
+
+	cm := objects[0].(*corev1.ConfigMap)
+	assert.Equal(t, "agent-gpu", cm.Name)
+	assert.Equal(t, "gpu", cm.Labels[constants.LabelNodeGroup])
+	assert.JSONEq(t, `{"logs":{"metrics_collected":{"kubernetes":{"cluster_name":"cluster","accelerated_compute_metrics":true}}}}`, cm.Data["cwagentconfig.json"])
+
+	gpuDS := objects[1].(*appsv1.DaemonSet)
+	assert.Equal(t, "agent-gpu", gpuDS.Name)
+	assert.Equal(t, "gpu", gpuDS.Spec.Selector.MatchLabels[constants.LabelNodeGroup])
+	assert.Equal(t, "gpu", gpuDS.Spec.Template.Labels[constants.LabelNodeGroup])
+	assert.Equal(t, []corev1.NodeSelectorRequirement{{Key: "nvidia.com/gpu.present", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}}},
+		gpuDS.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+	for _, v := range gpuDS.Spec.Template.Spec.Volumes {
+		if v.ConfigMap != nil {
+			assert.Equal(t, "agent-gpu", v.ConfigMap.Name)
+		}
+	}
+	assert.NotEqual(t, ds.Spec.Template.Annotations["amazon-cloudwatch-agent-operator-config/sha256"],
+		gpuDS.Spec.Template.Annotations["amazon-cloudwatch-agent-operator-config/sha256"])
+
+	// the neuron nodes which also have a GPU belong to the gpu group
+	neuronDS := objects[3].(*appsv1.DaemonSet)
+	assert.Equal(t, "agent-neuron", neuronDS.Name)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		{Key: "aws.amazon.com/neuron.present", Operator: corev1.NodeSelectorOpIn, Values: []string{"true"}},
+		notGPU,
+	}, neuronDS.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+
+	params.OtelCol.Spec.Mode = v1alpha1.ModeDeployment
+	objects, err = NodeGroups(context.Background(), params)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestNodeConfigOverrides(t *testing.T) {
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:   v1alpha1.ModeDaemonSet,
+				Config: `{"agent":{"metrics_collection_interval":60}}`,
+				NodeGroups: []v1alpha1.NodeGroupSpec{{
+					Name:      "gpu",
+					NodeLabel: v1alpha1.NodeLabel{Key: "nvidia.com/gpu.present", Value: "true"},
+					Config:    `{"agent":{"debug":true}}`,
+				}},
+				NodeConfigOverrides: []v1alpha1.NodeConfigOverrideSpec{{
+					Name:         "large",
+					NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "m5.24xlarge", "kubernetes.io/os": "linux"},
+					Config:       `{"agent":{"metrics_collection_interval":10}}`,
+				}},
+			},
+		},
+	}
+	inLarge := []corev1.NodeSelectorRequirement{
+		{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}},
+		{Key: "node.kubernetes.io/instance-type", Operator: corev1.NodeSelectorOpIn, Values: []string{"m5.24xlarge"}},
+	}
+	notGPU := corev1.NodeSelectorRequirement{Key: "nvidia.com/gpu.present", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}}
+
+	objects, err := NodeGroups(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, objects, 4)
+
+	cm := objects[2].(*corev1.ConfigMap)
+	assert.Equal(t, "agent-large", cm.Name)
+	assert.JSONEq(t, `{"agent":{"metrics_collection_interval":10}}`, cm.Data["cwagentconfig.json"])
+	ds := objects[3].(*appsv1.DaemonSet)
+	assert.Equal(t, "agent-large", ds.Name)
+	assert.Equal(t, "large", ds.Spec.Template.Labels[constants.LabelNodeGroup])
+	assert.Equal(t, []corev1.NodeSelectorTerm{{MatchExpressions: append(inLarge, notGPU)}},
+		ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	// the nodes of the override fail one of its labels, or more
+	notLinux := corev1.NodeSelectorRequirement{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"linux"}}
+	notLarge := corev1.NodeSelectorRequirement{Key: "node.kubernetes.io/instance-type", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"m5.24xlarge"}}
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{notGPU, notLinux}},
+		{MatchExpressions: []corev1.NodeSelectorRequirement{notGPU, notLarge}},
+	}, DaemonSet(params).Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
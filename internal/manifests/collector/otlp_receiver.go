@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const otlpReceiver = "otlp"
+
+// configWithOTLPReceiverSettings renders the typed otlp receiver settings into the protocols enabled on each
+// otlp receiver of the given configuration.
+func configWithOTLPReceiverSettings(config map[interface{}]interface{}, settings v1alpha1.OTLPReceiverSpec) {
+	receivers, ok := config["receivers"].(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	for k, v := range receivers {
+		name, ok := k.(string)
+		if !ok || (name != otlpReceiver && !strings.HasPrefix(name, otlpReceiver+"/")) {
+			continue
+		}
+		receiver, ok := v.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		protocols, ok := receiver["protocols"].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		if grpc, ok := protocolSettings(protocols, "grpc"); ok && settings.GRPCMaxRecvMsgSizeMiB != nil {
+			grpc["max_recv_msg_size_mib"] = int(*settings.GRPCMaxRecvMsgSizeMiB)
+		}
+		if http, ok := protocolSettings(protocols, "http"); ok {
+			if settings.HTTPMaxRequestBodySize != nil {
+				http["max_request_body_size"] = settings.HTTPMaxRequestBodySize.Value()
+			}
+			if len(settings.HTTPCompressionAlgorithms) > 0 {
+				var algorithms []interface{}
+				for _, algorithm := range settings.HTTPCompressionAlgorithms {
+					algorithms = append(algorithms, string(algorithm))
+				}
+				http["compression_algorithms"] = algorithms
+			}
+		}
+	}
+}
+
+// protocolSettings returns the settings of the given protocol, creating them when the protocol is enabled
+// without settings.
+func protocolSettings(protocols map[interface{}]interface{}, protocol string) (map[interface{}]interface{}, bool) {
+	v, enabled := protocols[protocol]
+	if !enabled {
+		return nil, false
+	}
+	settings, ok := v.(map[interface{}]interface{})
+	if !ok {
+		settings = map[interface{}]interface{}{}
+		protocols[protocol] = settings
+	}
+	return settings, true
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestReplaceOtelConfigOTLPReceiverSettings(t *testing.T) {
+	// prepare
+	maxRecvMsgSize := int32(16)
+	maxRequestBodySize := resource.MustParse("8Mi")
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        max_recv_msg_size_mib: 4
+      http:
+  otlp/grpc-only:
+    protocols:
+      grpc:
+  prometheus:
+    config: {}`,
+			OTLPReceiver: v1alpha1.OTLPReceiverSpec{
+				GRPCMaxRecvMsgSizeMiB:     &maxRecvMsgSize,
+				HTTPMaxRequestBodySize:    &maxRequestBodySize,
+				HTTPCompressionAlgorithms: []v1alpha1.CompressionAlgorithm{"gzip", "zstd"},
+			},
+		},
+	}
+
+	// test
+	actual, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	config, err := adapters.ConfigFromString(actual)
+	require.NoError(t, err)
+
+	// verify
+	receivers := config["receivers"].(map[interface{}]interface{})
+	otlp := receivers["otlp"].(map[interface{}]interface{})["protocols"].(map[interface{}]interface{})
+	assert.Equal(t, 16, otlp["grpc"].(map[interface{}]interface{})["max_recv_msg_size_mib"])
+	http := otlp["http"].(map[interface{}]interface{})
+	assert.Equal(t, 8388608, http["max_request_body_size"])
+	assert.Equal(t, []interface{}{"gzip", "zstd"}, http["compression_algorithms"])
+
+	grpcOnly := receivers["otlp/grpc-only"].(map[interface{}]interface{})["protocols"].(map[interface{}]interface{})
+	assert.Equal(t, 16, grpcOnly["grpc"].(map[interface{}]interface{})["max_recv_msg_size_mib"])
+	assert.NotContains(t, grpcOnly, "http")
+	assert.NotContains(t, receivers["prometheus"], "protocols")
+}
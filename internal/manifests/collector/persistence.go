@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+var defaultPersistenceSize = resource.MustParse("1Gi")
+
+// persistenceDirectory returns the directory persisting the agent's sending queues, or an empty string when
+// persistence is disabled.
+func persistenceDirectory(agent v1alpha1.AmazonCloudWatchAgent) string {
+	if agent.Spec.Mode != v1alpha1.ModeStatefulSet || agent.Spec.Persistence == nil {
+		return ""
+	}
+	if agent.Spec.Persistence.Directory != "" {
+		return agent.Spec.Persistence.Directory
+	}
+	return adapters.DefaultFileStorageDirectory
+}
+
+// persistenceVolumeClaims returns the volumeClaimTemplate of the volume persisting the agent's sending queues.
+func persistenceVolumeClaims(agent v1alpha1.AmazonCloudWatchAgent) []corev1.PersistentVolumeClaim {
+	if persistenceDirectory(agent) == "" {
+		return nil
+	}
+	size := defaultPersistenceSize
+	if agent.Spec.Persistence.Size != nil {
+		size = *agent.Spec.Persistence.Size
+	}
+	return []corev1.PersistentVolumeClaim{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.PersistenceVolume(),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: agent.Spec.Persistence.StorageClassName,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size.DeepCopy()},
+			},
+		},
+	}}
+}
+
+// persistenceVolumeMounts returns the mount of the volume persisting the agent's sending queues.
+func persistenceVolumeMounts(agent v1alpha1.AmazonCloudWatchAgent) []corev1.VolumeMount {
+	directory := persistenceDirectory(agent)
+	if directory == "" {
+		return nil
+	}
+	return []corev1.VolumeMount{{
+		Name:      naming.PersistenceVolume(),
+		MountPath: directory,
+	}}
+}
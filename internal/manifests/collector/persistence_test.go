@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func TestPersistence(t *testing.T) {
+	// prepare
+	size := resource.MustParse("5Gi")
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode: v1alpha1.ModeStatefulSet,
+			OtelConfig: `extensions:
+  file_storage:
+exporters:
+  otlp:
+    sending_queue:
+      enabled: true`,
+			Persistence: &v1alpha1.PersistenceSpec{Size: &size},
+		},
+	}
+
+	// test
+	claims := VolumeClaimTemplates(otelcol)
+	c := Container(config.New(), logger, otelcol, true)
+	volumes := Volumes(config.New(), otelcol)
+	otelConfig, err := ReplaceOtelConfig(otelcol)
+
+	// verify
+	require.Len(t, claims, 1)
+	assert.Equal(t, naming.PersistenceVolume(), claims[0].Name)
+	assert.Equal(t, size, claims[0].Spec.Resources.Requests[corev1.ResourceStorage])
+
+	assert.Contains(t, c.VolumeMounts, corev1.VolumeMount{Name: naming.PersistenceVolume(), MountPath: "/var/lib/otelcol/file_storage"})
+	// the persisted spool directory isn't backed by an emptyDir
+	for _, volume := range volumes {
+		assert.Nil(t, volume.EmptyDir)
+	}
+
+	require.NoError(t, err)
+	assert.Contains(t, otelConfig, "storage: file_storage")
+}
+
+func TestPersistenceDeployment(t *testing.T) {
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:        v1alpha1.ModeDeployment,
+			Persistence: &v1alpha1.PersistenceSpec{},
+		},
+	}
+
+	assert.Empty(t, persistenceDirectory(otelcol))
+	assert.Empty(t, persistenceVolumeClaims(otelcol))
+	assert.Empty(t, persistenceVolumeMounts(otelcol))
+}
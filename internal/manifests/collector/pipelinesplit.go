@@ -0,0 +1,352 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// The signals split out of the daemonset of an instance, each running in a deployment of its own. The logs, and
+// any other section of the config, stay in the daemonset.
+const (
+	signalMetrics = "metrics"
+	signalTraces  = "traces"
+)
+
+var splitSignals = []string{signalMetrics, signalTraces}
+
+// nodeScopedMetrics are the plugins of the metrics_collected of the metrics section which collect from the node they
+// run on, or receive from the applications of the node through its host ports, and stay in the daemonset.
+var nodeScopedMetrics = map[string]bool{
+	"cpu":        true,
+	"disk":       true,
+	"diskio":     true,
+	"mem":        true,
+	"net":        true,
+	"netstat":    true,
+	"processes":  true,
+	"procstat":   true,
+	"swap":       true,
+	"ethtool":    true,
+	"nvidia_gpu": true,
+	"statsd":     true,
+	"collectd":   true,
+}
+
+// nodeScopedReceivers are the types of the receivers of the OtelConfig which collect from the node they run on. The
+// pipelines using them stay in the daemonset.
+var nodeScopedReceivers = map[string]bool{
+	"hostmetrics":                 true,
+	"kubeletstats":                true,
+	"filelog":                     true,
+	"journald":                    true,
+	"docker_stats":                true,
+	"awscontainerinsightreceiver": true,
+}
+
+// isSplitSignal tells whether the given section of the config, or type of pipeline, is split out of the daemonset.
+func isSplitSignal(signal string) bool {
+	for _, s := range splitSignals {
+		if s == signal {
+			return true
+		}
+	}
+	return false
+}
+
+// pipelineDeployment returns the spec of the deployment of the given signal.
+func pipelineDeployment(split *v1alpha1.PipelineSplitSpec, signal string) v1alpha1.PipelineDeploymentSpec {
+	if signal == signalMetrics {
+		return split.Metrics
+	}
+	return split.Traces
+}
+
+// PipelineSplit builds the deployment, the config map, the service and the autoscaler of each signal split out of
+// the daemonset of the instance. A signal gets a deployment only when the Config or the OtelConfig has something to
+// run for it.
+func PipelineSplit(ctx context.Context, params manifests.Params) ([]client.Object, error) {
+	if params.OtelCol.Spec.PipelineSplit == nil || params.OtelCol.Spec.Mode != v1alpha1.ModeDaemonSet {
+		return nil, nil
+	}
+
+	var objects []client.Object
+	for _, signal := range splitSignals {
+		config, found, err := pipelineConfig(params.OtelCol.Spec.Config, signal)
+		if err != nil {
+			return nil, err
+		}
+		otelConfig, err := otelPipelineConfig(params.OtelCol.Spec.OtelConfig, func(pipelineSignal string, nodeScoped bool) bool {
+			return pipelineSignal == signal && !nodeScoped
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !found && otelConfig == "" {
+			continue
+		}
+
+		signalParams := params
+		signalParams.OtelCol = *params.OtelCol.DeepCopy()
+		deployment := pipelineDeployment(signalParams.OtelCol.Spec.PipelineSplit, signal)
+		spec := &signalParams.OtelCol.Spec
+		spec.Mode = v1alpha1.ModeDeployment
+		spec.Config = config
+		spec.OtelConfig = otelConfig
+		spec.PipelineSplit = nil
+		spec.NodeGroups = nil
+		spec.NodeConfigOverrides = nil
+		spec.Replicas = deployment.Replicas
+		if spec.Replicas == nil {
+			one := int32(1)
+			spec.Replicas = &one
+		}
+		spec.MinReplicas = nil
+		spec.MaxReplicas = nil
+		spec.Autoscaler = deployment.Autoscaler
+
+		component := ComponentAmazonCloudWatchAgent + "-" + signal
+		configMaps, err := ConfigMaps(ctx, signalParams)
+		if err != nil {
+			return nil, err
+		}
+		// the other config maps, such as the prometheus one, are shared with the daemonset of the instance
+		cm := configMaps[0]
+		cm.Name = naming.PipelineConfigMap(params.OtelCol.ResourceName(), signal)
+		cm.Labels[constants.LabelPipeline] = signal
+
+		// the pods get a component of their own, for the services of the instance not to select them
+		d := Deployment(signalParams)
+		d.Name = naming.PipelineCollector(params.OtelCol.ResourceName(), signal)
+		for _, labels := range []map[string]string{d.Labels, d.Spec.Selector.MatchLabels, d.Spec.Template.Labels} {
+			labels["app.kubernetes.io/component"] = component
+			labels[constants.LabelPipeline] = signal
+		}
+		for j := range d.Spec.Template.Spec.Volumes {
+			if v := &d.Spec.Template.Spec.Volumes[j]; v.Name == naming.ConfigMapVolume() {
+				v.ConfigMap.Name = cm.Name
+			}
+		}
+		objects = append(objects, cm, d)
+
+		svc, err := Service(signalParams)
+		if err != nil {
+			return nil, err
+		}
+		if svc != nil {
+			svc.Name = d.Name
+			svc.Labels["app.kubernetes.io/component"] = component
+			svc.Labels[constants.LabelPipeline] = signal
+			svc.Spec.Selector = map[string]string{}
+			for k, v := range d.Spec.Selector.MatchLabels {
+				svc.Spec.Selector[k] = v
+			}
+			objects = append(objects, svc)
+		}
+
+		if hpa, ok := HorizontalPodAutoscaler(signalParams).(*autoscalingv2.HorizontalPodAutoscaler); ok {
+			hpa.Name = d.Name
+			hpa.Labels[constants.LabelPipeline] = signal
+			hpa.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       d.Name,
+			}
+			// the replicas are left to the autoscaler
+			d.Spec.Replicas = nil
+			objects = append(objects, hpa)
+		}
+	}
+	return objects, nil
+}
+
+// routeSplitTraces routes the ports of the Service of the traces deployment through the Service of the instance,
+// which the applications send their traces to. Each port of the Service of the instance then targets the container
+// port of its name, which only the pods of one workload open, so that the Service selects the pods of both the
+// daemonset and the traces deployment. A port the daemonset also opens stays with the daemonset.
+func routeSplitTraces(params manifests.Params, objects []client.Object, pipelines []client.Object) []client.Object {
+	var traces *corev1.Service
+	for _, obj := range pipelines {
+		if svc, ok := obj.(*corev1.Service); ok && svc.Labels[constants.LabelPipeline] == signalTraces {
+			traces = svc
+		}
+	}
+	if traces == nil {
+		return objects
+	}
+
+	name := naming.Service(params.OtelCol.ResourceName())
+	var instance *corev1.Service
+	for _, obj := range objects {
+		if svc, ok := obj.(*corev1.Service); ok && svc.Name == name {
+			instance = svc
+		}
+	}
+	if instance == nil {
+		// the daemonset opens no port, the Service of the instance only routes the traces
+		instance = traces.DeepCopy()
+		instance.Name = name
+		instance.Labels = manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+		instance.Spec.Ports = nil
+		objects = append(objects, instance)
+	}
+
+	numbers, names := extractPortNumbersAndNames(instance.Spec.Ports)
+	for i := range instance.Spec.Ports {
+		instance.Spec.Ports[i].TargetPort = intstr.FromString(instance.Spec.Ports[i].Name)
+	}
+	for _, port := range traces.Spec.Ports {
+		if numbers[port.Port] || names[port.Name] {
+			continue
+		}
+		port.TargetPort = intstr.FromString(port.Name)
+		port.NodePort = 0
+		instance.Spec.Ports = append(instance.Spec.Ports, port)
+	}
+	instance.Spec.Selector = manifestutils.SelectorLabelsForAllOperatorManaged(params.OtelCol.ObjectMeta)
+	// the pods of the traces deployment don't run on every node
+	trafficPolicy := corev1.ServiceInternalTrafficPolicyCluster
+	instance.Spec.InternalTrafficPolicy = &trafficPolicy
+	return objects
+}
+
+// withoutSplitSignals returns the params of the daemonset of a split instance, running the sections of the Config
+// and the pipelines of the OtelConfig which are not split out of it, along with the node-scoped ones of the split
+// signals.
+func withoutSplitSignals(params manifests.Params) (manifests.Params, error) {
+	config, err := nodeConfig(params.OtelCol.Spec.Config)
+	if err != nil {
+		return params, err
+	}
+	otelConfig, err := otelPipelineConfig(params.OtelCol.Spec.OtelConfig, func(signal string, nodeScoped bool) bool {
+		return !isSplitSignal(signal) || nodeScoped
+	})
+	if err != nil {
+		return params, err
+	}
+	params.OtelCol = *params.OtelCol.DeepCopy()
+	params.OtelCol.Spec.Config = config
+	params.OtelCol.Spec.OtelConfig = otelConfig
+	return params, nil
+}
+
+// pipelineConfig returns the agent config of the deployment of the given signal, with the agent section and the
+// section of the signal less its node-scoped metrics. found tells whether the deployment has anything to run.
+func pipelineConfig(config string, signal string) (string, bool, error) {
+	if config == "" {
+		return "", false, nil
+	}
+	conf, err := adapters.ConfigFromJSONString(config)
+	if err != nil {
+		return "", false, err
+	}
+	for section := range conf {
+		if section != "agent" && section != signal {
+			delete(conf, section)
+		}
+	}
+	if signal == signalMetrics {
+		filterMetricsCollected(conf, func(plugin string) bool { return !nodeScopedMetrics[plugin] })
+	}
+	_, found := conf[signal]
+	out, err := json.Marshal(conf)
+	if err != nil {
+		return "", false, err
+	}
+	return string(out), found, nil
+}
+
+// nodeConfig returns the agent config of the daemonset of a split instance, with the sections which are not split
+// and the node-scoped metrics.
+func nodeConfig(config string) (string, error) {
+	if config == "" {
+		return "", nil
+	}
+	conf, err := adapters.ConfigFromJSONString(config)
+	if err != nil {
+		return "", err
+	}
+	for section := range conf {
+		if isSplitSignal(section) && section != signalMetrics {
+			delete(conf, section)
+		}
+	}
+	filterMetricsCollected(conf, func(plugin string) bool { return nodeScopedMetrics[plugin] })
+	out, err := json.Marshal(conf)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// filterMetricsCollected keeps the plugins of the metrics_collected of the metrics section accepted by keep, and
+// removes the metrics section when none is.
+func filterMetricsCollected(conf map[string]interface{}, keep func(plugin string) bool) {
+	metrics, ok := conf[signalMetrics].(map[string]interface{})
+	if !ok {
+		delete(conf, signalMetrics)
+		return
+	}
+	collected, _ := metrics["metrics_collected"].(map[string]interface{})
+	for plugin := range collected {
+		if !keep(plugin) {
+			delete(collected, plugin)
+		}
+	}
+	if len(collected) == 0 {
+		delete(conf, signalMetrics)
+	}
+}
+
+// otelPipelineConfig returns the OtelConfig with the pipelines accepted by keep, given their type and whether they
+// use a node-scoped receiver, or an empty string when none is. The components are kept, the collector only starts
+// the ones used by the pipelines.
+func otelPipelineConfig(otelConfig string, keep func(signal string, nodeScoped bool) bool) (string, error) {
+	if otelConfig == "" {
+		return "", nil
+	}
+	conf, err := adapters.ConfigFromString(otelConfig)
+	if err != nil {
+		return "", err
+	}
+	service, _ := conf["service"].(map[interface{}]interface{})
+	pipelines, _ := service["pipelines"].(map[interface{}]interface{})
+	for k, v := range pipelines {
+		id, _ := k.(string)
+		pipeline, _ := v.(map[interface{}]interface{})
+		receivers, _ := pipeline["receivers"].([]interface{})
+		nodeScoped := false
+		for _, r := range receivers {
+			receiver, _ := r.(string)
+			nodeScoped = nodeScoped || nodeScopedReceivers[strings.SplitN(receiver, "/", 2)[0]]
+		}
+		if !keep(strings.SplitN(id, "/", 2)[0], nodeScoped) {
+			delete(pipelines, k)
+		}
+	}
+	if len(pipelines) == 0 {
+		return "", nil
+	}
+	out, err := yaml.Marshal(conf)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
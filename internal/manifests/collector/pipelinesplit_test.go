@@ -0,0 +1,218 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestPipelineSplit(t *testing.T) {
+	three := int32(3)
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:   v1alpha1.ModeDaemonSet,
+				Config: `{"agent":{"region":"us-west-2"},"logs":{"logs_collected":{"files":{}}},"metrics":{"metrics_collected":{"statsd":{},"cpu":{},"otlp":{}}}}`,
+				OtelConfig: `
+receivers:
+  otlp:
+    protocols:
+      grpc: {}
+exporters:
+  awsxray: {}
+service:
+  pipelines:
+    traces/app:
+      receivers: [otlp]
+      exporters: [awsxray]
+    metrics/host:
+      receivers: [hostmetrics]
+      exporters: [awsemf]
+`,
+				PipelineSplit: &v1alpha1.PipelineSplitSpec{
+					Metrics: v1alpha1.PipelineDeploymentSpec{Autoscaler: &v1alpha1.AutoscalerSpec{MaxReplicas: &three}},
+				},
+			},
+		},
+	}
+
+	objects, err := PipelineSplit(context.Background(), params)
+	require.NoError(t, err)
+	find := func(obj client.Object) client.Object {
+		for _, o := range objects {
+			if reflect.TypeOf(o) == reflect.TypeOf(obj) && o.GetName() == obj.GetName() {
+				return o
+			}
+		}
+		require.Failf(t, "object not found", "%T %s", obj, obj.GetName())
+		return nil
+	}
+
+	// the metrics run in an autoscaled deployment with the metrics section of the config
+	cm := find(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "agent-metrics"}}).(*corev1.ConfigMap)
+	assert.Equal(t, "metrics", cm.Labels[constants.LabelPipeline])
+	conf, err := adapters.ConfigFromJSONString(cm.Data["cwagentconfig.json"])
+	require.NoError(t, err)
+	assert.Contains(t, conf, "agent")
+	assert.NotContains(t, conf, "logs")
+	// the node-scoped metrics stay in the daemonset
+	assert.Equal(t, map[string]interface{}{"otlp": map[string]interface{}{}}, conf["metrics"].(map[string]interface{})["metrics_collected"])
+	assert.NotContains(t, cm.Data, "cwagentotelconfig.yaml")
+
+	d := find(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "agent-metrics"}}).(*appsv1.Deployment)
+	assert.Nil(t, d.Spec.Replicas)
+	assert.Equal(t, "amazon-cloudwatch-agent-metrics", d.Spec.Selector.MatchLabels["app.kubernetes.io/component"])
+	assert.Equal(t, "metrics", d.Spec.Selector.MatchLabels[constants.LabelPipeline])
+	assert.Equal(t, d.Spec.Selector.MatchLabels[constants.LabelPipeline], d.Spec.Template.Labels[constants.LabelPipeline])
+	assert.Equal(t, "agent-metrics", d.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+
+	hpa := find(&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "agent-metrics"}}).(*autoscalingv2.HorizontalPodAutoscaler)
+	assert.Equal(t, autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "agent-metrics"}, hpa.Spec.ScaleTargetRef)
+	assert.Equal(t, int32(3), hpa.Spec.MaxReplicas)
+
+	// the traces run in a deployment of one replica with the traces pipelines of the OtelConfig
+	cm = find(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "agent-traces"}}).(*corev1.ConfigMap)
+	assert.Contains(t, cm.Data["cwagentotelconfig.yaml"], "traces/app")
+	d = find(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "agent-traces"}}).(*appsv1.Deployment)
+	assert.Equal(t, int32(1), *d.Spec.Replicas)
+	// the service of the traces selects the pods of their deployment only
+	svc := find(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "agent-traces"}}).(*corev1.Service)
+	assert.Equal(t, d.Spec.Selector.MatchLabels, svc.Spec.Selector)
+
+	// the daemonset keeps the logs and the node-scoped metrics
+	params, err = withoutSplitSignals(params)
+	require.NoError(t, err)
+	conf, err = adapters.ConfigFromJSONString(params.OtelCol.Spec.Config)
+	require.NoError(t, err)
+	assert.Contains(t, conf, "agent")
+	assert.Contains(t, conf, "logs")
+	assert.Equal(t, map[string]interface{}{"statsd": map[string]interface{}{}, "cpu": map[string]interface{}{}}, conf["metrics"].(map[string]interface{})["metrics_collected"])
+	assert.Contains(t, params.OtelCol.Spec.OtelConfig, "metrics/host")
+	assert.NotContains(t, params.OtelCol.Spec.OtelConfig, "traces/app")
+}
+
+func TestPipelineSplitNodeScopedOnly(t *testing.T) {
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:          v1alpha1.ModeDaemonSet,
+				Config:        `{"metrics":{"metrics_collected":{"cpu":{},"mem":{}}}}`,
+				PipelineSplit: &v1alpha1.PipelineSplitSpec{},
+			},
+		},
+	}
+	// the metrics of the nodes aren't moved to a deployment
+	objects, err := PipelineSplit(context.Background(), params)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestRouteSplitTraces(t *testing.T) {
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:          v1alpha1.ModeDaemonSet,
+				Config:        `{"logs":{"metrics_collected":{"emf":{}}},"traces":{"traces_collected":{"xray":{}}}}`,
+				PipelineSplit: &v1alpha1.PipelineSplitSpec{},
+			},
+		},
+	}
+	objects, err := Build(context.Background(), params)
+	require.NoError(t, err)
+
+	var instance, traces *corev1.Service
+	var daemonset *appsv1.DaemonSet
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *corev1.Service:
+			if o.Name == "agent" {
+				instance = o
+			} else if o.Name == "agent-traces" {
+				traces = o
+			}
+		case *appsv1.DaemonSet:
+			daemonset = o
+		}
+	}
+	require.NotNil(t, instance)
+	require.NotNil(t, traces)
+	require.NotNil(t, daemonset)
+
+	// the applications keep sending their traces to the Service of the instance
+	ports := map[string]corev1.ServicePort{}
+	for _, port := range instance.Spec.Ports {
+		ports[port.Name] = port
+	}
+	for _, port := range traces.Spec.Ports {
+		require.Contains(t, ports, port.Name)
+		assert.Equal(t, port.Port, ports[port.Name].Port)
+	}
+	// each port targets the pods opening the container port of its name
+	for name, port := range ports {
+		assert.Equal(t, intstr.FromString(name), port.TargetPort)
+	}
+	for _, port := range daemonset.Spec.Template.Spec.Containers[0].Ports {
+		assert.Contains(t, ports, port.Name)
+	}
+	for k, v := range instance.Spec.Selector {
+		assert.Equal(t, v, daemonset.Spec.Template.Labels[k])
+	}
+	assert.Equal(t, corev1.ServiceInternalTrafficPolicyCluster, *instance.Spec.InternalTrafficPolicy)
+}
+
+func TestOtelPipelineConfig(t *testing.T) {
+	otelConfig := `
+receivers:
+  otlp: {}
+exporters:
+  debug: {}
+service:
+  pipelines:
+    metrics:
+      receivers: [otlp]
+      exporters: [debug]
+    logs/app:
+      receivers: [otlp]
+      exporters: [debug]
+`
+	logs, err := otelPipelineConfig(otelConfig, func(signal string, _ bool) bool { return signal == "logs" })
+	require.NoError(t, err)
+	conf, err := adapters.ConfigFromString(logs)
+	require.NoError(t, err)
+	pipelines := conf["service"].(map[interface{}]interface{})["pipelines"].(map[interface{}]interface{})
+	assert.Contains(t, pipelines, "logs/app")
+	assert.NotContains(t, pipelines, "metrics")
+	assert.Contains(t, conf["receivers"], "otlp")
+
+	traces, err := otelPipelineConfig(otelConfig, func(signal string, _ bool) bool { return signal == "traces" })
+	require.NoError(t, err)
+	assert.Empty(t, traces)
+}
@@ -19,12 +19,12 @@ func PodDisruptionBudget(params manifests.Params) *policyV1.PodDisruptionBudget
 		return nil
 	}
 
-	name := naming.Collector(params.OtelCol.Name)
+	name := naming.Collector(params.OtelCol.ResourceName())
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter())
-	annotations := Annotations(params.OtelCol)
+	annotations := Annotations(params.Config, params.OtelCol)
 
 	objectMeta := metav1.ObjectMeta{
-		Name:        naming.PodDisruptionBudget(params.OtelCol.Name),
+		Name:        naming.PodDisruptionBudget(params.OtelCol.ResourceName()),
 		Namespace:   params.OtelCol.Namespace,
 		Labels:      labels,
 		Annotations: annotations,
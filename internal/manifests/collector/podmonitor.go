@@ -33,9 +33,9 @@ func PodMonitor(params manifests.Params) (*monitoringv1.PodMonitor, error) {
 	pm = monitoringv1.PodMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: params.OtelCol.Namespace,
-			Name:      naming.PodMonitor(params.OtelCol.Name),
+			Name:      naming.PodMonitor(params.OtelCol.ResourceName()),
 			Labels: map[string]string{
-				"app.kubernetes.io/name":       naming.PodMonitor(params.OtelCol.Name),
+				"app.kubernetes.io/name":       naming.PodMonitor(params.OtelCol.ResourceName()),
 				"app.kubernetes.io/instance":   fmt.Sprintf("%s.%s", params.OtelCol.Namespace, params.OtelCol.Name),
 				"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 			},
@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestPodSpecHostNamespaces(t *testing.T) {
+	gracePeriod := int64(120)
+	share := true
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				HostPID:                       true,
+				HostIPC:                       true,
+				ShareProcessNamespace:         &share,
+				TerminationGracePeriodSeconds: &gracePeriod,
+			},
+		},
+	}
+
+	for name, pod := range map[string]corev1.PodSpec{
+		"daemonset":   DaemonSet(params).Spec.Template.Spec,
+		"deployment":  Deployment(params).Spec.Template.Spec,
+		"statefulset": StatefulSet(params).Spec.Template.Spec,
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, pod.HostPID)
+			assert.True(t, pod.HostIPC)
+			assert.Equal(t, &share, pod.ShareProcessNamespace)
+			assert.Equal(t, &gracePeriod, pod.TerminationGracePeriodSeconds)
+		})
+	}
+
+	params.OtelCol.Spec = v1alpha1.AmazonCloudWatchAgentSpec{}
+	pod := DaemonSet(params).Spec.Template.Spec
+	assert.False(t, pod.HostPID)
+	assert.False(t, pod.HostIPC)
+	assert.Nil(t, pod.ShareProcessNamespace)
+	assert.Nil(t, pod.TerminationGracePeriodSeconds)
+}
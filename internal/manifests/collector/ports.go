@@ -16,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
 )
@@ -327,3 +328,13 @@ func isDuplicatePort(portsMap map[string]corev1.ContainerPort, servicePort corev
 	}
 	return false
 }
+
+// HostPorts returns the ports the agent pods of the instance bind on the nodes they run on, which are the container
+// ports of a daemonset on the host network. The pods of two daemonsets binding the same host port can't run on the
+// same node.
+func HostPorts(agent v1alpha1.AmazonCloudWatchAgent) []corev1.ContainerPort {
+	if agent.Spec.Mode != v1alpha1.ModeDaemonSet || !hostNetwork(agent) {
+		return nil
+	}
+	return portMapToContainerPortList(getContainerPorts(logr.Discard(), agent.Spec.Config, agent.Spec.OtelConfig, agent.Spec.Ports))
+}
@@ -11,6 +11,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 )
 
 func TestStatsDGetContainerPorts(t *testing.T) {
@@ -495,6 +497,28 @@ func TestJMXContainerInsightsGetContainerPorts(t *testing.T) {
 	assert.Equal(t, corev1.ProtocolTCP, containerPorts[JmxHttp].Protocol)
 }
 
+func TestHostPorts(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:        v1alpha1.ModeDaemonSet,
+			HostNetwork: true,
+			Config:      getStringFromFile("./test-resources/emfAgentConfig.json"),
+		},
+	}
+	hostPorts := HostPorts(agent)
+	assert.Equal(t, []corev1.ContainerPort{
+		{Name: EMFTcp, ContainerPort: 25888, Protocol: corev1.ProtocolTCP},
+		{Name: EMFUdp, ContainerPort: 25888, Protocol: corev1.ProtocolUDP},
+	}, hostPorts)
+
+	// the pods of deployments, and the ones off the host network, don't bind host ports
+	agent.Spec.Mode = v1alpha1.ModeDeployment
+	assert.Empty(t, HostPorts(agent))
+	agent.Spec.Mode = v1alpha1.ModeDaemonSet
+	agent.Spec.HostNetwork = false
+	assert.Empty(t, HostPorts(agent))
+}
+
 func checkPorts(t *testing.T, want []corev1.ContainerPort, got map[string]corev1.ContainerPort) {
 	t.Helper()
 
@@ -0,0 +1,107 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	prometheusReloaderContainer     = "prometheus-reloader"
+	defaultPrometheusReloadSignal   = "HUP"
+	defaultPrometheusReloadInterval = int32(10)
+	reloadAgentProcessMatch         = linuxAgentBinary
+
+	// reloadScript signals the agent whenever the checksum of the mounted configuration changes. When CONFIG_HASH is
+	// set, only the changes leaving the hash mounted at CONFIG_HASH_FILE equal to it are signaled, the others rolling
+	// out the pod. The values are passed through the environment, so that pkill doesn't match the command line of the
+	// script.
+	reloadScript = `last="$(md5sum "$CONFIG_FILE")"
+while true; do
+  sleep "$INTERVAL_SECONDS"
+  current="$(md5sum "$CONFIG_FILE")"
+  if [ "$current" != "$last" ] && { [ -z "$CONFIG_HASH" ] || [ "$(cat "$CONFIG_HASH_FILE")" = "$CONFIG_HASH" ]; } &&
+    pkill -"$SIGNAL" -f "$AGENT_PROCESS"; then
+    echo "signaled the agent with SIG$SIGNAL after a change of $CONFIG_FILE"
+    last="$current"
+  fi
+done`
+)
+
+// PrometheusReloaderContainer builds the sidecar signaling the agent when its Prometheus configuration changes.
+func PrometheusReloaderContainer(cfg config.Config, agent v1alpha1.AmazonCloudWatchAgent) corev1.Container {
+	reload := agent.Spec.PrometheusReload
+	image := reload.Image
+	if image == "" {
+		image = cfg.PrometheusReloaderImage()
+	}
+	signal := reload.Signal
+	if signal == "" {
+		signal = defaultPrometheusReloadSignal
+	}
+	interval := defaultPrometheusReloadInterval
+	if reload.IntervalSeconds != nil {
+		interval = *reload.IntervalSeconds
+	}
+
+	mount := getPrometheusVolumeMounts(agent.Spec.NodeSelector["kubernetes.io/os"])
+	return reloaderContainer(prometheusReloaderContainer, image, path.Join(mount.MountPath, cfg.PrometheusConfigMapEntry()), interval, signal, mount)
+}
+
+// reloaderContainer builds a sidecar signaling the agent process when the given mounted configuration file changes,
+// with the given additional environment variables of the reload script.
+func reloaderContainer(name, image, configFile string, interval int32, signal string, mount corev1.VolumeMount, env ...corev1.EnvVar) corev1.Container {
+	mount.ReadOnly = true
+	return corev1.Container{
+		Name:    name,
+		Image:   image,
+		Command: []string{"/bin/sh", "-c", reloadScript},
+		Env: append([]corev1.EnvVar{
+			{Name: "CONFIG_FILE", Value: configFile},
+			{Name: "INTERVAL_SECONDS", Value: fmt.Sprint(interval)},
+			{Name: "SIGNAL", Value: signal},
+			{Name: "AGENT_PROCESS", Value: reloadAgentProcessMatch},
+		}, env...),
+		VolumeMounts: []corev1.VolumeMount{mount},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("5m"),
+				corev1.ResourceMemory: resource.MustParse("8Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+	}
+}
+
+// prometheusConfigVolumeSource returns the source of the volume of the Prometheus configuration. It is projected
+// when the configuration is reloaded, the kubelet updating the mounted file in place.
+func prometheusConfigVolumeSource(cfg config.Config, agent v1alpha1.AmazonCloudWatchAgent) corev1.VolumeSource {
+	configMap := corev1.LocalObjectReference{Name: naming.PrometheusConfigMap(agent.ResourceName())}
+	items := []corev1.KeyToPath{{
+		Key:  cfg.PrometheusConfigMapEntry(),
+		Path: cfg.PrometheusConfigMapEntry(),
+	}}
+	if agent.PrometheusReloadEnabled() {
+		return corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: configMap, Items: items},
+				}},
+			},
+		}
+	}
+	return corev1.VolumeSource{
+		ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: configMap, Items: items},
+	}
+}
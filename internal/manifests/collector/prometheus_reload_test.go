@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func TestPrometheusReload(t *testing.T) {
+	interval := int32(30)
+	params := manifests.Params{
+		Config: config.New(config.WithPrometheusReloaderImage("busybox:default")),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode: v1alpha1.ModeDeployment,
+				Prometheus: v1alpha1.PrometheusConfig{
+					Config: &v1alpha1.AnyConfig{Object: map[string]interface{}{"scrape_configs": []interface{}{}}},
+				},
+				PrometheusReload: &v1alpha1.PrometheusReloadSpec{Signal: "USR1", IntervalSeconds: &interval},
+			},
+		},
+	}
+
+	pod := Deployment(params).Spec.Template.Spec
+	require.Len(t, pod.Containers, 2)
+	assert.Equal(t, naming.Container(), pod.Containers[0].Name)
+	reloader := pod.Containers[1]
+	assert.Equal(t, "prometheus-reloader", reloader.Name)
+	assert.Equal(t, "busybox:default", reloader.Image)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "CONFIG_FILE", Value: "/etc/prometheusconfig/prometheus.yaml"},
+		{Name: "INTERVAL_SECONDS", Value: "30"},
+		{Name: "SIGNAL", Value: "USR1"},
+		{Name: "AGENT_PROCESS", Value: "/opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent"},
+	}, reloader.Env)
+	assert.Equal(t, []corev1.VolumeMount{{Name: naming.PrometheusConfigMapVolume(), MountPath: "/etc/prometheusconfig", ReadOnly: true}}, reloader.VolumeMounts)
+	assert.Equal(t, &[]bool{true}[0], pod.ShareProcessNamespace)
+
+	var volume *corev1.Volume
+	for i := range pod.Volumes {
+		if pod.Volumes[i].Name == naming.PrometheusConfigMapVolume() {
+			volume = &pod.Volumes[i]
+		}
+	}
+	require.NotNil(t, volume)
+	require.NotNil(t, volume.Projected)
+	assert.Equal(t, naming.PrometheusConfigMap("agent"), volume.Projected.Sources[0].ConfigMap.Name)
+
+	// the image of the spec takes precedence over the operator default
+	params.OtelCol.Spec.PrometheusReload.Image = "busybox:custom"
+	assert.Equal(t, "busybox:custom", PrometheusReloaderContainer(params.Config, params.OtelCol).Image)
+
+	// not supported on Windows
+	params.OtelCol.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+	pod = Deployment(params).Spec.Template.Spec
+	assert.Len(t, pod.Containers, 1)
+	assert.Nil(t, pod.ShareProcessNamespace)
+
+	params.OtelCol.Spec.NodeSelector = nil
+	params.OtelCol.Spec.PrometheusReload = nil
+	pod = Deployment(params).Spec.Template.Spec
+	assert.Len(t, pod.Containers, 1)
+	assert.Nil(t, pod.ShareProcessNamespace)
+	for _, volume := range pod.Volumes {
+		if volume.Name == naming.PrometheusConfigMapVolume() {
+			assert.NotNil(t, volume.ConfigMap)
+		}
+	}
+}
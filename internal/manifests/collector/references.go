@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// Reference is a ConfigMap or a Secret of another namespace referenced by the spec of an instance.
+type Reference struct {
+	// Secret tells whether the reference is a Secret, rather than a ConfigMap.
+	Secret    bool
+	Namespace string
+	Name      string
+}
+
+// CopyName returns the name of the copy of the referenced object in the namespace of the instance.
+func (r Reference) CopyName(agent v1alpha1.AmazonCloudWatchAgent) string {
+	return naming.ReferenceCopy(agent.ResourceName(), r.Namespace, r.Name)
+}
+
+// CrossNamespaceReferences returns the ConfigMaps and the Secrets of other namespaces referenced by the instance.
+// Pods can only mount the ones of their namespace, so the manifests mount copies of them, which the operator keeps
+// in sync.
+func CrossNamespaceReferences(agent v1alpha1.AmazonCloudWatchAgent) []Reference {
+	var references []Reference
+	if agent.Spec.TLS != nil && isCrossNamespace(agent, agent.Spec.TLS.SecretNamespace) && agent.Spec.TLS.SecretName != "" {
+		references = append(references, Reference{Secret: true, Namespace: agent.Spec.TLS.SecretNamespace, Name: agent.Spec.TLS.SecretName})
+	}
+	if trustedCA := agent.Spec.TrustedCA; trustedCA != nil && isCrossNamespace(agent, trustedCA.Namespace) {
+		if trustedCA.Secret != "" {
+			references = append(references, Reference{Secret: true, Namespace: trustedCA.Namespace, Name: trustedCA.Secret})
+		} else {
+			references = append(references, Reference{Namespace: trustedCA.Namespace, Name: trustedCA.ConfigMap})
+		}
+	}
+	for _, m := range agent.Spec.ExtraMounts {
+		if !isCrossNamespace(agent, m.Namespace) {
+			continue
+		}
+		if m.Secret != "" {
+			references = append(references, Reference{Secret: true, Namespace: m.Namespace, Name: m.Secret})
+		} else {
+			references = append(references, Reference{Namespace: m.Namespace, Name: m.ConfigMap})
+		}
+	}
+	return references
+}
+
+// referenceName returns the name of the ConfigMap or the Secret of the given namespace to use in the namespace of
+// the instance: the copy of the ones of other namespaces.
+func referenceName(agent v1alpha1.AmazonCloudWatchAgent, namespace, name string) string {
+	if !isCrossNamespace(agent, namespace) {
+		return name
+	}
+	return Reference{Namespace: namespace, Name: name}.CopyName(agent)
+}
+
+func isCrossNamespace(agent v1alpha1.AmazonCloudWatchAgent, namespace string) bool {
+	return namespace != "" && namespace != agent.Namespace
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestCrossNamespaceReferences(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			TLS: &v1alpha1.TLSSpec{SecretName: "agent-tls", SecretNamespace: "certificates"},
+			ExtraMounts: []v1alpha1.ExtraMountSpec{
+				{ConfigMap: "ca", Namespace: "certificates", MountPath: "/etc/ssl/custom"},
+				{Secret: "credentials", Namespace: "amazon-cloudwatch", MountPath: "/root/.aws"},
+				{Secret: "proxy", MountPath: "/etc/proxy"},
+			},
+		},
+	}
+
+	assert.Equal(t, []Reference{
+		{Secret: true, Namespace: "certificates", Name: "agent-tls"},
+		{Namespace: "certificates", Name: "ca"},
+	}, CrossNamespaceReferences(agent))
+
+	assert.Equal(t, "agent-certificates-agent-tls", TLSSecretName(agent))
+	volumes := Volumes(config.New(), agent)
+	assert.Contains(t, volumes, corev1.Volume{
+		Name: "extra-mount-0",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "agent-certificates-ca"},
+		}},
+	})
+	assert.Contains(t, volumes, corev1.Volume{
+		Name:         "extra-mount-1",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "credentials"}},
+	})
+	assert.Contains(t, volumes, corev1.Volume{
+		Name:         "extra-mount-2",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "proxy"}},
+	})
+}
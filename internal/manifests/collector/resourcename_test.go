@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func TestNameOverride(t *testing.T) {
+	naming.SetResourceNamePrefix("obs-")
+	defer naming.SetResourceNamePrefix("")
+
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent-for-the-payments-team-in-the-production-cluster", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:         v1alpha1.ModeDaemonSet,
+				NameOverride: "payments",
+				Config:       `{"agent":{"region":"us-west-2"}}`,
+			},
+		},
+	}
+
+	ds := DaemonSet(params)
+	assert.Equal(t, "obs-payments", ds.Name)
+	assert.Equal(t, "obs-payments", ds.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+	assert.Equal(t, "obs-payments", ds.Spec.Template.Spec.ServiceAccountName)
+	// the labels still refer to the instance by its name
+	assert.Equal(t, naming.Truncate("%s.%s", 63, params.OtelCol.Namespace, params.OtelCol.Name), ds.Labels["app.kubernetes.io/instance"])
+
+	configMaps, err := ConfigMaps(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, "obs-payments", configMaps[0].Name)
+	assert.Equal(t, "obs-payments", ServiceAccountName(params.OtelCol))
+}
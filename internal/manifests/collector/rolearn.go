@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strings"
+)
+
+// configWithRoleArn renders the role to assume into the credentials of the agent section of the given agent config.
+// The sections setting credentials of their own keep them.
+func configWithRoleArn(config map[string]interface{}, roleArn string) {
+	if roleArn == "" {
+		return
+	}
+	agent, ok := config["agent"].(map[string]interface{})
+	if !ok {
+		agent = map[string]interface{}{}
+		config["agent"] = agent
+	}
+	credentials, ok := agent["credentials"].(map[string]interface{})
+	if !ok {
+		credentials = map[string]interface{}{}
+		agent["credentials"] = credentials
+	}
+	credentials["role_arn"] = roleArn
+}
+
+// otelConfigWithRoleArn renders the role to assume into the AWS exporters of the given configuration which don't set
+// a role of their own.
+func otelConfigWithRoleArn(config map[interface{}]interface{}, roleArn string) {
+	exporters, ok := config["exporters"].(map[interface{}]interface{})
+	if !ok || roleArn == "" {
+		return
+	}
+	for k, v := range exporters {
+		id, ok := k.(string)
+		if !ok {
+			continue
+		}
+		switch strings.SplitN(id, "/", 2)[0] {
+		case awsEMFExporter, awsCloudWatchLogsExporter, awsXRayExporter:
+		default:
+			continue
+		}
+		exporter, ok := v.(map[interface{}]interface{})
+		if !ok {
+			exporter = map[interface{}]interface{}{}
+			exporters[k] = exporter
+		}
+		if exporter["role_arn"] == nil {
+			exporter["role_arn"] = roleArn
+		}
+	}
+}
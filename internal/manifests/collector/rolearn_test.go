@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestRoleArn(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			RoleArn: "arn:aws:iam::123456789012:role/telemetry",
+			Config:  `{"agent":{"region":"us-west-2"},"logs":{"credentials":{"role_arn":"arn:aws:iam::123456789012:role/logs"}}}`,
+			OtelConfig: `
+exporters:
+  awsemf/app: {}
+  awscloudwatchlogs:
+    role_arn: arn:aws:iam::123456789012:role/logs
+  awsxray: {}
+  debug: {}
+`,
+		},
+	}
+
+	replaced, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"agent":{"region":"us-west-2","credentials":{"role_arn":"arn:aws:iam::123456789012:role/telemetry"}},
+		"logs":{"credentials":{"role_arn":"arn:aws:iam::123456789012:role/logs"}}
+	}`, replaced)
+
+	replaced, err = ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	config, err := adapters.ConfigFromString(replaced)
+	require.NoError(t, err)
+	exporters := config["exporters"].(map[interface{}]interface{})
+	assert.Equal(t, map[interface{}]interface{}{"role_arn": "arn:aws:iam::123456789012:role/telemetry"}, exporters["awsemf/app"])
+	assert.Equal(t, map[interface{}]interface{}{"role_arn": "arn:aws:iam::123456789012:role/logs"}, exporters["awscloudwatchlogs"])
+	assert.Equal(t, map[interface{}]interface{}{"role_arn": "arn:aws:iam::123456789012:role/telemetry"}, exporters["awsxray"])
+	assert.Equal(t, map[interface{}]interface{}{}, exporters["debug"])
+
+	// without a role, the config is left as is
+	agent.Spec.RoleArn = ""
+	replaced, err = ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, agent.Spec.Config, replaced)
+}
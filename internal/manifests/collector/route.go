@@ -61,11 +61,11 @@ func Routes(params manifests.Params) ([]*routev1.Route, error) {
 
 		routes[i] = &routev1.Route{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:        naming.Route(params.OtelCol.Name, p.Name),
+				Name:        naming.Route(params.OtelCol.ResourceName(), p.Name),
 				Namespace:   params.OtelCol.Namespace,
 				Annotations: params.OtelCol.Spec.Ingress.Annotations,
 				Labels: map[string]string{
-					"app.kubernetes.io/name":       naming.Route(params.OtelCol.Name, p.Name),
+					"app.kubernetes.io/name":       naming.Route(params.OtelCol.ResourceName(), p.Name),
 					"app.kubernetes.io/instance":   fmt.Sprintf("%s.%s", params.OtelCol.Namespace, params.OtelCol.Name),
 					"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 					"app.kubernetes.io/component":  "amazon-cloudwatch-agent",
@@ -75,7 +75,7 @@ func Routes(params manifests.Params) ([]*routev1.Route, error) {
 				Host: host,
 				To: routev1.RouteTargetReference{
 					Kind: "Service",
-					Name: naming.Service(params.OtelCol.Name),
+					Name: naming.Service(params.OtelCol.ResourceName()),
 				},
 				Port: &routev1.RoutePort{
 					TargetPort: intstr.FromString(portName),
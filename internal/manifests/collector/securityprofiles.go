@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// seccompSecurityContext returns the pod security context of the spec with the seccomp profile of its security
+// profiles.
+func seccompSecurityContext(agent v1alpha1.AmazonCloudWatchAgent) *corev1.PodSecurityContext {
+	profiles := agent.Spec.SecurityProfiles
+	if profiles == nil || profiles.Seccomp == nil {
+		return agent.Spec.PodSecurityContext
+	}
+	securityContext := &corev1.PodSecurityContext{}
+	if agent.Spec.PodSecurityContext != nil {
+		securityContext = agent.Spec.PodSecurityContext.DeepCopy()
+	}
+	securityContext.SeccompProfile = profiles.Seccomp.DeepCopy()
+	return securityContext
+}
+
+// withAppArmorProfile annotates the pods with the AppArmor profile of the security profiles of the spec for each of
+// their containers, leaving the annotations already set for a container untouched.
+func withAppArmorProfile(agent v1alpha1.AmazonCloudWatchAgent, annotations map[string]string, containers ...[]corev1.Container) map[string]string {
+	profiles := agent.Spec.SecurityProfiles
+	if profiles == nil || profiles.AppArmor == "" {
+		return annotations
+	}
+	for _, list := range containers {
+		for _, container := range list {
+			key := corev1.AppArmorBetaContainerAnnotationKeyPrefix + container.Name
+			if _, found := annotations[key]; !found {
+				annotations[key] = profiles.AppArmor
+			}
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestSecurityProfiles(t *testing.T) {
+	runAsNonRoot := true
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logger,
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				PodSecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: &runAsNonRoot},
+				SecurityProfiles: &v1alpha1.SecurityProfilesSpec{
+					Seccomp:  &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					AppArmor: "runtime/default",
+				},
+				InitContainers: []corev1.Container{{Name: "init"}},
+				PodAnnotations: map[string]string{
+					"container.apparmor.security.beta.kubernetes.io/init": "localhost/init",
+				},
+			},
+		},
+	}
+
+	for _, template := range []corev1.PodTemplateSpec{
+		DaemonSet(params).Spec.Template,
+		Deployment(params).Spec.Template,
+		StatefulSet(params).Spec.Template,
+	} {
+		assert.Equal(t, &corev1.PodSecurityContext{
+			RunAsNonRoot:   &runAsNonRoot,
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		}, template.Spec.SecurityContext)
+		assert.Equal(t, "runtime/default", template.Annotations["container.apparmor.security.beta.kubernetes.io/otc-container"])
+		// the annotations set by the users take precedence
+		assert.Equal(t, "localhost/init", template.Annotations["container.apparmor.security.beta.kubernetes.io/init"])
+	}
+	assert.Nil(t, params.OtelCol.Spec.PodSecurityContext.SeccompProfile, "the instance is left untouched")
+
+	// the pods are left unconfined without security profiles
+	params.OtelCol.Spec.SecurityProfiles = nil
+	template := DaemonSet(params).Spec.Template
+	assert.Equal(t, params.OtelCol.Spec.PodSecurityContext, template.Spec.SecurityContext)
+	assert.NotContains(t, template.Annotations, "container.apparmor.security.beta.kubernetes.io/otc-container")
+}
@@ -0,0 +1,108 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// The components rendered to export the telemetry of the agent itself, apart from the telemetry it collects.
+const (
+	defaultSelfTelemetryNamespace       = "CWAgent/SelfTelemetry"
+	defaultSelfTelemetryPort      int32 = 8888
+	selfTelemetryReceiver               = "prometheus/self-telemetry"
+	selfTelemetryExporter               = "awsemf/self-telemetry"
+	selfTelemetryPipeline               = "metrics/self-telemetry"
+)
+
+// selfTelemetryLogGroupName returns the log group of the telemetry of the agent itself.
+func selfTelemetryLogGroupName(instance v1alpha1.AmazonCloudWatchAgent) string {
+	if name := instance.Spec.Observability.SelfTelemetry.LogGroupName; name != "" {
+		return name
+	}
+	return fmt.Sprintf("/aws/cloudwatch-agent/%s/%s", instance.Namespace, instance.Name)
+}
+
+// selfTelemetryPort returns the port of the internal metrics endpoint of the agent.
+func selfTelemetryPort(selfTelemetry *v1alpha1.SelfTelemetrySpec) int32 {
+	if selfTelemetry.MetricsPort != 0 {
+		return selfTelemetry.MetricsPort
+	}
+	return defaultSelfTelemetryPort
+}
+
+// configWithSelfTelemetry collects the log file of the agent into the self-telemetry log group. The agent logs to
+// its standard output unless agent.logfile is set, which the config is left to choose.
+func configWithSelfTelemetry(config map[string]interface{}, instance v1alpha1.AmazonCloudWatchAgent) {
+	if instance.Spec.Observability.SelfTelemetry == nil {
+		return
+	}
+	agent, _ := config["agent"].(map[string]interface{})
+	logFile, _ := agent["logfile"].(string)
+	if logFile == "" {
+		return
+	}
+
+	logs := jsonChildMap(config, "logs")
+	files := jsonChildMap(jsonChildMap(logs, "logs_collected"), "files")
+	collectList, _ := files["collect_list"].([]interface{})
+	for _, v := range collectList {
+		if entry, ok := v.(map[string]interface{}); ok && entry["file_path"] == logFile {
+			return
+		}
+	}
+	files["collect_list"] = append(collectList, map[string]interface{}{
+		"file_path":       logFile,
+		"log_group_name":  selfTelemetryLogGroupName(instance),
+		"log_stream_name": "{hostname}",
+	})
+}
+
+// otelConfigWithSelfTelemetry exposes the internal metrics of the collector on the self-telemetry port, and adds a
+// pipeline scraping them into the self-telemetry namespace. The components it adds replace the ones of the same
+// IDs set in the configuration.
+func otelConfigWithSelfTelemetry(config map[interface{}]interface{}, instance v1alpha1.AmazonCloudWatchAgent) {
+	selfTelemetry := instance.Spec.Observability.SelfTelemetry
+	if selfTelemetry == nil {
+		return
+	}
+	port := selfTelemetryPort(selfTelemetry)
+	service := childMap(config, "service")
+	childMap(childMap(service, "telemetry"), "metrics")["address"] = fmt.Sprintf("0.0.0.0:%d", port)
+
+	namespace := selfTelemetry.MetricsNamespace
+	if namespace == "" {
+		namespace = defaultSelfTelemetryNamespace
+	}
+	childMap(config, "receivers")[selfTelemetryReceiver] = map[interface{}]interface{}{
+		"config": map[interface{}]interface{}{
+			"scrape_configs": []interface{}{
+				map[interface{}]interface{}{
+					"job_name":       "cloudwatch-agent",
+					"static_configs": []interface{}{map[interface{}]interface{}{"targets": []interface{}{fmt.Sprintf("localhost:%d", port)}}},
+				},
+			},
+		},
+	}
+	childMap(config, "exporters")[selfTelemetryExporter] = map[interface{}]interface{}{
+		"namespace":      namespace,
+		"log_group_name": selfTelemetryLogGroupName(instance),
+	}
+	childMap(service, "pipelines")[selfTelemetryPipeline] = map[interface{}]interface{}{
+		"receivers": []interface{}{selfTelemetryReceiver},
+		"exporters": []interface{}{selfTelemetryExporter},
+	}
+}
+
+// jsonChildMap returns the map under the given key of the agent config, creating it if needed.
+func jsonChildMap(config map[string]interface{}, key string) map[string]interface{} {
+	child, ok := config[key].(map[string]interface{})
+	if !ok {
+		child = map[string]interface{}{}
+		config[key] = child
+	}
+	return child
+}
@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestConfigWithSelfTelemetry(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app.log"}]}}}}`,
+			Observability: v1alpha1.ObservabilitySpec{
+				SelfTelemetry: &v1alpha1.SelfTelemetrySpec{},
+			},
+		},
+	}
+
+	// the agent logs to its standard output without a log file, which is left to the config
+	replaced, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, agent.Spec.Config, replaced)
+
+	agent.Spec.Config = `{"agent":{"logfile":"/opt/aws/amazon-cloudwatch-agent/logs/amazon-cloudwatch-agent.log"},"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app.log"}]}}}}`
+	replaced, err = ReplaceConfig(agent)
+	require.NoError(t, err)
+	config := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(replaced), &config))
+	collectList := config["logs"].(map[string]interface{})["logs_collected"].(map[string]interface{})["files"].(map[string]interface{})["collect_list"].([]interface{})
+	require.Len(t, collectList, 2)
+	assert.Equal(t, map[string]interface{}{
+		"file_path":       "/opt/aws/amazon-cloudwatch-agent/logs/amazon-cloudwatch-agent.log",
+		"log_group_name":  "/aws/cloudwatch-agent/amazon-cloudwatch/agent",
+		"log_stream_name": "{hostname}",
+	}, collectList[1])
+
+	// rendering is idempotent
+	agent.Spec.Config = replaced
+	again, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, replaced, again)
+
+	// the log file set in the config is collected into the log group of the spec
+	agent.Spec.Config = `{"agent":{"logfile":"/tmp/agent.log"}}`
+	agent.Spec.Observability.SelfTelemetry.LogGroupName = "fleet-health"
+	replaced, err = ReplaceConfig(agent)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"agent":{"logfile":"/tmp/agent.log"},"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/tmp/agent.log","log_group_name":"fleet-health","log_stream_name":"{hostname}"}]}}}}`, replaced)
+}
+
+func TestOtelConfigWithSelfTelemetry(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `
+receivers:
+  otlp: {}
+exporters:
+  awsxray: {}
+service:
+  telemetry:
+    metrics:
+      address: localhost:8888
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [awsxray]
+`,
+			Observability: v1alpha1.ObservabilitySpec{
+				SelfTelemetry: &v1alpha1.SelfTelemetrySpec{MetricsNamespace: "Fleet", MetricsPort: 9999},
+			},
+		},
+	}
+
+	replaced, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	config, err := adapters.ConfigFromString(replaced)
+	require.NoError(t, err)
+	service := config["service"].(map[interface{}]interface{})
+	assert.Equal(t, "0.0.0.0:9999", service["telemetry"].(map[interface{}]interface{})["metrics"].(map[interface{}]interface{})["address"])
+	assert.Equal(t, map[interface{}]interface{}{
+		"receivers": []interface{}{"prometheus/self-telemetry"},
+		"exporters": []interface{}{"awsemf/self-telemetry"},
+	}, service["pipelines"].(map[interface{}]interface{})["metrics/self-telemetry"])
+	assert.Equal(t, map[interface{}]interface{}{
+		"namespace":      "Fleet",
+		"log_group_name": "/aws/cloudwatch-agent/amazon-cloudwatch/agent",
+	}, config["exporters"].(map[interface{}]interface{})["awsemf/self-telemetry"])
+	assert.Contains(t, replaced, "localhost:9999")
+
+	// rendering is idempotent
+	agent.Spec.OtelConfig = replaced
+	again, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+	assert.Equal(t, replaced, again)
+}
+
+func TestSelfTelemetryRollsOutThePods(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config:     `{"agent":{"logfile":"/tmp/agent.log"}}`,
+			OtelConfig: "receivers:\n  otlp:\nexporters:\n  awsemf:\nservice:\n  pipelines:\n    metrics:\n      receivers: [otlp]\n      exporters: [awsemf]\n",
+		},
+	}
+	hash := ConfigHash(config.New(), agent)
+
+	// the pods are rolled out by the hash of the rendered configs, which the self-telemetry changes
+	agent.Spec.Observability.SelfTelemetry = &v1alpha1.SelfTelemetrySpec{}
+	assert.NotEqual(t, hash, ConfigHash(config.New(), agent))
+}
@@ -29,24 +29,28 @@ func HeadlessService(params manifests.Params) (*corev1.Service, error) {
 		return h, err
 	}
 
-	h.Name = naming.HeadlessService(params.OtelCol.Name)
+	h.Name = naming.HeadlessService(params.OtelCol.ResourceName())
 	h.Labels[headlessLabel] = headlessExists
 
-	// copy to avoid modifying params.OtelCol.Annotations
+	// copy to avoid modifying params.OtelCol.Annotations, the service annotations only apply to the exposed service
 	annotations := map[string]string{
 		"service.beta.openshift.io/serving-cert-secret-name": fmt.Sprintf("%s-tls", h.Name),
 	}
-	for k, v := range h.Annotations {
+	for k, v := range params.OtelCol.Annotations {
 		annotations[k] = v
 	}
 	h.Annotations = annotations
 
 	h.Spec.ClusterIP = "None"
+	h.Spec.Type = ""
+	for i := range h.Spec.Ports {
+		h.Spec.Ports[i].NodePort = 0
+	}
 	return h, nil
 }
 
 func MonitoringService(params manifests.Params) (*corev1.Service, error) {
-	name := naming.MonitoringService(params.OtelCol.Name)
+	name := naming.MonitoringService(params.OtelCol.ResourceName())
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
 
 	c, err := adapters.ConfigFromString(params.OtelCol.Spec.Config)
@@ -59,6 +63,9 @@ func MonitoringService(params manifests.Params) (*corev1.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	if selfTelemetry := params.OtelCol.Spec.Observability.SelfTelemetry; selfTelemetry != nil {
+		metricsPort = selfTelemetryPort(selfTelemetry)
+	}
 
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -74,12 +81,14 @@ func MonitoringService(params manifests.Params) (*corev1.Service, error) {
 				Name: "monitoring",
 				Port: metricsPort,
 			}},
+			IPFamilies:     params.OtelCol.Spec.IPFamilies,
+			IPFamilyPolicy: params.OtelCol.Spec.IPFamilyPolicy,
 		},
 	}, nil
 }
 
 func Service(params manifests.Params) (*corev1.Service, error) {
-	name := naming.Service(params.OtelCol.Name)
+	name := naming.Service(params.OtelCol.ResourceName())
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
 
 	ports := getContainerPorts(params.Log, params.OtelCol.Spec.Config, params.OtelCol.Spec.OtelConfig, params.OtelCol.Spec.Ports)
@@ -96,22 +105,51 @@ func Service(params manifests.Params) (*corev1.Service, error) {
 		trafficPolicy = corev1.ServiceInternalTrafficPolicyLocal
 	}
 
+	annotations := params.OtelCol.Annotations
+	if len(params.OtelCol.Spec.ServiceAnnotations) > 0 {
+		// copy to avoid modifying params.OtelCol.Annotations
+		annotations = map[string]string{}
+		for k, v := range params.OtelCol.Annotations {
+			annotations[k] = v
+		}
+		for k, v := range params.OtelCol.Spec.ServiceAnnotations {
+			annotations[k] = v
+		}
+	}
+
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        naming.Service(params.OtelCol.Name),
+			Name:        naming.Service(params.OtelCol.ResourceName()),
 			Namespace:   params.OtelCol.Namespace,
 			Labels:      labels,
-			Annotations: params.OtelCol.Annotations,
+			Annotations: annotations,
 		},
 		Spec: corev1.ServiceSpec{
+			Type:                  params.OtelCol.Spec.ServiceType,
 			InternalTrafficPolicy: &trafficPolicy,
 			Selector:              manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, ComponentAmazonCloudWatchAgent),
 			ClusterIP:             "",
-			Ports:                 containerPortsToServicePortList(ports),
+			Ports:                 withSpecPortFields(containerPortsToServicePortList(ports), params.OtelCol.Spec.Ports),
+			IPFamilies:            params.OtelCol.Spec.IPFamilies,
+			IPFamilyPolicy:        params.OtelCol.Spec.IPFamilyPolicy,
 		},
 	}, nil
 }
 
+// withSpecPortFields sets the appProtocol and the nodePort of the ports of the spec on the service ports of the same
+// name, which the container ports they are built from don't carry.
+func withSpecPortFields(ports []corev1.ServicePort, specPorts []corev1.ServicePort) []corev1.ServicePort {
+	for i := range ports {
+		for _, p := range specPorts {
+			if p.Name == ports[i].Name {
+				ports[i].AppProtocol = p.AppProtocol
+				ports[i].NodePort = p.NodePort
+			}
+		}
+	}
+	return ports
+}
+
 func containerPortsToServicePortList(portMap map[string]corev1.ContainerPort) []corev1.ServicePort {
 	var ports []corev1.ServicePort
 	for _, p := range portMap {
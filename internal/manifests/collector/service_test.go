@@ -219,6 +219,19 @@ func TestMonitoringService(t *testing.T) {
 		assert.NotNil(t, actual)
 		assert.Equal(t, expected, actual.Spec.Ports)
 	})
+
+	t.Run("returned the service in the self-telemetry port", func(t *testing.T) {
+		expected := []v1.ServicePort{{
+			Name: "monitoring",
+			Port: 9999,
+		}}
+		params := deploymentParams()
+		params.OtelCol.Spec.Observability.SelfTelemetry = &v1alpha1.SelfTelemetrySpec{MetricsPort: 9999}
+
+		actual, err := MonitoringService(params)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual.Spec.Ports)
+	})
 }
 
 func service(name string, ports []v1.ServicePort) v1.Service {
@@ -11,12 +11,13 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
 // ServiceAccountName returns the name of the existing or self-provisioned service account to use for the given instance.
 func ServiceAccountName(instance v1alpha1.AmazonCloudWatchAgent) string {
 	if len(instance.Spec.ServiceAccount) == 0 {
-		return naming.ServiceAccount(instance.Name)
+		return naming.ServiceAccount(instance.ResourceName())
 	}
 
 	return instance.Spec.ServiceAccount
@@ -27,7 +28,7 @@ func ServiceAccount(params manifests.Params) *corev1.ServiceAccount {
 	if params.OtelCol.Spec.ServiceAccount != "" {
 		return nil
 	}
-	name := naming.ServiceAccount(params.OtelCol.Name)
+	name := naming.ServiceAccount(params.OtelCol.ResourceName())
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
 
 	return &corev1.ServiceAccount{
@@ -35,7 +36,26 @@ func ServiceAccount(params manifests.Params) *corev1.ServiceAccount {
 			Name:        name,
 			Namespace:   params.OtelCol.Namespace,
 			Labels:      labels,
-			Annotations: params.OtelCol.Annotations,
+			Annotations: serviceAccountAnnotations(params.OtelCol),
 		},
 	}
 }
+
+// serviceAccountAnnotations returns the annotations of the instance, overridden by the service account
+// annotations and the IRSA role.
+func serviceAccountAnnotations(instance v1alpha1.AmazonCloudWatchAgent) map[string]string {
+	if len(instance.Spec.ServiceAccountAnnotations) == 0 && instance.Spec.IAMRoleArn == "" {
+		return instance.Annotations
+	}
+	annotations := map[string]string{}
+	for k, v := range instance.Annotations {
+		annotations[k] = v
+	}
+	for k, v := range instance.Spec.ServiceAccountAnnotations {
+		annotations[k] = v
+	}
+	if instance.Spec.IAMRoleArn != "" {
+		annotations[constants.AnnotationIAMRoleArn] = instance.Spec.IAMRoleArn
+	}
+	return annotations
+}
@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 )
 
@@ -45,3 +46,28 @@ func TestServiceAccountOverride(t *testing.T) {
 	// verify
 	assert.Equal(t, "my-special-sa", sa)
 }
+
+func TestServiceAccountIRSA(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-instance",
+			Annotations: map[string]string{"team": "a"},
+		},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			ServiceAccountAnnotations: map[string]string{"eks.amazonaws.com/sts-regional-endpoints": "true"},
+			IAMRoleArn:                "arn:aws:iam::123456789012:role/agent",
+		},
+	}
+
+	// test
+	sa := ServiceAccount(manifests.Params{OtelCol: otelcol})
+
+	// verify
+	assert.Equal(t, map[string]string{
+		"team": "a",
+		"eks.amazonaws.com/sts-regional-endpoints": "true",
+		"eks.amazonaws.com/role-arn":               "arn:aws:iam::123456789012:role/agent",
+	}, sa.Annotations)
+	assert.Len(t, otelcol.Annotations, 1)
+}
@@ -32,9 +32,9 @@ func ServiceMonitor(params manifests.Params) (*monitoringv1.ServiceMonitor, erro
 	sm = monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: params.OtelCol.Namespace,
-			Name:      naming.ServiceMonitor(params.OtelCol.Name),
+			Name:      naming.ServiceMonitor(params.OtelCol.ResourceName()),
 			Labels: map[string]string{
-				"app.kubernetes.io/name":       naming.ServiceMonitor(params.OtelCol.Name),
+				"app.kubernetes.io/name":       naming.ServiceMonitor(params.OtelCol.ResourceName()),
 				"app.kubernetes.io/instance":   fmt.Sprintf("%s.%s", params.OtelCol.Namespace, params.OtelCol.Name),
 				"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
 			},
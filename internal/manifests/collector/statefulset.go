@@ -15,11 +15,12 @@ import (
 
 // StatefulSet builds the statefulset for the given instance.
 func StatefulSet(params manifests.Params) *appsv1.StatefulSet {
-	name := naming.Collector(params.OtelCol.Name)
+	name := naming.Collector(params.OtelCol.ResourceName())
 	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter())
 
-	annotations := Annotations(params.OtelCol)
-	podAnnotations := PodAnnotations(params.OtelCol)
+	annotations := Annotations(params.Config, params.OtelCol)
+	containers := podContainers(params.Config, params.Log, params.OtelCol)
+	podAnnotations := withAppArmorProfile(params.OtelCol, PodAnnotations(params.Config, params.OtelCol), params.OtelCol.Spec.InitContainers, containers)
 
 	return &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -29,28 +30,32 @@ func StatefulSet(params manifests.Params) *appsv1.StatefulSet {
 			Annotations: annotations,
 		},
 		Spec: appsv1.StatefulSetSpec{
-			ServiceName: naming.Service(params.OtelCol.Name),
+			ServiceName: naming.Service(params.OtelCol.ResourceName()),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: manifestutils.SelectorLabels(params.OtelCol.ObjectMeta, ComponentAmazonCloudWatchAgent),
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      labels,
+					Labels:      podLabels(params.Config, params.OtelCol, labels),
 					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName:        ServiceAccountName(params.OtelCol),
-					InitContainers:            params.OtelCol.Spec.InitContainers,
-					Containers:                append(params.OtelCol.Spec.AdditionalContainers, Container(params.Config, params.Log, params.OtelCol, true)),
-					Volumes:                   Volumes(params.Config, params.OtelCol),
-					DNSPolicy:                 getDNSPolicy(params.OtelCol),
-					HostNetwork:               params.OtelCol.Spec.HostNetwork,
-					Tolerations:               params.OtelCol.Spec.Tolerations,
-					NodeSelector:              params.OtelCol.Spec.NodeSelector,
-					SecurityContext:           params.OtelCol.Spec.PodSecurityContext,
-					PriorityClassName:         params.OtelCol.Spec.PriorityClassName,
-					Affinity:                  params.OtelCol.Spec.Affinity,
-					TopologySpreadConstraints: params.OtelCol.Spec.TopologySpreadConstraints,
+					ServiceAccountName:            ServiceAccountName(params.OtelCol),
+					InitContainers:                params.OtelCol.Spec.InitContainers,
+					Containers:                    containers,
+					Volumes:                       Volumes(params.Config, params.OtelCol),
+					DNSPolicy:                     getDNSPolicy(params.OtelCol),
+					HostNetwork:                   params.OtelCol.Spec.HostNetwork,
+					HostPID:                       params.OtelCol.Spec.HostPID,
+					HostIPC:                       params.OtelCol.Spec.HostIPC,
+					Tolerations:                   params.OtelCol.Spec.Tolerations,
+					NodeSelector:                  params.OtelCol.Spec.NodeSelector,
+					SecurityContext:               podSecurityContext(params.OtelCol),
+					ShareProcessNamespace:         params.OtelCol.SharedProcessNamespace(),
+					PriorityClassName:             params.OtelCol.Spec.PriorityClassName,
+					Affinity:                      params.OtelCol.Spec.Affinity,
+					TerminationGracePeriodSeconds: params.OtelCol.Spec.TerminationGracePeriodSeconds,
+					TopologySpreadConstraints:     params.OtelCol.Spec.TopologySpreadConstraints,
 				},
 			},
 			Replicas:             params.OtelCol.Spec.Replicas,
@@ -0,0 +1,252 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	tlsDirectory        = "/etc/amazon-cloudwatch-agent-tls"
+	tlsWindowsDirectory = "C:\\Program Files\\Amazon\\AmazonCloudWatchAgent\\tls"
+
+	certManagerGroup = "cert-manager.io"
+
+	// TLSHashAnnotation is the pod annotation holding the hash of the certificate of the receivers, so that the
+	// pods roll out, and the agent serves the renewed certificate, when the Secret changes.
+	TLSHashAnnotation = "amazon-cloudwatch-agent-operator-tls/sha256"
+)
+
+// tlsReceiverSections are the sections of the agent config whose receivers serve the certificate.
+var tlsReceiverSections = [][]string{
+	{"logs", "metrics_collected", "otlp"},
+	{"logs", "metrics_collected", "application_signals"},
+	{"logs", "metrics_collected", "app_signals"},
+	{"traces", "traces_collected", "otlp"},
+	{"traces", "traces_collected", "application_signals"},
+	{"traces", "traces_collected", "app_signals"},
+}
+
+// TLSSecretName returns the name of the Secret holding the certificate of the receivers in the namespace of the
+// instance, or an empty string when TLS is disabled.
+func TLSSecretName(agent v1alpha1.AmazonCloudWatchAgent) string {
+	if agent.Spec.TLS == nil {
+		return ""
+	}
+	if agent.Spec.TLS.SecretName != "" {
+		return referenceName(agent, agent.Spec.TLS.SecretNamespace, agent.Spec.TLS.SecretName)
+	}
+	if agent.Spec.TLS.CertManager != nil {
+		return naming.TLSSecret(agent.ResourceName())
+	}
+	return ""
+}
+
+// TLSSecretSource returns the namespace and the name of the Secret the certificate of the receivers is read from,
+// which is copied into the namespace of the instance when it is another one, or empty strings when TLS is disabled.
+func TLSSecretSource(agent v1alpha1.AmazonCloudWatchAgent) (string, string) {
+	if agent.Spec.TLS == nil {
+		return "", ""
+	}
+	if agent.Spec.TLS.SecretName != "" {
+		if isCrossNamespace(agent, agent.Spec.TLS.SecretNamespace) {
+			return agent.Spec.TLS.SecretNamespace, agent.Spec.TLS.SecretName
+		}
+		return agent.Namespace, agent.Spec.TLS.SecretName
+	}
+	if agent.Spec.TLS.CertManager != nil {
+		return agent.Namespace, naming.TLSSecret(agent.ResourceName())
+	}
+	return "", ""
+}
+
+// WithTLSHash returns a copy of the given agent whose pods are annotated with the hash of the data of the Secret
+// holding the certificate of the receivers.
+func WithTLSHash(agent v1alpha1.AmazonCloudWatchAgent, secret *corev1.Secret) v1alpha1.AmazonCloudWatchAgent {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s=%d:", key, len(secret.Data[key]))
+		h.Write(secret.Data[key])
+	}
+
+	podAnnotations := make(map[string]string, len(agent.Spec.PodAnnotations)+1)
+	for k, v := range agent.Spec.PodAnnotations {
+		podAnnotations[k] = v
+	}
+	podAnnotations[TLSHashAnnotation] = fmt.Sprintf("%x", h.Sum(nil))
+	agent.Spec.PodAnnotations = podAnnotations
+	return agent
+}
+
+// tlsFiles returns the paths of the certificate and key files mounted into the agent container.
+func tlsFiles(agent v1alpha1.AmazonCloudWatchAgent) (string, string) {
+	if agent.Spec.NodeSelector["kubernetes.io/os"] == "windows" {
+		return tlsWindowsDirectory + "\\" + corev1.TLSCertKey, tlsWindowsDirectory + "\\" + corev1.TLSPrivateKeyKey
+	}
+	return tlsDirectory + "/" + corev1.TLSCertKey, tlsDirectory + "/" + corev1.TLSPrivateKeyKey
+}
+
+// tlsVolumes returns the volume of the Secret holding the certificate of the receivers.
+func tlsVolumes(agent v1alpha1.AmazonCloudWatchAgent) []corev1.Volume {
+	secretName := TLSSecretName(agent)
+	if secretName == "" {
+		return nil
+	}
+	return []corev1.Volume{{
+		Name: naming.TLSVolume(),
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}}
+}
+
+// tlsVolumeMounts returns the mount of the volume returned by tlsVolumes.
+func tlsVolumeMounts(agent v1alpha1.AmazonCloudWatchAgent) []corev1.VolumeMount {
+	if TLSSecretName(agent) == "" {
+		return nil
+	}
+	mountPath := tlsDirectory
+	if agent.Spec.NodeSelector["kubernetes.io/os"] == "windows" {
+		mountPath = tlsWindowsDirectory
+	}
+	return []corev1.VolumeMount{{
+		Name:      naming.TLSVolume(),
+		MountPath: mountPath,
+		ReadOnly:  true,
+	}}
+}
+
+// configWithTLS sets the certificate on the otlp and Application Signals receivers of the given agent config,
+// unless the receivers already configure one.
+func configWithTLS(config map[string]interface{}, certFile, keyFile string) {
+	for _, path := range tlsReceiverSections {
+		section := config
+		for _, key := range path {
+			next, ok := section[key].(map[string]interface{})
+			if !ok {
+				section = nil
+				break
+			}
+			section = next
+		}
+		if section == nil {
+			continue
+		}
+		if _, ok := section["tls"]; !ok {
+			section["tls"] = map[string]interface{}{
+				"cert_file": certFile,
+				"key_file":  keyFile,
+			}
+		}
+	}
+}
+
+// otelConfigWithTLS sets the certificate on the protocols enabled on each otlp receiver of the given
+// configuration, unless the protocols already configure one.
+func otelConfigWithTLS(config map[interface{}]interface{}, certFile, keyFile string) {
+	receivers, ok := config["receivers"].(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	for k, v := range receivers {
+		name, ok := k.(string)
+		if !ok || (name != otlpReceiver && !strings.HasPrefix(name, otlpReceiver+"/")) {
+			continue
+		}
+		receiver, ok := v.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		protocols, ok := receiver["protocols"].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		for _, protocol := range []string{"grpc", "http"} {
+			settings, ok := protocolSettings(protocols, protocol)
+			if !ok {
+				continue
+			}
+			if _, ok := settings["tls"]; !ok {
+				settings["tls"] = map[interface{}]interface{}{
+					"cert_file": certFile,
+					"key_file":  keyFile,
+				}
+			}
+		}
+	}
+}
+
+// Certificate returns the cert-manager Certificate issuing the certificate of the receivers into the TLS Secret.
+func Certificate(params manifests.Params) *unstructured.Unstructured {
+	if params.OtelCol.Spec.TLS == nil || params.OtelCol.Spec.TLS.CertManager == nil {
+		return nil
+	}
+	certManager := params.OtelCol.Spec.TLS.CertManager
+	name := naming.Certificate(params.OtelCol.ResourceName())
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, []string{})
+
+	var dnsNames []interface{}
+	for _, service := range []string{naming.Service(params.OtelCol.ResourceName()), naming.HeadlessService(params.OtelCol.ResourceName())} {
+		dnsNames = append(dnsNames,
+			service,
+			fmt.Sprintf("%s.%s", service, params.OtelCol.Namespace),
+			fmt.Sprintf("%s.%s.svc", service, params.OtelCol.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service, params.OtelCol.Namespace),
+		)
+	}
+	for _, dnsName := range certManager.DNSNames {
+		dnsNames = append(dnsNames, dnsName)
+	}
+
+	issuerRef := map[string]interface{}{
+		"name":  certManager.IssuerRef.Name,
+		"kind":  "Issuer",
+		"group": certManagerGroup,
+	}
+	if certManager.IssuerRef.Kind != "" {
+		issuerRef["kind"] = certManager.IssuerRef.Kind
+	}
+	if certManager.IssuerRef.Group != "" {
+		issuerRef["group"] = certManager.IssuerRef.Group
+	}
+
+	spec := map[string]interface{}{
+		"secretName": TLSSecretName(params.OtelCol),
+		"dnsNames":   dnsNames,
+		"issuerRef":  issuerRef,
+		"usages":     []interface{}{"server auth"},
+	}
+	if certManager.Duration != nil {
+		spec["duration"] = certManager.Duration.Duration.String()
+	}
+	if certManager.RenewBefore != nil {
+		spec["renewBefore"] = certManager.RenewBefore.Duration.String()
+	}
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	certificate.SetAPIVersion(certManagerGroup + "/v1")
+	certificate.SetKind("Certificate")
+	certificate.SetName(name)
+	certificate.SetNamespace(params.OtelCol.Namespace)
+	certificate.SetLabels(labels)
+	return certificate
+}
@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+func TestTLSConfig(t *testing.T) {
+	// prepare
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"logs":{"metrics_collected":{"application_signals":{}}},` +
+				`"traces":{"traces_collected":{"otlp":{"tls":{"cert_file":"/custom.crt","key_file":"/custom.key"}}}}}`,
+			OtelConfig: `receivers:
+  otlp:
+    protocols:
+      grpc:
+      http:
+        tls:
+          cert_file: /custom.crt
+`,
+			TLS: &v1alpha1.TLSSpec{SecretName: "agent-cert"},
+		},
+	}
+
+	// test
+	replacedConfig, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+	replacedOtelConfig, err := ReplaceOtelConfig(agent)
+	require.NoError(t, err)
+
+	// verify
+	expectedTLS := map[string]interface{}{"cert_file": "/etc/amazon-cloudwatch-agent-tls/tls.crt", "key_file": "/etc/amazon-cloudwatch-agent-tls/tls.key"}
+	cwaConfig, err := adapters.ConfigFromJSONString(replacedConfig)
+	require.NoError(t, err)
+	appSignals := cwaConfig["logs"].(map[string]interface{})["metrics_collected"].(map[string]interface{})["application_signals"].(map[string]interface{})
+	assert.Equal(t, expectedTLS, appSignals["tls"])
+	otlp := cwaConfig["traces"].(map[string]interface{})["traces_collected"].(map[string]interface{})["otlp"].(map[string]interface{})
+	assert.Equal(t, "/custom.crt", otlp["tls"].(map[string]interface{})["cert_file"])
+
+	otelConfig, err := adapters.ConfigFromString(replacedOtelConfig)
+	require.NoError(t, err)
+	protocols := otelConfig["receivers"].(map[interface{}]interface{})["otlp"].(map[interface{}]interface{})["protocols"].(map[interface{}]interface{})
+	assert.Equal(t, map[interface{}]interface{}{
+		"cert_file": "/etc/amazon-cloudwatch-agent-tls/tls.crt",
+		"key_file":  "/etc/amazon-cloudwatch-agent-tls/tls.key",
+	}, protocols["grpc"].(map[interface{}]interface{})["tls"])
+	assert.Equal(t, map[interface{}]interface{}{"cert_file": "/custom.crt"}, protocols["http"].(map[interface{}]interface{})["tls"])
+
+	volumes := Volumes(config.New(), agent)
+	assert.Equal(t, naming.TLSVolume(), volumes[len(volumes)-1].Name)
+	assert.Equal(t, "agent-cert", volumes[len(volumes)-1].Secret.SecretName)
+	mounts := tlsVolumeMounts(agent)
+	require.Len(t, mounts, 1)
+	assert.Equal(t, "/etc/amazon-cloudwatch-agent-tls", mounts[0].MountPath)
+}
+
+func TestCertificate(t *testing.T) {
+	// prepare
+	params := manifests.Params{
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				TLS: &v1alpha1.TLSSpec{
+					CertManager: &v1alpha1.CertManagerSpec{
+						IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "ca", Kind: "ClusterIssuer"},
+						DNSNames:  []string{"agent.example.com"},
+						Duration:  &metav1.Duration{Duration: 24 * time.Hour},
+					},
+				},
+			},
+		},
+	}
+
+	// test
+	certificate := Certificate(params)
+
+	// verify
+	require.NotNil(t, certificate)
+	assert.Equal(t, "cert-manager.io/v1", certificate.GetAPIVersion())
+	assert.Equal(t, "Certificate", certificate.GetKind())
+	assert.Equal(t, "agent", certificate.GetName())
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	assert.Equal(t, "agent-tls", secretName)
+	issuerRef, _, _ := unstructured.NestedStringMap(certificate.Object, "spec", "issuerRef")
+	assert.Equal(t, map[string]string{"name": "ca", "kind": "ClusterIssuer", "group": "cert-manager.io"}, issuerRef)
+	dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	assert.Contains(t, dnsNames, "agent.amazon-cloudwatch.svc")
+	assert.Contains(t, dnsNames, "agent-headless.amazon-cloudwatch.svc.cluster.local")
+	assert.Contains(t, dnsNames, "agent.example.com")
+	duration, _, _ := unstructured.NestedString(certificate.Object, "spec", "duration")
+	assert.Equal(t, "24h0m0s", duration)
+
+	// no certificate is requested for a user provided secret
+	params.OtelCol.Spec.TLS = &v1alpha1.TLSSpec{SecretName: "agent-cert"}
+	assert.Nil(t, Certificate(params))
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+const (
+	trustedCADirectory = "/etc/amazon-cloudwatch-agent-trusted-ca"
+	trustedCAFile      = "ca.crt"
+
+	// trustedCACertDirectories are the directories the agent loads the trusted certificate authorities from: the
+	// mounted bundle, followed by the default certificate directories of Linux, which SSL_CERT_DIR replaces. The
+	// default bundle files of the image are still loaded along with them.
+	trustedCACertDirectories = trustedCADirectory + ":/etc/ssl/certs:/etc/pki/tls/certs"
+)
+
+// trustedCAVolumes returns the volume of the ConfigMap or the Secret holding the bundle of the trusted certificate
+// authorities, projected to a single file whatever its key.
+func trustedCAVolumes(agent v1alpha1.AmazonCloudWatchAgent) []corev1.Volume {
+	trustedCA := agent.Spec.TrustedCA
+	if trustedCA == nil {
+		return nil
+	}
+	key := trustedCA.Key
+	if key == "" {
+		key = v1alpha1.DefaultTrustedCAKey
+	}
+	items := []corev1.KeyToPath{{Key: key, Path: trustedCAFile}}
+	var source corev1.VolumeSource
+	if trustedCA.Secret != "" {
+		source.Secret = &corev1.SecretVolumeSource{SecretName: referenceName(agent, trustedCA.Namespace, trustedCA.Secret), Items: items}
+	} else {
+		source.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: referenceName(agent, trustedCA.Namespace, trustedCA.ConfigMap)},
+			Items:                items,
+		}
+	}
+	return []corev1.Volume{{Name: naming.TrustedCAVolume(), VolumeSource: source}}
+}
+
+// trustedCAVolumeMounts returns the mount of the volume returned by trustedCAVolumes.
+func trustedCAVolumeMounts(agent v1alpha1.AmazonCloudWatchAgent) []corev1.VolumeMount {
+	if agent.Spec.TrustedCA == nil {
+		return nil
+	}
+	return []corev1.VolumeMount{{
+		Name:      naming.TrustedCAVolume(),
+		MountPath: trustedCADirectory,
+		ReadOnly:  true,
+	}}
+}
+
+// trustedCAEnvVars returns the environment variables making the agent trust the mounted bundle on top of the
+// certificate authorities of its image, for the TLS connections to the AWS services and to the OTLP backends alike.
+func trustedCAEnvVars(agent v1alpha1.AmazonCloudWatchAgent) []corev1.EnvVar {
+	if agent.Spec.TrustedCA == nil {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: "SSL_CERT_DIR", Value: trustedCACertDirectories}}
+}
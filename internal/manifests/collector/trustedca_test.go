@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestTrustedCA(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			TrustedCA: &v1alpha1.TrustedCASpec{ConfigMap: "corporate-ca", Key: "ca-bundle.crt"},
+		},
+	}
+
+	volumes := Volumes(config.New(), agent)
+	assert.Contains(t, volumes, corev1.Volume{
+		Name: "trusted-ca",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "corporate-ca"},
+			Items:                []corev1.KeyToPath{{Key: "ca-bundle.crt", Path: "ca.crt"}},
+		}},
+	})
+	container := Container(config.New(), logger, agent, true)
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "trusted-ca", MountPath: "/etc/amazon-cloudwatch-agent-trusted-ca", ReadOnly: true})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: "/etc/amazon-cloudwatch-agent-trusted-ca:/etc/ssl/certs:/etc/pki/tls/certs"})
+
+	// the SSL_CERT_DIR set by the users takes precedence
+	agent.Spec.Env = []corev1.EnvVar{{Name: "SSL_CERT_DIR", Value: "/etc/custom"}}
+	container = Container(config.New(), logger, agent, true)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: "/etc/custom"})
+	assert.NotContains(t, container.Env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: "/etc/amazon-cloudwatch-agent-trusted-ca:/etc/ssl/certs:/etc/pki/tls/certs"})
+}
+
+func TestTrustedCASecretOfAnotherNamespace(t *testing.T) {
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			TrustedCA: &v1alpha1.TrustedCASpec{Secret: "corporate-ca", Namespace: "certificates"},
+		},
+	}
+
+	assert.Equal(t, []Reference{{Secret: true, Namespace: "certificates", Name: "corporate-ca"}}, CrossNamespaceReferences(agent))
+	assert.Contains(t, Volumes(config.New(), agent), corev1.Volume{
+		Name: "trusted-ca",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+			SecretName: "agent-certificates-corporate-ca",
+			Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+		}},
+	})
+}
@@ -11,7 +11,7 @@ import (
 
 func getDNSPolicy(otelcol v1alpha1.AmazonCloudWatchAgent) corev1.DNSPolicy {
 	dnsPolicy := corev1.DNSClusterFirst
-	if otelcol.Spec.HostNetwork {
+	if hostNetwork(otelcol) {
 		dnsPolicy = corev1.DNSClusterFirstWithHostNet
 	}
 	return dnsPolicy
@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// CohortCanary is the value of the cohort label of the agents of the canary cohort of a version split.
+const CohortCanary = "canary"
+
+// CanaryDaemonSet builds the daemonset running the image of the version split on the nodes of the canary cohort.
+// The stable cohort keeps running in the daemonset of the instance, which avoids these nodes.
+func CanaryDaemonSet(params manifests.Params) *appsv1.DaemonSet {
+	split := params.OtelCol.Spec.VersionSplit
+	if split == nil || params.OtelCol.Spec.Mode != v1alpha1.ModeDaemonSet {
+		return nil
+	}
+
+	canaryParams := params
+	canaryParams.OtelCol = *params.OtelCol.DeepCopy()
+	canaryParams.OtelCol.Spec.Image = split.Image
+	canaryParams.OtelCol.Spec.VersionSplit = nil
+	ds := DaemonSet(canaryParams)
+
+	ds.Name = naming.CanaryCollector(params.OtelCol.ResourceName())
+	ds.Labels[constants.LabelCohort] = CohortCanary
+	// the label tells the pods of the two daemonsets apart, while the services keep selecting both cohorts
+	ds.Spec.Selector.MatchLabels[constants.LabelCohort] = CohortCanary
+	ds.Spec.Template.Labels[constants.LabelCohort] = CohortCanary
+	ds.Spec.Template.Spec.Affinity = withNodeRequirement(ds.Spec.Template.Spec.Affinity, corev1.NodeSelectorRequirement{
+		Key:      split.NodeLabel.Key,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{split.NodeLabel.Value},
+	})
+	return ds
+}
+
+// withNodeRequirement returns a copy of the affinity where every required node selector term also requires the
+// given node label requirement.
+func withNodeRequirement(affinity *corev1.Affinity, requirement corev1.NodeSelectorRequirement) *corev1.Affinity {
+	return withEachNodeTerm(affinity, func(term *corev1.NodeSelectorTerm) {
+		term.MatchExpressions = append(term.MatchExpressions, requirement)
+	})
+}
+
+// withEachNodeTerm returns a copy of the affinity where every required node selector term is changed by the given
+// function.
+func withEachNodeTerm(affinity *corev1.Affinity, change func(term *corev1.NodeSelectorTerm)) *corev1.Affinity {
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	} else {
+		affinity = affinity.DeepCopy()
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		required = &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{}}}
+	}
+	// the terms are ORed, the requirement must be added to each of them
+	for i := range required.NodeSelectorTerms {
+		change(&required.NodeSelectorTerms[i])
+	}
+	affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	return affinity
+}
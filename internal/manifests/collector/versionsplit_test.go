@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestVersionSplit(t *testing.T) {
+	zone := corev1.NodeSelectorRequirement{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us-west-2a"}}
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode:  v1alpha1.ModeDaemonSet,
+				Image: "cloudwatch-agent:1",
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zone}}},
+					},
+				}},
+				VersionSplit: &v1alpha1.VersionSplitSpec{
+					Image:     "cloudwatch-agent:2",
+					NodeLabel: v1alpha1.NodeLabel{Key: "agent-cohort", Value: "canary"},
+				},
+			},
+		},
+	}
+
+	stable := DaemonSet(params)
+	assert.Equal(t, "agent", stable.Name)
+	assert.Equal(t, "cloudwatch-agent:1", stable.Spec.Template.Spec.Containers[0].Image)
+	assert.NotContains(t, stable.Spec.Template.Labels, constants.LabelCohort)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		zone,
+		{Key: "agent-cohort", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"canary"}},
+	}, stable.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+
+	canary := CanaryDaemonSet(params)
+	require.NotNil(t, canary)
+	assert.Equal(t, "agent-canary", canary.Name)
+	assert.Equal(t, "cloudwatch-agent:2", canary.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, CohortCanary, canary.Spec.Selector.MatchLabels[constants.LabelCohort])
+	assert.Equal(t, CohortCanary, canary.Spec.Template.Labels[constants.LabelCohort])
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		zone,
+		{Key: "agent-cohort", Operator: corev1.NodeSelectorOpIn, Values: []string{"canary"}},
+	}, canary.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+
+	// the affinity of the instance is left untouched
+	assert.Len(t, params.OtelCol.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+
+	params.OtelCol.Spec.VersionSplit = nil
+	assert.Nil(t, CanaryDaemonSet(params))
+	assert.Equal(t, params.OtelCol.Spec.Affinity, DaemonSet(params).Spec.Template.Spec.Affinity)
+}
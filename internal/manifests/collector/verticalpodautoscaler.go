@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// VerticalPodAutoscalerGroup is the API group of the VerticalPodAutoscaler objects.
+const VerticalPodAutoscalerGroup = "autoscaling.k8s.io"
+
+// VerticalPodAutoscaler returns the VerticalPodAutoscaler adjusting the resource requests of the agent container.
+func VerticalPodAutoscaler(params manifests.Params) *unstructured.Unstructured {
+	if params.OtelCol.Spec.Autoscaler == nil || params.OtelCol.Spec.Autoscaler.Vertical == nil {
+		return nil
+	}
+	var kind string
+	switch params.OtelCol.Spec.Mode {
+	case v1alpha1.ModeDaemonSet:
+		kind = "DaemonSet"
+	case v1alpha1.ModeDeployment:
+		kind = "Deployment"
+	case v1alpha1.ModeStatefulSet:
+		kind = "StatefulSet"
+	default:
+		return nil
+	}
+	vertical := params.OtelCol.Spec.Autoscaler.Vertical
+	name := naming.VerticalPodAutoscaler(params.OtelCol.ResourceName())
+	labels := manifestutils.Labels(params.OtelCol.ObjectMeta, name, params.OtelCol.Spec.Image, ComponentAmazonCloudWatchAgent, params.Config.LabelsFilter())
+
+	updateMode := vertical.UpdateMode
+	if updateMode == "" {
+		updateMode = "Auto"
+	}
+	containerPolicy := map[string]interface{}{
+		"containerName": naming.Container(),
+	}
+	if len(vertical.MinAllowed) > 0 {
+		containerPolicy["minAllowed"] = resourceListObject(vertical.MinAllowed)
+	}
+	if len(vertical.MaxAllowed) > 0 {
+		containerPolicy["maxAllowed"] = resourceListObject(vertical.MaxAllowed)
+	}
+	spec := map[string]interface{}{
+		"targetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       kind,
+			"name":       naming.Collector(params.OtelCol.ResourceName()),
+		},
+		"updatePolicy": map[string]interface{}{
+			"updateMode": updateMode,
+		},
+		"resourcePolicy": map[string]interface{}{
+			"containerPolicies": []interface{}{containerPolicy},
+		},
+	}
+
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	vpa.SetAPIVersion(VerticalPodAutoscalerGroup + "/v1")
+	vpa.SetKind("VerticalPodAutoscaler")
+	vpa.SetName(name)
+	vpa.SetNamespace(params.OtelCol.Namespace)
+	vpa.SetLabels(labels)
+	return vpa
+}
+
+// resourceListObject converts the resource list to its unstructured representation.
+func resourceListObject(resources corev1.ResourceList) map[string]interface{} {
+	object := map[string]interface{}{}
+	for name, quantity := range resources {
+		object[string(name)] = quantity.String()
+	}
+	return object
+}
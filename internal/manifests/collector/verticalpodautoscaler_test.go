@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestVerticalPodAutoscaler(t *testing.T) {
+	params := manifests.Params{
+		Config: config.New(),
+		Log:    logr.Discard(),
+		OtelCol: v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+			Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+				Mode: v1alpha1.ModeDaemonSet,
+				Autoscaler: &v1alpha1.AutoscalerSpec{Vertical: &v1alpha1.VerticalAutoscalerSpec{
+					MinAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+					MaxAllowed: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi"), corev1.ResourceCPU: resource.MustParse("1")},
+				}},
+			},
+		},
+	}
+
+	vpa := VerticalPodAutoscaler(params)
+	require.NotNil(t, vpa)
+	assert.Equal(t, "autoscaling.k8s.io/v1", vpa.GetAPIVersion())
+	assert.Equal(t, "VerticalPodAutoscaler", vpa.GetKind())
+	assert.Equal(t, "agent", vpa.GetName())
+	assert.Equal(t, "amazon-cloudwatch", vpa.GetNamespace())
+
+	targetRef, _, _ := unstructured.NestedStringMap(vpa.Object, "spec", "targetRef")
+	assert.Equal(t, map[string]string{"apiVersion": "apps/v1", "kind": "DaemonSet", "name": "agent"}, targetRef)
+	updateMode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+	assert.Equal(t, "Auto", updateMode)
+	policies, _, _ := unstructured.NestedSlice(vpa.Object, "spec", "resourcePolicy", "containerPolicies")
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"containerName": "otc-container",
+		"minAllowed":    map[string]interface{}{"memory": "128Mi"},
+		"maxAllowed":    map[string]interface{}{"memory": "2Gi", "cpu": "1"},
+	}}, policies)
+	// the object must be copyable by the client
+	assert.NotPanics(t, func() { vpa.DeepCopy() })
+
+	// the vertical autoscaler doesn't render a horizontal one
+	assert.Nil(t, HorizontalPodAutoscaler(params))
+
+	params.OtelCol.Spec.Mode = v1alpha1.ModeStatefulSet
+	params.OtelCol.Spec.Autoscaler.Vertical.UpdateMode = "Initial"
+	vpa = VerticalPodAutoscaler(params)
+	kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+	assert.Equal(t, "StatefulSet", kind)
+	updateMode, _, _ = unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+	assert.Equal(t, "Initial", updateMode)
+
+	params.OtelCol.Spec.Mode = v1alpha1.ModeSidecar
+	assert.Nil(t, VerticalPodAutoscaler(params))
+
+	params.OtelCol.Spec.Mode = v1alpha1.ModeDaemonSet
+	params.OtelCol.Spec.Autoscaler = nil
+	assert.Nil(t, VerticalPodAutoscaler(params))
+}
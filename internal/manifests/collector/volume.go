@@ -28,11 +28,16 @@ func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1
 		})
 	}
 
+	// the entries the translator writes vary with the config, so the whole ConfigMap is projected
+	if configTranslated(cfg, otelcol) {
+		items = nil
+	}
+
 	volumes := []corev1.Volume{{
 		Name: naming.ConfigMapVolume(),
 		VolumeSource: corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
-				LocalObjectReference: corev1.LocalObjectReference{Name: naming.ConfigMap(otelcol.Name)},
+				LocalObjectReference: corev1.LocalObjectReference{Name: naming.ConfigMap(otelcol.ResourceName())},
 				Items:                items,
 			},
 		},
@@ -40,21 +45,17 @@ func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1
 
 	if !otelcol.Spec.Prometheus.IsEmpty() {
 		volumes = append(volumes, corev1.Volume{
-			Name: naming.PrometheusConfigMapVolume(),
-			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: naming.PrometheusConfigMap(otelcol.Name),
-					},
-					Items: []corev1.KeyToPath{{
-						Key:  cfg.PrometheusConfigMapEntry(),
-						Path: cfg.PrometheusConfigMapEntry(),
-					}},
-				},
-			},
+			Name:         naming.PrometheusConfigMapVolume(),
+			VolumeSource: prometheusConfigVolumeSource(cfg, otelcol),
 		})
 	}
 
+	volumes = append(volumes, bufferVolumes(otelcol)...)
+	volumes = append(volumes, logFileVolumes(otelcol)...)
+	volumes = append(volumes, tlsVolumes(otelcol)...)
+	volumes = append(volumes, trustedCAVolumes(otelcol)...)
+	volumes = append(volumes, extraMountVolumes(otelcol)...)
+
 	if len(otelcol.Spec.Volumes) > 0 {
 		volumes = append(volumes, otelcol.Spec.Volumes...)
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
@@ -119,3 +120,58 @@ func TestVolumeNoPrometheus(t *testing.T) {
 	// check that it's not the prometheus-config volume, with the config map
 	assert.NotEqual(t, naming.PrometheusConfigMapVolume(), volumes[0].Name)
 }
+
+func TestVolumeBufferDefault(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `extensions:
+  file_storage:
+    directory: /var/spool/otel`,
+		},
+	}
+
+	cfg := config.New()
+
+	// test
+	volumes := Volumes(cfg, otelcol)
+
+	// verify
+	assert.Len(t, volumes, 2)
+	assert.Equal(t, naming.BufferVolume(0), volumes[1].Name)
+	assert.Equal(t, corev1.StorageMediumDefault, volumes[1].EmptyDir.Medium)
+	assert.Equal(t, "1Gi", volumes[1].EmptyDir.SizeLimit.String())
+}
+
+func TestVolumeBufferMemory(t *testing.T) {
+	// prepare
+	sizeLimit := resource.MustParse("256Mi")
+	otelcol := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			OtelConfig: `extensions:
+  file_storage/a:
+    directory: /var/spool/a
+  file_storage/b:
+    directory: /var/spool/b`,
+			Buffer: v1alpha1.BufferSpec{
+				Storage: v1alpha1.BufferStorageSpec{
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: &sizeLimit,
+				},
+			},
+		},
+	}
+
+	cfg := config.New()
+
+	// test
+	volumes := Volumes(cfg, otelcol)
+
+	// verify
+	assert.Len(t, volumes, 3)
+	for i, volume := range volumes[1:] {
+		assert.Equal(t, naming.BufferVolume(i), volume.Name)
+		assert.Equal(t, corev1.StorageMediumMemory, volume.EmptyDir.Medium)
+		assert.Equal(t, "256Mi", volume.EmptyDir.SizeLimit.String())
+	}
+}
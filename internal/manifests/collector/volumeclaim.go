@@ -18,5 +18,5 @@ func VolumeClaimTemplates(otelcol v1alpha1.AmazonCloudWatchAgent) []corev1.Persi
 	}
 
 	// Add all user specified claims.
-	return otelcol.Spec.VolumeClaimTemplates
+	return append(otelcol.Spec.VolumeClaimTemplates, persistenceVolumeClaims(otelcol)...)
 }
@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+// windowsSystemUser is the user HostProcess containers run as unless the spec sets another one.
+const windowsSystemUser = "NT AUTHORITY\\SYSTEM"
+
+// collectsWindowsEvents returns whether the agent is a Windows DaemonSet collecting Windows event logs. The event
+// logs are only readable from the host, so its pods run as HostProcess containers.
+func collectsWindowsEvents(agent v1alpha1.AmazonCloudWatchAgent) bool {
+	if agent.Spec.Mode != v1alpha1.ModeDaemonSet || agent.Spec.NodeSelector["kubernetes.io/os"] != "windows" || agent.Spec.Config == "" {
+		return false
+	}
+	config, err := adapters.ConfigStructFromJSONString(agent.Spec.Config)
+	if err != nil || config == nil {
+		return false
+	}
+	return len(config.GetWindowsEvents()) > 0
+}
+
+// hostNetwork returns whether the agent pods use the host network, which HostProcess containers require.
+func hostNetwork(agent v1alpha1.AmazonCloudWatchAgent) bool {
+	return agent.Spec.HostNetwork || collectsWindowsEvents(agent)
+}
+
+// podSecurityContext returns the pod security context of the spec, running the pods as HostProcess containers
+// when the agent collects Windows event logs.
+func podSecurityContext(agent v1alpha1.AmazonCloudWatchAgent) *corev1.PodSecurityContext {
+	if !collectsWindowsEvents(agent) {
+		return seccompSecurityContext(agent)
+	}
+	securityContext := &corev1.PodSecurityContext{}
+	if agent.Spec.PodSecurityContext != nil {
+		securityContext = agent.Spec.PodSecurityContext.DeepCopy()
+	}
+	if securityContext.WindowsOptions == nil {
+		securityContext.WindowsOptions = &corev1.WindowsSecurityContextOptions{}
+	}
+	if securityContext.WindowsOptions.HostProcess == nil {
+		hostProcess := true
+		securityContext.WindowsOptions.HostProcess = &hostProcess
+	}
+	if securityContext.WindowsOptions.RunAsUserName == nil {
+		user := windowsSystemUser
+		securityContext.WindowsOptions.RunAsUserName = &user
+	}
+	return securityContext
+}
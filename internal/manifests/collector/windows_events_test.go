@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestWindowsEventsHostProcess(t *testing.T) {
+	// prepare
+	runAsUser := "ContainerUser"
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:         v1alpha1.ModeDaemonSet,
+			NodeSelector: map[string]string{"kubernetes.io/os": "windows"},
+			Config:       `{"logs": {"logs_collected": {"windows_events": {"collect_list": [{"event_name": "System", "event_levels": ["ERROR"], "log_group_name": "system"}]}}}}`,
+			PodSecurityContext: &corev1.PodSecurityContext{
+				WindowsOptions: &corev1.WindowsSecurityContextOptions{RunAsUserName: &runAsUser},
+			},
+		},
+	}
+
+	// test
+	securityContext := podSecurityContext(agent)
+
+	// verify
+	assert.True(t, hostNetwork(agent))
+	assert.Equal(t, corev1.DNSClusterFirstWithHostNet, getDNSPolicy(agent))
+	require.NotNil(t, securityContext.WindowsOptions.HostProcess)
+	assert.True(t, *securityContext.WindowsOptions.HostProcess)
+	assert.Equal(t, runAsUser, *securityContext.WindowsOptions.RunAsUserName)
+	assert.Nil(t, agent.Spec.PodSecurityContext.WindowsOptions.HostProcess)
+
+	// linux daemonsets are left untouched
+	delete(agent.Spec.NodeSelector, "kubernetes.io/os")
+	assert.False(t, hostNetwork(agent))
+	assert.Same(t, agent.Spec.PodSecurityContext, podSecurityContext(agent))
+}
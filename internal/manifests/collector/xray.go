@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// configWithXRay renders the X-Ray settings into the traces section of the given agent config, overriding the
+// ones set there. Configs without a traces section are left untouched.
+func configWithXRay(config map[string]interface{}, xray *v1alpha1.XRaySpec) {
+	traces, ok := config["traces"].(map[string]interface{})
+	if xray == nil || !ok {
+		return
+	}
+	if xray.LocalMode != nil {
+		traces["local_mode"] = *xray.LocalMode
+	}
+	if xray.RegionOverride != "" {
+		traces["region_override"] = xray.RegionOverride
+	}
+	if xray.EndpointOverride != "" {
+		traces["endpoint_override"] = xray.EndpointOverride
+	}
+	if xray.ResourceARN != "" {
+		traces["resource_arn"] = xray.ResourceARN
+	}
+	if xray.BufferSizeMB != nil {
+		traces["buffer_size_mb"] = *xray.BufferSizeMB
+	}
+	if xray.Concurrency != nil {
+		traces["concurrency"] = *xray.Concurrency
+	}
+}
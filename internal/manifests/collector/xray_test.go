@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestConfigWithXRay(t *testing.T) {
+	localMode := true
+	bufferSize := int32(10)
+	agent := v1alpha1.AmazonCloudWatchAgent{
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"traces":{"traces_collected":{"xray":{}},"region_override":"us-east-1","concurrency":4}}`,
+			XRay: &v1alpha1.XRaySpec{
+				LocalMode:      &localMode,
+				RegionOverride: "us-west-2",
+				BufferSizeMB:   &bufferSize,
+			},
+		},
+	}
+
+	replaced, err := ReplaceConfig(agent)
+	require.NoError(t, err)
+
+	config, err := adapters.ConfigFromJSONString(replaced)
+	require.NoError(t, err)
+	traces := config["traces"].(map[string]interface{})
+	assert.Equal(t, true, traces["local_mode"])
+	assert.Equal(t, "us-west-2", traces["region_override"])
+	assert.Equal(t, float64(10), traces["buffer_size_mb"])
+	assert.Equal(t, float64(4), traces["concurrency"])
+	assert.NotContains(t, traces, "endpoint_override")
+
+	// configs without traces are left untouched
+	noTraces := map[string]interface{}{"logs": map[string]interface{}{}}
+	configWithXRay(noTraces, agent.Spec.XRay)
+	assert.Equal(t, map[string]interface{}{"logs": map[string]interface{}{}}, noTraces)
+}
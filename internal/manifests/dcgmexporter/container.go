@@ -69,10 +69,13 @@ func Container(cfg config.Config, logger logr.Logger, exporter v1alpha1.DcgmExpo
 	if exporter.Spec.Env == nil {
 		envVars = []corev1.EnvVar{}
 	}
-	envVars = append(envVars, corev1.EnvVar{
-		Name:  metricsConfigEnvVar,
-		Value: fmt.Sprintf("%s/%s", configmapMountPath, DcgmMetricsIncludedCsv),
-	})
+	// the metrics config can be overridden by the user provided env vars
+	if !hasEnvVar(envVars, metricsConfigEnvVar) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  metricsConfigEnvVar,
+			Value: fmt.Sprintf("%s/%s", configmapMountPath, DcgmMetricsIncludedCsv),
+		})
+	}
 
 	return corev1.Container{
 		Name:         ComponentDcgmExporter,
@@ -80,7 +83,17 @@ func Container(cfg config.Config, logger logr.Logger, exporter v1alpha1.DcgmExpo
 		Args:         args,
 		Resources:    exporter.Spec.Resources,
 		Env:          envVars,
+		EnvFrom:      exporter.Spec.EnvFrom,
 		Ports:        ports,
 		VolumeMounts: volumeMounts,
 	}
 }
+
+func hasEnvVar(envVars []corev1.EnvVar, name string) bool {
+	for _, envVar := range envVars {
+		if envVar.Name == name {
+			return true
+		}
+	}
+	return false
+}
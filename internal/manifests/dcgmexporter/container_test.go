@@ -160,6 +160,56 @@ func TestDcgmContainer(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "envOverrides",
+			exporter: v1alpha1.DcgmExporter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-instance",
+					Namespace: "my-ns",
+				},
+				Spec: v1alpha1.DcgmExporterSpec{
+					Image: "test-image",
+					Env: []corev1.EnvVar{
+						{
+							Name:  metricsConfigEnvVar,
+							Value: "/etc/custom/metrics.csv",
+						},
+					},
+					EnvFrom: []corev1.EnvFromSource{
+						{
+							ConfigMapRef: &corev1.ConfigMapEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "tuning"},
+							},
+						},
+					},
+				},
+			},
+			expected: corev1.Container{
+				Name:  ComponentDcgmExporter,
+				Image: "test-image",
+				Args:  nil,
+				Env: []corev1.EnvVar{
+					{
+						Name:  metricsConfigEnvVar,
+						Value: "/etc/custom/metrics.csv",
+					},
+				},
+				EnvFrom: []corev1.EnvFromSource{
+					{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "tuning"},
+						},
+					},
+				},
+				Ports: []corev1.ContainerPort{},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      DcgmConfigMapVolumeName,
+						MountPath: configmapMountPath,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
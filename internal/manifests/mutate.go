@@ -18,6 +18,7 @@ import (
 	policyV1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -44,6 +45,7 @@ var (
 // - HorizontalPodAutoscaler
 // - Route
 // - Secret
+// - Unstructured
 // In order for the operator to reconcile other types, they must be added here.
 // The function returned takes no arguments but instead uses the existing and desired inputs here. Existing is expected
 // to be set by the controller-runtime package through a client get call.
@@ -155,6 +157,11 @@ func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 			wantPr := desired.(*corev1.Secret)
 			mutateSecret(pr, wantPr)
 
+		case *unstructured.Unstructured:
+			u := existing.(*unstructured.Unstructured)
+			wantU := desired.(*unstructured.Unstructured)
+			mutateUnstructured(u, wantU)
+
 		default:
 			t := reflect.TypeOf(existing).String()
 			return fmt.Errorf("missing mutate implementation for resource type: %s", t)
@@ -173,6 +180,12 @@ func mutateSecret(existing, desired *corev1.Secret) {
 	existing.Data = desired.Data
 }
 
+// mutateUnstructured handles the objects of optional APIs the operator has no typed client for, such as
+// cert-manager Certificates. Only their spec is managed.
+func mutateUnstructured(existing, desired *unstructured.Unstructured) {
+	existing.Object["spec"] = desired.Object["spec"]
+}
+
 func mutateConfigMap(existing, desired *corev1.ConfigMap) {
 	existing.BinaryData = desired.BinaryData
 	existing.Data = desired.Data
@@ -245,14 +258,102 @@ func mutatePodMonitor(existing, desired *monitoringv1.PodMonitor) {
 	existing.Spec = desired.Spec
 }
 
+// mutateService updates the ports and selector of the existing Service in place. The fields assigned by the API
+// server, such as the cluster IPs, the node ports and the health check node port, are kept so that a reconcile
+// doesn't churn them.
 func mutateService(existing, desired *corev1.Service) error {
-	existing.Spec.Ports = desired.Spec.Ports
-	if err := mergeWithOverride(&existing.Spec.Selector, desired.Spec.Selector); err != nil {
-		return err
+	if !existing.CreationTimestamp.IsZero() && desired.Spec.ClusterIP != "" && desired.Spec.ClusterIP != existing.Spec.ClusterIP {
+		return fmt.Errorf("Spec.ClusterIP is being changed, %w", ImmutableChangeErr)
+	}
+	if desired.Spec.ClusterIP != "" {
+		existing.Spec.ClusterIP = desired.Spec.ClusterIP
+	}
+	// the primary IP family can't change, but a secondary family can be added or removed
+	if !existing.CreationTimestamp.IsZero() && len(desired.Spec.IPFamilies) > 0 && len(existing.Spec.IPFamilies) > 0 && desired.Spec.IPFamilies[0] != existing.Spec.IPFamilies[0] {
+		return fmt.Errorf("Spec.IPFamilies is being changed, %w", ImmutableChangeErr)
+	}
+	// the API server defaults the IP families and policy left unset
+	if len(desired.Spec.IPFamilies) > 0 {
+		existing.Spec.IPFamilies = desired.Spec.IPFamilies
+		// the cluster IP of a removed secondary family is released along with it
+		if len(existing.Spec.ClusterIPs) > len(desired.Spec.IPFamilies) {
+			existing.Spec.ClusterIPs = existing.Spec.ClusterIPs[:len(desired.Spec.IPFamilies)]
+		}
+	}
+	if desired.Spec.IPFamilyPolicy != nil {
+		existing.Spec.IPFamilyPolicy = desired.Spec.IPFamilyPolicy
+	}
+	// the type defaults to ClusterIP
+	desiredType := desired.Spec.Type
+	if desiredType == "" {
+		desiredType = corev1.ServiceTypeClusterIP
+	}
+	if existing.Spec.Type != desiredType && !(existing.Spec.Type == "" && desiredType == corev1.ServiceTypeClusterIP) {
+		existing.Spec.Type = desiredType
+	}
+	existing.Spec.Ports = mergeServicePorts(existing.Spec.Ports, desired.Spec.Ports)
+	if desiredType == corev1.ServiceTypeClusterIP {
+		// the node ports allocated while the service was exposed on the nodes are released
+		for i := range existing.Spec.Ports {
+			existing.Spec.Ports[i].NodePort = 0
+		}
+	}
+	if !apiequality.Semantic.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) {
+		existing.Spec.Selector = desired.Spec.Selector
+	}
+	if desired.Spec.InternalTrafficPolicy != nil {
+		existing.Spec.InternalTrafficPolicy = desired.Spec.InternalTrafficPolicy
 	}
 	return nil
 }
 
+// mergeServicePorts returns the desired ports in the order of the existing ones, with the new ports appended. An
+// existing port is matched by name, or by number and protocol for unnamed ports, and keeps its allocated node port
+// unless the desired port asks for a specific one.
+func mergeServicePorts(existing, desired []corev1.ServicePort) []corev1.ServicePort {
+	servicePortKey := func(p corev1.ServicePort) string {
+		if p.Name != "" {
+			return p.Name
+		}
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		return fmt.Sprintf("%d/%s", p.Port, protocol)
+	}
+	existingPorts := make(map[string]corev1.ServicePort, len(existing))
+	for _, p := range existing {
+		existingPorts[servicePortKey(p)] = p
+	}
+	desiredPorts := make(map[string]corev1.ServicePort, len(desired))
+	var added []corev1.ServicePort
+	for _, p := range desired {
+		key := servicePortKey(p)
+		if current, ok := existingPorts[key]; ok {
+			if p.NodePort == 0 {
+				p.NodePort = current.NodePort
+			}
+			// fill in the values defaulted by the API server, so that they don't count as a change
+			if p.Protocol == "" {
+				p.Protocol = current.Protocol
+			}
+			if p.TargetPort.IntVal == 0 && p.TargetPort.StrVal == "" {
+				p.TargetPort = current.TargetPort
+			}
+			desiredPorts[key] = p
+			continue
+		}
+		added = append(added, p)
+	}
+	var ports []corev1.ServicePort
+	for _, p := range existing {
+		if want, ok := desiredPorts[servicePortKey(p)]; ok {
+			ports = append(ports, want)
+		}
+	}
+	return append(ports, added...)
+}
+
 func mutateDaemonset(existing, desired *appsv1.DaemonSet) error {
 	if !existing.CreationTimestamp.IsZero() && !apiequality.Semantic.DeepEqual(desired.Spec.Selector, existing.Spec.Selector) {
 		return ImmutableChangeErr
@@ -278,7 +379,10 @@ func mutateDeployment(existing, desired *appsv1.Deployment) error {
 	if existing.CreationTimestamp.IsZero() {
 		existing.Spec.Selector = desired.Spec.Selector
 	}
-	existing.Spec.Replicas = desired.Spec.Replicas
+	// the replicas of a deployment scaled by an autoscaler of its own are left to it
+	if desired.Spec.Replicas != nil {
+		existing.Spec.Replicas = desired.Spec.Replicas
+	}
 	if err := mergeWithOverride(&existing.Spec.Template, desired.Spec.Template); err != nil {
 		return err
 	}
@@ -0,0 +1,157 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestMutateServiceKeepsAllocatedFields(t *testing.T) {
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", CreationTimestamp: metav1.Now()},
+		Spec: corev1.ServiceSpec{
+			Type:                corev1.ServiceTypeLoadBalancer,
+			ClusterIP:           "10.0.0.10",
+			ClusterIPs:          []string{"10.0.0.10"},
+			HealthCheckNodePort: 31000,
+			Selector:            map[string]string{"app": "agent", "stale": "true"},
+			Ports: []corev1.ServicePort{
+				{Name: "otlp-grpc", Port: 4317, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(4317), NodePort: 30001},
+				{Name: "statsd", Port: 8125, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromInt(8125), NodePort: 30002},
+				{Name: "removed", Port: 9999, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(9999), NodePort: 30003},
+			},
+		},
+	}
+	desired := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeLoadBalancer,
+			Selector: map[string]string{"app": "agent"},
+			Ports: []corev1.ServicePort{
+				{Name: "otlp-http", Port: 4318},
+				{Name: "statsd", Port: 8125, Protocol: corev1.ProtocolUDP},
+				{Name: "otlp-grpc", Port: 4317},
+			},
+		},
+	}
+
+	require.NoError(t, MutateFuncFor(existing, desired)())
+
+	assert.Equal(t, "10.0.0.10", existing.Spec.ClusterIP)
+	assert.Equal(t, []string{"10.0.0.10"}, existing.Spec.ClusterIPs)
+	assert.Equal(t, int32(31000), existing.Spec.HealthCheckNodePort)
+	assert.Equal(t, map[string]string{"app": "agent"}, existing.Spec.Selector)
+	assert.Equal(t, []corev1.ServicePort{
+		{Name: "otlp-grpc", Port: 4317, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(4317), NodePort: 30001},
+		{Name: "statsd", Port: 8125, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromInt(8125), NodePort: 30002},
+		{Name: "otlp-http", Port: 4318},
+	}, existing.Spec.Ports)
+}
+
+func TestMutateServiceNoChange(t *testing.T) {
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", CreationTimestamp: metav1.Now()},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.0.0.10",
+			Selector:  map[string]string{"app": "agent"},
+			Ports:     []corev1.ServicePort{{Port: 4317, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(4317)}},
+		},
+	}
+	before := existing.DeepCopy()
+	desired := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "agent"},
+			Ports:    []corev1.ServicePort{{Port: 4317}},
+		},
+	}
+
+	require.NoError(t, MutateFuncFor(existing, desired)())
+	assert.Equal(t, before, existing)
+}
+
+func TestMutateServiceClusterIPChange(t *testing.T) {
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-headless", CreationTimestamp: metav1.Now()},
+		Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.10"},
+	}
+	desired := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-headless"},
+		Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone},
+	}
+
+	assert.ErrorIs(t, MutateFuncFor(existing, desired)(), ImmutableChangeErr)
+}
+
+func TestMutateServiceType(t *testing.T) {
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", CreationTimestamp: metav1.Now()},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: "10.0.0.10",
+			Ports:     []corev1.ServicePort{{Name: "emf-tcp", Port: 25888, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt(25888)}},
+		},
+	}
+	appProtocol := "tcp"
+	desired := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Name: "emf-tcp", Port: 25888, AppProtocol: &appProtocol, NodePort: 30888}},
+		},
+	}
+
+	require.NoError(t, MutateFuncFor(existing, desired)())
+	assert.Equal(t, corev1.ServiceTypeNodePort, existing.Spec.Type)
+	assert.Equal(t, int32(30888), existing.Spec.Ports[0].NodePort)
+	assert.Equal(t, &appProtocol, existing.Spec.Ports[0].AppProtocol)
+
+	// going back to ClusterIP releases the node ports
+	require.NoError(t, MutateFuncFor(existing, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "emf-tcp", Port: 25888}}},
+	})())
+	assert.Equal(t, corev1.ServiceTypeClusterIP, existing.Spec.Type)
+	assert.Zero(t, existing.Spec.Ports[0].NodePort)
+}
+
+func TestMutateServiceIPFamilies(t *testing.T) {
+	dualStack, singleStack := corev1.IPFamilyPolicyPreferDualStack, corev1.IPFamilyPolicySingleStack
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", CreationTimestamp: metav1.Now()},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:      "10.0.0.10",
+			ClusterIPs:     []string{"10.0.0.10", "fd00::10"},
+			IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			IPFamilyPolicy: &dualStack,
+		},
+	}
+	desired := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Spec: corev1.ServiceSpec{
+			IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol},
+			IPFamilyPolicy: &singleStack,
+		},
+	}
+
+	require.NoError(t, MutateFuncFor(existing, desired)())
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv4Protocol}, existing.Spec.IPFamilies)
+	assert.Equal(t, []string{"10.0.0.10"}, existing.Spec.ClusterIPs)
+	assert.Equal(t, corev1.IPFamilyPolicySingleStack, *existing.Spec.IPFamilyPolicy)
+
+	// the families defaulted by the API server are kept
+	desired.Spec.IPFamilies, desired.Spec.IPFamilyPolicy = nil, nil
+	require.NoError(t, MutateFuncFor(existing, desired)())
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv4Protocol}, existing.Spec.IPFamilies)
+	assert.Equal(t, corev1.IPFamilyPolicySingleStack, *existing.Spec.IPFamilyPolicy)
+
+	desired.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	assert.ErrorIs(t, MutateFuncFor(existing, desired)(), ImmutableChangeErr)
+}
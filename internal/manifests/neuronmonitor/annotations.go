@@ -10,6 +10,8 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 )
 
+const configSHAAnnotation = "amazon-cloudwatch-agent-operator-config/sha256"
+
 // Annotations return the annotations for NeuronMonitor pod.
 func Annotations(instance v1alpha1.NeuronMonitor) map[string]string {
 	// new map every time, so that we don't touch the instance's annotations
@@ -24,11 +26,19 @@ func Annotations(instance v1alpha1.NeuronMonitor) map[string]string {
 		}
 	}
 	// make sure sha256 for configMap is always calculated
-	annotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance.Spec.MonitorConfig)
+	annotations[configSHAAnnotation] = getConfigMapSHA(monitorConfig(instance))
 
 	return annotations
 }
 
+// PodAnnotations return the annotations for the NeuronMonitor pods, which carry the hash of the monitor config so
+// that the pods are rolled out when it changes.
+func PodAnnotations(instance v1alpha1.NeuronMonitor) map[string]string {
+	return map[string]string{
+		configSHAAnnotation: getConfigMapSHA(monitorConfig(instance)),
+	}
+}
+
 func getConfigMapSHA(config string) string {
 	h := sha256.Sum256([]byte(config))
 	return fmt.Sprintf("%x", h)
@@ -26,7 +26,7 @@ func TestDefaultAnnotations(t *testing.T) {
 
 	//verify
 	assert.Equal(t, "neuron-monitor", annotations["k8s-app"])
-	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", annotations["amazon-cloudwatch-agent-operator-config/sha256"])
+	assert.Equal(t, getConfigMapSHA(DefaultMonitorConfig), annotations["amazon-cloudwatch-agent-operator-config/sha256"])
 }
 
 func TestUserAnnotations(t *testing.T) {
@@ -49,5 +49,13 @@ func TestUserAnnotations(t *testing.T) {
 	//verify
 	assert.Equal(t, "test", annotations["prometheus.io/test"])
 	assert.Equal(t, "neuron-monitor", annotations["k8s-app"])
-	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", annotations["amazon-cloudwatch-agent-operator-config/sha256"])
+	assert.Equal(t, getConfigMapSHA(DefaultMonitorConfig), annotations["amazon-cloudwatch-agent-operator-config/sha256"])
+}
+
+func TestPodAnnotations(t *testing.T) {
+	exporter := v1alpha1.NeuronMonitor{
+		Spec: v1alpha1.NeuronMonitorSpec{MonitorConfig: `{"period":"10s"}`},
+	}
+
+	assert.Equal(t, map[string]string{"amazon-cloudwatch-agent-operator-config/sha256": getConfigMapSHA(`{"period":"10s"}`)}, PodAnnotations(exporter))
 }
@@ -4,9 +4,13 @@
 package neuronmonitor
 
 import (
+	"encoding/json"
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
@@ -15,14 +19,29 @@ const (
 	NeuronConfigMapName       = "neuron-monitor-config-map"
 	NeuronConfigMapVolumeName = "neuron-monitor-config"
 	NeuronMonitorJson         = "monitor.json"
+
+	// DefaultMonitorConfig collects the Neuron metrics of the enhanced Container Insights every 5 seconds.
+	DefaultMonitorConfig = `{"period":"5s","neuron_runtimes":[{"tag_filter":".*","metrics":[{"type":"neuroncore_counters"},{"type":"memory_used"},{"type":"neuron_runtime_vcpu_usage"},{"type":"execution_stats"}]}],"system_metrics":[{"type":"vcpu_usage"},{"type":"memory_info"},{"type":"neuron_hw_counters"}]}`
 )
 
+// monitorConfig returns the neuron-monitor config of the instance, or the default one when it has none.
+func monitorConfig(instance v1alpha1.NeuronMonitor) string {
+	if instance.Spec.MonitorConfig != "" {
+		return instance.Spec.MonitorConfig
+	}
+	return DefaultMonitorConfig
+}
+
 func ConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
 	name := NeuronConfigMapName
 	labels := manifestutils.Labels(params.NeuronExp.ObjectMeta, name, params.NeuronExp.Spec.Image, ComponentNeuronExporter, []string{})
 
+	config := monitorConfig(params.NeuronExp)
+	if !json.Valid([]byte(config)) {
+		return nil, fmt.Errorf("the monitorConfig of NeuronMonitor %s is not valid JSON", params.NeuronExp.Name)
+	}
 	data := map[string]string{
-		NeuronMonitorJson: params.NeuronExp.Spec.MonitorConfig,
+		NeuronMonitorJson: config,
 	}
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
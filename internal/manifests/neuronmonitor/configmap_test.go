@@ -43,6 +43,23 @@ func TestDesiredConfigMap(t *testing.T) {
 		assert.Equal(t, expectedData, actual.Data)
 
 	})
+
+	t.Run("should default the neuron monitor config", func(t *testing.T) {
+		param := getParams()
+		param.NeuronExp.Spec.MonitorConfig = ""
+		actual, err := ConfigMap(param)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{NeuronMonitorJson: DefaultMonitorConfig}, actual.Data)
+	})
+
+	t.Run("should reject a neuron monitor config which is not JSON", func(t *testing.T) {
+		param := getParams()
+		param.NeuronExp.Spec.MonitorConfig = `{"period":"5s"`
+		_, err := ConfigMap(param)
+
+		assert.Error(t, err)
+	})
 }
 
 func getParams() manifests.Params {
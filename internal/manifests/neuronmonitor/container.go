@@ -67,6 +67,7 @@ func Container(cfg config.Config, logger logr.Logger, exporter v1alpha1.NeuronMo
 		SecurityContext: exporter.Spec.SecurityContext,
 		Resources:       exporter.Spec.Resources,
 		Env:             envVars,
+		EnvFrom:         exporter.Spec.EnvFrom,
 		Ports:           ports,
 		VolumeMounts:    volumeMounts,
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -84,6 +85,57 @@ func TestNeuronContainer(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "envFrom and resources",
+			exporter: v1alpha1.NeuronMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-instance",
+					Namespace: "my-ns",
+				},
+				Spec: v1alpha1.NeuronMonitorSpec{
+					Image: "test-image",
+					EnvFrom: []corev1.EnvFromSource{
+						{
+							SecretRef: &corev1.SecretEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+							},
+						},
+					},
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				},
+			},
+			expected: corev1.Container{
+				Name:  ComponentNeuronExporter,
+				Image: "test-image",
+				Args: []string{
+					"--neuron-monitor-config", fmt.Sprintf("%s/%s", configmapMountPath, NeuronMonitorJson),
+				},
+				Env: []corev1.EnvVar{},
+				EnvFrom: []corev1.EnvFromSource{
+					{
+						SecretRef: &corev1.SecretEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+						},
+					},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+				},
+				Ports: []corev1.ContainerPort{},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      NeuronConfigMapVolumeName,
+						MountPath: configmapMountPath,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
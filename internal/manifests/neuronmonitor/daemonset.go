@@ -34,7 +34,8 @@ func DaemonSet(params manifests.Params) *appsv1.DaemonSet {
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      labels,
+					Annotations: PodAnnotations(params.NeuronExp),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: ServiceAccountName(params.NeuronExp),
@@ -4,6 +4,8 @@
 package neuronmonitor
 
 import (
+	"context"
+
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
@@ -14,7 +16,7 @@ const (
 )
 
 // Build creates the manifest for the exporter resource.
-func Build(params manifests.Params) ([]client.Object, error) {
+func Build(_ context.Context, params manifests.Params) ([]client.Object, error) {
 	var resourceManifests []client.Object
 	var manifestFactories []manifests.K8sManifestFactory
 	manifestFactories = append(manifestFactories, []manifests.K8sManifestFactory{
@@ -53,6 +53,8 @@ func Service(params manifests.Params) (*corev1.Service, error) {
 			InternalTrafficPolicy: &trafficPolicy,
 			Selector:              manifestutils.SelectorLabels(params.NeuronExp.ObjectMeta, ComponentNeuronExporter),
 			Ports:                 ports,
+			IPFamilies:            params.NeuronExp.Spec.IPFamilies,
+			IPFamilyPolicy:        params.NeuronExp.Spec.IPFamilyPolicy,
 		},
 	}, nil
 }
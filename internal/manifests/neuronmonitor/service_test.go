@@ -106,4 +106,22 @@ func TestDesiredNeuronService(t *testing.T) {
 		assert.Equal(t, expected.Spec.InternalTrafficPolicy, actual.Spec.InternalTrafficPolicy)
 		assert.Equal(t, expected.Spec.Ports, actual.Spec.Ports)
 	})
+	t.Run("should return a dual-stack service", func(t *testing.T) {
+		policy := v1.IPFamilyPolicyRequireDualStack
+		params := manifests.Params{
+			Config: config.Config{},
+			Log:    logger,
+			NeuronExp: v1alpha1.NeuronMonitor{
+				Spec: v1alpha1.NeuronMonitorSpec{
+					IPFamilies:     []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol},
+					IPFamilyPolicy: &policy,
+				},
+			},
+		}
+
+		actual, err := Service(params)
+		assert.Nil(t, err)
+		assert.Equal(t, []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}, actual.Spec.IPFamilies)
+		assert.Equal(t, &policy, actual.Spec.IPFamilyPolicy)
+	})
 }
@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package manifests
+
+import (
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "amazon-cloudwatch-agent-operator"
+)
+
+var (
+	ErrNotOwned = errors.New("object is not managed by the operator")
+)
+
+// VerifyOwnership returns ErrNotOwned when the existing object was created by another tool, that is when it is
+// neither controlled by the owner nor labeled as managed by the operator. Objects that don't exist yet are owned.
+func VerifyOwnership(existing client.Object, owner metav1.Object) error {
+	if existing.GetResourceVersion() == "" {
+		return nil
+	}
+	if metav1.IsControlledBy(existing, owner) || existing.GetLabels()[managedByLabel] == managedByValue {
+		return nil
+	}
+	return ErrNotOwned
+}
@@ -24,7 +24,7 @@ const (
 )
 
 func ConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
-	name := naming.TAConfigMap(params.OtelCol.Name)
+	name := naming.TAConfigMap(params.OtelCol.ResourceName())
 	version := strings.Split(params.OtelCol.Spec.TargetAllocator.Image, ":")
 	labels := Labels(params.OtelCol, name)
 	if len(version) > 1 {
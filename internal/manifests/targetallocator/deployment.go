@@ -16,7 +16,7 @@ import (
 
 // Deployment builds the deployment for the given instance.
 func Deployment(params manifests.Params) (*appsv1.Deployment, error) {
-	name := naming.TargetAllocator(params.OtelCol.Name)
+	name := naming.TargetAllocator(params.OtelCol.ResourceName())
 	version := strings.Split(params.OtelCol.Spec.TargetAllocator.Image, ":")
 	labels := Labels(params.OtelCol, name)
 	if len(version) > 1 {
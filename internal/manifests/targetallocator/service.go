@@ -16,18 +16,18 @@ import (
 
 func Service(params manifests.Params) *corev1.Service {
 	version := strings.Split(params.OtelCol.Spec.TargetAllocator.Image, ":")
-	labels := Labels(params.OtelCol, naming.TAService(params.OtelCol.Name))
+	labels := Labels(params.OtelCol, naming.TAService(params.OtelCol.ResourceName()))
 	if len(version) > 1 {
 		labels["app.kubernetes.io/version"] = version[len(version)-1]
 	} else {
 		labels["app.kubernetes.io/version"] = "latest"
 	}
 
-	selector := Labels(params.OtelCol, naming.TargetAllocator(params.OtelCol.Name))
+	selector := Labels(params.OtelCol, naming.TargetAllocator(params.OtelCol.ResourceName()))
 
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      naming.TAService(params.OtelCol.Name),
+			Name:      naming.TAService(params.OtelCol.ResourceName()),
 			Namespace: params.OtelCol.Namespace,
 			Labels:    labels,
 		},
@@ -4,13 +4,15 @@
 package targetallocator
 
 import (
+	"context"
+
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
 
 // Build creates the manifest for the TargetAllocator resource.
-func Build(params manifests.Params) ([]client.Object, error) {
+func Build(_ context.Context, params manifests.Params) ([]client.Object, error) {
 	var resourceManifests []client.Object
 	if !params.OtelCol.Spec.TargetAllocator.Enabled {
 		return resourceManifests, nil
@@ -17,7 +17,7 @@ func Volumes(cfg config.Config, otelcol v1alpha1.AmazonCloudWatchAgent) []corev1
 		Name: naming.TAConfigMapVolume(),
 		VolumeSource: corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
-				LocalObjectReference: corev1.LocalObjectReference{Name: naming.TAConfigMap(otelcol.Name)},
+				LocalObjectReference: corev1.LocalObjectReference{Name: naming.TAConfigMap(otelcol.ResourceName())},
 				Items: []corev1.KeyToPath{
 					{
 						Key:  cfg.TargetAllocatorConfigMapEntry(),
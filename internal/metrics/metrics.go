@@ -0,0 +1,108 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics contains the operator's own Prometheus metrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricPrefix = "cloudwatch_agent_operator_"
+
+	// TaskBuild is the reconcile task generating the desired manifests.
+	TaskBuild = "build"
+	// TaskApply is the reconcile task creating or updating the desired objects.
+	TaskApply = "apply"
+	// TaskStatus is the reconcile task updating the CR status.
+	TaskStatus = "status"
+	// TaskAlarms is the reconcile task creating or updating the CloudWatch alarms.
+	TaskAlarms = "alarms"
+	// TaskConfigMapHistory is the reconcile task keeping the previous revisions of the ConfigMaps.
+	TaskConfigMapHistory = "configmap-history"
+	// TaskReferences is the reconcile task copying the ConfigMaps and Secrets referenced in other namespaces.
+	TaskReferences = "references"
+	// TaskRestart is the reconcile task rolling the restart requested through the restart annotation.
+	TaskRestart = "restart"
+	// TaskControlPlaneMetrics is the reconcile task binding the reading of the control plane metrics.
+	TaskControlPlaneMetrics = "control-plane-metrics"
+	// TaskAcceleratedCompute is the reconcile task deploying the DCGM exporter and the Neuron monitor.
+	TaskAcceleratedCompute = "accelerated-compute"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	// ReconcileDuration records how long each reconcile task takes, per controller.
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: metricPrefix + "reconcile_task_duration_seconds",
+		Help: "Duration of the reconcile tasks.",
+	}, []string{"controller", "task"})
+	// ReconcileFailures counts the failed reconcile tasks, per controller.
+	ReconcileFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: metricPrefix + "reconcile_task_failures_total",
+		Help: "Number of failed reconcile tasks.",
+	}, []string{"controller", "task"})
+	// ReconcileRetries counts the failed reconciliations retried after the backoff of the rate limiter, per controller.
+	ReconcileRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: metricPrefix + "reconcile_retries_total",
+		Help: "Number of failed reconciliations retried after a backoff.",
+	}, []string{"controller"})
+	// ManagedResources records the number of AmazonCloudWatchAgent resources by deployment mode.
+	ManagedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricPrefix + "managed_resources",
+		Help: "Number of AmazonCloudWatchAgent resources managed by the operator, by mode.",
+	}, []string{"mode"})
+	// DriftCorrections counts the updates applied to existing objects that no longer matched the desired state while the
+	// generation of their owner didn't change, leaving out the updates applying the changes of the owner.
+	DriftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: metricPrefix + "drift_corrections_total",
+		Help: "Number of updates applied to owned objects which drifted from the desired state.",
+	}, []string{"kind"})
+	// Injections counts the auto-instrumentation injections performed by the pod webhook.
+	Injections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: metricPrefix + "webhook_injections_total",
+		Help: "Number of auto-instrumentation injections performed by the pod webhook.",
+	}, []string{"language", "result"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		ReconcileDuration,
+		ReconcileFailures,
+		ReconcileRetries,
+		ManagedResources,
+		DriftCorrections,
+		Injections,
+	)
+}
+
+// ObserveReconcileTask records the duration of a reconcile task started at start, counting it as failed when err is set.
+func ObserveReconcileTask(controller, task string, start time.Time, err error) {
+	ReconcileDuration.WithLabelValues(controller, task).Observe(time.Since(start).Seconds())
+	if err != nil {
+		ReconcileFailures.WithLabelValues(controller, task).Inc()
+	}
+}
+
+// SetManagedResources replaces the managed resources gauge with the given count per mode.
+func SetManagedResources(countByMode map[string]int) {
+	ManagedResources.Reset()
+	for mode, count := range countByMode {
+		ManagedResources.WithLabelValues(mode).Set(float64(count))
+	}
+}
+
+// RecordInjection counts an auto-instrumentation injection attempt for the given language.
+func RecordInjection(language string, err error) {
+	result := resultSuccess
+	if err != nil {
+		result = resultFailure
+	}
+	Injections.WithLabelValues(language, result).Inc()
+}
@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveReconcileTask(t *testing.T) {
+	ObserveReconcileTask("test", TaskBuild, time.Now(), nil)
+	ObserveReconcileTask("test", TaskApply, time.Now(), errors.New("failed"))
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(ReconcileFailures.WithLabelValues("test", TaskBuild)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(ReconcileFailures.WithLabelValues("test", TaskApply)))
+	assert.Equal(t, 2, testutil.CollectAndCount(ReconcileDuration))
+}
+
+func TestSetManagedResources(t *testing.T) {
+	SetManagedResources(map[string]int{"daemonset": 2, "deployment": 1})
+	assert.Equal(t, 2.0, testutil.ToFloat64(ManagedResources.WithLabelValues("daemonset")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(ManagedResources.WithLabelValues("deployment")))
+
+	// modes that are no longer in use must not be reported anymore
+	SetManagedResources(map[string]int{"daemonset": 1})
+	assert.Equal(t, 1, testutil.CollectAndCount(ManagedResources))
+}
+
+func TestRecordInjection(t *testing.T) {
+	RecordInjection("java", nil)
+	RecordInjection("java", nil)
+	RecordInjection("python", errors.New("failed"))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(Injections.WithLabelValues("java", resultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(Injections.WithLabelValues("python", resultFailure)))
+}
@@ -4,6 +4,29 @@
 // Package naming is for determining the names for components (containers, services, ...).
 package naming
 
+import (
+	"fmt"
+)
+
+// resourceNamePrefix is prepended to the names of the objects the operator creates for the AmazonCloudWatchAgents.
+var resourceNamePrefix string
+
+// SetResourceNamePrefix sets the prefix prepended to the names of the objects the operator creates for the
+// AmazonCloudWatchAgents. It is set once, when the operator starts.
+func SetResourceNamePrefix(prefix string) {
+	resourceNamePrefix = prefix
+}
+
+// Instance builds the base of the names of the objects of an AmazonCloudWatchAgent: its name override, or else its
+// name, after the resource name prefix. The builders of the names of its objects take it in place of the name of the
+// instance.
+func Instance(name, nameOverride string) string {
+	if nameOverride != "" {
+		name = nameOverride
+	}
+	return resourceNamePrefix + name
+}
+
 // ConfigMap builds the name for the config map used in the AmazonCloudWatchAgent containers.
 func ConfigMap(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
@@ -29,6 +52,31 @@ func ConfigMapExtra(extraConfigMapName string) string {
 	return DNSName(Truncate("configmap-%s", 63, extraConfigMapName))
 }
 
+// BufferVolume returns the name to use for the volume backing the spool directory with the given index.
+func BufferVolume(index int) string {
+	return fmt.Sprintf("buffer-%d", index)
+}
+
+// PersistenceVolume returns the name to use for the volume persisting the agent's sending queues.
+func PersistenceVolume() string {
+	return "persistence"
+}
+
+// TLSVolume returns the name to use for the volume holding the certificate of the receivers.
+func TLSVolume() string {
+	return "tls"
+}
+
+// TrustedCAVolume returns the name to use for the volume holding the bundle of the trusted certificate authorities.
+func TrustedCAVolume() string {
+	return "trusted-ca"
+}
+
+// LogFileVolume returns the name to use for the hostPath volume of the collected log directory with the given index.
+func LogFileVolume(index int) string {
+	return fmt.Sprintf("log-files-%d", index)
+}
+
 // TAConfigMapVolume returns the name to use for the config map's volume in the TargetAllocator pod.
 func TAConfigMapVolume() string {
 	return "ta-internal"
@@ -57,6 +105,38 @@ func Collector(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
 }
 
+// CanaryCollector builds the name of the daemonset of the canary cohort of a version split, or of the canary nodes
+// of a canary rollout, based on the instance.
+func CanaryCollector(otelcol string) string {
+	return DNSName(Truncate("%s-canary", 63, otelcol))
+}
+
+// CanaryConfigMap builds the name of the config map of the agents of the canary nodes of a canary rollout.
+func CanaryConfigMap(otelcol string) string {
+	return DNSName(Truncate("%s-canary", 63, otelcol))
+}
+
+// NodeGroupCollector builds the name of the daemonset running the agents of the given node group.
+func NodeGroupCollector(otelcol, group string) string {
+	return DNSName(Truncate("%s-%s", 63, otelcol, group))
+}
+
+// NodeGroupConfigMap builds the name of the config map of the agents of the given node group.
+func NodeGroupConfigMap(otelcol, group string) string {
+	return DNSName(Truncate("%s-%s", 63, otelcol, group))
+}
+
+// PipelineCollector builds the name of the deployment running the given signal of a split instance.
+func PipelineCollector(otelcol, signal string) string {
+	return DNSName(Truncate("%s-%s", 63, otelcol, signal))
+}
+
+// PipelineConfigMap builds the name of the config map of the deployment running the given signal of a split
+// instance.
+func PipelineConfigMap(otelcol, signal string) string {
+	return DNSName(Truncate("%s-%s", 63, otelcol, signal))
+}
+
 // HorizontalPodAutoscaler builds the autoscaler name based on the instance.
 func HorizontalPodAutoscaler(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
@@ -92,6 +172,11 @@ func MonitoringService(otelcol string) string {
 	return DNSName(Truncate("%s-monitoring", 63, Service(otelcol)))
 }
 
+// DebugService builds the name for the service of the debug endpoints based on the instance.
+func DebugService(otelcol string) string {
+	return DNSName(Truncate("%s-debug", 63, Service(otelcol)))
+}
+
 // Service builds the service name based on the instance.
 func Service(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
@@ -130,3 +215,45 @@ func ServiceMonitor(otelcol string) string {
 func PodMonitor(otelcol string) string {
 	return DNSName(Truncate("%s", 63, otelcol))
 }
+
+// JavaPodMonitor builds the name of the PodMonitor scraping the Java metrics of the pods instrumented by the
+// Instrumentation.
+func JavaPodMonitor(instrumentation string) string {
+	return DNSName(Truncate("%s-java", 63, instrumentation))
+}
+
+// Certificate builds the cert-manager Certificate name based on the instance.
+func Certificate(otelcol string) string {
+	return DNSName(Truncate("%s", 63, otelcol))
+}
+
+// TLSSecret builds the name of the Secret the certificate of the instance is issued into.
+func TLSSecret(otelcol string) string {
+	return DNSName(Truncate("%s-tls", 63, otelcol))
+}
+
+// CABundleConfigMap builds the name of the ConfigMaps the CA certificate of the instance is distributed in.
+func CABundleConfigMap(otelcol string) string {
+	return DNSName(Truncate("%s-ca-bundle", 63, otelcol))
+}
+
+// ConfigMapRevision builds the name of the copy of the given config map holding the revision with the given hash.
+func ConfigMapRevision(configMap, hash string) string {
+	return DNSName(Truncate("%s-%s", 63, configMap, hash))
+}
+
+// ReferenceCopy builds the name of the copy, in the namespace of the instance, of a ConfigMap or a Secret of
+// another namespace.
+func ReferenceCopy(otelcol, namespace, name string) string {
+	return DNSName(Truncate("%s-%s-%s", 253, otelcol, namespace, name))
+}
+
+// ExtraMountVolume returns the name to use for the volume of the extra mount with the given index.
+func ExtraMountVolume(index int) string {
+	return fmt.Sprintf("extra-mount-%d", index)
+}
+
+// VerticalPodAutoscaler builds the name of the VerticalPodAutoscaler of the instance.
+func VerticalPodAutoscaler(otelcol string) string {
+	return DNSName(Truncate("%s", 63, otelcol))
+}
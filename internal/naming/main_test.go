@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package naming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstance(t *testing.T) {
+	assert.Equal(t, "agent", Instance("agent", ""))
+	assert.Equal(t, "short", Instance("agent", "short"))
+
+	SetResourceNamePrefix("team-a-")
+	defer SetResourceNamePrefix("")
+	assert.Equal(t, "team-a-agent", Instance("agent", ""))
+	assert.Equal(t, "team-a-short", Instance("agent", "short"))
+	assert.Equal(t, "team-a-short-canary", CanaryCollector(Instance("agent", "short")))
+}
@@ -0,0 +1,170 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package promguardrails bounds the size of the Prometheus scrape configs of the agents, so that a single
+// scrape config can't take down the agents running it.
+package promguardrails
+
+import (
+	"fmt"
+	"os"
+	"regexp/syntax"
+
+	"github.com/ghodss/yaml"
+)
+
+// clusterWideRoles are the Kubernetes service discovery roles whose targets grow with the workloads of the
+// discovered namespaces.
+var clusterWideRoles = map[string]bool{
+	"pod":           true,
+	"endpoints":     true,
+	"endpointslice": true,
+	"service":       true,
+	"ingress":       true,
+}
+
+// Guardrails are the limits of the Prometheus scrape configs. A zero limit is not checked.
+type Guardrails struct {
+	// Enforce rejects the configs exceeding the guardrails, which are only warned about otherwise.
+	Enforce bool `json:"enforce,omitempty"`
+	// MaxScrapeConfigs is the maximum number of scrape jobs of a config.
+	MaxScrapeConfigs int `json:"maxScrapeConfigs,omitempty"`
+	// MaxStaticTargets is the maximum number of static targets of a config, over all of its jobs.
+	MaxStaticTargets int `json:"maxStaticTargets,omitempty"`
+	// MaxRelabelConfigs is the maximum number of relabel and metric relabel rules of a job.
+	MaxRelabelConfigs int `json:"maxRelabelConfigs,omitempty"`
+	// MaxRegexComplexity is the maximum number of repetitions, such as .* or [a-z]+, in the regex of a relabel
+	// rule. They drive the cost of matching the rule against every scraped series.
+	MaxRegexComplexity int `json:"maxRegexComplexity,omitempty"`
+	// DenyClusterWideDiscovery flags the Kubernetes service discovery of pods, endpoints, endpoint slices,
+	// services or ingresses restricted neither to some namespaces nor by selectors, whose targets grow with
+	// the whole cluster.
+	DenyClusterWideDiscovery bool `json:"denyClusterWideDiscovery,omitempty"`
+}
+
+// Load reads the guardrails from the YAML or JSON file at the given path.
+func Load(file string) (*Guardrails, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Prometheus guardrails: %w", err)
+	}
+	guardrails := &Guardrails{}
+	if err := yaml.Unmarshal(content, guardrails); err != nil {
+		return nil, fmt.Errorf("failed to parse the Prometheus guardrails %s: %w", file, err)
+	}
+	return guardrails, nil
+}
+
+// Check returns the guardrails the given Prometheus config, holding the scrape_configs, exceeds.
+func (g *Guardrails) Check(config map[interface{}]interface{}) []string {
+	if g == nil {
+		return nil
+	}
+	var violations []string
+	jobs, _ := config["scrape_configs"].([]interface{})
+	if g.MaxScrapeConfigs > 0 && len(jobs) > g.MaxScrapeConfigs {
+		violations = append(violations, fmt.Sprintf("the config has %d scrape configs, more than the limit of %d", len(jobs), g.MaxScrapeConfigs))
+	}
+
+	staticTargets := 0
+	for i, item := range jobs {
+		job, ok := item.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := job["job_name"].(string)
+		if !ok {
+			name = fmt.Sprintf("#%d", i)
+		}
+		staticTargets += countStaticTargets(job)
+
+		var relabelConfigs []interface{}
+		relabelConfigs = append(relabelConfigs, list(job["relabel_configs"])...)
+		relabelConfigs = append(relabelConfigs, list(job["metric_relabel_configs"])...)
+		if g.MaxRelabelConfigs > 0 && len(relabelConfigs) > g.MaxRelabelConfigs {
+			violations = append(violations, fmt.Sprintf("the scrape config %s has %d relabel configs, more than the limit of %d", name, len(relabelConfigs), g.MaxRelabelConfigs))
+		}
+		if g.MaxRegexComplexity > 0 {
+			for _, rc := range relabelConfigs {
+				rule, _ := rc.(map[interface{}]interface{})
+				regex, ok := rule["regex"].(string)
+				if !ok {
+					continue
+				}
+				if complexity := regexComplexity(regex); complexity > g.MaxRegexComplexity {
+					violations = append(violations, fmt.Sprintf("the scrape config %s has a relabel regex %q of complexity %d, more than the limit of %d", name, regex, complexity, g.MaxRegexComplexity))
+				}
+			}
+		}
+		if g.DenyClusterWideDiscovery {
+			for _, sd := range list(job["kubernetes_sd_configs"]) {
+				if role := clusterWideRole(sd); role != "" {
+					violations = append(violations, fmt.Sprintf("the scrape config %s discovers the %s targets of the whole cluster, restrict it with namespaces or selectors", name, role))
+				}
+			}
+		}
+	}
+	if g.MaxStaticTargets > 0 && staticTargets > g.MaxStaticTargets {
+		violations = append(violations, fmt.Sprintf("the config has %d static targets, more than the limit of %d", staticTargets, g.MaxStaticTargets))
+	}
+	return violations
+}
+
+// countStaticTargets returns the number of targets of the static configs of a job.
+func countStaticTargets(job map[interface{}]interface{}) int {
+	count := 0
+	for _, sc := range list(job["static_configs"]) {
+		if static, ok := sc.(map[interface{}]interface{}); ok {
+			count += len(list(static["targets"]))
+		}
+	}
+	return count
+}
+
+// clusterWideRole returns the role of the Kubernetes service discovery config when it discovers the targets of
+// the whole cluster, or an empty string.
+func clusterWideRole(item interface{}) string {
+	sd, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return ""
+	}
+	role, _ := sd["role"].(string)
+	if !clusterWideRoles[role] {
+		return ""
+	}
+	if len(list(sd["selectors"])) > 0 {
+		return ""
+	}
+	if namespaces, ok := sd["namespaces"].(map[interface{}]interface{}); ok {
+		if ownNamespace, _ := namespaces["own_namespace"].(bool); ownNamespace || len(list(namespaces["names"])) > 0 {
+			return ""
+		}
+	}
+	return role
+}
+
+// regexComplexity returns the number of repetitions in the regex. An invalid regex is left to the agent.
+func regexComplexity(regex string) int {
+	re, err := syntax.Parse(regex, syntax.Perl)
+	if err != nil {
+		return 0
+	}
+	return countRepetitions(re)
+}
+
+func countRepetitions(re *syntax.Regexp) int {
+	count := 0
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		count++
+	}
+	for _, sub := range re.Sub {
+		count += countRepetitions(sub)
+	}
+	return count
+}
+
+func list(value interface{}) []interface{} {
+	items, _ := value.([]interface{})
+	return items
+}
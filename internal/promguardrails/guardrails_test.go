@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package promguardrails
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func parse(t *testing.T, config string) map[interface{}]interface{} {
+	parsed := map[interface{}]interface{}{}
+	require.NoError(t, yaml.Unmarshal([]byte(config), &parsed))
+	return parsed
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "guardrails.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("enforce: true\nmaxScrapeConfigs: 10\ndenyClusterWideDiscovery: true\n"), 0600))
+	guardrails, err := Load(file)
+	require.NoError(t, err)
+	assert.Equal(t, &Guardrails{Enforce: true, MaxScrapeConfigs: 10, DenyClusterWideDiscovery: true}, guardrails)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	config := parse(t, `
+scrape_configs:
+- job_name: static
+  static_configs:
+  - targets: [a:9100, b:9100]
+  - targets: [c:9100]
+  metric_relabel_configs:
+  - source_labels: [__name__]
+    regex: (.*)_(.*)_(.*)_(.*)_(.*)_(.*)
+    action: drop
+- job_name: pods
+  kubernetes_sd_configs:
+  - role: pod
+  - role: pod
+    namespaces:
+      names: [default]
+  - role: endpoints
+    selectors:
+    - role: endpoints
+      label: app=web
+  - role: node
+  relabel_configs:
+  - action: keep
+    regex: "true"
+  - action: labelmap
+    regex: __meta_kubernetes_pod_label_(.+)
+`)
+
+	for _, tt := range []struct {
+		name       string
+		guardrails *Guardrails
+		expected   []string
+	}{
+		{name: "without guardrails"},
+		{name: "within the guardrails", guardrails: &Guardrails{MaxScrapeConfigs: 2, MaxStaticTargets: 3, MaxRelabelConfigs: 2, MaxRegexComplexity: 6}},
+		{name: "scrape configs", guardrails: &Guardrails{MaxScrapeConfigs: 1}, expected: []string{
+			"the config has 2 scrape configs, more than the limit of 1",
+		}},
+		{name: "static targets", guardrails: &Guardrails{MaxStaticTargets: 2}, expected: []string{
+			"the config has 3 static targets, more than the limit of 2",
+		}},
+		{name: "relabel configs", guardrails: &Guardrails{MaxRelabelConfigs: 1}, expected: []string{
+			"the scrape config pods has 2 relabel configs, more than the limit of 1",
+		}},
+		{name: "regex complexity", guardrails: &Guardrails{MaxRegexComplexity: 5}, expected: []string{
+			`the scrape config static has a relabel regex "(.*)_(.*)_(.*)_(.*)_(.*)_(.*)" of complexity 6, more than the limit of 5`,
+		}},
+		{name: "cluster wide discovery", guardrails: &Guardrails{DenyClusterWideDiscovery: true}, expected: []string{
+			"the scrape config pods discovers the pod targets of the whole cluster, restrict it with namespaces or selectors",
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.guardrails.Check(config))
+		})
+	}
+}
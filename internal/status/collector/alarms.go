@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	reasonAlarmsSynced = "AlarmsSynced"
+	reasonAlarmsFailed = "AlarmsFailed"
+)
+
+// StatusOption changes the status HandleReconcileStatus reports for the instance.
+type StatusOption func(changed *v1alpha1.AmazonCloudWatchAgent)
+
+// WithAlarms reports whether the CloudWatch alarms of the instance were reconciled, given the error of their
+// reconciliation. The condition is removed when the instance defines no alarms, or the operator doesn't manage them.
+func WithAlarms(enabled bool, err error) StatusOption {
+	return func(changed *v1alpha1.AmazonCloudWatchAgent) {
+		if !enabled || changed.Spec.Alarms == nil {
+			meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.ConditionTypeAlarmsSynced)
+			return
+		}
+		condition := metav1.Condition{
+			Type:               v1alpha1.ConditionTypeAlarmsSynced,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: changed.Generation,
+			Reason:             reasonAlarmsSynced,
+			Message:            "the CloudWatch alarms of the agent are up to date",
+		}
+		if err != nil {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = reasonAlarmsFailed
+			condition.Message = err.Error()
+		}
+		meta.SetStatusCondition(&changed.Status.Conditions, condition)
+	}
+}
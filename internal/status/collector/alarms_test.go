@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestWithAlarms(t *testing.T) {
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Generation: 2},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Alarms: &v1alpha1.AlarmsSpec{ClusterName: "cluster"}},
+	}
+
+	WithAlarms(true, errors.New("failed to describe the alarms: AccessDenied"))(agent)
+	condition := meta.FindStatusCondition(agent.Status.Conditions, v1alpha1.ConditionTypeAlarmsSynced)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "failed to describe the alarms: AccessDenied", condition.Message)
+
+	WithAlarms(true, nil)(agent)
+	assert.True(t, meta.IsStatusConditionTrue(agent.Status.Conditions, v1alpha1.ConditionTypeAlarmsSynced))
+
+	// the condition is removed when the operator doesn't manage the alarms of the agent
+	WithAlarms(false, nil)(agent)
+	assert.Nil(t, meta.FindStatusCondition(agent.Status.Conditions, v1alpha1.ConditionTypeAlarmsSynced))
+}
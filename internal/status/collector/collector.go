@@ -9,10 +9,13 @@ import (
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/imagedigest"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
@@ -25,32 +28,51 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1al
 		changed.Status.Version = version.AmazonCloudWatchAgent()
 	}
 	mode := changed.Spec.Mode
-	if mode != v1alpha1.ModeDeployment && mode != v1alpha1.ModeStatefulSet {
+	if mode != v1alpha1.ModeDeployment && mode != v1alpha1.ModeStatefulSet && mode != v1alpha1.ModeDaemonSet {
 		changed.Status.Scale.Replicas = 0
 		changed.Status.Scale.Selector = ""
 		return nil
 	}
 
-	name := naming.Collector(changed.Name)
+	condition, err := credentialsCondition(ctx, cli, changed)
+	if err != nil {
+		return err
+	}
+	meta.SetStatusCondition(&changed.Status.Conditions, condition)
 
-	// Set the scale selector
-	labels := manifestutils.Labels(changed.ObjectMeta, name, changed.Spec.Image, collector.ComponentAmazonCloudWatchAgent, []string{})
-	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: labels})
+	condition, err = serviceAccountCondition(ctx, cli, changed)
 	if err != nil {
-		return fmt.Errorf("failed to get selector for labelSelector: %w", err)
+		return err
+	}
+	meta.SetStatusCondition(&changed.Status.Conditions, condition)
+
+	name := naming.Collector(changed.ResourceName())
+
+	// Set the scale selector, daemonsets can't be scaled
+	changed.Status.Scale.Selector = ""
+	if mode != v1alpha1.ModeDaemonSet {
+		labels := manifestutils.Labels(changed.ObjectMeta, name, changed.Spec.Image, collector.ComponentAmazonCloudWatchAgent, []string{})
+		selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: labels})
+		if err != nil {
+			return fmt.Errorf("failed to get selector for labelSelector: %w", err)
+		}
+		changed.Status.Scale.Selector = selector.String()
 	}
-	changed.Status.Scale.Selector = selector.String()
 
 	// Set the scale replicas
 	objKey := client.ObjectKey{
 		Namespace: changed.GetNamespace(),
-		Name:      naming.Collector(changed.Name),
+		Name:      naming.Collector(changed.ResourceName()),
 	}
 
 	var replicas int32
 	var readyReplicas int32
 	var statusReplicas string
 	var statusImage string
+	// the pods of the workload checked against the resource quotas
+	var template corev1.PodTemplateSpec
+	var selector *metav1.LabelSelector
+	var desired int32
 
 	switch mode { // nolint:exhaustive
 	case v1alpha1.ModeDeployment:
@@ -61,7 +83,8 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1al
 		replicas = obj.Status.Replicas
 		readyReplicas = obj.Status.ReadyReplicas
 		statusReplicas = strconv.Itoa(int(readyReplicas)) + "/" + strconv.Itoa(int(replicas))
-		statusImage = obj.Spec.Template.Spec.Containers[0].Image
+		statusImage = agentImage(obj.Spec.Template)
+		template, selector, desired = obj.Spec.Template, obj.Spec.Selector, replicasOrDefault(obj.Spec.Replicas)
 
 	case v1alpha1.ModeStatefulSet:
 		obj := &appsv1.StatefulSet{}
@@ -71,18 +94,54 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1al
 		replicas = obj.Status.Replicas
 		readyReplicas = obj.Status.ReadyReplicas
 		statusReplicas = strconv.Itoa(int(readyReplicas)) + "/" + strconv.Itoa(int(replicas))
-		statusImage = obj.Spec.Template.Spec.Containers[0].Image
+		statusImage = agentImage(obj.Spec.Template)
+		template, selector, desired = obj.Spec.Template, obj.Spec.Selector, replicasOrDefault(obj.Spec.Replicas)
 
 	case v1alpha1.ModeDaemonSet:
 		obj := &appsv1.DaemonSet{}
 		if err := cli.Get(ctx, objKey, obj); err != nil {
 			return fmt.Errorf("failed to get daemonSet status.replicas: %w", err)
 		}
-		statusImage = obj.Spec.Template.Spec.Containers[0].Image
+		statusReplicas = strconv.Itoa(int(obj.Status.NumberReady)) + "/" + strconv.Itoa(int(obj.Status.DesiredNumberScheduled))
+		statusImage = agentImage(obj.Spec.Template)
+		template, selector, desired = obj.Spec.Template, obj.Spec.Selector, obj.Status.DesiredNumberScheduled
+
+		versionSplit, err := versionSplitStatus(ctx, cli, changed, obj)
+		if err != nil {
+			return err
+		}
+		changed.Status.VersionSplit = versionSplit
 	}
 	changed.Status.Scale.Replicas = replicas
 	changed.Status.Image = statusImage
+	changed.Status.ImageDigest = imagedigest.Digest(statusImage)
 	changed.Status.Scale.StatusReplicas = statusReplicas
 
+	workloads, err := workloadsStatus(ctx, cli, changed)
+	if err != nil {
+		return err
+	}
+	changed.Status.Workloads = workloads
+
+	configHashes, err := configHashesStatus(ctx, cli, changed, workloads)
+	if err != nil {
+		return err
+	}
+	changed.Status.ConfigHashes = configHashes
+
+	quota, err := quotaCondition(ctx, cli, changed, template, selector, desired)
+	if err != nil {
+		return err
+	}
+	meta.SetStatusCondition(&changed.Status.Conditions, quota)
+
 	return nil
 }
+
+// replicasOrDefault returns the replicas of a workload, which defaults to 1.
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+const (
+	reasonSupportedVersions   = "SupportedVersions"
+	reasonUnsupportedVersions = "UnsupportedVersions"
+)
+
+// reportCompatibility sets the condition telling whether the versions of the operator, of the agent image and of
+// Kubernetes are supported together, and records an event when they stop being so. The condition is removed when the
+// versions aren't checked.
+func reportCompatibility(params manifests.Params, changed *v1alpha1.AmazonCloudWatchAgent) {
+	checker := params.Config.CompatibilityChecker()
+	if checker == nil {
+		meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.ConditionTypeVersionsSupported)
+		return
+	}
+	image := changed.Spec.Image
+	if image == "" {
+		image = params.Config.CollectorImage()
+	}
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionTypeVersionsSupported,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: changed.Generation,
+		Reason:             reasonSupportedVersions,
+		Message:            "the versions of the operator, of the agent and of Kubernetes are supported together",
+	}
+	if problems := checker.Check(image); len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonUnsupportedVersions
+		condition.Message = strings.Join(problems, "; ")
+	}
+	previous := meta.FindStatusCondition(params.OtelCol.Status.Conditions, v1alpha1.ConditionTypeVersionsSupported)
+	if condition.Status == metav1.ConditionFalse && (previous == nil || previous.Message != condition.Message) {
+		params.Recorder.Event(changed, eventTypeWarning, reasonUnsupportedVersions, condition.Message)
+	}
+	meta.SetStatusCondition(&changed.Status.Conditions, condition)
+}
@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/compatibility"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestReportCompatibility(t *testing.T) {
+	checker := &compatibility.Checker{
+		Matrix: &compatibility.Matrix{Entries: []compatibility.Entry{{
+			Operator: compatibility.Range{Min: "2.0.0"},
+			Agent:    compatibility.Range{Min: "1.300040.0"},
+		}}},
+		Operator: "2.0.1",
+	}
+	cfg := config.New(config.WithCollectorImage("cloudwatch-agent:1.300045.0"), config.WithCompatibilityChecker(checker))
+
+	// the default image is supported
+	recorder := record.NewFakeRecorder(10)
+	agent := v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Generation: 1}}
+	changed := agent.DeepCopy()
+	reportCompatibility(manifests.Params{Config: cfg, OtelCol: agent, Recorder: recorder}, changed)
+	assert.True(t, meta.IsStatusConditionTrue(changed.Status.Conditions, v1alpha1.ConditionTypeVersionsSupported))
+	assert.Empty(t, recorder.Events)
+
+	// the image of the agent isn't, which is recorded once
+	agent = *changed
+	agent.Spec.Image = "cloudwatch-agent:1.300039.0"
+	for i := 0; i < 2; i++ {
+		changed = agent.DeepCopy()
+		reportCompatibility(manifests.Params{Config: cfg, OtelCol: agent, Recorder: recorder}, changed)
+		agent = *changed
+	}
+	condition := meta.FindStatusCondition(agent.Status.Conditions, v1alpha1.ConditionTypeVersionsSupported)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "the agent version 1.300039.0 is not supported by the operator version 2.0.1, which supports versions 1.300040.0 and later", condition.Message)
+	assert.Len(t, recorder.Events, 1)
+
+	// the condition is removed when the versions aren't checked
+	changed = agent.DeepCopy()
+	reportCompatibility(manifests.Params{Config: config.New(), OtelCol: agent, Recorder: recorder}, changed)
+	assert.Nil(t, meta.FindStatusCondition(changed.Status.Conditions, v1alpha1.ConditionTypeVersionsSupported))
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// configHashesStatus counts the agent pods of the instance by the config hash they are labeled with, sorted by hash.
+// A hash is current when a workload of the instance templates its pods with it; the terminating pods are left out.
+func configHashesStatus(ctx context.Context, cli client.Client, changed *v1alpha1.AmazonCloudWatchAgent, workloads []v1alpha1.WorkloadStatus) ([]v1alpha1.ConfigHashStatus, error) {
+	pods := &corev1.PodList{}
+	opts := []client.ListOption{
+		client.InNamespace(changed.Namespace),
+		client.MatchingLabels(manifestutils.SelectorLabels(changed.ObjectMeta, collector.ComponentAmazonCloudWatchAgent)),
+	}
+	if err := cli.List(ctx, pods, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list the agent pods: %w", err)
+	}
+
+	current := map[string]bool{}
+	for _, workload := range workloads {
+		if workload.ConfigHash != "" {
+			current[workload.ConfigHash] = true
+		}
+	}
+	byHash := map[string]*v1alpha1.ConfigHashStatus{}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		hash := pod.Labels[constants.LabelConfigHash]
+		status, ok := byHash[hash]
+		if !ok {
+			status = &v1alpha1.ConfigHashStatus{Hash: hash, Current: current[hash]}
+			byHash[hash] = status
+		}
+		status.Pods++
+		if podReady(pod) {
+			status.Ready++
+		}
+	}
+
+	var result []v1alpha1.ConfigHashStatus
+	for _, status := range byHash {
+		result = append(result, *status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Hash < result[j].Hash
+	})
+	return result, nil
+}
+
+// podReady tells whether the Ready condition of the pod is true.
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
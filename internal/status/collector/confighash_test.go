@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestConfigHashesStatus(t *testing.T) {
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+	}
+	now := metav1.Now()
+	pod := func(name, hash string, ready bool) *corev1.Pod {
+		labels := manifestutils.SelectorLabels(agent.ObjectMeta, collector.ComponentAmazonCloudWatchAgent)
+		if hash != "" {
+			labels[constants.LabelConfigHash] = hash
+		}
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: agent.Namespace, Labels: labels},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+		}
+	}
+	terminating := pod("agent-terminating", "new", true)
+	terminating.DeletionTimestamp = &now
+	terminating.Finalizers = []string{"test"}
+	cli := fake.NewClientBuilder().WithObjects(
+		pod("agent-a", "new", true),
+		pod("agent-b", "new", false),
+		pod("agent-c", "old", true),
+		pod("agent-d", "", true),
+		terminating,
+		// the pods of other instances are left out
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: agent.Namespace, Labels: map[string]string{
+			"app.kubernetes.io/instance": "amazon-cloudwatch.other",
+			constants.LabelConfigHash:    "new",
+		}}},
+	).Build()
+
+	hashes, err := configHashesStatus(context.Background(), cli, agent, []v1alpha1.WorkloadStatus{
+		{Kind: "DaemonSet", Name: "agent", ConfigHash: "new"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []v1alpha1.ConfigHashStatus{
+		{Hash: "", Pods: 1, Ready: 1},
+		{Hash: "new", Current: true, Pods: 2, Ready: 1},
+		{Hash: "old", Pods: 1, Ready: 1},
+	}, hashes)
+}
@@ -0,0 +1,81 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+const (
+	reasonIRSA               = "IRSA"
+	reasonEnvironment        = "Environment"
+	reasonAgentConfig        = "AgentConfig"
+	reasonNoCredentialsFound = "NoCredentialsFound"
+)
+
+// credentialsEnvVars are the environment variables the AWS SDK reads credentials from.
+var credentialsEnvVars = []string{
+	"AWS_ACCESS_KEY_ID",
+	"AWS_ROLE_ARN",
+	"AWS_WEB_IDENTITY_TOKEN_FILE",
+	"AWS_SHARED_CREDENTIALS_FILE",
+	"AWS_CONTAINER_CREDENTIALS_FULL_URI",
+	"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI",
+}
+
+// credentialsCondition tells whether the agent has credentials which the operator can discover: an IRSA role on its
+// service account, credentials in its environment or in the agent config.
+func credentialsCondition(ctx context.Context, cli client.Client, instance *v1alpha1.AmazonCloudWatchAgent) (metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionTypeCredentialsAvailable,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: instance.Generation,
+	}
+
+	sa := &corev1.ServiceAccount{}
+	key := client.ObjectKey{Namespace: instance.Namespace, Name: collector.ServiceAccountName(*instance)}
+	if err := cli.Get(ctx, key, sa); err != nil && !apierrors.IsNotFound(err) {
+		return condition, fmt.Errorf("failed to get service account: %w", err)
+	}
+	if roleArn := sa.Annotations[constants.AnnotationIAMRoleArn]; roleArn != "" {
+		condition.Reason = reasonIRSA
+		condition.Message = fmt.Sprintf("the service account %s assumes the IAM role %s", key.Name, roleArn)
+		return condition, nil
+	}
+
+	for _, env := range instance.Spec.Env {
+		for _, name := range credentialsEnvVars {
+			if env.Name == name {
+				condition.Reason = reasonEnvironment
+				condition.Message = fmt.Sprintf("the environment variable %s provides the credentials", name)
+				return condition, nil
+			}
+		}
+	}
+
+	if config, err := adapters.ConfigFromJSONString(instance.Spec.Config); err == nil {
+		if agent, ok := config["agent"].(map[string]interface{}); ok && agent["credentials"] != nil {
+			condition.Reason = reasonAgentConfig
+			condition.Message = "the agent config provides the credentials"
+			return condition, nil
+		}
+	}
+
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = reasonNoCredentialsFound
+	condition.Message = fmt.Sprintf("no IAM role is set on the service account %s and no credentials are set in the environment or the agent config, "+
+		"the agent relies on EKS Pod Identity or the node instance role", key.Name)
+	return condition, nil
+}
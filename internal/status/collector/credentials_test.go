@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestCredentialsCondition(t *testing.T) {
+	irsa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "irsa",
+			Namespace:   "default",
+			Annotations: map[string]string{constants.AnnotationIAMRoleArn: "arn:aws:iam::123456789012:role/agent"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		objects        []client.Object
+		spec           v1alpha1.AmazonCloudWatchAgentSpec
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "irsa",
+			objects:        []client.Object{irsa},
+			spec:           v1alpha1.AmazonCloudWatchAgentSpec{ServiceAccount: "irsa"},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: reasonIRSA,
+		},
+		{
+			name:           "environment",
+			spec:           v1alpha1.AmazonCloudWatchAgentSpec{Env: []corev1.EnvVar{{Name: "AWS_ACCESS_KEY_ID", Value: "key"}}},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: reasonEnvironment,
+		},
+		{
+			name:           "agent config",
+			spec:           v1alpha1.AmazonCloudWatchAgentSpec{Config: `{"agent":{"credentials":{"role_arn":"arn:aws:iam::123456789012:role/agent"}}}`},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: reasonAgentConfig,
+		},
+		{
+			name:           "none",
+			spec:           v1alpha1.AmazonCloudWatchAgentSpec{Config: `{"agent":{}}`},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: reasonNoCredentialsFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			instance := &v1alpha1.AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			condition, err := credentialsCondition(context.Background(), cli, instance)
+			require.NoError(t, err)
+			assert.Equal(t, v1alpha1.ConditionTypeCredentialsAvailable, condition.Type)
+			assert.Equal(t, tt.expectedStatus, condition.Status)
+			assert.Equal(t, tt.expectedReason, condition.Reason)
+		})
+	}
+}
@@ -5,12 +5,16 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
 
@@ -21,22 +25,50 @@ const (
 	reasonError         = "Error"
 	reasonStatusFailure = "StatusFailure"
 	reasonInfo          = "Info"
+
+	reasonObjectNotOwned = "ObjectNotOwned"
+	reasonInvalidConfig  = "InvalidConfig"
+	reasonHostPortInUse  = "HostPortInUse"
+	reasonUnmanaged      = "ManagementStateUnmanaged"
 )
 
-// HandleReconcileStatus handles updating the status of the CRDs managed by the operator.
+// HandleReconcileStatus handles updating the status of the CRDs managed by the operator, changed by the given
+// options once the reconciliation succeeded.
 // TODO: make the status more useful https://github.com/open-telemetry/opentelemetry-operator/issues/1972
-func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifests.Params, err error) (ctrl.Result, error) {
+func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifests.Params, err error, opts ...StatusOption) (ctrl.Result, error) {
 	log.V(2).Info("updating collector status")
 	if err != nil {
 		params.Recorder.Event(&params.OtelCol, eventTypeWarning, reasonError, err.Error())
+		if errors.Is(err, manifests.ErrNotOwned) {
+			changed := params.OtelCol.DeepCopy()
+			meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+				Type:               v1alpha1.ConditionTypeOwnershipConflict,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: changed.Generation,
+				Reason:             reasonObjectNotOwned,
+				Message:            err.Error(),
+			})
+			if patchErr := params.Client.Status().Patch(ctx, changed, client.MergeFrom(&params.OtelCol)); patchErr != nil {
+				log.Error(patchErr, "failed to report the ownership conflict")
+			}
+		}
 		return ctrl.Result{}, err
 	}
 	changed := params.OtelCol.DeepCopy()
+	meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.ConditionTypeOwnershipConflict)
+	meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.ConditionTypeDegraded)
+	meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.ConditionTypeHostPortConflict)
+	meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.ConditionTypeUnmanaged)
 	statusErr := UpdateCollectorStatus(ctx, params.Client, changed)
 	if statusErr != nil {
 		params.Recorder.Event(changed, eventTypeWarning, reasonStatusFailure, statusErr.Error())
 		return ctrl.Result{}, statusErr
 	}
+	reportConfigWarnings(ctx, log, params, changed)
+	reportCompatibility(params, changed)
+	for _, opt := range opts {
+		opt(changed)
+	}
 	statusPatch := client.MergeFrom(&params.OtelCol)
 	if err := params.Client.Status().Patch(ctx, changed, statusPatch); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the AmazonCloudWatchAgent CR: %w", err)
@@ -44,3 +76,78 @@ func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifest
 	params.Recorder.Event(changed, eventTypeNormal, reasonInfo, "applied status changes")
 	return ctrl.Result{}, nil
 }
+
+// HandleInvalidConfig reports the configs of the instance failed the validation, leaving the agents on the last
+// good configs until the instance is fixed.
+func HandleInvalidConfig(ctx context.Context, log logr.Logger, params manifests.Params, err error) (ctrl.Result, error) {
+	log.Info("holding the rollout of an invalid config", "reason", err.Error())
+	params.Recorder.Event(&params.OtelCol, eventTypeWarning, reasonInvalidConfig, err.Error())
+	changed := params.OtelCol.DeepCopy()
+	meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionTypeDegraded,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: changed.Generation,
+		Reason:             reasonInvalidConfig,
+		Message:            err.Error(),
+	})
+	if patchErr := params.Client.Status().Patch(ctx, changed, client.MergeFrom(&params.OtelCol)); patchErr != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to report the invalid config: %w", patchErr)
+	}
+	return ctrl.Result{}, nil
+}
+
+// HandleHostPortConflict reports the daemonset of the instance binds a host port already used on the same nodes,
+// holding its rollout until the conflict is solved.
+func HandleHostPortConflict(ctx context.Context, log logr.Logger, params manifests.Params, message string) (ctrl.Result, error) {
+	log.Info("holding the rollout of a daemonset with conflicting host ports", "reason", message)
+	params.Recorder.Event(&params.OtelCol, eventTypeWarning, reasonHostPortInUse, message)
+	changed := params.OtelCol.DeepCopy()
+	meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionTypeHostPortConflict,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: changed.Generation,
+		Reason:             reasonHostPortInUse,
+		Message:            message,
+	})
+	if patchErr := params.Client.Status().Patch(ctx, changed, client.MergeFrom(&params.OtelCol)); patchErr != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to report the host port conflict: %w", patchErr)
+	}
+	return ctrl.Result{}, nil
+}
+
+// HandleUnmanaged reports the operator no longer updates the objects of the unmanaged instance, and keeps the status
+// of its workload up to date. The workload may have been changed or deleted by hand, so failing to read it only
+// leaves the previous status.
+func HandleUnmanaged(ctx context.Context, log logr.Logger, params manifests.Params) (ctrl.Result, error) {
+	log.V(2).Info("updating the status of an unmanaged collector")
+	changed := params.OtelCol.DeepCopy()
+	if err := UpdateCollectorStatus(ctx, params.Client, changed); err != nil {
+		log.V(2).Info("unable to update the status of the unmanaged collector", "reason", err.Error())
+	}
+	meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionTypeUnmanaged,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: changed.Generation,
+		Reason:             reasonUnmanaged,
+		Message:            "the operator doesn't update the objects of the AmazonCloudWatchAgent",
+	})
+	if patchErr := params.Client.Status().Patch(ctx, changed, client.MergeFrom(&params.OtelCol)); patchErr != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the AmazonCloudWatchAgent CR: %w", patchErr)
+	}
+	return ctrl.Result{}, nil
+}
+
+// reportConfigWarnings sets the condition of the warnings about the configs of the instance, and records them in an
+// event when they change. Failing to analyze the configs leaves the previous condition.
+func reportConfigWarnings(ctx context.Context, log logr.Logger, params manifests.Params, changed *v1alpha1.AmazonCloudWatchAgent) {
+	condition, err := lintCondition(ctx, params.Client, changed)
+	if err != nil {
+		log.V(2).Info("unable to analyze the configs", "reason", err.Error())
+		return
+	}
+	previous := meta.FindStatusCondition(params.OtelCol.Status.Conditions, v1alpha1.ConditionTypeConfigWarnings)
+	if condition.Status == metav1.ConditionTrue && (previous == nil || previous.Message != condition.Message) {
+		params.Recorder.Event(changed, eventTypeWarning, reasonConfigWarnings, condition.Message)
+	}
+	meta.SetStatusCondition(&changed.Status.Conditions, condition)
+}
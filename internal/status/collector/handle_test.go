@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestHandleUnmanaged(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode:            v1alpha1.ModeDaemonSet,
+			ManagementState: v1alpha1.ManagementStateUnmanaged,
+		},
+	}
+	// the daemonset was changed by hand to debug the agent
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: agent.Namespace},
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "otc-container", Image: "cloudwatch-agent:debug"}},
+		}}},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent, daemonSet).WithStatusSubresource(agent).Build()
+
+	_, err := HandleUnmanaged(ctx, logr.Discard(), manifests.Params{Client: cli, OtelCol: *agent})
+	require.NoError(t, err)
+
+	updated := &v1alpha1.AmazonCloudWatchAgent{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(agent), updated))
+	assert.Equal(t, "cloudwatch-agent:debug", updated.Status.Image)
+	assert.Equal(t, "2/3", updated.Status.Scale.StatusReplicas)
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, v1alpha1.ConditionTypeUnmanaged))
+}
@@ -0,0 +1,175 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+const (
+	reasonNoConfigWarnings = "NoConfigWarnings"
+	reasonConfigWarnings   = "ConfigWarnings"
+
+	// emfPort is the port the agent receives the embedded metric format on.
+	emfPort = 25888
+	// maxForceFlushInterval is the force_flush_interval, in seconds, above which the logs reach CloudWatch late.
+	maxForceFlushInterval = 60
+)
+
+// regionEnvVars are the environment variables the AWS SDK reads the region from.
+var regionEnvVars = []string{"AWS_REGION", "AWS_DEFAULT_REGION"}
+
+// lintCondition analyzes the configs of the instance for the common mistakes the validation accepts, as the agents
+// run with them but don't behave as the users expect.
+func lintCondition(ctx context.Context, cli client.Client, instance *v1alpha1.AmazonCloudWatchAgent) (metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionTypeConfigWarnings,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: instance.Generation,
+		Reason:             reasonNoConfigWarnings,
+		Message:            "no common mistake was found in the configs",
+	}
+
+	config, err := adapters.ConfigFromJSONString(instance.Spec.Config)
+	if err != nil {
+		// the validation reports the config which can't be parsed
+		return condition, nil
+	}
+	var warnings []string
+	warnings = append(warnings, lintEMFPorts(config, instance.Spec.Ports)...)
+	warnings = append(warnings, lintForceFlushInterval(config)...)
+	warnings = append(warnings, lintRegion(config, instance.Spec.Env)...)
+	prometheus, err := lintPrometheusRBAC(ctx, cli, instance, config)
+	if err != nil {
+		return condition, err
+	}
+	warnings = append(warnings, prometheus...)
+
+	if len(warnings) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonConfigWarnings
+		condition.Message = strings.Join(warnings, "; ")
+	}
+	return condition, nil
+}
+
+// lintEMFPorts warns about the EMF ports of the spec which don't match the port the agent receives EMF on.
+func lintEMFPorts(config map[string]interface{}, ports []corev1.ServicePort) []string {
+	_, emfEnabled := jsonPath(config, "logs", "metrics_collected", "emf")
+	var warnings []string
+	for _, port := range ports {
+		if !strings.HasPrefix(port.Name, collector.EMF) {
+			continue
+		}
+		switch {
+		case !emfEnabled:
+			warnings = append(warnings, fmt.Sprintf("the port %s is exposed for EMF, which the agent config doesn't collect", port.Name))
+		case port.Port != emfPort:
+			warnings = append(warnings, fmt.Sprintf("the port %s exposes %d, while the agent receives EMF on %d", port.Name, port.Port, emfPort))
+		}
+	}
+	return warnings
+}
+
+// lintForceFlushInterval warns about a force_flush_interval delaying the logs.
+func lintForceFlushInterval(config map[string]interface{}) []string {
+	value, ok := jsonPath(config, "logs", "force_flush_interval")
+	if !ok {
+		return nil
+	}
+	if interval, ok := value.(float64); ok && interval > maxForceFlushInterval {
+		return []string{fmt.Sprintf("the logs force_flush_interval of %vs delays the logs by more than %ds", interval, maxForceFlushInterval)}
+	}
+	return nil
+}
+
+// lintRegion warns about an agent without region, which then relies on the instance metadata that the pods may not
+// reach.
+func lintRegion(config map[string]interface{}, env []corev1.EnvVar) []string {
+	if region, ok := jsonPath(config, "agent", "region"); ok && region != "" {
+		return nil
+	}
+	for _, e := range env {
+		for _, name := range regionEnvVars {
+			if e.Name == name {
+				return nil
+			}
+		}
+	}
+	return []string{"no region is set in the agent config or the environment, the agent reads it from the instance metadata"}
+}
+
+// lintPrometheusRBAC warns about an agent scraping Prometheus targets while its service account can't list the pods
+// to discover them.
+func lintPrometheusRBAC(ctx context.Context, cli client.Client, instance *v1alpha1.AmazonCloudWatchAgent, config map[string]interface{}) ([]string, error) {
+	if !scrapesPrometheus(instance, config) {
+		return nil, nil
+	}
+	serviceAccount := collector.ServiceAccountName(*instance)
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   fmt.Sprintf("system:serviceaccount:%s:%s", instance.Namespace, serviceAccount),
+			Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + instance.Namespace},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "list",
+				Resource: "pods",
+			},
+		},
+	}
+	if err := cli.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to review the access of the service account: %w", err)
+	}
+	if review.Status.Allowed {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("the agent scrapes Prometheus targets, but its service account %s can't list the pods to discover them", serviceAccount)}, nil
+}
+
+// scrapesPrometheus tells whether the agent runs a Prometheus receiver, through its config, its OpenTelemetry config or
+// its Prometheus config.
+func scrapesPrometheus(instance *v1alpha1.AmazonCloudWatchAgent, config map[string]interface{}) bool {
+	if _, ok := jsonPath(config, "logs", "metrics_collected", "prometheus"); ok {
+		return true
+	}
+	if !instance.Spec.Prometheus.IsEmpty() {
+		return true
+	}
+	otelConfig, err := adapters.ConfigFromString(instance.Spec.OtelConfig)
+	if err != nil {
+		return false
+	}
+	receivers, _ := otelConfig["receivers"].(map[interface{}]interface{})
+	for id := range receivers {
+		if name, ok := id.(string); ok && (name == "prometheus" || strings.HasPrefix(name, "prometheus/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonPath returns the value at the path of keys of a JSON config.
+func jsonPath(config map[string]interface{}, keys ...string) (interface{}, bool) {
+	var value interface{} = config
+	for _, key := range keys {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
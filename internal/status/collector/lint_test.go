@@ -0,0 +1,86 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestLintCondition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	// the service accounts of the agents in the amazon-cloudwatch namespace can list the pods
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if review, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+				review.Status.Allowed = review.Spec.User == "system:serviceaccount:amazon-cloudwatch:agent"
+				return nil
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	agent := func(namespace, config string, ports ...corev1.ServicePort) *v1alpha1.AmazonCloudWatchAgent {
+		return &v1alpha1.AmazonCloudWatchAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: namespace},
+			Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Config: config, Ports: ports, ServiceAccount: "agent"},
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		agent    *v1alpha1.AmazonCloudWatchAgent
+		expected string
+	}{
+		{
+			name:  "without warnings",
+			agent: agent("amazon-cloudwatch", `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"emf":{},"prometheus":{}}}}`, corev1.ServicePort{Name: "emf-tcp", Port: 25888}),
+		},
+		{
+			name:     "with EMF port mismatch",
+			agent:    agent("amazon-cloudwatch", `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"emf":{}}}}`, corev1.ServicePort{Name: "emf-tcp", Port: 25999}),
+			expected: "the port emf-tcp exposes 25999, while the agent receives EMF on 25888",
+		},
+		{
+			name:     "with EMF port but no EMF",
+			agent:    agent("amazon-cloudwatch", `{"agent":{"region":"us-west-2"}}`, corev1.ServicePort{Name: "emf", Port: 25888}),
+			expected: "the port emf is exposed for EMF, which the agent config doesn't collect",
+		},
+		{
+			name:     "with high force_flush_interval and no region",
+			agent:    agent("amazon-cloudwatch", `{"logs":{"force_flush_interval":300}}`),
+			expected: "the logs force_flush_interval of 300s delays the logs by more than 60s; no region is set in the agent config or the environment, the agent reads it from the instance metadata",
+		},
+		{
+			name:     "with Prometheus without RBAC",
+			agent:    agent("team", `{"agent":{"region":"us-west-2"},"logs":{"metrics_collected":{"prometheus":{}}}}`),
+			expected: "the agent scrapes Prometheus targets, but its service account agent can't list the pods to discover them",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := lintCondition(context.Background(), cli, tt.agent)
+			require.NoError(t, err)
+			assert.Equal(t, v1alpha1.ConditionTypeConfigWarnings, condition.Type)
+			if tt.expected == "" {
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				return
+			}
+			assert.Equal(t, metav1.ConditionTrue, condition.Status)
+			assert.Equal(t, tt.expected, condition.Message)
+		})
+	}
+}
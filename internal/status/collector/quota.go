@@ -0,0 +1,235 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	reasonNoResourceQuota       = "NoResourceQuota"
+	reasonWithinResourceQuota   = "WithinResourceQuota"
+	reasonResourceQuotaExceeded = "ResourceQuotaExceeded"
+)
+
+// quotaCondition tells whether the resource quotas of the namespace of the instance leave room for all the agent
+// pods of the workload. The pods already running are deducted from the used resources, so that the condition
+// reports what the agent needs against what the rest of the namespace leaves available.
+func quotaCondition(ctx context.Context, cli client.Client, instance *v1alpha1.AmazonCloudWatchAgent, template corev1.PodTemplateSpec, selector *metav1.LabelSelector, desired int32) (metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionTypeQuotaExceeded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: instance.Generation,
+		Reason:             reasonNoResourceQuota,
+		Message:            "no resource quota applies to the agent pods",
+	}
+
+	quotas := &corev1.ResourceQuotaList{}
+	if err := cli.List(ctx, quotas, client.InNamespace(instance.Namespace)); err != nil {
+		return condition, fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+	if len(quotas.Items) == 0 {
+		return condition, nil
+	}
+
+	podSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return condition, fmt.Errorf("failed to get selector for labelSelector: %w", err)
+	}
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: podSelector}); err != nil {
+		return condition, fmt.Errorf("failed to list the agent pods: %w", err)
+	}
+
+	pod := corev1.Pod{ObjectMeta: template.ObjectMeta, Spec: template.Spec}
+	var exceeded []string
+	var applied []string
+	for _, quota := range quotas.Items {
+		if !quotaMatchesPod(quota, pod) {
+			continue
+		}
+		applied = append(applied, quota.Name)
+
+		needed := scaleResources(podUsage(pod, quota.Status.Hard), int64(desired))
+		running := corev1.ResourceList{}
+		for _, existing := range pods.Items {
+			if existing.Status.Phase == corev1.PodSucceeded || existing.Status.Phase == corev1.PodFailed || !quotaMatchesPod(quota, existing) {
+				continue
+			}
+			addResources(running, podUsage(existing, quota.Status.Hard))
+		}
+
+		names := make([]string, 0, len(needed))
+		for name := range needed {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			resourceName := corev1.ResourceName(name)
+			hard, used := quota.Status.Hard[resourceName], quota.Status.Used[resourceName]
+			available := hard.DeepCopy()
+			available.Sub(used)
+			available.Add(running[resourceName])
+			need := needed[resourceName]
+			if need.Cmp(available) > 0 {
+				exceeded = append(exceeded, fmt.Sprintf("the resource quota %s leaves %s of %s available while the %d agent pods need %s",
+					quota.Name, available.String(), name, desired, need.String()))
+			}
+		}
+	}
+
+	switch {
+	case len(exceeded) > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonResourceQuotaExceeded
+		condition.Message = strings.Join(exceeded, "; ")
+	case len(applied) > 0:
+		condition.Reason = reasonWithinResourceQuota
+		condition.Message = fmt.Sprintf("the resource quotas %s leave room for the %d agent pods", strings.Join(applied, ", "), desired)
+	}
+	return condition, nil
+}
+
+// podUsage returns the usage of the pod of the resources constrained by a quota, following the quota evaluation of
+// the API server: the effective requests and limits of the pod, the init containers running one at a time.
+func podUsage(pod corev1.Pod, hard corev1.ResourceList) corev1.ResourceList {
+	requests, limits := podResources(pod.Spec)
+	usage := corev1.ResourceList{}
+	for name := range hard {
+		switch name {
+		case corev1.ResourcePods:
+			usage[name] = *resource.NewQuantity(1, resource.DecimalSI)
+		case corev1.ResourceCPU, corev1.ResourceRequestsCPU:
+			usage[name] = requests[corev1.ResourceCPU]
+		case corev1.ResourceMemory, corev1.ResourceRequestsMemory:
+			usage[name] = requests[corev1.ResourceMemory]
+		case corev1.ResourceEphemeralStorage, corev1.ResourceRequestsEphemeralStorage:
+			usage[name] = requests[corev1.ResourceEphemeralStorage]
+		case corev1.ResourceLimitsCPU:
+			usage[name] = limits[corev1.ResourceCPU]
+		case corev1.ResourceLimitsMemory:
+			usage[name] = limits[corev1.ResourceMemory]
+		case corev1.ResourceLimitsEphemeralStorage:
+			usage[name] = limits[corev1.ResourceEphemeralStorage]
+		}
+	}
+	return usage
+}
+
+// podResources returns the effective requests and limits of a pod.
+func podResources(spec corev1.PodSpec) (corev1.ResourceList, corev1.ResourceList) {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range spec.Containers {
+		addResources(requests, container.Resources.Requests)
+		addResources(limits, container.Resources.Limits)
+	}
+	for _, container := range spec.InitContainers {
+		maxResources(requests, container.Resources.Requests)
+		maxResources(limits, container.Resources.Limits)
+	}
+	addResources(requests, spec.Overhead)
+	addResources(limits, spec.Overhead)
+	return requests, limits
+}
+
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func maxResources(total, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func scaleResources(list corev1.ResourceList, factor int64) corev1.ResourceList {
+	scaled := corev1.ResourceList{}
+	for name, quantity := range list {
+		scaled[name] = *resource.NewMilliQuantity(quantity.MilliValue()*factor, quantity.Format)
+	}
+	return scaled
+}
+
+// quotaMatchesPod tells whether the scopes of the quota select the pod, such as the PriorityClass scope selecting
+// the pods of some priority classes.
+func quotaMatchesPod(quota corev1.ResourceQuota, pod corev1.Pod) bool {
+	for _, scope := range quota.Spec.Scopes {
+		if !scopeMatchesPod(corev1.ScopedResourceSelectorRequirement{ScopeName: scope, Operator: corev1.ScopeSelectorOpExists}, pod) {
+			return false
+		}
+	}
+	if quota.Spec.ScopeSelector != nil {
+		for _, requirement := range quota.Spec.ScopeSelector.MatchExpressions {
+			if !scopeMatchesPod(requirement, pod) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func scopeMatchesPod(requirement corev1.ScopedResourceSelectorRequirement, pod corev1.Pod) bool {
+	if requirement.ScopeName == corev1.ResourceQuotaScopePriorityClass {
+		var operator selection.Operator
+		switch requirement.Operator {
+		case corev1.ScopeSelectorOpIn:
+			operator = selection.In
+		case corev1.ScopeSelectorOpNotIn:
+			operator = selection.NotIn
+		case corev1.ScopeSelectorOpExists:
+			operator = selection.Exists
+		case corev1.ScopeSelectorOpDoesNotExist:
+			operator = selection.DoesNotExist
+		}
+		req, err := labels.NewRequirement("priorityClassName", operator, requirement.Values)
+		if err != nil {
+			return false
+		}
+		set := labels.Set{}
+		if pod.Spec.PriorityClassName != "" {
+			set["priorityClassName"] = pod.Spec.PriorityClassName
+		}
+		return req.Matches(set)
+	}
+
+	// the other scopes only support the Exists and DoesNotExist operators
+	var matches bool
+	requests, limits := podResources(pod.Spec)
+	switch requirement.ScopeName {
+	case corev1.ResourceQuotaScopeTerminating:
+		matches = pod.Spec.ActiveDeadlineSeconds != nil
+	case corev1.ResourceQuotaScopeNotTerminating:
+		matches = pod.Spec.ActiveDeadlineSeconds == nil
+	case corev1.ResourceQuotaScopeBestEffort:
+		matches = len(requests) == 0 && len(limits) == 0
+	case corev1.ResourceQuotaScopeNotBestEffort:
+		matches = len(requests) > 0 || len(limits) > 0
+	case corev1.ResourceQuotaScopeCrossNamespacePodAffinity:
+		matches = false
+	default:
+		matches = true
+	}
+	if requirement.Operator == corev1.ScopeSelectorOpDoesNotExist {
+		return !matches
+	}
+	return matches
+}
@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestQuotaCondition(t *testing.T) {
+	agentLabels := map[string]string{"app": "agent"}
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: agentLabels},
+		Spec: corev1.PodSpec{
+			PriorityClassName: "system-node-critical",
+			Containers: []corev1.Container{{
+				Name: "otc-container",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+			}},
+		},
+	}
+	runningPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-1", Namespace: "default", Labels: agentLabels},
+		Spec:       template.Spec,
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	quota := func(name string, hard, used corev1.ResourceList, scopeSelector *corev1.ScopeSelector) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard, ScopeSelector: scopeSelector},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+	priorityClass := func(operator corev1.ScopeSelectorOperator, values ...string) *corev1.ScopeSelector {
+		return &corev1.ScopeSelector{MatchExpressions: []corev1.ScopedResourceSelectorRequirement{
+			{ScopeName: corev1.ResourceQuotaScopePriorityClass, Operator: operator, Values: values},
+		}}
+	}
+
+	tests := []struct {
+		name            string
+		objects         []client.Object
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "no quota",
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: reasonNoResourceQuota,
+		},
+		{
+			name: "within quota",
+			objects: []client.Object{
+				runningPod,
+				quota("compute", corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")}, corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("750m")}, nil),
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  reasonWithinResourceQuota,
+			expectedMessage: "the resource quotas compute leave room for the 2 agent pods",
+		},
+		{
+			name: "quota exceeded",
+			objects: []client.Object{
+				runningPod,
+				quota("compute", corev1.ResourceList{
+					corev1.ResourceRequestsCPU:    resource.MustParse("1"),
+					corev1.ResourceRequestsMemory: resource.MustParse("1Gi"),
+					corev1.ResourcePods:           resource.MustParse("10"),
+				}, corev1.ResourceList{
+					corev1.ResourceRequestsCPU:    resource.MustParse("1"),
+					corev1.ResourceRequestsMemory: resource.MustParse("256Mi"),
+					corev1.ResourcePods:           resource.MustParse("4"),
+				}, nil),
+			},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  reasonResourceQuotaExceeded,
+			expectedMessage: "the resource quota compute leaves 250m of requests.cpu available while the 2 agent pods need 500m",
+		},
+		{
+			name: "quota of another priority class",
+			objects: []client.Object{
+				quota("batch", corev1.ResourceList{corev1.ResourcePods: resource.MustParse("0")}, nil, priorityClass(corev1.ScopeSelectorOpIn, "batch")),
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: reasonNoResourceQuota,
+		},
+		{
+			name: "quota of the agent priority class",
+			objects: []client.Object{
+				quota("critical", corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}, nil, priorityClass(corev1.ScopeSelectorOpIn, "system-node-critical")),
+			},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  reasonResourceQuotaExceeded,
+			expectedMessage: "the resource quota critical leaves 1 of pods available while the 2 agent pods need 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			instance := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"}}
+			condition, err := quotaCondition(context.Background(), cli, instance, template, &metav1.LabelSelector{MatchLabels: agentLabels}, 2)
+			require.NoError(t, err)
+			assert.Equal(t, v1alpha1.ConditionTypeQuotaExceeded, condition.Type)
+			assert.Equal(t, tt.expectedStatus, condition.Status)
+			assert.Equal(t, tt.expectedReason, condition.Reason)
+			if tt.expectedMessage != "" {
+				assert.Equal(t, tt.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+)
+
+const (
+	reasonUserManaged     = "UserManaged"
+	reasonOperatorManaged = "OperatorManaged"
+)
+
+// serviceAccountCondition tells whether the service account of the agent is managed by the user, which is the case
+// of the existing service accounts the operator didn't create.
+func serviceAccountCondition(ctx context.Context, cli client.Client, instance *v1alpha1.AmazonCloudWatchAgent) (metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionTypeServiceAccountUserManaged,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: instance.Generation,
+		Reason:             reasonOperatorManaged,
+	}
+
+	sa := &corev1.ServiceAccount{}
+	key := client.ObjectKey{Namespace: instance.Namespace, Name: collector.ServiceAccountName(*instance)}
+	err := cli.Get(ctx, key, sa)
+	switch {
+	case err != nil && !apierrors.IsNotFound(err):
+		return condition, fmt.Errorf("failed to get service account: %w", err)
+	case err != nil && instance.Spec.ServiceAccount != "":
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonUserManaged
+		condition.Message = fmt.Sprintf("the service account %s doesn't exist, the agent pods can't be created until it does", key.Name)
+		return condition, nil
+	case err != nil || metav1.IsControlledBy(sa, instance):
+		condition.Message = fmt.Sprintf("the operator creates and updates the service account %s", key.Name)
+		return condition, nil
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = reasonUserManaged
+	condition.Message = fmt.Sprintf("the service account %s was not created by the operator, which leaves it and its annotations untouched", key.Name)
+	if instance.Spec.ServiceAccount == "" && (instance.Spec.IAMRoleArn != "" || len(instance.Spec.ServiceAccountAnnotations) > 0) {
+		condition.Message += ", the iamRoleArn and the serviceAccountAnnotations must be set on it directly"
+	}
+	return condition, nil
+}
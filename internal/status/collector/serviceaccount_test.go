@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestServiceAccountCondition(t *testing.T) {
+	controller := true
+	owned := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "agent",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "cloudwatch.aws.amazon.com/v1alpha1",
+				Kind:       "AmazonCloudWatchAgent",
+				Name:       "agent",
+				UID:        "agent-uid",
+				Controller: &controller,
+			}},
+		},
+	}
+	existing := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"}}
+
+	tests := []struct {
+		name           string
+		objects        []client.Object
+		spec           v1alpha1.AmazonCloudWatchAgentSpec
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "created by the operator",
+			objects:        []client.Object{owned},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: reasonOperatorManaged,
+		},
+		{
+			name:           "not created yet",
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: reasonOperatorManaged,
+		},
+		{
+			name:           "existing",
+			objects:        []client.Object{existing},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: reasonUserManaged,
+		},
+		{
+			name:           "referenced",
+			spec:           v1alpha1.AmazonCloudWatchAgentSpec{ServiceAccount: "cloudwatch-agent"},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: reasonUserManaged,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			instance := &v1alpha1.AmazonCloudWatchAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", UID: "agent-uid"},
+				Spec:       tt.spec,
+			}
+			condition, err := serviceAccountCondition(context.Background(), cli, instance)
+			require.NoError(t, err)
+			assert.Equal(t, v1alpha1.ConditionTypeServiceAccountUserManaged, condition.Type)
+			assert.Equal(t, tt.expectedStatus, condition.Status)
+			assert.Equal(t, tt.expectedReason, condition.Reason)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// versionSplitStatus compares the stable daemonset with the canary one of the version split of the instance.
+func versionSplitStatus(ctx context.Context, cli client.Client, changed *v1alpha1.AmazonCloudWatchAgent, stable *appsv1.DaemonSet) (*v1alpha1.VersionSplitStatus, error) {
+	if changed.Spec.VersionSplit == nil {
+		return nil, nil
+	}
+	canary := &appsv1.DaemonSet{}
+	canaryKey := client.ObjectKey{Namespace: changed.Namespace, Name: naming.CanaryCollector(changed.ResourceName())}
+	if err := cli.Get(ctx, canaryKey, canary); err != nil {
+		if apierrors.IsNotFound(err) {
+			// created on the next reconcile
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the canary daemonSet: %w", err)
+	}
+
+	selector := labels.SelectorFromSet(manifestutils.SelectorLabels(changed.ObjectMeta, collector.ComponentAmazonCloudWatchAgent))
+	notCanary, err := labels.NewRequirement(constants.LabelCohort, selection.DoesNotExist, nil)
+	if err != nil {
+		return nil, err
+	}
+	isCanary, err := labels.NewRequirement(constants.LabelCohort, selection.Equals, []string{collector.CohortCanary})
+	if err != nil {
+		return nil, err
+	}
+
+	stableStatus, err := cohortStatus(ctx, cli, stable, selector.Add(*notCanary))
+	if err != nil {
+		return nil, err
+	}
+	canaryStatus, err := cohortStatus(ctx, cli, canary, selector.Add(*isCanary))
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.VersionSplitStatus{Stable: stableStatus, Canary: canaryStatus}, nil
+}
+
+// cohortStatus reports the state of the daemonset of a cohort, counting the restarts of the agent containers of
+// the pods matching the selector.
+func cohortStatus(ctx context.Context, cli client.Client, ds *appsv1.DaemonSet, selector labels.Selector) (v1alpha1.CohortStatus, error) {
+	status := v1alpha1.CohortStatus{
+		DesiredNumberScheduled: ds.Status.DesiredNumberScheduled,
+		NumberReady:            ds.Status.NumberReady,
+	}
+	for _, container := range ds.Spec.Template.Spec.Containers {
+		if container.Name == naming.Container() {
+			status.Image = container.Image
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(ds.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return status, fmt.Errorf("failed to list the pods of the %s daemonSet: %w", ds.Name, err)
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Name == naming.Container() {
+				status.Restarts += container.RestartCount
+			}
+		}
+	}
+	return status, nil
+}
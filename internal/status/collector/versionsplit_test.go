@@ -0,0 +1,76 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestVersionSplitStatus(t *testing.T) {
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Mode: v1alpha1.ModeDaemonSet,
+			VersionSplit: &v1alpha1.VersionSplitSpec{
+				Image:     "cloudwatch-agent:2",
+				NodeLabel: v1alpha1.NodeLabel{Key: "agent-cohort", Value: "canary"},
+			},
+		},
+	}
+	daemonSet := func(name, image string, desired, ready int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: agent.Namespace},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "otc-container", Image: image}},
+			}}},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: desired, NumberReady: ready},
+		}
+	}
+	pod := func(name string, canary bool, restarts int32) *corev1.Pod {
+		labels := manifestutils.SelectorLabels(agent.ObjectMeta, collector.ComponentAmazonCloudWatchAgent)
+		if canary {
+			labels[constants.LabelCohort] = collector.CohortCanary
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: agent.Namespace, Labels: labels},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "otc-container", RestartCount: restarts},
+				{Name: "sidecar", RestartCount: 100},
+			}},
+		}
+	}
+	stable := daemonSet("agent", "cloudwatch-agent:1", 9, 9)
+	cli := fake.NewClientBuilder().WithObjects(
+		stable,
+		daemonSet("agent-canary", "cloudwatch-agent:2", 1, 0),
+		pod("stable-1", false, 0),
+		pod("stable-2", false, 1),
+		pod("canary-1", true, 5),
+	).Build()
+
+	status, err := versionSplitStatus(context.Background(), cli, agent, stable)
+	require.NoError(t, err)
+	assert.Equal(t, &v1alpha1.VersionSplitStatus{
+		Stable: v1alpha1.CohortStatus{Image: "cloudwatch-agent:1", DesiredNumberScheduled: 9, NumberReady: 9, Restarts: 1},
+		Canary: v1alpha1.CohortStatus{Image: "cloudwatch-agent:2", DesiredNumberScheduled: 1, NumberReady: 0, Restarts: 5},
+	}, status)
+
+	agent.Spec.VersionSplit = nil
+	status, err = versionSplitStatus(context.Background(), cli, agent, stable)
+	require.NoError(t, err)
+	assert.Nil(t, status)
+}
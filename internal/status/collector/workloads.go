@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// workloadsStatus reports the state of the daemonsets, deployments and statefulsets labeled as part of the instance,
+// sorted by kind and name. The operator watches the workloads it owns, so a change of their status updates the one
+// of the instance.
+func workloadsStatus(ctx context.Context, cli client.Client, changed *v1alpha1.AmazonCloudWatchAgent) ([]v1alpha1.WorkloadStatus, error) {
+	opts := []client.ListOption{
+		client.InNamespace(changed.Namespace),
+		client.MatchingLabels(manifestutils.SelectorLabelsForAllOperatorManaged(changed.ObjectMeta)),
+	}
+	var workloads []v1alpha1.WorkloadStatus
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := cli.List(ctx, daemonSets, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list daemonSets: %w", err)
+	}
+	for _, ds := range daemonSets.Items {
+		workloads = append(workloads, workloadStatus("DaemonSet", ds.ObjectMeta, ds.Spec.Template,
+			ds.Status.DesiredNumberScheduled, ds.Status.NumberReady, ds.Status.UpdatedNumberScheduled))
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := cli.List(ctx, deployments, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		workloads = append(workloads, workloadStatus("Deployment", d.ObjectMeta, d.Spec.Template,
+			replicasOrDefault(d.Spec.Replicas), d.Status.ReadyReplicas, d.Status.UpdatedReplicas))
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := cli.List(ctx, statefulSets, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list statefulSets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		workloads = append(workloads, workloadStatus("StatefulSet", s.ObjectMeta, s.Spec.Template,
+			replicasOrDefault(s.Spec.Replicas), s.Status.ReadyReplicas, s.Status.UpdatedReplicas))
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Kind != workloads[j].Kind {
+			return workloads[i].Kind < workloads[j].Kind
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	return workloads, nil
+}
+
+// workloadStatus reports the state of a workload from its counts of pods.
+func workloadStatus(kind string, meta metav1.ObjectMeta, template corev1.PodTemplateSpec, desired, ready, updated int32) v1alpha1.WorkloadStatus {
+	status := v1alpha1.WorkloadStatus{
+		Kind:           kind,
+		Name:           meta.Name,
+		Desired:        desired,
+		Ready:          ready,
+		Updated:        updated,
+		StatusReplicas: strconv.Itoa(int(ready)) + "/" + strconv.Itoa(int(desired)),
+		Version:        meta.Labels["app.kubernetes.io/version"],
+		ConfigHash:     template.Labels[constants.LabelConfigHash],
+	}
+	status.Image = agentImage(template)
+	return status
+}
+
+// agentImage returns the image of the agent container of the pod template, which the additional containers of the
+// spec come before.
+func agentImage(template corev1.PodTemplateSpec) string {
+	for _, container := range template.Spec.Containers {
+		if container.Name == naming.Container() {
+			return container.Image
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestWorkloadsStatus(t *testing.T) {
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch"},
+	}
+	meta := func(name, component, version string) metav1.ObjectMeta {
+		labels := manifestutils.SelectorLabels(agent.ObjectMeta, component)
+		labels["app.kubernetes.io/version"] = version
+		return metav1.ObjectMeta{Name: name, Namespace: agent.Namespace, Labels: labels}
+	}
+	template := func(image string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{constants.LabelConfigHash: "0123456789abcdef"}},
+			// the additional containers come before the agent container
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "sidecar", Image: "sidecar:1"},
+				{Name: "otc-container", Image: image},
+			}},
+		}
+	}
+	two := int32(2)
+	cli := fake.NewClientBuilder().WithObjects(
+		&appsv1.DaemonSet{
+			ObjectMeta: meta("agent", collector.ComponentAmazonCloudWatchAgent, "1"),
+			Spec:       appsv1.DaemonSetSpec{Template: template("cloudwatch-agent:1")},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 5, NumberReady: 4, UpdatedNumberScheduled: 3},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: meta("agent-metrics", collector.ComponentAmazonCloudWatchAgent+"-metrics", "1"),
+			Spec:       appsv1.DeploymentSpec{Replicas: &two, Template: template("cloudwatch-agent:1")},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+		},
+		// the workloads of other instances are left out
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: agent.Namespace, Labels: map[string]string{
+				"app.kubernetes.io/instance": "amazon-cloudwatch.other",
+			}},
+		},
+	).Build()
+
+	workloads, err := workloadsStatus(context.Background(), cli, agent)
+	require.NoError(t, err)
+	assert.Equal(t, []v1alpha1.WorkloadStatus{
+		{Kind: "DaemonSet", Name: "agent", Desired: 5, Ready: 4, Updated: 3, StatusReplicas: "4/5", Image: "cloudwatch-agent:1", Version: "1", ConfigHash: "0123456789abcdef"},
+		{Kind: "Deployment", Name: "agent-metrics", Desired: 2, Ready: 2, Updated: 2, StatusReplicas: "2/2", Image: "cloudwatch-agent:1", Version: "1", ConfigHash: "0123456789abcdef"},
+	}, workloads)
+}
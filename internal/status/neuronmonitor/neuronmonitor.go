@@ -5,7 +5,11 @@ package neuronmonitor
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
@@ -16,5 +20,19 @@ func UpdateNeuronMonitorStatus(ctx context.Context, cli client.Client, changed *
 	if changed.Status.Version == "" {
 		changed.Status.Version = version.NeuronMonitor()
 	}
+
+	obj := &appsv1.DaemonSet{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: changed.Namespace, Name: changed.Name}, obj); err != nil {
+		// the daemonset may not be in the cache yet, its creation triggers another reconciliation
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get daemonSet status: %w", err)
+	}
+	changed.Status.Scale.StatusReplicas = strconv.Itoa(int(obj.Status.NumberReady)) + "/" + strconv.Itoa(int(obj.Status.DesiredNumberScheduled))
+	if len(obj.Spec.Template.Spec.Containers) > 0 {
+		changed.Status.Image = obj.Spec.Template.Spec.Containers[0].Image
+	}
+
 	return nil
 }
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// cacheSize bounds the number of translations kept, the oldest ones being evicted first.
+const cacheSize = 256
+
+// Cached memoizes the translations of a translator by the hash of the config and the OS, so that the reconciliations
+// of an unchanged config don't run the translator again. The failed translations aren't kept.
+type Cached struct {
+	translator Translator
+
+	mu      sync.Mutex
+	results map[string]Result
+	// keys are the keys of the results, from the oldest to the newest.
+	keys []string
+}
+
+var _ Translator = &Cached{}
+
+// NewCached returns a translator memoizing the translations of the given one.
+func NewCached(translator Translator) *Cached {
+	return &Cached{translator: translator, results: map[string]Result{}}
+}
+
+// Translate returns the kept translation of the config, or translates it.
+func (c *Cached) Translate(ctx context.Context, config string, goos string) (Result, error) {
+	sum := sha256.Sum256([]byte(goos + "\x00" + config))
+	key := hex.EncodeToString(sum[:])
+	c.mu.Lock()
+	result, ok := c.results[key]
+	c.mu.Unlock()
+	if ok {
+		return result, nil
+	}
+
+	result, err := c.translator.Translate(ctx, config, goos)
+	if err != nil {
+		return Result{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.results[key]; !ok {
+		c.keys = append(c.keys, key)
+		if len(c.keys) > cacheSize {
+			delete(c.results, c.keys[0])
+			c.keys = c.keys[1:]
+		}
+	}
+	c.results[key] = result
+	return result, nil
+}
@@ -0,0 +1,187 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package translator translates the JSON config of the agent into the TOML config and the YAML OpenTelemetry config
+// the agent runs, ahead of its start, through the config-translator of the agent.
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v2"
+)
+
+// The names of the files the config-translator reads and writes, the translated ones being the ConfigMap entries
+// the translated configs are projected to.
+const (
+	inputFile      = "cwagentconfig.json"
+	TOMLEntry      = "cwagentconfig.toml"
+	YAMLEntry      = "cwagentconfig.yaml"
+	EnvConfigEntry = "env-config.json"
+)
+
+// timeout bounds each run of the config-translator.
+const timeout = 30 * time.Second
+
+// Result holds the configs translated from the JSON config of the agent.
+type Result struct {
+	// TOML is the TOML config of the agent.
+	TOML string
+	// YAML is the OpenTelemetry config of the agent, empty when the translator writes none.
+	YAML string
+	// EnvConfig is the environment config of the agent, empty when the translator writes none.
+	EnvConfig string
+}
+
+// Translator translates the JSON config of an agent running on the given OS.
+type Translator interface {
+	Translate(ctx context.Context, config string, os string) (Result, error)
+}
+
+// Command runs the config-translator binary of the agent.
+type Command struct {
+	path string
+}
+
+var _ Translator = &Command{}
+
+// NewCommand returns a translator running the config-translator binary at the given path.
+func NewCommand(path string) *Command {
+	return &Command{path: path}
+}
+
+// Translate writes the config to a temporary directory and runs the config-translator on it, in the auto mode of
+// agents running in containers.
+func (c *Command) Translate(ctx context.Context, config string, goos string) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "cwagent-translate-")
+	if err != nil {
+		return Result{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, inputFile), []byte(config), 0600); err != nil {
+		return Result{}, err
+	}
+	cmd := exec.CommandContext(ctx, c.path,
+		"-input", filepath.Join(dir, inputFile),
+		"-output", filepath.Join(dir, TOMLEntry),
+		"-mode", "auto",
+		"-os", goos,
+	)
+	cmd.Env = append(os.Environ(), "RUN_IN_CONTAINER=True")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return Result{}, fmt.Errorf("the config-translator failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	tomlConfig, err := os.ReadFile(filepath.Join(dir, TOMLEntry))
+	if err != nil {
+		return Result{}, fmt.Errorf("the config-translator wrote no TOML config: %w", err)
+	}
+	result := Result{TOML: string(tomlConfig)}
+	if yamlConfig, err := os.ReadFile(filepath.Join(dir, YAMLEntry)); err == nil {
+		result.YAML = string(yamlConfig)
+	}
+	if envConfig, err := os.ReadFile(filepath.Join(dir, EnvConfigEntry)); err == nil {
+		result.EnvConfig = string(envConfig)
+	}
+	return result, nil
+}
+
+// signalOutputs are the TOML outputs the sections of the JSON config are translated into, when the translator doesn't
+// translate them into the pipelines of their signal in the OpenTelemetry config.
+var signalOutputs = map[string]string{
+	"metrics": "cloudwatch",
+	"logs":    "cloudwatchlogs",
+	"traces":  "",
+}
+
+// Validate checks that the translated configs parse and agree with the given JSON config, each of its metrics, logs
+// and traces sections being translated into a TOML output or a pipeline of its signal, and that the translated
+// OpenTelemetry config and the given OtelConfig of the agent, which the agent runs together, don't define the same
+// pipelines.
+func Validate(config string, result Result, otelConfig string) error {
+	tomlConfig := struct {
+		Outputs map[string]interface{} `toml:"outputs"`
+	}{}
+	if err := toml.Unmarshal([]byte(result.TOML), &tomlConfig); err != nil {
+		return fmt.Errorf("the translated TOML config is invalid: %w", err)
+	}
+	translated := map[string]bool{}
+	if result.YAML != "" {
+		var err error
+		if translated, err = pipelines(result.YAML); err != nil {
+			return fmt.Errorf("the translated YAML config is invalid: %w", err)
+		}
+	}
+
+	sections := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(config), &sections); err != nil {
+		return fmt.Errorf("the config is invalid: %w", err)
+	}
+	for section, output := range signalOutputs {
+		if _, ok := sections[section]; !ok {
+			continue
+		}
+		if _, ok := tomlConfig.Outputs[output]; output != "" && ok {
+			continue
+		}
+		if !hasSignalPipeline(translated, section) {
+			return fmt.Errorf("the %s section of the config isn't translated into the TOML config nor the YAML config", section)
+		}
+	}
+
+	if otelConfig == "" {
+		return nil
+	}
+	own, err := pipelines(otelConfig)
+	if err != nil {
+		return fmt.Errorf("the otelConfig is invalid: %w", err)
+	}
+	for name := range own {
+		if translated[name] {
+			return fmt.Errorf("the otelConfig defines the pipeline %s, which the config already translates into", name)
+		}
+	}
+	return nil
+}
+
+// hasSignalPipeline tells whether one of the given pipelines is a pipeline of the given signal, named after it.
+func hasSignalPipeline(pipelines map[string]bool, signal string) bool {
+	for name := range pipelines {
+		if name == signal || strings.HasPrefix(name, signal+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// pipelines returns the names of the pipelines of the given OpenTelemetry config.
+func pipelines(config string) (map[string]bool, error) {
+	parsed := struct {
+		Service struct {
+			Pipelines map[string]interface{} `yaml:"pipelines"`
+		} `yaml:"service"`
+	}{}
+	if err := yaml.Unmarshal([]byte(config), &parsed); err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for name := range parsed.Service.Pipelines {
+		names[name] = true
+	}
+	return names, nil
+}
@@ -0,0 +1,165 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTranslator returns a translator running the given script in place of the config-translator.
+func fakeTranslator(t *testing.T, script string) *Command {
+	path := filepath.Join(t.TempDir(), "config-translator")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0700))
+	return NewCommand(path)
+}
+
+func TestCommandTranslate(t *testing.T) {
+	command := fakeTranslator(t, `printf '[agent]\n  os = "%s"\n' "$8" > "$4"
+printf 'service:\n  pipelines: {}\n' > "$(dirname "$4")/cwagentconfig.yaml"
+printf '{}' > "$(dirname "$4")/env-config.json"
+`)
+	result, err := command.Translate(context.Background(), `{"agent":{}}`, "linux")
+	require.NoError(t, err)
+	assert.Equal(t, Result{TOML: "[agent]\n  os = \"linux\"\n", YAML: "service:\n  pipelines: {}\n", EnvConfig: "{}"}, result)
+
+	result, err = fakeTranslator(t, `printf '[agent]\n' > "$4"`).Translate(context.Background(), `{}`, "linux")
+	require.NoError(t, err)
+	assert.Equal(t, Result{TOML: "[agent]\n"}, result)
+
+	_, err = fakeTranslator(t, "echo 'invalid config' >&2; exit 1").Translate(context.Background(), `{}`, "linux")
+	assert.ErrorContains(t, err, "invalid config")
+
+	_, err = fakeTranslator(t, "exit 0").Translate(context.Background(), `{}`, "linux")
+	assert.ErrorContains(t, err, "wrote no TOML config")
+}
+
+func TestValidate(t *testing.T) {
+	translated := "service:\n  pipelines:\n    metrics/host: {}\n"
+	for _, tt := range []struct {
+		name       string
+		config     string
+		result     Result
+		otelConfig string
+		err        string
+	}{
+		{
+			name:   "TOML only",
+			config: `{"agent":{}}`,
+			result: Result{TOML: "[agent]\n  interval = \"60s\"\n"},
+		},
+		{
+			name:   "invalid TOML",
+			config: `{}`,
+			result: Result{TOML: "[agent"},
+			err:    "the translated TOML config is invalid",
+		},
+		{
+			name:   "logs translated into the TOML config",
+			config: `{"logs":{"logs_collected":{}}}`,
+			result: Result{TOML: "[outputs]\n  [[outputs.cloudwatchlogs]]\n    region = \"us-west-2\"\n"},
+		},
+		{
+			name:   "metrics translated into a pipeline",
+			config: `{"metrics":{"metrics_collected":{}}}`,
+			result: Result{TOML: "[agent]\n", YAML: translated},
+		},
+		{
+			name:   "untranslated section",
+			config: `{"metrics":{},"traces":{}}`,
+			result: Result{TOML: "[agent]\n", YAML: translated},
+			err:    "the traces section of the config isn't translated",
+		},
+		{
+			name:   "invalid config",
+			config: `{`,
+			result: Result{TOML: "[agent]\n"},
+			err:    "the config is invalid",
+		},
+		{
+			name:       "other pipelines",
+			config:     `{}`,
+			result:     Result{TOML: "[agent]\n", YAML: translated},
+			otelConfig: "service:\n  pipelines:\n    traces: {}\n",
+		},
+		{
+			name:       "invalid YAML",
+			config:     `{}`,
+			result:     Result{TOML: "[agent]\n", YAML: "service: ["},
+			otelConfig: "service:\n  pipelines:\n    traces: {}\n",
+			err:        "the translated YAML config is invalid",
+		},
+		{
+			name:       "same pipeline",
+			config:     `{}`,
+			result:     Result{TOML: "[agent]\n", YAML: translated},
+			otelConfig: "service:\n  pipelines:\n    metrics/host: {}\n",
+			err:        "the otelConfig defines the pipeline metrics/host",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.config, tt.result, tt.otelConfig)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+// countingTranslator counts its translations.
+type countingTranslator struct {
+	calls int
+}
+
+func (c *countingTranslator) Translate(_ context.Context, config string, goos string) (Result, error) {
+	c.calls++
+	if config == "" {
+		return Result{}, errors.New("empty config")
+	}
+	return Result{TOML: goos + ":" + config}, nil
+}
+
+func TestCachedTranslate(t *testing.T) {
+	counting := &countingTranslator{}
+	cached := NewCached(counting)
+
+	for i := 0; i < 2; i++ {
+		result, err := cached.Translate(context.Background(), `{"agent":{}}`, "linux")
+		require.NoError(t, err)
+		assert.Equal(t, `linux:{"agent":{}}`, result.TOML)
+	}
+	assert.Equal(t, 1, counting.calls)
+
+	// the same config of another OS is translated again
+	result, err := cached.Translate(context.Background(), `{"agent":{}}`, "windows")
+	require.NoError(t, err)
+	assert.Equal(t, `windows:{"agent":{}}`, result.TOML)
+	assert.Equal(t, 2, counting.calls)
+
+	// the failures aren't kept
+	for i := 0; i < 2; i++ {
+		_, err = cached.Translate(context.Background(), "", "linux")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 4, counting.calls)
+
+	// the oldest translations are evicted
+	for i := 0; i < cacheSize; i++ {
+		_, err = cached.Translate(context.Background(), fmt.Sprintf(`{"agent":{"interval":%d}}`, i), "linux")
+		require.NoError(t, err)
+	}
+	assert.Len(t, cached.results, cacheSize)
+	_, err = cached.Translate(context.Background(), `{"agent":{}}`, "linux")
+	require.NoError(t, err)
+	assert.Equal(t, 4+cacheSize+1, counting.calls)
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package certrotation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// keyPair is a PEM encoded certificate and its private key.
+type keyPair struct {
+	cert []byte
+	key  []byte
+}
+
+// newCA creates a self-signed certificate authority valid for the given duration.
+func newCA(commonName string, now time.Time, validity time.Duration) (keyPair, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return newKeyPair(template, nil)
+}
+
+// newServingCert creates a serving certificate for the given DNS names, signed by the given certificate authority.
+func newServingCert(ca keyPair, dnsNames []string, now time.Time, validity time.Duration) (keyPair, error) {
+	caCert, err := tls.X509KeyPair(ca.cert, ca.key)
+	if err != nil {
+		return keyPair{}, fmt.Errorf("invalid certificate authority: %w", err)
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return newKeyPair(template, &caCert)
+}
+
+// newKeyPair generates a key and a certificate from the template, signed by the parent or self-signed when the
+// parent is nil.
+func newKeyPair(template *x509.Certificate, parent *tls.Certificate) (keyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return keyPair{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return keyPair{}, err
+	}
+	template.SerialNumber = serial
+
+	parentCert, signer := template, interface{}(key)
+	if parent != nil {
+		if parentCert, err = x509.ParseCertificate(parent.Certificate[0]); err != nil {
+			return keyPair{}, err
+		}
+		signer = parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, key.Public(), signer)
+	if err != nil {
+		return keyPair{}, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return keyPair{}, err
+	}
+	return keyPair{
+		cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// parseCert returns the first certificate of the given PEM data.
+func parseCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// needsRenewal tells whether the given PEM certificate is missing, invalid or expires within the renewal window.
+func needsRenewal(data []byte, now time.Time, renewBefore time.Duration) bool {
+	cert, err := parseCert(data)
+	if err != nil {
+		return true
+	}
+	return now.Add(renewBefore).After(cert.NotAfter)
+}
+
+// verifyServingCert tells whether the serving certificate is signed by the certificate authority and valid for the
+// given DNS names.
+func verifyServingCert(serving keyPair, ca []byte, dnsNames []string, now time.Time) bool {
+	if _, err := tls.X509KeyPair(serving.cert, serving.key); err != nil {
+		return false
+	}
+	cert, err := parseCert(serving.cert)
+	if err != nil {
+		return false
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return false
+	}
+	for _, dnsName := range dnsNames {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: dnsName, Roots: roots, CurrentTime: now}); err != nil {
+			return false
+		}
+	}
+	return true
+}
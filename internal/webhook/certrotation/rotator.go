@@ -0,0 +1,370 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certrotation issues the webhook serving certificate from a self-signed certificate authority, for the
+// clusters where cert-manager isn't installed.
+package certrotation
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update
+
+const (
+	// CACertKey is the key of the certificate authority in the Secret, which is injected into the webhook
+	// configurations.
+	CACertKey = "ca.crt"
+	// CAKeyKey is the key of the private key of the certificate authority in the Secret.
+	CAKeyKey = "ca.key"
+	// PreviousCACertKey is the key of the certificate authority replaced by the last rotation, which stays trusted
+	// until the serving certificate signed by it is replaced by every operator replica.
+	PreviousCACertKey = "previous-ca.crt"
+
+	caValidity       = 10 * 365 * 24 * time.Hour
+	caRenewBefore    = 365 * 24 * time.Hour
+	certValidity     = 365 * 24 * time.Hour
+	certRenewBefore  = 30 * 24 * time.Hour
+	rotationInterval = time.Hour
+	// reloadInterval is the interval the replicas which aren't the leader pick up the certificate renewed by the
+	// leader at.
+	reloadInterval = time.Minute
+)
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+var _ manager.LeaderElectionRunnable = (*Rotator)(nil)
+var _ manager.LeaderElectionRunnable = (*Reloader)(nil)
+
+// Options configures a Rotator.
+type Options struct {
+	// CertDir is the directory the webhook server reads tls.crt and tls.key from.
+	CertDir string
+	// Secret is the Secret storing the certificate authority and the serving certificate.
+	Secret types.NamespacedName
+	// Service is the Service in front of the webhook server.
+	Service types.NamespacedName
+	// WebhookConfigurations are the names of the mutating and validating webhook configurations calling the
+	// webhook server.
+	WebhookConfigurations []string
+	// CRDs are the names of the CustomResourceDefinitions whose conversion webhook is served by the webhook server.
+	CRDs []string
+	// OnRotation is called when the serving certificate read by the webhook server changed.
+	OnRotation func()
+}
+
+// Rotator keeps a self-signed certificate authority and a webhook serving certificate in a Secret, writes the
+// serving certificate for the webhook server and injects the certificate authority into the webhook and CRD
+// conversion configurations.
+type Rotator struct {
+	client client.Client
+	logger logr.Logger
+	opts   Options
+	now    func() time.Time
+}
+
+// NewRotator creates a Rotator. The client must not depend on the manager cache, so that the certificate is
+// issued before the webhook server starts.
+func NewRotator(c client.Client, logger logr.Logger, opts Options) *Rotator {
+	return &Rotator{
+		client: c,
+		logger: logger,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+// Start rotates the certificates once elected, then at regular intervals until the context is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(rotationInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.Sync(ctx); err != nil {
+			r.logger.Error(err, "failed to rotate the webhook certificate", "secret", r.opts.Secret)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection is true, a single replica renews the certificates and injects the certificate authority, the
+// other replicas reload the serving certificate through the Reloader.
+func (r *Rotator) NeedLeaderElection() bool {
+	return true
+}
+
+// Load writes the serving certificate of the Secret into the certificate directory, so that the webhook server
+// starts before any replica is elected. Only the Secret which doesn't exist yet is issued, the certificates about to
+// expire being renewed by the leader.
+func (r *Rotator) Load(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, r.opts.Secret, secret)
+	if apierrors.IsNotFound(err) {
+		issued, issueErr := r.ensureSecret(ctx)
+		switch {
+		case issueErr == nil:
+			secret, err = issued, nil
+		case apierrors.IsAlreadyExists(issueErr):
+			// another operator replica issued the certificates first
+			err = r.client.Get(ctx, r.opts.Secret, secret)
+		default:
+			return issueErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the webhook certificate secret: %w", err)
+	}
+	_, err = r.writeCerts(secret)
+	return err
+}
+
+// Reloader returns the runnable reloading the serving certificate renewed by the leader on every replica.
+func (r *Rotator) Reloader() *Reloader {
+	return &Reloader{rotator: r}
+}
+
+// Sync renews the certificates which are missing or about to expire, writes the serving certificate into the
+// certificate directory and injects the certificate authority into the webhook and CRD configurations.
+func (r *Rotator) Sync(ctx context.Context) error {
+	secret, err := r.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	changed, err := r.writeCerts(secret)
+	if err != nil {
+		return err
+	}
+	caBundle := append(append([]byte{}, secret.Data[CACertKey]...), secret.Data[PreviousCACertKey]...)
+	for _, name := range r.opts.WebhookConfigurations {
+		if err := r.injectWebhookConfiguration(ctx, name, caBundle); err != nil {
+			return err
+		}
+	}
+	for _, name := range r.opts.CRDs {
+		if err := r.injectCRD(ctx, name, caBundle); err != nil {
+			return err
+		}
+	}
+	if changed && r.opts.OnRotation != nil {
+		r.opts.OnRotation()
+	}
+	return nil
+}
+
+// Reloader writes the serving certificate of the Secret into the certificate directory of every operator replica.
+type Reloader struct {
+	rotator *Rotator
+}
+
+// Start reloads the serving certificate at regular intervals until the context is done.
+func (l *Reloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := l.Reload(ctx); err != nil {
+				l.rotator.logger.Error(err, "failed to reload the webhook certificate", "secret", l.rotator.opts.Secret)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection is false, every operator replica serves the webhooks and needs the serving certificate.
+func (l *Reloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Reload writes the serving certificate of the Secret into the certificate directory, without changing the Secret.
+func (l *Reloader) Reload(ctx context.Context) error {
+	r := l.rotator
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, r.opts.Secret, secret); err != nil {
+		return fmt.Errorf("failed to get the webhook certificate secret: %w", err)
+	}
+	changed, err := r.writeCerts(secret)
+	if err != nil {
+		return err
+	}
+	if changed && r.opts.OnRotation != nil {
+		r.opts.OnRotation()
+	}
+	return nil
+}
+
+// dnsNames are the names the webhook server is called by through its Service.
+func (r *Rotator) dnsNames() []string {
+	service, namespace := r.opts.Service.Name, r.opts.Service.Namespace
+	return []string{
+		fmt.Sprintf("%s.%s.svc", service, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace),
+	}
+}
+
+func (r *Rotator) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, r.opts.Secret, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get the webhook certificate secret: %w", err)
+	}
+	exists := err == nil
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: r.opts.Secret.Name, Namespace: r.opts.Secret.Namespace},
+			Type:       corev1.SecretTypeTLS,
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	now := r.now()
+	updated := false
+	ca := keyPair{cert: secret.Data[CACertKey], key: secret.Data[CAKeyKey]}
+	if needsRenewal(ca.cert, now, caRenewBefore) {
+		if ca, err = newCA(r.opts.Service.Name+"-ca", now, caValidity); err != nil {
+			return nil, fmt.Errorf("failed to create the webhook certificate authority: %w", err)
+		}
+		if previous := secret.Data[CACertKey]; !needsRenewal(previous, now, 0) {
+			secret.Data[PreviousCACertKey] = previous
+		} else {
+			delete(secret.Data, PreviousCACertKey)
+		}
+		secret.Data[CACertKey], secret.Data[CAKeyKey] = ca.cert, ca.key
+		updated = true
+		r.logger.Info("created the webhook certificate authority", "secret", r.opts.Secret)
+	}
+	serving := keyPair{cert: secret.Data[corev1.TLSCertKey], key: secret.Data[corev1.TLSPrivateKeyKey]}
+	if updated || needsRenewal(serving.cert, now, certRenewBefore) || !verifyServingCert(serving, ca.cert, r.dnsNames(), now) {
+		if serving, err = newServingCert(ca, r.dnsNames(), now, certValidity); err != nil {
+			return nil, fmt.Errorf("failed to create the webhook serving certificate: %w", err)
+		}
+		secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey] = serving.cert, serving.key
+		updated = true
+		r.logger.Info("issued the webhook serving certificate", "secret", r.opts.Secret)
+	}
+
+	switch {
+	case !exists:
+		err = r.client.Create(ctx, secret)
+	case updated:
+		err = r.client.Update(ctx, secret)
+	}
+	if err != nil {
+		// another operator replica rotated the certificates first, use its certificates on the next sync
+		return nil, fmt.Errorf("failed to store the webhook certificates: %w", err)
+	}
+	return secret, nil
+}
+
+// writeCerts writes the serving certificate into the certificate directory, where the webhook server picks up
+// changes without a restart. It tells whether the files changed.
+func (r *Rotator) writeCerts(secret *corev1.Secret) (bool, error) {
+	if err := os.MkdirAll(r.opts.CertDir, 0700); err != nil {
+		return false, fmt.Errorf("failed to create the webhook certificate directory: %w", err)
+	}
+	changed := false
+	for _, key := range []string{corev1.TLSPrivateKeyKey, corev1.TLSCertKey} {
+		file := filepath.Join(r.opts.CertDir, key)
+		if existing, err := os.ReadFile(file); err == nil && bytes.Equal(existing, secret.Data[key]) {
+			continue
+		}
+		if err := os.WriteFile(file, secret.Data[key], 0600); err != nil {
+			return false, fmt.Errorf("failed to write the webhook certificate: %w", err)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// injectWebhookConfiguration sets the CA bundle on the webhooks of the mutating or validating webhook
+// configuration with the given name.
+func (r *Rotator) injectWebhookConfiguration(ctx context.Context, name string, caBundle []byte) error {
+	key := types.NamespacedName{Name: name}
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	err := r.client.Get(ctx, key, mutating)
+	if err == nil {
+		changed := false
+		for i := range mutating.Webhooks {
+			if !bytes.Equal(mutating.Webhooks[i].ClientConfig.CABundle, caBundle) {
+				mutating.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return r.client.Update(ctx, mutating)
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the webhook configuration %s: %w", name, err)
+	}
+
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, key, validating); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.logger.Info("webhook configuration not found, skipping the CA injection", "name", name)
+			return nil
+		}
+		return fmt.Errorf("failed to get the webhook configuration %s: %w", name, err)
+	}
+	changed := false
+	for i := range validating.Webhooks {
+		if !bytes.Equal(validating.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			validating.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.client.Update(ctx, validating)
+}
+
+// injectCRD sets the CA bundle on the conversion webhook of the CustomResourceDefinition with the given name.
+func (r *Rotator) injectCRD(ctx context.Context, name string, caBundle []byte) error {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.logger.Info("CustomResourceDefinition not found, skipping the CA injection", "name", name)
+			return nil
+		}
+		return fmt.Errorf("failed to get the CustomResourceDefinition %s: %w", name, err)
+	}
+	if strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy"); strategy != "Webhook" {
+		return nil
+	}
+	encoded := base64.StdEncoding.EncodeToString(caBundle)
+	if existing, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle"); existing == encoded {
+		return nil
+	}
+	if err := unstructured.SetNestedField(crd.Object, encoded, "spec", "conversion", "webhook", "clientConfig", "caBundle"); err != nil {
+		return err
+	}
+	return r.client.Update(ctx, crd)
+}
@@ -0,0 +1,159 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package certrotation
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSync(t *testing.T) {
+	// prepare
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "mutating"},
+		Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mpod.kb.io"}},
+	}
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "validating"},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "vpod.kb.io"}},
+	}
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"conversion": map[string]interface{}{"strategy": "Webhook"},
+		},
+	}}
+	crd.SetGroupVersionKind(crdGVK)
+	crd.SetName("agents.example.com")
+	c := fake.NewClientBuilder().WithObjects(mutating, validating, crd).Build()
+
+	certDir := t.TempDir()
+	secretName := types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-cert"}
+	rotations := 0
+	rotator := NewRotator(c, logr.Discard(), Options{
+		OnRotation:            func() { rotations++ },
+		CertDir:               certDir,
+		Secret:                secretName,
+		Service:               types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-service"},
+		WebhookConfigurations: []string{"mutating", "validating", "missing"},
+		CRDs:                  []string{"agents.example.com"},
+	})
+
+	// test
+	require.NoError(t, rotator.Sync(context.Background()))
+
+	// verify
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretName, secret))
+	caBundle := secret.Data[CACertKey]
+	require.NotEmpty(t, caBundle)
+	serving := keyPair{cert: secret.Data[corev1.TLSCertKey], key: secret.Data[corev1.TLSPrivateKeyKey]}
+	assert.True(t, verifyServingCert(serving, caBundle, []string{"webhook-service.amazon-cloudwatch.svc"}, time.Now()))
+
+	cert, err := os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+	require.NoError(t, err)
+	assert.Equal(t, serving.cert, cert)
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(mutating), mutating))
+	assert.Equal(t, caBundle, mutating.Webhooks[0].ClientConfig.CABundle)
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(validating), validating))
+	assert.Equal(t, caBundle, validating.Webhooks[0].ClientConfig.CABundle)
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(crd), crd))
+	injected, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+	assert.Equal(t, base64.StdEncoding.EncodeToString(caBundle), injected)
+
+	// the certificates are kept until they are about to expire
+	require.NoError(t, rotator.Sync(context.Background()))
+	kept := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretName, kept))
+	assert.Equal(t, secret.Data, kept.Data)
+	assert.Equal(t, 1, rotations)
+}
+
+func TestSyncRotation(t *testing.T) {
+	// prepare
+	c := fake.NewClientBuilder().Build()
+	secretName := types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-cert"}
+	rotator := NewRotator(c, logr.Discard(), Options{
+		CertDir: t.TempDir(),
+		Secret:  secretName,
+		Service: types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-service"},
+	})
+	require.NoError(t, rotator.Sync(context.Background()))
+	initial := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretName, initial))
+
+	// test
+	rotator.now = func() time.Time { return time.Now().Add(certValidity - certRenewBefore/2) }
+	require.NoError(t, rotator.Sync(context.Background()))
+	renewed := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretName, renewed))
+
+	rotator.now = func() time.Time { return time.Now().Add(caValidity - caRenewBefore/2) }
+	require.NoError(t, rotator.Sync(context.Background()))
+	rotated := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretName, rotated))
+
+	// verify
+	assert.Equal(t, initial.Data[CACertKey], renewed.Data[CACertKey])
+	assert.NotEqual(t, initial.Data[corev1.TLSCertKey], renewed.Data[corev1.TLSCertKey])
+	assert.NotEqual(t, initial.Data[CACertKey], rotated.Data[CACertKey])
+	assert.Equal(t, initial.Data[CACertKey], rotated.Data[PreviousCACertKey])
+}
+
+func TestLoadAndReload(t *testing.T) {
+	// prepare
+	c := fake.NewClientBuilder().Build()
+	secretName := types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-cert"}
+	service := types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-service"}
+	leader := NewRotator(c, logr.Discard(), Options{CertDir: t.TempDir(), Secret: secretName, Service: service})
+	certDir := t.TempDir()
+	rotations := 0
+	replica := NewRotator(c, logr.Discard(), Options{
+		OnRotation: func() { rotations++ },
+		CertDir:    certDir,
+		Secret:     secretName,
+		Service:    service,
+	})
+
+	// test
+	require.NoError(t, replica.Load(context.Background()))
+	issued := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretName, issued))
+
+	// the certificates about to expire are only renewed by the leader
+	replica.now = func() time.Time { return time.Now().Add(certValidity) }
+	require.NoError(t, replica.Load(context.Background()))
+	loaded := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretName, loaded))
+	assert.Equal(t, issued.Data, loaded.Data)
+
+	leader.now = func() time.Time { return time.Now().Add(certValidity - certRenewBefore/2) }
+	require.NoError(t, leader.Sync(context.Background()))
+	require.NoError(t, replica.Reloader().Reload(context.Background()))
+	require.NoError(t, replica.Reloader().Reload(context.Background()))
+
+	// verify
+	renewed := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), secretName, renewed))
+	assert.NotEqual(t, issued.Data[corev1.TLSCertKey], renewed.Data[corev1.TLSCertKey])
+	cert, err := os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+	require.NoError(t, err)
+	assert.Equal(t, renewed.Data[corev1.TLSCertKey], cert)
+	assert.Equal(t, 1, rotations)
+}
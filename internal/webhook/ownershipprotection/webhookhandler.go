@@ -0,0 +1,156 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ownershipprotection contains the webhook that rejects the manual edits of the objects managed by the
+// operator, which the operator would revert.
+package ownershipprotection
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-v1-owned-object,mutating=false,failurePolicy=ignore,groups="";apps,resources=configmaps;daemonsets;deployments;statefulsets,verbs=update,versions=v1,name=vownedobject.kb.io,sideEffects=none,admissionReviewVersions=v1
+
+const (
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "amazon-cloudwatch-agent-operator"
+
+	// restartedAtAnnotation is the annotation of the pod template which kubectl rollout restart sets, and which the
+	// operator keeps on the workloads.
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// DefaultAllowedUsers are the users of the control plane updating the objects of the operator, such as the garbage
+// collector removing their owner references.
+var DefaultAllowedUsers = []string{
+	"system:kube-controller-manager",
+	"system:serviceaccount:kube-system:generic-garbage-collector",
+}
+
+var _ admission.Handler = (*WebhookHandler)(nil)
+
+// WebhookHandler rejects the updates of the objects labeled as managed by the operator, unless they are made by the
+// operator or by the allowed users, only restart the pods of the object, or the agent owning them is unmanaged.
+type WebhookHandler struct {
+	client       client.Client
+	logger       logr.Logger
+	enabled      bool
+	allowedUsers sets.Set[string]
+}
+
+// NewWebhookHandler returns the webhook handler. When it isn't enabled, every update is allowed.
+func NewWebhookHandler(c client.Client, logger logr.Logger, enabled bool, allowedUsers []string) *WebhookHandler {
+	return &WebhookHandler{
+		client:       c,
+		logger:       logger,
+		enabled:      enabled,
+		allowedUsers: sets.New(allowedUsers...),
+	}
+}
+
+func (h *WebhookHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if !h.enabled || req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	old := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if old.Labels[managedByLabel] != managedByValue || h.allowedUsers.Has(req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+	if restartOnly, err := restartOnly(req.OldObject.Raw, req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	} else if restartOnly {
+		return admission.Allowed("")
+	}
+	if owner := metav1.GetControllerOf(old); owner != nil && owner.Kind == "AmazonCloudWatchAgent" {
+		agent := &v1alpha1.AmazonCloudWatchAgent{}
+		// the objects of an unmanaged agent are left to the users, as are the ones of a deleted agent
+		err := h.client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: owner.Name}, agent)
+		if err != nil || agent.Spec.ManagementState == v1alpha1.ManagementStateUnmanaged {
+			return admission.Allowed("")
+		}
+	}
+	h.logger.V(1).Info("rejecting the manual edit of an object managed by the operator", "kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name, "user", req.UserInfo.Username)
+	return admission.Denied(fmt.Sprintf("the %s %s/%s is managed by the amazon-cloudwatch-agent-operator, which reverts manual edits: edit the resource it is generated from instead, or set the managementState of the AmazonCloudWatchAgent to unmanaged", req.Kind.Kind, req.Namespace, req.Name))
+}
+
+// restartOnly tells whether the update of the object only restarts its pods, changing the restartedAt annotation of
+// the pod template, like kubectl rollout restart. The fields the API server maintains are left out of the comparison.
+func restartOnly(oldRaw, newRaw []byte) (bool, error) {
+	var (
+		objects     [2]map[string]interface{}
+		restartedAt [2]string
+	)
+	for i, raw := range [][]byte{oldRaw, newRaw} {
+		if err := json.Unmarshal(raw, &objects[i]); err != nil {
+			return false, err
+		}
+		restartedAt[i], _, _ = unstructured.NestedString(objects[i], "spec", "template", "metadata", "annotations", restartedAtAnnotation)
+		for _, field := range [][]string{
+			{"metadata", "resourceVersion"},
+			{"metadata", "generation"},
+			{"metadata", "managedFields"},
+			{"status"},
+			{"spec", "template", "metadata", "annotations", restartedAtAnnotation},
+		} {
+			unstructured.RemoveNestedField(objects[i], field...)
+		}
+		if annotations, found, _ := unstructured.NestedMap(objects[i], "spec", "template", "metadata", "annotations"); found && len(annotations) == 0 {
+			unstructured.RemoveNestedField(objects[i], "spec", "template", "metadata", "annotations")
+		}
+	}
+	return restartedAt[0] != restartedAt[1] && reflect.DeepEqual(objects[0], objects[1]), nil
+}
+
+// OperatorUsername returns the username of the service account the operator authenticates with, read from the
+// subject of its token.
+func OperatorUsername(config *rest.Config) (string, error) {
+	token := config.BearerToken
+	if token == "" && config.BearerTokenFile != "" {
+		content, err := os.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return "", err
+		}
+		token = strings.TrimSpace(string(content))
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("the operator doesn't authenticate with a service account token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode the service account token: %w", err)
+	}
+	claims := struct {
+		Subject string `json:"sub"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("failed to decode the service account token: %w", err)
+	}
+	if !strings.HasPrefix(claims.Subject, "system:serviceaccount:") {
+		return "", fmt.Errorf("the subject %q of the token isn't a service account", claims.Subject)
+	}
+	return claims.Subject, nil
+}
@@ -0,0 +1,167 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ownershipprotection
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestHandle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	managed := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "amazon-cloudwatch"}}
+	unmanaged := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "amazon-cloudwatch"},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{ManagementState: v1alpha1.ManagementStateUnmanaged},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(managed, unmanaged).Build()
+	h := NewWebhookHandler(c, logr.Discard(), true, append(DefaultAllowedUsers, "system:serviceaccount:amazon-cloudwatch:operator"))
+
+	request := func(user string, labels map[string]string, owner string) admission.Request {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "amazon-cloudwatch", Labels: labels}}
+		if owner != "" {
+			controller := true
+			configMap.OwnerReferences = []metav1.OwnerReference{{APIVersion: "cloudwatch.aws.amazon.com/v1alpha1", Kind: "AmazonCloudWatchAgent", Name: owner, Controller: &controller}}
+		}
+		raw, err := json.Marshal(configMap)
+		require.NoError(t, err)
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Namespace: "amazon-cloudwatch",
+			Name:      "agent",
+			UserInfo:  authenticationv1.UserInfo{Username: user},
+			Object:    runtime.RawExtension{Raw: raw},
+			OldObject: runtime.RawExtension{Raw: raw},
+		}}
+	}
+	operatorLabels := map[string]string{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"}
+
+	for _, tt := range []struct {
+		desc    string
+		req     admission.Request
+		allowed bool
+	}{
+		{
+			desc:    "manual edit",
+			req:     request("admin", operatorLabels, "managed"),
+			allowed: false,
+		},
+		{
+			desc:    "operator",
+			req:     request("system:serviceaccount:amazon-cloudwatch:operator", operatorLabels, "managed"),
+			allowed: true,
+		},
+		{
+			desc:    "garbage collector",
+			req:     request("system:serviceaccount:kube-system:generic-garbage-collector", operatorLabels, "managed"),
+			allowed: true,
+		},
+		{
+			desc:    "object of another tool",
+			req:     request("admin", map[string]string{"app.kubernetes.io/managed-by": "Helm"}, ""),
+			allowed: true,
+		},
+		{
+			desc:    "object of an unmanaged agent",
+			req:     request("admin", operatorLabels, "unmanaged"),
+			allowed: true,
+		},
+		{
+			desc:    "object of a deleted agent",
+			req:     request("admin", operatorLabels, "deleted"),
+			allowed: true,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			response := h.Handle(context.Background(), tt.req)
+			assert.Equal(t, tt.allowed, response.Allowed)
+			if !tt.allowed {
+				assert.Equal(t, "the ConfigMap amazon-cloudwatch/agent is managed by the amazon-cloudwatch-agent-operator, which reverts manual edits: edit the resource it is generated from instead, or set the managementState of the AmazonCloudWatchAgent to unmanaged", response.Result.Message)
+			}
+		})
+	}
+
+	// every edit is allowed when the protection isn't enabled
+	h = NewWebhookHandler(c, logr.Discard(), false, nil)
+	assert.True(t, h.Handle(context.Background(), request("admin", operatorLabels, "managed")).Allowed)
+}
+
+func TestHandleRestart(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	managed := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "amazon-cloudwatch"}}
+	h := NewWebhookHandler(fake.NewClientBuilder().WithScheme(scheme).WithObjects(managed).Build(), logr.Discard(), true, DefaultAllowedUsers)
+
+	controller := true
+	old := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            "agent",
+		Namespace:       "amazon-cloudwatch",
+		ResourceVersion: "1",
+		Labels:          map[string]string{"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator"},
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "cloudwatch.aws.amazon.com/v1alpha1", Kind: "AmazonCloudWatchAgent", Name: "managed", Controller: &controller}},
+	}}
+	request := func(mutate func(*appsv1.DaemonSet)) admission.Request {
+		updated := old.DeepCopy()
+		mutate(updated)
+		oldRaw, err := json.Marshal(old)
+		require.NoError(t, err)
+		newRaw, err := json.Marshal(updated)
+		require.NoError(t, err)
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+			Namespace: "amazon-cloudwatch",
+			Name:      "agent",
+			UserInfo:  authenticationv1.UserInfo{Username: "admin"},
+			Object:    runtime.RawExtension{Raw: newRaw},
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+		}}
+	}
+	restart := func(ds *appsv1.DaemonSet) {
+		ds.ResourceVersion = "2"
+		ds.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "2024-01-01T00:00:00Z"}
+	}
+
+	// kubectl rollout restart only sets the restartedAt annotation of the pod template
+	assert.True(t, h.Handle(context.Background(), request(restart)).Allowed)
+	assert.False(t, h.Handle(context.Background(), request(func(ds *appsv1.DaemonSet) {
+		restart(ds)
+		ds.Spec.Template.Spec.HostNetwork = true
+	})).Allowed)
+	assert.False(t, h.Handle(context.Background(), request(func(ds *appsv1.DaemonSet) {
+		ds.Spec.Template.Annotations = map[string]string{"example.com/debug": "true"}
+	})).Allowed)
+}
+
+func TestOperatorUsername(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"system:serviceaccount:amazon-cloudwatch:operator"}`))
+	user, err := OperatorUsername(&rest.Config{BearerToken: "header." + payload + ".signature"})
+	require.NoError(t, err)
+	assert.Equal(t, "system:serviceaccount:amazon-cloudwatch:operator", user)
+
+	_, err = OperatorUsername(&rest.Config{Username: "admin", Password: "secret"})
+	assert.Error(t, err)
+	payload = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`))
+	_, err = OperatorUsername(&rest.Config{BearerToken: "header." + payload + ".signature"})
+	assert.Error(t, err)
+}
@@ -7,15 +7,24 @@ package podmutation
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
 // +kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,groups="",resources=pods,verbs=create;update,versions=v1,name=mpod.kb.io,sideEffects=none,admissionReviewVersions=v1
@@ -23,6 +32,7 @@ import (
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=instrumentations,verbs=get;list;watch
 // +kubebuilder:rbac:groups="apps",resources=replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch
 
 var _ WebhookHandler = (*podMutationWebhook)(nil)
 
@@ -38,6 +48,7 @@ type podMutationWebhook struct {
 	logger      logr.Logger
 	podMutators []PodMutator
 	config      config.Config
+	recorder    record.EventRecorder
 }
 
 // PodMutator mutates a pod.
@@ -45,14 +56,15 @@ type PodMutator interface {
 	Mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error)
 }
 
-// NewWebhookHandler creates a new WebhookHandler.
-func NewWebhookHandler(cfg config.Config, logger logr.Logger, decoder *admission.Decoder, cl client.Client, podMutators []PodMutator) WebhookHandler {
+// NewWebhookHandler creates a new WebhookHandler. The recorder records the mutations of the audited pods.
+func NewWebhookHandler(cfg config.Config, logger logr.Logger, decoder *admission.Decoder, cl client.Client, recorder record.EventRecorder, podMutators []PodMutator) WebhookHandler {
 	return &podMutationWebhook{
 		config:      cfg,
 		decoder:     decoder,
 		logger:      logger,
 		client:      cl,
 		podMutators: podMutators,
+		recorder:    recorder,
 	}
 }
 
@@ -77,6 +89,11 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 		return res
 	}
 
+	audit := p.config.InjectionAudit() || ns.Annotations[constants.AnnotationInjectionAudit] == "true"
+	if audit {
+		ctx = context.WithValue(ctx, auditKey{}, true)
+	}
+	original := pod.DeepCopy()
 	for _, m := range p.podMutators {
 		pod, err = m.Mutate(ctx, ns, pod)
 		if err != nil {
@@ -86,6 +103,11 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 		}
 	}
 
+	if audit {
+		p.audit(req.Namespace, *original, pod)
+		return admission.Allowed("the mutations of the pod are audited")
+	}
+
 	marshaledPod, err := json.Marshal(pod)
 	if err != nil {
 		res := admission.Errored(http.StatusInternalServerError, err)
@@ -94,3 +116,96 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 	}
 	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
 }
+
+type auditKey struct{}
+
+// Auditing tells whether the mutations of the pod handled with the context are only audited, rather than applied.
+// The mutators skip their side effects, such as counting the injections, for the audited pods.
+func Auditing(ctx context.Context) bool {
+	audit, _ := ctx.Value(auditKey{}).(bool)
+	return audit
+}
+
+// audit logs the mutations of the pod and records them as an Event of its controller, or of the pod itself when it
+// has none, since the pods of the controllers are named once they are admitted.
+func (p *podMutationWebhook) audit(namespace string, original, mutated corev1.Pod) {
+	mutations := podMutations(original, mutated)
+	if len(mutations) == 0 {
+		return
+	}
+	message := "the pod webhook would " + strings.Join(mutations, ", ")
+	name := original.Name
+	if name == "" {
+		name = original.GenerateName
+	}
+	p.logger.Info("audited the mutations of a pod", "namespace", namespace, "name", name, "mutations", mutations)
+
+	var object runtime.Object = original.DeepCopy()
+	if owner := metav1.GetControllerOf(&original); owner != nil {
+		object = &corev1.ObjectReference{
+			APIVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+			Name:       owner.Name,
+			Namespace:  namespace,
+			UID:        owner.UID,
+		}
+	}
+	p.recorder.Event(object, corev1.EventTypeNormal, "InjectionAudited", message)
+}
+
+// podMutations describes the containers, init containers and volumes the mutators added to the pod, as well as the
+// containers and metadata they changed.
+func podMutations(original, mutated corev1.Pod) []string {
+	var mutations []string
+	describe := func(verb, what string, names []string) {
+		if len(names) > 0 {
+			mutations = append(mutations, fmt.Sprintf("%s the %s %s", verb, what, strings.Join(names, ", ")))
+		}
+	}
+	addedInit, _ := containerChanges(original.Spec.InitContainers, mutated.Spec.InitContainers)
+	added, changed := containerChanges(original.Spec.Containers, mutated.Spec.Containers)
+	describe("inject", "init containers", addedInit)
+	describe("inject", "containers", added)
+	describe("change", "containers", changed)
+
+	var volumes []string
+	for _, volume := range mutated.Spec.Volumes {
+		if !hasVolume(original.Spec.Volumes, volume.Name) {
+			volumes = append(volumes, volume.Name)
+		}
+	}
+	describe("add", "volumes", volumes)
+	describe("set", "annotations", changedKeys(original.Annotations, mutated.Annotations))
+	describe("set", "labels", changedKeys(original.Labels, mutated.Labels))
+	return mutations
+}
+
+// containerChanges returns the names of the mutated containers missing from the original ones, and of the ones
+// that differ from their original.
+func containerChanges(original, mutated []corev1.Container) (added, changed []string) {
+	for _, container := range mutated {
+		i := slices.IndexFunc(original, func(c corev1.Container) bool { return c.Name == container.Name })
+		if i < 0 {
+			added = append(added, container.Name)
+		} else if !equality.Semantic.DeepEqual(original[i], container) {
+			changed = append(changed, container.Name)
+		}
+	}
+	return added, changed
+}
+
+func hasVolume(volumes []corev1.Volume, name string) bool {
+	return slices.ContainsFunc(volumes, func(v corev1.Volume) bool { return v.Name == name })
+}
+
+// changedKeys returns the sorted keys whose values differ in the mutated map.
+func changedKeys(original, mutated map[string]string) []string {
+	var keys []string
+	for key, value := range mutated {
+		if previous, ok := original[key]; !ok || previous != value {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -13,13 +13,17 @@ import (
 	"github.com/stretchr/testify/require"
 	admv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/sidecar"
 )
 
@@ -64,7 +68,7 @@ func TestFailOnInvalidRequest(t *testing.T) {
 			// prepare
 			cfg := config.New()
 			decoder := admission.NewDecoder(scheme.Scheme)
-			injector := NewWebhookHandler(cfg, logger, decoder, k8sClient, []PodMutator{sidecar.NewMutator(logger, cfg, k8sClient)})
+			injector := NewWebhookHandler(cfg, logger, decoder, k8sClient, record.NewFakeRecorder(10), []PodMutator{sidecar.NewMutator(logger, cfg, k8sClient)})
 
 			// test
 			res := injector.Handle(context.Background(), tt.req)
@@ -76,3 +80,74 @@ func TestFailOnInvalidRequest(t *testing.T) {
 		})
 	}
 }
+
+// injectingMutator injects an init container and a volume into the pods, recording whether they are audited.
+type injectingMutator struct {
+	audited bool
+}
+
+func (m *injectingMutator) Mutate(ctx context.Context, _ corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
+	m.audited = Auditing(ctx)
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{Name: "opentelemetry-auto-instrumentation-java"})
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{Name: "JAVA_TOOL_OPTIONS", Value: "-javaagent:/otel-auto-instrumentation-java/javaagent.jar"})
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{Name: "opentelemetry-auto-instrumentation-java"})
+	return pod, nil
+}
+
+func TestInjectionAudit(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "app-7d4b9c-",
+			Namespace:    "audited",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "app-7d4b9c", UID: "uid", Controller: &[]bool{true}[0]},
+			},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+	}
+	encoded, err := json.Marshal(pod)
+	require.NoError(t, err)
+	req := func(namespace string) admission.Request {
+		return admission.Request{AdmissionRequest: admv1.AdmissionRequest{
+			Namespace: namespace,
+			Object:    runtime.RawExtension{Raw: encoded},
+		}}
+	}
+	cl := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "audited", Annotations: map[string]string{constants.AnnotationInjectionAudit: "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "injected"}},
+	).Build()
+	decoder := admission.NewDecoder(scheme.Scheme)
+
+	for _, tt := range []struct {
+		name      string
+		cfg       config.Config
+		namespace string
+		audited   bool
+	}{
+		{name: "annotated namespace", cfg: config.New(), namespace: "audited", audited: true},
+		{name: "audit mode", cfg: config.New(config.WithInjectionAudit(true)), namespace: "injected", audited: true},
+		{name: "injected namespace", cfg: config.New(), namespace: "injected", audited: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			mutator := &injectingMutator{}
+			injector := NewWebhookHandler(tt.cfg, logger, decoder, cl, recorder, []PodMutator{mutator})
+
+			res := injector.Handle(context.Background(), req(tt.namespace))
+
+			assert.True(t, res.Allowed)
+			assert.Equal(t, tt.audited, mutator.audited)
+			if !tt.audited {
+				assert.NotEmpty(t, res.Patches)
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			assert.Empty(t, res.Patches, "the audited pods are left unchanged")
+			require.Len(t, recorder.Events, 1)
+			assert.Equal(t, "Normal InjectionAudited the pod webhook would inject the init containers "+
+				"opentelemetry-auto-instrumentation-java, change the containers app, add the volumes "+
+				"opentelemetry-auto-instrumentation-java", <-recorder.Events)
+		})
+	}
+}
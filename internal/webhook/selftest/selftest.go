@@ -0,0 +1,259 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package selftest calls the operator webhooks through the Kubernetes API server, and checks the CA bundles of all the
+// webhook configurations pointing at the operator, so that a serving certificate which doesn't match them is reported
+// by the operator readiness instead of silently breaking the injection.
+package selftest
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch
+
+const (
+	testInterval = time.Minute
+	// triggerDelay leaves time for the webhook server to reload a rotated certificate.
+	triggerDelay = 10 * time.Second
+
+	objectName = "amazon-cloudwatch-agent-operator-self-test"
+	certFile   = "tls.crt"
+)
+
+// certificateErrors are the fragments of the API server errors caused by a certificate the API server doesn't trust.
+var certificateErrors = []string{"x509:", "tls:"}
+
+var _ manager.Runnable = (*SelfTest)(nil)
+var _ healthz.Checker = (*SelfTest)(nil).Check
+
+// SelfTest periodically creates an AmazonCloudWatchAgent in dry-run mode, which makes the API server call the
+// operator webhooks, and checks that the CA bundles of the webhooks pointing at the operator service trust its
+// serving certificate, which covers the pod mutation webhooks of the regular and the critical namespaces, whose
+// failures the API server ignores. It remembers whether the API server could reach the webhooks over TLS.
+type SelfTest struct {
+	client  client.Client
+	logger  logr.Logger
+	service types.NamespacedName
+	certDir string
+	trigger chan struct{}
+
+	mu  sync.RWMutex
+	err error
+}
+
+// New creates a SelfTest for the webhooks of the given service, served with the certificate of the given directory.
+// Its requests are dry-run in the namespace of the service.
+func New(c client.Client, logger logr.Logger, service types.NamespacedName, certDir string) *SelfTest {
+	return &SelfTest{
+		client:  c,
+		logger:  logger,
+		service: service,
+		certDir: certDir,
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// Trigger schedules a self-test, e.g. after the serving certificate was rotated.
+func (s *SelfTest) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the self-test periodically and whenever it is triggered, until the context is done.
+func (s *SelfTest) Start(ctx context.Context) error {
+	ticker := time.NewTicker(testInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.trigger:
+			select {
+			case <-time.After(triggerDelay):
+			case <-ctx.Done():
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
+		}
+		s.Run(ctx)
+	}
+}
+
+// NeedLeaderElection is false, every operator replica reports its own readiness.
+func (s *SelfTest) NeedLeaderElection() bool {
+	return false
+}
+
+// Run calls the webhooks once and records the result reported by Check. An inconclusive test keeps the previous
+// result, so that a certificate failure is reported until a test passes: the replicas failing the readiness check
+// are removed from the endpoints of the webhook service, which makes the next calls inconclusive.
+func (s *SelfTest) Run(ctx context.Context) {
+	passed, err := s.run(ctx)
+	if !passed && err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil && s.err == nil {
+		s.logger.Error(err, "the webhook self-test failed, the API server can't call the webhooks")
+	} else if err == nil && s.err != nil {
+		s.logger.Info("the webhook self-test passed")
+	}
+	s.err = err
+}
+
+// run tells whether the test passed, or returns the certificate failure it found. A test neither passing nor failing
+// is inconclusive.
+func (s *SelfTest) run(ctx context.Context) (bool, error) {
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      objectName,
+			Namespace: s.service.Namespace,
+		},
+	}
+	dryRunErr := s.client.Create(ctx, agent, client.DryRunAll)
+	if dryRunErr != nil && isCertificateError(dryRunErr) {
+		return false, fmt.Errorf("the API server can't call the webhooks over TLS, check the CA bundle of the webhook configurations: %w", dryRunErr)
+	}
+
+	checked, err := s.checkCABundles(ctx)
+	if err != nil {
+		return false, err
+	}
+	if dryRunErr != nil {
+		// other errors, e.g. while no operator replica is ready to serve the webhooks, leave the CA bundles to tell
+		s.logger.V(2).Info("the webhook dry-run is inconclusive", "error", dryRunErr.Error())
+		return checked, nil
+	}
+	return true, nil
+}
+
+// checkCABundles checks that the CA bundles of the webhooks pointing at the service trust the serving certificate
+// for the name of the service, and tells whether it could check any. The certificate or the configurations that
+// can't be read make the check inconclusive.
+func (s *SelfTest) checkCABundles(ctx context.Context) (bool, error) {
+	certs, err := readCertificates(filepath.Join(s.certDir, certFile))
+	if err != nil {
+		s.logger.V(2).Info("the serving certificate can't be read", "error", err.Error())
+		return false, nil
+	}
+	mutating := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	validating := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err = s.client.List(ctx, mutating); err == nil {
+		err = s.client.List(ctx, validating)
+	}
+	if err != nil {
+		s.logger.V(2).Info("the webhook configurations can't be listed", "error", err.Error())
+		return false, nil
+	}
+
+	type webhook struct {
+		configuration, name string
+		clientConfig        admissionregistrationv1.WebhookClientConfig
+	}
+	var webhooks []webhook
+	for _, config := range mutating.Items {
+		for _, w := range config.Webhooks {
+			webhooks = append(webhooks, webhook{config.Name, w.Name, w.ClientConfig})
+		}
+	}
+	for _, config := range validating.Items {
+		for _, w := range config.Webhooks {
+			webhooks = append(webhooks, webhook{config.Name, w.Name, w.ClientConfig})
+		}
+	}
+
+	checked := false
+	for _, w := range webhooks {
+		service := w.clientConfig.Service
+		if service == nil || service.Namespace != s.service.Namespace || service.Name != s.service.Name {
+			continue
+		}
+		checked = true
+		if err := verify(certs, w.clientConfig.CABundle, fmt.Sprintf("%s.%s.svc", s.service.Name, s.service.Namespace)); err != nil {
+			return true, fmt.Errorf("the CA bundle of the webhook %s of %s doesn't trust the serving certificate: %w", w.name, w.configuration, err)
+		}
+	}
+	return checked, nil
+}
+
+// readCertificates reads the PEM certificate chain of the given file, starting with the serving certificate.
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate in %s", path)
+	}
+	return certs, nil
+}
+
+// verify checks that the given CA bundle trusts the serving certificate of the given chain for the given name.
+func verify(certs []*x509.Certificate, caBundle []byte, name string) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caBundle) {
+		return errors.New("the CA bundle holds no certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       name,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// Check fails while the last self-test found a certificate error.
+func (s *SelfTest) Check(_ *http.Request) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
+
+func isCertificateError(err error) bool {
+	message := err.Error()
+	for _, fragment := range certificateErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+var service = types.NamespacedName{Namespace: "amazon-cloudwatch", Name: "webhook-service"}
+
+const (
+	tlsError       = `Internal error occurred: failed calling webhook "mamazoncloudwatchagent.kb.io": tls: failed to verify certificate: x509: certificate signed by unknown authority`
+	noEndpoints    = `Internal error occurred: failed calling webhook "mamazoncloudwatchagent.kb.io": no endpoints available for service "webhook-service"`
+	criticalConfig = "mutating-webhook-configuration-critical"
+)
+
+// newCA returns a self-signed CA, as a PEM bundle, and a serving certificate it signed for the webhook service.
+func newCA(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	serving := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "webhook-service.amazon-cloudwatch.svc"},
+		DNSNames:     []string{"webhook-service.amazon-cloudwatch.svc"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	servingDER, err := x509.CreateCertificate(rand.Reader, serving, ca, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: servingDER})
+}
+
+func mutatingConfig(name, webhook string, caBundle []byte) *admissionregistrationv1.MutatingWebhookConfiguration {
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name: webhook,
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service:  &admissionregistrationv1.ServiceReference{Namespace: service.Namespace, Name: service.Name},
+				CABundle: caBundle,
+			},
+		}},
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, admissionregistrationv1.AddToScheme(scheme))
+
+	caBundle, servingCert := newCA(t)
+	otherCABundle, _ := newCA(t)
+	certDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, "tls.crt"), servingCert, 0600))
+
+	tests := []struct {
+		name string
+		// createErrs are the errors of the dry-runs of the successive runs
+		createErrs []error
+		// criticalCABundle is the CA bundle of the pod webhook of the critical namespaces, when set
+		criticalCABundle []byte
+		certDir          string
+		healthy          bool
+	}{
+		{
+			name:       "webhooks reachable",
+			createErrs: []error{nil},
+			certDir:    certDir,
+			healthy:    true,
+		},
+		{
+			name:       "untrusted certificate",
+			createErrs: []error{errors.New(tlsError)},
+			certDir:    certDir,
+		},
+		{
+			name:       "no ready replica",
+			createErrs: []error{errors.New(noEndpoints)},
+			certDir:    t.TempDir(),
+			healthy:    true,
+		},
+		{
+			name:       "untrusted certificate, then no ready replica",
+			createErrs: []error{errors.New(tlsError), errors.New(noEndpoints)},
+			certDir:    t.TempDir(),
+		},
+		{
+			name:       "untrusted certificate, then trusted CA bundles",
+			createErrs: []error{errors.New(tlsError), errors.New(noEndpoints)},
+			certDir:    certDir,
+			healthy:    true,
+		},
+		{
+			name:             "untrusted certificate in the critical pod webhook",
+			createErrs:       []error{nil},
+			criticalCABundle: otherCABundle,
+			certDir:          certDir,
+		},
+		{
+			name:             "trusted certificate in the critical pod webhook",
+			createErrs:       []error{nil},
+			criticalCABundle: caBundle,
+			certDir:          certDir,
+			healthy:          true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects := []client.Object{mutatingConfig("mutating-webhook-configuration", "mpod.kb.io", caBundle)}
+			if test.criticalCABundle != nil {
+				objects = append(objects, mutatingConfig(criticalConfig, "mpod-critical.kb.io", test.criticalCABundle))
+			}
+			var dryRun bool
+			run := 0
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					createOpts := &client.CreateOptions{}
+					createOpts.ApplyOptions(opts)
+					dryRun = len(createOpts.DryRun) > 0
+					return test.createErrs[run]
+				},
+			}).Build()
+			selfTest := New(c, logr.Discard(), service, test.certDir)
+
+			for run = range test.createErrs {
+				selfTest.Run(context.Background())
+			}
+
+			assert.True(t, dryRun)
+			if test.healthy {
+				assert.NoError(t, selfTest.Check(nil))
+			} else {
+				assert.Error(t, selfTest.Check(nil))
+			}
+		})
+	}
+}
+
+func TestSelfTestReportsTheWebhook(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, admissionregistrationv1.AddToScheme(scheme))
+
+	_, servingCert := newCA(t)
+	otherCABundle, _ := newCA(t)
+	certDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, "tls.crt"), servingCert, 0600))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mutatingConfig(criticalConfig, "mpod-critical.kb.io", otherCABundle)).Build()
+
+	selfTest := New(c, logr.Discard(), service, certDir)
+	selfTest.Run(context.Background())
+
+	assert.ErrorContains(t, selfTest.Check(nil), "the CA bundle of the webhook mpod-critical.kb.io of mutating-webhook-configuration-critical doesn't trust the serving certificate")
+}
@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhookconfig keeps the pod mutation webhook split between the critical namespaces, where it always
+// fails open, and the regular namespaces, where its failure policy is configurable. The namespaces and the pods
+// the webhook applies to can be restricted further through its selectors.
+package webhookconfig
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	// PodWebhookName is the name of the pod mutation webhook in the webhook configuration.
+	PodWebhookName = "mpod.kb.io"
+	// CriticalPodWebhookName is the name of the pod mutation webhook for the critical namespaces.
+	CriticalPodWebhookName = "mpod-critical.kb.io"
+
+	criticalSuffix = "-critical"
+	syncInterval   = time.Minute
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedBy      = "amazon-cloudwatch-agent-operator"
+)
+
+// DefaultCriticalNamespaces are the namespaces needed to bootstrap a cluster, where pods are never rejected
+// because the operator is unavailable.
+var DefaultCriticalNamespaces = []string{metav1.NamespaceSystem, metav1.NamespacePublic, corev1.NamespaceNodeLease}
+
+var _ manager.Runnable = (*Syncer)(nil)
+
+// Syncer keeps the pod mutation webhook of a MutatingWebhookConfiguration restricted to the regular namespaces
+// with the configured failure policy, and mirrors it into a separate, fail-open configuration for the critical
+// namespaces.
+type Syncer struct {
+	client             client.Client
+	logger             logr.Logger
+	name               string
+	criticalNamespaces []string
+	failurePolicy      admissionregistrationv1.FailurePolicyType
+	namespaceSelector  *metav1.LabelSelector
+	objectSelector     *metav1.LabelSelector
+}
+
+// NewSyncer creates a Syncer for the MutatingWebhookConfiguration with the given name.
+func NewSyncer(c client.Client, logger logr.Logger, name string, criticalNamespaces []string, failurePolicy admissionregistrationv1.FailurePolicyType) *Syncer {
+	return &Syncer{
+		client:             c,
+		logger:             logger,
+		name:               name,
+		criticalNamespaces: criticalNamespaces,
+		failurePolicy:      failurePolicy,
+	}
+}
+
+// WithSelectors sets the namespace and the object selectors of the pod mutation webhook, which restrict the
+// namespaces and the pods it applies to. A nil selector keeps the one of the webhook configuration. The critical
+// namespaces are split from the namespace selector, so the webhook never applies to the namespaces it excludes.
+func (s *Syncer) WithSelectors(namespaceSelector, objectSelector *metav1.LabelSelector) *Syncer {
+	s.namespaceSelector = namespaceSelector
+	s.objectSelector = objectSelector
+	return s
+}
+
+// Start syncs the webhook configurations until the context is done, so that changes made by
+// cert-manager or a redeployment are picked up.
+func (s *Syncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sync(ctx); err != nil {
+			s.logger.Error(err, "failed to sync the pod webhook configurations", "name", s.name)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection makes sure a single operator replica updates the webhook configurations.
+func (s *Syncer) NeedLeaderElection() bool {
+	return true
+}
+
+// Sync updates the pod mutation webhook and creates or updates its critical namespaces counterpart, or deletes it
+// when there are no critical namespaces.
+func (s *Syncer) Sync(ctx context.Context) error {
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := s.client.Get(ctx, types.NamespacedName{Name: s.name}, config); err != nil {
+		return err
+	}
+
+	var podWebhook *admissionregistrationv1.MutatingWebhook
+	for i := range config.Webhooks {
+		if config.Webhooks[i].Name == PodWebhookName {
+			podWebhook = &config.Webhooks[i]
+		}
+	}
+	if podWebhook == nil {
+		return fmt.Errorf("webhook %s not found in %s", PodWebhookName, s.name)
+	}
+
+	updated := podWebhook.DeepCopy()
+	updated.FailurePolicy = &s.failurePolicy
+	updated.NamespaceSelector = s.podNamespaceSelector(podWebhook.NamespaceSelector, metav1.LabelSelectorOpNotIn)
+	if s.objectSelector != nil {
+		updated.ObjectSelector = s.objectSelector.DeepCopy()
+	}
+	if !equality.Semantic.DeepEqual(podWebhook, updated) {
+		*podWebhook = *updated
+		if err := s.client.Update(ctx, config); err != nil {
+			return err
+		}
+		s.logger.Info("updated the pod webhook", "name", s.name, "failurePolicy", s.failurePolicy)
+	}
+
+	critical := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: s.name + criticalSuffix},
+	}
+	// the critical namespaces configuration left by a previous configuration of the operator is garbage collected
+	if len(s.criticalNamespaces) == 0 {
+		if err := s.client.Get(ctx, client.ObjectKeyFromObject(critical), critical); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !managedByOperator(critical) {
+			return nil
+		}
+		if err := s.client.Delete(ctx, critical); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		s.logger.Info("deleted the pod webhook of the critical namespaces", "name", critical.Name)
+		return nil
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, s.client, critical, func() error {
+		webhook := podWebhook.DeepCopy()
+		webhook.Name = CriticalPodWebhookName
+		ignore := admissionregistrationv1.Ignore
+		webhook.FailurePolicy = &ignore
+		webhook.NamespaceSelector = s.podNamespaceSelector(podWebhook.NamespaceSelector, metav1.LabelSelectorOpIn)
+		critical.Labels = map[string]string{}
+		for k, v := range config.Labels {
+			critical.Labels[k] = v
+		}
+		critical.Labels[managedByLabel] = managedBy
+		// the configuration is deleted along with the one of the install it mirrors
+		critical.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+			Kind:       "MutatingWebhookConfiguration",
+			Name:       config.Name,
+			UID:        config.UID,
+		}}
+		critical.Webhooks = []admissionregistrationv1.MutatingWebhook{*webhook}
+		return nil
+	})
+	return err
+}
+
+// managedByOperator tells whether the operator created the given critical namespaces configuration, including the
+// ones created before it was labeled.
+func managedByOperator(critical *admissionregistrationv1.MutatingWebhookConfiguration) bool {
+	if critical.Labels[managedByLabel] == managedBy {
+		return true
+	}
+	return len(critical.Webhooks) == 1 && critical.Webhooks[0].Name == CriticalPodWebhookName
+}
+
+// podNamespaceSelector returns the namespace selector of the pod mutation webhook selecting the critical namespaces
+// with the In operator, or the other ones with NotIn. It is based on the configured namespace selector, or else on
+// the current selector of the webhook.
+func (s *Syncer) podNamespaceSelector(current *metav1.LabelSelector, operator metav1.LabelSelectorOperator) *metav1.LabelSelector {
+	if s.namespaceSelector == nil {
+		return withNamespaces(current, operator, s.criticalNamespaces)
+	}
+	// the requirements of the configured selector on the namespace names are kept
+	result := s.namespaceSelector.DeepCopy()
+	if len(s.criticalNamespaces) > 0 {
+		result.MatchExpressions = append(result.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      corev1.LabelMetadataName,
+			Operator: operator,
+			Values:   s.criticalNamespaces,
+		})
+	}
+	return result
+}
+
+// withNamespaces returns a copy of the selector whose namespace name requirement is replaced by the given one.
+func withNamespaces(selector *metav1.LabelSelector, operator metav1.LabelSelectorOperator, namespaces []string) *metav1.LabelSelector {
+	result := &metav1.LabelSelector{}
+	if selector != nil {
+		result = selector.DeepCopy()
+	}
+	var expressions []metav1.LabelSelectorRequirement
+	for _, expression := range result.MatchExpressions {
+		if expression.Key != corev1.LabelMetadataName {
+			expressions = append(expressions, expression)
+		}
+	}
+	if len(namespaces) > 0 {
+		expressions = append(expressions, metav1.LabelSelectorRequirement{
+			Key:      corev1.LabelMetadataName,
+			Operator: operator,
+			Values:   namespaces,
+		})
+	}
+	result.MatchExpressions = expressions
+	return result
+}
@@ -0,0 +1,151 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package webhookconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSync(t *testing.T) {
+	ignore := admissionregistrationv1.Ignore
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-config"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{Name: "minstrumentation.kb.io"},
+			{
+				Name:          PodWebhookName,
+				FailurePolicy: &ignore,
+				ClientConfig:  admissionregistrationv1.WebhookClientConfig{CABundle: []byte("ca")},
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"team": "a"},
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(config).Build()
+	syncer := NewSyncer(c, logr.Discard(), "webhook-config", DefaultCriticalNamespaces, admissionregistrationv1.Fail)
+
+	// the second sync is a no-op
+	for i := 0; i < 2; i++ {
+		require.NoError(t, syncer.Sync(context.Background()))
+	}
+
+	updated := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "webhook-config"}, updated))
+	require.Len(t, updated.Webhooks, 2)
+	podWebhook := updated.Webhooks[1]
+	assert.Equal(t, admissionregistrationv1.Fail, *podWebhook.FailurePolicy)
+	assert.Equal(t, map[string]string{"team": "a"}, podWebhook.NamespaceSelector.MatchLabels)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   DefaultCriticalNamespaces,
+	}}, podWebhook.NamespaceSelector.MatchExpressions)
+
+	critical := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "webhook-config-critical"}, critical))
+	require.Len(t, critical.Webhooks, 1)
+	assert.Equal(t, CriticalPodWebhookName, critical.Webhooks[0].Name)
+	assert.Equal(t, admissionregistrationv1.Ignore, *critical.Webhooks[0].FailurePolicy)
+	assert.Equal(t, []byte("ca"), critical.Webhooks[0].ClientConfig.CABundle)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   DefaultCriticalNamespaces,
+	}}, critical.Webhooks[0].NamespaceSelector.MatchExpressions)
+	assert.Equal(t, "amazon-cloudwatch-agent-operator", critical.Labels["app.kubernetes.io/managed-by"])
+	require.Len(t, critical.OwnerReferences, 1)
+	assert.Equal(t, "webhook-config", critical.OwnerReferences[0].Name)
+
+	// the critical namespaces configuration is deleted once there are no critical namespaces
+	syncer = NewSyncer(c, logr.Discard(), "webhook-config", nil, admissionregistrationv1.Fail)
+	require.NoError(t, syncer.Sync(context.Background()))
+	assert.True(t, apierrors.IsNotFound(c.Get(context.Background(), types.NamespacedName{Name: "webhook-config-critical"}, critical)))
+	require.NoError(t, syncer.Sync(context.Background()))
+}
+
+func TestSyncKeepsForeignCriticalConfig(t *testing.T) {
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-config"},
+		Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: PodWebhookName}},
+	}
+	foreign := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-config-critical"},
+		Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "other.example.com"}},
+	}
+	c := fake.NewClientBuilder().WithObjects(config, foreign).Build()
+	syncer := NewSyncer(c, logr.Discard(), "webhook-config", nil, admissionregistrationv1.Fail)
+
+	require.NoError(t, syncer.Sync(context.Background()))
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "webhook-config-critical"}, foreign))
+}
+
+func TestSyncMissingWebhook(t *testing.T) {
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-config"},
+	}
+	c := fake.NewClientBuilder().WithObjects(config).Build()
+	syncer := NewSyncer(c, logr.Discard(), "webhook-config", DefaultCriticalNamespaces, admissionregistrationv1.Fail)
+	assert.Error(t, syncer.Sync(context.Background()))
+}
+
+func TestSyncSelectors(t *testing.T) {
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-config"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name: PodWebhookName,
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "a"},
+			},
+		}},
+	}
+	c := fake.NewClientBuilder().WithObjects(config).Build()
+	excludeKubeSystem := metav1.LabelSelectorRequirement{
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   []string{metav1.NamespaceSystem},
+	}
+	syncer := NewSyncer(c, logr.Discard(), "webhook-config", DefaultCriticalNamespaces, admissionregistrationv1.Ignore).WithSelectors(
+		&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{excludeKubeSystem}},
+		&metav1.LabelSelector{MatchLabels: map[string]string{"instrumentation": "enabled"}},
+	)
+
+	// the second sync is a no-op
+	for i := 0; i < 2; i++ {
+		require.NoError(t, syncer.Sync(context.Background()))
+	}
+
+	updated := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "webhook-config"}, updated))
+	podWebhook := updated.Webhooks[0]
+	// the configured namespace selector replaces the one of the configuration
+	assert.Empty(t, podWebhook.NamespaceSelector.MatchLabels)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{excludeKubeSystem, {
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   DefaultCriticalNamespaces,
+	}}, podWebhook.NamespaceSelector.MatchExpressions)
+	assert.Equal(t, map[string]string{"instrumentation": "enabled"}, podWebhook.ObjectSelector.MatchLabels)
+
+	critical := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "webhook-config-critical"}, critical))
+	// kube-system is excluded from the critical namespaces webhook too
+	assert.Equal(t, []metav1.LabelSelectorRequirement{excludeKubeSystem, {
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   DefaultCriticalNamespaces,
+	}}, critical.Webhooks[0].NamespaceSelector.MatchExpressions)
+	assert.Equal(t, map[string]string{"instrumentation": "enabled"}, critical.Webhooks[0].ObjectSelector.MatchLabels)
+}
@@ -8,22 +8,41 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
 	routev1 "github.com/openshift/api/route/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/spf13/pflag"
 	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
 	k8sapiflag "k8s.io/component-base/cli/flag"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -32,10 +51,24 @@ import (
 
 	otelv1alpha1 "github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/controllers"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/alarms"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/bootstrap"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/clusterinfo"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/compatibility"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/exporterpolicy"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/imagedigest"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/injectionstats"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/promguardrails"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/translator"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/certrotation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/namespacemutation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/ownershipprotection"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/selftest"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/webhookconfig"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/workloadmutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
@@ -53,6 +86,16 @@ const (
 	dcgmExporterImageRepository              = "nvcr.io/nvidia/k8s/dcgm-exporter"
 	neuronMonitorImageRepository             = "public.ecr.aws/neuron"
 	targetAllocatorImageRepository           = "public.ecr.aws/cloudwatch-agent/cloudwatch-agent-target-allocator"
+	prometheusReloaderImage                  = "public.ecr.aws/docker/library/busybox:1.36"
+
+	webhookCertProviderExternal   = "external"
+	webhookCertProviderSelfSigned = "self-signed"
+
+	compatibilityCheckWarn     = "warn"
+	compatibilityCheckEnforce  = "enforce"
+	compatibilityCheckDisabled = "disabled"
+	// serverVersionPeriod is how long the version of the Kubernetes API server is used before being read again.
+	serverVersionPeriod = 10 * time.Minute
 )
 
 var (
@@ -134,6 +177,63 @@ func main() {
 		dcgmExporterImage            string
 		neuronMonitorImage           string
 		targetAllocatorImage         string
+		prometheusReloader           string
+		legacyAgentKind              string
+		translateOtelCollectors      bool
+		enableTenants                bool
+		reconcileInterval            time.Duration
+		reconcileRetryBaseDelay      time.Duration
+		reconcileRetryMaxDelay       time.Duration
+		maxConcurrentReconciles      int
+		kubeAPIQPS                   float32
+		kubeAPIBurst                 int
+		watchNamespaces              string
+		crLabelSelector              string
+		podWebhookConfiguration      string
+		podWebhookFailurePolicy      string
+		podWebhookNamespaceSelector  string
+		podWebhookObjectSelector     string
+		criticalNamespaces           []string
+		exporterPolicyFile           string
+		prometheusGuardrailsFile     string
+		webhookCertProvider          string
+		webhookCertSecret            string
+		webhookService               string
+		webhookConfigurations        []string
+		webhookConversionCRDs        []string
+		enableAlarms                 bool
+		alarmsRegion                 string
+		enableConfigMapHistory       bool
+		configMapHistoryLimit        int
+		enableWebhooks               bool
+		enableTargetAllocator        bool
+		leaderElect                  bool
+		leaderElectionID             string
+		leaseDuration                time.Duration
+		renewDeadline                time.Duration
+		retryPeriod                  time.Duration
+		webhookOnly                  bool
+		clusterName                  string
+		region                       string
+		discoverClusterInfo          bool
+		clusterInfoConfigMap         string
+		disabledTasks                []string
+		autoDeployAcceleratedCompute bool
+		nodeLocalExport              bool
+		requireImageDigest           bool
+		enableConfigOverrides        bool
+		injectionAuditMode           bool
+		resourceNamePrefix           string
+		configTranslatorPath         string
+		compatibilityCheck           string
+		compatibilityMatrix          string
+		protectOwnedObjects          bool
+		ownedObjectsAllowedUsers     []string
+		nodeTerminationFlush         bool
+		bootstrapStack               bool
+		bootstrapNamespace           string
+		bootstrapServiceAccount      string
+		controlPlaneMetricsRole      string
 	)
 
 	pflag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
@@ -149,6 +249,63 @@ func main() {
 	stringFlagOrEnv(&dcgmExporterImage, "dcgm-exporter-image", "RELATED_IMAGE_DCGM_EXPORTER", fmt.Sprintf("%s:%s", dcgmExporterImageRepository, v.DcgmExporter), "The default DCGM Exporter image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&neuronMonitorImage, "neuron-monitor-image", "RELATED_IMAGE_NEURON_MONITOR", fmt.Sprintf("%s:%s", neuronMonitorImageRepository, v.NeuronMonitor), "The default Neuron monitor image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&targetAllocatorImage, "target-allocator-image", "RELATED_IMAGE_TARGET_ALLOCATOR", fmt.Sprintf("%s:%s", targetAllocatorImageRepository, v.TargetAllocator), "The default AmazonCloudWatchAgent target allocator image. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&prometheusReloader, "prometheus-reloader-image", "RELATED_IMAGE_PROMETHEUS_RELOADER", prometheusReloaderImage, "The default image of the sidecars reloading the Prometheus configuration and the config of the agents. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&watchNamespaces, "watch-namespaces", "WATCH_NAMESPACE", "", "The comma-separated list of namespaces watched by this operator instance. All namespaces are watched when empty.")
+	pflag.StringVar(&crLabelSelector, "cr-label-selector", "", "The label selector restricting the AmazonCloudWatchAgent, DcgmExporter and NeuronMonitor CRs reconciled by this operator instance. All CRs are reconciled when empty. The host port and object name conflicts are still checked against all the agents, while the pod webhook only injects the sidecars of the agents matching it.")
+	pflag.StringVar(&podWebhookConfiguration, "pod-webhook-configuration", "", "The name of the MutatingWebhookConfiguration holding the pod mutation webhook. When set, the operator keeps the pod webhook split between the critical namespaces, where it fails open, and the other namespaces.")
+	pflag.StringVar(&podWebhookFailurePolicy, "pod-webhook-failure-policy", string(admissionregistrationv1.Ignore), "The failure policy of the pod mutation webhook outside of the critical namespaces, either Ignore or Fail. Requires --pod-webhook-configuration.")
+	pflag.StringVar(&podWebhookNamespaceSelector, "pod-webhook-namespace-selector", "", "The label selector of the namespaces the pod mutation webhook applies to, such as kubernetes.io/metadata.name notin (kube-system). The namespace selector of the webhook configuration is kept when empty. Requires --pod-webhook-configuration.")
+	pflag.StringVar(&podWebhookObjectSelector, "pod-webhook-object-selector", "", "The label selector of the pods the pod mutation webhook applies to. The object selector of the webhook configuration is kept when empty. Requires --pod-webhook-configuration.")
+	pflag.StringSliceVar(&criticalNamespaces, "critical-namespaces", webhookconfig.DefaultCriticalNamespaces, "The namespaces where the pod mutation webhook always fails open. Requires --pod-webhook-configuration.")
+	pflag.StringVar(&legacyAgentKind, "legacy-agent-kind", "", "The kind of a legacy AmazonCloudWatchAgent API to mirror into AmazonCloudWatchAgent objects during a migration, in the Kind.version.group form. Mirroring is disabled when empty.")
+	pflag.StringVar(&exporterPolicyFile, "exporter-policy", "", "The path to a YAML file listing the allowedExporters and allowedEndpoints of the agents. The validating webhook rejects agent configs using other exporters or endpoints. Every exporter is allowed when empty.")
+	pflag.StringVar(&prometheusGuardrailsFile, "prometheus-guardrails", "", "The path to a YAML file listing the limits of the Prometheus scrape configs of the agents: maxScrapeConfigs, maxStaticTargets, maxRelabelConfigs, maxRegexComplexity and denyClusterWideDiscovery. The validating webhook warns about the configs exceeding them, or rejects them when enforce is true. The scrape configs are not limited when empty.")
+	pflag.StringVar(&webhookCertProvider, "webhook-cert-provider", webhookCertProviderExternal, "The provider of the webhook serving certificate, either external, when cert-manager or the user mounts it, or self-signed, when the operator issues it from its own certificate authority.")
+	pflag.StringVar(&webhookCertSecret, "webhook-cert-secret", "amazon-cloudwatch/amazon-cloudwatch-agent-operator-webhook-cert", "The namespace/name of the Secret storing the self-signed webhook certificates. Requires --webhook-cert-provider=self-signed.")
+	pflag.StringVar(&webhookService, "webhook-service", "amazon-cloudwatch/amazon-cloudwatch-agent-operator-webhook-service", "The namespace/name of the Service in front of the webhook server. The webhook self-test runs in its namespace.")
+	pflag.StringSliceVar(&webhookConfigurations, "webhook-configurations", []string{"amazon-cloudwatch-agent-operator-mutating-webhook-configuration", "amazon-cloudwatch-agent-operator-validating-webhook-configuration"}, "The mutating and validating webhook configurations the self-signed certificate authority is injected into. Requires --webhook-cert-provider=self-signed.")
+	pflag.StringSliceVar(&webhookConversionCRDs, "webhook-conversion-crds", nil, "The CustomResourceDefinitions whose conversion webhook the self-signed certificate authority is injected into. Requires --webhook-cert-provider=self-signed.")
+	pflag.BoolVar(&translateOtelCollectors, "translate-opentelemetry-collectors", false, "Translate the opentelemetry.io/v1alpha1 OpenTelemetryCollector objects into AmazonCloudWatchAgent objects, to migrate from the OpenTelemetry operator. Requires the OpenTelemetryCollector CRD.")
+	pflag.BoolVar(&enableTenants, "enable-tenants", false, "Stamp out an AmazonCloudWatchAgent in each namespace labeled cloudwatch.aws.amazon.com/tenant=true, from the AmazonCloudWatchAgentTemplate named by the cloudwatch.aws.amazon.com/tenant-template annotation of the namespace, or the default one. Requires the AmazonCloudWatchAgentTemplate CRD.")
+	pflag.DurationVar(&reconcileInterval, "reconcile-interval", 0, "The interval after which the AmazonCloudWatchAgent, DcgmExporter and NeuronMonitor objects are reconciled again, to correct changes made to the managed objects outside the operator. The objects are only reconciled on changes when zero.")
+	pflag.DurationVar(&reconcileRetryBaseDelay, "reconcile-retry-base-delay", time.Second, "The delay before retrying the failed reconciliation of an AmazonCloudWatchAgent, DcgmExporter or NeuronMonitor object, doubled after each consecutive failure of the object.")
+	pflag.DurationVar(&reconcileRetryMaxDelay, "reconcile-retry-max-delay", 5*time.Minute, "The maximum delay before retrying the failed reconciliation of an AmazonCloudWatchAgent, DcgmExporter or NeuronMonitor object.")
+	pflag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1, "The number of objects of each kind reconciled concurrently. An object is never reconciled by two workers at once. Raise it, along with --kube-api-qps and --kube-api-burst, for the clusters with many agents.")
+	pflag.Float32Var(&kubeAPIQPS, "kube-api-qps", 20, "The maximum number of queries per second of the operator to the Kubernetes API server.")
+	pflag.IntVar(&kubeAPIBurst, "kube-api-burst", 30, "The maximum burst of queries of the operator to the Kubernetes API server.")
+	pflag.BoolVar(&enableAlarms, "enable-cloudwatch-alarms", false, "Manage the CloudWatch alarms defined in the alarms attribute of the AmazonCloudWatchAgent objects. Requires AWS credentials allowing cloudwatch:PutMetricAlarm, cloudwatch:DescribeAlarms and cloudwatch:DeleteAlarms.")
+	stringFlagOrEnv(&alarmsRegion, "cloudwatch-alarms-region", "AWS_REGION", "", "The AWS region of the CloudWatch alarms. Requires --enable-cloudwatch-alarms.")
+	pflag.BoolVar(&enableConfigMapHistory, "enable-configmap-history", false, "Keep immutable copies of the previous revisions of the agent ConfigMaps, to roll back a configuration change.")
+	pflag.IntVar(&configMapHistoryLimit, "configmap-history-limit", 5, "The number of previous revisions of each agent ConfigMap kept, the older ones are deleted. Requires --enable-configmap-history.")
+	pflag.BoolVar(&enableWebhooks, "enable-webhooks", os.Getenv("ENABLE_WEBHOOKS") != "false", "Serve the admission webhooks: the validation and defaulting of the CRs, the pod mutation injecting the instrumentation and the auto-annotation. Without them, the operator only renders the agent workloads, which is an unsupported mode. Defaults to false when the ENABLE_WEBHOOKS environment variable is false.")
+	pflag.BoolVar(&enableTargetAllocator, "enable-target-allocator", true, "Deploy the target allocators of the AmazonCloudWatchAgent objects enabling them. When disabled, the AmazonCloudWatchAgent objects enabling the target allocator are rejected.")
+	stringFlagOrEnv(&clusterName, "cluster-name", "CLUSTER_NAME", "", "The name of the cluster, substituted for the ${cluster_name} variable of the agent configs.")
+	stringFlagOrEnv(&region, "region", "AWS_REGION", "", "The AWS region of the cluster, substituted for the ${region} variable of the agent configs.")
+	pflag.BoolVar(&discoverClusterInfo, "discover-cluster-info", true, "Discover the name and the region of the cluster left empty by --cluster-name and --region, from the --cluster-info-configmap, the labels of the nodes or the EC2 instance metadata.")
+	pflag.StringVar(&clusterInfoConfigMap, "cluster-info-configmap", "amazon-cloudwatch/cluster-info", "The namespace/name of the ConfigMap whose cluster_name and region keys set the name and the region of the cluster. Requires --discover-cluster-info.")
+	pflag.BoolVar(&autoDeployAcceleratedCompute, "auto-deploy-accelerated-compute", false, "Deploy the DCGM exporter and the Neuron monitor on the nodes with GPUs and Neuron devices when the amazon-cloudwatch/cloudwatch-agent collects the accelerated compute metrics of the enhanced Container Insights.")
+	pflag.BoolVar(&nodeLocalExport, "node-local-export", false, "Make the instrumented pods export to the amazon-cloudwatch/cloudwatch-agent of their own node through its host IP, when the agent is a daemonset on the host network. The cloudwatch.aws.amazon.com/node-local-export annotation of the pods or their namespace overrides it.")
+	pflag.BoolVar(&requireImageDigest, "require-image-digest", false, "Deploy the agents by image digest only. The tags of the images without spec.imageDigest are resolved through the registries, authenticated with the image pull secrets of the service account of the agent, or the AWS credentials of the operator for the private Amazon ECR registries, and an agent whose digest can't be resolved isn't deployed.")
+	pflag.BoolVar(&enableConfigOverrides, "enable-config-overrides", false, "Layer the patches of the AmazonCloudWatchAgentConfigOverrides onto the configs of the agents they select, by increasing priority. Requires the AmazonCloudWatchAgentConfigOverride CRD.")
+	pflag.BoolVar(&injectionAuditMode, "injection-audit-mode", false, "Only log and record as Events the sidecars and the auto-instrumentation the pod webhook would inject into the pods, rather than injecting them, to preview the pods affected by the annotations. The cloudwatch.aws.amazon.com/injection-audit annotation of a namespace enables it for its pods.")
+	pflag.StringVar(&resourceNamePrefix, "resource-name-prefix", "", "The prefix of the names of the objects created for the AmazonCloudWatchAgents, such as their config maps, services and workloads, to follow naming policies. Changing it renames the objects of the existing agents.")
+	pflag.StringVar(&configTranslatorPath, "config-translator", "", "The path to the config-translator binary of the CloudWatch agent. When set, the TOML and YAML translations of the JSON config of each agent are projected to its ConfigMap and the agent binary is started directly on them, and an agent whose config can't be translated isn't deployed. The configs are translated by the agents at start when empty.")
+	pflag.StringVar(&compatibilityCheck, "compatibility-check", compatibilityCheckDisabled, "How the versions of the operator, of the agent images and of Kubernetes are checked against the support matrix: disabled, warn, where the validating webhook warns about the unsupported agents, or enforce, where it rejects them. Either way, the VersionsSupported condition of the agents reports the check.")
+	pflag.StringVar(&compatibilityMatrix, "compatibility-matrix", "", "The YAML or JSON file of the support matrix the versions are checked against, in place of the one embedded in the operator, which only holds the lower bounds the operator requires. Requires --compatibility-check.")
+	pflag.BoolVar(&protectOwnedObjects, "protect-owned-objects", false, "Reject the manual updates of the Deployments, DaemonSets, StatefulSets and ConfigMaps labeled as managed by the operator, which it would revert, unless their AmazonCloudWatchAgent is unmanaged. The operator itself and the --owned-objects-allowed-users can still update them. Requires --enable-webhooks.")
+	pflag.StringSliceVar(&ownedObjectsAllowedUsers, "owned-objects-allowed-users", ownershipprotection.DefaultAllowedUsers, "The users, such as system:serviceaccount:<namespace>:<name>, allowed to update the objects managed by the operator. Requires --protect-owned-objects.")
+	pflag.BoolVar(&nodeTerminationFlush, "node-termination-flush", false, "Flush the agent pods of the AmazonCloudWatchAgents setting spec.nodeTermination once their node is tainted for its termination, by deleting them with a grace period so that the agent flushes on the SIGTERM.")
+	pflag.BoolVar(&bootstrapStack, "bootstrap", false, "Create the default Application Signals stack of the --bootstrap-namespace at start, like the Amazon CloudWatch Observability EKS add-on: a cloudwatch-agent AmazonCloudWatchAgent daemonset collecting the enhanced Container Insights and Application Signals, unless the namespace has an AmazonCloudWatchAgent, and a default-instrumentation Instrumentation, unless it has an Instrumentation.")
+	pflag.StringVar(&bootstrapNamespace, "bootstrap-namespace", "amazon-cloudwatch", "The namespace of the default Application Signals stack. Requires --bootstrap.")
+	pflag.StringVar(&bootstrapServiceAccount, "bootstrap-service-account", bootstrap.DefaultAgentServiceAccount, "The service account of the default agent, which the operator manifests bind to the agent ClusterRole in the amazon-cloudwatch namespace. Another --bootstrap-namespace needs the same binding. Empty for the operator to create a service account for the agent, which needs a binding too. Requires --bootstrap.")
+	pflag.StringVar(&controlPlaneMetricsRole, "control-plane-metrics-role", "cloudwatch-control-plane-metrics", "The name of the ClusterRole reading the metrics of the control plane, and of its ClusterRoleBinding deployed along with the operator, whose subjects the operator sets to the service accounts of the agents setting spec.controlPlaneMetrics. The manager ClusterRole of the operator manifests only allows binding the default one.")
+	pflag.StringSliceVar(&disabledTasks, "disable-reconcile-tasks", nil, "The comma-separated names of the registered reconcile tasks not to run.")
+	pflag.BoolVar(&leaderElect, "leader-elect", false, "Elect a leader among the operator replicas, which alone reconciles the objects while every replica serves the webhooks.")
+	pflag.StringVar(&leaderElectionID, "leader-election-id", "amazon-cloudwatch-agent-operator-leader", "The name of the Lease the leader is elected through, in the namespace of the operator. Requires --leader-elect.")
+	pflag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second, "The duration the other replicas wait before taking over the leadership when the leader stops renewing it. Requires --leader-elect.")
+	pflag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second, "The duration the leader retries renewing its leadership before giving it up. Requires --leader-elect.")
+	pflag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second, "The interval between the attempts of the replicas to acquire or renew the leadership. Requires --leader-elect.")
+	pflag.BoolVar(&webhookOnly, "webhook-only", false, "Only serve the admission webhooks, without reconciling the objects. Additional replicas running in this mode keep the pod mutation webhook available while the replica reconciling the objects restarts. Requires --enable-webhooks.")
 	pflag.Parse()
 
 	// set instrumentation cpu and memory limits in environment variables to be used for default instrumentation; default values received from https://github.com/open-telemetry/opentelemetry-operator/blob/main/apis/v1alpha1/instrumentation_webhook.go
@@ -189,12 +346,93 @@ func main() {
 		"dcgm-exporter", dcgmExporterImage,
 		"neuron-monitor", neuronMonitorImage,
 		"amazon-cloudwatch-agent-target-allocator", targetAllocatorImage,
+		"prometheus-reloader", prometheusReloader,
 		"build-date", v.BuildDate,
 		"go-version", v.Go,
 		"go-arch", runtime.GOARCH,
 		"go-os", runtime.GOOS,
 	)
 
+	var policy *exporterpolicy.Policy
+	if exporterPolicyFile != "" {
+		if policy, err = exporterpolicy.Load(exporterPolicyFile); err != nil {
+			setupLog.Error(err, "unable to load the exporter policy")
+			os.Exit(1)
+		}
+		setupLog.Info("enforcing the exporter policy", "file", exporterPolicyFile)
+	}
+
+	var guardrails *promguardrails.Guardrails
+	if prometheusGuardrailsFile != "" {
+		if guardrails, err = promguardrails.Load(prometheusGuardrailsFile); err != nil {
+			setupLog.Error(err, "unable to load the Prometheus guardrails")
+			os.Exit(1)
+		}
+		setupLog.Info("checking the Prometheus guardrails", "file", prometheusGuardrailsFile, "enforce", guardrails.Enforce)
+	}
+
+	var configMapHistory int
+	if enableConfigMapHistory {
+		if configMapHistoryLimit < 1 {
+			setupLog.Error(fmt.Errorf("expected a positive number, got %d", configMapHistoryLimit), "invalid --configmap-history-limit")
+			os.Exit(1)
+		}
+		configMapHistory = configMapHistoryLimit
+		setupLog.Info("keeping the previous revisions of the agent ConfigMaps", "revisions", configMapHistory)
+	}
+
+	if maxConcurrentReconciles < 1 {
+		setupLog.Error(fmt.Errorf("expected a positive number, got %d", maxConcurrentReconciles), "invalid --max-concurrent-reconciles")
+		os.Exit(1)
+	}
+	if reconcileRetryBaseDelay <= 0 || reconcileRetryMaxDelay < reconcileRetryBaseDelay {
+		setupLog.Error(fmt.Errorf("expected a positive base delay up to the max delay, got %s and %s", reconcileRetryBaseDelay, reconcileRetryMaxDelay), "invalid --reconcile-retry-base-delay or --reconcile-retry-max-delay")
+		os.Exit(1)
+	}
+	if kubeAPIQPS <= 0 || kubeAPIBurst < 1 {
+		setupLog.Error(fmt.Errorf("expected positive numbers, got %v and %d", kubeAPIQPS, kubeAPIBurst), "invalid --kube-api-qps or --kube-api-burst")
+		os.Exit(1)
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = kubeAPIQPS
+	restConfig.Burst = kubeAPIBurst
+	if discoverClusterInfo && (clusterName == "" || region == "") {
+		info, discoverErr := discoverClusterInfoOf(restConfig, clusterInfoConfigMap, clusterinfo.Info{ClusterName: clusterName, Region: region})
+		if discoverErr != nil {
+			setupLog.Error(discoverErr, "invalid --cluster-info-configmap")
+			os.Exit(1)
+		}
+		clusterName, region = info.ClusterName, info.Region
+	}
+
+	var configTranslator translator.Translator
+	if configTranslatorPath != "" {
+		configTranslator = translator.NewCached(translator.NewCommand(configTranslatorPath))
+		setupLog.Info("translating the agent configs", "config-translator", configTranslatorPath)
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create the discovery client")
+		os.Exit(1)
+	}
+	serverVersion := compatibility.ServerVersion(dc, serverVersionPeriod, ctrl.Log.WithName("serverversion"))
+
+	var compatibilityChecker *compatibility.Checker
+	switch compatibilityCheck {
+	case compatibilityCheckDisabled:
+	case compatibilityCheckWarn, compatibilityCheckEnforce:
+		if compatibilityChecker, err = newCompatibilityChecker(serverVersion, compatibilityMatrix, v.Operator, compatibilityCheck == compatibilityCheckEnforce); err != nil {
+			setupLog.Error(err, "unable to check the versions against the support matrix")
+			os.Exit(1)
+		}
+		setupLog.Info("checking the versions against the support matrix", "operator", compatibilityChecker.Operator, "kubernetes", compatibilityChecker.KubernetesVersion(), "matrix", compatibilityMatrix, "enforce", compatibilityChecker.Enforce)
+	default:
+		setupLog.Error(fmt.Errorf("expected warn, enforce or disabled, got %s", compatibilityCheck), "invalid --compatibility-check")
+		os.Exit(1)
+	}
+
 	cfg := config.New(
 		config.WithLogger(ctrl.Log.WithName("config")),
 		config.WithVersion(v),
@@ -206,24 +444,89 @@ func main() {
 		config.WithDcgmExporterImage(dcgmExporterImage),
 		config.WithNeuronMonitorImage(neuronMonitorImage),
 		config.WithTargetAllocatorImage(targetAllocatorImage),
+		config.WithTargetAllocatorEnabled(enableTargetAllocator),
+		config.WithPrometheusReloaderImage(prometheusReloader),
+		config.WithExporterPolicy(policy),
+		config.WithReconcileInterval(reconcileInterval),
+		config.WithReconcileRetryDelays(reconcileRetryBaseDelay, reconcileRetryMaxDelay),
+		config.WithConfigMapHistory(configMapHistory),
+		config.WithPrometheusGuardrails(guardrails),
+		config.WithClusterName(clusterName),
+		config.WithRegion(region),
+		config.WithAcceleratedComputeAutoDeploy(autoDeployAcceleratedCompute),
+		config.WithNodeLocalExport(nodeLocalExport),
+		config.WithRequireImageDigest(requireImageDigest),
+		config.WithConfigOverrides(enableConfigOverrides),
+		config.WithInjectionAudit(injectionAuditMode),
+		config.WithConfigTranslator(configTranslator),
+		config.WithCompatibilityChecker(compatibilityChecker),
+		config.WithKubernetesVersion(serverVersion),
+		config.WithControlPlaneMetricsRole(controlPlaneMetricsRole),
 	)
 
-	watchNamespace, found := os.LookupEnv("WATCH_NAMESPACE")
-	if found {
-		setupLog.Info("watching namespace(s)", "namespaces", watchNamespace)
+	var namespaces map[string]cache.Config
+	for _, ns := range strings.Split(watchNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns == "" {
+			continue
+		}
+		if namespaces == nil {
+			namespaces = map[string]cache.Config{}
+		}
+		namespaces[ns] = cache.Config{}
+	}
+	if namespaces != nil {
+		setupLog.Info("watching namespace(s)", "namespaces", watchNamespaces)
 	} else {
-		setupLog.Info("the env var WATCH_NAMESPACE isn't set, watching all namespaces")
+		setupLog.Info("neither --watch-namespaces nor WATCH_NAMESPACE is set, watching all namespaces")
+	}
+
+	if resourceNamePrefix != "" {
+		if errs := validation.IsDNS1123Label(strings.TrimSuffix(resourceNamePrefix, "-")); len(errs) > 0 {
+			setupLog.Error(fmt.Errorf("%s", strings.Join(errs, ", ")), "invalid resource name prefix", "prefix", resourceNamePrefix)
+			os.Exit(1)
+		}
+		naming.SetResourceNamePrefix(resourceNamePrefix)
+	}
+
+	// restrict the CRs reconciled by this operator instance, the objects they own are filtered through their owner
+	var byObject map[client.Object]cache.ByObject
+	if crLabelSelector != "" {
+		selector, selectorErr := labels.Parse(crLabelSelector)
+		if selectorErr != nil {
+			setupLog.Error(selectorErr, "invalid CR label selector", "selector", crLabelSelector)
+			os.Exit(1)
+		}
+		setupLog.Info("reconciling the CRs matching the label selector", "selector", selector.String())
+		byObject = map[client.Object]cache.ByObject{
+			&otelv1alpha1.AmazonCloudWatchAgent{}: {Label: selector},
+			&otelv1alpha1.DcgmExporter{}:          {Label: selector},
+			&otelv1alpha1.NeuronMonitor{}:         {Label: selector},
+		}
 	}
 
 	optionsTlSOptsFuncs := []func(*tls.Config){
 		func(config *tls.Config) { tlsConfigSetting(config, tlsOpt) },
 	}
-	var namespaces map[string]cache.Config
-	if strings.Contains(watchNamespace, ",") {
-		namespaces = map[string]cache.Config{}
-		for _, ns := range strings.Split(watchNamespace, ",") {
-			namespaces[ns] = cache.Config{}
-		}
+
+	var webhookCertDir string
+	switch webhookCertProvider {
+	case webhookCertProviderExternal:
+	case webhookCertProviderSelfSigned:
+		// the default certificate directory is where cert-manager's certificate is mounted read-only
+		webhookCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "self-signed-certs")
+	default:
+		setupLog.Error(fmt.Errorf("expected %s or %s, got %q", webhookCertProviderExternal, webhookCertProviderSelfSigned, webhookCertProvider), "invalid webhook certificate provider")
+		os.Exit(1)
+	}
+
+	if webhookOnly && !enableWebhooks {
+		setupLog.Error(fmt.Errorf("--webhook-only requires --enable-webhooks"), "invalid webhook-only mode")
+		os.Exit(1)
+	}
+	if webhookOnly && leaderElect {
+		// the webhook-only replicas never reconcile, they have no leadership to take
+		setupLog.Info("ignoring --leader-elect in the webhook-only mode")
+		leaderElect = false
 	}
 
 	mgrOptions := ctrl.Options{
@@ -235,14 +538,26 @@ func main() {
 		PprofBindAddress:       pprofAddr,
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port:    webhookPort,
+			CertDir: webhookCertDir,
 			TLSOpts: optionsTlSOptsFuncs,
 		}),
 		Cache: cache.Options{
 			DefaultNamespaces: namespaces,
+			ByObject:          byObject,
+		},
+		LeaderElection:   leaderElect,
+		LeaderElectionID: leaderElectionID,
+		LeaseDuration:    &leaseDuration,
+		RenewDeadline:    &renewDeadline,
+		RetryPeriod:      &retryPeriod,
+		// the replica stopping hands the leadership over right away rather than after the lease duration
+		LeaderElectionReleaseOnCancel: true,
+		Controller: ctrlconfig.Controller{
+			MaxConcurrentReconciles: maxConcurrentReconciles,
 		},
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
+	mgr, err := ctrl.NewManager(restConfig, mgrOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -250,42 +565,161 @@ func main() {
 
 	ctx := ctrl.SetupSignalHandler()
 
-	if err = controllers.NewReconciler(controllers.Params{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("AmazonCloudWatchAgent"),
-		Scheme:   mgr.GetScheme(),
-		Config:   cfg,
-		Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "AmazonCloudWatchAgent")
-		os.Exit(1)
-	}
+	if webhookOnly {
+		setupLog.Info("serving the webhooks only, the objects are reconciled by the other operator replicas")
+	} else {
+		var alarmsReconciler *alarms.Reconciler
+		if enableAlarms {
+			// the AWS session is only created once an agent defines alarms
+			alarmsReconciler = alarms.NewLazyReconciler(func() (cloudwatchiface.CloudWatchAPI, error) {
+				sess, sessErr := session.NewSession(aws.NewConfig().WithRegion(alarmsRegion))
+				if sessErr != nil {
+					return nil, sessErr
+				}
+				return cloudwatch.New(sess), nil
+			})
+			setupLog.Info("managing the CloudWatch alarms", "region", alarmsRegion)
+		}
 
-	if err = controllers.NewDcgmExporterReconciler(controllers.Params{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("DcgmExporter"),
-		Scheme:   mgr.GetScheme(),
-		Config:   cfg,
-		Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "DcgmExporter")
-		os.Exit(1)
-	}
+		var digestResolver *imagedigest.Resolver
+		if requireImageDigest {
+			// the private ECR registries are authenticated with the credentials of the operator
+			digestResolver = imagedigest.NewResolver(&http.Client{Timeout: 10 * time.Second}, imagedigest.ECRKeychain(func(region string) (ecriface.ECRAPI, error) {
+				sess, sessErr := session.NewSession(aws.NewConfig().WithRegion(region))
+				if sessErr != nil {
+					return nil, sessErr
+				}
+				return ecr.New(sess), nil
+			}))
+		}
 
-	if err = controllers.NewNeuronMonitorReconciler(controllers.Params{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("NeuronMonitor"),
-		Scheme:   mgr.GetScheme(),
-		Config:   cfg,
-		Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "NeuronMonitor")
-		os.Exit(1)
+		if err = controllers.NewReconciler(controllers.Params{
+			Client:        mgr.GetClient(),
+			Log:           ctrl.Log.WithName("controllers").WithName("AmazonCloudWatchAgent"),
+			Scheme:        mgr.GetScheme(),
+			Config:        cfg,
+			Recorder:      mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+			Alarms:        alarmsReconciler,
+			ImageDigests:  digestResolver,
+			Reader:        mgr.GetAPIReader(),
+			DisabledTasks: disabledTasks,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AmazonCloudWatchAgent")
+			os.Exit(1)
+		}
+
+		if err = controllers.NewCABundleReconciler(controllers.Params{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("CABundle"),
+			Scheme: mgr.GetScheme(),
+			Config: cfg,
+		}, mgr.GetAPIReader()).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CABundle")
+			os.Exit(1)
+		}
+
+		if err = controllers.NewDcgmExporterReconciler(controllers.Params{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("DcgmExporter"),
+			Scheme:   mgr.GetScheme(),
+			Config:   cfg,
+			Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DcgmExporter")
+			os.Exit(1)
+		}
+
+		if err = controllers.NewNeuronMonitorReconciler(controllers.Params{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("NeuronMonitor"),
+			Scheme:   mgr.GetScheme(),
+			Config:   cfg,
+			Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NeuronMonitor")
+			os.Exit(1)
+		}
+
+		if featuregate.PrometheusOperatorIsAvailable.IsEnabled() {
+			if err = controllers.NewInstrumentationReconciler(controllers.Params{
+				Client: mgr.GetClient(),
+				Log:    ctrl.Log.WithName("controllers").WithName("Instrumentation"),
+				Scheme: mgr.GetScheme(),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Instrumentation")
+				os.Exit(1)
+			}
+		}
+
+		if legacyAgentKind != "" {
+			legacyGVK, _ := schema.ParseKindArg(legacyAgentKind)
+			if legacyGVK == nil {
+				setupLog.Error(fmt.Errorf("expected Kind.version.group, got %q", legacyAgentKind), "invalid legacy agent kind")
+				os.Exit(1)
+			}
+			setupLog.Info("Mirroring legacy objects into AmazonCloudWatchAgent", "kind", legacyGVK.String())
+			if err = controllers.NewLegacyAgentReconciler(controllers.Params{
+				Client:   mgr.GetClient(),
+				Log:      ctrl.Log.WithName("controllers").WithName("LegacyAmazonCloudWatchAgent"),
+				Scheme:   mgr.GetScheme(),
+				Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+			}, *legacyGVK).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "LegacyAmazonCloudWatchAgent")
+				os.Exit(1)
+			}
+		}
+
+		if translateOtelCollectors {
+			setupLog.Info("Translating OpenTelemetryCollector objects into AmazonCloudWatchAgent", "kind", controllers.OpenTelemetryCollectorGVK.String())
+			if err = controllers.NewOpenTelemetryCollectorReconciler(controllers.Params{
+				Client:   mgr.GetClient(),
+				Log:      ctrl.Log.WithName("controllers").WithName("OpenTelemetryCollector"),
+				Scheme:   mgr.GetScheme(),
+				Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "OpenTelemetryCollector")
+				os.Exit(1)
+			}
+		}
+
+		if enableTenants {
+			setupLog.Info("Stamping out the agents of the tenant namespaces")
+			if err = controllers.NewTenantReconciler(controllers.Params{
+				Client:   mgr.GetClient(),
+				Log:      ctrl.Log.WithName("controllers").WithName("Tenant"),
+				Scheme:   mgr.GetScheme(),
+				Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Tenant")
+				os.Exit(1)
+			}
+		}
+
+		if nodeTerminationFlush {
+			setupLog.Info("Flushing the agents of the nodes being terminated")
+			if err = controllers.NewNodeTerminationReconciler(controllers.Params{
+				Client:   mgr.GetClient(),
+				Log:      ctrl.Log.WithName("controllers").WithName("NodeTermination"),
+				Scheme:   mgr.GetScheme(),
+				Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
+			}, mgr.GetAPIReader()).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "NodeTermination")
+				os.Exit(1)
+			}
+		}
+
+		if bootstrapStack {
+			setupLog.Info("Bootstrapping the default Application Signals stack", "namespace", bootstrapNamespace)
+			if err = mgr.Add(bootstrap.New(mgr.GetClient(), mgr.GetAPIReader(), ctrl.Log.WithName("bootstrap"), bootstrapNamespace, bootstrapServiceAccount, clusterName, region)); err != nil {
+				setupLog.Error(err, "unable to set up the bootstrap")
+				os.Exit(1)
+			}
+		}
 	}
 
 	decoder := admission.NewDecoder(mgr.GetScheme())
 
-	if os.Getenv("DISABLE_AUTO_ANNOTATION") == "true" || autoAnnotationConfigStr == "" {
+	if !enableWebhooks || os.Getenv("DISABLE_AUTO_ANNOTATION") == "true" || autoAnnotationConfigStr == "" {
 		setupLog.Info("Auto-annotation is disabled")
 	} else {
 		var autoAnnotationConfig auto.AnnotationConfig
@@ -310,18 +744,69 @@ func main() {
 				Handler: namespacemutation.NewWebhookHandler(decoder, autoAnnotationMutators),
 			})
 			setupLog.Info("Auto-annotation is enabled")
-			go waitForWebhookServerStart(
-				ctx,
-				mgr.GetWebhookServer().StartedChecker(),
-				func(ctx context.Context) {
-					setupLog.Info("Applying auto-annotation")
-					autoAnnotationMutators.MutateAndPatchAll(ctx)
-				},
-			)
+			// the existing workloads are annotated by the replica reconciling the objects
+			if !webhookOnly {
+				go waitForWebhookServerStart(
+					ctx,
+					mgr.GetWebhookServer().StartedChecker(),
+					func(ctx context.Context) {
+						select {
+						case <-mgr.Elected():
+						case <-ctx.Done():
+							return
+						}
+						setupLog.Info("Applying auto-annotation")
+						autoAnnotationMutators.MutateAndPatchAll(ctx)
+					},
+				)
+			}
 		}
 	}
 
-	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+	if enableWebhooks {
+		serviceName, err := parseNamespacedName(webhookService)
+		if err != nil {
+			setupLog.Error(err, "invalid webhook service")
+			os.Exit(1)
+		}
+		// the webhook server reads the certificate of cert-manager from its default directory
+		selfTestCertDir := webhookCertDir
+		if selfTestCertDir == "" {
+			selfTestCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+		}
+		webhookSelfTest := selftest.New(mgr.GetClient(), ctrl.Log.WithName("webhook-self-test"), serviceName, selfTestCertDir)
+		if err = mgr.Add(webhookSelfTest); err != nil {
+			setupLog.Error(err, "unable to set up the webhook self-test")
+			os.Exit(1)
+		}
+		if err = mgr.AddReadyzCheck("webhook-self-test", webhookSelfTest.Check); err != nil {
+			setupLog.Error(err, "unable to set up the webhook self-test check")
+			os.Exit(1)
+		}
+		if webhookCertProvider == webhookCertProviderSelfSigned {
+			rotator, err := newCertRotator(mgr, webhookCertDir, webhookCertSecret, serviceName, webhookConfigurations, webhookConversionCRDs, webhookSelfTest.Trigger)
+			if err != nil {
+				setupLog.Error(err, "unable to set up the webhook certificate rotation")
+				os.Exit(1)
+			}
+			// the webhook server can't start without a certificate
+			if err = rotator.Load(ctx); err != nil {
+				setupLog.Error(err, "unable to load the webhook certificate")
+				os.Exit(1)
+			}
+			// the leader renews the certificates, which every replica reloads, the webhook-only replicas aren't part of
+			// the election
+			if !webhookOnly {
+				if err = mgr.Add(rotator); err != nil {
+					setupLog.Error(err, "unable to set up the webhook certificate rotation")
+					os.Exit(1)
+				}
+			}
+			if err = mgr.Add(rotator.Reloader()); err != nil {
+				setupLog.Error(err, "unable to set up the webhook certificate reload")
+				os.Exit(1)
+			}
+		}
 		if err = otelv1alpha1.SetupCollectorWebhook(mgr, cfg); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "AmazonCloudWatchAgent")
 			os.Exit(1)
@@ -330,15 +815,63 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Instrumentation")
 			os.Exit(1)
 		}
+		if protectOwnedObjects {
+			// the operator has to keep updating its objects
+			operatorUser, err := ownershipprotection.OperatorUsername(restConfig)
+			switch {
+			case err == nil:
+				ownedObjectsAllowedUsers = append(ownedObjectsAllowedUsers, operatorUser)
+			case !pflag.CommandLine.Changed("owned-objects-allowed-users"):
+				setupLog.Error(err, "unable to determine the user of the operator, add it to --owned-objects-allowed-users")
+				os.Exit(1)
+			}
+		}
+		mgr.GetWebhookServer().Register("/validate-v1-owned-object", &webhook.Admission{
+			Handler: ownershipprotection.NewWebhookHandler(mgr.GetClient(), ctrl.Log.WithName("ownership-protection"), protectOwnedObjects, ownedObjectsAllowedUsers),
+		})
+		// the injection statistics are written by the leader, the webhook-only replicas aren't part of the election
+		var injectionStats *injectionstats.Stats
+		if !webhookOnly {
+			injectionStats = injectionstats.New(mgr.GetClient(), ctrl.Log.WithName("injection-stats"))
+			if err = mgr.Add(injectionStats); err != nil {
+				setupLog.Error(err, "unable to set up the injection statistics")
+				os.Exit(1)
+			}
+		}
 		mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
-			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, mgr.GetClient(),
+			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, mgr.GetClient(), mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"),
 				[]podmutation.PodMutator{
 					sidecar.NewMutator(logger, cfg, mgr.GetClient()),
-					instrumentation.NewMutator(logger, mgr.GetClient(), mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator")),
+					instrumentation.NewMutator(logger, cfg, mgr.GetClient(), mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator")).
+						WithInjectionStats(injectionStats),
 				}),
 		})
+		// the webhook configurations are kept up to date by the replica reconciling the objects
+		if podWebhookConfiguration != "" && !webhookOnly {
+			failurePolicy := admissionregistrationv1.FailurePolicyType(podWebhookFailurePolicy)
+			if failurePolicy != admissionregistrationv1.Ignore && failurePolicy != admissionregistrationv1.Fail {
+				setupLog.Error(fmt.Errorf("expected Ignore or Fail, got %q", podWebhookFailurePolicy), "invalid pod webhook failure policy")
+				os.Exit(1)
+			}
+			namespaceSelector, err := parseLabelSelector(podWebhookNamespaceSelector)
+			if err != nil {
+				setupLog.Error(err, "invalid pod webhook namespace selector")
+				os.Exit(1)
+			}
+			objectSelector, err := parseLabelSelector(podWebhookObjectSelector)
+			if err != nil {
+				setupLog.Error(err, "invalid pod webhook object selector")
+				os.Exit(1)
+			}
+			syncer := webhookconfig.NewSyncer(mgr.GetClient(), ctrl.Log.WithName("pod-webhook-config"), podWebhookConfiguration, criticalNamespaces, failurePolicy).
+				WithSelectors(namespaceSelector, objectSelector)
+			if err = mgr.Add(syncer); err != nil {
+				setupLog.Error(err, "unable to set up the pod webhook configuration sync")
+				os.Exit(1)
+			}
+		}
 	} else {
-		ctrl.Log.Info("Webhooks are disabled, operator is running an unsupported mode", "ENABLE_WEBHOOKS", "false")
+		ctrl.Log.Info("Webhooks are disabled, operator is running an unsupported mode", "enable-webhooks", false)
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -358,6 +891,86 @@ func main() {
 	}
 }
 
+// newCertRotator creates the rotator of the self-signed webhook certificate. It uses a client which doesn't depend
+// on the manager cache, so that the certificate is issued before the manager starts.
+func newCertRotator(mgr ctrl.Manager, certDir, secret string, service types.NamespacedName, webhookConfigurations, crds []string, onRotation func()) (*certrotation.Rotator, error) {
+	secretName, err := parseNamespacedName(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook certificate secret: %w", err)
+	}
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return nil, err
+	}
+	return certrotation.NewRotator(c, ctrl.Log.WithName("webhook-cert-rotation"), certrotation.Options{
+		CertDir:               certDir,
+		Secret:                secretName,
+		Service:               service,
+		WebhookConfigurations: webhookConfigurations,
+		CRDs:                  crds,
+		OnRotation:            onRotation,
+	}), nil
+}
+
+// discoverClusterInfoOf fills the cluster name and the region left unknown by the flags from the cluster info
+// ConfigMap, the labels of the nodes and the instance metadata. It gives up on the sources still unanswered after a
+// few seconds, so that the operator starts timely outside of EC2.
+func discoverClusterInfoOf(restConfig *rest.Config, configMap string, known clusterinfo.Info) (clusterinfo.Info, error) {
+	configMapName, err := parseNamespacedName(configMap)
+	if err != nil {
+		return known, err
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return known, err
+	}
+	sess, err := session.NewSession(aws.NewConfig().WithMaxRetries(0).WithHTTPClient(&http.Client{Timeout: 2 * time.Second}))
+	if err != nil {
+		return known, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return clusterinfo.Discover(ctx, ctrl.Log.WithName("cluster-info"), known,
+		clusterinfo.ConfigMap(c, configMapName),
+		clusterinfo.Nodes(c),
+		clusterinfo.InstanceMetadata(ec2metadata.New(sess)),
+	), nil
+}
+
+// newCompatibilityChecker returns the checker of the versions against the support matrix of the given file, or the
+// embedded one, for the given version of the operator and the version of the Kubernetes API server, which is read
+// again every serverVersionPeriod to follow the upgrades of the cluster.
+func newCompatibilityChecker(serverVersion func() string, matrixFile, operator string, enforce bool) (*compatibility.Checker, error) {
+	matrix, err := compatibility.Default()
+	if matrixFile != "" {
+		matrix, err = compatibility.Load(matrixFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if serverVersion() == "" {
+		return nil, fmt.Errorf("failed to get the version of the Kubernetes API server")
+	}
+	return &compatibility.Checker{Enforce: enforce, Matrix: matrix, Operator: operator, KubernetesVersion: serverVersion}, nil
+}
+
+func parseNamespacedName(s string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(s, "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("expected namespace/name, got %q", s)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// parseLabelSelector parses a label selector, returning nil for an empty one.
+func parseLabelSelector(s string) (*metav1.LabelSelector, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return metav1.ParseToLabelSelector(s)
+}
+
 func waitForWebhookServerStart(ctx context.Context, checker healthz.Checker, callback func(context.Context)) {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
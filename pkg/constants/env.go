@@ -20,9 +20,39 @@ const (
 	AnnotationDefaultAutoInstrumentationApacheHttpd = InstrumentationPrefix + "default-auto-instrumentation-apache-httpd-image"
 	AnnotationDefaultAutoInstrumentationNginx       = InstrumentationPrefix + "default-auto-instrumentation-nginx-image"
 
+	AnnotationDefaultsApplied = "cloudwatch.aws.amazon.com/defaults-applied"
+	LabelMirroredFrom         = "cloudwatch.aws.amazon.com/mirrored-from"
+	LabelCohort               = "cloudwatch.aws.amazon.com/cohort"
+	LabelNodeGroup            = "cloudwatch.aws.amazon.com/node-group"
+	LabelConfigRevision       = "cloudwatch.aws.amazon.com/config-revision"
+	LabelConfigHash           = "cloudwatch.aws.amazon.com/config-hash"
+	LabelPipeline             = "cloudwatch.aws.amazon.com/pipeline"
+	AnnotationRestart         = "cloudwatch.aws.amazon.com/restart"
+	AnnotationRestartedAt     = "cloudwatch.aws.amazon.com/restartedAt"
+	AnnotationSharedWith      = "cloudwatch.aws.amazon.com/shared-with"
+	AnnotationIAMRoleArn      = "eks.amazonaws.com/role-arn"
+	// LabelTenant marks the namespaces getting an AmazonCloudWatchAgent stamped out of a template.
+	LabelTenant = "cloudwatch.aws.amazon.com/tenant"
+	// AnnotationTenantTemplate selects the AmazonCloudWatchAgentTemplate of a tenant namespace.
+	AnnotationTenantTemplate = "cloudwatch.aws.amazon.com/tenant-template"
+	// LabelTenantTemplate holds the AmazonCloudWatchAgentTemplate an agent was stamped out of.
+	LabelTenantTemplate = "cloudwatch.aws.amazon.com/tenant-template"
+	// LabelJavaMetrics holds the UID of the Instrumentation whose PodMonitor scrapes the Java metrics of the pod.
+	LabelJavaMetrics = "cloudwatch.aws.amazon.com/java-metrics"
+	// AnnotationInjectionAudit makes the pod webhook only log and record as Events the mutations of the pods of
+	// the namespace it annotates, rather than applying them.
+	AnnotationInjectionAudit = "cloudwatch.aws.amazon.com/injection-audit"
+	// JavaMetricsPortName is the container port the javaagent exposes the Java metrics on.
+	JavaMetricsPortName = "java-metrics"
+
 	EnvPodName  = "OTEL_RESOURCE_ATTRIBUTES_POD_NAME"
 	EnvPodUID   = "OTEL_RESOURCE_ATTRIBUTES_POD_UID"
 	EnvNodeName = "OTEL_RESOURCE_ATTRIBUTES_NODE_NAME"
+	// EnvHostIP holds the IP of the node of the pod, for the exporter endpoints of the agents running on the node.
+	EnvHostIP = "HOST_IP"
+	// EnvResourceAttrsValueFrom holds the OTEL_RESOURCE_ATTRIBUTES a container reads from a source, such as a Secret,
+	// which the injected resource attributes are appended to.
+	EnvResourceAttrsValueFrom = "OTEL_RESOURCE_ATTRIBUTES_VALUE_FROM"
 
 	AWSEntityPrefix       = "com.amazonaws.cloudwatch.entity.internal."
 	ServiceNameSource     = AWSEntityPrefix + "service.name.source"
@@ -80,6 +80,15 @@ var (
 		featuregate.WithRegisterFromVersion("v0.82.0"),
 	)
 
+	// ServerSideApply is the feature gate that controls whether the operator applies the objects of the agents through
+	// server-side apply, owning only the fields it sets, instead of updating them. The fields set by other controllers,
+	// such as the sidecars injected by service meshes, are then left untouched.
+	ServerSideApply = featuregate.GlobalRegistry().MustRegister(
+		"operator.serversideapply",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("controls whether the operator applies the objects of the agents through server-side apply"),
+	)
+
 	// SkipMultiInstrumentationContainerValidation is the feature gate that controls whether the operator will skip
 	// container name validation during pod mutation for multi-instrumentation. Enabling this feature allows multiple
 	// instrumentations for pods without specified container name annotations. Does not prevent specification
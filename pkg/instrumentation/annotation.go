@@ -17,6 +17,7 @@ const (
 	annotationInjectJavaContainersName        = "instrumentation.opentelemetry.io/java-container-names"
 	annotationInjectNodeJS                    = "instrumentation.opentelemetry.io/inject-nodejs"
 	annotationInjectNodeJSContainersName      = "instrumentation.opentelemetry.io/nodejs-container-names"
+	annotationNodeJSRuntime                   = "instrumentation.opentelemetry.io/otel-nodejs-auto-runtime"
 	annotationInjectPython                    = "instrumentation.opentelemetry.io/inject-python"
 	annotationInjectPythonContainersName      = "instrumentation.opentelemetry.io/python-container-names"
 	annotationInjectDotNet                    = "instrumentation.opentelemetry.io/inject-dotnet"
@@ -0,0 +1,137 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	envOTELBSPScheduleDelay     = "OTEL_BSP_SCHEDULE_DELAY"
+	envOTELBLRPScheduleDelay    = "OTEL_BLRP_SCHEDULE_DELAY"
+	envOTELMetricExportInterval = "OTEL_METRIC_EXPORT_INTERVAL"
+	envOTELBSPExportTimeout     = "OTEL_BSP_EXPORT_TIMEOUT"
+	envOTELBLRPExportTimeout    = "OTEL_BLRP_EXPORT_TIMEOUT"
+	envOTELMetricExportTimeout  = "OTEL_METRIC_EXPORT_TIMEOUT"
+
+	defaultBatchExportInterval = time.Second
+	defaultBatchShutdownDelay  = 5 * time.Second
+)
+
+// jobOwner returns the Job controlling the pod, if any.
+func jobOwner(objectMeta metav1.ObjectMeta) *metav1.OwnerReference {
+	for i, owner := range objectMeta.OwnerReferences {
+		if owner.Kind == "Job" && owner.APIVersion == batchv1.SchemeGroupVersion.String() {
+			return &objectMeta.OwnerReferences[i]
+		}
+	}
+	return nil
+}
+
+// isBatchPod tells whether the pod is created by a Job, on its own or for a CronJob.
+func isBatchPod(pod corev1.Pod) bool {
+	return jobOwner(pod.ObjectMeta) != nil
+}
+
+// batchWorkloadKind returns the kind of batch workload the pod runs for, or an empty kind when it isn't created by a
+// Job. The Job is looked up for the CronJob creating it, and is taken as a Job of its own when it can't be found.
+func batchWorkloadKind(ctx context.Context, c client.Client, ns corev1.Namespace, pod corev1.Pod) v1alpha1.BatchWorkloadKind {
+	owner := jobOwner(pod.ObjectMeta)
+	if owner == nil {
+		return ""
+	}
+	job := batchv1.Job{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: owner.Name}, &job); err != nil {
+		return v1alpha1.BatchWorkloadJob
+	}
+	for _, jobOwner := range job.OwnerReferences {
+		if jobOwner.Kind == "CronJob" && jobOwner.APIVersion == batchv1.SchemeGroupVersion.String() {
+			return v1alpha1.BatchWorkloadCronJob
+		}
+	}
+	return v1alpha1.BatchWorkloadJob
+}
+
+// skipBatchWorkload removes the instrumentations skipping the given kind of batch workload. It tells whether any
+// was removed.
+func (langInsts *languageInstrumentations) skipBatchWorkload(kind v1alpha1.BatchWorkloadKind) bool {
+	skipped := false
+	for _, inst := range []*instrumentationWithContainers{
+		&langInsts.Java, &langInsts.NodeJS, &langInsts.Python, &langInsts.DotNet,
+		&langInsts.ApacheHttpd, &langInsts.Nginx, &langInsts.Go, &langInsts.Sdk,
+	} {
+		if inst.Instrumentation != nil && slices.Contains(inst.Instrumentation.Spec.Batch.SkipKinds, kind) {
+			inst.Instrumentation = nil
+			skipped = true
+		}
+	}
+	return skipped
+}
+
+// sleepHookMinVersion is the first Kubernetes version enabling the sleep action of the lifecycle hooks by default,
+// which the API server rejects before.
+var sleepHookMinVersion = version.MajorMinor(1, 30)
+
+// sleepHookSupported tells whether the API server of the given version accepts the sleep action of the lifecycle
+// hooks. An unknown version is taken as not accepting it.
+func sleepHookSupported(kubernetesVersion string) bool {
+	v, err := version.ParseGeneric(kubernetesVersion)
+	return err == nil && v.AtLeast(sleepHookMinVersion)
+}
+
+// injectBatchConfig makes the SDK of a container of a Job pod export its telemetry often, for little of it to be left
+// when the process exits. The SDK flushes the rest when the process exits, and its export timeouts are set to the
+// shutdown delay for the flush to end within the delay. On the Kubernetes versions supporting the sleep action, a
+// preStop hook also delays the termination of the container for the SDK to export the rest when the job is stopped.
+// The env vars and the preStop hook defined by the container are kept.
+func injectBatchConfig(batch v1alpha1.Batch, container *corev1.Container, kubernetesVersion string) {
+	interval := defaultBatchExportInterval
+	if batch.ExportInterval != nil {
+		interval = batch.ExportInterval.Duration
+	}
+	setDefaultEnvVars(container, interval, envOTELBSPScheduleDelay, envOTELBLRPScheduleDelay, envOTELMetricExportInterval)
+
+	delay := defaultBatchShutdownDelay
+	if batch.ShutdownDelay != nil {
+		delay = batch.ShutdownDelay.Duration
+	}
+	seconds := int64(delay.Round(time.Second) / time.Second)
+	if seconds <= 0 {
+		return
+	}
+	setDefaultEnvVars(container, delay, envOTELBSPExportTimeout, envOTELBLRPExportTimeout, envOTELMetricExportTimeout)
+	if !sleepHookSupported(kubernetesVersion) || container.Lifecycle != nil && container.Lifecycle.PreStop != nil {
+		return
+	}
+	if container.Lifecycle == nil {
+		container.Lifecycle = &corev1.Lifecycle{}
+	}
+	container.Lifecycle.PreStop = &corev1.LifecycleHandler{
+		Sleep: &corev1.SleepAction{Seconds: seconds},
+	}
+}
+
+// setDefaultEnvVars sets the given env vars the container doesn't define to the duration in milliseconds.
+func setDefaultEnvVars(container *corev1.Container, duration time.Duration, names ...string) {
+	for _, name := range names {
+		if getIndexOfEnv(container.Env, name) == -1 {
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  name,
+				Value: strconv.FormatInt(duration.Milliseconds(), 10),
+			})
+		}
+	}
+}
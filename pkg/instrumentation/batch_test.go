@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestBatchWorkloadKind(t *testing.T) {
+	ctx := context.Background()
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	pod := func(owner string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "Job", Name: owner}},
+		}}
+	}
+	cronJobJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		Name:            "report-28500000",
+		Namespace:       "default",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "CronJob", Name: "report"}},
+	}}
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithObjects(cronJobJob, job).Build()
+
+	assert.Equal(t, v1alpha1.BatchWorkloadCronJob, batchWorkloadKind(ctx, c, ns, pod("report-28500000")))
+	assert.Equal(t, v1alpha1.BatchWorkloadJob, batchWorkloadKind(ctx, c, ns, pod("migration")))
+	// a Job which can't be found is taken as a Job of its own
+	assert.Equal(t, v1alpha1.BatchWorkloadJob, batchWorkloadKind(ctx, c, ns, pod("unknown")))
+	assert.Empty(t, batchWorkloadKind(ctx, c, ns, corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "app"}},
+	}}))
+}
+
+func TestSkipBatchWorkload(t *testing.T) {
+	skipCronJobs := &v1alpha1.Instrumentation{Spec: v1alpha1.InstrumentationSpec{
+		Batch: v1alpha1.Batch{SkipKinds: []v1alpha1.BatchWorkloadKind{v1alpha1.BatchWorkloadCronJob}},
+	}}
+	insts := languageInstrumentations{
+		Java:   instrumentationWithContainers{Instrumentation: skipCronJobs},
+		Python: instrumentationWithContainers{Instrumentation: &v1alpha1.Instrumentation{}},
+	}
+
+	assert.False(t, insts.skipBatchWorkload(v1alpha1.BatchWorkloadJob))
+	assert.NotNil(t, insts.Java.Instrumentation)
+	assert.True(t, insts.skipBatchWorkload(v1alpha1.BatchWorkloadCronJob))
+	assert.Nil(t, insts.Java.Instrumentation)
+	assert.NotNil(t, insts.Python.Instrumentation)
+}
+
+func TestInjectBatchConfig(t *testing.T) {
+	container := corev1.Container{Env: []corev1.EnvVar{{Name: "OTEL_METRIC_EXPORT_INTERVAL", Value: "30000"}}}
+	injectBatchConfig(v1alpha1.Batch{}, &container, "v1.30.2-eks-1552ad0")
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "OTEL_METRIC_EXPORT_INTERVAL", Value: "30000"},
+		{Name: "OTEL_BSP_SCHEDULE_DELAY", Value: "1000"},
+		{Name: "OTEL_BLRP_SCHEDULE_DELAY", Value: "1000"},
+		{Name: "OTEL_BSP_EXPORT_TIMEOUT", Value: "5000"},
+		{Name: "OTEL_BLRP_EXPORT_TIMEOUT", Value: "5000"},
+		{Name: "OTEL_METRIC_EXPORT_TIMEOUT", Value: "5000"},
+	}, container.Env)
+	assert.Equal(t, &corev1.SleepAction{Seconds: 5}, container.Lifecycle.PreStop.Sleep)
+
+	// the preStop hook of the container is kept
+	preStop := &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"flush"}}}
+	container = corev1.Container{Lifecycle: &corev1.Lifecycle{PreStop: preStop}}
+	injectBatchConfig(v1alpha1.Batch{ExportInterval: &metav1.Duration{Duration: 500 * time.Millisecond}}, &container, "v1.31.0")
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "OTEL_BSP_SCHEDULE_DELAY", Value: "500"})
+	assert.Equal(t, preStop, container.Lifecycle.PreStop)
+
+	// the API servers before 1.30 reject the sleep action, the SDK only flushes when the process exits
+	for _, kubernetesVersion := range []string{"v1.29.4", ""} {
+		container = corev1.Container{}
+		injectBatchConfig(v1alpha1.Batch{ShutdownDelay: &metav1.Duration{Duration: 10 * time.Second}}, &container, kubernetesVersion)
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "OTEL_BSP_EXPORT_TIMEOUT", Value: "10000"})
+		assert.Nil(t, container.Lifecycle)
+	}
+
+	// a delay of 0 disables the hook and keeps the export timeouts of the SDK
+	container = corev1.Container{}
+	injectBatchConfig(v1alpha1.Batch{ShutdownDelay: &metav1.Duration{}}, &container, "v1.30.0")
+	assert.Nil(t, container.Lifecycle)
+	assert.NotContains(t, container.Env, corev1.EnvVar{Name: "OTEL_BSP_EXPORT_TIMEOUT", Value: "0"})
+	assert.Len(t, container.Env, 3)
+}
@@ -51,6 +51,36 @@ func getInstrumentationConfigForResource(langStr string, resourceStr string) cor
 }
 
 func getDefaultInstrumentation(agentConfig *adapters.CwaConfig, additionalEnvs map[Type]map[string]string, isWindowsPod bool) (*v1alpha1.Instrumentation, error) {
+	cloudwatchAgentServiceEndpoint := "cloudwatch-agent.amazon-cloudwatch"
+	if isWindowsPod {
+		// Windows pods use the headless service endpoint due to limitations with the agent on host network mode
+		// https://kubernetes.io/docs/concepts/services-networking/windows-networking/#limitations
+		cloudwatchAgentServiceEndpoint = "cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local"
+	}
+	return defaultInstrumentationFor(agentConfig, additionalEnvs, cloudwatchAgentServiceEndpoint)
+}
+
+// NewBootstrapInstrumentation returns the default Instrumentation with Application Signals enabled, exporting to the
+// given Service of the agent of the given namespace, which the operator creates when it bootstraps the cluster.
+func NewBootstrapInstrumentation(namespace, name, agentService string) (*v1alpha1.Instrumentation, error) {
+	applicationSignalsConfig, err := adapters.ConfigWithApplicationSignals("")
+	if err != nil {
+		return nil, err
+	}
+	agentConfig, err := adapters.ConfigStructFromJSONString(applicationSignalsConfig)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := defaultInstrumentationFor(agentConfig, nil, agentService+"."+namespace)
+	if err != nil {
+		return nil, err
+	}
+	inst.Name = name
+	inst.Namespace = namespace
+	return inst, nil
+}
+
+func defaultInstrumentationFor(agentConfig *adapters.CwaConfig, additionalEnvs map[Type]map[string]string, cloudwatchAgentServiceEndpoint string) (*v1alpha1.Instrumentation, error) {
 	javaInstrumentationImage, ok := os.LookupEnv("AUTO_INSTRUMENTATION_JAVA")
 	if !ok {
 		return nil, errors.New("unable to determine java instrumentation image")
@@ -68,13 +98,6 @@ func getDefaultInstrumentation(agentConfig *adapters.CwaConfig, additionalEnvs m
 		return nil, errors.New("unable to determine nodejs instrumentation image")
 	}
 
-	cloudwatchAgentServiceEndpoint := "cloudwatch-agent.amazon-cloudwatch"
-	if isWindowsPod {
-		// Windows pods use the headless service endpoint due to limitations with the agent on host network mode
-		// https://kubernetes.io/docs/concepts/services-networking/windows-networking/#limitations
-		cloudwatchAgentServiceEndpoint = "cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local"
-	}
-
 	// set protocol by checking cloudwatch agent config for tls setting
 	exporterPrefix := http
 	isApplicationSignalsEnabled := agentConfig != nil && agentConfig.GetApplicationSignalsMetricsConfig() != nil
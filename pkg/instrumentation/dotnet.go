@@ -24,6 +24,7 @@ const (
 	dotNetCoreClrProfilerID             = "{918728DD-259F-4A6A-AC2B-B85E1B658318}"
 	dotNetCoreClrProfilerGlibcPath      = "/otel-auto-instrumentation-dotnet/linux-x64/OpenTelemetry.AutoInstrumentation.Native.so"
 	dotNetCoreClrProfilerMuslPath       = "/otel-auto-instrumentation-dotnet/linux-musl-x64/OpenTelemetry.AutoInstrumentation.Native.so"
+	dotNetCoreClrProfilerArm64Path      = "/otel-auto-instrumentation-dotnet/linux-arm64/OpenTelemetry.AutoInstrumentation.Native.so"
 	dotNetAdditionalDepsPath            = "/otel-auto-instrumentation-dotnet/AdditionalDeps"
 	dotNetOTelAutoHomePath              = "/otel-auto-instrumentation-dotnet"
 	dotNetSharedStorePath               = "/otel-auto-instrumentation-dotnet/store"
@@ -43,10 +44,11 @@ const (
 	dotnetInstrMountPathWindows      = "\\otel-auto-instrumentation-dotnet"
 )
 
-// Supported .NET runtime identifiers (https://learn.microsoft.com/en-us/dotnet/core/rid-catalog), can be set by instrumentation.opentelemetry.io/inject-dotnet.
+// Supported .NET runtime identifiers (https://learn.microsoft.com/en-us/dotnet/core/rid-catalog), can be set by instrumentation.opentelemetry.io/otel-dotnet-auto-runtime.
 const (
-	dotNetRuntimeLinuxGlibc = "linux-x64"
-	dotNetRuntimeLinuxMusl  = "linux-musl-x64"
+	dotNetRuntimeLinuxGlibc = runtimeLinuxX64
+	dotNetRuntimeLinuxMusl  = runtimeLinuxMuslX64
+	dotNetRuntimeLinuxArm64 = runtimeLinuxArm64
 )
 
 var (
@@ -76,12 +78,15 @@ func injectDotNetSDK(dotNetSpec v1alpha1.DotNet, pod corev1.Pod, index int, runt
 		return pod, errors.New("OTEL_DOTNET_AUTO_HOME environment variable is already set in the .NET instrumentation spec")
 	}
 
+	runtime = autoInstrumentationRuntime(runtime, dotNetSpec.Runtime, pod)
 	coreClrProfilerPath := ""
 	switch runtime {
-	case "", dotNetRuntimeLinuxGlibc:
+	case dotNetRuntimeLinuxGlibc:
 		coreClrProfilerPath = dotNetCoreClrProfilerGlibcPath
 	case dotNetRuntimeLinuxMusl:
 		coreClrProfilerPath = dotNetCoreClrProfilerMuslPath
+	case dotNetRuntimeLinuxArm64:
+		coreClrProfilerPath = dotNetCoreClrProfilerArm64Path
 	default:
 		return pod, fmt.Errorf("provided instrumentation.opentelemetry.io/dotnet-runtime annotation value '%s' is not supported", runtime)
 	}
@@ -137,7 +142,7 @@ func injectDotNetSDK(dotNetSpec v1alpha1.DotNet, pod corev1.Pod, index int, runt
 
 		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
 			Name:      dotnetInitContainerName,
-			Image:     dotNetSpec.Image,
+			Image:     runtimeImage(dotNetSpec.Image, dotNetSpec.RuntimeImages, runtime),
 			Command:   command,
 			Resources: dotNetSpec.Resources,
 			VolumeMounts: []corev1.VolumeMount{{
@@ -4,9 +4,15 @@
 package instrumentation
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/strings/slices"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
 const (
@@ -16,6 +22,12 @@ const (
 	javaVolumeName            = volumeName + "-java"
 	javaInstrMountPath        = "/otel-auto-instrumentation-java"
 	javaInstrMountPathWindows = "\\otel-auto-instrumentation-java"
+
+	// javaExtensionsProperty is the system property listing the extensions loaded by the javaagent.
+	javaExtensionsProperty = "-Dotel.javaagent.extensions"
+
+	// defaultJavaMetricsPort is the default port of the Prometheus exporter of the javaagent.
+	defaultJavaMetricsPort = 9464
 )
 
 var (
@@ -32,6 +44,21 @@ func injectJavaagent(javaSpec v1alpha1.Java, pod corev1.Pod, index int) (corev1.
 		return pod, err
 	}
 
+	// the arguments are checked before the container is changed, a conflict leaving it as is.
+	options := javaJVMArgument
+	idx := getIndexOfEnv(container.Env, envJavaToolsOptions)
+	if idx != -1 {
+		options = container.Env[idx].Value + javaJVMArgument
+	}
+	options, err = appendJVMArgs(options, javaArgs(javaSpec))
+	if err != nil {
+		return pod, err
+	}
+
+	if javaSpec.PodMonitor != nil && !hasContainerPort(pod, constants.JavaMetricsPortName) {
+		injectJavaMetrics(*javaSpec.PodMonitor, container)
+	}
+
 	// inject Java instrumentation spec env vars.
 	for _, env := range javaSpec.Env {
 		idx := getIndexOfEnv(container.Env, env.Name)
@@ -40,20 +67,29 @@ func injectJavaagent(javaSpec v1alpha1.Java, pod corev1.Pod, index int) (corev1.
 		}
 	}
 
-	idx := getIndexOfEnv(container.Env, envJavaToolsOptions)
+	idx = getIndexOfEnv(container.Env, envJavaToolsOptions)
 	if idx == -1 {
 		container.Env = append(container.Env, corev1.EnvVar{
 			Name:  envJavaToolsOptions,
-			Value: javaJVMArgument,
+			Value: options,
 		})
 	} else {
-		container.Env[idx].Value = container.Env[idx].Value + javaJVMArgument
+		container.Env[idx].Value = options
 	}
 
 	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
 		Name:      javaVolumeName,
 		MountPath: javaInstrMountPath,
 	})
+	for i, extension := range javaSpec.Extensions {
+		if extension.ConfigMap != "" {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      javaExtensionName(javaVolumeName, i),
+				MountPath: javaExtensionPath(extension, i),
+				ReadOnly:  true,
+			})
+		}
+	}
 
 	// We just inject Volumes and init containers for the first processed container.
 	if isInitContainerMissing(pod, javaInitContainerName) {
@@ -80,6 +116,136 @@ func injectJavaagent(javaSpec v1alpha1.Java, pod corev1.Pod, index int) (corev1.
 				MountPath: javaInstrMountPath,
 			}},
 		})
+		injectJavaExtensions(javaSpec, &pod)
 	}
 	return pod, err
 }
+
+// injectJavaExtensions adds the init containers copying the extensions found in images next to the javaagent, and
+// the volumes of the extensions found in config maps.
+func injectJavaExtensions(javaSpec v1alpha1.Java, pod *corev1.Pod) {
+	for i, extension := range javaSpec.Extensions {
+		if extension.ConfigMap != "" {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+				Name: javaExtensionName(javaVolumeName, i),
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: extension.ConfigMap},
+					},
+				}})
+			continue
+		}
+		command := []string{"cp", "-r", extension.Dir, javaExtensionPath(extension, i)}
+		if isWindowsPod(*pod) {
+			command = []string{"CMD", "/c", "xcopy", "/e", "/i", extension.Dir, fmt.Sprintf("%s\\extensions-%d", javaInstrMountPathWindows, i)}
+		}
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+			Name:      javaExtensionName(javaInitContainerName, i),
+			Image:     extension.Image,
+			Command:   command,
+			Resources: javaSpec.Resources,
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      javaVolumeName,
+				MountPath: javaInstrMountPath,
+			}},
+		})
+	}
+}
+
+// javaExtensionName returns the name of the init container or of the volume of the extension at the given index.
+func javaExtensionName(prefix string, index int) string {
+	return fmt.Sprintf("%s-extension-%d", prefix, index)
+}
+
+// javaExtensionPath returns the path of the extension at the given index in the instrumented containers. The ones
+// found in images are copied in the volume of the javaagent, the ones found in config maps are mounted next to it.
+func javaExtensionPath(extension v1alpha1.JavaExtension, index int) string {
+	if extension.ConfigMap != "" {
+		return fmt.Sprintf("%s-extension-%d", javaInstrMountPath, index)
+	}
+	return fmt.Sprintf("%s/extensions-%d", javaInstrMountPath, index)
+}
+
+// javaArgs returns the JVM arguments added after the javaagent: the extensions to load, then the JVMArgs.
+func javaArgs(javaSpec v1alpha1.Java) []string {
+	var args []string
+	if len(javaSpec.Extensions) > 0 {
+		paths := make([]string, len(javaSpec.Extensions))
+		for i, extension := range javaSpec.Extensions {
+			paths[i] = javaExtensionPath(extension, i)
+		}
+		args = append(args, javaExtensionsProperty+"="+strings.Join(paths, ","))
+	}
+	return append(args, javaSpec.JVMArgs...)
+}
+
+// appendJVMArgs appends the arguments to the JAVA_TOOL_OPTIONS, in order. An argument the options already have is
+// skipped, and one setting a system property or a JVM option to another value than the options is a conflict.
+func appendJVMArgs(options string, args []string) (string, error) {
+	for _, arg := range args {
+		fields := strings.Fields(options)
+		if slices.Contains(fields, arg) {
+			continue
+		}
+		if key := jvmArgKey(arg); key != "" {
+			for _, field := range fields {
+				if jvmArgKey(field) == key {
+					return options, fmt.Errorf("the JVM argument %s conflicts with %s of %s", arg, field, envJavaToolsOptions)
+				}
+			}
+		}
+		options = options + " " + arg
+	}
+	return options, nil
+}
+
+// jvmArgKey returns what a JVM argument sets, such as a system property or a JVM option, for two arguments setting
+// it to different values to be detected. It is empty for the arguments which can be repeated, such as -javaagent.
+func jvmArgKey(arg string) string {
+	switch {
+	case strings.HasPrefix(arg, "-D"):
+		return strings.SplitN(arg, "=", 2)[0]
+	case strings.HasPrefix(arg, "-XX:+"), strings.HasPrefix(arg, "-XX:-"):
+		return "-XX:" + arg[len("-XX:+"):]
+	case strings.HasPrefix(arg, "-XX:"):
+		return strings.SplitN(arg, "=", 2)[0]
+	case strings.HasPrefix(arg, "-Xmx"), strings.HasPrefix(arg, "-Xms"), strings.HasPrefix(arg, "-Xss"):
+		return arg[:len("-Xmx")]
+	}
+	return ""
+}
+
+// injectJavaMetrics makes the javaagent of the container expose the JMX and application metrics in the Prometheus
+// format, on a port named for the PodMonitor to scrape. The env vars defined by the container are kept, and take
+// precedence over the ones of the Java instrumentation spec, which disable the metrics by default.
+func injectJavaMetrics(podMonitor v1alpha1.JavaPodMonitor, container *corev1.Container) {
+	port := podMonitor.Port
+	if port == 0 {
+		port = defaultJavaMetricsPort
+	}
+	for _, env := range []corev1.EnvVar{
+		{Name: "OTEL_METRICS_EXPORTER", Value: "prometheus"},
+		{Name: "OTEL_EXPORTER_PROMETHEUS_PORT", Value: strconv.Itoa(int(port))},
+	} {
+		if getIndexOfEnv(container.Env, env.Name) == -1 {
+			container.Env = append(container.Env, env)
+		}
+	}
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		Name:          constants.JavaMetricsPortName,
+		ContainerPort: port,
+		Protocol:      corev1.ProtocolTCP,
+	})
+}
+
+// hasContainerPort tells whether a container of the pod already has a port of the given name.
+func hasContainerPort(pod corev1.Pod, name string) bool {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
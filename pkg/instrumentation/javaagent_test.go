@@ -350,3 +350,116 @@ func TestInjectJavaagentWindows(t *testing.T) {
 		})
 	}
 }
+
+func TestInjectJavaagentMetrics(t *testing.T) {
+	javaSpec := v1alpha1.Java{
+		Image:      "foo/bar:1",
+		Env:        []corev1.EnvVar{{Name: "OTEL_METRICS_EXPORTER", Value: "none"}},
+		PodMonitor: &v1alpha1.JavaPodMonitor{},
+	}
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app"},
+				{Name: "worker", Env: []corev1.EnvVar{{Name: "OTEL_EXPORTER_PROMETHEUS_PORT", Value: "9000"}}},
+			},
+		},
+	}
+
+	pod, err := injectJavaagent(javaSpec, pod, 0)
+	assert.NoError(t, err)
+	pod, err = injectJavaagent(javaSpec, pod, 1)
+	assert.NoError(t, err)
+
+	// only the first container exposes the metrics, as the containers share the port
+	assert.Equal(t, []corev1.ContainerPort{{Name: "java-metrics", ContainerPort: 9464, Protocol: corev1.ProtocolTCP}}, pod.Spec.Containers[0].Ports)
+	assert.Contains(t, pod.Spec.Containers[0].Env, corev1.EnvVar{Name: "OTEL_METRICS_EXPORTER", Value: "prometheus"})
+	assert.Contains(t, pod.Spec.Containers[0].Env, corev1.EnvVar{Name: "OTEL_EXPORTER_PROMETHEUS_PORT", Value: "9464"})
+	assert.NotContains(t, pod.Spec.Containers[0].Env, corev1.EnvVar{Name: "OTEL_METRICS_EXPORTER", Value: "none"})
+	assert.Empty(t, pod.Spec.Containers[1].Ports)
+	assert.Contains(t, pod.Spec.Containers[1].Env, corev1.EnvVar{Name: "OTEL_METRICS_EXPORTER", Value: "none"})
+}
+
+func TestInjectJavaagentExtensions(t *testing.T) {
+	javaSpec := v1alpha1.Java{
+		Image: "foo/bar:1",
+		Extensions: []v1alpha1.JavaExtension{
+			{Image: "extension:1", Dir: "/extensions"},
+			{ConfigMap: "extension"},
+		},
+		JVMArgs: []string{"-Xmx512m", "-Dfoo=bar"},
+	}
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app", Env: []corev1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-Dfoo=bar"}}},
+				{Name: "worker"},
+			},
+		},
+	}
+
+	pod, err := injectJavaagent(javaSpec, pod, 0)
+	assert.NoError(t, err)
+	pod, err = injectJavaagent(javaSpec, pod, 1)
+	assert.NoError(t, err)
+
+	// the arguments the container already sets are not repeated
+	assert.Equal(t, "-Dfoo=bar -javaagent:/otel-auto-instrumentation-java/javaagent.jar"+
+		" -Dotel.javaagent.extensions=/otel-auto-instrumentation-java/extensions-0,/otel-auto-instrumentation-java-extension-1 -Xmx512m",
+		pod.Spec.Containers[0].Env[0].Value)
+	assert.Equal(t, " -javaagent:/otel-auto-instrumentation-java/javaagent.jar"+
+		" -Dotel.javaagent.extensions=/otel-auto-instrumentation-java/extensions-0,/otel-auto-instrumentation-java-extension-1 -Xmx512m -Dfoo=bar",
+		pod.Spec.Containers[1].Env[0].Value)
+	for _, container := range pod.Spec.Containers {
+		assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{
+			Name:      "opentelemetry-auto-instrumentation-java-extension-1",
+			MountPath: "/otel-auto-instrumentation-java-extension-1",
+			ReadOnly:  true,
+		})
+	}
+
+	// the extensions are added once to the pod
+	assert.Len(t, pod.Spec.InitContainers, 2)
+	assert.Equal(t, corev1.Container{
+		Name:    "opentelemetry-auto-instrumentation-java-extension-0",
+		Image:   "extension:1",
+		Command: []string{"cp", "-r", "/extensions", "/otel-auto-instrumentation-java/extensions-0"},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      "opentelemetry-auto-instrumentation-java",
+			MountPath: "/otel-auto-instrumentation-java",
+		}},
+	}, pod.Spec.InitContainers[1])
+	assert.Len(t, pod.Spec.Volumes, 2)
+	assert.Equal(t, "extension", pod.Spec.Volumes[1].ConfigMap.Name)
+}
+
+func TestInjectJavaagentJVMArgsConflict(t *testing.T) {
+	javaSpec := v1alpha1.Java{Image: "foo/bar:1", JVMArgs: []string{"-XX:+UseG1GC", "-Xmx512m"}}
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Env: []corev1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx1g"}}},
+			},
+		},
+	}
+
+	injected, err := injectJavaagent(javaSpec, *pod.DeepCopy(), 0)
+	assert.EqualError(t, err, "the JVM argument -Xmx512m conflicts with -Xmx1g of JAVA_TOOL_OPTIONS")
+	// the container is left as is
+	assert.Equal(t, pod, injected)
+}
+
+func TestJVMArgKey(t *testing.T) {
+	for arg, key := range map[string]string{
+		"-Dfoo=bar":               "-Dfoo",
+		"-Dfoo":                   "-Dfoo",
+		"-XX:+UseG1GC":            "-XX:UseG1GC",
+		"-XX:-UseG1GC":            "-XX:UseG1GC",
+		"-XX:MaxRAMPercentage=75": "-XX:MaxRAMPercentage",
+		"-Xmx512m":                "-Xmx",
+		"-javaagent:/agent.jar":   "",
+		"-verbose:gc":             "",
+	} {
+		assert.Equal(t, key, jvmArgKey(arg), arg)
+	}
+}
@@ -4,6 +4,8 @@
 package instrumentation
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
@@ -17,7 +19,7 @@ const (
 	nodejsInstrMountPath    = "/otel-auto-instrumentation-nodejs"
 )
 
-func injectNodeJSSDK(nodeJSSpec v1alpha1.NodeJS, pod corev1.Pod, index int) (corev1.Pod, error) {
+func injectNodeJSSDK(nodeJSSpec v1alpha1.NodeJS, pod corev1.Pod, index int, runtime string) (corev1.Pod, error) {
 	// caller checks if there is at least one container.
 	container := &pod.Spec.Containers[index]
 
@@ -26,6 +28,13 @@ func injectNodeJSSDK(nodeJSSpec v1alpha1.NodeJS, pod corev1.Pod, index int) (cor
 		return pod, err
 	}
 
+	runtime = autoInstrumentationRuntime(runtime, nodeJSSpec.Runtime, pod)
+	switch runtime {
+	case runtimeLinuxX64, runtimeLinuxMuslX64, runtimeLinuxArm64:
+	default:
+		return pod, fmt.Errorf("provided %s annotation value '%s' is not supported", annotationNodeJSRuntime, runtime)
+	}
+
 	// inject NodeJS instrumentation spec env vars.
 	for _, env := range nodeJSSpec.Env {
 		idx := getIndexOfEnv(container.Env, env.Name)
@@ -61,7 +70,7 @@ func injectNodeJSSDK(nodeJSSpec v1alpha1.NodeJS, pod corev1.Pod, index int) (cor
 
 		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
 			Name:      nodejsInitContainerName,
-			Image:     nodeJSSpec.Image,
+			Image:     runtimeImage(nodeJSSpec.Image, nodeJSSpec.RuntimeImages, runtime),
 			Command:   []string{"cp", "-a", "/autoinstrumentation/.", nodejsInstrMountPath},
 			Resources: nodeJSSpec.Resources,
 			VolumeMounts: []corev1.VolumeMount{{
@@ -18,6 +18,7 @@ func TestInjectNodeJSSDK(t *testing.T) {
 		name string
 		v1alpha1.NodeJS
 		pod      corev1.Pod
+		runtime  string
 		expected corev1.Pod
 		err      error
 	}{
@@ -168,11 +169,86 @@ func TestInjectNodeJSSDK(t *testing.T) {
 			},
 			err: fmt.Errorf("the container defines env var value via ValueFrom, envVar: %s", envNodeOptions),
 		},
+		{
+			name:   "runtime linux-arm64 from node selector",
+			NodeJS: v1alpha1.NodeJS{Image: "foo/bar:1", RuntimeImages: map[string]string{runtimeLinuxArm64: "foo/bar:1-arm64"}},
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{corev1.LabelArchStable: "arm64"},
+					Containers: []corev1.Container{
+						{},
+					},
+				},
+			},
+			expected: corev1.Pod{
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{corev1.LabelArchStable: "arm64"},
+					Volumes: []corev1.Volume{
+						{
+							Name: "opentelemetry-auto-instrumentation-nodejs",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{
+									SizeLimit: &defaultVolumeLimitSize,
+								},
+							},
+						},
+					},
+					InitContainers: []corev1.Container{
+						{
+							Name:    "opentelemetry-auto-instrumentation-nodejs",
+							Image:   "foo/bar:1-arm64",
+							Command: []string{"cp", "-a", "/autoinstrumentation/.", "/otel-auto-instrumentation-nodejs"},
+							VolumeMounts: []corev1.VolumeMount{{
+								Name:      "opentelemetry-auto-instrumentation-nodejs",
+								MountPath: "/otel-auto-instrumentation-nodejs",
+							}},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "opentelemetry-auto-instrumentation-nodejs",
+									MountPath: "/otel-auto-instrumentation-nodejs",
+								},
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "NODE_OPTIONS",
+									Value: " --require /otel-auto-instrumentation-nodejs/autoinstrumentation.js",
+								},
+							},
+						},
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			name:   "runtime not-supported",
+			NodeJS: v1alpha1.NodeJS{Image: "foo/bar:1"},
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{},
+					},
+				},
+			},
+			runtime: "not-supported",
+			expected: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{},
+					},
+				},
+			},
+			err: fmt.Errorf("provided instrumentation.opentelemetry.io/otel-nodejs-auto-runtime annotation value 'not-supported' is not supported"),
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod, err := injectNodeJSSDK(test.NodeJS, test.pod, 0)
+			pod, err := injectNodeJSSDK(test.NodeJS, test.pod, 0, test.runtime)
 			assert.Equal(t, test.expected, pod)
 			assert.Equal(t, test.err, err)
 		})
@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+// annotationNodeLocalExport tells whether the instrumented pods export to the agent of their own node rather than
+// through the Service of the agent. Possible values are "true" and "false".
+const annotationNodeLocalExport = "cloudwatch.aws.amazon.com/node-local-export"
+
+// hostIPEnvVar returns the variable holding the IP of the node of the pod.
+func hostIPEnvVar() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: constants.EnvHostIP,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "status.hostIP",
+			},
+		},
+	}
+}
+
+// nodeLocalExport tells whether the pod exports to the agent of its node, following the annotation of the pod or
+// its namespace, and the operator configuration otherwise.
+func nodeLocalExport(cfg config.Config, ns corev1.Namespace, pod corev1.Pod) bool {
+	value := annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationNodeLocalExport)
+	if value == "" {
+		return cfg.NodeLocalExport()
+	}
+	return strings.EqualFold(value, "true")
+}
+
+// nodeLocalAgents returns the agents the pods of the namespace may export to which bind their ports on the nodes, for
+// the pods to reach them through the IP of their node: the agents of the namespace and the default agent.
+func nodeLocalAgents(ctx context.Context, c client.Client, namespace string) []v1alpha1.AmazonCloudWatchAgent {
+	agents := &v1alpha1.AmazonCloudWatchAgentList{}
+	_ = c.List(ctx, agents, client.InNamespace(namespace))
+	if namespace != amazonCloudWatchNamespace {
+		agents.Items = append(agents.Items, GetAmazonCloudWatchAgentResource(ctx, c, amazonCloudWatchAgentName))
+	}
+	var local []v1alpha1.AmazonCloudWatchAgent
+	for _, agent := range agents.Items {
+		if agent.ExportsOnNode() {
+			local = append(local, agent)
+		}
+	}
+	return local
+}
+
+// exportToNode rewrites the exporter endpoints of the containers sending to the Service of one of the agents to the
+// IP of the node of the pod, which the variable of the host IP defined first in the container holds.
+func exportToNode(pod corev1.Pod, agents []v1alpha1.AmazonCloudWatchAgent) corev1.Pod {
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		rewritten := false
+		for j, env := range container.Env {
+			if !strings.HasPrefix(env.Name, "OTEL_") || !strings.HasSuffix(env.Name, "_ENDPOINT") || env.ValueFrom != nil {
+				continue
+			}
+			for _, agent := range agents {
+				if endpoint, ok := agent.NodeLocalEndpoint(env.Value); ok {
+					container.Env[j].Value = endpoint
+					rewritten = true
+					break
+				}
+			}
+		}
+		if rewritten && getIndexOfEnv(container.Env, constants.EnvHostIP) == -1 {
+			container.Env = append([]corev1.EnvVar{hostIPEnvVar()}, container.Env...)
+		}
+	}
+	return pod
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+func TestNodeLocalExport(t *testing.T) {
+	annotated := func(value string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Annotations: map[string]string{annotationNodeLocalExport: value}}
+	}
+	enabled := config.New(config.WithNodeLocalExport(true))
+
+	assert.False(t, nodeLocalExport(config.New(), corev1.Namespace{}, corev1.Pod{}))
+	assert.True(t, nodeLocalExport(enabled, corev1.Namespace{}, corev1.Pod{}))
+	assert.True(t, nodeLocalExport(config.New(), corev1.Namespace{ObjectMeta: annotated("true")}, corev1.Pod{}))
+	assert.False(t, nodeLocalExport(enabled, corev1.Namespace{}, corev1.Pod{ObjectMeta: annotated("false")}))
+}
+
+func TestExportToNode(t *testing.T) {
+	pod := corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{
+			Name: "app",
+			Env: []corev1.EnvVar{
+				{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+				{Name: "OTEL_AWS_APPLICATION_SIGNALS_EXPORTER_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch.svc.cluster.local:4316/v1/metrics"},
+				{Name: "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", Value: "https://cloudwatch-agent.amazon-cloudwatch:4316/v1/logs"},
+				{Name: "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", Value: "http://collector.monitoring:4318/v1/metrics"},
+				{Name: "OTEL_EXPORTER_OTLP_PROFILES_ENDPOINT", Value: "http://team-agent.team:4318/v1/profiles"},
+			},
+		},
+		{
+			Name: "sidecar",
+			Env:  []corev1.EnvVar{{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://collector.monitoring:4318"}},
+		},
+	}}}
+
+	agents := []v1alpha1.AmazonCloudWatchAgent{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "team"}, Spec: v1alpha1.AmazonCloudWatchAgentSpec{NameOverride: "team-agent"}},
+	}
+	pod = exportToNode(pod, agents)
+	assert.Equal(t, []corev1.EnvVar{
+		hostIPEnvVar(),
+		{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://$(HOST_IP):4316/v1/traces"},
+		{Name: "OTEL_AWS_APPLICATION_SIGNALS_EXPORTER_ENDPOINT", Value: "http://$(HOST_IP):4316/v1/metrics"},
+		{Name: "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", Value: "https://cloudwatch-agent.amazon-cloudwatch:4316/v1/logs"},
+		{Name: "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", Value: "http://collector.monitoring:4318/v1/metrics"},
+		{Name: "OTEL_EXPORTER_OTLP_PROFILES_ENDPOINT", Value: "http://$(HOST_IP):4318/v1/profiles"},
+	}, pod.Spec.Containers[0].Env)
+	assert.Equal(t, []corev1.EnvVar{{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://collector.monitoring:4318"}}, pod.Spec.Containers[1].Env)
+}
@@ -16,6 +16,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/injectionstats"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
@@ -33,6 +35,7 @@ var (
 
 type instPodMutator struct {
 	Client      client.Client
+	Config      config.Config
 	sdkInjector *sdkInjector
 	Logger      logr.Logger
 	Recorder    record.EventRecorder
@@ -192,18 +195,26 @@ func (langInsts *languageInstrumentations) setInstrumentationLanguageContainers(
 
 var _ podmutation.PodMutator = (*instPodMutator)(nil)
 
-func NewMutator(logger logr.Logger, client client.Client, recorder record.EventRecorder) *instPodMutator {
+func NewMutator(logger logr.Logger, config config.Config, client client.Client, recorder record.EventRecorder) *instPodMutator {
 	return &instPodMutator{
 		Logger: logger,
+		Config: config,
 		Client: client,
 		sdkInjector: &sdkInjector{
 			logger: logger,
 			client: client,
+			cfg:    config,
 		},
 		Recorder: recorder,
 	}
 }
 
+// WithInjectionStats counts the pods injected with each Instrumentation in the given statistics.
+func (pm *instPodMutator) WithInjectionStats(stats *injectionstats.Stats) *instPodMutator {
+	pm.sdkInjector.stats = stats
+	return pm
+}
+
 func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
 	logger := pm.Logger.WithValues("namespace", pod.Namespace, "name", pod.Name)
 
@@ -239,6 +250,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	}
 	if featuregate.EnableNodeJSAutoInstrumentationSupport.IsEnabled() || inst == nil {
 		insts.NodeJS.Instrumentation = inst
+		insts.NodeJS.AdditionalAnnotations = map[string]string{annotationNodeJSRuntime: annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationNodeJSRuntime)}
 	} else {
 		logger.Error(nil, "support for NodeJS auto instrumentation is not enabled")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for NodeJS auto instrumentation is not enabled")
@@ -312,6 +324,10 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	}
 	insts.Sdk.Instrumentation = inst
 
+	if kind := batchWorkloadKind(ctx, pm.Client, ns, pod); kind != "" && insts.skipBatchWorkload(kind) {
+		logger.V(1).Info("skipping instrumentation injection for the pods of this kind of workload", "kind", kind)
+	}
+
 	if insts.Java.Instrumentation == nil && insts.NodeJS.Instrumentation == nil && insts.Python.Instrumentation == nil &&
 		insts.DotNet.Instrumentation == nil && insts.Go.Instrumentation == nil && insts.ApacheHttpd.Instrumentation == nil &&
 		insts.Nginx.Instrumentation == nil &&
@@ -337,6 +353,7 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		ok, msg := insts.areContainerNamesConfiguredForMultipleInstrumentations()
 		if !ok {
 			logger.V(1).Error(msg, "skipping instrumentation injection")
+			pm.recordSkipped(ctx, insts, msg)
 			return pod, nil
 		}
 	} else {
@@ -347,7 +364,9 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 			generalContainerNames := annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectContainerName)
 			insts.setInstrumentationLanguageContainers(generalContainerNames)
 		} else {
-			logger.V(1).Error(fmt.Errorf("multiple injection annotations present"), "skipping instrumentation injection")
+			err := fmt.Errorf("multiple injection annotations present")
+			logger.V(1).Error(err, "skipping instrumentation injection")
+			pm.recordSkipped(ctx, insts, err)
 			return pod, nil
 		}
 
@@ -358,9 +377,40 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	modifiedPod := pod
 	modifiedPod = pm.sdkInjector.inject(ctx, insts, ns, modifiedPod)
 
+	if nodeLocalExport(pm.Config, ns, pod) {
+		if agents := nodeLocalAgents(ctx, pm.Client, ns.Name); len(agents) > 0 {
+			modifiedPod = exportToNode(modifiedPod, agents)
+		} else {
+			logger.V(1).Info("not exporting to the agent of the node, no agent is a daemonset on the host network without TLS")
+		}
+	}
+
 	return modifiedPod, nil
 }
 
+// recordSkipped counts the pod as failed for the instrumentation of each language selected for it, when their
+// injection is skipped and the pod isn't audited.
+func (pm *instPodMutator) recordSkipped(ctx context.Context, insts languageInstrumentations, err error) {
+	if podmutation.Auditing(ctx) {
+		return
+	}
+	injections := injectionstats.NewPod()
+	for language, inst := range map[string]*v1alpha1.Instrumentation{
+		string(TypeJava):   insts.Java.Instrumentation,
+		string(TypeNodeJS): insts.NodeJS.Instrumentation,
+		string(TypePython): insts.Python.Instrumentation,
+		string(TypeDotNet): insts.DotNet.Instrumentation,
+		string(TypeGo):     insts.Go.Instrumentation,
+		"apache-httpd":     insts.ApacheHttpd.Instrumentation,
+		"nginx":            insts.Nginx.Instrumentation,
+	} {
+		if inst != nil {
+			injections.Record(client.ObjectKeyFromObject(inst), language, err)
+		}
+	}
+	pm.sdkInjector.stats.Add(injections)
+}
+
 func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns corev1.Namespace, pod corev1.Pod, instAnnotation string) (*v1alpha1.Instrumentation, error) {
 	instValue := annotationValue(ns.ObjectMeta, pod.ObjectMeta, instAnnotation)
 
@@ -409,7 +459,13 @@ func (pm *instPodMutator) selectInstrumentationInstanceFromNamespace(ctx context
 	case s == 0:
 		pm.Logger.Info("no OpenTelemetry Instrumentation instances available. Using default Instrumentation instance")
 		cr := GetAmazonCloudWatchAgentResource(ctx, pm.Client, amazonCloudWatchAgentName)
-		config, err := adapters.ConfigStructFromJSONString(cr.Spec.Config)
+		agentConfig := cr.Spec.Config
+		if cr.Spec.ApplicationSignals.IsEnabled() {
+			if withApplicationSignals, err := adapters.ConfigWithApplicationSignals(agentConfig); err == nil {
+				agentConfig = withApplicationSignals
+			}
+		}
+		config, err := adapters.ConfigStructFromJSONString(agentConfig)
 		if err != nil {
 			pm.Logger.Error(err, "unable to retrieve cloudwatch agent config for instrumentation")
 		}
@@ -5,6 +5,7 @@ package instrumentation
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -17,6 +18,8 @@ const (
 	envOtelMetricsExporter             = "OTEL_METRICS_EXPORTER"
 	envOtelExporterOTLPTracesProtocol  = "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"
 	envOtelExporterOTLPMetricsProtocol = "OTEL_EXPORTER_OTLP_METRICS_PROTOCOL"
+	envOtelPythonDisabledInstr         = "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS"
+	envOtelPythonDisabledInstrFrom     = "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS_VALUE_FROM"
 	pythonPathPrefix                   = "/otel-auto-instrumentation-python/opentelemetry/instrumentation/auto_instrumentation"
 	pythonPathSuffix                   = "/otel-auto-instrumentation-python"
 	pythonInstrMountPath               = "/otel-auto-instrumentation-python"
@@ -41,6 +44,8 @@ func injectPythonSDK(pythonSpec v1alpha1.Python, pod corev1.Pod, index int) (cor
 		}
 	}
 
+	container.Env = disablePythonInstrumentations(container.Env, pythonSpec.ExcludedInstrumentations)
+
 	idx := getIndexOfEnv(container.Env, envPythonPath)
 	if idx == -1 {
 		container.Env = append(container.Env, corev1.EnvVar{
@@ -115,3 +120,33 @@ func injectPythonSDK(pythonSpec v1alpha1.Python, pod corev1.Pod, index int) (cor
 	}
 	return pod, nil
 }
+
+// disablePythonInstrumentations adds the excluded instrumentations to the ones the container disables, if any.
+func disablePythonInstrumentations(envs []corev1.EnvVar, excluded []string) []corev1.EnvVar {
+	if len(excluded) == 0 {
+		return envs
+	}
+	idx := getIndexOfEnv(envs, envOtelPythonDisabledInstr)
+	if idx == -1 {
+		return append(envs, corev1.EnvVar{Name: envOtelPythonDisabledInstr, Value: strings.Join(excluded, ",")})
+	}
+	if envs[idx].ValueFrom != nil {
+		return appendToEnvValueFrom(envs, idx, envOtelPythonDisabledInstrFrom, strings.Join(excluded, ","))
+	}
+	values := strings.Split(envs[idx].Value, ",")
+	disabled := map[string]bool{}
+	for _, value := range values {
+		disabled[strings.TrimSpace(value)] = true
+	}
+	for _, name := range excluded {
+		if !disabled[name] {
+			values = append(values, name)
+			disabled[name] = true
+		}
+	}
+	if values[0] == "" {
+		values = values[1:]
+	}
+	envs[idx].Value = strings.Join(values, ",")
+	return envs
+}
@@ -362,3 +362,54 @@ func TestInjectPythonSDK(t *testing.T) {
 		})
 	}
 }
+
+func TestDisablePythonInstrumentations(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		envs     []corev1.EnvVar
+		excluded []string
+		expected []corev1.EnvVar
+	}{
+		{
+			name: "nothing excluded",
+			envs: []corev1.EnvVar{{Name: "OTEL_SERVICE_NAME", Value: "app"}},
+			expected: []corev1.EnvVar{
+				{Name: "OTEL_SERVICE_NAME", Value: "app"},
+			},
+		},
+		{
+			name:     "not disabled by the container",
+			excluded: []string{"psycopg2", "requests"},
+			expected: []corev1.EnvVar{
+				{Name: "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS", Value: "psycopg2,requests"},
+			},
+		},
+		{
+			name:     "added to the ones disabled by the container",
+			envs:     []corev1.EnvVar{{Name: "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS", Value: "redis, psycopg2"}},
+			excluded: []string{"psycopg2", "requests"},
+			expected: []corev1.EnvVar{
+				{Name: "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS", Value: "redis, psycopg2,requests"},
+			},
+		},
+		{
+			name: "disabled by the container with ValueFrom",
+			envs: []corev1.EnvVar{{
+				Name:      "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS",
+				ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "disabled"}},
+			}},
+			excluded: []string{"psycopg2"},
+			expected: []corev1.EnvVar{
+				{
+					Name:      "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS_VALUE_FROM",
+					ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "disabled"}},
+				},
+				{Name: "OTEL_PYTHON_DISABLED_INSTRUMENTATIONS", Value: "$(OTEL_PYTHON_DISABLED_INSTRUMENTATIONS_VALUE_FROM),psycopg2"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, disablePythonInstrumentations(tt.envs, tt.excluded))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Runtime identifiers of the auto-instrumentation builds that can be injected into Linux pods.
+const (
+	runtimeLinuxX64     = "linux-x64"
+	runtimeLinuxMuslX64 = "linux-musl-x64"
+	runtimeLinuxArm64   = "linux-arm64"
+)
+
+// autoInstrumentationRuntime picks the runtime of the auto-instrumentation injected into the pod. The annotation
+// takes precedence over the Instrumentation spec; when neither sets it, the pod's architecture node selector
+// decides between the x64 and arm64 builds.
+func autoInstrumentationRuntime(annotation, spec string, pod corev1.Pod) string {
+	if annotation != "" {
+		return annotation
+	}
+	if spec != "" {
+		return spec
+	}
+	if pod.Spec.NodeSelector[corev1.LabelArchStable] == "arm64" {
+		return runtimeLinuxArm64
+	}
+	return runtimeLinuxX64
+}
+
+// runtimeImage returns the init container image for the runtime, falling back to the default image.
+func runtimeImage(image string, runtimeImages map[string]string, runtime string) string {
+	if runtimeImage, ok := runtimeImages[runtime]; ok && runtimeImage != "" {
+		return runtimeImage
+	}
+	return image
+}
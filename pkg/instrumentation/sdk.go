@@ -6,6 +6,7 @@ package instrumentation
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -24,6 +25,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/injectionstats"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/metrics"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
@@ -38,12 +43,17 @@ const (
 type sdkInjector struct {
 	client client.Client
 	logger logr.Logger
+	stats  *injectionstats.Stats
+	// cfg tells the version of the Kubernetes API server, which the pods must be valid for
+	cfg config.Config
 }
 
 func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations, ns corev1.Namespace, pod corev1.Pod) corev1.Pod {
 	if len(pod.Spec.Containers) < 1 {
 		return pod
 	}
+	injections := injectionstats.NewPod()
+	defer i.stats.Add(injections)
 
 	if insts.Java.Instrumentation != nil {
 		otelinst := *insts.Java.Instrumentation
@@ -55,11 +65,18 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 		for _, container := range strings.Split(javaContainers, ",") {
 			index := getContainerIndex(container, pod)
 			pod, err = injectJavaagent(otelinst.Spec.Java, pod, index)
+			i.recordInjection(ctx, injections, otelinst, string(TypeJava), err)
 			if err != nil {
 				i.logger.Info("Skipping javaagent injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				if otelinst.Spec.Java.PodMonitor != nil {
+					if pod.Labels == nil {
+						pod.Labels = map[string]string{}
+					}
+					pod.Labels[constants.LabelJavaMetrics] = string(otelinst.UID)
+				}
 				//disable setting security context in init container due to issue with runAsNonRoot conflict
 				//https://github.com/open-telemetry/opentelemetry-operator/issues/2272
 				//pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, javaInitContainerName)
@@ -75,7 +92,8 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 
 		for _, container := range strings.Split(nodejsContainers, ",") {
 			index := getContainerIndex(container, pod)
-			pod, err = injectNodeJSSDK(otelinst.Spec.NodeJS, pod, index)
+			pod, err = injectNodeJSSDK(otelinst.Spec.NodeJS, pod, index, insts.NodeJS.AdditionalAnnotations[annotationNodeJSRuntime])
+			i.recordInjection(ctx, injections, otelinst, string(TypeNodeJS), err)
 			if err != nil {
 				i.logger.Info("Skipping NodeJS SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
@@ -95,6 +113,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 		for _, container := range strings.Split(pythonContainers, ",") {
 			index := getContainerIndex(container, pod)
 			pod, err = injectPythonSDK(otelinst.Spec.Python, pod, index)
+			i.recordInjection(ctx, injections, otelinst, string(TypePython), err)
 			if err != nil {
 				i.logger.Info("Skipping Python SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
@@ -114,6 +133,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 		for _, container := range strings.Split(dotnetContainers, ",") {
 			index := getContainerIndex(container, pod)
 			pod, err = injectDotNetSDK(otelinst.Spec.DotNet, pod, index, insts.DotNet.AdditionalAnnotations[annotationDotNetRuntime])
+			i.recordInjection(ctx, injections, otelinst, string(TypeDotNet), err)
 			if err != nil {
 				i.logger.Info("Skipping DotNet SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
@@ -136,6 +156,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 		pod, err = injectGoSDK(otelinst.Spec.Go, pod)
 		if err != nil {
 			i.logger.Info("Skipping Go SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+			i.recordInjection(ctx, injections, otelinst, string(TypeGo), err)
 		} else {
 			// Common env vars and config need to be applied to the agent contain.
 			pod = i.injectCommonEnvVar(otelinst, pod, len(pod.Spec.Containers)-1)
@@ -146,6 +167,9 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			if idx == -1 {
 				i.logger.Info("Skipping Go SDK injection", "reason", "OTEL_GO_AUTO_TARGET_EXE not set", "container", pod.Spec.Containers[index].Name)
 				pod = origPod
+				i.recordInjection(ctx, injections, otelinst, string(TypeGo), fmt.Errorf("%s not set", envOtelTargetExe))
+			} else {
+				i.recordInjection(ctx, injections, otelinst, string(TypeGo), nil)
 			}
 		}
 	}
@@ -161,6 +185,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			// Therefore, service name, otlp endpoint and other attributes are passed to the agent injection method
 			resMap, _ := i.createResourceMap(ctx, otelinst, ns, pod, index)
 			pod = injectApacheHttpdagent(i.logger, otelinst.Spec.ApacheHttpd, pod, index, otelinst.Spec.Endpoint, resMap)
+			i.recordInjection(ctx, injections, otelinst, "apache-httpd", nil)
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 			pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, apacheAgentInitContainerName)
@@ -180,6 +205,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			// Therefore, service name, otlp endpoint and other attributes are passed to the agent injection method
 			resMap, _ := i.createResourceMap(ctx, otelinst, ns, pod, index)
 			pod = injectNginxSDK(i.logger, otelinst.Spec.Nginx, pod, index, otelinst.Spec.Endpoint, resMap)
+			i.recordInjection(ctx, injections, otelinst, "nginx", nil)
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 		}
@@ -201,6 +227,16 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	return pod
 }
 
+// recordInjection records the injection of the instrumentation of the language into a container of the pod, in the
+// metrics of the operator and in the statistics of the Instrumentation, unless the pod is audited.
+func (i *sdkInjector) recordInjection(ctx context.Context, injections *injectionstats.Pod, otelinst v1alpha1.Instrumentation, language string, err error) {
+	if podmutation.Auditing(ctx) {
+		return
+	}
+	metrics.RecordInjection(language, err)
+	injections.Record(client.ObjectKeyFromObject(&otelinst), language, err)
+}
+
 func (i *sdkInjector) setInitContainerSecurityContext(pod corev1.Pod, securityContext *corev1.SecurityContext, instrInitContainerName string) corev1.Pod {
 	for i, initContainer := range pod.Spec.InitContainers {
 		if initContainer.Name == instrInitContainerName {
@@ -257,6 +293,10 @@ func (i *sdkInjector) injectCommonSDKConfig(ctx context.Context, otelinst v1alph
 	if otelinst.Spec.Exporter.Endpoint != "" {
 		idx = getIndexOfEnv(container.Env, constants.EnvOTELExporterOTLPEndpoint)
 		if idx == -1 {
+			// the host IP is defined before the endpoint referencing it, for Kubernetes to expand it
+			if strings.Contains(otelinst.Spec.Endpoint, fmt.Sprintf("$(%s)", constants.EnvHostIP)) && getIndexOfEnv(container.Env, constants.EnvHostIP) == -1 {
+				container.Env = append(container.Env, hostIPEnvVar())
+			}
 			container.Env = append(container.Env, corev1.EnvVar{
 				Name:  constants.EnvOTELExporterOTLPEndpoint,
 				Value: otelinst.Spec.Endpoint,
@@ -264,6 +304,10 @@ func (i *sdkInjector) injectCommonSDKConfig(ctx context.Context, otelinst v1alph
 		}
 	}
 
+	if isBatchPod(pod) {
+		injectBatchConfig(otelinst.Spec.Batch, container, i.cfg.KubernetesVersion())
+	}
+
 	// Some attributes might be empty, we should get them via k8s downward API
 	if !existingRes[string(semconv.K8SPodNameKey)] && resourceMap[string(semconv.K8SPodNameKey)] == "" {
 		container.Env = append(container.Env, corev1.EnvVar{
@@ -321,6 +365,8 @@ func (i *sdkInjector) injectCommonSDKConfig(ctx context.Context, otelinst v1alph
 			Name:  constants.EnvOTELResourceAttrs,
 			Value: resStr,
 		})
+	} else if container.Env[idx].ValueFrom != nil {
+		container.Env = appendToEnvValueFrom(container.Env, idx, constants.EnvResourceAttrsValueFrom, resStr)
 	} else {
 		if !strings.HasSuffix(container.Env[idx].Value, ",") && resStr != "" {
 			resStr = "," + resStr
@@ -548,6 +594,17 @@ func moveEnvToListEnd(envs []corev1.EnvVar, idx int) []corev1.EnvVar {
 	return envs
 }
 
+// appendToEnvValueFrom appends the given value to the env var at idx, whose value comes from a source such as a
+// Secret or a field of the pod. The source is moved to a variable with the given name, which the env var references.
+func appendToEnvValueFrom(envs []corev1.EnvVar, idx int, name, value string) []corev1.EnvVar {
+	env := corev1.EnvVar{Name: envs[idx].Name, Value: fmt.Sprintf("$(%s)", name)}
+	if value != "" {
+		env.Value += "," + value
+	}
+	envs[idx].Name = name
+	return slices.Insert(envs, idx+1, env)
+}
+
 func validateContainerEnv(envs []corev1.EnvVar, envsToBeValidated ...string) error {
 	for _, envToBeValidated := range envsToBeValidated {
 		for _, containerEnv := range envs {
@@ -582,6 +582,80 @@ func TestInjectJava(t *testing.T) {
 	}, pod)
 }
 
+func TestInjectResourceAttributesValueFrom(t *testing.T) {
+	inst := v1alpha1.Instrumentation{
+		Spec: v1alpha1.InstrumentationSpec{
+			Java: v1alpha1.Java{
+				Image: "img:1",
+			},
+		},
+	}
+	insts := languageInstrumentations{
+		Java: instrumentationWithContainers{Instrumentation: &inst, Containers: ""},
+	}
+	inj := sdkInjector{
+		logger: logr.Discard(),
+	}
+	source := &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "attributes"},
+			Key:                  "resource",
+		},
+	}
+	pod := inj.inject(context.Background(), insts,
+		corev1.Namespace{},
+		corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  "app",
+						Image: "app:latest",
+						Env: []corev1.EnvVar{
+							{Name: "OTEL_RESOURCE_ATTRIBUTES", ValueFrom: source},
+						},
+					},
+				},
+			},
+		})
+	env := pod.Spec.Containers[0].Env
+	assert.Equal(t, corev1.EnvVar{Name: "OTEL_RESOURCE_ATTRIBUTES_VALUE_FROM", ValueFrom: source}, env[0])
+	assert.Equal(t, corev1.EnvVar{
+		Name:  "OTEL_RESOURCE_ATTRIBUTES",
+		Value: "$(OTEL_RESOURCE_ATTRIBUTES_VALUE_FROM),com.amazonaws.cloudwatch.entity.internal.service.name.source=K8sWorkload,k8s.container.name=app,k8s.node.name=$(OTEL_RESOURCE_ATTRIBUTES_NODE_NAME),k8s.pod.name=$(OTEL_RESOURCE_ATTRIBUTES_POD_NAME),service.version=latest",
+	}, env[len(env)-1])
+}
+
+func TestInjectHostIPEndpoint(t *testing.T) {
+	inst := v1alpha1.Instrumentation{
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://$(HOST_IP):4316"},
+			Java: v1alpha1.Java{
+				Image: "img:1",
+			},
+		},
+	}
+	insts := languageInstrumentations{
+		Java: instrumentationWithContainers{Instrumentation: &inst, Containers: ""},
+	}
+	inj := sdkInjector{
+		logger: logr.Discard(),
+	}
+	pod := inj.inject(context.Background(), insts,
+		corev1.Namespace{},
+		corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Image: "app:latest"}},
+			},
+		})
+	env := pod.Spec.Containers[0].Env
+	hostIP := getIndexOfEnv(env, "HOST_IP")
+	endpoint := getIndexOfEnv(env, "OTEL_EXPORTER_OTLP_ENDPOINT")
+	require.NotEqual(t, -1, hostIP)
+	assert.Equal(t, "status.hostIP", env[hostIP].ValueFrom.FieldRef.FieldPath)
+	assert.Less(t, hostIP, endpoint)
+	assert.Equal(t, "http://$(HOST_IP):4316", env[endpoint].Value)
+}
+
 func TestInjectNodeJS(t *testing.T) {
 	inst := v1alpha1.Instrumentation{
 		Spec: v1alpha1.InstrumentationSpec{
@@ -23,6 +23,10 @@ const (
 
 // add a new sidecar container to the given pod, based on the given AmazonCloudWatchAgent.
 func add(cfg config.Config, logger logr.Logger, otelcol v1alpha1.AmazonCloudWatchAgent, pod corev1.Pod, attributes []corev1.EnvVar) (corev1.Pod, error) {
+	otelcol, err := collector.WithConfigVariables(cfg, otelcol)
+	if err != nil {
+		return pod, err
+	}
 	otelColCfg, err := collector.ReplaceConfig(otelcol)
 	if err != nil {
 		return pod, err